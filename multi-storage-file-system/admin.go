@@ -0,0 +1,736 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/fission/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	errFUSEOpInterrupted = errors.New("FUSE op interrupted")                        // Cause of an inFlightOpStruct.ctx canceled by DoInterrupt()
+	errFUSEOpTimedOut    = errors.New("FUSE op exceeded fuse_op_timeout")           // Cause of an inFlightOpStruct.ctx whose fuse_op_timeout expired
+	errFUSEUnmounting    = errors.New("FUSE file system is being unmounted")        // Cause of every inFlightOpStruct.ctx canceled by cancelInFlightOps() upon unmount
+	errFUSEOpCompleted   = errors.New("FUSE op completed (context no longer used)") // Cause of an inFlightOpStruct.ctx canceled by endInFlightOp()
+)
+
+// `inFlightOpStruct` describes a package fission callback currently being serviced.
+type inFlightOpStruct struct {
+	Op              string                  `json:"op"`
+	NodeID          uint64                  `json:"node_id"`
+	PID             uint32                  `json:"pid"`
+	UID             uint32                  `json:"uid"`
+	Unique          uint64                  `json:"unique"` // The FUSE request's unique ID (as referenced by a subsequent FUSE_INTERRUPT)
+	StartTime       time.Time               `json:"start_time"`
+	Interrupted     bool                    `json:"interrupted"`
+	key             uint64                  // Key in inFlightOpsStruct.shards[key%inFlightOpsShards].opMap
+	ctx             context.Context         // Carries .span; to be supplied to backend calls made on behalf of the op
+	cancel          context.CancelCauseFunc // Cancels .ctx (with one of the errFUSE* causes)
+	stopTimer       context.CancelFunc      // Releases any fuse_op_timeout timer
+	span            trace.Span              // Started by beginInFlightOp(); ended by endInFlightOp()
+	backendTimedOut *atomic.Bool            // Set by markInFlightOpBackendTimedOut() if a backend call exceeded its read_timeout, list_timeout, head_timeout, or write_timeout
+}
+
+// `inFlightOpContextKeyStruct` is the type of the context.Context key used to locate the inFlightOpStruct of an inFlightOpStruct.ctx.
+type inFlightOpContextKeyStruct struct{}
+
+// `inFlightOpsShards` is the number of inFlightOpsStruct.shards among which the in-flight
+// ops are spread (by inFlightOpStruct.key) such that concurrent beginInFlightOp() and
+// endInFlightOp() calls seldom contend for the same lock.
+const inFlightOpsShards = 64
+
+// `inFlightOpsShardStruct` tracks those in-flight ops whose inFlightOpStruct.key selects it.
+type inFlightOpsShardStruct struct {
+	sync.Mutex
+	opMap map[uint64]*inFlightOpStruct // Key == inFlightOpStruct.key
+}
+
+// `inFlightOpsParentStruct` holds the context from which each inFlightOpStruct.ctx is derived.
+type inFlightOpsParentStruct struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// `inFlightOpsStruct` tracks the package fission callbacks currently being serviced.
+type inFlightOpsStruct struct {
+	sync.Mutex                                           // Serializes the (re)creation and cancelation of .parent only
+	parent     atomic.Pointer[inFlightOpsParentStruct]   // Lazily created; canceled (and reset to nil) by cancelInFlightOps()
+	lastKey    atomic.Uint64                             // Last inFlightOpStruct.key allocated
+	shards     [inFlightOpsShards]inFlightOpsShardStruct //
+}
+
+// `adminHandlerStruct` serves the admin_listen JSON endpoints.
+type adminHandlerStruct struct{}
+
+// `adminConfigStruct` is the JSON form of the (non-secret) configuration reported by /config.
+type adminConfigStruct struct {
+	MountName                   string                     `json:"mountname"`
+	MountPoint                  string                     `json:"mountpoint"`
+	FUSEWorkers                 uint64                     `json:"fuse_workers"`
+	UID                         uint64                     `json:"uid"`
+	GID                         uint64                     `json:"gid"`
+	DirPerm                     string                     `json:"dir_perm"`
+	AllowOther                  bool                       `json:"allow_other"`
+	MaxWrite                    uint64                     `json:"max_write"`
+	EntryAttrTTL                string                     `json:"entry_attr_ttl"`
+	EntryTTL                    string                     `json:"entry_ttl"`
+	AttrTTL                     string                     `json:"attr_ttl"`
+	EvictableInodeTTL           string                     `json:"evictable_inode_ttl"`
+	VirtualDirTTL               string                     `json:"virtual_dir_ttl"`
+	VirtualFileTTL              string                     `json:"virtual_file_ttl"`
+	CacheStorage                string                     `json:"cache_storage"`
+	CacheLineSize               uint64                     `json:"cache_line_size"`
+	CacheLines                  uint64                     `json:"cache_lines"`
+	CacheLinesToPrefetch        uint64                     `json:"cache_lines_to_prefetch"`
+	CacheDedup                  bool                       `json:"cache_dedup"`
+	DirtyCacheLinesFlushTrigger uint64                     `json:"dirty_cache_lines_flush_trigger"` // In data cache lines
+	DirtyCacheLinesMax          uint64                     `json:"dirty_cache_lines_max"`           // In data cache lines
+	CachePartitionBy            string                     `json:"cache_partition_by"`
+	CacheDirPath                string                     `json:"cache_dir_path"`
+	MetadataCachePagingMode     string                     `json:"metadata_cache_paging_mode"`
+	ProcessMemoryLimit          uint64                     `json:"process_memory_limit"`
+	AutoSIGHUPInterval          string                     `json:"auto_sighup_interval"`
+	Endpoint                    string                     `json:"endpoint"`
+	AdminListen                 string                     `json:"admin_listen"`
+	EventSink                   string                     `json:"event_sink"`
+	ReadyFile                   string                     `json:"ready_file"`
+	ReadyProbeInterval          string                     `json:"ready_probe_interval"`
+	LogFormat                   string                     `json:"log_format"`
+	LogLevel                    string                     `json:"log_level"`
+	LogFile                     string                     `json:"log_file"`
+	Backends                    []adminBackendConfigStruct `json:"backends"`
+}
+
+// `adminBackendConfigStruct` is the JSON form of each backend's (non-secret) configuration reported by /config.
+type adminBackendConfigStruct struct {
+	DirName             string `json:"dir_name"`
+	BackendType         string `json:"backend_type"`
+	BucketContainerName string `json:"bucket_container_name"`
+	Prefix              string `json:"prefix"`
+	ReadOnly            bool   `json:"readonly"`
+	FlushOnClose        bool   `json:"flush_on_close"`
+	UID                 uint64 `json:"uid"`
+	GID                 uint64 `json:"gid"`
+	DirPerm             string `json:"dir_perm"`
+	FilePerm            string `json:"file_perm"`
+	DirectoryPageSize   uint64 `json:"directory_page_size"`
+	LogLevel            string `json:"log_level"`
+	CacheBypass         bool   `json:"cache_bypass"`
+	Mounted             bool   `json:"mounted"`
+	MountPoint          string `json:"mountpoint"`
+}
+
+// `adminBackendHealthStruct` is the JSON form of each backend's health reported by /health.
+type adminBackendHealthStruct struct {
+	DirName string  `json:"dir_name"`
+	Mounted bool    `json:"mounted"`
+	State   string  `json:"state"` // One of BackendState*
+	Healthy bool    `json:"healthy"`
+	Latency float64 `json:"latency_seconds"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// `adminHealthProbeTTL` is how long the outcome of a /health probe of a backend is reused
+// rather than each GET of /health issuing a fresh listing of every mounted backend.
+const adminHealthProbeTTL = 5 * time.Second
+
+// `adminHealthProbeStruct` records the outcome of a recent /health probe of a backend.
+type adminHealthProbeStruct struct {
+	expiration time.Time
+	healthy    bool
+	latency    float64
+	err        string
+}
+
+// `adminHealthProbesStruct` caches the outcome of recent /health probes.
+type adminHealthProbesStruct struct {
+	sync.Mutex
+	probeMap map[backendContextIf]*adminHealthProbeStruct // Key == backendStruct.context probed (so a remounted backend is probed afresh)
+}
+
+// `adminLiveStruct` is the JSON form of the liveness reported by /live.
+type adminLiveStruct struct {
+	Live bool `json:"live"`
+}
+
+// `adminReadyStruct` is the JSON form of the readiness reported by /ready.
+type adminReadyStruct struct {
+	Ready bool `json:"ready"`
+}
+
+// `adminInodesStruct` is the JSON form of the inode counts reported by /inodes.
+type adminInodesStruct struct {
+	Inodes              int `json:"inodes"`
+	OpenHandles         int `json:"open_handles"`
+	InodeDiskCacheFiles int `json:"inode_disk_cache_files"`
+}
+
+// `adminCacheStruct` is the JSON form of the data cache occupancy reported by /cache.
+type adminCacheStruct struct {
+	CacheLineSize  uint64            `json:"cache_line_size"`
+	CacheLines     uint64            `json:"cache_lines"`
+	Free           uint64            `json:"free"`
+	Inbound        uint64            `json:"inbound"`
+	Clean          uint64            `json:"clean"`
+	Outbound       uint64            `json:"outbound"`
+	Dirty          uint64            `json:"dirty"`
+	PartitionLines map[string]uint64 `json:"partition_lines,omitempty"`
+	MemoryPressure bool              `json:"memory_pressure"`
+	Deduplicated   uint64            `json:"deduplicated,omitempty"`
+}
+
+// `adminDirtyStruct` is the JSON form of the dirty data cache line backlog reported by /dirty.
+type adminDirtyStruct struct {
+	Dirty        uint64 `json:"dirty"`
+	Outbound     uint64 `json:"outbound"`
+	DirtyBytes   uint64 `json:"dirty_bytes"`
+	FlushTrigger uint64 `json:"flush_trigger"` // In data cache lines
+	Max          uint64 `json:"max"`           // In data cache lines
+}
+
+// `adminInFlightOpStruct` is the JSON form of each in-flight package fission callback reported by /inflight.
+type adminInFlightOpStruct struct {
+	inFlightOpStruct
+	Age float64 `json:"age_seconds"`
+}
+
+// `beginInFlightOp` records the start of servicing a package fission callback (starting
+// its span) returning the inFlightOpStruct to subsequently supply to endInFlightOp(). The
+// op's context is canceled upon a FUSE_INTERRUPT of the op (see interruptInFlightOp()),
+// upon unmount (see cancelInFlightOps()), or once fuse_op_timeout (if != 0) expires.
+func beginInFlightOp(op string, inHeader *fission.InHeader) (inFlightOp *inFlightOpStruct) {
+	var (
+		ctx    context.Context
+		parent *inFlightOpsParentStruct
+		shard  *inFlightOpsShardStruct
+	)
+
+	inFlightOp = &inFlightOpStruct{
+		Op:        op,
+		NodeID:    inHeader.NodeID,
+		PID:       inHeader.PID,
+		UID:       inHeader.UID,
+		Unique:    inHeader.Unique,
+		StartTime: time.Now(),
+		stopTimer: func() {},
+
+		backendTimedOut: &atomic.Bool{},
+	}
+
+	parent = globals.inFlightOps.parent.Load()
+	if parent == nil {
+		globals.inFlightOps.Lock()
+		parent = globals.inFlightOps.parent.Load()
+		if parent == nil {
+			parent = &inFlightOpsParentStruct{}
+			parent.ctx, parent.cancel = context.WithCancelCause(context.Background())
+			globals.inFlightOps.parent.Store(parent)
+		}
+		globals.inFlightOps.Unlock()
+	}
+
+	ctx, inFlightOp.cancel = context.WithCancelCause(parent.ctx)
+
+	inFlightOp.key = globals.inFlightOps.lastKey.Add(1)
+
+	shard = &globals.inFlightOps.shards[inFlightOp.key%inFlightOpsShards]
+	shard.Lock()
+	if shard.opMap == nil {
+		shard.opMap = make(map[uint64]*inFlightOpStruct)
+	}
+	shard.opMap[inFlightOp.key] = inFlightOp
+	shard.Unlock()
+
+	if (globals.config != nil) && (globals.config.fuseOpTimeout != 0) {
+		ctx, inFlightOp.stopTimer = context.WithTimeoutCause(ctx, globals.config.fuseOpTimeout, errFUSEOpTimedOut)
+	}
+
+	ctx = context.WithValue(ctx, inFlightOpContextKeyStruct{}, inFlightOp)
+
+	inFlightOp.ctx, inFlightOp.span = startFUSESpan(ctx, op, inHeader)
+
+	return
+}
+
+// `endInFlightOp` records the completion of servicing a package fission callback (ending
+// its span). If errno is non-nil and indicates a failure, and the op's context has been
+// canceled, *errno is replaced by the errno appropriate for the cancellation's cause.
+// Similarly, if a backend call made on behalf of the op timed out, *errno is replaced by EIO.
+func endInFlightOp(inFlightOp *inFlightOpStruct, errno *syscall.Errno) {
+	var (
+		shard = &globals.inFlightOps.shards[inFlightOp.key%inFlightOpsShards]
+	)
+
+	if (errno != nil) && (*errno != 0) {
+		if inFlightOp.ctx.Err() != nil {
+			*errno = canceledErrno(inFlightOp.ctx)
+		} else if inFlightOp.backendTimedOut.Load() {
+			*errno = syscall.EIO
+		}
+	}
+
+	shard.Lock()
+	delete(shard.opMap, inFlightOp.key)
+	shard.Unlock()
+
+	inFlightOp.stopTimer()
+	inFlightOp.cancel(errFUSEOpCompleted)
+
+	inFlightOp.span.End()
+}
+
+// `canceledErrno` returns the errno to report for an op whose (canceled) context is ctx.
+func canceledErrno(ctx context.Context) syscall.Errno {
+	if errors.Is(context.Cause(ctx), errFUSEOpInterrupted) {
+		return syscall.EINTR
+	}
+
+	return syscall.EIO
+}
+
+// `markInFlightOpBackendTimedOut` records that a backend call made with ctx (derived from an
+// inFlightOpStruct.ctx) exceeded its timeout. It is a no-op if ctx is not so derived.
+func markInFlightOpBackendTimedOut(ctx context.Context) {
+	var (
+		inFlightOp *inFlightOpStruct
+		ok         bool
+	)
+
+	inFlightOp, ok = ctx.Value(inFlightOpContextKeyStruct{}).(*inFlightOpStruct)
+	if ok {
+		inFlightOp.backendTimedOut.Store(true)
+	}
+}
+
+// `interruptInFlightOp` cancels the context of the in-flight op (if any) whose FUSE
+// request unique ID matches unique (as supplied by a FUSE_INTERRUPT).
+func interruptInFlightOp(unique uint64) (found bool) {
+	var (
+		inFlightOp *inFlightOpStruct
+		shard      *inFlightOpsShardStruct
+		shardIndex int
+	)
+
+	for shardIndex = range globals.inFlightOps.shards {
+		shard = &globals.inFlightOps.shards[shardIndex]
+		shard.Lock()
+		for _, inFlightOp = range shard.opMap {
+			if inFlightOp.Unique == unique {
+				inFlightOp.Interrupted = true
+				inFlightOp.cancel(errFUSEOpInterrupted)
+				found = true
+				break
+			}
+		}
+		shard.Unlock()
+		if found {
+			return
+		}
+	}
+
+	return
+}
+
+// `cancelInFlightOps` cancels the context of every in-flight op (with cause). Ops
+// subsequently begun receive a fresh (uncanceled) context.
+func cancelInFlightOps(cause error) {
+	var (
+		parent *inFlightOpsParentStruct
+	)
+
+	globals.inFlightOps.Lock()
+
+	parent = globals.inFlightOps.parent.Swap(nil)
+	if parent != nil {
+		parent.cancel(cause)
+	}
+
+	globals.inFlightOps.Unlock()
+}
+
+// `startAdminHandler` launches the admin_listen HTTP server (if configured).
+func startAdminHandler() {
+	if globals.config.adminListen == "" {
+		return
+	}
+
+	_ = startWorker("adminServer", func(ctx context.Context) {
+		var (
+			err                     error
+			adminServer             *http.Server
+			adminServerLoggerLogger = log.New(globals.logger.Writer(), "[ADMIN-SERVER] ", globals.logger.Flags()) // set prefix to differentiate adminServer logging
+		)
+
+		adminServer = &http.Server{
+			Addr:         globals.config.adminListen,
+			Handler:      &adminHandlerStruct{},
+			ReadTimeout:  HTTP_SERVER_READ_TIMEOUT,
+			WriteTimeout: HTTP_SERVER_WRITE_TIMEOUT,
+			IdleTimeout:  HTTP_SERVER_IDLE_TIMEOUT,
+			ErrorLog:     adminServerLoggerLogger,
+		}
+
+		_ = context.AfterFunc(ctx, func() { _ = adminServer.Close() })
+
+		err = adminServer.ListenAndServe()
+		if (err != nil) && !errors.Is(err, http.ErrServerClosed) {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] adminServer.ListenAndServe() failed: %v", err)
+		}
+	})
+
+	globals.logger.Printf("[INFO] admin_listen: %s", globals.config.adminListen)
+}
+
+func (*adminHandlerStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		live     bool
+		ready    bool
+		response interface{}
+		status   = http.StatusOK
+	)
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		fmt.Fprintf(w, "only GET is supported\n")
+		return
+	}
+
+	switch r.URL.Path {
+	case "/":
+		response = []string{"/cache", "/config", "/dirty", "/health", "/inflight", "/inodes", "/live", "/prefetch", "/ready"}
+	case "/cache":
+		response = adminCache()
+	case "/config":
+		response = adminConfig()
+	case "/dirty":
+		response = adminDirty()
+	case "/health":
+		response = adminHealth()
+	case "/inflight":
+		response = adminInFlightOps()
+	case "/inodes":
+		response = adminInodes()
+	case "/live":
+		live = adminLive()
+		if !live {
+			status = http.StatusServiceUnavailable
+		}
+		response = &adminLiveStruct{Live: live}
+	case "/prefetch":
+		response = adminPrefetches()
+	case "/ready":
+		ready = globals.ready.Load()
+		if !ready {
+			status = http.StatusServiceUnavailable
+		}
+		response = &adminReadyStruct{Ready: ready}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, "unknown endpoint - must be one of: /cache, /config, /dirty, /health, /inflight, /inodes, /live, /prefetch, /ready\n")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// `adminConfig` is called to report the current (non-secret) configuration.
+func adminConfig() (adminConfig *adminConfigStruct) {
+	var (
+		backend *backendStruct
+	)
+
+	globalsLock("admin.go:458:2:adminConfig")
+
+	adminConfig = &adminConfigStruct{
+		MountName:                   globals.config.mountName,
+		MountPoint:                  globals.config.mountPoint,
+		FUSEWorkers:                 globals.config.fuseWorkers,
+		UID:                         globals.config.uid,
+		GID:                         globals.config.gid,
+		DirPerm:                     fmt.Sprintf("%#o", globals.config.dirPerm),
+		AllowOther:                  globals.config.allowOther,
+		MaxWrite:                    globals.config.maxWrite,
+		EntryAttrTTL:                globals.config.entryAttrTTL.String(),
+		EntryTTL:                    globals.config.entryTTL.String(),
+		AttrTTL:                     globals.config.attrTTL.String(),
+		EvictableInodeTTL:           globals.config.evictableInodeTTL.String(),
+		VirtualDirTTL:               globals.config.virtualDirTTL.String(),
+		VirtualFileTTL:              globals.config.virtualFileTTL.String(),
+		CacheStorage:                globals.config.cacheStorage,
+		CacheLineSize:               globals.config.cacheLineSize,
+		CacheLines:                  globals.config.cacheLines,
+		CacheLinesToPrefetch:        globals.config.cacheLinesToPrefetch,
+		CacheDedup:                  globals.config.cacheDedup,
+		DirtyCacheLinesFlushTrigger: globals.config.dirtyCacheLinesFlushTrigger,
+		DirtyCacheLinesMax:          globals.config.dirtyCacheLinesMax,
+		CachePartitionBy:            globals.config.cachePartitionBy,
+		CacheDirPath:                globals.config.cacheDirPath,
+		MetadataCachePagingMode:     globals.config.metadataCachePagingMode,
+		ProcessMemoryLimit:          globals.config.processMemoryLimit,
+		AutoSIGHUPInterval:          globals.config.autoSIGHUPInterval.String(),
+		Endpoint:                    globals.config.endpoint,
+		AdminListen:                 globals.config.adminListen,
+		EventSink:                   globals.config.eventSink,
+		ReadyFile:                   globals.config.readyFile,
+		ReadyProbeInterval:          globals.config.readyProbeInterval.String(),
+		LogFormat:                   globals.config.logFormat,
+		LogLevel:                    logLevelString(globals.config.logLevel),
+		LogFile:                     globals.config.logFile,
+		Backends:                    make([]adminBackendConfigStruct, 0, len(globals.config.backends)),
+	}
+
+	for _, backend = range globals.config.backends {
+		adminConfig.Backends = append(adminConfig.Backends, adminBackendConfigStruct{
+			DirName:             backend.dirName,
+			BackendType:         backend.backendType,
+			BucketContainerName: backend.bucketContainerName,
+			Prefix:              backend.prefix,
+			ReadOnly:            backend.readOnly,
+			FlushOnClose:        backend.flushOnClose,
+			UID:                 backend.uid,
+			GID:                 backend.gid,
+			DirPerm:             fmt.Sprintf("%#o", backend.dirPerm),
+			FilePerm:            fmt.Sprintf("%#o", backend.filePerm),
+			DirectoryPageSize:   backend.directoryPageSize,
+			LogLevel:            logLevelString(backend.logLevel.Level()),
+			CacheBypass:         backend.cacheBypass,
+			Mounted:             backend.mounted,
+			MountPoint:          cmp.Or(backend.mountPoint, globals.config.mountPoint),
+		})
+	}
+
+	globalsUnlock()
+
+	sort.Slice(adminConfig.Backends, func(i, j int) bool { return adminConfig.Backends[i].DirName < adminConfig.Backends[j].DirName })
+
+	return
+}
+
+// `adminHealth` is called to probe each mounted backend (concurrently) with a single
+// one-entry listing of its root and report the outcome. Backends that could not be
+// mounted (see globals.backendsFailed) are reported (unhealthy) as well. A backend
+// probed within the last adminHealthProbeTTL is reported as of that probe.
+func adminHealth() (adminBackendHealths []*adminBackendHealthStruct) {
+	var (
+		adminBackendHealth *adminBackendHealthStruct
+		adminHealthProbe   *adminHealthProbeStruct
+		backend            *backendStruct
+		backendContext     backendContextIf
+		backendContexts    = make(map[*adminBackendHealthStruct]backendContextIf)
+		ok                 bool
+		probeWG            sync.WaitGroup
+		timeNow            time.Time
+	)
+
+	globalsLock("admin.go:541:2:adminHealth")
+
+	adminBackendHealths = make([]*adminBackendHealthStruct, 0, len(globals.config.backends)+len(globals.backendsFailed))
+
+	for _, backend = range globals.config.backends {
+		adminBackendHealth = &adminBackendHealthStruct{
+			DirName: backend.dirName,
+			Mounted: backend.mounted,
+			State:   backendState(backend),
+		}
+		adminBackendHealths = append(adminBackendHealths, adminBackendHealth)
+
+		if backend.mounted && (backend.context != nil) {
+			backendContexts[adminBackendHealth] = backend.context
+		}
+	}
+
+	for _, backend = range globals.backendsFailed {
+		adminBackendHealths = append(adminBackendHealths, &adminBackendHealthStruct{
+			DirName: backend.dirName,
+			Mounted: false,
+			State:   backendState(backend),
+			Error:   redactSecrets(backend, backend.setupErr.Error()),
+		})
+	}
+
+	globalsUnlock()
+
+	slices.SortFunc(adminBackendHealths, func(a, b *adminBackendHealthStruct) int { return strings.Compare(a.DirName, b.DirName) })
+
+	timeNow = time.Now()
+
+	globals.healthProbes.Lock()
+
+	for adminBackendHealth, backendContext = range backendContexts {
+		adminHealthProbe, ok = globals.healthProbes.probeMap[backendContext]
+		if ok && timeNow.Before(adminHealthProbe.expiration) {
+			adminBackendHealth.Healthy = adminHealthProbe.healthy
+			adminBackendHealth.Latency = adminHealthProbe.latency
+			adminBackendHealth.Error = adminHealthProbe.err
+			delete(backendContexts, adminBackendHealth)
+		}
+	}
+
+	globals.healthProbes.Unlock()
+
+	for adminBackendHealth, backendContext = range backendContexts {
+		probeWG.Add(1)
+		go func(backendContext backendContextIf, adminBackendHealth *adminBackendHealthStruct) {
+			var (
+				err       error
+				startTime = time.Now()
+			)
+
+			defer probeWG.Done()
+
+			_, err = listDirectoryWrapper(context.Background(), backendContext, &listDirectoryInputStruct{
+				maxItems: 1,
+				dirPath:  "",
+			})
+
+			adminBackendHealth.Latency = time.Since(startTime).Seconds()
+
+			if err == nil {
+				adminBackendHealth.Healthy = true
+			} else {
+				adminBackendHealth.Error = backendContext.redactSecrets(err.Error())
+			}
+		}(backendContext, adminBackendHealth)
+	}
+
+	probeWG.Wait()
+
+	if len(backendContexts) == 0 {
+		return
+	}
+
+	timeNow = time.Now()
+
+	globals.healthProbes.Lock()
+
+	if globals.healthProbes.probeMap == nil {
+		globals.healthProbes.probeMap = make(map[backendContextIf]*adminHealthProbeStruct)
+	}
+
+	for backendContext, adminHealthProbe = range globals.healthProbes.probeMap {
+		if !timeNow.Before(adminHealthProbe.expiration) {
+			delete(globals.healthProbes.probeMap, backendContext)
+		}
+	}
+
+	for adminBackendHealth, backendContext = range backendContexts {
+		globals.healthProbes.probeMap[backendContext] = &adminHealthProbeStruct{
+			expiration: timeNow.Add(adminHealthProbeTTL),
+			healthy:    adminBackendHealth.Healthy,
+			latency:    adminBackendHealth.Latency,
+			err:        adminBackendHealth.Error,
+		}
+	}
+
+	globals.healthProbes.Unlock()
+
+	return
+}
+
+// `adminInodes` is called to report inode and file handle counts.
+func adminInodes() (adminInodes *adminInodesStruct) {
+	globalsLock("admin.go:648:2:adminInodes")
+
+	adminInodes = &adminInodesStruct{
+		Inodes:              globals.inodeMap.len(),
+		OpenHandles:         len(globals.fhMap),
+		InodeDiskCacheFiles: len(globals.inodeDiskCacheFiles),
+	}
+
+	globalsUnlock()
+
+	return
+}
+
+// `adminCache` is called to report data cache line occupancy by state.
+func adminCache() (adminCache *adminCacheStruct) {
+	globalsLock("admin.go:663:2:adminCache")
+
+	adminCache = &adminCacheStruct{
+		CacheLineSize:  globals.config.cacheLineSize,
+		CacheLines:     globals.config.cacheLines,
+		Free:           globals.dataCacheLineFreeLRU.lruCount.Load(),
+		Inbound:        globals.dataCacheLineInboundLRU.lruCount.Load(),
+		Clean:          globals.dataCacheLineCleanLRU.lruCount.Load(),
+		Outbound:       globals.dataCacheLineOutboundLRU.lruCount.Load(),
+		Dirty:          globals.dataCacheLineDirtyLRU.lruCount.Load(),
+		MemoryPressure: globals.memoryPressure.Load(),
+		Deduplicated:   globals.dataCacheDedupLines,
+	}
+
+	if len(globals.dataCachePartitionLines) > 0 {
+		adminCache.PartitionLines = make(map[string]uint64, len(globals.dataCachePartitionLines))
+		for partition, lines := range globals.dataCachePartitionLines {
+			adminCache.PartitionLines[partition] = lines
+		}
+	}
+
+	globalsUnlock()
+
+	return
+}
+
+// `adminDirty` is called to report the backlog of data cache lines awaiting (or undergoing) upload.
+// As the LRU counts are atomics, globals.Lock() need not be held (so as not to contend with FUSE
+// operations should the backlog be polled frequently). The counts are thus not captured as of
+// the same instant, though each is accurate.
+func adminDirty() (adminDirty *adminDirtyStruct) {
+	var (
+		dirty    = globals.dataCacheLineDirtyLRU.lruCount.Load()
+		outbound = globals.dataCacheLineOutboundLRU.lruCount.Load()
+	)
+
+	adminDirty = &adminDirtyStruct{
+		Dirty:        dirty,
+		Outbound:     outbound,
+		DirtyBytes:   (dirty + outbound) * globals.config.cacheLineSize,
+		FlushTrigger: globals.config.dirtyCacheLinesFlushTrigger,
+		Max:          globals.config.dirtyCacheLinesMax,
+	}
+
+	return
+}
+
+// `adminInFlightOps` is called to report the package fission callbacks currently being serviced, oldest first.
+func adminInFlightOps() (adminInFlightOps []*adminInFlightOpStruct) {
+	var (
+		inFlightOp *inFlightOpStruct
+		shard      *inFlightOpsShardStruct
+		shardIndex int
+		timeNow    = time.Now()
+	)
+
+	adminInFlightOps = make([]*adminInFlightOpStruct, 0)
+
+	for shardIndex = range globals.inFlightOps.shards {
+		shard = &globals.inFlightOps.shards[shardIndex]
+		shard.Lock()
+		for _, inFlightOp = range shard.opMap {
+			adminInFlightOps = append(adminInFlightOps, &adminInFlightOpStruct{
+				inFlightOpStruct: *inFlightOp,
+				Age:              timeNow.Sub(inFlightOp.StartTime).Seconds(),
+			})
+		}
+		shard.Unlock()
+	}
+
+	sort.Slice(adminInFlightOps, func(i, j int) bool { return adminInFlightOps[i].StartTime.Before(adminInFlightOps[j].StartTime) })
+
+	return
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+// TestAdminHandler verifies the admin_listen JSON endpoints.
+func TestAdminHandler(t *testing.T) {
+	var (
+		adminCache       adminCacheStruct
+		adminConfig      adminConfigStruct
+		adminHealth      []adminBackendHealthStruct
+		adminInFlightOps []adminInFlightOpStruct
+		adminInodes      adminInodesStruct
+		inFlightOp       *inFlightOpStruct
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	get := func(path string, expectedStatusCode int, v interface{}) {
+		recorder := httptest.NewRecorder()
+		(&adminHandlerStruct{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != expectedStatusCode {
+			t.Fatalf("GET %s returned status %d (expected %d)", path, recorder.Code, expectedStatusCode)
+		}
+		if v != nil {
+			err := json.Unmarshal(recorder.Body.Bytes(), v)
+			if err != nil {
+				t.Fatalf("GET %s returned unparseable JSON: %v", path, err)
+			}
+		}
+	}
+
+	get("/config", http.StatusOK, &adminConfig)
+	if adminConfig.CacheLines != globals.config.cacheLines {
+		t.Fatalf("GET /config returned cache_lines %d (expected %d)", adminConfig.CacheLines, globals.config.cacheLines)
+	}
+	if len(adminConfig.Backends) != len(globals.config.backends) {
+		t.Fatalf("GET /config returned %d backends (expected %d)", len(adminConfig.Backends), len(globals.config.backends))
+	}
+
+	get("/health", http.StatusOK, &adminHealth)
+	for _, backendHealth := range adminHealth {
+		if (backendHealth.DirName == "ram") && !backendHealth.Healthy {
+			t.Fatalf("GET /health reported backend \"ram\" unhealthy: %s", backendHealth.Error)
+		}
+	}
+
+	get("/inodes", http.StatusOK, &adminInodes)
+	if adminInodes.Inodes == 0 {
+		t.Fatalf("GET /inodes reported no inodes")
+	}
+
+	get("/cache", http.StatusOK, &adminCache)
+	if adminCache.Free+adminCache.Inbound+adminCache.Clean+adminCache.Outbound+adminCache.Dirty != globals.config.cacheLines {
+		t.Fatalf("GET /cache returned line counts not summing to cache_lines: %+v", adminCache)
+	}
+
+	get("/dirty", http.StatusOK, nil)
+
+	inFlightOp = beginInFlightOp("Read", &fission.InHeader{NodeID: 42, PID: 1234})
+
+	get("/inflight", http.StatusOK, &adminInFlightOps)
+	if (len(adminInFlightOps) != 1) || (adminInFlightOps[0].Op != "Read") || (adminInFlightOps[0].NodeID != 42) || (adminInFlightOps[0].PID != 1234) {
+		t.Fatalf("GET /inflight returned unexpected ops: %+v", adminInFlightOps)
+	}
+
+	endInFlightOp(inFlightOp, nil)
+
+	get("/inflight", http.StatusOK, &adminInFlightOps)
+	if len(adminInFlightOps) != 0 {
+		t.Fatalf("GET /inflight returned unexpected ops after endInFlightOp(): %+v", adminInFlightOps)
+	}
+
+	get("/unknown", http.StatusNotFound, nil)
+}
+
+// TestInFlightOpCancellation verifies that an in-flight op's context is canceled
+// upon FUSE_INTERRUPT, fuse_op_timeout expiry, and unmount and that a failing op
+// then reports the errno appropriate to the cause.
+func TestInFlightOpCancellation(t *testing.T) {
+	var (
+		errno       syscall.Errno
+		inFlightOp  *inFlightOpStruct
+		inFlightOp2 *inFlightOpStruct
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	inFlightOp = beginInFlightOp("Read", &fission.InHeader{NodeID: 42, Unique: 7})
+	inFlightOp2 = beginInFlightOp("Read", &fission.InHeader{NodeID: 42, Unique: 8})
+
+	if interruptInFlightOp(9) {
+		t.Fatalf("interruptInFlightOp(9) unexpectedly found an op")
+	}
+	if !interruptInFlightOp(7) {
+		t.Fatalf("interruptInFlightOp(7) did not find the op")
+	}
+
+	if !errors.Is(context.Cause(inFlightOp.ctx), errFUSEOpInterrupted) {
+		t.Fatalf("interrupted op's context cause: %v", context.Cause(inFlightOp.ctx))
+	}
+	if inFlightOp2.ctx.Err() != nil {
+		t.Fatalf("interruptInFlightOp(7) should not have canceled op 8")
+	}
+
+	errno = syscall.EIO
+	endInFlightOp(inFlightOp, &errno)
+	if errno != syscall.EINTR {
+		t.Fatalf("interrupted op should have returned EINTR, got %v", errno)
+	}
+
+	cancelInFlightOps(errFUSEUnmounting)
+
+	if !errors.Is(context.Cause(inFlightOp2.ctx), errFUSEUnmounting) {
+		t.Fatalf("op's context cause after cancelInFlightOps(): %v", context.Cause(inFlightOp2.ctx))
+	}
+
+	errno = 0
+	endInFlightOp(inFlightOp2, &errno)
+	if errno != 0 {
+		t.Fatalf("a successful op's errno should be left alone, got %v", errno)
+	}
+
+	globals.config.fuseOpTimeout = 10 * time.Millisecond
+
+	inFlightOp = beginInFlightOp("Lookup", &fission.InHeader{NodeID: 42, Unique: 10})
+	if inFlightOp.ctx.Err() != nil {
+		t.Fatalf("op begun after cancelInFlightOps() should have a fresh context")
+	}
+
+	<-inFlightOp.ctx.Done()
+
+	if !errors.Is(context.Cause(inFlightOp.ctx), errFUSEOpTimedOut) {
+		t.Fatalf("timed out op's context cause: %v", context.Cause(inFlightOp.ctx))
+	}
+
+	errno = syscall.ENOENT
+	endInFlightOp(inFlightOp, &errno)
+	if errno != syscall.EIO {
+		t.Fatalf("timed out op should have returned EIO, got %v", errno)
+	}
+
+	globals.config.fuseOpTimeout = 0
+}
+
+// TestInFlightOpBackendTimeout verifies that a backend call exceeding its timeout fails the
+// in-flight op on whose behalf it was made with EIO.
+func TestInFlightOpBackendTimeout(t *testing.T) {
+	var (
+		cancel     context.CancelFunc
+		err        error
+		errno      syscall.Errno
+		errNotHere = errors.New("not here")
+		inFlightOp *inFlightOpStruct
+		timeoutCtx context.Context
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	inFlightOp = beginInFlightOp("Lookup", &fission.InHeader{NodeID: 1, Unique: 1})
+
+	timeoutCtx, cancel = withBackendOpTimeout(inFlightOp.ctx, 0)
+	if timeoutCtx != inFlightOp.ctx {
+		t.Fatalf("withBackendOpTimeout(ctx, 0) should have returned ctx unchanged")
+	}
+	cancel()
+
+	err = checkBackendOpTimeout(timeoutCtx, 0, errNotHere)
+	if err != errNotHere {
+		t.Fatalf("checkBackendOpTimeout() without a timeout should have returned err unchanged, got %v", err)
+	}
+
+	errno = syscall.ENOENT
+	endInFlightOp(inFlightOp, &errno)
+	if errno != syscall.ENOENT {
+		t.Fatalf("op without a backend timeout should have returned ENOENT, got %v", errno)
+	}
+
+	inFlightOp = beginInFlightOp("Lookup", &fission.InHeader{NodeID: 1, Unique: 2})
+
+	timeoutCtx, cancel = withBackendOpTimeout(inFlightOp.ctx, 10*time.Millisecond)
+	defer cancel()
+
+	<-timeoutCtx.Done()
+
+	err = checkBackendOpTimeout(timeoutCtx, 10*time.Millisecond, timeoutCtx.Err())
+	if !errors.Is(err, errBackendOpTimedOut) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("checkBackendOpTimeout() should have wrapped errBackendOpTimedOut and the original err, got %v", err)
+	}
+
+	if inFlightOp.ctx.Err() != nil {
+		t.Fatalf("a backend timeout should not cancel the op's context")
+	}
+
+	errno = syscall.ENOENT
+	endInFlightOp(inFlightOp, &errno)
+	if errno != syscall.EIO {
+		t.Fatalf("op whose backend call timed out should have returned EIO, got %v", errno)
+	}
+}
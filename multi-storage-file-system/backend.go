@@ -2,11 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // `setupContext` is called to establish the client that will be used
@@ -16,6 +30,16 @@ import (
 func (backend *backendStruct) setupContext() (err error) {
 	backend.backendPath = "<unknown>"
 
+	if (backend.maxConcurrentRequests != 0) && (backend.requestSlots == nil) {
+		backend.requestSlots = make(chan struct{}, backend.maxConcurrentRequests)
+	}
+	if backend.readBucket == nil {
+		backend.readBucket = newTokenBucket(backend.readBandwidthLimit)
+	}
+	if backend.requestBucket == nil {
+		backend.requestBucket = newTokenBucket(backend.requestRateLimit)
+	}
+
 	switch backend.backendType {
 	case "AIStore":
 		err = backend.setupAIStoreContext()
@@ -31,6 +55,10 @@ func (backend *backendStruct) setupContext() (err error) {
 		err = fmt.Errorf("for backend.dir_name \"%s\", unexpected backend_type \"%s\" (must be \"AIStore\", \"GCS\", \"PSEUDO\", \"RAM\", or \"S3\")", backend.dirName, backend.backendType)
 	}
 
+	if (err == nil) && (backend.chaos != nil) {
+		backend.setupChaosContext()
+	}
+
 	return
 }
 
@@ -39,35 +67,66 @@ func (backend *backendStruct) setupContext() (err error) {
 // backend type-specific implementation for each of these methods
 // must be provided.
 type backendContextIf interface {
+	// `abortMultipartUploads` is called to abort each multipart upload in progress beneath the
+	// backend's prefix that was initiated before abortMultipartUploadsInput.initiatedBefore (e.g.
+	// one abandoned by an interrupted copyFile()). Should the backend not support multipart
+	// uploads, errAbortMultipartUploadsNotSupported will be returned.
+	abortMultipartUploads(ctx context.Context, abortMultipartUploadsInput *abortMultipartUploadsInputStruct) (abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct, err error)
+
 	// `backendCommon` is called to return a pointer to the context's common `backendStruct`.
 	backendCommon() (backendCommon *backendStruct)
 
+	// `copyFile` is called to perform a server-side copy of the `file` at the specified source path to
+	// the specified destination path, replacing any existing `file` there. Should the backend not
+	// support such copies, errCopyFileNotSupported will be returned.
+	copyFile(ctx context.Context, copyFileInput *copyFileInputStruct) (copyFileOutput *copyFileOutputStruct, err error)
+
 	// `deleteFile` is called to remove a `file` at the specified path.
-	// If a `subdirectory` or nothing is found at that path, an error will be returned.
-	deleteFile(deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error)
+	// If a `subdirectory` or nothing is found at that path, an error will be returned. Where the
+	// backend reports that nothing is found (S3, for one, may instead quietly succeed), that
+	// error wraps errFileNotFound.
+	deleteFile(ctx context.Context, deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error)
 
 	// `listDirectory` is called to fetch a `page` of the `directory` at the specified path.
 	// An empty continuationToken or empty list of directory elements (`subdirectories` and `files`)
 	// indicates the `directory` has been completely enumerated. The `isTruncated` field will also
 	// align with this convention.
-	listDirectory(listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error)
+	listDirectory(ctx context.Context, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error)
 
 	// `listObjects` is called to fetch a `page` of the objects. An empty continuationToken or
 	// empty list of elements (`objects`) indicates the list of `objects` has been completely
 	// enumerated. The `isTruncated` field will also align with this convention.
-	listObjects(listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error)
+	listObjects(ctx context.Context, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error)
 
-	// `readFile` is called to read a range of a `file` at the specified path.
+	// `presignFile` is called to produce a time-limited URL via which the `file` at the specified
+	// path may be fetched (with a plain HTTP GET) without credentials. Should the backend not support
+	// such URLs, errPresignFileNotSupported will be returned.
+	presignFile(ctx context.Context, presignFileInput *presignFileInputStruct) (presignFileOutput *presignFileOutputStruct, err error)
+
+	// `putFile` is called to create (or replace) a `file` at the specified path with the supplied content.
+	putFile(ctx context.Context, putFileInput *putFileInputStruct) (putFileOutput *putFileOutputStruct, err error)
+
+	// `readFile` is called to read a byte range of a `file` at the specified path. The range
+	// is truncated at the end of the `file` (so fewer than readFileInput.length bytes may be returned).
 	// As error will result if either the specified path is not a `file` or non-existent.
-	readFile(readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error)
+	// If readFileInput.ifNoneMatch matches the `file`'s eTag, no content is returned and
+	// readFileOutput.notModified will be set instead.
+	readFile(ctx context.Context, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error)
+
+	// `restoreFile` is called to request that the archived content of the `file` at the specified
+	// path be restored such that it may be read. Should the backend not support (or not be configured
+	// to request) such restores, errRestoreFileNotSupported will be returned.
+	restoreFile(ctx context.Context, restoreFileInput *restoreFileInputStruct) (restoreFileOutput *restoreFileOutputStruct, err error)
 
 	// `statDirectory` is called to verify that the specified path refers to a `directory`.
 	// An error will result if either the specified path is not a `directory` or non-existent.
-	statDirectory(statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error)
+	statDirectory(ctx context.Context, statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error)
 
 	// `statFile` is called to fetch the `file` metadata at the specified path.
 	// As error will result if either the specified path is not a `file` or non-existent.
-	statFile(statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error)
+	// If statFileInput.ifNoneMatch matches the `file`'s eTag, statFileOutput.notModified
+	// will be set and only statFileOutput.eTag will be populated.
+	statFile(ctx context.Context, statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error)
 
 	// `redactSecrets` returns s with this backend's configured secret values
 	// (access keys, tokens, etc.) replaced by placeholders. Backends without
@@ -79,6 +138,45 @@ type backendContextIf interface {
 	// [TODO] writeFile equivalents: simple PUT as well as the exciting challenges of MPU
 }
 
+// `errAbortMultipartUploadsNotSupported` is returned by abortMultipartUploads() for backends without multipart uploads.
+var errAbortMultipartUploadsNotSupported = errors.New("abortMultipartUploads not supported")
+
+// `abortMultipartUploadsInputStruct` lays out the fields provided as input
+// to abortMultipartUploads().
+type abortMultipartUploadsInputStruct struct {
+	initiatedBefore time.Time // Only multipart uploads initiated before this time are aborted
+}
+
+// `abortedMultipartUploadStruct` describes each multipart upload aborted by abortMultipartUploads().
+type abortedMultipartUploadStruct struct {
+	filePath  string // Relative to backend.prefix
+	initiated time.Time
+}
+
+// `abortMultipartUploadsOutputStruct` lays out the fields produced as output
+// by abortMultipartUploads().
+type abortMultipartUploadsOutputStruct struct {
+	aborted []abortedMultipartUploadStruct
+}
+
+// `errCopyFileNotSupported` is returned by copyFile() for backends unable to perform server-side copies.
+var errCopyFileNotSupported = errors.New("copyFile not supported")
+
+// `copyFileInputStruct` lays out the fields provided as input
+// to copyFile().
+type copyFileInputStruct struct {
+	srcFilePath string           // Relative to backend.prefix
+	dstFilePath string           // Relative to backend.prefix
+	ifMatch     string           // If == "", then always matches existing source object; if != "", must match existing source object's eTag
+	srcContext  backendContextIf // If != nil, srcFilePath is instead relative to this other backend's prefix (only supported between S3 backends)
+}
+
+// `copyFileOutputStruct` lays out the fields produced as output
+// by copyFile().
+type copyFileOutputStruct struct {
+	eTag string // eTag of the newly created destination object
+}
+
 // `deleteFileInputStruct` lays out the fields provided as input
 // to deleteFile().
 type deleteFileInputStruct struct {
@@ -94,7 +192,7 @@ type deleteFileOutputStruct struct{}
 // to listDirectory().
 type listDirectoryInputStruct struct {
 	continuationToken string // If != "", from prior listDirectoryOutput.nextContinuationToken
-	startAfter        string // If != "", start listing after this key (relative to backend.prefix); ignored when continuationToken is set
+	startAfter        string // If != "", start listing after this key (relative to backend.prefix) to resume an interrupted traversal; ignored when continuationToken is set
 	maxItems          uint64 // If == 0, limited instead by the object server
 	dirPath           string // Relative to backend.prefix; if != "", should end with a trailing "/"
 }
@@ -165,19 +263,71 @@ type listPrefixOutputStruct struct {
 	stopped               bool // True if listing was stopped early because a key >= stopAt was encountered
 }
 
+// `errPresignFileNotSupported` is returned by presignFile() for backends unable to produce pre-signed URLs.
+var errPresignFileNotSupported = errors.New("presignFile not supported")
+
+// `presignFileInputStruct` lays out the fields provided as input
+// to presignFile().
+type presignFileInputStruct struct {
+	filePath string        // Relative to backend.prefix
+	expires  time.Duration // Lifetime of the URL
+}
+
+// `presignFileOutputStruct` lays out the fields produced as output
+// by presignFile().
+type presignFileOutputStruct struct {
+	url string
+}
+
+// `putFileInputStruct` lays out the fields provided as input
+// to putFile().
+type putFileInputStruct struct {
+	filePath string // Relative to backend.prefix
+	buf      []byte // The entire content of the `file`
+}
+
+// `putFileOutputStruct` lays out the fields produced as output
+// by putFile().
+type putFileOutputStruct struct {
+	eTag string // eTag of the newly created object
+}
+
 // `readFileInputStruct` lays out the fields provided as input
 // to readFile().
 type readFileInputStruct struct {
-	filePath        string // Relative to backend.prefix
-	offsetCacheLine uint64 // Read byte range [offsetCacheLine * backend.config.cacheLineSize:min((offsetCacheLine+1) * backend.config.cacheLineSize, <object size>))
-	ifMatch         string // If == "", then always matches existing object; if != "", must match existing object's eTag
+	filePath    string // Relative to backend.prefix
+	offset      uint64 // Read byte range [offset:min(offset+length, <object size>))
+	length      uint64 // Must be != 0
+	ifMatch     string // If == "", then always matches existing object; if != "", must match existing object's eTag
+	ifNoneMatch string // If != "" and matches existing object's eTag, nothing is read and readFileOutput.notModified is set
 }
 
 // `readFileOutputStruct` lays out the fields produced as output
 // by readFile().
 type readFileOutputStruct struct {
-	eTag string
-	buf  []byte
+	eTag        string
+	buf         []byte
+	notModified bool // If true, readFileInput.ifNoneMatch matched eTag and buf is empty
+}
+
+// `errObjectArchived` is wrapped by the error returned from readFile() when the content of
+// the `file` is archived (e.g. in the S3 GLACIER storage class) and must first be restored.
+var errObjectArchived = errors.New("object is archived")
+
+// `errRestoreFileNotSupported` is returned by restoreFile() for backends unable (or not
+// configured) to restore archived content.
+var errRestoreFileNotSupported = errors.New("restoreFile not supported")
+
+// `restoreFileInputStruct` lays out the fields provided as input
+// to restoreFile().
+type restoreFileInputStruct struct {
+	filePath string // Relative to backend.prefix
+}
+
+// `restoreFileOutputStruct` lays out the fields produced as output
+// by restoreFile().
+type restoreFileOutputStruct struct {
+	alreadyInProgress bool // If true, a restore of the `file` had already been requested
 }
 
 // `statDirectoryInputStruct` lays out the fields provided as input
@@ -195,23 +345,42 @@ type statDirectoryOutputStruct struct{}
 // `statFileInputStruct` lays out the fields provided as input
 // to statFile().
 type statFileInputStruct struct {
-	filePath string // Relative to backend.prefix
-	ifMatch  string // If == "", then always matches existing object; if != "", must match existing object's eTag
+	filePath    string // Relative to backend.prefix
+	ifMatch     string // If == "", then always matches existing object; if != "", must match existing object's eTag
+	ifNoneMatch string // If != "" and matches existing object's eTag, statFileOutput.notModified is set
 }
 
 // `statFileOutputStruct` lays out the fields produced as output
 // by statFile(). A failure indicates either a "subdirectory"
 // exists at that path or nothing does.
 type statFileOutputStruct struct {
-	eTag  string
-	mTime time.Time
-	size  uint64
+	eTag          string
+	mTime         time.Time
+	size          uint64
+	storageClass  string            // If != "", the backend-specific storage class of the object
+	restoreStatus string            // If != "", the object's content is archived and this is one of restoreStatus{Archived|Ongoing|Restored}
+	metadata      map[string]string // User-defined object metadata (if any)
+	contentType   string            // If != "", the object's Content-Type (only reported by S3)
+	notModified   bool              // If true, statFileInput.ifNoneMatch matched eTag and no other fields are populated
 }
 
+// `errFileNotFound` is wrapped by the error returned from statFile() when the backend has
+// definitively reported that no `file` exists at filePath (as opposed to having failed to
+// determine whether one does).
+var errFileNotFound = errors.New("file not found")
+
+const (
+	restoreStatusArchived = "archived" // The content must be restored before it may be read (and no restore has been requested)
+	restoreStatusOngoing  = "ongoing"  // A restore of the content has been requested but has yet to complete
+	restoreStatusRestored = "restored" // A (temporary) restored copy of the content may be read
+)
+
 // `recordRequest` records the request counter at the START of an operation.
 // Matches Python's behavior: request.sum is recorded BEFORE the operation executes (line 209).
 // This should be called immediately at the start of each backend operation (not in defer).
-// Note: Does not accept additional attributes to avoid high cardinality issues.
+// Note: Beyond those opted into via opentelemetry.metrics.backend_attributes (see
+// backendMetricsAttributes()), does not accept additional attributes to avoid high
+// cardinality issues.
 //
 // Call Chain:
 //
@@ -224,7 +393,7 @@ type statFileOutputStruct struct {
 //	MSCPMetricsDiperiodic.RecordBackendRequest() (metrics_diperiodic.go line 135)
 //	  ↓
 //	requestSumCounter.Add() (metrics_diperiodic.go line 146)
-func recordRequest(backendName, operation string) {
+func recordRequest(backend *backendStruct, operation string) {
 	if globals.metrics == nil {
 		return
 	}
@@ -240,7 +409,7 @@ func recordRequest(backendName, operation string) {
 		version = "dev" // Fallback for development builds
 	}
 
-	metrics.RecordBackendRequest(context.Background(), operation, version, backendName)
+	metrics.RecordBackendRequest(context.Background(), operation, version, backend.dirName, backendMetricsAttributes(backend))
 }
 
 // `recordBackendMetrics` is a helper to record metrics for backend operations.
@@ -248,8 +417,10 @@ func recordRequest(backendName, operation string) {
 // This records latency, response, data_size, etc. AFTER the operation completes.
 // Matches Python's behavior: these metrics are recorded in the finally block (lines 235-272).
 // This function is in backend.go (not backend_s3.go) so it can be used by all backend types.
-// Note: Does not accept additional attributes (like file paths) to avoid high cardinality issues.
-func recordBackendMetrics(backendName, operation string, startTime time.Time, err error, bytesTransferred int64) {
+// Note: Beyond those opted into via opentelemetry.metrics.backend_attributes (see
+// backendMetricsAttributes()), does not accept additional attributes (like file paths)
+// to avoid high cardinality issues.
+func recordBackendMetrics(backend *backendStruct, operation string, startTime time.Time, err error, bytesTransferred int64) {
 	if globals.metrics == nil {
 		return
 	}
@@ -268,27 +439,392 @@ func recordBackendMetrics(backendName, operation string, startTime time.Time, er
 		version = "dev" // Fallback for development builds
 	}
 
-	metrics.RecordBackendOperation(context.Background(), operation, version, backendName, duration, success, bytesTransferred)
+	metrics.RecordBackendOperation(context.Background(), operation, version, backend.dirName, backendMetricsAttributes(backend), duration, success, bytesTransferred)
+}
+
+// `backendMetricsAttributes` returns the attributes identifying backend that have been opted
+// into (due to their cardinality) via opentelemetry.metrics.backend_attributes such that
+// dashboards may break down latency and throughput per bucket. No attribute is offered for
+// backend.dirName as every backend metric already carries it as multistorageclient.provider.
+// A backend without a bucket_container_name (e.g. one using S3.discover_buckets) is given no
+// msfs.bucket attribute rather than an empty one.
+func backendMetricsAttributes(backend *backendStruct) (backendAttributes []attribute.KeyValue) {
+	var (
+		backendAttribute string
+	)
+
+	if (globals.config == nil) || (globals.config.observability == nil) {
+		return
+	}
+
+	for _, backendAttribute = range globals.config.observability.metricsBackendAttributes {
+		switch backendAttribute {
+		case metricsBackendAttributeBucket:
+			if backend.bucketContainerName != "" {
+				backendAttributes = append(backendAttributes, attribute.String("msfs.bucket", backend.bucketContainerName))
+			}
+		}
+	}
+
+	return
+}
+
+// `errorHintHTTPStatusRegexp` extracts the HTTP status from the error strings produced by
+// the various backend SDKs (e.g. "StatusCode: 403" for AWS/AIStore or "Error 403:" for GCS).
+var errorHintHTTPStatusRegexp = regexp.MustCompile(`(?i)(?:status ?code:? ?|\berror )(\d{3})\b`)
+
+// `errorHintFor` returns the hint text of the first configured error_hints entry
+// matching the HTTP status and/or error code substring found in errString.
+func errorHintFor(errString string) (hint string, ok bool) {
+	var (
+		errorHint  errorHintStruct
+		httpStatus uint64
+		submatch   []string
+	)
+
+	submatch = errorHintHTTPStatusRegexp.FindStringSubmatch(errString)
+	if submatch != nil {
+		httpStatus, _ = strconv.ParseUint(submatch[1], 10, 64)
+	}
+
+	for _, errorHint = range globals.config.errorHints {
+		if (errorHint.httpStatus != 0) && (errorHint.httpStatus != httpStatus) {
+			continue
+		}
+		if (errorHint.errorCode != "") && !strings.Contains(errString, errorHint.errorCode) {
+			continue
+		}
+
+		hint = errorHint.hint
+		ok = true
+		return
+	}
+
+	ok = false
+	return
+}
+
+// `annotateBackendError` appends the matching error_hints hint (if any) to a non-nil err
+// returned by a backend. The original err remains available via errors.Is() and errors.As().
+// Note that globals.config.errorHints may be read without holding globals lock since it
+// cannot be changed via SIGHUP.
+func annotateBackendError(err error) error {
+	var (
+		hint string
+		ok   bool
+	)
+
+	if (err == nil) || (len(globals.config.errorHints) == 0) {
+		return err
+	}
+
+	hint, ok = errorHintFor(err.Error())
+	if !ok {
+		return err
+	}
+
+	return fmt.Errorf("%w [hint: %s]", err, hint)
+}
+
+// `errBackendOpTimedOut` is the cause of a backend operation's context whose read_timeout,
+// list_timeout, head_timeout, or write_timeout expired.
+var errBackendOpTimedOut = errors.New("backend operation timed out")
+
+// `withBackendOpTimeout` returns a ctx derived from ctx that expires (with cause
+// errBackendOpTimedOut) after timeout. If timeout == 0, ctx is returned unchanged.
+func withBackendOpTimeout(ctx context.Context, timeout time.Duration) (timeoutCtx context.Context, cancel context.CancelFunc) {
+	if timeout == 0 {
+		timeoutCtx = ctx
+		cancel = func() {}
+		return
+	}
+
+	timeoutCtx, cancel = context.WithTimeoutCause(ctx, timeout, errBackendOpTimedOut)
+	return
+}
+
+// `checkBackendOpTimeout` examines the err returned by a backend operation performed with the
+// timeoutCtx from withBackendOpTimeout(). If that operation failed because timeout expired, the
+// in-flight FUSE op (if any) on whose behalf it was performed is marked such that it will fail
+// with EIO (rather than, say, ENOENT) and an err wrapping errBackendOpTimedOut is returned.
+func checkBackendOpTimeout(timeoutCtx context.Context, timeout time.Duration, err error) error {
+	if (err == nil) || !errors.Is(context.Cause(timeoutCtx), errBackendOpTimedOut) {
+		return err
+	}
+
+	markInFlightOpBackendTimedOut(timeoutCtx)
+
+	if errors.Is(err, errBackendOpTimedOut) {
+		return err
+	}
+
+	return fmt.Errorf("%w after %v: %w", errBackendOpTimedOut, timeout, err)
+}
+
+// `acquireRequestSlot` blocks until fewer than max_concurrent_requests backendContextIf calls
+// are outstanding for this backend. Should ctx be done first, context.Cause(ctx) is returned
+// instead. Each successful call must be paired with a call to releaseRequestSlot().
+func (backend *backendStruct) acquireRequestSlot(ctx context.Context) (err error) {
+	if backend.requestSlots == nil {
+		return
+	}
+
+	select {
+	case backend.requestSlots <- struct{}{}:
+		err = nil
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	}
+
+	return
+}
+
+// `releaseRequestSlot` returns the slot obtained by a successful acquireRequestSlot().
+func (backend *backendStruct) releaseRequestSlot() {
+	if backend.requestSlots == nil {
+		return
+	}
+
+	<-backend.requestSlots
+}
+
+// `keySaltSeparator` follows the salt prepended to the basename of each object key when
+// backend.keySaltWidth != 0 (e.g. "dir/3f_file" for the logical path "dir/file").
+const keySaltSeparator = "_"
+
+// `keySalt` returns the leading keySaltWidth hex digits of the CRC32 of basename.
+func keySalt(basename string, keySaltWidth uint64) (salt string) {
+	salt = fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(basename)))[:keySaltWidth]
+	return
+}
+
+// `saltFilePath` converts the logical filePath presented through the mount into the object key
+// under which the backend stores it. With key_salt_width set, a hash of the basename is prepended
+// to it so that objects written under a single prefix are spread across many key ranges (and,
+// hence, S3 partitions). Directory path elements are never salted.
+func (backend *backendStruct) saltFilePath(filePath string) (saltedFilePath string) {
+	var (
+		basename string
+		dirPath  string
+	)
+
+	if backend.keySaltWidth == 0 {
+		saltedFilePath = filePath
+		return
+	}
+
+	dirPath, basename = path.Split(filePath)
+
+	saltedFilePath = dirPath + keySalt(basename, backend.keySaltWidth) + keySaltSeparator + basename
+	return
+}
+
+// `desaltBasename` reverses saltFilePath() for a file basename returned by listDirectory(). Basenames
+// not prefixed by their own salt (e.g. objects written before key_salt_width was set) are returned
+// unchanged. As such objects would not be found at their salted key, seeing one sets
+// backend.unsaltedSeen such that statFileWrapper() and readFileWrapper() thereafter retry a miss at
+// the unsalted key (while a prefix holding only salted objects never pays for that extra request).
+func (backend *backendStruct) desaltBasename(saltedBasename string) (basename string) {
+	var (
+		saltLen = int(backend.keySaltWidth) + len(keySaltSeparator)
+	)
+
+	if backend.keySaltWidth == 0 {
+		basename = saltedBasename
+		return
+	}
+
+	if (len(saltedBasename) <= saltLen) || (saltedBasename[saltLen-len(keySaltSeparator):saltLen] != keySaltSeparator) {
+		basename = saltedBasename
+		backend.unsaltedSeen.Store(true)
+		return
+	}
+
+	basename = saltedBasename[saltLen:]
+
+	if saltedBasename[:saltLen-len(keySaltSeparator)] != keySalt(basename, backend.keySaltWidth) {
+		basename = saltedBasename
+		backend.unsaltedSeen.Store(true)
+	}
+
+	return
+}
+
+// `abortMultipartUploadsWrapper` is a wrapper function around the supplied backendContext's `abortMultipartUploads` function enabling centralized metrics and tracing capture.
+func abortMultipartUploadsWrapper(ctx context.Context, backendContext backendContextIf, abortMultipartUploadsInput *abortMultipartUploadsInputStruct) (abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct, err error) {
+	var (
+		backendCommon = backendContext.backendCommon()
+		cancel        context.CancelFunc
+		retryHistory  *retryHistoryStruct
+		span          trace.Span
+		startTime     time.Time
+	)
+
+	recordRequest(backendCommon, "abortMultipartUploads")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "abortMultipartUploads", attribute.String("msfs.initiated_before", abortMultipartUploadsInput.initiatedBefore.UTC().Format(time.RFC3339)))
+
+	ctx, retryHistory = withRetryHistory(ctx)
+
+	startTime = time.Now()
+
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.writeTimeout)
+	defer cancel()
+
+	err = backendCommon.acquireRequestSlot(ctx)
+	if err == nil {
+		abortMultipartUploadsOutput, err = backendContext.abortMultipartUploads(ctx, abortMultipartUploadsInput)
+		backendCommon.releaseRequestSlot()
+	}
+
+	err = checkBackendOpTimeout(ctx, backendCommon.writeTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
+	if (err == nil) && (len(abortMultipartUploadsOutput.aborted) > 0) {
+		globals.backendMetrics.MultipartUploadsAborted.Add(float64(len(abortMultipartUploadsOutput.aborted)))
+		backendCommon.backendMetrics.MultipartUploadsAborted.Add(float64(len(abortMultipartUploadsOutput.aborted)))
+	}
+
+	recordBackendMetrics(backendCommon, "abortMultipartUploads", startTime, err, 0)
+
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.abortMultipartUploads(%#v) returning err: %v", backendCommon.dirName, abortMultipartUploadsInput, err)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.abortMultipartUploads(%#v) aborted %d multipart upload(s)", backendCommon.dirName, abortMultipartUploadsInput, len(abortMultipartUploadsOutput.aborted))
+	}
+
+	return
+}
+
+// `copyFileWrapper` is a wrapper function around the supplied backendContext's `copyFile` function enabling centralized metrics and tracing capture.
+func copyFileWrapper(ctx context.Context, backendContext backendContextIf, copyFileInput *copyFileInputStruct) (copyFileOutput *copyFileOutputStruct, err error) {
+	var (
+		backendCommon    = backendContext.backendCommon()
+		cancel           context.CancelFunc
+		latency          float64
+		retryHistory     *retryHistoryStruct
+		span             trace.Span
+		srcBackendCommon = backendCommon
+		startTime        time.Time
+	)
+
+	if copyFileInput.srcContext != nil {
+		srcBackendCommon = copyFileInput.srcContext.backendCommon()
+	}
+
+	recordRequest(backendCommon, "copyFile")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "copyFile", attribute.String("msfs.src_path", copyFileInput.srcFilePath), attribute.String("msfs.dst_path", copyFileInput.dstFilePath))
+
+	ctx, retryHistory = withRetryHistory(ctx)
+
+	startTime = time.Now()
+
+	if (backendCommon.keySaltWidth != 0) || (srcBackendCommon.keySaltWidth != 0) {
+		copyFileInputCopy := *copyFileInput
+		copyFileInputCopy.srcFilePath = srcBackendCommon.saltFilePath(copyFileInput.srcFilePath)
+		copyFileInputCopy.dstFilePath = backendCommon.saltFilePath(copyFileInput.dstFilePath)
+		copyFileInput = &copyFileInputCopy
+	}
+
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.writeTimeout)
+	defer cancel()
+
+	err = backendCommon.throttle(ctx, 0)
+	if err == nil {
+		err = backendCommon.acquireRequestSlot(ctx)
+	}
+	if err == nil {
+		copyFileOutput, err = backendContext.copyFile(ctx, copyFileInput)
+		backendCommon.releaseRequestSlot()
+	}
+
+	latency = time.Since(startTime).Seconds()
+
+	err = checkBackendOpTimeout(ctx, backendCommon.writeTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
+	go func(backend *backendStruct, latency float64, err error) {
+		globalsLock("backend.go:754:3:funcLit@753")
+		if err == nil {
+			globals.backendMetrics.CopyFileSuccesses.Inc()
+			globals.backendMetrics.CopyFileSuccessLatencies.Observe(latency)
+
+			backend.backendMetrics.CopyFileSuccesses.Inc()
+			backend.backendMetrics.CopyFileSuccessLatencies.Observe(latency)
+		} else {
+			globals.backendMetrics.CopyFileFailures.Inc()
+			globals.backendMetrics.CopyFileFailureLatencies.Observe(latency)
+
+			backend.backendMetrics.CopyFileFailures.Inc()
+			backend.backendMetrics.CopyFileFailureLatencies.Observe(latency)
+		}
+		retryHistory.observe(globals.backendMetrics, err)
+		retryHistory.observe(backend.backendMetrics, err)
+		globalsUnlock()
+	}(backendCommon, latency, err)
+
+	recordBackendMetrics(backendCommon, "copyFile", startTime, err, 0)
+
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.copyFile(%#v) returning err: %v", backendCommon.dirName, copyFileInput, err)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.copyFile(%#v) returning copyFileOutput: %#v", backendCommon.dirName, copyFileInput, copyFileOutput)
+	}
+
+	return
 }
 
 // `deleteFileWrapper` is a wrapper function around the supplied backendContext's `deleteFile` function enabling centralized metrics and tracing capture.
-func deleteFileWrapper(backendContext backendContextIf, deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
+func deleteFileWrapper(ctx context.Context, backendContext backendContextIf, deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
 	var (
 		backendCommon = backendContext.backendCommon()
+		cancel        context.CancelFunc
 		latency       float64
+		retryHistory  *retryHistoryStruct
+		span          trace.Span
 		startTime     time.Time
 	)
 
-	recordRequest(backendCommon.dirName, "deleteFile")
+	recordRequest(backendCommon, "deleteFile")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "deleteFile", attribute.String("msfs.path", deleteFileInput.filePath))
+
+	ctx, retryHistory = withRetryHistory(ctx)
 
 	startTime = time.Now()
 
-	deleteFileOutput, err = backendContext.deleteFile(deleteFileInput)
+	if backendCommon.keySaltWidth != 0 {
+		deleteFileInputCopy := *deleteFileInput
+		deleteFileInputCopy.filePath = backendCommon.saltFilePath(deleteFileInput.filePath)
+		deleteFileInput = &deleteFileInputCopy
+	}
+
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.writeTimeout)
+	defer cancel()
+
+	err = backendCommon.acquireRequestSlot(ctx)
+	if err == nil {
+		deleteFileOutput, err = backendContext.deleteFile(ctx, deleteFileInput)
+		backendCommon.releaseRequestSlot()
+	}
 
 	latency = time.Since(startTime).Seconds()
 
+	err = checkBackendOpTimeout(ctx, backendCommon.writeTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
 	go func(backend *backendStruct, latency float64, err error) {
-		globalsLock("backend.go:291:3:funcLit@290")
+		globalsLock("backend.go:827:3:funcLit@826")
 		if err == nil {
 			globals.backendMetrics.DeleteFileSuccesses.Inc()
 			globals.backendMetrics.DeleteFileSuccessLatencies.Observe(latency)
@@ -302,47 +838,71 @@ func deleteFileWrapper(backendContext backendContextIf, deleteFileInput *deleteF
 			backend.backendMetrics.DeleteFileFailures.Inc()
 			backend.backendMetrics.DeleteFileFailureLatencies.Observe(latency)
 		}
+		retryHistory.observe(globals.backendMetrics, err)
+		retryHistory.observe(backend.backendMetrics, err)
 		globalsUnlock()
 	}(backendCommon, latency, err)
 
-	recordBackendMetrics(backendCommon.dirName, "deleteFile", startTime, err, 0)
+	recordBackendMetrics(backendCommon, "deleteFile", startTime, err, 0)
 
-	switch backendCommon.traceLevel {
-	case 0:
-		// Trace nothing
-	case 1:
-		if err != nil {
-			globals.logger.Printf("[WARN] %s.deleteFile(%#v) returning err: %v", backendCommon.dirName, deleteFileInput, err)
-		}
-	default:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.deleteFile(%#v) succeeded", backendCommon.dirName, deleteFileInput)
-		} else {
-			globals.logger.Printf("[WARN] %s.deleteFile(%#v) returning err: %v", backendCommon.dirName, deleteFileInput, err)
-		}
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.deleteFile(%#v) returning err: %v", backendCommon.dirName, deleteFileInput, err)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.deleteFile(%#v) succeeded", backendCommon.dirName, deleteFileInput)
 	}
 
 	return
 }
 
 // `listDirectoryWrapper` is a wrapper function around the supplied backendContext's `listDirectory` function enabling centralized metrics and tracing capture.
-func listDirectoryWrapper(backendContext backendContextIf, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+func listDirectoryWrapper(ctx context.Context, backendContext backendContextIf, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
 	var (
 		backendCommon = backendContext.backendCommon()
+		cancel        context.CancelFunc
 		latency       float64
+		retryHistory  *retryHistoryStruct
+		span          trace.Span
 		startTime     time.Time
 	)
 
-	recordRequest(backendCommon.dirName, "listDirectory")
+	recordRequest(backendCommon, "listDirectory")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "listDirectory", attribute.String("msfs.path", listDirectoryInput.dirPath))
+
+	ctx, retryHistory = withRetryHistory(ctx)
 
 	startTime = time.Now()
 
-	listDirectoryOutput, err = backendContext.listDirectory(listDirectoryInput)
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.listTimeout)
+	defer cancel()
+
+	err = backendCommon.throttle(ctx, 0)
+	if err == nil {
+		err = backendCommon.acquireRequestSlot(ctx)
+	}
+	if err == nil {
+		listDirectoryOutput, err = backendContext.listDirectory(ctx, listDirectoryInput)
+		backendCommon.releaseRequestSlot()
+	}
+	if (err == nil) && (backendCommon.keySaltWidth != 0) {
+		for fileIndex := range listDirectoryOutput.file {
+			listDirectoryOutput.file[fileIndex].basename = backendCommon.desaltBasename(listDirectoryOutput.file[fileIndex].basename)
+		}
+	}
+	if err == nil {
+		backendCommon.filter.filterListDirectoryOutput(listDirectoryInput.dirPath, listDirectoryOutput)
+	}
 
 	latency = time.Since(startTime).Seconds()
 
+	err = checkBackendOpTimeout(ctx, backendCommon.listTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
 	go func(backend *backendStruct, latency float64, err error) {
-		globalsLock("backend.go:345:3:funcLit@344")
+		globalsLock("backend.go:905:3:funcLit@904")
 		if err == nil {
 			globals.backendMetrics.ListDirectorySuccesses.Inc()
 			globals.backendMetrics.ListDirectorySuccessLatencies.Observe(latency)
@@ -356,54 +916,63 @@ func listDirectoryWrapper(backendContext backendContextIf, listDirectoryInput *l
 			backend.backendMetrics.ListDirectoryFailures.Inc()
 			backend.backendMetrics.ListDirectoryFailureLatencies.Observe(latency)
 		}
+		retryHistory.observe(globals.backendMetrics, err)
+		retryHistory.observe(backend.backendMetrics, err)
 		globalsUnlock()
 	}(backendCommon, latency, err)
 
-	recordBackendMetrics(backendCommon.dirName, "listDirectory", startTime, err, 0)
+	recordBackendMetrics(backendCommon, "listDirectory", startTime, err, 0)
 
-	switch backendCommon.traceLevel {
-	case 0:
-		// Trace nothing
-	case 1:
-		if err != nil {
-			globals.logger.Printf("[WARN] %s.listDirectory(%#v) returning err: %v", backendCommon.dirName, listDirectoryInput, err)
-		}
-	case 2:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.listDirectory(%#v) succeeded", backendCommon.dirName, listDirectoryInput)
-		} else {
-			globals.logger.Printf("[WARN] %s.listDirectory(%#v) returning err: %v", backendCommon.dirName, listDirectoryInput, err)
-		}
-	default:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.listDirectory(%#v) returning listDirectoryOutput: {len(\"subdirectory\"):%v,len(\"file\"):%v,nextContinuationToken:\"%s\",isTruncated:%v}", backendCommon.dirName, listDirectoryInput, len(listDirectoryOutput.subdirectory), len(listDirectoryOutput.file), listDirectoryOutput.nextContinuationToken, listDirectoryOutput.isTruncated)
-		} else {
-			globals.logger.Printf("[WARN] %s.listDirectory(%#v) returning err: %v", backendCommon.dirName, listDirectoryInput, err)
-		}
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.listDirectory(%#v) returning err: %v", backendCommon.dirName, listDirectoryInput, err)
+	} else if backendCommon.logEnabled(slog.LevelDebug) {
+		backendCommon.logf(slog.LevelDebug, "%s.listDirectory(%#v) returning listDirectoryOutput: {len(\"subdirectory\"):%v,len(\"file\"):%v,nextContinuationToken:\"%s\",isTruncated:%v}", backendCommon.dirName, listDirectoryInput, len(listDirectoryOutput.subdirectory), len(listDirectoryOutput.file), listDirectoryOutput.nextContinuationToken, listDirectoryOutput.isTruncated)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.listDirectory(%#v) succeeded", backendCommon.dirName, listDirectoryInput)
 	}
 
 	return
 }
 
 // `listObjectsWrapper` is a wrapper function around the supplied backendContext's `listObjects` function enabling centralized metrics and tracing capture.
-func listObjectsWrapper(backendContext backendContextIf, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+func listObjectsWrapper(ctx context.Context, backendContext backendContextIf, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
 	var (
 		backendCommon = backendContext.backendCommon()
+		cancel        context.CancelFunc
 		latency       float64
+		retryHistory  *retryHistoryStruct
+		span          trace.Span
 		startTime     time.Time
 	)
 
-	recordRequest(backendCommon.dirName, "listObjects")
+	recordRequest(backendCommon, "listObjects")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "listObjects", attribute.String("msfs.prefix", listObjectsInput.prefix))
+
+	ctx, retryHistory = withRetryHistory(ctx)
 
 	startTime = time.Now()
 
-	listObjectsOutput, err = backendContext.listObjects(listObjectsInput)
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.listTimeout)
+	defer cancel()
+
+	err = backendCommon.acquireRequestSlot(ctx)
+	if err == nil {
+		listObjectsOutput, err = backendContext.listObjects(ctx, listObjectsInput)
+		backendCommon.releaseRequestSlot()
+	}
 
 	latency = time.Since(startTime).Seconds()
 
+	err = checkBackendOpTimeout(ctx, backendCommon.listTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
 	if globals.backendMetrics != nil && backendCommon.backendMetrics != nil {
 		go func(backend *backendStruct, latency float64, err error) {
-			globalsLock("backend.go:406:4:funcLit@405")
+			globalsLock("backend.go:975:4:funcLit@974")
 			if err == nil {
 				globals.backendMetrics.ListObjectsSuccesses.Inc()
 				globals.backendMetrics.ListObjectsSuccessLatencies.Observe(latency)
@@ -417,31 +986,54 @@ func listObjectsWrapper(backendContext backendContextIf, listObjectsInput *listO
 				backend.backendMetrics.ListObjectsFailures.Inc()
 				backend.backendMetrics.ListObjectsFailureLatencies.Observe(latency)
 			}
+			retryHistory.observe(globals.backendMetrics, err)
+			retryHistory.observe(backend.backendMetrics, err)
 			globalsUnlock()
 		}(backendCommon, latency, err)
 	}
 
-	recordBackendMetrics(backendCommon.dirName, "listObjects", startTime, err, 0)
+	recordBackendMetrics(backendCommon, "listObjects", startTime, err, 0)
 
-	switch backendCommon.traceLevel {
-	case 0:
-		// Trace nothing
-	case 1:
-		if err != nil {
-			globals.logger.Printf("[WARN] %s.listObjects(%#v) returning err: %v", backendCommon.dirName, listObjectsInput, err)
-		}
-	case 2:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.listObjects(%#v) succeeded", backendCommon.dirName, listObjectsInput)
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.listObjects(%#v) returning err: %v", backendCommon.dirName, listObjectsInput, err)
+	} else if backendCommon.logEnabled(slog.LevelDebug) {
+		backendCommon.logf(slog.LevelDebug, "%s.listObjects(%#v) returning listDirectoryOutput: {len(\"object\"):%v,nextContinuationToken:\"%s\",isTruncated:%v}", backendCommon.dirName, listObjectsInput, len(listObjectsOutput.object), listObjectsOutput.nextContinuationToken, listObjectsOutput.isTruncated)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.listObjects(%#v) succeeded", backendCommon.dirName, listObjectsInput)
+	}
+
+	return
+}
+
+// `listDirectoryStartAfterIndex` is a helper for backends whose listDirectory() continuation
+// tokens are an index into the directory's entries (ordered as all subdirectories followed by
+// all files). It returns the index at which to resume listing after listDirectoryInput.startAfter.
+// The supplied funcs return the number of subdirectories or files, respectively, whose basename
+// is lexicographically less than or equal to the supplied basename.
+func listDirectoryStartAfterIndex(listDirectoryInput *listDirectoryInputStruct, numSubdirectories, numFiles uint64, subdirectoriesAtOrBefore, filesAtOrBefore func(basename string) uint64) (index uint64) {
+	var (
+		basename       string
+		isSubdirectory bool
+	)
+
+	if !strings.HasPrefix(listDirectoryInput.startAfter, listDirectoryInput.dirPath) {
+		if listDirectoryInput.startAfter < listDirectoryInput.dirPath {
+			index = 0
 		} else {
-			globals.logger.Printf("[WARN] %s.listObjects(%#v) returning err: %v", backendCommon.dirName, listObjectsInput, err)
+			index = numSubdirectories + numFiles
 		}
+		return
+	}
+
+	basename, _, isSubdirectory = strings.Cut(strings.TrimPrefix(listDirectoryInput.startAfter, listDirectoryInput.dirPath), "/")
+
+	switch {
+	case basename == "":
+		index = 0
+	case isSubdirectory:
+		index = subdirectoriesAtOrBefore(basename)
 	default:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.listObjects(%#v) returning listDirectoryOutput: {len(\"object\"):%v,nextContinuationToken:\"%s\",isTruncated:%v}", backendCommon.dirName, listObjectsInput, len(listObjectsOutput.object), listObjectsOutput.nextContinuationToken, listObjectsOutput.isTruncated)
-		} else {
-			globals.logger.Printf("[WARN] %s.listObjects(%#v) returning err: %v", backendCommon.dirName, listObjectsInput, err)
-		}
+		index = numSubdirectories + filesAtOrBefore(basename)
 	}
 
 	return
@@ -454,7 +1046,7 @@ func listObjectsWrapper(backendContext backendContextIf, listObjectsInput *listO
 // StopAt bound and basename trimming are applied here, client-side, so this works for
 // every backend that implements listObjects(). Metrics and tracing are captured by the
 // underlying listObjectsWrapper() (so listObjects metrics cover these operations).
-func listPrefixWrapper(backendContext backendContextIf, listPrefixInput *listPrefixInputStruct) (listPrefixOutput *listPrefixOutputStruct, err error) {
+func listPrefixWrapper(ctx context.Context, backendContext backendContextIf, listPrefixInput *listPrefixInputStruct) (listPrefixOutput *listPrefixOutputStruct, err error) {
 	var (
 		listObjectsOutput *listObjectsOutputStruct
 		object            listObjectsOutputObjectStruct
@@ -469,7 +1061,7 @@ func listPrefixWrapper(backendContext backendContextIf, listPrefixInput *listPre
 		startAfter = ""
 	}
 
-	listObjectsOutput, err = listObjectsWrapper(backendContext, &listObjectsInputStruct{
+	listObjectsOutput, err = listObjectsWrapper(ctx, backendContext, &listObjectsInputStruct{
 		prefix:            listPrefixInput.prefix,
 		startAfter:        startAfter,
 		continuationToken: listPrefixInput.continuationToken,
@@ -515,6 +1107,93 @@ func redactSecrets(backend *backendStruct, s string) string {
 	return redactAWSSecretShapes(s)
 }
 
+// `newBackendTLSConfig` returns the TLS client configuration for a backend's HTTP transport
+// or nil if the defaults (verifying against the system trust store without presenting a
+// client certificate) apply. If caBundleFile is set, the PEM-encoded certificates it contains
+// are trusted instead of the system trust store. If clientCertFile and clientKeyFile are set,
+// the PEM-encoded certificate/key pair is presented for mutual TLS.
+func newBackendTLSConfig(skipTLSCertificateVerify bool, caBundleFile, clientCertFile, clientKeyFile string) (tlsConfig *tls.Config, err error) {
+	var (
+		caBundle   []byte
+		clientCert tls.Certificate
+	)
+
+	if !skipTLSCertificateVerify && (caBundleFile == "") && (clientCertFile == "") {
+		return
+	}
+
+	tlsConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if skipTLSCertificateVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if caBundleFile != "" {
+		caBundle, err = os.ReadFile(caBundleFile)
+		if err != nil {
+			err = fmt.Errorf("unable to read ca_bundle_file: %v", err)
+			return
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caBundle) {
+			err = fmt.Errorf("no PEM-encoded certificates found in ca_bundle_file (\"%s\")", caBundleFile)
+			return
+		}
+	}
+
+	if clientCertFile != "" {
+		clientCert, err = tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+		if err != nil {
+			err = fmt.Errorf("unable to load client_cert_file/client_key_file: %v", err)
+			return
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return
+}
+
+// `newBackendProxyFunc` returns the http.Transport.Proxy func for a backend or nil if neither
+// proxy_url nor no_proxy is set (leaving the transport's default in place). A proxy_url
+// overrides the ${HTTP_PROXY} and ${HTTPS_PROXY} environment variables while no_proxy (with
+// the same syntax as ${NO_PROXY}) supplements ${NO_PROXY}. Note that requests to localhost
+// are never proxied.
+func newBackendProxyFunc(proxyURL, noProxy string) func(*http.Request) (*url.URL, error) {
+	var (
+		proxyConfig *httpproxy.Config
+		proxyFunc   func(*url.URL) (*url.URL, error)
+	)
+
+	if (proxyURL == "") && (noProxy == "") {
+		return nil
+	}
+
+	proxyConfig = httpproxy.FromEnvironment()
+
+	if proxyURL != "" {
+		proxyConfig.HTTPProxy = proxyURL
+		proxyConfig.HTTPSProxy = proxyURL
+	}
+
+	if noProxy != "" {
+		if proxyConfig.NoProxy == "" {
+			proxyConfig.NoProxy = noProxy
+		} else {
+			proxyConfig.NoProxy += "," + noProxy
+		}
+	}
+
+	proxyFunc = proxyConfig.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
 // `redactValue` replaces every occurrence of secret in s with placeholder. Values
 // shorter than 8 characters are ignored to avoid redacting incidental substrings
 // (and empty secrets, which would otherwise match everywhere).
@@ -525,25 +1204,189 @@ func redactValue(s, secret, placeholder string) string {
 	return strings.ReplaceAll(s, secret, placeholder)
 }
 
-// `readFileWrapper` is a wrapper function around the supplied backendContext's `readFile` function enabling centralized metrics and tracing capture.
-func readFileWrapper(backendContext backendContextIf, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
+// `presignFileWrapper` is a wrapper function around the supplied backendContext's `presignFile` function enabling centralized metrics and tracing capture.
+func presignFileWrapper(ctx context.Context, backendContext backendContextIf, presignFileInput *presignFileInputStruct) (presignFileOutput *presignFileOutputStruct, err error) {
+	var (
+		backendCommon = backendContext.backendCommon()
+		cancel        context.CancelFunc
+		retryHistory  *retryHistoryStruct
+		span          trace.Span
+		startTime     time.Time
+	)
+
+	recordRequest(backendCommon, "presignFile")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "presignFile", attribute.String("msfs.path", presignFileInput.filePath))
+
+	ctx, retryHistory = withRetryHistory(ctx)
+
+	startTime = time.Now()
+
+	if backendCommon.keySaltWidth != 0 {
+		presignFileInputCopy := *presignFileInput
+		presignFileInputCopy.filePath = backendCommon.saltFilePath(presignFileInput.filePath)
+		presignFileInput = &presignFileInputCopy
+	}
+
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.headTimeout)
+	defer cancel()
+
+	err = backendCommon.throttle(ctx, 0)
+	if err == nil {
+		err = backendCommon.acquireRequestSlot(ctx)
+	}
+	if err == nil {
+		presignFileOutput, err = backendContext.presignFile(ctx, presignFileInput)
+		backendCommon.releaseRequestSlot()
+	}
+
+	err = checkBackendOpTimeout(ctx, backendCommon.headTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
+	recordBackendMetrics(backendCommon, "presignFile", startTime, err, 0)
+
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.presignFile(%#v) returning err: %v", backendCommon.dirName, presignFileInput, err)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.presignFile(%#v) succeeded", backendCommon.dirName, presignFileInput)
+	}
+
+	return
+}
+
+// `putFileWrapper` is a wrapper function around the supplied backendContext's `putFile` function enabling centralized metrics and tracing capture.
+func putFileWrapper(ctx context.Context, backendContext backendContextIf, putFileInput *putFileInputStruct) (putFileOutput *putFileOutputStruct, err error) {
 	var (
 		backendCommon = backendContext.backendCommon()
-		bytesRead     = int64(0)
+		cancel        context.CancelFunc
 		latency       float64
+		retryHistory  *retryHistoryStruct
+		span          trace.Span
 		startTime     time.Time
 	)
 
-	recordRequest(backendCommon.dirName, "readFile")
+	recordRequest(backendCommon, "putFile")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "putFile", attribute.String("msfs.path", putFileInput.filePath), attribute.Int("msfs.length", len(putFileInput.buf)))
+
+	ctx, retryHistory = withRetryHistory(ctx)
+
+	startTime = time.Now()
+
+	if backendCommon.keySaltWidth != 0 {
+		putFileInputCopy := *putFileInput
+		putFileInputCopy.filePath = backendCommon.saltFilePath(putFileInput.filePath)
+		putFileInput = &putFileInputCopy
+	}
+
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.writeTimeout)
+	defer cancel()
+
+	err = backendCommon.acquireRequestSlot(ctx)
+	if err == nil {
+		putFileOutput, err = backendContext.putFile(ctx, putFileInput)
+		backendCommon.releaseRequestSlot()
+	}
+
+	latency = time.Since(startTime).Seconds()
+
+	err = checkBackendOpTimeout(ctx, backendCommon.writeTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
+	go func(backend *backendStruct, latency float64, err error) {
+		globalsLock("backend.go:1303:3:funcLit@1302")
+		if err == nil {
+			globals.backendMetrics.PutFileSuccesses.Inc()
+			globals.backendMetrics.PutFileSuccessLatencies.Observe(latency)
+
+			backend.backendMetrics.PutFileSuccesses.Inc()
+			backend.backendMetrics.PutFileSuccessLatencies.Observe(latency)
+		} else {
+			globals.backendMetrics.PutFileFailures.Inc()
+			globals.backendMetrics.PutFileFailureLatencies.Observe(latency)
+
+			backend.backendMetrics.PutFileFailures.Inc()
+			backend.backendMetrics.PutFileFailureLatencies.Observe(latency)
+		}
+		retryHistory.observe(globals.backendMetrics, err)
+		retryHistory.observe(backend.backendMetrics, err)
+		globalsUnlock()
+	}(backendCommon, latency, err)
+
+	recordBackendMetrics(backendCommon, "putFile", startTime, err, int64(len(putFileInput.buf)))
+
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.putFile(\"%s\",len(buf):%v) returning err: %v", backendCommon.dirName, putFileInput.filePath, len(putFileInput.buf), err)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.putFile(\"%s\",len(buf):%v) returning putFileOutput: %#v", backendCommon.dirName, putFileInput.filePath, len(putFileInput.buf), putFileOutput)
+	}
+
+	return
+}
+
+// `readFileWrapper` is a wrapper function around the supplied backendContext's `readFile` function enabling centralized metrics and tracing capture.
+func readFileWrapper(ctx context.Context, backendContext backendContextIf, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
+	var (
+		backendCommon         = backendContext.backendCommon()
+		cancel                context.CancelFunc
+		bytesRead             = int64(0)
+		latency               float64
+		retryHistory          *retryHistoryStruct
+		span                  trace.Span
+		startTime             time.Time
+		unsaltedReadFileInput *readFileInputStruct
+	)
+
+	recordRequest(backendCommon, "readFile")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "readFile", attribute.String("msfs.path", readFileInput.filePath))
+
+	ctx, retryHistory = withRetryHistory(ctx)
 
 	startTime = time.Now()
 
-	readFileOutput, err = backendContext.readFile(readFileInput)
+	if backendCommon.keySaltWidth != 0 {
+		unsaltedReadFileInput = readFileInput
+		readFileInputCopy := *readFileInput
+		readFileInputCopy.filePath = backendCommon.saltFilePath(readFileInput.filePath)
+		readFileInput = &readFileInputCopy
+	}
+
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.readTimeout)
+	defer cancel()
+
+	err = backendCommon.throttle(ctx, readFileInput.length)
+	if err == nil {
+		err = backendCommon.acquireRequestSlot(ctx)
+	}
+	if err == nil {
+		readFileOutput, err = backendContext.readFile(ctx, readFileInput)
+		if (unsaltedReadFileInput != nil) && errors.Is(err, errFileNotFound) && backendCommon.unsaltedSeen.Load() {
+			// The object may predate key_salt_width (see desaltBasename())
+			readFileOutput, err = backendContext.readFile(ctx, unsaltedReadFileInput)
+		}
+		backendCommon.releaseRequestSlot()
+		if (err == nil) && (readFileOutput != nil) && (uint64(len(readFileOutput.buf)) < readFileInput.length) {
+			backendCommon.readBucket.give(readFileInput.length - uint64(len(readFileOutput.buf))) // Refund that not read (e.g. at EOF)
+		}
+	}
 
 	latency = time.Since(startTime).Seconds()
 
+	err = checkBackendOpTimeout(ctx, backendCommon.readTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
 	go func(backend *backendStruct, latency float64, err error) {
-		globalsLock("backend.go:546:3:funcLit@545")
+		globalsLock("backend.go:1389:3:funcLit@1388")
 		if err == nil {
 			globals.backendMetrics.ReadFileSuccesses.Inc()
 			globals.backendMetrics.ReadFileSuccessLatencies.Observe(latency)
@@ -557,6 +1400,8 @@ func readFileWrapper(backendContext backendContextIf, readFileInput *readFileInp
 			backend.backendMetrics.ReadFileFailures.Inc()
 			backend.backendMetrics.ReadFileFailureLatencies.Observe(latency)
 		}
+		retryHistory.observe(globals.backendMetrics, err)
+		retryHistory.observe(backend.backendMetrics, err)
 		globalsUnlock()
 	}(backendCommon, latency, err)
 
@@ -564,50 +1409,136 @@ func readFileWrapper(backendContext backendContextIf, readFileInput *readFileInp
 		bytesRead = int64(len(readFileOutput.buf))
 	}
 
-	recordBackendMetrics(backendCommon.dirName, "readFile", startTime, err, bytesRead)
+	recordBackendMetrics(backendCommon, "readFile", startTime, err, bytesRead)
 
-	switch backendCommon.traceLevel {
-	case 0:
-		// Trace nothing
-	case 1:
-		if err != nil {
-			globals.logger.Printf("[WARN] %s.readFile(%#v) returning err: %v", backendCommon.dirName, readFileInput, err)
-		}
-	case 2:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.readFile(%#v) succeeded", backendCommon.dirName, readFileInput)
-		} else {
-			globals.logger.Printf("[WARN] %s.readFile(%#v) returning err: %v", backendCommon.dirName, readFileInput, err)
-		}
-	default:
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.readFile(%#v) returning err: %v", backendCommon.dirName, readFileInput, err)
+	} else if backendCommon.logEnabled(slog.LevelDebug) {
+		backendCommon.logf(slog.LevelDebug, "%s.readFile(%#v) returning readFileOutput: {\"eTag\":\"%s\",len(\"buf\":%v)}", backendCommon.dirName, readFileInput, readFileOutput.eTag, len(readFileOutput.buf))
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.readFile(%#v) succeeded", backendCommon.dirName, readFileInput)
+	}
+
+	return
+}
+
+// `restoreFileWrapper` is a wrapper function around the supplied backendContext's `restoreFile` function enabling centralized metrics and tracing capture.
+func restoreFileWrapper(ctx context.Context, backendContext backendContextIf, restoreFileInput *restoreFileInputStruct) (restoreFileOutput *restoreFileOutputStruct, err error) {
+	var (
+		backendCommon = backendContext.backendCommon()
+		cancel        context.CancelFunc
+		latency       float64
+		retryHistory  *retryHistoryStruct
+		span          trace.Span
+		startTime     time.Time
+	)
+
+	recordRequest(backendCommon, "restoreFile")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "restoreFile", attribute.String("msfs.path", restoreFileInput.filePath))
+
+	ctx, retryHistory = withRetryHistory(ctx)
+
+	startTime = time.Now()
+
+	if backendCommon.keySaltWidth != 0 {
+		restoreFileInputCopy := *restoreFileInput
+		restoreFileInputCopy.filePath = backendCommon.saltFilePath(restoreFileInput.filePath)
+		restoreFileInput = &restoreFileInputCopy
+	}
+
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.headTimeout)
+	defer cancel()
+
+	err = backendCommon.throttle(ctx, 0)
+	if err == nil {
+		err = backendCommon.acquireRequestSlot(ctx)
+	}
+	if err == nil {
+		restoreFileOutput, err = backendContext.restoreFile(ctx, restoreFileInput)
+		backendCommon.releaseRequestSlot()
+	}
+
+	latency = time.Since(startTime).Seconds()
+
+	err = checkBackendOpTimeout(ctx, backendCommon.headTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
+	go func(backend *backendStruct, latency float64, err error) {
+		globalsLock("backend.go:1471:3:funcLit@1470")
 		if err == nil {
-			globals.logger.Printf("[INFO] %s.readFile(%#v) returning readFileOutput: {\"eTag\":\"%s\",len(\"buf\":%v)}", backendCommon.dirName, readFileInput, readFileOutput.eTag, len(readFileOutput.buf))
+			globals.backendMetrics.RestoreFileSuccesses.Inc()
+			globals.backendMetrics.RestoreFileSuccessLatencies.Observe(latency)
+
+			backend.backendMetrics.RestoreFileSuccesses.Inc()
+			backend.backendMetrics.RestoreFileSuccessLatencies.Observe(latency)
 		} else {
-			globals.logger.Printf("[WARN] %s.readFile(%#v) returning err: %v", backendCommon.dirName, readFileInput, err)
+			globals.backendMetrics.RestoreFileFailures.Inc()
+			globals.backendMetrics.RestoreFileFailureLatencies.Observe(latency)
+
+			backend.backendMetrics.RestoreFileFailures.Inc()
+			backend.backendMetrics.RestoreFileFailureLatencies.Observe(latency)
 		}
+		retryHistory.observe(globals.backendMetrics, err)
+		retryHistory.observe(backend.backendMetrics, err)
+		globalsUnlock()
+	}(backendCommon, latency, err)
+
+	recordBackendMetrics(backendCommon, "restoreFile", startTime, err, 0)
+
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.restoreFile(%#v) returning err: %v", backendCommon.dirName, restoreFileInput, err)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.restoreFile(%#v) returning restoreFileOutput: %#v", backendCommon.dirName, restoreFileInput, restoreFileOutput)
 	}
 
 	return
 }
 
 // `statDirectoryWrapper` is a wrapper function around the supplied backendContext's `statDirectory` function enabling centralized metrics and tracing capture.
-func statDirectoryWrapper(backendContext backendContextIf, statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
+func statDirectoryWrapper(ctx context.Context, backendContext backendContextIf, statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
 	var (
 		backendCommon = backendContext.backendCommon()
+		cancel        context.CancelFunc
 		latency       float64
+		retryHistory  *retryHistoryStruct
+		span          trace.Span
 		startTime     time.Time
 	)
 
-	recordRequest(backendCommon.dirName, "statDirectory")
+	recordRequest(backendCommon, "statDirectory")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "statDirectory", attribute.String("msfs.path", statDirectoryInput.dirPath))
+
+	ctx, retryHistory = withRetryHistory(ctx)
 
 	startTime = time.Now()
 
-	statDirectoryOutput, err = backendContext.statDirectory(statDirectoryInput)
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.headTimeout)
+	defer cancel()
+
+	err = backendCommon.throttle(ctx, 0)
+	if err == nil {
+		err = backendCommon.acquireRequestSlot(ctx)
+	}
+	if err == nil {
+		statDirectoryOutput, err = backendContext.statDirectory(ctx, statDirectoryInput)
+		backendCommon.releaseRequestSlot()
+	}
 
 	latency = time.Since(startTime).Seconds()
 
+	err = checkBackendOpTimeout(ctx, backendCommon.headTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
 	go func(backend *backendStruct, latency float64, err error) {
-		globalsLock("backend.go:610:3:funcLit@609")
+		globalsLock("backend.go:1541:3:funcLit@1540")
 		if err == nil {
 			globals.backendMetrics.StatDirectorySuccesses.Inc()
 			globals.backendMetrics.StatDirectorySuccessLatencies.Observe(latency)
@@ -621,54 +1552,78 @@ func statDirectoryWrapper(backendContext backendContextIf, statDirectoryInput *s
 			backend.backendMetrics.StatDirectoryFailures.Inc()
 			backend.backendMetrics.StatDirectoryFailureLatencies.Observe(latency)
 		}
+		retryHistory.observe(globals.backendMetrics, err)
+		retryHistory.observe(backend.backendMetrics, err)
 		globalsUnlock()
 	}(backendCommon, latency, err)
 
-	recordBackendMetrics(backendCommon.dirName, "statDirectory", startTime, err, 0)
+	recordBackendMetrics(backendCommon, "statDirectory", startTime, err, 0)
 
-	switch backendCommon.traceLevel {
-	case 0:
-		// Trace nothing
-	case 1:
-		if err != nil {
-			globals.logger.Printf("[WARN] %s.statDirectory(%#v) returning err: %v", backendCommon.dirName, statDirectoryInput, err)
-		}
-	case 2:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.statDirectory(%#v) succeeded", backendCommon.dirName, statDirectoryInput)
-		} else {
-			globals.logger.Printf("[WARN] %s.statDirectory(%#v) returning err: %v", backendCommon.dirName, statDirectoryInput, err)
-		}
-	default:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.statDirectory(%#v) returning statDirectoryOutput: %#v", backendCommon.dirName, statDirectoryInput, statDirectoryOutput)
-		} else {
-			globals.logger.Printf("[WARN] %s.statDirectory(%#v) returning err: %v", backendCommon.dirName, statDirectoryInput, err)
-		}
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.statDirectory(%#v) returning err: %v", backendCommon.dirName, statDirectoryInput, err)
+	} else if backendCommon.logEnabled(slog.LevelDebug) {
+		backendCommon.logf(slog.LevelDebug, "%s.statDirectory(%#v) returning statDirectoryOutput: %#v", backendCommon.dirName, statDirectoryInput, statDirectoryOutput)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.statDirectory(%#v) succeeded", backendCommon.dirName, statDirectoryInput)
 	}
 
 	return
 }
 
 // `statFileWrapper` is a wrapper function around the supplied backendContext's `statFile` function enabling centralized metrics and tracing capture.
-func statFileWrapper(backendContext backendContextIf, statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
+func statFileWrapper(ctx context.Context, backendContext backendContextIf, statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
 	var (
-		backendCommon = backendContext.backendCommon()
-		bytesReported = int64(0)
-		latency       float64
-		startTime     time.Time
+		backendCommon         = backendContext.backendCommon()
+		cancel                context.CancelFunc
+		bytesReported         = int64(0)
+		latency               float64
+		retryHistory          *retryHistoryStruct
+		span                  trace.Span
+		startTime             time.Time
+		unsaltedStatFileInput *statFileInputStruct
 	)
 
-	recordRequest(backendCommon.dirName, "statFile")
+	recordRequest(backendCommon, "statFile")
+
+	ctx, span = startBackendSpan(ctx, backendCommon, "statFile", attribute.String("msfs.path", statFileInput.filePath))
+
+	ctx, retryHistory = withRetryHistory(ctx)
 
 	startTime = time.Now()
 
-	statFileOutput, err = backendContext.statFile(statFileInput)
+	if backendCommon.keySaltWidth != 0 {
+		unsaltedStatFileInput = statFileInput
+		statFileInputCopy := *statFileInput
+		statFileInputCopy.filePath = backendCommon.saltFilePath(statFileInput.filePath)
+		statFileInput = &statFileInputCopy
+	}
+
+	ctx, cancel = withBackendOpTimeout(ctx, backendCommon.headTimeout)
+	defer cancel()
+
+	err = backendCommon.throttle(ctx, 0)
+	if err == nil {
+		err = backendCommon.acquireRequestSlot(ctx)
+	}
+	if err == nil {
+		statFileOutput, err = backendContext.statFile(ctx, statFileInput)
+		if (unsaltedStatFileInput != nil) && errors.Is(err, errFileNotFound) && backendCommon.unsaltedSeen.Load() {
+			// The object may predate key_salt_width (see desaltBasename())
+			statFileOutput, err = backendContext.statFile(ctx, unsaltedStatFileInput)
+		}
+		backendCommon.releaseRequestSlot()
+	}
 
 	latency = time.Since(startTime).Seconds()
 
+	err = checkBackendOpTimeout(ctx, backendCommon.headTimeout, err)
+
+	err = annotateBackendError(retryHistory.annotate(err))
+
+	endSpan(span, err)
+
 	go func(backend *backendStruct, latency float64, err error) {
-		globalsLock("backend.go:671:3:funcLit@670")
+		globalsLock("backend.go:1626:3:funcLit@1625")
 		if err == nil {
 			globals.backendMetrics.StatFileSuccesses.Inc()
 			globals.backendMetrics.StatFileSuccessLatencies.Observe(latency)
@@ -682,6 +1637,8 @@ func statFileWrapper(backendContext backendContextIf, statFileInput *statFileInp
 			backend.backendMetrics.StatFileFailures.Inc()
 			backend.backendMetrics.StatFileFailureLatencies.Observe(latency)
 		}
+		retryHistory.observe(globals.backendMetrics, err)
+		retryHistory.observe(backend.backendMetrics, err)
 		globalsUnlock()
 	}(backendCommon, latency, err)
 
@@ -689,27 +1646,14 @@ func statFileWrapper(backendContext backendContextIf, statFileInput *statFileInp
 		bytesReported = int64(statFileOutput.size)
 	}
 
-	recordBackendMetrics(backendCommon.dirName, "statFile", startTime, err, bytesReported)
+	recordBackendMetrics(backendCommon, "statFile", startTime, err, bytesReported)
 
-	switch backendCommon.traceLevel {
-	case 0:
-		// Trace nothing
-	case 1:
-		if err != nil {
-			globals.logger.Printf("[WARN] %s.statFile(%#v) returning err: %v", backendCommon.dirName, statFileInput, err)
-		}
-	case 2:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.statFile(%#v) succeeded", backendCommon.dirName, statFileInput)
-		} else {
-			globals.logger.Printf("[WARN] %s.statFile(%#v) returning err: %v", backendCommon.dirName, statFileInput, err)
-		}
-	default:
-		if err == nil {
-			globals.logger.Printf("[INFO] %s.statFile(%#v) returning statFileOutput: %#v", backendCommon.dirName, statFileInput, statFileOutput)
-		} else {
-			globals.logger.Printf("[WARN] %s.statFile(%#v) returning err: %v", backendCommon.dirName, statFileInput, err)
-		}
+	if err != nil {
+		backendCommon.logf(slog.LevelWarn, "%s.statFile(%#v) returning err: %v", backendCommon.dirName, statFileInput, err)
+	} else if backendCommon.logEnabled(slog.LevelDebug) {
+		backendCommon.logf(slog.LevelDebug, "%s.statFile(%#v) returning statFileOutput: %#v", backendCommon.dirName, statFileInput, statFileOutput)
+	} else {
+		backendCommon.logf(slog.LevelInfo, "%s.statFile(%#v) succeeded", backendCommon.dirName, statFileInput)
 	}
 
 	return
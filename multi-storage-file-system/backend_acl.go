@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"syscall"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+// `backendACLStruct` describes which requesting UIDs/GIDs may access a backend. A request
+// matching either deny list is refused. Otherwise, if either allow list is non-empty, the
+// request must match one of them. The superuser (UID 0) is never refused. Note that FUSE only
+// supplies the requester's primary GID, so supplementary groups are not considered.
+type backendACLStruct struct {
+	allowUIDs []uint32 // JSON/YAML "allow_uids" default:[] (any UID)
+	allowGIDs []uint32 // JSON/YAML "allow_gids" default:[] (any GID)
+	denyUIDs  []uint32 // JSON/YAML "deny_uids"  default:[]
+	denyGIDs  []uint32 // JSON/YAML "deny_gids"  default:[]
+}
+
+// `parseACL` parses a backend's optional "allow_uids", "allow_gids", "deny_uids", and
+// "deny_gids" lists. If none are present, acl is returned as nil (i.e. unrestricted).
+func parseACL(backendAsMap map[string]interface{}) (acl *backendACLStruct, err error) {
+	acl = &backendACLStruct{}
+
+	acl.allowUIDs, err = parseIDList(backendAsMap, "allow_uids")
+	if err != nil {
+		acl = nil
+		return
+	}
+
+	acl.allowGIDs, err = parseIDList(backendAsMap, "allow_gids")
+	if err != nil {
+		acl = nil
+		return
+	}
+
+	acl.denyUIDs, err = parseIDList(backendAsMap, "deny_uids")
+	if err != nil {
+		acl = nil
+		return
+	}
+
+	acl.denyGIDs, err = parseIDList(backendAsMap, "deny_gids")
+	if err != nil {
+		acl = nil
+		return
+	}
+
+	if (acl.allowUIDs == nil) && (acl.allowGIDs == nil) && (acl.denyUIDs == nil) && (acl.denyGIDs == nil) {
+		acl = nil
+	}
+
+	return
+}
+
+// `parseIDList` parses the optional list of UIDs or GIDs at key. If not present, ids is returned as nil.
+func parseIDList(m map[string]interface{}, key string) (ids []uint32, err error) {
+	var (
+		id              uint64
+		idAsInterface   interface{}
+		idsAsInterface  interface{}
+		idsAsInterfaces []interface{}
+		ok              bool
+	)
+
+	idsAsInterface, ok = m[key]
+	if !ok {
+		return
+	}
+
+	idsAsInterfaces, ok = idsAsInterface.([]interface{})
+	if !ok {
+		err = fmt.Errorf("%s must be a list", key)
+		return
+	}
+
+	ids = make([]uint32, 0, len(idsAsInterfaces))
+
+	for _, idAsInterface = range idsAsInterfaces {
+		id, ok = parseUint64(map[string]interface{}{key: idAsInterface}, key, nil)
+		if !ok || (id > uint64(^uint32(0))) {
+			err = fmt.Errorf("bad %s element (%v)", key, idAsInterface)
+			ids = nil
+			return
+		}
+		ids = append(ids, uint32(id))
+	}
+
+	return
+}
+
+// `equal` reports whether acl and other impose the same restrictions.
+func (acl *backendACLStruct) equal(other *backendACLStruct) bool {
+	if (acl == nil) || (other == nil) {
+		return (acl == nil) && (other == nil)
+	}
+
+	return slices.Equal(acl.allowUIDs, other.allowUIDs) &&
+		slices.Equal(acl.allowGIDs, other.allowGIDs) &&
+		slices.Equal(acl.denyUIDs, other.denyUIDs) &&
+		slices.Equal(acl.denyGIDs, other.denyGIDs)
+}
+
+// `permits` reports whether a request from uid/gid may access the backend governed by acl.
+// A nil acl permits every request.
+func (acl *backendACLStruct) permits(uid, gid uint32) bool {
+	if (acl == nil) || (uid == 0) {
+		return true
+	}
+
+	if slices.Contains(acl.denyUIDs, uid) || slices.Contains(acl.denyGIDs, gid) {
+		return false
+	}
+
+	if (len(acl.allowUIDs) == 0) && (len(acl.allowGIDs) == 0) {
+		return true
+	}
+
+	return slices.Contains(acl.allowUIDs, uid) || slices.Contains(acl.allowGIDs, gid)
+}
+
+// `updateBackendACLsInUse` recomputes globals.backendACLsInUse following any change to the set
+// of mounted backends or their ACLs. It must be called while holding globals.Lock().
+func updateBackendACLsInUse() {
+	var (
+		backend *backendStruct
+	)
+
+	for _, backend = range globals.backendMap {
+		if backend.acl != nil {
+			globals.backendACLsInUse.Store(true)
+			return
+		}
+	}
+
+	globals.backendACLsInUse.Store(false)
+}
+
+// `backendACLErrno` returns EACCES if the requester described by inHeader is not permitted by
+// the ACL of the backend containing inHeader.NodeID. As the kernel caches dentries and inodes
+// without regard to the requester, this is checked by each FUSE callback referencing an inode
+// rather than just at lookup time. Unknown inodes are left to the caller to reject. As this
+// only reads globals, it holds globals only shared (see globalsRLock()).
+func backendACLErrno(inHeader *fission.InHeader) (errno syscall.Errno) {
+	var (
+		backend *backendStruct
+		inode   *inodeStruct
+		ok      bool
+	)
+
+	if !globals.backendACLsInUse.Load() {
+		return
+	}
+
+	globalsRLock()
+
+	inode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if ok && (inode.backendNonce != 0) {
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if ok && !backend.acl.permits(inHeader.UID, inHeader.GID) {
+			errno = syscall.EACCES
+		}
+	}
+
+	globalsRUnlock()
+
+	return
+}
+
+// `hiddenFrom` reports whether childInode, an entry of the FUSE root directory, should be
+// omitted from the listing returned to the requester described by inHeader. This is only
+// the case when hide_inaccessible_backends is set and childInode is the BackendRootDir of a
+// backend whose ACL does not permit the requester. It must be called while holding globals.Lock().
+func (childInode *inodeStruct) hiddenFrom(inHeader *fission.InHeader) bool {
+	var (
+		backend *backendStruct
+		ok      bool
+	)
+
+	if !globals.config.hideInaccessibleBackends || (childInode.inodeType != BackendRootDir) {
+		return false
+	}
+
+	backend, ok = globals.backendMap[childInode.backendNonce]
+	if !ok {
+		return false
+	}
+
+	return !backend.acl.permits(inHeader.UID, inHeader.GID)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"slices"
+	"syscall"
+	"testing"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+func TestACLParse(t *testing.T) {
+	var (
+		acl *backendACLStruct
+		err error
+	)
+
+	acl, err = parseACL(map[string]interface{}{})
+	if (err != nil) || (acl != nil) {
+		t.Fatalf("parseACL() of a backend without any lists should have returned nil, nil")
+	}
+
+	_, err = parseACL(map[string]interface{}{"allow_uids": float64(1000)})
+	if err == nil {
+		t.Fatalf("parseACL() should have rejected a non-list allow_uids")
+	}
+
+	_, err = parseACL(map[string]interface{}{"deny_gids": []interface{}{float64(-1)}})
+	if err == nil {
+		t.Fatalf("parseACL() should have rejected a negative deny_gids element")
+	}
+
+	acl, err = parseACL(map[string]interface{}{
+		"allow_uids": []interface{}{float64(1000), 1001},
+		"allow_gids": []interface{}{float64(2000)},
+		"deny_uids":  []interface{}{float64(1001)},
+	})
+	if err != nil {
+		t.Fatalf("parseACL() failed: %v", err)
+	}
+
+	for _, testCase := range []struct {
+		uid      uint32
+		gid      uint32
+		expected bool
+	}{
+		{0, 0, true},
+		{1000, 0, true},
+		{1001, 2000, false},
+		{1002, 2000, true},
+		{1002, 2001, false},
+	} {
+		if acl.permits(testCase.uid, testCase.gid) != testCase.expected {
+			t.Errorf("permits(%v, %v) should have returned %v", testCase.uid, testCase.gid, testCase.expected)
+		}
+	}
+
+	if !acl.equal(acl) || acl.equal(nil) || !(*backendACLStruct)(nil).permits(1000, 1000) {
+		t.Fatalf("equal()/permits() returned unexpected results")
+	}
+}
+
+func TestFissionBackendACL(t *testing.T) {
+	var (
+		errno     syscall.Errno
+		lookupOut *fission.LookupOut
+		ramDirIno uint64
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.LookupIn{Name: []byte("ram")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") failed (errno: %v)", errno)
+	}
+	ramDirIno = lookupOut.EntryOut.NodeID
+
+	globalsLock("backend_acl_test.go:78:2:TestFissionBackendACL")
+	globals.config.backends["ram"].acl = &backendACLStruct{allowUIDs: []uint32{1000}}
+	updateBackendACLsInUse()
+	globalsUnlock()
+
+	_, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber, UID: 1001, GID: 1001}, &fission.LookupIn{Name: []byte("ram")})
+	if errno != syscall.EACCES {
+		t.Errorf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") by UID 1001 returned errno: %v (expected: EACCES)", errno)
+	}
+	_, errno = globals.DoGetAttr(&fission.InHeader{NodeID: ramDirIno, UID: 1001, GID: 1001}, &fission.GetAttrIn{})
+	if errno != syscall.EACCES {
+		t.Errorf("DoGetAttr(ramDir) by UID 1001 returned errno: %v (expected: EACCES)", errno)
+	}
+	_, errno = globals.DoOpenDir(&fission.InHeader{NodeID: ramDirIno, UID: 1001, GID: 1001}, &fission.OpenDirIn{})
+	if errno != syscall.EACCES {
+		t.Errorf("DoOpenDir(ramDir) by UID 1001 returned errno: %v (expected: EACCES)", errno)
+	}
+
+	_, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber, UID: 1000, GID: 1000}, &fission.LookupIn{Name: []byte("ram")})
+	if errno != 0 {
+		t.Errorf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") by UID 1000 failed (errno: %v)", errno)
+	}
+	_, errno = globals.DoGetAttr(&fission.InHeader{NodeID: ramDirIno, UID: 1000, GID: 1000}, &fission.GetAttrIn{})
+	if errno != 0 {
+		t.Errorf("DoGetAttr(ramDir) by UID 1000 failed (errno: %v)", errno)
+	}
+
+	// Other backends remain accessible
+
+	_, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber, UID: 1001, GID: 1001}, &fission.LookupIn{Name: []byte("pseudo")})
+	if errno != 0 {
+		t.Errorf("DoLookup(FUSERootDirInodeNumber,Name:\"pseudo\") by UID 1001 failed (errno: %v)", errno)
+	}
+
+	globalsLock("backend_acl_test.go:112:2:TestFissionBackendACL")
+	globals.config.backends["ram"].acl = nil
+	updateBackendACLsInUse()
+	globalsUnlock()
+
+	if globals.backendACLsInUse.Load() {
+		t.Fatalf("backendACLsInUse should be false once no backend has an acl")
+	}
+}
+
+func TestFissionHideInaccessibleBackends(t *testing.T) {
+	var (
+		errno      syscall.Errno
+		inHeader   *fission.InHeader
+		names      []string
+		openDirOut *fission.OpenDirOut
+		readDirOut *fission.ReadDirOut
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	globalsLock("backend_acl_test.go:134:2:TestFissionHideInaccessibleBackends")
+	globals.config.backends["ram"].acl = &backendACLStruct{denyUIDs: []uint32{1001}}
+	updateBackendACLsInUse()
+	globalsUnlock()
+
+	listRootDir := func(uid uint32) (names []string) {
+		inHeader = &fission.InHeader{NodeID: FUSERootDirInodeNumber, UID: uid, GID: uid}
+
+		openDirOut, errno = globals.DoOpenDir(inHeader, &fission.OpenDirIn{})
+		if errno != 0 {
+			t.Fatalf("DoOpenDir(FUSERootDirInodeNumber) failed (errno: %v)", errno)
+		}
+
+		readDirOut, errno = globals.DoReadDir(inHeader, &fission.ReadDirIn{FH: openDirOut.FH, Offset: 0, Size: testFissionReadDirBufSize})
+		if errno != 0 {
+			t.Fatalf("DoReadDir(rootDirFH) failed (errno: %v)", errno)
+		}
+
+		for _, dirEnt := range readDirOut.DirEnt {
+			names = append(names, string(dirEnt.Name))
+		}
+
+		errno = globals.DoReleaseDir(inHeader, &fission.ReleaseDirIn{FH: openDirOut.FH})
+		if errno != 0 {
+			t.Fatalf("DoReleaseDir(rootDirFH) failed (errno: %v)", errno)
+		}
+
+		return
+	}
+
+	// Without hide_inaccessible_backends, every backend is listed
+
+	names = listRootDir(1001)
+	if len(names) != 4 {
+		t.Fatalf("DoReadDir(rootDirFH) by UID 1001 returned %v (expected: 4 entries)", names)
+	}
+
+	globalsLock("backend_acl_test.go:171:2:TestFissionHideInaccessibleBackends")
+	globals.config.hideInaccessibleBackends = true
+	globalsUnlock()
+
+	names = listRootDir(1001)
+	if (len(names) != 3) || slices.Contains(names, "ram") {
+		t.Fatalf("DoReadDir(rootDirFH) by UID 1001 returned %v (expected: \"ram\" to be hidden)", names)
+	}
+
+	names = listRootDir(1000)
+	if (len(names) != 4) || !slices.Contains(names, "ram") {
+		t.Fatalf("DoReadDir(rootDirFH) by UID 1000 returned %v (expected: \"ram\" to be listed)", names)
+	}
+
+	globalsLock("backend_acl_test.go:185:2:TestFissionHideInaccessibleBackends")
+	globals.config.hideInaccessibleBackends = false
+	globals.config.backends["ram"].acl = nil
+	updateBackendACLsInUse()
+	globalsUnlock()
+}
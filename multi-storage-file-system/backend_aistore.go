@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -48,6 +49,13 @@ type aistoreContextStruct struct {
 	tokenMTime     time.Time
 }
 
+// `abortMultipartUploads` is called to abort abandoned multipart uploads. As AIStore backends
+// never initiate multipart uploads, errAbortMultipartUploadsNotSupported is returned.
+func (aisContext *aistoreContextStruct) abortMultipartUploads(ctx context.Context, abortMultipartUploadsInput *abortMultipartUploadsInputStruct) (abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct, err error) {
+	err = errAbortMultipartUploadsNotSupported
+	return
+}
+
 // `backendCommon` is called to return a pointer to the context's common `backendStruct`.
 func (backend *aistoreContextStruct) backendCommon() (backendCommon *backendStruct) {
 	backendCommon = backend.backend
@@ -80,6 +88,8 @@ func (backend *backendStruct) setupAIStoreContext() (err error) {
 		transport.TLSClientConfig.MinVersion = tls.VersionTLS12 // Match S3 backend: allow TLS 1.2+
 	}
 
+	transport.Proxy = newBackendProxyFunc(backend.proxyURL, backend.noProxy)
+
 	// Fetch  AuthN Token from either backendAIStore.authnToken or backendAIStore.authnTokenFile
 	if backendAIStore.authnToken == "" {
 		if backendAIStore.authnTokenFile == "" {
@@ -166,11 +176,27 @@ func (backend *backendStruct) setupAIStoreContext() (err error) {
 // and the parameters are returned unchanged. The stat performed here is on the
 // AIStore request path, which is only reached on a cache miss, so the overhead is
 // negligible relative to the network round-trip it precedes.
-func (aisContext *aistoreContextStruct) currentBaseParams() api.BaseParams {
+//
+// As the AIStore SDK accepts no context.Context, the returned parameters' Client is
+// (if ctx may be canceled) a copy whose requests are issued under ctx such that the
+// backend's timeouts (and FUSE interrupts) abort them.
+func (aisContext *aistoreContextStruct) currentBaseParams(ctx context.Context) (baseParams api.BaseParams) {
 	if aisContext.authnTokenFile == "" {
-		return aisContext.baseParams
+		baseParams = aisContext.baseParams
+	} else {
+		baseParams = aisContext.currentBaseParamsWithFreshToken()
 	}
 
+	if ctx.Done() != nil {
+		baseParams.Client = withRequestContext(ctx, baseParams.Client)
+	}
+
+	return
+}
+
+// `currentBaseParamsWithFreshToken` returns a copy of the AIStore connection parameters
+// after re-reading authnTokenFile if its mtime has changed (see currentBaseParams()).
+func (aisContext *aistoreContextStruct) currentBaseParamsWithFreshToken() api.BaseParams {
 	aisContext.tokenMu.Lock()
 	defer aisContext.tokenMu.Unlock()
 
@@ -188,6 +214,44 @@ func (aisContext *aistoreContextStruct) currentBaseParams() api.BaseParams {
 	return aisContext.baseParams
 }
 
+// `requestContextTransportStruct` is an http.RoundTripper issuing each request under ctx
+// (rather than whatever context, typically context.Background(), it was built with).
+type requestContextTransportStruct struct {
+	ctx       context.Context
+	transport http.RoundTripper
+}
+
+// `RoundTrip` implements http.RoundTripper.
+func (requestContextTransport *requestContextTransportStruct) RoundTrip(request *http.Request) (*http.Response, error) {
+	return requestContextTransport.transport.RoundTrip(request.WithContext(requestContextTransport.ctx))
+}
+
+// `withRequestContext` returns a copy of client whose requests are issued under ctx.
+func withRequestContext(ctx context.Context, client *http.Client) (clientCopy *http.Client) {
+	var (
+		transport http.RoundTripper
+	)
+
+	if client == nil {
+		clientCopy = &http.Client{}
+	} else {
+		clientCopy = new(http.Client)
+		*clientCopy = *client
+	}
+
+	transport = clientCopy.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	clientCopy.Transport = &requestContextTransportStruct{
+		ctx:       ctx,
+		transport: transport,
+	}
+
+	return
+}
+
 // Note on Retry Logic:
 // Unlike S3 backend which implements aws.Retryer interface (IsErrorRetryable, MaxAttempts,
 // RetryDelay, GetRetryToken, GetInitialToken, GetAttemptToken), AIStore backend does NOT
@@ -198,9 +262,18 @@ func (aisContext *aistoreContextStruct) currentBaseParams() api.BaseParams {
 // See: https://github.com/NVIDIA/aistore/tree/main/aistore/cmn/retry.go and
 // https://github.com/NVIDIA/aistore/tree/main/aistore/api/client.go:215-222
 
+// `copyFile` is called to perform a server-side copy of the "file" at the specified source path
+// to the specified destination path.
+//
+// Note: The AIStore SDK offers no single-object server-side copy, so errCopyFileNotSupported is returned.
+func (aisContext *aistoreContextStruct) copyFile(ctx context.Context, copyFileInput *copyFileInputStruct) (copyFileOutput *copyFileOutputStruct, err error) {
+	err = errCopyFileNotSupported
+	return
+}
+
 // `deleteFile` is called to remove a "file" at the specified path.
 // If a `subdirectory` or nothing is found at that path, an error will be returned.
-func (aisContext *aistoreContextStruct) deleteFile(deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
+func (aisContext *aistoreContextStruct) deleteFile(ctx context.Context, deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
 	var (
 		backend      = aisContext.backend
 		fullFilePath = backend.prefix + deleteFileInput.filePath
@@ -212,7 +285,7 @@ func (aisContext *aistoreContextStruct) deleteFile(deleteFileInput *deleteFileIn
 	//       the AIStore SDK does not expose DeleteArgs where in the If-Match Header could be inserted.
 	if deleteFileInput.ifMatch != "" {
 		var props *cmn.ObjectProps
-		props, err = api.HeadObject(aisContext.currentBaseParams(), aisContext.bck, fullFilePath, api.HeadArgs{
+		props, err = api.HeadObject(aisContext.currentBaseParams(ctx), aisContext.bck, fullFilePath, api.HeadArgs{
 			Silent: true,
 		})
 		if err != nil {
@@ -225,7 +298,10 @@ func (aisContext *aistoreContextStruct) deleteFile(deleteFileInput *deleteFileIn
 	}
 
 	// Delete the object
-	err = api.DeleteObject(aisContext.currentBaseParams(), aisContext.bck, fullFilePath)
+	err = api.DeleteObject(aisContext.currentBaseParams(ctx), aisContext.bck, fullFilePath)
+	if (err != nil) && cmn.IsStatusNotFound(err) {
+		err = fmt.Errorf("%w: %w", errFileNotFound, err)
+	}
 
 	return
 }
@@ -234,10 +310,10 @@ func (aisContext *aistoreContextStruct) deleteFile(deleteFileInput *deleteFileIn
 // An empty continuationToken or empty list of directory elements (`subdirectories` and `files`)
 // indicates the `directory` has been completely enumerated. The `isTruncated` field will also
 // align with this convention.
-func (aisContext *aistoreContextStruct) listDirectory(listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+func (aisContext *aistoreContextStruct) listDirectory(ctx context.Context, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
 	// Delegate listing to the manifest_gen_backend (e.g. direct S3) when configured.
 	if aisContext.listBackend != nil {
-		return aisContext.listBackend.context.listDirectory(listDirectoryInput)
+		return aisContext.listBackend.context.listDirectory(ctx, listDirectoryInput)
 	}
 
 	var (
@@ -251,9 +327,11 @@ func (aisContext *aistoreContextStruct) listDirectory(listDirectoryInput *listDi
 		timeNow = time.Now()
 	)
 
-	// Set continuation token if provided
+	// Set continuation token if provided (otherwise, start after if provided)
 	if listDirectoryInput.continuationToken != "" {
 		lsmsg.ContinuationToken = listDirectoryInput.continuationToken
+	} else if listDirectoryInput.startAfter != "" {
+		lsmsg.StartAfter = backend.prefix + listDirectoryInput.startAfter
 	}
 
 	// Set page size if specified
@@ -262,8 +340,8 @@ func (aisContext *aistoreContextStruct) listDirectory(listDirectoryInput *listDi
 	}
 
 	// List objects (one page)
-	var lsoResult *cmn.LsoRes                                                                                   // List Objects Result
-	lsoResult, err = api.ListObjectsPage(aisContext.currentBaseParams(), aisContext.bck, lsmsg, api.ListArgs{}) // List Objects Page
+	var lsoResult *cmn.LsoRes                                                                                      // List Objects Result
+	lsoResult, err = api.ListObjectsPage(aisContext.currentBaseParams(ctx), aisContext.bck, lsmsg, api.ListArgs{}) // List Objects Page
 	if err != nil {
 		err = fmt.Errorf("[AIStore] listDirectory failed: %v", err)
 		return
@@ -304,10 +382,10 @@ func (aisContext *aistoreContextStruct) listDirectory(listDirectoryInput *listDi
 // `listObjects` is called to fetch a `page` of the objects. An empty continuationToken or
 // empty list of elements (`objects`) indicates the list of `objects` has been completely
 // enumerated. The `isTruncated` field will also align with this convention.
-func (aisContext *aistoreContextStruct) listObjects(listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+func (aisContext *aistoreContextStruct) listObjects(ctx context.Context, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
 	// Delegate listing to the manifest_gen_backend (e.g. direct S3) when configured.
 	if aisContext.listBackend != nil {
-		return aisContext.listBackend.context.listObjects(listObjectsInput)
+		return aisContext.listBackend.context.listObjects(ctx, listObjectsInput)
 	}
 
 	var (
@@ -327,7 +405,7 @@ func (aisContext *aistoreContextStruct) listObjects(listObjectsInput *listObject
 
 	// Set start after if provided
 	if listObjectsInput.startAfter != "" {
-		lsmsg.StartAfter = listObjectsInput.startAfter
+		lsmsg.StartAfter = backend.prefix + listObjectsInput.startAfter
 	}
 
 	// Set continuation token if provided
@@ -341,8 +419,8 @@ func (aisContext *aistoreContextStruct) listObjects(listObjectsInput *listObject
 	}
 
 	// List objects (one page)
-	var lsoResult *cmn.LsoRes                                                                                   // List Objects Result
-	lsoResult, err = api.ListObjectsPage(aisContext.currentBaseParams(), aisContext.bck, lsmsg, api.ListArgs{}) // List Objects Page
+	var lsoResult *cmn.LsoRes                                                                                      // List Objects Result
+	lsoResult, err = api.ListObjectsPage(aisContext.currentBaseParams(ctx), aisContext.bck, lsmsg, api.ListArgs{}) // List Objects Page
 	if err != nil {
 		err = fmt.Errorf("[AIStore] ListObjectsPage failed: %v", err)
 		return
@@ -373,7 +451,48 @@ func (aisContext *aistoreContextStruct) listObjects(listObjectsInput *listObject
 	return
 }
 
-// `readFile` is called to read a range of a `file` at the specified path.
+// `presignFile` is called to produce a pre-signed GET URL for a `file`. As AIStore backends
+// have no such URLs, errPresignFileNotSupported is returned.
+func (aisContext *aistoreContextStruct) presignFile(ctx context.Context, presignFileInput *presignFileInputStruct) (presignFileOutput *presignFileOutputStruct, err error) {
+	err = errPresignFileNotSupported
+	return
+}
+
+// `putFile` is called to create (or replace) a "file" at the specified path with the supplied content.
+func (aisContext *aistoreContextStruct) putFile(ctx context.Context, putFileInput *putFileInputStruct) (putFileOutput *putFileOutputStruct, err error) {
+	var (
+		backend        = aisContext.backend
+		fullFilePath   = backend.prefix + putFileInput.filePath
+		statFileOutput *statFileOutputStruct
+	)
+
+	_, err = api.PutObject(&api.PutArgs{
+		BaseParams: aisContext.currentBaseParams(ctx),
+		Bck:        aisContext.bck,
+		ObjName:    fullFilePath,
+		Reader:     cos.NewByteHandle(putFileInput.buf),
+		Size:       uint64(len(putFileInput.buf)),
+	})
+	if err != nil {
+		return
+	}
+
+	// The eTag reported by statFile() is the object's checksum, so fetch it the same way
+
+	statFileOutput, err = aisContext.statFile(ctx, &statFileInputStruct{
+		filePath: putFileInput.filePath,
+	})
+	if err != nil {
+		return
+	}
+
+	putFileOutput = &putFileOutputStruct{
+		eTag: statFileOutput.eTag,
+	}
+
+	return
+}
+
 // `redactSecrets` redacts this AIStore backend's configured authentication token from s.
 func (aisContext *aistoreContextStruct) redactSecrets(s string) string {
 	if cfg, ok := aisContext.backend.backendTypeSpecifics.(*backendConfigAIStoreStruct); ok && cfg != nil {
@@ -382,15 +501,21 @@ func (aisContext *aistoreContextStruct) redactSecrets(s string) string {
 	return s
 }
 
+// `readFile` is called to read a range of a `file` at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (aisContext *aistoreContextStruct) readFile(readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
+func (aisContext *aistoreContextStruct) readFile(ctx context.Context, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
 	var (
 		backend      = aisContext.backend
 		fullFilePath = backend.prefix + readFileInput.filePath
-		rangeBegin   = readFileInput.offsetCacheLine * globals.config.cacheLineSize
-		rangeEnd     = rangeBegin + globals.config.cacheLineSize - 1
+		rangeBegin   uint64
+		rangeEnd     uint64
+		rangeLength  uint64
 	)
 
+	rangeBegin = readFileInput.offset
+	rangeLength = readFileInput.length
+	rangeEnd = rangeBegin + rangeLength - 1
+
 	// Create buffer and GetArgs
 	buf := &bytes.Buffer{}
 	getArgs := &api.GetArgs{
@@ -403,13 +528,35 @@ func (aisContext *aistoreContextStruct) readFile(readFileInput *readFileInputStr
 		getArgs.Header.Set("If-Match", fmt.Sprintf("\"%s\"", readFileInput.ifMatch))
 	}
 
+	// Verify ETag has changed if specified
+	// Note: This .ifNoneMatch is a non-atomic implementation via a preceding HeadObject()
+	if readFileInput.ifNoneMatch != "" {
+		var props *cmn.ObjectProps
+		props, err = api.HeadObject(aisContext.currentBaseParams(ctx), aisContext.bck, fullFilePath, api.HeadArgs{
+			Silent: true,
+		})
+		if err != nil {
+			return
+		}
+		if props.Cksum != nil && props.Cksum.Value() == readFileInput.ifNoneMatch {
+			readFileOutput = &readFileOutputStruct{
+				eTag:        readFileInput.ifNoneMatch,
+				notModified: true,
+			}
+			return
+		}
+	}
+
 	// Set range header
 	getArgs.Header.Set(cos.HdrRange, fmt.Sprintf("bytes=%d-%d", rangeBegin, rangeEnd))
 
 	// Get the object
 	var oah api.ObjAttrs
-	oah, err = api.GetObject(aisContext.currentBaseParams(), aisContext.bck, fullFilePath, getArgs)
+	oah, err = api.GetObject(aisContext.currentBaseParams(ctx), aisContext.bck, fullFilePath, getArgs)
 	if err != nil {
+		if cmn.IsStatusNotFound(err) {
+			err = fmt.Errorf("%w: %w", errFileNotFound, err)
+		}
 		return
 	}
 
@@ -422,12 +569,19 @@ func (aisContext *aistoreContextStruct) readFile(readFileInput *readFileInputStr
 	return
 }
 
+// `restoreFile` is called to request that the archived content of the `file` at the specified
+// path be restored. As AIStore backends do not archive content, errRestoreFileNotSupported is returned.
+func (aisContext *aistoreContextStruct) restoreFile(ctx context.Context, restoreFileInput *restoreFileInputStruct) (restoreFileOutput *restoreFileOutputStruct, err error) {
+	err = errRestoreFileNotSupported
+	return
+}
+
 // `statDirectory` is called to verify that the specified path refers to a `directory`.
 // An error is returned if either the specified path is not a `directory` or non-existent.
-func (aisContext *aistoreContextStruct) statDirectory(statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
+func (aisContext *aistoreContextStruct) statDirectory(ctx context.Context, statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
 	// Delegate directory stat to the manifest_gen_backend (e.g. direct S3) when configured.
 	if aisContext.listBackend != nil {
-		return aisContext.listBackend.context.statDirectory(statDirectoryInput)
+		return aisContext.listBackend.context.statDirectory(ctx, statDirectoryInput)
 	}
 
 	var (
@@ -444,7 +598,7 @@ func (aisContext *aistoreContextStruct) statDirectory(statDirectoryInput *statDi
 	// List with limit of 1 to check if directory is accessible
 	// Note: In object storage, directories are just prefixes and can be empty.
 	// We rely on the API error to determine if the bucket/prefix is inaccessible.
-	lsoResult, err = api.ListObjectsPage(aisContext.currentBaseParams(), aisContext.bck, lsmsg, api.ListArgs{})
+	lsoResult, err = api.ListObjectsPage(aisContext.currentBaseParams(ctx), aisContext.bck, lsmsg, api.ListArgs{})
 	if err == nil {
 		if (lsoResult == nil) || (lsoResult.Entries == nil) || (len(lsoResult.Entries) == 0) {
 			err = errors.New("missing directory")
@@ -459,7 +613,7 @@ func (aisContext *aistoreContextStruct) statDirectory(statDirectoryInput *statDi
 
 // `statFile` is called to fetch the `file` metadata at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (aisContext *aistoreContextStruct) statFile(statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
+func (aisContext *aistoreContextStruct) statFile(ctx context.Context, statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
 	var (
 		backend      = aisContext.backend
 		fullFilePath = backend.prefix + statFileInput.filePath
@@ -467,10 +621,13 @@ func (aisContext *aistoreContextStruct) statFile(statFileInput *statFileInputStr
 
 	// Head the object
 	var props *cmn.ObjectProps
-	props, err = api.HeadObject(aisContext.currentBaseParams(), aisContext.bck, fullFilePath, api.HeadArgs{
+	props, err = api.HeadObject(aisContext.currentBaseParams(ctx), aisContext.bck, fullFilePath, api.HeadArgs{
 		Silent: true,
 	})
 	if err != nil {
+		if cmn.IsStatusNotFound(err) {
+			err = fmt.Errorf("%w: %w", errFileNotFound, err)
+		}
 		return
 	}
 
@@ -482,10 +639,22 @@ func (aisContext *aistoreContextStruct) statFile(statFileInput *statFileInputStr
 		}
 	}
 
+	// Report not modified if specified ETag still matches
+	if statFileInput.ifNoneMatch != "" {
+		if props.Cksum != nil && props.Cksum.Value() == statFileInput.ifNoneMatch {
+			statFileOutput = &statFileOutputStruct{
+				eTag:        statFileInput.ifNoneMatch,
+				notModified: true,
+			}
+			return
+		}
+	}
+
 	statFileOutput = &statFileOutputStruct{
-		eTag:  props.Cksum.Value(),
-		mTime: time.UnixMicro(props.Atime),
-		size:  uint64(props.Size),
+		eTag:     props.Cksum.Value(),
+		mTime:    time.UnixMicro(props.Atime),
+		size:     uint64(props.Size),
+		metadata: props.CustomMD,
 	}
 
 	return
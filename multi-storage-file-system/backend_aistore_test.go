@@ -1,6 +1,10 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -26,7 +30,7 @@ func TestCurrentBaseParams_NoTokenFileReturnsUnchanged(t *testing.T) {
 	aisContext := &aistoreContextStruct{
 		baseParams: api.BaseParams{Token: "inline-or-anonymous"}, //nolint:gosec // G101: fake test value
 	}
-	if got := aisContext.currentBaseParams().Token; got != "inline-or-anonymous" {
+	if got := aisContext.currentBaseParams(context.Background()).Token; got != "inline-or-anonymous" {
 		t.Fatalf("token = %q, want the fixed setup token (no file reload)", got)
 	}
 }
@@ -50,7 +54,7 @@ func TestCurrentBaseParams_ReloadsOnTokenFileRotation(t *testing.T) {
 	}
 
 	// Unchanged file -> cached token, no reload.
-	if got := aisContext.currentBaseParams().Token; got != "token-1" {
+	if got := aisContext.currentBaseParams(context.Background()).Token; got != "token-1" {
 		t.Fatalf("unchanged file: token = %q, want token-1", got)
 	}
 
@@ -62,12 +66,49 @@ func TestCurrentBaseParams_ReloadsOnTokenFileRotation(t *testing.T) {
 		t.Fatalf("chtimes: %v", err)
 	}
 
-	if got := aisContext.currentBaseParams().Token; got != "token-2" {
+	if got := aisContext.currentBaseParams(context.Background()).Token; got != "token-2" {
 		t.Fatalf("after rotation: token = %q, want token-2 (reloaded)", got)
 	}
 
 	// With no further change the refreshed token is retained.
-	if got := aisContext.currentBaseParams().Token; got != "token-2" {
+	if got := aisContext.currentBaseParams(context.Background()).Token; got != "token-2" {
 		t.Fatalf("steady state: token = %q, want token-2", got)
 	}
 }
+
+// A cancelable ctx must govern the requests issued via currentBaseParams (as the AIStore SDK
+// accepts no context.Context of its own) such that backend timeouts abort them.
+func TestCurrentBaseParams_RequestsHonorContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	aisContext := &aistoreContextStruct{
+		baseParams: api.BaseParams{Client: server.Client(), URL: server.URL},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	baseParams := aisContext.currentBaseParams(ctx)
+
+	request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() failed: %v", err)
+	}
+
+	_, err = baseParams.Client.Do(request)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("request should have been aborted by ctx's deadline, got err: %v", err)
+	}
+
+	if aisContext.currentBaseParams(context.Background()).Client != server.Client() {
+		t.Fatalf("a ctx that is never canceled should leave the Client unchanged")
+	}
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	chaosOperationDefault = "default"
+
+	chaosETagFlapSuffix = "~chaos"
+)
+
+// `chaosOperationNames` lists the keys accepted in a backend's "chaos" section. Settings for
+// chaosOperationDefault apply to each of the others not explicitly present.
+var chaosOperationNames = []string{
+	chaosOperationDefault,
+	"abort_multipart_uploads",
+	"copy_file",
+	"delete_file",
+	"list_directory",
+	"list_objects",
+	"presign_file",
+	"put_file",
+	"read_file",
+	"restore_file",
+	"stat_directory",
+	"stat_file",
+}
+
+// `errChaosInjected` is returned by chaosContextStruct methods electing to inject a failure.
+var errChaosInjected = errors.New("chaos injected error")
+
+// `backendChaosStruct` describes a backend's fault injection settings.
+type backendChaosStruct struct {
+	seed       uint64                           // JSON/YAML "seed"             default:0 (randomly seeded)
+	operations map[string]*chaosOperationStruct // JSON/YAML <operation name>   default:<"default" settings>
+}
+
+// `chaosOperationStruct` describes the faults to inject into each call of a particular backendContextIf method.
+type chaosOperationStruct struct {
+	latency         time.Duration // JSON/YAML "latency"           default:0 (in milliseconds)
+	latencyJitter   time.Duration // JSON/YAML "latency_jitter"    default:0 (in milliseconds; uniformly added to latency)
+	errorRate       float64       // JSON/YAML "error_rate"        default:0.0 (fraction of calls returning errChaosInjected)
+	partialReadRate float64       // JSON/YAML "partial_read_rate" default:0.0 (fraction of read_file calls returning a truncated buf)
+	eTagFlapRate    float64       // JSON/YAML "etag_flap_rate"    default:0.0 (fraction of read_file/stat_file calls returning an altered eTag)
+}
+
+// `chaosContextStruct` is a decorator backendContextIf injecting the faults described
+// by backend.chaos into calls to the backendContextIf it wraps.
+type chaosContextStruct struct {
+	sync.Mutex
+	backend *backendStruct
+	wrapped backendContextIf
+	rand    *rand.Rand
+}
+
+// `parseChaos` parses a backend's optional "chaos" section. If not present, chaos is returned as nil.
+func parseChaos(backendAsMap map[string]interface{}) (chaos *backendChaosStruct, err error) {
+	var (
+		chaosAsInterface     interface{}
+		chaosAsMap           map[string]interface{}
+		chaosOperation       *chaosOperationStruct
+		chaosOperationAsIntf interface{}
+		chaosOperationAsMap  map[string]interface{}
+		chaosOperationDflt   *chaosOperationStruct
+		chaosOperationName   string
+		ok                   bool
+	)
+
+	chaosAsInterface, ok = backendAsMap["chaos"]
+	if !ok {
+		chaos = nil
+		err = nil
+		return
+	}
+
+	chaosAsMap, ok = chaosAsInterface.(map[string]interface{})
+	if !ok {
+		err = errors.New("chaos must be a map")
+		return
+	}
+
+	chaos = &backendChaosStruct{
+		operations: make(map[string]*chaosOperationStruct),
+	}
+
+	chaos.seed, ok = parseUint64(chaosAsMap, "seed", uint64(0))
+	if !ok {
+		err = errors.New("bad chaos.seed")
+		return
+	}
+
+	for chaosOperationName = range chaosAsMap {
+		if (chaosOperationName != "seed") && !slices.Contains(chaosOperationNames, chaosOperationName) {
+			err = fmt.Errorf("unrecognized chaos operation \"%s\"", chaosOperationName)
+			return
+		}
+	}
+
+	chaosOperationDflt = &chaosOperationStruct{}
+
+	for _, chaosOperationName = range chaosOperationNames {
+		chaosOperationAsIntf, ok = chaosAsMap[chaosOperationName]
+		if !ok {
+			chaos.operations[chaosOperationName] = chaosOperationDflt
+			continue
+		}
+
+		chaosOperationAsMap, ok = chaosOperationAsIntf.(map[string]interface{})
+		if !ok {
+			err = fmt.Errorf("bad chaos.%s section", chaosOperationName)
+			return
+		}
+
+		chaosOperation = &chaosOperationStruct{}
+
+		chaosOperation.latency, ok = parseMilliseconds(chaosOperationAsMap, "latency", chaosOperationDflt.latency)
+		if !ok || (chaosOperation.latency < 0) {
+			err = fmt.Errorf("bad chaos.%s.latency", chaosOperationName)
+			return
+		}
+
+		chaosOperation.latencyJitter, ok = parseMilliseconds(chaosOperationAsMap, "latency_jitter", chaosOperationDflt.latencyJitter)
+		if !ok || (chaosOperation.latencyJitter < 0) {
+			err = fmt.Errorf("bad chaos.%s.latency_jitter", chaosOperationName)
+			return
+		}
+
+		chaosOperation.errorRate, ok = parseFloat64(chaosOperationAsMap, "error_rate", chaosOperationDflt.errorRate)
+		if !ok || (chaosOperation.errorRate < 0) || (chaosOperation.errorRate > 1) {
+			err = fmt.Errorf("bad chaos.%s.error_rate - must be between 0 and 1", chaosOperationName)
+			return
+		}
+
+		chaosOperation.partialReadRate, ok = parseFloat64(chaosOperationAsMap, "partial_read_rate", chaosOperationDflt.partialReadRate)
+		if !ok || (chaosOperation.partialReadRate < 0) || (chaosOperation.partialReadRate > 1) {
+			err = fmt.Errorf("bad chaos.%s.partial_read_rate - must be between 0 and 1", chaosOperationName)
+			return
+		}
+
+		chaosOperation.eTagFlapRate, ok = parseFloat64(chaosOperationAsMap, "etag_flap_rate", chaosOperationDflt.eTagFlapRate)
+		if !ok || (chaosOperation.eTagFlapRate < 0) || (chaosOperation.eTagFlapRate > 1) {
+			err = fmt.Errorf("bad chaos.%s.etag_flap_rate - must be between 0 and 1", chaosOperationName)
+			return
+		}
+
+		chaos.operations[chaosOperationName] = chaosOperation
+
+		if chaosOperationName == chaosOperationDefault {
+			chaosOperationDflt = chaosOperation
+		}
+	}
+
+	err = nil
+	return
+}
+
+// `equal` reports whether chaos and other (either of which may be nil) describe the same settings.
+func (chaos *backendChaosStruct) equal(other *backendChaosStruct) bool {
+	var (
+		chaosOperationName string
+	)
+
+	if (chaos == nil) || (other == nil) {
+		return chaos == other
+	}
+
+	if chaos.seed != other.seed {
+		return false
+	}
+
+	for _, chaosOperationName = range chaosOperationNames {
+		if *chaos.operations[chaosOperationName] != *other.operations[chaosOperationName] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// `setupChaosContext` wraps the already established backend.context with a chaosContextStruct.
+func (backend *backendStruct) setupChaosContext() {
+	var (
+		chaosContext = &chaosContextStruct{
+			backend: backend,
+			wrapped: backend.context,
+		}
+		seed = backend.chaos.seed
+	)
+
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	chaosContext.rand = rand.New(rand.NewPCG(seed, seed))
+
+	backend.context = chaosContext
+}
+
+// `unwrapBackendContext` returns the backendContextIf underlying any chaosContextStruct
+// decorating backendContext such that it may be type asserted (e.g. to *s3ContextStruct).
+func unwrapBackendContext(backendContext backendContextIf) backendContextIf {
+	var (
+		chaosContext *chaosContextStruct
+		ok           bool
+	)
+
+	for {
+		chaosContext, ok = backendContext.(*chaosContextStruct)
+		if !ok {
+			return backendContext
+		}
+		backendContext = chaosContext.wrapped
+	}
+}
+
+// `chance` returns true with probability rate.
+func (chaosContext *chaosContextStruct) chance(rate float64) (hit bool) {
+	if rate <= 0 {
+		return false
+	}
+
+	chaosContext.Lock()
+	hit = chaosContext.rand.Float64() < rate
+	chaosContext.Unlock()
+
+	return
+}
+
+// `inject` is called at the start of each backendContextIf method to apply the configured
+// latency (honoring ctx cancellation) and, possibly, return errChaosInjected.
+func (chaosContext *chaosContextStruct) inject(ctx context.Context, chaosOperationName string) (chaosOperation *chaosOperationStruct, err error) {
+	var (
+		latency time.Duration
+		timer   *time.Timer
+	)
+
+	chaosOperation = chaosContext.backend.chaos.operations[chaosOperationName]
+
+	latency = chaosOperation.latency
+	if chaosOperation.latencyJitter > 0 {
+		chaosContext.Lock()
+		latency += time.Duration(chaosContext.rand.Int64N(int64(chaosOperation.latencyJitter) + 1))
+		chaosContext.Unlock()
+	}
+
+	if latency > 0 {
+		timer = time.NewTimer(latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			err = context.Cause(ctx)
+			return
+		}
+	}
+
+	if chaosContext.chance(chaosOperation.errorRate) {
+		err = fmt.Errorf("%w [%s]", errChaosInjected, chaosOperationName)
+		return
+	}
+
+	err = nil
+	return
+}
+
+// `flapETag` returns eTag altered with probability chaosOperation.eTagFlapRate.
+func (chaosContext *chaosContextStruct) flapETag(chaosOperation *chaosOperationStruct, eTag string) string {
+	if chaosContext.chance(chaosOperation.eTagFlapRate) {
+		return eTag + chaosETagFlapSuffix
+	}
+
+	return eTag
+}
+
+// `abortMultipartUploads` is called to abort abandoned multipart uploads.
+func (chaosContext *chaosContextStruct) abortMultipartUploads(ctx context.Context, abortMultipartUploadsInput *abortMultipartUploadsInputStruct) (abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct, err error) {
+	_, err = chaosContext.inject(ctx, "abort_multipart_uploads")
+	if err != nil {
+		return
+	}
+
+	abortMultipartUploadsOutput, err = chaosContext.wrapped.abortMultipartUploads(ctx, abortMultipartUploadsInput)
+	return
+}
+
+// `backendCommon` is called to return a pointer to the context's common `backendStruct`.
+func (chaosContext *chaosContextStruct) backendCommon() (backendCommon *backendStruct) {
+	backendCommon = chaosContext.backend
+	return
+}
+
+// `copyFile` is called to perform a server-side copy of the `file` at the specified source path to
+// the specified destination path, replacing any existing `file` there.
+func (chaosContext *chaosContextStruct) copyFile(ctx context.Context, copyFileInput *copyFileInputStruct) (copyFileOutput *copyFileOutputStruct, err error) {
+	_, err = chaosContext.inject(ctx, "copy_file")
+	if err != nil {
+		return
+	}
+
+	copyFileOutput, err = chaosContext.wrapped.copyFile(ctx, copyFileInput)
+	return
+}
+
+// `deleteFile` is called to remove a `file` at the specified path.
+func (chaosContext *chaosContextStruct) deleteFile(ctx context.Context, deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
+	_, err = chaosContext.inject(ctx, "delete_file")
+	if err != nil {
+		return
+	}
+
+	deleteFileOutput, err = chaosContext.wrapped.deleteFile(ctx, deleteFileInput)
+	return
+}
+
+// `listDirectory` is called to fetch a `page` of the `directory` at the specified path.
+func (chaosContext *chaosContextStruct) listDirectory(ctx context.Context, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+	_, err = chaosContext.inject(ctx, "list_directory")
+	if err != nil {
+		return
+	}
+
+	listDirectoryOutput, err = chaosContext.wrapped.listDirectory(ctx, listDirectoryInput)
+	return
+}
+
+// `listObjects` is called to fetch a `page` of the objects.
+func (chaosContext *chaosContextStruct) listObjects(ctx context.Context, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+	_, err = chaosContext.inject(ctx, "list_objects")
+	if err != nil {
+		return
+	}
+
+	listObjectsOutput, err = chaosContext.wrapped.listObjects(ctx, listObjectsInput)
+	return
+}
+
+// `presignFile` is called to produce a pre-signed GET URL for a `file`.
+func (chaosContext *chaosContextStruct) presignFile(ctx context.Context, presignFileInput *presignFileInputStruct) (presignFileOutput *presignFileOutputStruct, err error) {
+	_, err = chaosContext.inject(ctx, "presign_file")
+	if err != nil {
+		return
+	}
+
+	presignFileOutput, err = chaosContext.wrapped.presignFile(ctx, presignFileInput)
+	return
+}
+
+// `putFile` is called to create (or replace) a `file` at the specified path with the supplied content.
+func (chaosContext *chaosContextStruct) putFile(ctx context.Context, putFileInput *putFileInputStruct) (putFileOutput *putFileOutputStruct, err error) {
+	_, err = chaosContext.inject(ctx, "put_file")
+	if err != nil {
+		return
+	}
+
+	putFileOutput, err = chaosContext.wrapped.putFile(ctx, putFileInput)
+	return
+}
+
+// `readFile` is called to read a range of a `file` at the specified path. Besides injected
+// latency and errors, a successful result may have its buf truncated (a partial read) and/or
+// its eTag altered (as if the object had changed). A notModified result is never altered.
+func (chaosContext *chaosContextStruct) readFile(ctx context.Context, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
+	var (
+		chaosOperation *chaosOperationStruct
+		partialLen     int
+	)
+
+	chaosOperation, err = chaosContext.inject(ctx, "read_file")
+	if err != nil {
+		return
+	}
+
+	readFileOutput, err = chaosContext.wrapped.readFile(ctx, readFileInput)
+	if (err != nil) || readFileOutput.notModified {
+		return
+	}
+
+	if (len(readFileOutput.buf) > 0) && chaosContext.chance(chaosOperation.partialReadRate) {
+		chaosContext.Lock()
+		partialLen = chaosContext.rand.IntN(len(readFileOutput.buf))
+		chaosContext.Unlock()
+
+		readFileOutput.buf = readFileOutput.buf[:partialLen]
+	}
+
+	readFileOutput.eTag = chaosContext.flapETag(chaosOperation, readFileOutput.eTag)
+
+	return
+}
+
+// `restoreFile` is called to request that the archived content of the `file` at the specified path be restored.
+func (chaosContext *chaosContextStruct) restoreFile(ctx context.Context, restoreFileInput *restoreFileInputStruct) (restoreFileOutput *restoreFileOutputStruct, err error) {
+	_, err = chaosContext.inject(ctx, "restore_file")
+	if err != nil {
+		return
+	}
+
+	restoreFileOutput, err = chaosContext.wrapped.restoreFile(ctx, restoreFileInput)
+	return
+}
+
+// `statDirectory` is called to verify that the specified path refers to a `directory`.
+func (chaosContext *chaosContextStruct) statDirectory(ctx context.Context, statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
+	_, err = chaosContext.inject(ctx, "stat_directory")
+	if err != nil {
+		return
+	}
+
+	statDirectoryOutput, err = chaosContext.wrapped.statDirectory(ctx, statDirectoryInput)
+	return
+}
+
+// `statFile` is called to fetch the `file` metadata at the specified path. Besides injected
+// latency and errors, a successful result may have its eTag altered (as if the object had
+// changed). A notModified result is never altered.
+func (chaosContext *chaosContextStruct) statFile(ctx context.Context, statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
+	var (
+		chaosOperation *chaosOperationStruct
+	)
+
+	chaosOperation, err = chaosContext.inject(ctx, "stat_file")
+	if err != nil {
+		return
+	}
+
+	statFileOutput, err = chaosContext.wrapped.statFile(ctx, statFileInput)
+	if (err != nil) || statFileOutput.notModified {
+		return
+	}
+
+	statFileOutput.eTag = chaosContext.flapETag(chaosOperation, statFileOutput.eTag)
+
+	return
+}
+
+// `redactSecrets` defers to the wrapped backendContextIf.
+func (chaosContext *chaosContextStruct) redactSecrets(s string) string {
+	return chaosContext.wrapped.redactSecrets(s)
+}
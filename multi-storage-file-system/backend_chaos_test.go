@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// `newChaosTestBackend` returns a RAM backend holding fileA wrapped by a chaosContextStruct
+// configured from chaosAsMap.
+func newChaosTestBackend(t *testing.T, chaosAsMap map[string]interface{}) (backend *backendStruct) {
+	var (
+		err error
+	)
+
+	backend = &backendStruct{
+		dirName:     "chaos",
+		backendType: "RAM",
+		backendTypeSpecifics: &backendConfigRAMStruct{
+			maxListPageSize:     defaultRAMMaxListPageSize,
+			maxTotalObjectSpace: defaultRAMMaxTotalObjectSpace,
+			maxTotalObjects:     defaultRAMMaxTotalObjects,
+		},
+	}
+
+	backend.chaos, err = parseChaos(map[string]interface{}{"chaos": chaosAsMap})
+	if err != nil {
+		t.Fatalf("parseChaos() failed: %v", err)
+	}
+
+	err = backend.setupContext()
+	if err != nil {
+		t.Fatalf("setupContext() failed: %v", err)
+	}
+
+	_, err = backend.context.(*chaosContextStruct).wrapped.putFile(context.Background(), &putFileInputStruct{filePath: "fileA", buf: []byte("/fileA\n")})
+	if err != nil {
+		t.Fatalf("putFile(\"fileA\") failed: %v", err)
+	}
+
+	return
+}
+
+func TestChaosParse(t *testing.T) {
+	var (
+		chaos *backendChaosStruct
+		err   error
+	)
+
+	chaos, err = parseChaos(map[string]interface{}{})
+	if (err != nil) || (chaos != nil) {
+		t.Fatalf("parseChaos() of a backend without a chaos section should have returned nil, nil")
+	}
+
+	_, err = parseChaos(map[string]interface{}{"chaos": map[string]interface{}{"write_file": map[string]interface{}{}}})
+	if err == nil {
+		t.Fatalf("parseChaos() should have rejected an unrecognized operation")
+	}
+
+	_, err = parseChaos(map[string]interface{}{"chaos": map[string]interface{}{"read_file": map[string]interface{}{"error_rate": float64(1.5)}}})
+	if err == nil {
+		t.Fatalf("parseChaos() should have rejected an error_rate > 1")
+	}
+
+	chaos, err = parseChaos(map[string]interface{}{"chaos": map[string]interface{}{
+		"default":   map[string]interface{}{"latency": float64(20), "error_rate": float64(0.5)},
+		"read_file": map[string]interface{}{"error_rate": float64(0.25)},
+	}})
+	if err != nil {
+		t.Fatalf("parseChaos() failed: %v", err)
+	}
+
+	if (chaos.operations["read_file"].latency != 20*time.Millisecond) || (chaos.operations["read_file"].errorRate != 0.25) {
+		t.Fatalf("read_file should have inherited latency but overridden error_rate: %+v", *chaos.operations["read_file"])
+	}
+	if (chaos.operations["stat_file"].latency != 20*time.Millisecond) || (chaos.operations["stat_file"].errorRate != 0.5) {
+		t.Fatalf("stat_file should have inherited the default settings: %+v", *chaos.operations["stat_file"])
+	}
+
+	if !chaos.equal(chaos) || chaos.equal(nil) || !(*backendChaosStruct)(nil).equal(nil) {
+		t.Fatalf("equal() returned unexpected results")
+	}
+}
+
+func TestChaosInjection(t *testing.T) {
+	var (
+		backend        *backendStruct
+		cancel         context.CancelFunc
+		ctx            context.Context
+		err            error
+		readFileOutput *readFileOutputStruct
+		statFileOutput *statFileOutputStruct
+	)
+
+	backend = newChaosTestBackend(t, map[string]interface{}{
+		"seed":           float64(1),
+		"delete_file":    map[string]interface{}{"error_rate": float64(1)},
+		"read_file":      map[string]interface{}{"partial_read_rate": float64(1), "etag_flap_rate": float64(1)},
+		"stat_directory": map[string]interface{}{"latency": float64(10000)},
+		"stat_file":      map[string]interface{}{"etag_flap_rate": float64(1)},
+	})
+
+	if _, ok := unwrapBackendContext(backend.context).(*ramContextStruct); !ok {
+		t.Fatalf("unwrapBackendContext() should have returned the wrapped *ramContextStruct")
+	}
+
+	_, err = backend.context.deleteFile(context.Background(), &deleteFileInputStruct{filePath: "fileA"})
+	if !errors.Is(err, errChaosInjected) {
+		t.Fatalf("deleteFile() should have returned errChaosInjected but returned: %v", err)
+	}
+
+	readFileOutput, err = backend.context.readFile(context.Background(), &readFileInputStruct{filePath: "fileA", offset: 0, length: 7})
+	if err != nil {
+		t.Fatalf("readFile(\"fileA\") failed: %v", err)
+	}
+	if (len(readFileOutput.buf) >= len("/fileA\n")) || !strings.HasPrefix("/fileA\n", string(readFileOutput.buf)) {
+		t.Fatalf("readFile(\"fileA\") should have returned a partial read but returned %q", readFileOutput.buf)
+	}
+	if !strings.HasSuffix(readFileOutput.eTag, chaosETagFlapSuffix) {
+		t.Fatalf("readFile(\"fileA\") should have returned a flapped eTag but returned \"%s\"", readFileOutput.eTag)
+	}
+
+	statFileOutput, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "fileA"})
+	if err != nil {
+		t.Fatalf("statFile(\"fileA\") failed: %v", err)
+	}
+	if (statFileOutput.size != uint64(len("/fileA\n"))) || !strings.HasSuffix(statFileOutput.eTag, chaosETagFlapSuffix) {
+		t.Fatalf("statFile(\"fileA\") should have returned the true size and a flapped eTag")
+	}
+
+	// Injected latency must honor ctx cancellation
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = backend.context.statDirectory(ctx, &statDirectoryInputStruct{dirPath: ""})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("statDirectory() should have returned context.DeadlineExceeded but returned: %v", err)
+	}
+
+	// Operations without injected faults are passed through unaltered
+
+	_, err = backend.context.listDirectory(context.Background(), &listDirectoryInputStruct{dirPath: ""})
+	if err != nil {
+		t.Fatalf("listDirectory() failed: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// `backendFilterStruct` describes which of a backend's files and directories are exposed. Each
+// pattern is a glob (as supported by path.Match) matched against the basename of a file or
+// directory or, if the pattern contains a "/" other than a trailing one, against its entire
+// path relative to the backend's prefix. A pattern ending in "/" only matches directories.
+// A file or directory matching any exclude pattern (or within a directory that does) is hidden.
+// Otherwise, if any include patterns are specified, a file must match one of them to be exposed.
+// Include patterns do not apply to directories so that included files remain reachable.
+type backendFilterStruct struct {
+	include []string // JSON/YAML "include" default:[] (every file)
+	exclude []string // JSON/YAML "exclude" default:[] (nothing)
+}
+
+// `parseFilter` parses a backend's optional "include" and "exclude" lists. If neither is
+// present, filter is returned as nil (i.e. everything is exposed).
+func parseFilter(backendAsMap map[string]interface{}) (filter *backendFilterStruct, err error) {
+	filter = &backendFilterStruct{}
+
+	filter.include, err = parseFilterPatterns(backendAsMap, "include")
+	if err != nil {
+		filter = nil
+		return
+	}
+
+	filter.exclude, err = parseFilterPatterns(backendAsMap, "exclude")
+	if err != nil {
+		filter = nil
+		return
+	}
+
+	if (filter.include == nil) && (filter.exclude == nil) {
+		filter = nil
+	}
+
+	return
+}
+
+// `parseFilterPatterns` parses the optional list of glob patterns at key. If not present,
+// patterns is returned as nil.
+func parseFilterPatterns(m map[string]interface{}, key string) (patterns []string, err error) {
+	var (
+		ok                   bool
+		pattern              string
+		patternAsInterface   interface{}
+		patternsAsInterface  interface{}
+		patternsAsInterfaces []interface{}
+	)
+
+	patternsAsInterface, ok = m[key]
+	if !ok {
+		return
+	}
+
+	patternsAsInterfaces, ok = patternsAsInterface.([]interface{})
+	if !ok {
+		err = fmt.Errorf("%s must be a list", key)
+		return
+	}
+
+	patterns = make([]string, 0, len(patternsAsInterfaces))
+
+	for _, patternAsInterface = range patternsAsInterfaces {
+		pattern, ok = patternAsInterface.(string)
+		if !ok || (pattern == "") || (pattern == "/") || strings.HasPrefix(pattern, "/") {
+			err = fmt.Errorf("bad %s element (%v)", key, patternAsInterface)
+			patterns = nil
+			return
+		}
+		_, err = path.Match(pattern, "")
+		if err != nil {
+			err = fmt.Errorf("bad %s element (%v): %v", key, patternAsInterface, err)
+			patterns = nil
+			return
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return
+}
+
+// `equal` reports whether filter and other expose the same files and directories.
+func (filter *backendFilterStruct) equal(other *backendFilterStruct) bool {
+	if (filter == nil) || (other == nil) {
+		return (filter == nil) && (other == nil)
+	}
+
+	return slices.Equal(filter.include, other.include) &&
+		slices.Equal(filter.exclude, other.exclude)
+}
+
+// `filterPatternMatches` reports whether pattern matches the file or directory at entryPath
+// (relative to the backend's prefix). Directory paths end in "/".
+func filterPatternMatches(pattern string, entryPath string) (matches bool) {
+	var (
+		isDir = strings.HasSuffix(entryPath, "/")
+	)
+
+	if strings.HasSuffix(pattern, "/") {
+		if !isDir {
+			return false
+		}
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	entryPath = strings.TrimSuffix(entryPath, "/")
+
+	if !strings.Contains(pattern, "/") {
+		entryPath = path.Base(entryPath)
+	}
+
+	matches, _ = path.Match(pattern, entryPath)
+
+	return
+}
+
+// `hides` reports whether the file or directory (if ending in "/") at entryPath (relative to
+// the backend's prefix) is hidden by filter. A nil filter hides nothing.
+func (filter *backendFilterStruct) hides(entryPath string) bool {
+	var (
+		dirPath string
+		pattern string
+	)
+
+	if filter == nil {
+		return false
+	}
+
+	for _, pattern = range filter.exclude {
+		if filterPatternMatches(pattern, entryPath) {
+			return true
+		}
+
+		// Any directory containing entryPath being excluded also hides entryPath
+
+		for dirPath = path.Dir(strings.TrimSuffix(entryPath, "/")); dirPath != "."; dirPath = path.Dir(dirPath) {
+			if filterPatternMatches(pattern, dirPath+"/") {
+				return true
+			}
+		}
+	}
+
+	if (len(filter.include) == 0) || strings.HasSuffix(entryPath, "/") {
+		return false
+	}
+
+	for _, pattern = range filter.include {
+		if filterPatternMatches(pattern, entryPath) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// `filterListDirectoryOutput` removes from listDirectoryOutput (listing dirPath) those
+// subdirectories and files hidden by filter. As listDirectoryOutput.isTruncated is left
+// intact, a page may well be emptied without signaling the end of the listing.
+func (filter *backendFilterStruct) filterListDirectoryOutput(dirPath string, listDirectoryOutput *listDirectoryOutputStruct) {
+	if filter == nil {
+		return
+	}
+
+	listDirectoryOutput.subdirectory = slices.DeleteFunc(listDirectoryOutput.subdirectory, func(subdirectory string) bool {
+		return filter.hides(dirPath + subdirectory + "/")
+	})
+
+	listDirectoryOutput.file = slices.DeleteFunc(listDirectoryOutput.file, func(file listDirectoryOutputFileStruct) bool {
+		return filter.hides(dirPath + file.basename)
+	})
+}
+
+// `filterManifestEntries` returns manifestEntries (those of the manifest part for dirPath)
+// less the subdirectories and files hidden by filter.
+func (filter *backendFilterStruct) filterManifestEntries(dirPath string, manifestEntries []manifestDirEntry) []manifestDirEntry {
+	if filter == nil {
+		return manifestEntries
+	}
+
+	return slices.DeleteFunc(manifestEntries, func(manifestEntry manifestDirEntry) bool {
+		if manifestEntry.Kind == "d" {
+			return filter.hides(dirPath + manifestEntry.Basename + "/")
+		}
+		return filter.hides(dirPath + manifestEntry.Basename)
+	})
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"syscall"
+	"testing"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+func TestFilterParse(t *testing.T) {
+	var (
+		err    error
+		filter *backendFilterStruct
+	)
+
+	filter, err = parseFilter(map[string]interface{}{})
+	if (err != nil) || (filter != nil) {
+		t.Fatalf("parseFilter() of a backend without include or exclude should have returned nil, nil")
+	}
+
+	_, err = parseFilter(map[string]interface{}{"include": "*.tar"})
+	if err == nil {
+		t.Fatalf("parseFilter() should have rejected a non-list include")
+	}
+
+	_, err = parseFilter(map[string]interface{}{"exclude": []interface{}{"[_tmp/"}})
+	if err == nil {
+		t.Fatalf("parseFilter() should have rejected a malformed exclude pattern")
+	}
+
+	filter, err = parseFilter(map[string]interface{}{
+		"include": []interface{}{"*.tar", "*.idx", "meta/*.json"},
+		"exclude": []interface{}{"_tmp/", "*.partial.tar"},
+	})
+	if err != nil {
+		t.Fatalf("parseFilter() failed: %v", err)
+	}
+
+	for _, testCase := range []struct {
+		entryPath string
+		expected  bool
+	}{
+		{"shard-0.tar", false},
+		{"a/b/shard-0.idx", false},
+		{"shard-0.txt", true},
+		{"shard-0.partial.tar", true},
+		{"_tmp/", true},
+		{"a/_tmp/", true},
+		{"a/_tmp/shard-0.tar", true},
+		{"_tmp", true},
+		{"a/", false},
+		{"meta/info.json", false},
+		{"a/meta/info.json", true},
+	} {
+		if filter.hides(testCase.entryPath) != testCase.expected {
+			t.Errorf("hides(\"%s\") should have returned %v", testCase.entryPath, testCase.expected)
+		}
+	}
+
+	if !filter.equal(filter) || filter.equal(nil) || (*backendFilterStruct)(nil).hides("_tmp/") {
+		t.Fatalf("equal()/hides() returned unexpected results")
+	}
+
+	manifestEntries := filter.filterManifestEntries("a/", []manifestDirEntry{
+		{Kind: "d", Basename: "_tmp"},
+		{Kind: "d", Basename: "b"},
+		{Kind: "f", Basename: "shard-0.tar"},
+		{Kind: "f", Basename: "shard-0.txt"},
+		{Kind: "f", Basename: "shard-1.partial.tar"},
+	})
+	if (len(manifestEntries) != 2) || (manifestEntries[0].Basename != "b") || (manifestEntries[1].Basename != "shard-0.tar") {
+		t.Fatalf("filterManifestEntries() returned %+v (expected only \"b\" and \"shard-0.tar\")", manifestEntries)
+	}
+}
+
+func TestFissionBackendFilter(t *testing.T) {
+	var (
+		backend             *backendStruct
+		dir1Ino             uint64
+		err                 error
+		errno               syscall.Errno
+		listDirectoryOutput *listDirectoryOutputStruct
+		lookupOut           *fission.LookupOut
+		ramDirIno           uint64
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	globalsLock("backend_filter_test.go:92:2:TestFissionBackendFilter")
+	backend = globals.config.backends["ram"]
+	backend.filter = &backendFilterStruct{include: []string{"file[AC]"}, exclude: []string{"dir2/"}}
+	globalsUnlock()
+
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), backend.context, &listDirectoryInputStruct{dirPath: ""})
+	if err != nil {
+		t.Fatalf("listDirectoryWrapper(\"\") failed: %v", err)
+	}
+	if !slices.Equal(listDirectoryOutput.subdirectory, []string{"dir1"}) || (len(listDirectoryOutput.file) != 1) || (listDirectoryOutput.file[0].basename != "fileA") {
+		t.Fatalf("listDirectoryWrapper(\"\") returned unexpected content: %#v", listDirectoryOutput)
+	}
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.LookupIn{Name: []byte("ram")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") failed (errno: %v)", errno)
+	}
+	ramDirIno = lookupOut.EntryOut.NodeID
+
+	for _, testCase := range []struct {
+		name     string
+		expected syscall.Errno
+	}{
+		{"fileA", 0},
+		{"fileB", syscall.ENOENT},
+		{"dir1", 0},
+		{"dir2", syscall.ENOENT},
+	} {
+		_, errno = globals.DoLookup(&fission.InHeader{NodeID: ramDirIno}, &fission.LookupIn{Name: []byte(testCase.name)})
+		if errno != testCase.expected {
+			t.Errorf("DoLookup(ramDirIno,Name:\"%s\") returned errno: %v (expected: %v)", testCase.name, errno, testCase.expected)
+		}
+	}
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: ramDirIno}, &fission.LookupIn{Name: []byte("dir1")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(ramDirIno,Name:\"dir1\") failed (errno: %v)", errno)
+	}
+	dir1Ino = lookupOut.EntryOut.NodeID
+
+	_, errno = globals.DoLookup(&fission.InHeader{NodeID: dir1Ino}, &fission.LookupIn{Name: []byte("fileC")})
+	if errno != 0 {
+		t.Errorf("DoLookup(dir1Ino,Name:\"fileC\") failed (errno: %v)", errno)
+	}
+}
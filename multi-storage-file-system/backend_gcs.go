@@ -5,7 +5,6 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"path"
@@ -136,15 +135,95 @@ func (backend *backendStruct) setupGCSContext() (err error) {
 	return
 }
 
+// `abortMultipartUploads` is called to abort abandoned multipart uploads. As GCS backends
+// never initiate multipart uploads, errAbortMultipartUploadsNotSupported is returned.
+func (gcsContext *gcsContextStruct) abortMultipartUploads(ctx context.Context, abortMultipartUploadsInput *abortMultipartUploadsInputStruct) (abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct, err error) {
+	err = errAbortMultipartUploadsNotSupported
+	return
+}
+
 // `backendCommon` is called to return a pointer to the context's common `backendStruct`.
 func (backend *gcsContextStruct) backendCommon() (backendCommon *backendStruct) {
 	backendCommon = backend.backend
 	return
 }
 
+// `retryOptions` returns the storage.RetryOption's to apply to a handle used on behalf of ctx.
+// In addition to the configured backoff, each retried error is recorded in ctx's
+// retryHistoryStruct. As the storage package does not expose the backoff it selects, no
+// retry delay is recorded.
+func (gcsContext *gcsContextStruct) retryOptions(ctx context.Context) (retryOptions []storage.RetryOption) {
+	var (
+		retryHistory = retryHistoryFromContext(ctx)
+	)
+
+	retryOptions = []storage.RetryOption{gcsContext.retryOption}
+
+	if retryHistory != nil {
+		retryOptions = append(retryOptions, storage.WithErrorFunc(func(err error) (shouldRetry bool) {
+			shouldRetry = storage.ShouldRetry(err)
+			if shouldRetry {
+				retryHistory.recordRetry(err, 0)
+			} else {
+				retryHistory.recordFailure(err, 0)
+			}
+			return
+		}))
+	}
+
+	return
+}
+
+// `copyFile` is called to perform a server-side copy of the "file" at the specified source path
+// to the specified destination path. The storage.Copier issues as many rewrite requests as
+// required to complete the copy of arbitrarily large objects.
+func (gcsContext *gcsContextStruct) copyFile(ctx context.Context, copyFileInput *copyFileInputStruct) (copyFileOutput *copyFileOutputStruct, err error) {
+	var (
+		attrs           *storage.ObjectAttrs
+		bucketHandle    *storage.BucketHandle
+		dstObjectHandle *storage.ObjectHandle
+		generation      int64
+		metageneration  int64
+		srcObjectHandle *storage.ObjectHandle
+	)
+
+	bucketHandle = gcsContext.gcsClient.Bucket(gcsContext.backend.bucketContainerName)
+
+	srcObjectHandle = bucketHandle.Object(gcsContext.backend.prefix + copyFileInput.srcFilePath)
+	srcObjectHandle = srcObjectHandle.Retryer(gcsContext.retryOptions(ctx)...)
+
+	if copyFileInput.ifMatch != "" {
+		generation, metageneration, err = eTagToGenerationMetageneration(copyFileInput.ifMatch)
+		if err != nil {
+			err = fmt.Errorf("[GCS] eTagToGenerationMetageneration(copyFileInput.ifMatch) failed: %v", err)
+			return
+		}
+
+		srcObjectHandle = srcObjectHandle.If(storage.Conditions{
+			GenerationMatch:     generation,
+			MetagenerationMatch: metageneration,
+		})
+	}
+
+	dstObjectHandle = bucketHandle.Object(gcsContext.backend.prefix + copyFileInput.dstFilePath)
+	dstObjectHandle = dstObjectHandle.Retryer(gcsContext.retryOptions(ctx)...)
+
+	attrs, err = dstObjectHandle.CopierFrom(srcObjectHandle).Run(ctx)
+	if err != nil {
+		err = fmt.Errorf("[GCS] dstObjectHandle.CopierFrom(srcObjectHandle).Run() failed: %v", err)
+		return
+	}
+
+	copyFileOutput = &copyFileOutputStruct{
+		eTag: generationMetagenerationToETag(attrs.Generation, attrs.Metageneration),
+	}
+
+	return
+}
+
 // `deleteFile` is called to remove a "file" at the specified path.
 // If a `subdirectory` or nothing is found at that path, an error will be returned.
-func (gcsContext *gcsContextStruct) deleteFile(deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
+func (gcsContext *gcsContextStruct) deleteFile(ctx context.Context, deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
 	var (
 		generation     int64
 		metageneration int64
@@ -152,7 +231,7 @@ func (gcsContext *gcsContextStruct) deleteFile(deleteFileInput *deleteFileInputS
 	)
 
 	objectHandle = gcsContext.gcsClient.Bucket(gcsContext.backend.bucketContainerName).Object(gcsContext.backend.prefix + deleteFileInput.filePath)
-	objectHandle = objectHandle.Retryer(gcsContext.retryOption)
+	objectHandle = objectHandle.Retryer(gcsContext.retryOptions(ctx)...)
 
 	if deleteFileInput.ifMatch != "" {
 		generation, metageneration, err = eTagToGenerationMetageneration(deleteFileInput.ifMatch)
@@ -167,9 +246,13 @@ func (gcsContext *gcsContextStruct) deleteFile(deleteFileInput *deleteFileInputS
 		})
 	}
 
-	err = objectHandle.Delete(context.Background())
+	err = objectHandle.Delete(ctx)
 	if err != nil {
-		err = fmt.Errorf("[GCS] objectHandle.Delete() failed: %v", err)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			err = fmt.Errorf("%w: [GCS] objectHandle.Delete() failed: %v", errFileNotFound, err)
+		} else {
+			err = fmt.Errorf("[GCS] objectHandle.Delete() failed: %v", err)
+		}
 		return
 	}
 
@@ -180,7 +263,7 @@ func (gcsContext *gcsContextStruct) deleteFile(deleteFileInput *deleteFileInputS
 // An empty continuationToken or empty list of directory elements (`subdirectories` and `files`)
 // indicates the `directory` has been completely enumerated. The `isTruncated` field will also
 // align with this convention.
-func (gcsContext *gcsContextStruct) listDirectory(listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+func (gcsContext *gcsContextStruct) listDirectory(ctx context.Context, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
 	var (
 		bucketHandle          *storage.BucketHandle
 		nextContinuationToken string
@@ -192,14 +275,17 @@ func (gcsContext *gcsContextStruct) listDirectory(listDirectoryInput *listDirect
 	)
 
 	bucketHandle = gcsContext.gcsClient.Bucket(gcsContext.backend.bucketContainerName)
-	bucketHandle = bucketHandle.Retryer(gcsContext.retryOption)
+	bucketHandle = bucketHandle.Retryer(gcsContext.retryOptions(ctx)...)
 
 	query = &storage.Query{
 		Prefix:    gcsContext.backend.prefix + listDirectoryInput.dirPath,
 		Delimiter: "/",
 	}
+	if (listDirectoryInput.continuationToken == "") && (listDirectoryInput.startAfter != "") {
+		query.StartOffset = gcsStartOffset(gcsContext.backend.prefix + listDirectoryInput.startAfter)
+	}
 
-	objectIterator = bucketHandle.Objects(context.Background(), query)
+	objectIterator = bucketHandle.Objects(ctx, query)
 
 	// [TODO] Revert NextPage() workaround in listDirectory()
 	//
@@ -259,7 +345,7 @@ func (gcsContext *gcsContextStruct) listDirectory(listDirectoryInput *listDirect
 // `listObjects` is called to fetch a `page` of the objects. An empty continuationToken or
 // empty list of elements (`objects`) indicates the list of `objects` has been completely
 // enumerated. The `isTruncated` field will also align with this convention.
-func (gcsContext *gcsContextStruct) listObjects(listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+func (gcsContext *gcsContextStruct) listObjects(ctx context.Context, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
 	var (
 		backend               = gcsContext.backend
 		bucketHandle          *storage.BucketHandle
@@ -277,14 +363,16 @@ func (gcsContext *gcsContextStruct) listObjects(listObjectsInput *listObjectsInp
 	}
 
 	bucketHandle = gcsContext.gcsClient.Bucket(gcsContext.backend.bucketContainerName)
-	bucketHandle = bucketHandle.Retryer(gcsContext.retryOption)
+	bucketHandle = bucketHandle.Retryer(gcsContext.retryOptions(ctx)...)
 
 	query = &storage.Query{
-		Prefix:      gcsContext.backend.prefix + listObjectsInput.prefix,
-		StartOffset: listObjectsInput.startAfter,
+		Prefix: gcsContext.backend.prefix + listObjectsInput.prefix,
+	}
+	if listObjectsInput.startAfter != "" {
+		query.StartOffset = gcsStartOffset(gcsContext.backend.prefix + listObjectsInput.startAfter)
 	}
 
-	objectIterator = bucketHandle.Objects(context.Background(), query)
+	objectIterator = bucketHandle.Objects(ctx, query)
 
 	// [TODO] Revert NextPage() workaround in listDirectory()
 	//
@@ -336,7 +424,49 @@ func (gcsContext *gcsContextStruct) listObjects(listObjectsInput *listObjectsInp
 	return
 }
 
-// `readFile` is called to read a range of a `file` at the specified path.
+// `presignFile` is called to produce a pre-signed GET URL for a `file`. As GCS backends are
+// accessed either anonymously or via an API key (neither able to sign URLs),
+// errPresignFileNotSupported is returned.
+func (gcsContext *gcsContextStruct) presignFile(ctx context.Context, presignFileInput *presignFileInputStruct) (presignFileOutput *presignFileOutputStruct, err error) {
+	err = errPresignFileNotSupported
+	return
+}
+
+// `putFile` is called to create (or replace) a "file" at the specified path with the supplied content.
+func (gcsContext *gcsContextStruct) putFile(ctx context.Context, putFileInput *putFileInputStruct) (putFileOutput *putFileOutputStruct, err error) {
+	var (
+		attrs        *storage.ObjectAttrs
+		objectHandle *storage.ObjectHandle
+		writer       *storage.Writer
+	)
+
+	objectHandle = gcsContext.gcsClient.Bucket(gcsContext.backend.bucketContainerName).Object(gcsContext.backend.prefix + putFileInput.filePath)
+	objectHandle = objectHandle.Retryer(gcsContext.retryOptions(ctx)...)
+
+	writer = objectHandle.NewWriter(ctx)
+
+	_, err = writer.Write(putFileInput.buf)
+	if err != nil {
+		_ = writer.Close()
+		err = fmt.Errorf("[GCS] writer.Write() failed: %v", err)
+		return
+	}
+
+	err = writer.Close()
+	if err != nil {
+		err = fmt.Errorf("[GCS] writer.Close() failed: %v", err)
+		return
+	}
+
+	attrs = writer.Attrs()
+
+	putFileOutput = &putFileOutputStruct{
+		eTag: generationMetagenerationToETag(attrs.Generation, attrs.Metageneration),
+	}
+
+	return
+}
+
 // `redactSecrets` redacts this GCS backend's configured API key from s.
 func (gcsContext *gcsContextStruct) redactSecrets(s string) string {
 	if cfg, ok := gcsContext.backend.backendTypeSpecifics.(*backendConfigGCSStruct); ok && cfg != nil {
@@ -345,9 +475,11 @@ func (gcsContext *gcsContextStruct) redactSecrets(s string) string {
 	return s
 }
 
+// `readFile` is called to read a range of a `file` at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (gcsContext *gcsContextStruct) readFile(readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
+func (gcsContext *gcsContextStruct) readFile(ctx context.Context, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
 	var (
+		attrs             *storage.ObjectAttrs
 		generation        int64
 		metageneration    int64
 		objectHandle      *storage.ObjectHandle
@@ -357,7 +489,7 @@ func (gcsContext *gcsContextStruct) readFile(readFileInput *readFileInputStruct)
 	)
 
 	objectHandle = gcsContext.gcsClient.Bucket(gcsContext.backend.bucketContainerName).Object(gcsContext.backend.prefix + readFileInput.filePath)
-	objectHandle = objectHandle.Retryer(gcsContext.retryOption)
+	objectHandle = objectHandle.Retryer(gcsContext.retryOptions(ctx)...)
 
 	if readFileInput.ifMatch != "" {
 		generation, metageneration, err = eTagToGenerationMetageneration(readFileInput.ifMatch)
@@ -372,32 +504,64 @@ func (gcsContext *gcsContextStruct) readFile(readFileInput *readFileInputStruct)
 		})
 	}
 
-	rangeReaderOffset = readFileInput.offsetCacheLine * globals.config.cacheLineSize
-	rangeReaderLength = globals.config.cacheLineSize
+	if readFileInput.ifNoneMatch != "" {
+		// Note: GCS has no conditional read matching on both generation and metageneration,
+		//       so this .ifNoneMatch is a non-atomic implementation via a preceding metadata fetch
 
-	rangeReader, err = objectHandle.NewRangeReader(context.Background(), int64(rangeReaderOffset), int64(rangeReaderLength))
+		attrs, err = objectHandle.Attrs(ctx)
+		if err != nil {
+			err = fmt.Errorf("[GCS] objectHandle.Attrs() failed: %v", err)
+			return
+		}
+
+		if generationMetagenerationToETag(attrs.Generation, attrs.Metageneration) == readFileInput.ifNoneMatch {
+			readFileOutput = &readFileOutputStruct{
+				eTag:        readFileInput.ifNoneMatch,
+				notModified: true,
+			}
+			return
+		}
+	}
+
+	rangeReaderOffset = readFileInput.offset
+	rangeReaderLength = readFileInput.length
+
+	rangeReader, err = objectHandle.NewRangeReader(ctx, int64(rangeReaderOffset), int64(rangeReaderLength))
 	if err == nil {
 		readFileOutput = &readFileOutputStruct{
 			eTag: generationMetagenerationToETag(rangeReader.Attrs.Generation, rangeReader.Attrs.Metageneration),
 		}
 
-		readFileOutput.buf, err = io.ReadAll(rangeReader)
+		readFileOutput.buf, err = readAllSized(rangeReader, rangeReader.Remain())
 		if err != nil {
-			globals.logger.Fatalf("[FATAL] io.ReadAll(rangeReader) failed: %v", err)
+			_ = rangeReader.Close()
+			readFileOutput = nil
+			err = fmt.Errorf("[GCS] readAllSized(rangeReader, rangeReader.Remain()) failed: %w", err)
+			return
 		}
 
 		err = rangeReader.Close()
 		if err != nil {
-			globals.logger.Fatalf("[FATAL] rangeReader.Close() failed: %v", err)
+			readFileOutput = nil
+			err = fmt.Errorf("[GCS] rangeReader.Close() failed: %w", err)
 		}
+	} else if errors.Is(err, storage.ErrObjectNotExist) {
+		err = fmt.Errorf("%w: [GCS] objectHandle.NewRangeReader() failed: %v", errFileNotFound, err)
 	}
 
 	return
 }
 
+// `restoreFile` is called to request that the archived content of the `file` at the specified
+// path be restored. As GCS backends do not archive content, errRestoreFileNotSupported is returned.
+func (gcsContext *gcsContextStruct) restoreFile(ctx context.Context, restoreFileInput *restoreFileInputStruct) (restoreFileOutput *restoreFileOutputStruct, err error) {
+	err = errRestoreFileNotSupported
+	return
+}
+
 // `statDirectory` is called to verify that the specified path refers to a `directory`.
 // An error is returned if either the specified path is not a `directory` or non-existent.
-func (gcsContext *gcsContextStruct) statDirectory(statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
+func (gcsContext *gcsContextStruct) statDirectory(ctx context.Context, statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
 	var (
 		bucketHandle     *storage.BucketHandle
 		objectAttrsSlice []*storage.ObjectAttrs
@@ -407,13 +571,13 @@ func (gcsContext *gcsContextStruct) statDirectory(statDirectoryInput *statDirect
 	)
 
 	bucketHandle = gcsContext.gcsClient.Bucket(gcsContext.backend.bucketContainerName)
-	bucketHandle = bucketHandle.Retryer(gcsContext.retryOption)
+	bucketHandle = bucketHandle.Retryer(gcsContext.retryOptions(ctx)...)
 
 	query = &storage.Query{
 		Prefix: gcsContext.backend.prefix + statDirectoryInput.dirPath,
 	}
 
-	objectIterator = bucketHandle.Objects(context.Background(), query)
+	objectIterator = bucketHandle.Objects(ctx, query)
 
 	pager = iterator.NewPager(objectIterator, int(1), "")
 
@@ -433,7 +597,7 @@ func (gcsContext *gcsContextStruct) statDirectory(statDirectoryInput *statDirect
 
 // `statFile` is called to fetch the `file` metadata at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (gcsContext *gcsContextStruct) statFile(statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
+func (gcsContext *gcsContextStruct) statFile(ctx context.Context, statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
 	var (
 		attrs          *storage.ObjectAttrs
 		generation     int64
@@ -442,7 +606,7 @@ func (gcsContext *gcsContextStruct) statFile(statFileInput *statFileInputStruct)
 	)
 
 	objectHandle = gcsContext.gcsClient.Bucket(gcsContext.backend.bucketContainerName).Object(gcsContext.backend.prefix + statFileInput.filePath)
-	objectHandle = objectHandle.Retryer(gcsContext.retryOption)
+	objectHandle = objectHandle.Retryer(gcsContext.retryOptions(ctx)...)
 
 	if statFileInput.ifMatch != "" {
 		generation, metageneration, err = eTagToGenerationMetageneration(statFileInput.ifMatch)
@@ -457,16 +621,30 @@ func (gcsContext *gcsContextStruct) statFile(statFileInput *statFileInputStruct)
 		})
 	}
 
-	attrs, err = objectHandle.Attrs(context.Background())
+	attrs, err = objectHandle.Attrs(ctx)
 	if err != nil {
-		err = fmt.Errorf("[GCS] objectHandle.Attrs() failed: %v", err)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			err = fmt.Errorf("%w: [GCS] objectHandle.Attrs() failed: %v", errFileNotFound, err)
+		} else {
+			err = fmt.Errorf("[GCS] objectHandle.Attrs() failed: %v", err)
+		}
+		return
+	}
+
+	if (statFileInput.ifNoneMatch != "") && (generationMetagenerationToETag(attrs.Generation, attrs.Metageneration) == statFileInput.ifNoneMatch) {
+		statFileOutput = &statFileOutputStruct{
+			eTag:        statFileInput.ifNoneMatch,
+			notModified: true,
+		}
 		return
 	}
 
 	statFileOutput = &statFileOutputStruct{
-		eTag:  generationMetagenerationToETag(attrs.Generation, attrs.Metageneration),
-		mTime: attrs.Updated,
-		size:  uint64(attrs.Size),
+		eTag:         generationMetagenerationToETag(attrs.Generation, attrs.Metageneration),
+		mTime:        attrs.Updated,
+		size:         uint64(attrs.Size),
+		storageClass: attrs.StorageClass,
+		metadata:     attrs.Metadata,
 	}
 
 	return
@@ -483,3 +661,10 @@ func generationMetagenerationToETag(generation, metaGeneration int64) (eTag stri
 	eTag = fmt.Sprintf(generationMetagenerationETagFormat, generation, metaGeneration)
 	return
 }
+
+// `gcsStartOffset` converts an (exclusive) S3-style StartAfter key into the
+// (inclusive) storage.Query.StartOffset that lists only keys following it.
+func gcsStartOffset(startAfter string) (startOffset string) {
+	startOffset = startAfter + "\x00"
+	return
+}
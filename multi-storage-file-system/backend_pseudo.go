@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"hash/crc32"
@@ -19,6 +20,13 @@ type pseudoContextStruct struct {
 	backendPSEUDO *backendConfigPSEUDOStruct
 }
 
+// `abortMultipartUploads` is called to abort abandoned multipart uploads. As PSEUDO backends
+// never initiate multipart uploads, errAbortMultipartUploadsNotSupported is returned.
+func (pseudoContext *pseudoContextStruct) abortMultipartUploads(ctx context.Context, abortMultipartUploadsInput *abortMultipartUploadsInputStruct) (abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct, err error) {
+	err = errAbortMultipartUploadsNotSupported
+	return
+}
+
 // `backendCommon` is called to return a pointer to the context's common `backendStruct`.
 func (backend *pseudoContextStruct) backendCommon() (backendCommon *backendStruct) {
 	backendCommon = backend.backend
@@ -70,9 +78,16 @@ func (backend *backendStruct) setupPSEUDOContext() (err error) {
 	return
 }
 
+// `copyFile` is called to perform a server-side copy of the "file" at the specified source path
+// to the specified destination path.
+func (pseudoContext *pseudoContextStruct) copyFile(ctx context.Context, copyFileInput *copyFileInputStruct) (copyFileOutput *copyFileOutputStruct, err error) {
+	err = errors.New("PSEUDO backend is read-only")
+	return
+}
+
 // `deleteFile` is called to remove a "file" at the specified path.
 // If a `subdirectory` or nothing is found at that path, an error will be returned.
-func (pseudoContext *pseudoContextStruct) deleteFile(deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
+func (pseudoContext *pseudoContextStruct) deleteFile(ctx context.Context, deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
 	var (
 		timeNotToReturnBefore = time.Now().Add(pseudoContext.backendPSEUDO.minLatencyDeleteFile)
 	)
@@ -89,7 +104,7 @@ func (pseudoContext *pseudoContextStruct) deleteFile(deleteFileInput *deleteFile
 // An empty continuationToken or empty list of directory elements (`subdirectories` and `files`)
 // indicates the `directory` has been completely enumerated. The `isTruncated` field will also
 // align with this convention.
-func (pseudoContext *pseudoContextStruct) listDirectory(listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+func (pseudoContext *pseudoContextStruct) listDirectory(ctx context.Context, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
 	var (
 		continuationTokenAsUint64 uint64
 		depth                     int
@@ -136,6 +151,19 @@ func (pseudoContext *pseudoContextStruct) listDirectory(listDirectoryInput *list
 
 	numDirFileToReturn = pseudoContext.backendPSEUDO.subdirectoriesAtDepth[depth] + pseudoContext.backendPSEUDO.filesAtDepth[depth]
 
+	if (listDirectoryInput.continuationToken == "") && (listDirectoryInput.startAfter != "") {
+		continuationTokenAsUint64 = listDirectoryStartAfterIndex(
+			listDirectoryInput,
+			pseudoContext.backendPSEUDO.subdirectoriesAtDepth[depth],
+			pseudoContext.backendPSEUDO.filesAtDepth[depth],
+			func(basename string) uint64 {
+				return pseudoPathElementsAtOrBefore(pseudoContext.backendPSEUDO.dirNameFormat, basename, pseudoContext.backendPSEUDO.subdirectoriesAtDepth[depth], pseudoContext.backendPSEUDO.dirStartingNumber)
+			},
+			func(basename string) uint64 {
+				return pseudoPathElementsAtOrBefore(pseudoContext.backendPSEUDO.fileNameFormat, basename, pseudoContext.backendPSEUDO.filesAtDepth[depth], pseudoContext.backendPSEUDO.fileStartingNumber)
+			})
+	}
+
 	if continuationTokenAsUint64 >= numDirFileToReturn {
 		listDirectoryOutput = &listDirectoryOutputStruct{
 			subdirectory:          make([]string, 0),
@@ -218,6 +246,29 @@ func (pseudoContext *pseudoContextStruct) listDirectory(listDirectoryInput *list
 	return
 }
 
+// `pseudoPathElementsAtOrBefore` returns the number of path elements in the allowable range
+// [0:pathElementNumberLimit] that are lexigraphically less than or equal to pathElement.
+func pseudoPathElementsAtOrBefore(pathElementFormat, pathElement string, pathElementNumberLimit, startingNumber uint64) (pathElementsAtOrBefore uint64) {
+	var (
+		comparison        int
+		pathElementNumber uint64
+	)
+
+	if pathElementNumberLimit == 0 {
+		pathElementsAtOrBefore = 0
+		return
+	}
+
+	pathElementNumber, comparison = checkPathElement(pathElementFormat, pathElement, pathElementNumberLimit, startingNumber)
+	if comparison < 0 {
+		pathElementsAtOrBefore = 0
+	} else {
+		pathElementsAtOrBefore = pathElementNumber + 1
+	}
+
+	return
+}
+
 // `checkPathElement` checks pathElement against the allowable range [0:pageElementNumberMax]
 // returning the pathElementNumber that is lexigraphically identical or just less than that
 // contained in pathElement. If the match is exact, comparison will be zero. If pathElement is
@@ -327,7 +378,7 @@ func (pseudoContext *pseudoContextStruct) listObjectsIndexedObjectPathPrefixPrun
 // `listObjects` is called to fetch a `page` of the objects. An empty continuationToken or
 // empty list of elements (`objects`) indicates the list of `objects` has been completely
 // enumerated. The `isTruncated` field will also align with this convention.
-func (pseudoContext *pseudoContextStruct) listObjects(listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+func (pseudoContext *pseudoContextStruct) listObjects(ctx context.Context, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
 	var (
 		checkPathElementForFile        bool
 		comparison                     int
@@ -495,11 +546,25 @@ func (pseudoContext *pseudoContextStruct) listObjects(listObjectsInput *listObje
 	return
 }
 
+// `presignFile` is called to produce a pre-signed GET URL for a `file`. As PSEUDO backends
+// have no such URLs, errPresignFileNotSupported is returned.
+func (pseudoContext *pseudoContextStruct) presignFile(ctx context.Context, presignFileInput *presignFileInputStruct) (presignFileOutput *presignFileOutputStruct, err error) {
+	err = errPresignFileNotSupported
+	return
+}
+
+// `putFile` is called to create (or replace) a "file" at the specified path with the supplied content.
+func (pseudoContext *pseudoContextStruct) putFile(ctx context.Context, putFileInput *putFileInputStruct) (putFileOutput *putFileOutputStruct, err error) {
+	err = errors.New("PSEUDO backend is read-only")
+	return
+}
+
 // `readFile` is called to read a range of a `file` at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (pseudoContext *pseudoContextStruct) readFile(readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
+func (pseudoContext *pseudoContextStruct) readFile(ctx context.Context, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
 	var (
 		fullFilePath          string
+		length                uint64
 		limit                 uint64
 		offset                uint64
 		ok                    bool
@@ -518,8 +583,18 @@ func (pseudoContext *pseudoContextStruct) readFile(readFileInput *readFileInputS
 		return
 	}
 
-	offset = readFileInput.offsetCacheLine * globals.config.cacheLineSize
-	limit = offset + globals.config.cacheLineSize
+	if readFileInput.ifNoneMatch == fmt.Sprintf(PSEUDOFileCRC32ETagFormat, crc32.ChecksumIEEE([]byte(fullFilePath))) {
+		readFileOutput = &readFileOutputStruct{
+			eTag:        readFileInput.ifNoneMatch,
+			notModified: true,
+		}
+		err = nil
+		return
+	}
+
+	offset = readFileInput.offset
+	length = readFileInput.length
+	limit = offset + length
 
 	if offset > pseudoContext.backend.backendTypeSpecifics.(*backendConfigPSEUDOStruct).fileSize {
 		offset = pseudoContext.backend.backendTypeSpecifics.(*backendConfigPSEUDOStruct).fileSize
@@ -537,9 +612,16 @@ func (pseudoContext *pseudoContextStruct) readFile(readFileInput *readFileInputS
 	return
 }
 
+// `restoreFile` is called to request that the archived content of the `file` at the specified
+// path be restored. As PSEUDO backends do not archive content, errRestoreFileNotSupported is returned.
+func (pseudoContext *pseudoContextStruct) restoreFile(ctx context.Context, restoreFileInput *restoreFileInputStruct) (restoreFileOutput *restoreFileOutputStruct, err error) {
+	err = errRestoreFileNotSupported
+	return
+}
+
 // `statDirectory` is called to verify that the specified path refers to a `directory`.
 // An error is returned if either the specified path is not a `directory` or non-existent.
-func (pseudoContext *pseudoContextStruct) statDirectory(statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
+func (pseudoContext *pseudoContextStruct) statDirectory(ctx context.Context, statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
 	var (
 		fullDirPath           string
 		ok                    bool
@@ -566,7 +648,7 @@ func (pseudoContext *pseudoContextStruct) statDirectory(statDirectoryInput *stat
 
 // `statFile` is called to fetch the `file` metadata at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (pseudoContext *pseudoContextStruct) statFile(statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
+func (pseudoContext *pseudoContextStruct) statFile(ctx context.Context, statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
 	var (
 		fullFilePath          string
 		ok                    bool
@@ -585,6 +667,15 @@ func (pseudoContext *pseudoContextStruct) statFile(statFileInput *statFileInputS
 		return
 	}
 
+	if statFileInput.ifNoneMatch == fmt.Sprintf(PSEUDOFileCRC32ETagFormat, crc32.ChecksumIEEE([]byte(fullFilePath))) {
+		statFileOutput = &statFileOutputStruct{
+			eTag:        statFileInput.ifNoneMatch,
+			notModified: true,
+		}
+		err = nil
+		return
+	}
+
 	statFileOutput = &statFileOutputStruct{
 		eTag:  fmt.Sprintf(PSEUDOFileCRC32ETagFormat, crc32.ChecksumIEEE([]byte(fullFilePath))),
 		mTime: time.Now(),
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"testing"
 )
 
@@ -97,7 +98,7 @@ func TestPSEUDOBackendDirectly(t *testing.T) {
 		maxItems:          3,
 	}
 
-	listObjectsOutput, err = listObjectsWrapper(pseudoBackend.context, listObjectsInput)
+	listObjectsOutput, err = listObjectsWrapper(context.Background(), pseudoBackend.context, listObjectsInput)
 	if err != nil {
 		t.Fatalf("listObjectsWrapper(pseudoBackend.context, listObjectsInput) failed: %v [case 1]", err)
 	}
@@ -114,7 +115,7 @@ func TestPSEUDOBackendDirectly(t *testing.T) {
 	listObjectsInput.continuationToken = listObjectsOutput.nextContinuationToken
 	listObjectsInput.maxItems = 0
 
-	listObjectsOutput, err = listObjectsWrapper(pseudoBackend.context, listObjectsInput)
+	listObjectsOutput, err = listObjectsWrapper(context.Background(), pseudoBackend.context, listObjectsInput)
 	if err != nil {
 		t.Fatalf("listObjectsWrapper(pseudoBackend.context, listObjectsInput) failed: %v [case 2]", err)
 	}
@@ -131,7 +132,7 @@ func TestPSEUDOBackendDirectly(t *testing.T) {
 	listObjectsInput.startAfter = "dir_00000001/file_00000000"
 	listObjectsInput.continuationToken = ""
 
-	listObjectsOutput, err = listObjectsWrapper(pseudoBackend.context, listObjectsInput)
+	listObjectsOutput, err = listObjectsWrapper(context.Background(), pseudoBackend.context, listObjectsInput)
 	if err != nil {
 		t.Fatalf("listObjectsWrapper(pseudoBackend.context, listObjectsInput) failed: %v [case 3]", err)
 	}
@@ -148,7 +149,7 @@ func TestPSEUDOBackendDirectly(t *testing.T) {
 	listObjectsInput.startAfter = "foo"
 	listObjectsInput.continuationToken = "bar"
 
-	_, err = listObjectsWrapper(pseudoBackend.context, listObjectsInput)
+	_, err = listObjectsWrapper(context.Background(), pseudoBackend.context, listObjectsInput)
 	if err == nil {
 		t.Fatalf("listObjectsWrapper(pseudoBackend.context, listObjectsInput) succeeded unexpectedly [case 4]")
 	}
@@ -159,7 +160,7 @@ func TestPSEUDOBackendDirectly(t *testing.T) {
 		dirPath:           "",
 	}
 
-	listDirectoryOutput, err = listDirectoryWrapper(pseudoBackend.context, listDirectoryInput)
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), pseudoBackend.context, listDirectoryInput)
 	if err != nil {
 		t.Fatalf("listDirectoryWrapper(pseudoBackend.context, listDirectoryInput) failed: %v [case 1]", err)
 	}
@@ -179,7 +180,7 @@ func TestPSEUDOBackendDirectly(t *testing.T) {
 	listDirectoryInput.continuationToken = listDirectoryOutput.nextContinuationToken
 	listDirectoryInput.maxItems = 0
 
-	listDirectoryOutput, err = listDirectoryWrapper(pseudoBackend.context, listDirectoryInput)
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), pseudoBackend.context, listDirectoryInput)
 	if err != nil {
 		t.Fatalf("listDirectoryWrapper(pseudoBackend.context, listDirectoryInput) failed: %v [case 2]", err)
 	}
@@ -196,11 +197,39 @@ func TestPSEUDOBackendDirectly(t *testing.T) {
 		t.Fatalf("listDirectoryOutput.isTruncated unexpected [case 2]")
 	}
 
+	listDirectoryInput = &listDirectoryInputStruct{
+		continuationToken: "",
+		startAfter:        "dir_00000000/",
+		maxItems:          0,
+		dirPath:           "",
+	}
+
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), pseudoBackend.context, listDirectoryInput)
+	if err != nil {
+		t.Fatalf("listDirectoryWrapper(pseudoBackend.context, listDirectoryInput) failed: %v [case 3]", err)
+	}
+	if (len(listDirectoryOutput.subdirectory) != 1) || (listDirectoryOutput.subdirectory[0] != "dir_00000001") {
+		t.Fatalf("listDirectoryOutput.subdirectory unexpected [case 3]")
+	}
+	if (len(listDirectoryOutput.file) != 1) || (listDirectoryOutput.file[0].basename != "file_00000000") {
+		t.Fatalf("listDirectoryOutput.file unexpected [case 3]")
+	}
+
+	listDirectoryInput.startAfter = "file_00000000"
+
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), pseudoBackend.context, listDirectoryInput)
+	if err != nil {
+		t.Fatalf("listDirectoryWrapper(pseudoBackend.context, listDirectoryInput) failed: %v [case 4]", err)
+	}
+	if (len(listDirectoryOutput.subdirectory) != 0) || (len(listDirectoryOutput.file) != 0) {
+		t.Fatalf("listDirectoryOutput unexpected [case 4]")
+	}
+
 	statDirectoryInput = &statDirectoryInputStruct{
 		dirPath: "dir_00000000",
 	}
 
-	_, err = statDirectoryWrapper(pseudoBackend.context, statDirectoryInput)
+	_, err = statDirectoryWrapper(context.Background(), pseudoBackend.context, statDirectoryInput)
 	if err != nil {
 		t.Fatalf("statDirectoryWrapper(pseudoBackend.context, statDirectoryInput) failed: %v", err)
 	}
@@ -210,7 +239,7 @@ func TestPSEUDOBackendDirectly(t *testing.T) {
 		ifMatch:  "",
 	}
 
-	statFileOutput, err = statFileWrapper(pseudoBackend.context, statFileInput)
+	statFileOutput, err = statFileWrapper(context.Background(), pseudoBackend.context, statFileInput)
 	if err != nil {
 		t.Fatalf("statFileWrapper(pseudoBackend.context, statFileInput) failed: %v", err)
 	}
@@ -219,16 +248,58 @@ func TestPSEUDOBackendDirectly(t *testing.T) {
 	}
 
 	readFileInput = &readFileInputStruct{
-		filePath:        "file_00000000",
-		offsetCacheLine: 0,
-		ifMatch:         "",
+		filePath: "file_00000000",
+		offset:   0,
+		length:   globals.config.cacheLineSize,
+		ifMatch:  "",
 	}
 
-	readFileOutput, err = readFileWrapper(pseudoBackend.context, readFileInput)
+	readFileOutput, err = readFileWrapper(context.Background(), pseudoBackend.context, readFileInput)
 	if err != nil {
 		t.Fatalf("readFileWrapper(pseudoBackend.context, readFileInput) failed: %v", err)
 	}
 	if !bytes.Equal(readFileOutput.buf, make([]byte, 1024)) {
 		t.Fatalf("readFileOutput.buf unexpected")
 	}
+	if readFileOutput.notModified {
+		t.Fatalf("readFileOutput.notModified unexpectedly set")
+	}
+
+	statFileInput = &statFileInputStruct{
+		filePath:    "file_00000000",
+		ifNoneMatch: statFileOutput.eTag,
+	}
+
+	statFileOutput, err = statFileWrapper(context.Background(), pseudoBackend.context, statFileInput)
+	if err != nil {
+		t.Fatalf("statFileWrapper(pseudoBackend.context, statFileInput) [ifNoneMatch matching] failed: %v", err)
+	}
+	if !statFileOutput.notModified || (statFileOutput.eTag != statFileInput.ifNoneMatch) {
+		t.Fatalf("statFileWrapper(pseudoBackend.context, statFileInput) [ifNoneMatch matching] should have returned notModified")
+	}
+
+	statFileInput.ifNoneMatch = "stale"
+
+	statFileOutput, err = statFileWrapper(context.Background(), pseudoBackend.context, statFileInput)
+	if err != nil {
+		t.Fatalf("statFileWrapper(pseudoBackend.context, statFileInput) [ifNoneMatch not matching] failed: %v", err)
+	}
+	if statFileOutput.notModified || (statFileOutput.size != 1024) {
+		t.Fatalf("statFileWrapper(pseudoBackend.context, statFileInput) [ifNoneMatch not matching] unexpected output")
+	}
+
+	readFileInput = &readFileInputStruct{
+		filePath:    "file_00000000",
+		offset:      0,
+		length:      globals.config.cacheLineSize,
+		ifNoneMatch: readFileOutput.eTag,
+	}
+
+	readFileOutput, err = readFileWrapper(context.Background(), pseudoBackend.context, readFileInput)
+	if err != nil {
+		t.Fatalf("readFileWrapper(pseudoBackend.context, readFileInput) [ifNoneMatch matching] failed: %v", err)
+	}
+	if !readFileOutput.notModified || (len(readFileOutput.buf) != 0) {
+		t.Fatalf("readFileWrapper(pseudoBackend.context, readFileInput) [ifNoneMatch matching] should have returned notModified")
+	}
 }
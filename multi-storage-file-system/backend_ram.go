@@ -2,11 +2,15 @@ package main
 
 import (
 	"cmp"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/NVIDIA/sortedmap"
@@ -30,14 +34,34 @@ type ramDirStruct struct {
 	fileMap ramDirEntryFileMapStruct
 }
 
-// `ramContextStruct` holds the RAM-specific backend details.
+// `ramContextStruct` holds the RAM-specific backend details. As backend methods
+// are invoked concurrently, each holds the embedded sync.Mutex while executing.
 type ramContextStruct struct {
+	sync.Mutex
 	backend             *backendStruct
 	rootDir             *ramDirStruct
 	curTotalObjects     uint64
 	curTotalObjectSpace uint64
 }
 
+// `ramSnapshotStruct` is the JSON-encoded content of a RAM.snapshot_file.
+type ramSnapshotStruct struct {
+	Objects []ramSnapshotObjectStruct `json:"objects"`
+}
+
+// `ramSnapshotObjectStruct` describes each object in a ramSnapshotStruct.
+type ramSnapshotObjectStruct struct {
+	Path    string `json:"path"`    // Relative to backend.prefix
+	Content []byte `json:"content"` // Encoded as base64
+}
+
+// `abortMultipartUploads` is called to abort abandoned multipart uploads. As RAM backends
+// never initiate multipart uploads, errAbortMultipartUploadsNotSupported is returned.
+func (ramContext *ramContextStruct) abortMultipartUploads(ctx context.Context, abortMultipartUploadsInput *abortMultipartUploadsInputStruct) (abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct, err error) {
+	err = errAbortMultipartUploadsNotSupported
+	return
+}
+
 // `backendCommon` is called to return a pointer to the context's common `backendStruct`.
 func (backend *ramContextStruct) backendCommon() (backendCommon *backendStruct) {
 	backendCommon = backend.backend
@@ -47,23 +71,195 @@ func (backend *ramContextStruct) backendCommon() (backendCommon *backendStruct)
 // `setupRAMContext` establishes the RAM client context. Once set up, each
 // method defined in the `backendConfigIf` interface may be invoked.
 // Note that there is no `destroyContext` counterpart.
+//
+// If RAM.snapshot_file is set and exists, the objects it contains are restored.
 func (backend *backendStruct) setupRAMContext() (err error) {
-	backend.context = &ramContextStruct{
-		backend:             backend,
-		rootDir:             newRamDir(""),
-		curTotalObjects:     0,
-		curTotalObjectSpace: 0,
+	var (
+		ramContext = &ramContextStruct{
+			backend:             backend,
+			rootDir:             newRamDir(""),
+			curTotalObjects:     0,
+			curTotalObjectSpace: 0,
+		}
+	)
+
+	err = ramContext.restoreSnapshot()
+	if err != nil {
+		return
 	}
 
+	backend.context = ramContext
+
 	backend.backendPath = "ram://"
 
 	err = nil
 	return
 }
 
+// `restoreSnapshot` populates an empty ramContext with the objects recorded in
+// RAM.snapshot_file (if set and present).
+func (ramContext *ramContextStruct) restoreSnapshot() (err error) {
+	var (
+		snapshot        ramSnapshotStruct
+		snapshotContent []byte
+		snapshotFile    = ramContext.backend.backendTypeSpecifics.(*backendConfigRAMStruct).snapshotFile
+		snapshotObject  ramSnapshotObjectStruct
+	)
+
+	if snapshotFile == "" {
+		err = nil
+		return
+	}
+
+	snapshotContent, err = os.ReadFile(snapshotFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		} else {
+			err = fmt.Errorf("[RAM] os.ReadFile(\"%s\") failed: %v", snapshotFile, err)
+		}
+		return
+	}
+
+	err = json.Unmarshal(snapshotContent, &snapshot)
+	if err != nil {
+		err = fmt.Errorf("[RAM] json.Unmarshal() of snapshot_file \"%s\" failed: %v", snapshotFile, err)
+		return
+	}
+
+	for _, snapshotObject = range snapshot.Objects {
+		err = ramContext.putFileContent(snapshotObject.Path, snapshotObject.Content)
+		if err != nil {
+			err = fmt.Errorf("[RAM] restoring \"%s\" from snapshot_file \"%s\" failed: %v", snapshotObject.Path, snapshotFile, err)
+			return
+		}
+	}
+
+	err = nil
+	return
+}
+
+// `saveSnapshot` is called, while holding ramContext's lock, following each change
+// to the objects of ramContext to record them (if RAM.snapshot_file is set). The
+// snapshot is written to a temporary file that then replaces snapshot_file so that
+// a crash never leaves a partially written snapshot_file behind.
+func (ramContext *ramContextStruct) saveSnapshot() {
+	var (
+		dirName         []string
+		err             error
+		fileName        string
+		ramDir          []*ramDirStruct
+		snapshot        ramSnapshotStruct
+		snapshotContent []byte
+		snapshotFile    = ramContext.backend.backendTypeSpecifics.(*backendConfigRAMStruct).snapshotFile
+	)
+
+	if snapshotFile == "" {
+		return
+	}
+
+	snapshot.Objects = make([]ramSnapshotObjectStruct, 0, ramContext.curTotalObjects)
+
+	dirName, fileName, ramDir = ramContext.findFullPathElements(ramContext.canonicalDirPath(""))
+	if (len(dirName)+1 == len(ramDir)) && (fileName == "") {
+		appendSnapshotObjects(ramDir[len(ramDir)-1], "", &snapshot.Objects)
+	}
+
+	snapshotContent, err = json.Marshal(&snapshot)
+	if err != nil {
+		globals.logger.Printf("[WARN] [RAM] json.Marshal() of snapshot for backends[\"%s\"] failed: %v", ramContext.backend.dirName, err)
+		return
+	}
+
+	err = os.WriteFile(snapshotFile+".tmp", snapshotContent, 0o600)
+	if err == nil {
+		err = os.Rename(snapshotFile+".tmp", snapshotFile)
+	}
+	if err != nil {
+		globals.logger.Printf("[WARN] [RAM] writing snapshot_file \"%s\" for backends[\"%s\"] failed: %v", snapshotFile, ramContext.backend.dirName, err)
+	}
+}
+
+// `appendSnapshotObjects` appends each object found in thisDir (and, recursively, its
+// subdirectories) to snapshotObjects with paths prefix'd by thisDirPrefix.
+func appendSnapshotObjects(thisDir *ramDirStruct, thisDirPrefix string, snapshotObjects *[]ramSnapshotObjectStruct) {
+	var (
+		childDir          *ramDirStruct
+		childDirBasename  string
+		childFileBasename string
+		childFileContent  []byte
+		dirMapIndex       int
+		fileMapIndex      int
+		ok                bool
+	)
+
+	for fileMapIndex = range thisDir.fileMap.Len() {
+		childFileBasename, childFileContent, ok = thisDir.fileMap.GetByIndex(fileMapIndex)
+		if !ok {
+			globals.logger.Fatalf("[FATAL] thisDir.fileMap.GetByIndex(fileMapIndex) returned !ok")
+		}
+
+		*snapshotObjects = append(*snapshotObjects, ramSnapshotObjectStruct{
+			Path:    thisDirPrefix + childFileBasename,
+			Content: childFileContent,
+		})
+	}
+
+	for dirMapIndex = range thisDir.dirMap.Len() {
+		childDirBasename, childDir, ok = thisDir.dirMap.GetByIndex(dirMapIndex)
+		if !ok {
+			globals.logger.Fatalf("[FATAL] thisDir.dirMap.GetByIndex(dirMapIndex) returned !ok")
+		}
+
+		appendSnapshotObjects(childDir, thisDirPrefix+childDirBasename+"/", snapshotObjects)
+	}
+}
+
+// `copyFile` is called to perform a server-side copy of the "file" at the specified source path
+// to the specified destination path. Any missing directories in the destination path are created.
+func (ramContext *ramContextStruct) copyFile(ctx context.Context, copyFileInput *copyFileInputStruct) (copyFileOutput *copyFileOutputStruct, err error) {
+	var (
+		dirName    []string
+		fileName   string
+		ok         bool
+		ramDir     []*ramDirStruct
+		srcContent []byte
+	)
+
+	ramContext.Lock()
+	defer ramContext.Unlock()
+
+	dirName, fileName, ramDir = ramContext.findFullPathElements(ramContext.canonicalFilePath(copyFileInput.srcFilePath))
+	if (len(dirName)+1 > len(ramDir)) || (fileName == "") {
+		// Either not all directories in the path exist... or this is actually not a reference to a file... so we know file does not exist
+		err = errors.New("file not found")
+		return
+	}
+
+	srcContent, ok = ramDir[len(ramDir)-1].fileMap.GetByKey(fileName)
+	if !ok {
+		// Containing directory existed, but file didn't
+		err = errors.New("file not found")
+		return
+	}
+
+	err = ramContext.putFileContent(copyFileInput.dstFilePath, slices.Clone(srcContent))
+	if err != nil {
+		return
+	}
+
+	ramContext.saveSnapshot()
+
+	copyFileOutput = &copyFileOutputStruct{
+		eTag: "",
+	}
+
+	return
+}
+
 // `deleteFile` is called to remove a "file" at the specified path.
 // If a `subdirectory` or nothing is found at that path, an error will be returned.
-func (ramContext *ramContextStruct) deleteFile(deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
+func (ramContext *ramContextStruct) deleteFile(ctx context.Context, deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
 	var (
 		dirName     []string
 		fileContent []byte
@@ -73,10 +269,13 @@ func (ramContext *ramContextStruct) deleteFile(deleteFileInput *deleteFileInputS
 		ramDirIndex int
 	)
 
+	ramContext.Lock()
+	defer ramContext.Unlock()
+
 	dirName, fileName, ramDir = ramContext.findFullPathElements(ramContext.canonicalFilePath(deleteFileInput.filePath))
 	if (len(dirName) + 1) > len(ramDir) {
 		// Not all directories in the path exist... so we know fileName does not exist
-		err = errors.New("file not found")
+		err = errFileNotFound
 		return
 	}
 
@@ -85,7 +284,7 @@ func (ramContext *ramContextStruct) deleteFile(deleteFileInput *deleteFileInputS
 	fileContent, ok = ramDir[ramDirIndex].fileMap.GetByKey(fileName)
 	if !ok {
 		// Didn't find fileName in leaf ramDir... so we know fileName does not exist
-		err = errors.New("file not found")
+		err = errFileNotFound
 		return
 	}
 
@@ -103,6 +302,8 @@ func (ramContext *ramContextStruct) deleteFile(deleteFileInput *deleteFileInputS
 
 	err = nil
 
+	defer ramContext.saveSnapshot()
+
 	// ...but we possibly have emptied one or more directories
 
 	for ramDirIndex > 0 {
@@ -128,7 +329,7 @@ func (ramContext *ramContextStruct) deleteFile(deleteFileInput *deleteFileInputS
 // An empty continuationToken or empty list of directory elements (`subdirectories` and `files`)
 // indicates the `directory` has been completely enumerated. The `isTruncated` field will also
 // align with this convention.
-func (ramContext *ramContextStruct) listDirectory(listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+func (ramContext *ramContextStruct) listDirectory(ctx context.Context, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
 	var (
 		continuationTokenAsUint64 uint64
 		dirName                   []string
@@ -146,6 +347,9 @@ func (ramContext *ramContextStruct) listDirectory(listDirectoryInput *listDirect
 		timeNow                   = time.Now()
 	)
 
+	ramContext.Lock()
+	defer ramContext.Unlock()
+
 	if listDirectoryInput.continuationToken == "" {
 		continuationTokenAsUint64 = 0
 	} else {
@@ -210,6 +414,31 @@ func (ramContext *ramContextStruct) listDirectory(listDirectoryInput *listDirect
 		})
 	}
 
+	// If no listDirectoryInput.continuationToken was supplied, convert any listDirectoryInput.startAfter into one
+
+	if (listDirectoryInput.continuationToken == "") && (listDirectoryInput.startAfter != "") {
+		continuationTokenAsUint64 = listDirectoryStartAfterIndex(
+			listDirectoryInput,
+			uint64(ramDirLeafDirMapLen),
+			uint64(ramDirLeafFileMapLen),
+			func(basename string) uint64 {
+				index, found := slices.BinarySearch(listDirectoryOutput.subdirectory, basename)
+				if found {
+					index++
+				}
+				return uint64(index)
+			},
+			func(basename string) uint64 {
+				index, found := slices.BinarySearchFunc(listDirectoryOutput.file, basename, func(file listDirectoryOutputFileStruct, basename string) int {
+					return cmp.Compare(file.basename, basename)
+				})
+				if found {
+					index++
+				}
+				return uint64(index)
+			})
+	}
+
 	// Now apply listDirectoryInput.continuationToken
 
 	switch {
@@ -306,7 +535,7 @@ func (ramContext *ramContextStruct) appendObjects(thisDir *ramDirStruct, thisDir
 // `listObjects` is called to fetch a `page` of the objects. An empty continuationToken or
 // empty list of elements (`objects`) indicates the list of `objects` has been completely
 // enumerated. The `isTruncated` field will also align with this convention.
-func (ramContext *ramContextStruct) listObjects(listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+func (ramContext *ramContextStruct) listObjects(ctx context.Context, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
 	var (
 		continuationTokenAsUint64 uint64
 		dirName                   []string
@@ -317,6 +546,9 @@ func (ramContext *ramContextStruct) listObjects(listObjectsInput *listObjectsInp
 		ramDirLeaf                *ramDirStruct
 	)
 
+	ramContext.Lock()
+	defer ramContext.Unlock()
+
 	if (listObjectsInput.startAfter != "") && (listObjectsInput.continuationToken != "") {
 		err = errors.New("[RAM] .startAfter and .continuationToken can't both be non-empty strings")
 		return
@@ -424,13 +656,116 @@ func (ramContext *ramContextStruct) redactSecrets(s string) string {
 	return s
 }
 
+// `presignFile` is called to produce a pre-signed GET URL for a `file`. As RAM backends
+// have no such URLs, errPresignFileNotSupported is returned.
+func (ramContext *ramContextStruct) presignFile(ctx context.Context, presignFileInput *presignFileInputStruct) (presignFileOutput *presignFileOutputStruct, err error) {
+	err = errPresignFileNotSupported
+	return
+}
+
+// `putFile` is called to create (or replace) a "file" at the specified path with the supplied content.
+// Any missing directories in the path are created.
+func (ramContext *ramContextStruct) putFile(ctx context.Context, putFileInput *putFileInputStruct) (putFileOutput *putFileOutputStruct, err error) {
+	ramContext.Lock()
+	defer ramContext.Unlock()
+
+	err = ramContext.putFileContent(putFileInput.filePath, slices.Clone(putFileInput.buf))
+	if err != nil {
+		return
+	}
+
+	ramContext.saveSnapshot()
+
+	putFileOutput = &putFileOutputStruct{
+		eTag: "",
+	}
+
+	return
+}
+
+// `putFileContent` stores fileContent at filePath, replacing any existing "file" there and
+// creating any missing directories along the way. An error is returned (and nothing is
+// changed) should doing so exceed either RAM.max_total_objects or RAM.max_total_object_space.
+func (ramContext *ramContextStruct) putFileContent(filePath string, fileContent []byte) (err error) {
+	var (
+		backendRAM          = ramContext.backend.backendTypeSpecifics.(*backendConfigRAMStruct)
+		dirName             []string
+		dirNameElement      string
+		fileName            string
+		newTotalObjectSpace uint64
+		newTotalObjects     uint64
+		oldFileContent      []byte
+		ok                  bool
+		ramDir              []*ramDirStruct
+		ramDirIndex         int
+	)
+
+	dirName, fileName, ramDir = ramContext.findFullPathElements(ramContext.canonicalFilePath(filePath))
+	if fileName == "" {
+		err = errors.New("not a file path")
+		return
+	}
+
+	newTotalObjects = ramContext.curTotalObjects + 1
+	newTotalObjectSpace = ramContext.curTotalObjectSpace + uint64(len(fileContent))
+
+	if len(dirName)+1 == len(ramDir) {
+		oldFileContent, ok = ramDir[len(ramDir)-1].fileMap.GetByKey(fileName)
+		if ok {
+			newTotalObjects--
+			newTotalObjectSpace -= uint64(len(oldFileContent))
+		}
+	}
+
+	if newTotalObjects > backendRAM.maxTotalObjects {
+		err = fmt.Errorf("[RAM] max_total_objects (%v) would be exceeded", backendRAM.maxTotalObjects)
+		return
+	}
+	if newTotalObjectSpace > backendRAM.maxTotalObjectSpace {
+		err = fmt.Errorf("[RAM] max_total_object_space (%v) would be exceeded", backendRAM.maxTotalObjectSpace)
+		return
+	}
+
+	for ramDirIndex = len(ramDir) - 1; ramDirIndex < len(dirName); ramDirIndex++ {
+		dirNameElement = dirName[ramDirIndex]
+		ramDir = append(ramDir, newRamDir(dirNameElement))
+		ok = ramDir[ramDirIndex].dirMap.Put(dirNameElement, ramDir[ramDirIndex+1])
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] ramDir[ramDirIndex].dirMap.Put(dirNameElement, ramDir[ramDirIndex+1]) returned !ok")
+		}
+	}
+
+	ramDirIndex = len(ramDir) - 1
+
+	oldFileContent, ok = ramDir[ramDirIndex].fileMap.GetByKey(fileName)
+	if ok {
+		_ = ramDir[ramDirIndex].fileMap.DeleteByKey(fileName)
+		ramContext.curTotalObjects--
+		ramContext.curTotalObjectSpace -= uint64(len(oldFileContent))
+	}
+
+	ok = ramDir[ramDirIndex].fileMap.Put(fileName, fileContent)
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] ramDir[ramDirIndex].fileMap.Put(fileName, fileContent) returned !ok")
+	}
+
+	ramContext.curTotalObjects++
+	ramContext.curTotalObjectSpace += uint64(len(fileContent))
+
+	err = nil
+	return
+}
+
 // `readFile` is called to read a range of a `file` at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (ramContext *ramContextStruct) readFile(readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
+func (ramContext *ramContextStruct) readFile(ctx context.Context, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
 	var (
 		dirName     []string
 		fileContent []byte
 		fileName    string
+		length      uint64
 		limit       uint64
 		offset      uint64
 		ok          bool
@@ -438,10 +773,13 @@ func (ramContext *ramContextStruct) readFile(readFileInput *readFileInputStruct)
 		ramDirIndex int
 	)
 
+	ramContext.Lock()
+	defer ramContext.Unlock()
+
 	dirName, fileName, ramDir = ramContext.findFullPathElements(ramContext.canonicalFilePath(readFileInput.filePath))
 	if (len(dirName) + 1) > len(ramDir) {
 		// Not all directories in the path exist... so we know fileName does not exist
-		err = errors.New("file not found")
+		err = errFileNotFound
 		return
 	}
 
@@ -450,7 +788,7 @@ func (ramContext *ramContextStruct) readFile(readFileInput *readFileInputStruct)
 	fileContent, ok = ramDir[ramDirIndex].fileMap.GetByKey(fileName)
 	if !ok {
 		// Didn't find fileName in leaf ramDir... so we know fileName does not exist
-		err = errors.New("file not found")
+		err = errFileNotFound
 		return
 	}
 
@@ -460,8 +798,9 @@ func (ramContext *ramContextStruct) readFile(readFileInput *readFileInputStruct)
 
 	// Fetch copy of bytes to return
 
-	offset = readFileInput.offsetCacheLine * globals.config.cacheLineSize
-	limit = offset + globals.config.cacheLineSize
+	offset = readFileInput.offset
+	length = readFileInput.length
+	limit = offset + length
 
 	switch {
 	case offset >= uint64(len(fileContent)):
@@ -483,15 +822,25 @@ func (ramContext *ramContextStruct) readFile(readFileInput *readFileInputStruct)
 	return
 }
 
+// `restoreFile` is called to request that the archived content of the `file` at the specified
+// path be restored. As RAM backends do not archive content, errRestoreFileNotSupported is returned.
+func (ramContext *ramContextStruct) restoreFile(ctx context.Context, restoreFileInput *restoreFileInputStruct) (restoreFileOutput *restoreFileOutputStruct, err error) {
+	err = errRestoreFileNotSupported
+	return
+}
+
 // `statDirectory` is called to verify that the specified path refers to a `directory`.
 // An error is returned if either the specified path is not a `directory` or non-existent.
-func (ramContext *ramContextStruct) statDirectory(statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
+func (ramContext *ramContextStruct) statDirectory(ctx context.Context, statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
 	var (
 		dirName  []string
 		fileName string
 		ramDir   []*ramDirStruct
 	)
 
+	ramContext.Lock()
+	defer ramContext.Unlock()
+
 	dirName, fileName, ramDir = ramContext.findFullPathElements(ramContext.canonicalDirPath(statDirectoryInput.dirPath))
 	if (len(dirName)+1 > len(ramDir)) || (fileName != "") {
 		// Either not all directories in the path exist... or this is actually a reference to a file... so we know directory does not exist
@@ -507,7 +856,7 @@ func (ramContext *ramContextStruct) statDirectory(statDirectoryInput *statDirect
 
 // `statFile` is called to fetch the `file` metadata at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (ramContext *ramContextStruct) statFile(statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
+func (ramContext *ramContextStruct) statFile(ctx context.Context, statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
 	var (
 		dirName     []string
 		fileContent []byte
@@ -516,17 +865,20 @@ func (ramContext *ramContextStruct) statFile(statFileInput *statFileInputStruct)
 		ramDir      []*ramDirStruct
 	)
 
+	ramContext.Lock()
+	defer ramContext.Unlock()
+
 	dirName, fileName, ramDir = ramContext.findFullPathElements(ramContext.canonicalFilePath(statFileInput.filePath))
 	if (len(dirName)+1 > len(ramDir)) || (fileName == "") {
 		// Either not all directories in the path exist... or this is actually not a reference to a file... so we know file does not exist
-		err = errors.New("file not found")
+		err = errFileNotFound
 		return
 	}
 
 	fileContent, ok = ramDir[len(ramDir)-1].fileMap.GetByKey(fileName)
 	if !ok {
 		// Containing directory existed, but file didn't
-		err = errors.New("file not found")
+		err = errFileNotFound
 		return
 	}
 
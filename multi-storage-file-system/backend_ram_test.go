@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"syscall"
 	"testing"
 
@@ -105,7 +108,7 @@ func TestRAMBackendDirectly(t *testing.T) {
 		maxItems:          3,
 	}
 
-	listObjectsOutput, err = listObjectsWrapper(ramBackend.context, listObjectsInput)
+	listObjectsOutput, err = listObjectsWrapper(context.Background(), ramBackend.context, listObjectsInput)
 	if err != nil {
 		t.Fatalf("listObjectsWrapper(ramBackend.context, listObjectsInput) failed: %v [case 1]", err)
 	}
@@ -122,7 +125,7 @@ func TestRAMBackendDirectly(t *testing.T) {
 	listObjectsInput.continuationToken = listObjectsOutput.nextContinuationToken
 	listObjectsInput.maxItems = 0
 
-	listObjectsOutput, err = listObjectsWrapper(ramBackend.context, listObjectsInput)
+	listObjectsOutput, err = listObjectsWrapper(context.Background(), ramBackend.context, listObjectsInput)
 	if err != nil {
 		t.Fatalf("listObjectsWrapper(ramBackend.context, listObjectsInput) failed: %v [case 2]", err)
 	}
@@ -139,7 +142,7 @@ func TestRAMBackendDirectly(t *testing.T) {
 	listObjectsInput.startAfter = "dir1/fileC"
 	listObjectsInput.continuationToken = ""
 
-	listObjectsOutput, err = listObjectsWrapper(ramBackend.context, listObjectsInput)
+	listObjectsOutput, err = listObjectsWrapper(context.Background(), ramBackend.context, listObjectsInput)
 	if err != nil {
 		t.Fatalf("listObjectsWrapper(ramBackend.context, listObjectsInput) failed: %v [case 3]", err)
 	}
@@ -156,7 +159,7 @@ func TestRAMBackendDirectly(t *testing.T) {
 	listObjectsInput.startAfter = "foo"
 	listObjectsInput.continuationToken = "bar"
 
-	_, err = listObjectsWrapper(ramBackend.context, listObjectsInput)
+	_, err = listObjectsWrapper(context.Background(), ramBackend.context, listObjectsInput)
 	if err == nil {
 		t.Fatalf("listObjectsWrapper(ramBackend.context, listObjectsInput) succeeded unexpectedly [case 4]")
 	}
@@ -167,7 +170,7 @@ func TestRAMBackendDirectly(t *testing.T) {
 		dirPath:           "",
 	}
 
-	listDirectoryOutput, err = listDirectoryWrapper(ramBackend.context, listDirectoryInput)
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), ramBackend.context, listDirectoryInput)
 	if err != nil {
 		t.Fatalf("listDirectoryWrapper(ramBackend.context, listDirectoryInput) failed: %v [case 1]", err)
 	}
@@ -187,7 +190,7 @@ func TestRAMBackendDirectly(t *testing.T) {
 	listDirectoryInput.continuationToken = listDirectoryOutput.nextContinuationToken
 	listDirectoryInput.maxItems = 0
 
-	listDirectoryOutput, err = listDirectoryWrapper(ramBackend.context, listDirectoryInput)
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), ramBackend.context, listDirectoryInput)
 	if err != nil {
 		t.Fatalf("listDirectoryWrapper(ramBackend.context, listDirectoryInput) failed: %v [case 2]", err)
 	}
@@ -204,11 +207,46 @@ func TestRAMBackendDirectly(t *testing.T) {
 		t.Fatalf("listDirectoryOutput.isTruncated unexpected [case 2]")
 	}
 
+	listDirectoryInput = &listDirectoryInputStruct{
+		continuationToken: "",
+		startAfter:        "dir1/fileC",
+		maxItems:          2,
+		dirPath:           "",
+	}
+
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), ramBackend.context, listDirectoryInput)
+	if err != nil {
+		t.Fatalf("listDirectoryWrapper(ramBackend.context, listDirectoryInput) failed: %v [case 3]", err)
+	}
+	if (len(listDirectoryOutput.subdirectory) != 1) || (listDirectoryOutput.subdirectory[0] != "dir2") {
+		t.Fatalf("listDirectoryOutput.subdirectory unexpected [case 3]")
+	}
+	if (len(listDirectoryOutput.file) != 1) || (listDirectoryOutput.file[0].basename != "fileA") {
+		t.Fatalf("listDirectoryOutput.file unexpected [case 3]")
+	}
+	if listDirectoryOutput.nextContinuationToken != "3" {
+		t.Fatalf("listDirectoryOutput.nextContinuationToken unexpected [case 3]")
+	}
+
+	listDirectoryInput.startAfter = "fileA"
+	listDirectoryInput.maxItems = 0
+
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), ramBackend.context, listDirectoryInput)
+	if err != nil {
+		t.Fatalf("listDirectoryWrapper(ramBackend.context, listDirectoryInput) failed: %v [case 4]", err)
+	}
+	if len(listDirectoryOutput.subdirectory) != 0 {
+		t.Fatalf("listDirectoryOutput.subdirectory unexpected [case 4]")
+	}
+	if (len(listDirectoryOutput.file) != 1) || (listDirectoryOutput.file[0].basename != "fileB") {
+		t.Fatalf("listDirectoryOutput.file unexpected [case 4]")
+	}
+
 	statDirectoryInput = &statDirectoryInputStruct{
 		dirPath: "dir1",
 	}
 
-	_, err = statDirectoryWrapper(ramBackend.context, statDirectoryInput)
+	_, err = statDirectoryWrapper(context.Background(), ramBackend.context, statDirectoryInput)
 	if err != nil {
 		t.Fatalf("statDirectoryWrapper(ramBackend.context, statDirectoryInput) failed: %v", err)
 	}
@@ -218,7 +256,7 @@ func TestRAMBackendDirectly(t *testing.T) {
 		ifMatch:  "",
 	}
 
-	statFileOutput, err = statFileWrapper(ramBackend.context, statFileInput)
+	statFileOutput, err = statFileWrapper(context.Background(), ramBackend.context, statFileInput)
 	if err != nil {
 		t.Fatalf("statFileWrapper(ramBackend.context, statFileInput) failed: %v [case 1]", err)
 	}
@@ -227,12 +265,13 @@ func TestRAMBackendDirectly(t *testing.T) {
 	}
 
 	readFileInput = &readFileInputStruct{
-		filePath:        "fileA",
-		offsetCacheLine: 0,
-		ifMatch:         "",
+		filePath: "fileA",
+		offset:   0,
+		length:   globals.config.cacheLineSize,
+		ifMatch:  "",
 	}
 
-	readFileOutput, err = readFileWrapper(ramBackend.context, readFileInput)
+	readFileOutput, err = readFileWrapper(context.Background(), ramBackend.context, readFileInput)
 	if err != nil {
 		t.Fatalf("readFileWrapper(ramBackend.context, readFileInput) failed: %v", err)
 	}
@@ -245,7 +284,7 @@ func TestRAMBackendDirectly(t *testing.T) {
 		ifMatch:  "",
 	}
 
-	_, err = deleteFileWrapper(ramBackend.context, deleteFileInput)
+	_, err = deleteFileWrapper(context.Background(), ramBackend.context, deleteFileInput)
 	if err != nil {
 		t.Fatalf("deleteFileWrapper(ramBackend.context, deleteFileInput) failed: %v", err)
 	}
@@ -255,8 +294,191 @@ func TestRAMBackendDirectly(t *testing.T) {
 		ifMatch:  "",
 	}
 
-	_, err = statFileWrapper(ramBackend.context, statFileInput)
+	_, err = statFileWrapper(context.Background(), ramBackend.context, statFileInput)
 	if err == nil {
 		t.Fatalf("statFileWrapper(ramBackend.context, statFileInput) succeeded unexpectedly [case 2]")
 	}
 }
+
+func TestRAMBackendKeySalting(t *testing.T) {
+	var (
+		err                 error
+		fileName            string
+		listDirectoryOutput *listDirectoryOutputStruct
+		ok                  bool
+		ramBackend          *backendStruct
+		ramContext          *ramContextStruct
+		readFileOutput      *readFileOutputStruct
+		saltedFilePath      string
+		statFileOutput      *statFileOutputStruct
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	ramBackend, ok = globals.config.backends["ram"]
+	if !ok {
+		t.Fatalf("globals.config.backends[\"ram\"] returned !ok")
+	}
+	ramContext = ramBackend.context.(*ramContextStruct)
+
+	ramBackend.keySaltWidth = 2
+	defer func() {
+		ramBackend.keySaltWidth = 0
+		ramBackend.unsaltedSeen.Store(false)
+	}()
+
+	// Until a listing has returned an unsalted basename, a miss at the salted key is not retried
+
+	_, err = statFileWrapper(context.Background(), ramBackend.context, &statFileInputStruct{filePath: "fileA"})
+	if !errors.Is(err, errFileNotFound) {
+		t.Fatalf("statFileWrapper(\"fileA\") before any listing should have failed with errFileNotFound (err: %v)", err)
+	}
+
+	saltedFilePath = ramBackend.saltFilePath("fileC")
+	if saltedFilePath != keySalt("fileC", 2)+keySaltSeparator+"fileC" {
+		t.Fatalf("saltFilePath(\"fileC\") returned unexpected \"%s\"", saltedFilePath)
+	}
+	if ramBackend.saltFilePath("dir1/fileC") != "dir1/"+saltedFilePath {
+		t.Fatalf("saltFilePath(\"dir1/fileC\") must not salt directory path elements")
+	}
+
+	ok = ramContext.rootDir.fileMap.Put(saltedFilePath, []byte("/fileC\n"))
+	if !ok {
+		t.Fatalf("ramContext.rootDir.fileMap.Put(saltedFilePath, []byte(\"/fileC\\n\")) returned !ok")
+	}
+
+	statFileOutput, err = statFileWrapper(context.Background(), ramBackend.context, &statFileInputStruct{filePath: "fileC"})
+	if err != nil {
+		t.Fatalf("statFileWrapper(\"fileC\") failed: %v", err)
+	}
+	if statFileOutput.size != uint64(len("/fileC\n")) {
+		t.Fatalf("statFileWrapper(\"fileC\") returned unexpected size %v", statFileOutput.size)
+	}
+
+	listDirectoryOutput, err = listDirectoryWrapper(context.Background(), ramBackend.context, &listDirectoryInputStruct{dirPath: ""})
+	if err != nil {
+		t.Fatalf("listDirectoryWrapper(\"\") failed: %v", err)
+	}
+
+	// Pre-existing unsalted objects (fileA & fileB) are listed unchanged alongside the desalted fileC
+
+	ok = false
+	for _, listDirectoryOutputFile := range listDirectoryOutput.file {
+		fileName = listDirectoryOutputFile.basename
+		if fileName == saltedFilePath {
+			t.Fatalf("listDirectoryWrapper(\"\") returned salted basename \"%s\"", fileName)
+		}
+		if fileName == "fileC" {
+			ok = true
+		}
+	}
+	if !ok {
+		t.Fatalf("listDirectoryWrapper(\"\") did not return desalted basename \"fileC\"")
+	}
+	if !ramBackend.unsaltedSeen.Load() {
+		t.Fatalf("listDirectoryWrapper(\"\") returning unsalted basenames should have set unsaltedSeen")
+	}
+
+	// ...after which those listed unsalted objects may be stat'd and read via their unsalted keys
+
+	_, err = statFileWrapper(context.Background(), ramBackend.context, &statFileInputStruct{filePath: "fileA"})
+	if err != nil {
+		t.Fatalf("statFileWrapper(\"fileA\") after listing failed: %v", err)
+	}
+	readFileOutput, err = readFileWrapper(context.Background(), ramBackend.context, &readFileInputStruct{filePath: "fileA", offset: 0, length: uint64(len("/fileA\n"))})
+	if err != nil {
+		t.Fatalf("readFileWrapper(\"fileA\") after listing failed: %v", err)
+	}
+	if string(readFileOutput.buf) != "/fileA\n" {
+		t.Fatalf("readFileWrapper(\"fileA\") returned unexpected content %q", readFileOutput.buf)
+	}
+
+	if ramBackend.desaltBasename("fileA") != "fileA" {
+		t.Fatalf("desaltBasename(\"fileA\") must return unsalted basenames unchanged")
+	}
+	if ramBackend.desaltBasename("zz"+keySaltSeparator+"fileC") != "zz"+keySaltSeparator+"fileC" {
+		t.Fatalf("desaltBasename() must return basenames prefixed by a mismatched salt unchanged")
+	}
+}
+
+func TestRAMBackendSnapshot(t *testing.T) {
+	var (
+		backend        *backendStruct
+		err            error
+		ramContext     *ramContextStruct
+		readFileOutput *readFileOutputStruct
+		snapshotFile   = filepath.Join(t.TempDir(), "ram.json")
+	)
+
+	backend = &backendStruct{
+		dirName: "snap",
+		prefix:  "pre/",
+		backendTypeSpecifics: &backendConfigRAMStruct{
+			maxListPageSize:     defaultRAMMaxListPageSize,
+			maxTotalObjectSpace: 16,
+			maxTotalObjects:     2,
+			snapshotFile:        snapshotFile,
+		},
+	}
+
+	err = backend.setupRAMContext()
+	if err != nil {
+		t.Fatalf("setupRAMContext() [absent snapshot_file] failed: %v", err)
+	}
+	ramContext = backend.context.(*ramContextStruct)
+
+	_, err = ramContext.putFile(context.Background(), &putFileInputStruct{filePath: "dir1/fileX", buf: []byte("0123456789")})
+	if err != nil {
+		t.Fatalf("putFile(\"dir1/fileX\") failed: %v", err)
+	}
+	_, err = ramContext.putFile(context.Background(), &putFileInputStruct{filePath: "fileY", buf: []byte("abcdef")})
+	if err != nil {
+		t.Fatalf("putFile(\"fileY\") failed: %v", err)
+	}
+
+	// Both max_total_objects and max_total_object_space are now reached
+
+	_, err = ramContext.putFile(context.Background(), &putFileInputStruct{filePath: "fileZ", buf: []byte{}})
+	if err == nil {
+		t.Fatalf("putFile(\"fileZ\") should have exceeded max_total_objects")
+	}
+	_, err = ramContext.putFile(context.Background(), &putFileInputStruct{filePath: "fileY", buf: []byte("abcdefg")})
+	if err == nil {
+		t.Fatalf("putFile(\"fileY\") should have exceeded max_total_object_space")
+	}
+	_, err = ramContext.putFile(context.Background(), &putFileInputStruct{filePath: "fileY", buf: []byte("ABCDEF")})
+	if err != nil {
+		t.Fatalf("putFile(\"fileY\") replacing an equal-sized object failed: %v", err)
+	}
+
+	_, err = ramContext.deleteFile(context.Background(), &deleteFileInputStruct{filePath: "fileY"})
+	if err != nil {
+		t.Fatalf("deleteFile(\"fileY\") failed: %v", err)
+	}
+
+	// A fresh RAM backend should restore only dir1/fileX from snapshot_file
+
+	err = backend.setupRAMContext()
+	if err != nil {
+		t.Fatalf("setupRAMContext() [present snapshot_file] failed: %v", err)
+	}
+	ramContext = backend.context.(*ramContextStruct)
+
+	if (ramContext.curTotalObjects != 1) || (ramContext.curTotalObjectSpace != 10) {
+		t.Fatalf("restored curTotalObjects (%v) and/or curTotalObjectSpace (%v) unexpected", ramContext.curTotalObjects, ramContext.curTotalObjectSpace)
+	}
+
+	readFileOutput, err = ramContext.readFile(context.Background(), &readFileInputStruct{filePath: "dir1/fileX", offset: 0, length: 10})
+	if err != nil {
+		t.Fatalf("readFile(\"dir1/fileX\") failed: %v", err)
+	}
+	if string(readFileOutput.buf) != "0123456789" {
+		t.Fatalf("readFile(\"dir1/fileX\") returned unexpected \"%s\"", string(readFileOutput.buf))
+	}
+
+	_, err = ramContext.statFile(context.Background(), &statFileInputStruct{filePath: "fileY"})
+	if err == nil {
+		t.Fatalf("statFile(\"fileY\") should have failed following its deletion")
+	}
+}
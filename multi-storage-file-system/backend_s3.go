@@ -1,29 +1,107 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
 	"crypto/tls"
+	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
+	"maps"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+const (
+	s3SSETypeAES256      = "AES256"       // SSE-S3
+	s3SSETypeKMS         = "aws:kms"      // SSE-KMS
+	s3SSETypeCustomerKey = "customer-key" // SSE-C
+)
+
+const (
+	s3RestoreTierBulk      = string(types.TierBulk)      // Cheapest and slowest (hours to days) retrieval of archived content
+	s3RestoreTierExpedited = string(types.TierExpedited) // Fastest (minutes) retrieval of archived content; not available for DEEP_ARCHIVE
+	s3RestoreTierStandard  = string(types.TierStandard)  // Retrieval of archived content within hours
 )
 
 // `s3ContextStruct` holds the S3-specific backend details.
 type s3ContextStruct struct {
-	backend  *backendStruct
-	s3Client *s3.Client
+	backend      *backendStruct
+	s3Client     *s3.Client
+	sse          s3SSEStruct
+	endpointPool *s3EndpointPoolStruct // If fewer than two endpoints are configured, == nil
+	snapshot     *s3SnapshotStruct     // If snapshot_time is not set, == nil
+}
+
+// `s3SSEStruct` holds the server-side encryption request fields derived from the backend's
+// sse_type, kms_key_id, and sse_c_key settings. Unset fields are nil (or "") so that they may
+// be assigned unconditionally to each request's input.
+type s3SSEStruct struct {
+	serverSideEncryption types.ServerSideEncryption // Applied to writes (PutObject, CopyObject, CreateMultipartUpload) if sse_type is AES256 or aws:kms
+	kmsKeyID             *string                    // Applied along with serverSideEncryption if sse_type is aws:kms
+	customerAlgorithm    *string                    // Applied to every request touching object content (including reads) if sse_type is customer-key
+	customerKey          *string                    //   "      "
+	customerKeyMD5       *string                    //   "      "
+}
+
+// `decodeSSECKey` decodes a base64-encoded SSE-C key verifying it is 256 bits long.
+func decodeSSECKey(sseCKey string) (key []byte, err error) {
+	key, err = base64.StdEncoding.DecodeString(sseCKey)
+	if err != nil {
+		err = fmt.Errorf("not base64-encoded: %v", err)
+		return
+	}
+
+	if len(key) != 32 {
+		err = fmt.Errorf("must be a 256-bit key (found %d bits)", 8*len(key))
+	}
+
+	return
+}
+
+// `newS3SSE` computes the server-side encryption request fields for backendS3. The sse_c_key
+// was validated by checkConfigFile(), so it is assumed to decode successfully.
+func newS3SSE(backendS3 *backendConfigS3Struct) (sse s3SSEStruct) {
+	var (
+		key    []byte
+		keyMD5 [md5.Size]byte
+	)
+
+	switch backendS3.sseType {
+	case s3SSETypeAES256:
+		sse.serverSideEncryption = types.ServerSideEncryptionAes256
+	case s3SSETypeKMS:
+		sse.serverSideEncryption = types.ServerSideEncryptionAwsKms
+		if backendS3.kmsKeyID != "" {
+			sse.kmsKeyID = aws.String(backendS3.kmsKeyID)
+		}
+	case s3SSETypeCustomerKey:
+		key, _ = decodeSSECKey(backendS3.sseCKey)
+		keyMD5 = md5.Sum(key)
+		sse.customerAlgorithm = aws.String(string(types.ServerSideEncryptionAes256))
+		sse.customerKey = aws.String(backendS3.sseCKey)
+		sse.customerKeyMD5 = aws.String(base64.StdEncoding.EncodeToString(keyMD5[:]))
+	}
+
+	return
 }
 
 // `backendCommon` is called to return a pointer to the context's common `backendStruct`.
@@ -37,11 +115,18 @@ func (backend *s3ContextStruct) backendCommon() (backendCommon *backendStruct) {
 // Note that there is no `destroyContext` counterpart.
 func (backend *backendStruct) setupS3Context() (err error) {
 	var (
+		apiOptions        []func(*middleware.Stack) error
 		backendPathParsed *url.URL
 		backendS3         = backend.backendTypeSpecifics.(*backendConfigS3Struct)
 		configOptions     []func(*config.LoadOptions) error
+		endpointPool      *s3EndpointPoolStruct
+		proxyFunc         func(*http.Request) (*url.URL, error)
+		requestHeaderName string
 		s3Config          aws.Config
+		s3Context         *s3ContextStruct
 		s3Endpoint        string
+		tlsConfig         *tls.Config
+		transportOptions  func(*http.Transport)
 	)
 
 	configOptions = []func(*config.LoadOptions) error{}
@@ -71,14 +156,55 @@ func (backend *backendStruct) setupS3Context() (err error) {
 			}}))
 	}
 
-	if backendS3.skipTLSCertificateVerify {
-		configOptions = append(configOptions, config.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(func(t *http.Transport) {
-			if t.TLSClientConfig == nil {
-				t.TLSClientConfig = &tls.Config{}
-			}
-			t.TLSClientConfig.InsecureSkipVerify = true
-			t.TLSClientConfig.MinVersion = tls.VersionTLS12
-		})))
+	// Both requester_pays and request_headers are applied to every request (not just reads)
+	// as a requester-pays bucket rejects any request lacking x-amz-request-payer. As these
+	// headers are set during the build step, they are included in the request signature.
+
+	if backendS3.requesterPays {
+		apiOptions = append(apiOptions, smithyhttp.SetHeaderValue("X-Amz-Request-Payer", string(types.RequestPayerRequester)))
+	}
+
+	for _, requestHeaderName = range slices.Sorted(maps.Keys(backendS3.requestHeaders)) {
+		apiOptions = append(apiOptions, smithyhttp.SetHeaderValue(requestHeaderName, backendS3.requestHeaders[requestHeaderName]))
+	}
+
+	tlsConfig, err = newBackendTLSConfig(backendS3.skipTLSCertificateVerify, backendS3.caBundleFile, backendS3.clientCertFile, backendS3.clientKeyFile)
+	if err != nil {
+		err = fmt.Errorf("[S3] %v", err)
+		return
+	}
+
+	proxyFunc = newBackendProxyFunc(backend.proxyURL, backend.noProxy)
+
+	transportOptions = func(t *http.Transport) {
+		if tlsConfig != nil {
+			t.TLSClientConfig = tlsConfig
+		}
+		if proxyFunc != nil {
+			t.Proxy = proxyFunc
+		}
+		if backendS3.maxIdleConns != 0 {
+			t.MaxIdleConns = int(backendS3.maxIdleConns)
+		}
+		if backendS3.maxIdleConnsPerHost != 0 {
+			t.MaxIdleConnsPerHost = int(backendS3.maxIdleConnsPerHost)
+		}
+		if backendS3.maxConnsPerHost != 0 {
+			t.MaxConnsPerHost = int(backendS3.maxConnsPerHost)
+		}
+	}
+
+	if (tlsConfig != nil) || (proxyFunc != nil) || (backendS3.maxIdleConns != 0) || (backendS3.maxIdleConnsPerHost != 0) || (backendS3.maxConnsPerHost != 0) {
+		configOptions = append(configOptions, config.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(transportOptions)))
+	}
+
+	endpointPool = newS3EndpointPool(backendS3, awshttp.NewBuildableClient().WithTransportOptions(transportOptions))
+	if endpointPool != nil {
+		apiOptions = append(apiOptions, endpointPool.apiOption(backend.dirName))
+	}
+
+	if len(apiOptions) > 0 {
+		configOptions = append(configOptions, config.WithAPIOptions(apiOptions))
 	}
 
 	configOptions = append(configOptions, config.WithRetryer(func() aws.Retryer {
@@ -109,7 +235,16 @@ func (backend *backendStruct) setupS3Context() (err error) {
 		}
 	}
 
-	if backendS3.virtualHostedStyleRequest {
+	if backendS3.discoverBuckets {
+		// Each discovered bucket (always addressed path-style) appears as a subdirectory
+
+		s3Endpoint = backendPathParsed.Scheme + "://" + backendPathParsed.Host + backendPathParsed.Path
+		if backendS3.discoveredBuckets == nil {
+			backendS3.discoveredBuckets = &s3DiscoveredBucketsStruct{}
+		} else {
+			backendS3.discoveredBuckets.invalidate()
+		}
+	} else if backendS3.virtualHostedStyleRequest {
 		backendPathParsed.Host = backend.bucketContainerName + "." + backendPathParsed.Host
 		s3Endpoint = backendPathParsed.Scheme + "://" + backendPathParsed.Host + backendPathParsed.Path
 	} else {
@@ -117,20 +252,29 @@ func (backend *backendStruct) setupS3Context() (err error) {
 		backendPathParsed.Path += "/" + backend.bucketContainerName
 	}
 
-	if backend.prefix == "" {
+	if (backend.prefix == "") || backendS3.discoverBuckets {
 		backend.backendPath = backendPathParsed.String() + "/"
 	} else {
 		backendPathParsed.Path += "/" + backend.prefix
 		backend.backendPath = backendPathParsed.String()
 	}
 
-	backend.context = &s3ContextStruct{
+	s3Context = &s3ContextStruct{
 		backend: backend,
+		sse:     newS3SSE(backendS3),
 		s3Client: s3.NewFromConfig(s3Config, func(o *s3.Options) {
 			o.BaseEndpoint = aws.String(s3Endpoint)
 			o.UsePathStyle = !backendS3.virtualHostedStyleRequest
 			o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
 		}),
+		endpointPool: endpointPool,
+		snapshot:     newS3Snapshot(backendS3.snapshotTime),
+	}
+
+	backend.context = s3Context
+
+	if (endpointPool != nil) && (endpointPool.healthCheckInterval != 0) {
+		go endpointPool.healthCheckLoop(backend, s3Context)
 	}
 
 	return
@@ -156,6 +300,8 @@ func (backend *backendStruct) IsErrorRetryable(err error) bool {
 	httpErrStatusCode = httpErr.HTTPStatusCode()
 
 	switch {
+	case httpErrStatusCode == http.StatusNotModified:
+		return false
 	case httpErrStatusCode < 400:
 		return true
 	case httpErrStatusCode == http.StatusTooManyRequests:
@@ -167,6 +313,26 @@ func (backend *backendStruct) IsErrorRetryable(err error) bool {
 	}
 }
 
+// `s3IsNotModified` returns whether or not err is the result of a conditional
+// (i.e. If-None-Match) request having been answered with a 304 (Not Modified).
+func s3IsNotModified(err error) bool {
+	var (
+		httpErr *awshttp.ResponseError
+	)
+
+	return errors.As(err, &httpErr) && (httpErr.HTTPStatusCode() == http.StatusNotModified)
+}
+
+// `s3IsNotFound` reports whether err is the HTTP 404 (Not Found) response to a request
+// (e.g. HeadObject) referencing a non-existent object.
+func s3IsNotFound(err error) bool {
+	var (
+		httpErr *awshttp.ResponseError
+	)
+
+	return errors.As(err, &httpErr) && (httpErr.HTTPStatusCode() == http.StatusNotFound)
+}
+
 // `MaxAttempts` is an aws.Retryer callback that returns the maximum number of attempts
 // (including the initial attempt) to be made for a retryable request.
 // See https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/aws/retry#Standard.MaxAttempts.
@@ -177,18 +343,78 @@ func (backend *backendStruct) MaxAttempts() int {
 // `RetryDelay` is an aws.Retryer callback that returns the delay before a previously
 // failed request should be retried.
 // See https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/aws/retry#Standard.RetryDelay.
-func (backend *backendStruct) RetryDelay(attempt int, _ error) (time.Duration, error) {
-	if (attempt < 1) || (attempt > len(backend.backendTypeSpecifics.(*backendConfigS3Struct).retryDelay)) {
-		return time.Duration(0), fmt.Errorf("unexpected attempt: %v (should have been in [1:%v])", attempt, len(backend.backendTypeSpecifics.(*backendConfigS3Struct).retryDelay))
+//
+// The delay is chosen uniformly from [0, retryDelay[attempt-1]] ("full jitter") so that
+// the retries of many requests (and mounts) failing together do not remain synchronized.
+// Should the response carry a Retry-After header, the delay is at least that long (but
+// no longer than retry_max_delay).
+func (backend *backendStruct) RetryDelay(attempt int, opErr error) (retryDelay time.Duration, err error) {
+	var (
+		backendS3  = backend.backendTypeSpecifics.(*backendConfigS3Struct)
+		ok         bool
+		retryAfter time.Duration
+	)
+
+	if (attempt < 1) || (attempt > len(backendS3.retryDelay)) {
+		retryDelay = time.Duration(0)
+		err = fmt.Errorf("unexpected attempt: %v (should have been in [1:%v])", attempt, len(backendS3.retryDelay))
+		return
+	}
+
+	retryDelay = time.Duration(rand.Int64N(int64(backendS3.retryDelay[attempt-1]) + 1))
+
+	retryAfter, ok = s3RetryAfter(opErr, time.Now())
+	if ok {
+		retryDelay = max(retryDelay, min(retryAfter, backendS3.retryMaxDelay))
 	}
 
-	return backend.backendTypeSpecifics.(*backendConfigS3Struct).retryDelay[attempt-1], nil
+	err = nil
+	return
+}
+
+// `s3RetryAfter` returns the delay requested by the Retry-After header (in either its
+// delay-seconds or HTTP-date form) of the response (if any) that produced err.
+func s3RetryAfter(err error, now time.Time) (retryAfter time.Duration, ok bool) {
+	var (
+		httpErr          *awshttp.ResponseError
+		retryAfterDate   time.Time
+		retryAfterHeader string
+		retryAfterSecs   uint64
+	)
+
+	if !errors.As(err, &httpErr) || (httpErr.ResponseError == nil) || (httpErr.Response == nil) || (httpErr.Response.Response == nil) {
+		ok = false
+		return
+	}
+
+	retryAfterHeader = strings.TrimSpace(httpErr.Response.Header.Get("Retry-After"))
+	if retryAfterHeader == "" {
+		ok = false
+		return
+	}
+
+	retryAfterSecs, err = strconv.ParseUint(retryAfterHeader, 10, 32)
+	if err == nil {
+		retryAfter = time.Duration(retryAfterSecs) * time.Second
+		ok = true
+		return
+	}
+
+	retryAfterDate, err = http.ParseTime(retryAfterHeader)
+	if err == nil {
+		retryAfter = max(retryAfterDate.Sub(now), time.Duration(0))
+		ok = true
+		return
+	}
+
+	ok = false
+	return
 }
 
 // `GetRetryToken` is an aws.Retryer callback that returns a func used to additionally
 // apply a retry `cost` for performing a retry of a previously failed request.
 // See https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/aws/retry#Standard.GetRetryToken.
-func (backend *backendStruct) GetRetryToken(ctx context.Context, opErr error) (releaseToken func(error) error, err error) {
+func (backend *backendStruct) GetRetryToken(_ context.Context, _ error) (releaseToken func(error) error, err error) {
 	return func(error) error {
 		return nil
 	}, nil
@@ -208,30 +434,382 @@ func (backend *backendStruct) GetInitialToken() (releaseToken func(error) error)
 // `GetAttemptToken` is an aws.Retryer callback that returns a func used to additionally
 // apply a `cost` for performing a retry of a previously failed request.
 // See https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/aws/retry#AdaptiveMode.GetAttemptToken.
-func (backend *backendStruct) GetAttemptToken(context.Context) (func(error) error, error) {
+//
+// Each attempt is counted in ctx's retryHistoryStruct.
+func (backend *backendStruct) GetAttemptToken(ctx context.Context) (func(error) error, error) {
+	retryHistoryFromContext(ctx).recordAttempt()
+
 	return func(error) error {
 		return nil
 	}, nil
 }
 
+// `s3RetryerStruct` is the aws.Retryer applied to each S3 request made on behalf of a ctx.
+// It adds, to the backendStruct callbacks, the recording of each failed attempt (and the
+// delay preceding its retry) in ctx's retryHistoryStruct. Note that, as RetryDelay() is not
+// passed the ctx, this could not be done by the client-wide backendStruct aws.Retryer.
+type s3RetryerStruct struct {
+	*backendStruct
+	retryHistory *retryHistoryStruct // If nil, nothing is recorded
+}
+
+// `RetryDelay` is an aws.Retryer callback that returns (and records) the delay before
+// a previously failed request should be retried.
+func (s3Retryer *s3RetryerStruct) RetryDelay(attempt int, opErr error) (retryDelay time.Duration, err error) {
+	retryDelay, err = s3Retryer.backendStruct.RetryDelay(attempt, opErr)
+	if err == nil {
+		s3Retryer.retryHistory.recordFailure(opErr, retryDelay)
+	}
+
+	return
+}
+
+// `retryOptions` returns the s3.Options modifier to apply to each request made on behalf of ctx.
+func (s3Context *s3ContextStruct) retryOptions(ctx context.Context) func(*s3.Options) {
+	return func(o *s3.Options) {
+		o.Retryer = &s3RetryerStruct{
+			backendStruct: s3Context.backend,
+			retryHistory:  retryHistoryFromContext(ctx),
+		}
+	}
+}
+
+// `abortMultipartUploads` is called to abort each multipart upload in progress beneath the
+// backend's prefix (in each discovered bucket if discover_buckets is set) that was initiated
+// before abortMultipartUploadsInput.initiatedBefore. An upload found to have been completed or
+// aborted in the meantime is simply skipped.
+func (s3Context *s3ContextStruct) abortMultipartUploads(ctx context.Context, abortMultipartUploadsInput *abortMultipartUploadsInputStruct) (abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct, err error) {
+	var (
+		backend                      = s3Context.backend
+		bucket                       string
+		buckets                      []string
+		discoverBuckets              = backend.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets
+		filePath                     string
+		keyMarker                    *string
+		s3ListMultipartUploadsOutput *s3.ListMultipartUploadsOutput
+		s3MultipartUpload            types.MultipartUpload
+		uploadIDMarker               *string
+	)
+
+	if s3Context.snapshot != nil {
+		err = errS3SnapshotReadOnly
+		return
+	}
+
+	if discoverBuckets {
+		buckets, err = s3Context.buckets(ctx)
+		if err != nil {
+			return
+		}
+	} else {
+		buckets = []string{backend.bucketContainerName}
+	}
+
+	abortMultipartUploadsOutput = &abortMultipartUploadsOutputStruct{
+		aborted: make([]abortedMultipartUploadStruct, 0),
+	}
+
+	for _, bucket = range buckets {
+		keyMarker, uploadIDMarker = nil, nil
+
+		for {
+			s3ListMultipartUploadsOutput, err = s3Context.s3Client.ListMultipartUploads(ctx, &s3.ListMultipartUploadsInput{
+				Bucket:         aws.String(bucket),
+				KeyMarker:      keyMarker,
+				Prefix:         aws.String(backend.prefix),
+				UploadIdMarker: uploadIDMarker,
+			}, s3Context.retryOptions(ctx))
+			if err != nil {
+				err = fmt.Errorf("[S3] abortMultipartUploads failed: %v", err)
+				return
+			}
+
+			for _, s3MultipartUpload = range s3ListMultipartUploadsOutput.Uploads {
+				if (s3MultipartUpload.Initiated == nil) || !s3MultipartUpload.Initiated.Before(abortMultipartUploadsInput.initiatedBefore) {
+					continue
+				}
+
+				_, err = s3Context.s3Client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+					Bucket:   aws.String(bucket),
+					Key:      s3MultipartUpload.Key,
+					UploadId: s3MultipartUpload.UploadId,
+				}, s3Context.retryOptions(ctx))
+				if err != nil {
+					if s3IsNotFound(err) {
+						err = nil
+						continue
+					}
+					err = fmt.Errorf("[S3] abortMultipartUploads failed: %v", err)
+					return
+				}
+
+				filePath = strings.TrimPrefix(aws.ToString(s3MultipartUpload.Key), backend.prefix)
+				if discoverBuckets {
+					filePath = bucket + "/" + filePath
+				}
+
+				abortMultipartUploadsOutput.aborted = append(abortMultipartUploadsOutput.aborted, abortedMultipartUploadStruct{
+					filePath:  filePath,
+					initiated: *s3MultipartUpload.Initiated,
+				})
+			}
+
+			if !aws.ToBool(s3ListMultipartUploadsOutput.IsTruncated) {
+				break
+			}
+
+			keyMarker, uploadIDMarker = s3ListMultipartUploadsOutput.NextKeyMarker, s3ListMultipartUploadsOutput.NextUploadIdMarker
+		}
+	}
+
+	return
+}
+
+// `s3CopyObjectMaxSize` is the largest object S3 will copy via a single CopyObject request.
+// Larger objects are copied via a multipart upload of s3CopyPartSize parts.
+const (
+	s3CopyObjectMaxSize = uint64(5 * 1024 * 1024 * 1024)
+	s3CopyPartSize      = uint64(512 * 1024 * 1024)
+)
+
+// `sameService` reports whether otherS3Context addresses the same S3 service (i.e. region and
+// endpoint(s)) as s3Context such that a CopyObject sent via s3Context may name its buckets.
+func (s3Context *s3ContextStruct) sameService(otherS3Context *s3ContextStruct) bool {
+	var (
+		backendS3      = s3Context.backend.backendTypeSpecifics.(*backendConfigS3Struct)
+		otherBackendS3 = otherS3Context.backend.backendTypeSpecifics.(*backendConfigS3Struct)
+	)
+
+	return (backendS3.region == otherBackendS3.region) && (backendS3.endpoint == otherBackendS3.endpoint) && slices.Equal(backendS3.endpoints, otherBackendS3.endpoints)
+}
+
+// `copyFile` is called to perform a server-side copy of the "file" at the specified source path
+// to the specified destination path. Objects larger than s3CopyObjectMaxSize are copied via
+// a multipart upload (given the source's user metadata and Content-Type, as CopyObject would
+// have preserved) with each part produced by an UploadPartCopy request. If srcContext is
+// set, the source is instead that other S3 backend's object (which must be readable using
+// this backend's credentials and endpoint).
+func (s3Context *s3ContextStruct) copyFile(ctx context.Context, copyFileInput *copyFileInputStruct) (copyFileOutput *copyFileOutputStruct, err error) {
+	var (
+		bucket                          string
+		completedParts                  []types.CompletedPart
+		copySource                      string
+		eTag                            *string
+		fullDstFilePath                 string
+		fullSrcFilePath                 string
+		partNumber                      int32
+		rangeBegin                      uint64
+		rangeEnd                        uint64
+		s3CompleteMultipartUploadOutput *s3.CompleteMultipartUploadOutput
+		s3CopyObjectInput               *s3.CopyObjectInput
+		s3CopyObjectOutput              *s3.CopyObjectOutput
+		s3CreateMultipartUploadInput    *s3.CreateMultipartUploadInput
+		s3CreateMultipartUploadOutput   *s3.CreateMultipartUploadOutput
+		s3UploadPartCopyInput           *s3.UploadPartCopyInput
+		s3UploadPartCopyOutput          *s3.UploadPartCopyOutput
+		ok                              bool
+		srcBucket                       string
+		srcS3Context                    = s3Context
+		srcVersionID                    *string
+		statFileOutput                  *statFileOutputStruct
+	)
+
+	if copyFileInput.srcContext != nil {
+		srcS3Context, ok = unwrapBackendContext(copyFileInput.srcContext).(*s3ContextStruct)
+		if !ok {
+			err = errCopyFileNotSupported
+			return
+		}
+	}
+
+	if s3Context.snapshot != nil {
+		err = errS3SnapshotReadOnly
+		return
+	}
+
+	err = srcS3Context.rejectVersionsPath(copyFileInput.srcFilePath)
+	if err != nil {
+		return
+	}
+	err = s3Context.rejectVersionsPath(copyFileInput.dstFilePath)
+	if err != nil {
+		return
+	}
+
+	srcBucket, fullSrcFilePath, srcVersionID, err = srcS3Context.resolveFile(ctx, copyFileInput.srcFilePath)
+	if err != nil {
+		return
+	}
+
+	bucket, fullDstFilePath, err = s3Context.bucketAndKey(ctx, copyFileInput.dstFilePath)
+	if err != nil {
+		return
+	}
+
+	copySource = url.PathEscape(srcBucket) + "/" + url.PathEscape(fullSrcFilePath)
+	if srcVersionID != nil {
+		copySource += "?versionId=" + url.QueryEscape(*srcVersionID)
+	}
+
+	statFileOutput, err = srcS3Context.statFile(ctx, &statFileInputStruct{
+		filePath: copyFileInput.srcFilePath,
+		ifMatch:  copyFileInput.ifMatch,
+	})
+	if err != nil {
+		return
+	}
+
+	if statFileOutput.size <= s3CopyObjectMaxSize {
+		s3CopyObjectInput = &s3.CopyObjectInput{
+			Bucket:                         aws.String(bucket),
+			CopySource:                     aws.String(copySource),
+			CopySourceSSECustomerAlgorithm: srcS3Context.sse.customerAlgorithm,
+			CopySourceSSECustomerKey:       srcS3Context.sse.customerKey,
+			CopySourceSSECustomerKeyMD5:    srcS3Context.sse.customerKeyMD5,
+			Key:                            aws.String(fullDstFilePath),
+			SSECustomerAlgorithm:           s3Context.sse.customerAlgorithm,
+			SSECustomerKey:                 s3Context.sse.customerKey,
+			SSECustomerKeyMD5:              s3Context.sse.customerKeyMD5,
+			SSEKMSKeyId:                    s3Context.sse.kmsKeyID,
+			ServerSideEncryption:           s3Context.sse.serverSideEncryption,
+		}
+		if copyFileInput.ifMatch != "" {
+			s3CopyObjectInput.CopySourceIfMatch = aws.String(copyFileInput.ifMatch)
+		}
+
+		s3CopyObjectOutput, err = s3Context.s3Client.CopyObject(ctx, s3CopyObjectInput, s3Context.retryOptions(ctx))
+		if err != nil {
+			return
+		}
+
+		if s3CopyObjectOutput.CopyObjectResult != nil {
+			eTag = s3CopyObjectOutput.CopyObjectResult.ETag
+		}
+	} else {
+		// Unlike CopyObject, a multipart upload does not inherit the source's metadata... so pass it along
+
+		s3CreateMultipartUploadInput = &s3.CreateMultipartUploadInput{
+			Bucket:               aws.String(bucket),
+			Key:                  aws.String(fullDstFilePath),
+			Metadata:             statFileOutput.metadata,
+			SSECustomerAlgorithm: s3Context.sse.customerAlgorithm,
+			SSECustomerKey:       s3Context.sse.customerKey,
+			SSECustomerKeyMD5:    s3Context.sse.customerKeyMD5,
+			SSEKMSKeyId:          s3Context.sse.kmsKeyID,
+			ServerSideEncryption: s3Context.sse.serverSideEncryption,
+		}
+		if statFileOutput.contentType != "" {
+			s3CreateMultipartUploadInput.ContentType = aws.String(statFileOutput.contentType)
+		}
+
+		s3CreateMultipartUploadOutput, err = s3Context.s3Client.CreateMultipartUpload(ctx, s3CreateMultipartUploadInput, s3Context.retryOptions(ctx))
+		if err != nil {
+			return
+		}
+
+		for rangeBegin = 0; rangeBegin < statFileOutput.size; rangeBegin += s3CopyPartSize {
+			rangeEnd = min(rangeBegin+s3CopyPartSize, statFileOutput.size) - 1
+			partNumber++
+
+			s3UploadPartCopyInput = &s3.UploadPartCopyInput{
+				Bucket:                         aws.String(bucket),
+				CopySource:                     aws.String(copySource),
+				CopySourceRange:                aws.String(fmt.Sprintf("bytes=%d-%d", rangeBegin, rangeEnd)),
+				CopySourceSSECustomerAlgorithm: srcS3Context.sse.customerAlgorithm,
+				CopySourceSSECustomerKey:       srcS3Context.sse.customerKey,
+				CopySourceSSECustomerKeyMD5:    srcS3Context.sse.customerKeyMD5,
+				Key:                            aws.String(fullDstFilePath),
+				PartNumber:                     aws.Int32(partNumber),
+				SSECustomerAlgorithm:           s3Context.sse.customerAlgorithm,
+				SSECustomerKey:                 s3Context.sse.customerKey,
+				SSECustomerKeyMD5:              s3Context.sse.customerKeyMD5,
+				UploadId:                       s3CreateMultipartUploadOutput.UploadId,
+			}
+			if copyFileInput.ifMatch != "" {
+				s3UploadPartCopyInput.CopySourceIfMatch = aws.String(copyFileInput.ifMatch)
+			}
+
+			s3UploadPartCopyOutput, err = s3Context.s3Client.UploadPartCopy(ctx, s3UploadPartCopyInput, s3Context.retryOptions(ctx))
+			if err != nil {
+				_, _ = s3Context.s3Client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+					Bucket:   aws.String(bucket),
+					Key:      aws.String(fullDstFilePath),
+					UploadId: s3CreateMultipartUploadOutput.UploadId,
+				}, s3Context.retryOptions(ctx))
+				return
+			}
+
+			completedParts = append(completedParts, types.CompletedPart{
+				ETag:       s3UploadPartCopyOutput.CopyPartResult.ETag,
+				PartNumber: aws.Int32(partNumber),
+			})
+		}
+
+		s3CompleteMultipartUploadOutput, err = s3Context.s3Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:               aws.String(bucket),
+			Key:                  aws.String(fullDstFilePath),
+			MultipartUpload:      &types.CompletedMultipartUpload{Parts: completedParts},
+			SSECustomerAlgorithm: s3Context.sse.customerAlgorithm,
+			SSECustomerKey:       s3Context.sse.customerKey,
+			SSECustomerKeyMD5:    s3Context.sse.customerKeyMD5,
+			UploadId:             s3CreateMultipartUploadOutput.UploadId,
+		}, s3Context.retryOptions(ctx))
+		if err != nil {
+			_, _ = s3Context.s3Client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(bucket),
+				Key:      aws.String(fullDstFilePath),
+				UploadId: s3CreateMultipartUploadOutput.UploadId,
+			}, s3Context.retryOptions(ctx))
+			return
+		}
+
+		eTag = s3CompleteMultipartUploadOutput.ETag
+	}
+
+	copyFileOutput = &copyFileOutputStruct{}
+	if eTag != nil {
+		copyFileOutput.eTag = strings.TrimLeft(strings.TrimRight(*eTag, "\""), "\"")
+	}
+
+	return
+}
+
 // `deleteFile` is called to remove a "file" at the specified path.
 // If a `subdirectory` or nothing is found at that path, an error will be returned.
-func (s3Context *s3ContextStruct) deleteFile(deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
+func (s3Context *s3ContextStruct) deleteFile(ctx context.Context, deleteFileInput *deleteFileInputStruct) (deleteFileOutput *deleteFileOutputStruct, err error) {
 	var (
-		backend             = s3Context.backend
-		fullFilePath        = backend.prefix + deleteFileInput.filePath
+		bucket              string
+		fullFilePath        string
 		s3DeleteObjectInput *s3.DeleteObjectInput
 	)
 
+	if s3Context.snapshot != nil {
+		err = errS3SnapshotReadOnly
+		return
+	}
+
+	err = s3Context.rejectVersionsPath(deleteFileInput.filePath)
+	if err != nil {
+		return
+	}
+
+	bucket, fullFilePath, err = s3Context.bucketAndKey(ctx, deleteFileInput.filePath)
+	if err != nil {
+		return
+	}
+
 	s3DeleteObjectInput = &s3.DeleteObjectInput{
-		Bucket: aws.String(backend.bucketContainerName),
+		Bucket: aws.String(bucket),
 		Key:    aws.String(fullFilePath),
 	}
 	if deleteFileInput.ifMatch != "" {
 		s3DeleteObjectInput.IfMatch = aws.String(deleteFileInput.ifMatch)
 	}
 
-	_, err = s3Context.s3Client.DeleteObject(context.Background(), s3DeleteObjectInput)
+	_, err = s3Context.s3Client.DeleteObject(ctx, s3DeleteObjectInput, s3Context.retryOptions(ctx))
+	if (err != nil) && s3IsNotFound(err) {
+		err = fmt.Errorf("%w: %w", errFileNotFound, err)
+	}
 
 	return
 }
@@ -240,32 +818,62 @@ func (s3Context *s3ContextStruct) deleteFile(deleteFileInput *deleteFileInputStr
 // An empty continuationToken or empty list of directory elements (`subdirectories` and `files`)
 // indicates the `directory` has been completely enumerated. The `isTruncated` field will also
 // align with this convention.
-func (s3Context *s3ContextStruct) listDirectory(listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+func (s3Context *s3ContextStruct) listDirectory(ctx context.Context, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
 	var (
-		backend               = s3Context.backend
-		fullDirPath           = backend.prefix + listDirectoryInput.dirPath
+		bucket                string
+		fullDirPath           string
+		isVersionsDir         bool
 		s3CommonPrefix        types.CommonPrefix
 		s3ListObjectsV2Input  *s3.ListObjectsV2Input
 		s3ListObjectsV2Output *s3.ListObjectsV2Output
 		s3Object              types.Object
+		startAfter            string
+		versionsFilePath      string
 	)
 
+	if (listDirectoryInput.dirPath == "") && s3Context.backend.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets {
+		listDirectoryOutput, err = s3Context.listBuckets(ctx, listDirectoryInput)
+		return
+	}
+
+	versionsFilePath, _, isVersionsDir = s3Context.versionsPath(listDirectoryInput.dirPath)
+	if isVersionsDir {
+		listDirectoryOutput, err = s3Context.listVersionsDirectory(ctx, listDirectoryInput.dirPath, versionsFilePath, listDirectoryInput)
+		return
+	}
+
+	bucket, fullDirPath, err = s3Context.bucketAndKey(ctx, listDirectoryInput.dirPath)
+	if err != nil {
+		return
+	}
+
+	if (listDirectoryInput.continuationToken == "") && (listDirectoryInput.startAfter != "") {
+		_, startAfter, err = s3Context.bucketAndKey(ctx, listDirectoryInput.startAfter)
+		if err != nil {
+			return
+		}
+	}
+
+	if s3Context.snapshot != nil {
+		listDirectoryOutput, err = s3Context.listSnapshotDirectory(ctx, bucket, fullDirPath, startAfter, listDirectoryInput)
+		return
+	}
+
 	s3ListObjectsV2Input = &s3.ListObjectsV2Input{
-		Bucket:    aws.String(backend.bucketContainerName),
+		Bucket:    aws.String(bucket),
 		Prefix:    aws.String(fullDirPath),
 		Delimiter: aws.String("/"),
 	}
 	if listDirectoryInput.continuationToken != "" {
 		s3ListObjectsV2Input.ContinuationToken = aws.String(listDirectoryInput.continuationToken)
-	}
-	if listDirectoryInput.startAfter != "" {
-		s3ListObjectsV2Input.StartAfter = aws.String(backend.prefix + listDirectoryInput.startAfter)
+	} else if startAfter != "" {
+		s3ListObjectsV2Input.StartAfter = aws.String(startAfter)
 	}
 	if listDirectoryInput.maxItems != 0 {
 		s3ListObjectsV2Input.MaxKeys = aws.Int32(int32(listDirectoryInput.maxItems))
 	}
 
-	s3ListObjectsV2Output, err = s3Context.s3Client.ListObjectsV2(context.Background(), s3ListObjectsV2Input)
+	s3ListObjectsV2Output, err = s3Context.s3Client.ListObjectsV2(ctx, s3ListObjectsV2Input, s3Context.retryOptions(ctx))
 	if err != nil {
 		err = fmt.Errorf("[S3] listDirectory failed: %v", err)
 		return
@@ -307,7 +915,18 @@ func (s3Context *s3ContextStruct) listDirectory(listDirectoryInput *listDirector
 // `listObjects` is called to fetch a `page` of the objects. An empty continuationToken or
 // empty list of elements (`objects`) indicates the list of `objects` has been completely
 // enumerated. The `isTruncated` field will also align with this convention.
-func (s3Context *s3ContextStruct) listObjects(listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+func (s3Context *s3ContextStruct) listObjects(ctx context.Context, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+	if s3Context.backend.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets {
+		listObjectsOutput, err = s3Context.listDiscoveredObjects(ctx, listObjectsInput)
+	} else {
+		listObjectsOutput, err = s3Context.listBucketObjects(ctx, s3Context.backend.bucketContainerName, listObjectsInput)
+	}
+
+	return
+}
+
+// `listBucketObjects` implements listObjects() for the objects (below backend.prefix) of bucket.
+func (s3Context *s3ContextStruct) listBucketObjects(ctx context.Context, bucket string, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
 	var (
 		backend               = s3Context.backend
 		s3ListObjectsV2Input  *s3.ListObjectsV2Input
@@ -315,8 +934,13 @@ func (s3Context *s3ContextStruct) listObjects(listObjectsInput *listObjectsInput
 		s3Object              types.Object
 	)
 
+	if s3Context.snapshot != nil {
+		listObjectsOutput, err = s3Context.listSnapshotObjects(ctx, bucket, listObjectsInput)
+		return
+	}
+
 	s3ListObjectsV2Input = &s3.ListObjectsV2Input{
-		Bucket: aws.String(backend.bucketContainerName),
+		Bucket: aws.String(bucket),
 		Prefix: aws.String(backend.prefix + listObjectsInput.prefix),
 	}
 	if listObjectsInput.continuationToken != "" {
@@ -329,7 +953,7 @@ func (s3Context *s3ContextStruct) listObjects(listObjectsInput *listObjectsInput
 		s3ListObjectsV2Input.MaxKeys = aws.Int32(int32(listObjectsInput.maxItems))
 	}
 
-	s3ListObjectsV2Output, err = s3Context.s3Client.ListObjectsV2(context.Background(), s3ListObjectsV2Input)
+	s3ListObjectsV2Output, err = s3Context.s3Client.ListObjectsV2(ctx, s3ListObjectsV2Input, s3Context.retryOptions(ctx))
 	if err != nil {
 		err = fmt.Errorf("[S3] listObjects failed: %v", err)
 		return
@@ -390,40 +1014,234 @@ func (s3Context *s3ContextStruct) redactSecrets(s string) string {
 	if cfg, ok := s3Context.backend.backendTypeSpecifics.(*backendConfigS3Struct); ok && cfg != nil {
 		s = redactValue(s, cfg.secretAccessKey, "***REDACTED-AWS-SECRET-ACCESS-KEY***")
 		s = redactValue(s, cfg.accessKeyID, "***REDACTED-AWS-ACCESS-KEY-ID***")
+		s = redactValue(s, cfg.sseCKey, "***REDACTED-SSE-C-KEY***")
 	}
 	return redactAWSSecretShapes(s)
 }
 
+// `presignFile` is called to produce a pre-signed GET URL for the `file` at the specified path
+// valid for presignFileInput.expires. As such a URL cannot carry the SSE-C key needed to read
+// the object, errPresignFileNotSupported is returned if sse_type is customer-key.
+func (s3Context *s3ContextStruct) presignFile(ctx context.Context, presignFileInput *presignFileInputStruct) (presignFileOutput *presignFileOutputStruct, err error) {
+	var (
+		bucket               string
+		fullFilePath         string
+		presignedHTTPRequest *v4.PresignedHTTPRequest
+		versionID            *string
+	)
+
+	if s3Context.sse.customerKey != nil {
+		err = errPresignFileNotSupported
+		return
+	}
+
+	bucket, fullFilePath, versionID, err = s3Context.resolveFile(ctx, presignFileInput.filePath)
+	if err != nil {
+		return
+	}
+
+	presignedHTTPRequest, err = s3.NewPresignClient(s3Context.s3Client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(fullFilePath),
+		VersionId: versionID,
+	}, s3.WithPresignExpires(presignFileInput.expires))
+	if err == nil {
+		presignFileOutput = &presignFileOutputStruct{
+			url: presignedHTTPRequest.URL,
+		}
+	}
+
+	return
+}
+
+// `putFile` is called to create (or replace) a "file" at the specified path with the supplied content.
+func (s3Context *s3ContextStruct) putFile(ctx context.Context, putFileInput *putFileInputStruct) (putFileOutput *putFileOutputStruct, err error) {
+	var (
+		bucket            string
+		fullFilePath      string
+		s3PutObjectOutput *s3.PutObjectOutput
+	)
+
+	if s3Context.snapshot != nil {
+		err = errS3SnapshotReadOnly
+		return
+	}
+
+	err = s3Context.rejectVersionsPath(putFileInput.filePath)
+	if err != nil {
+		return
+	}
+
+	bucket, fullFilePath, err = s3Context.bucketAndKey(ctx, putFileInput.filePath)
+	if err != nil {
+		return
+	}
+
+	s3PutObjectOutput, err = s3Context.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(fullFilePath),
+		Body:                 bytes.NewReader(putFileInput.buf),
+		ContentLength:        aws.Int64(int64(len(putFileInput.buf))),
+		SSECustomerAlgorithm: s3Context.sse.customerAlgorithm,
+		SSECustomerKey:       s3Context.sse.customerKey,
+		SSECustomerKeyMD5:    s3Context.sse.customerKeyMD5,
+		SSEKMSKeyId:          s3Context.sse.kmsKeyID,
+		ServerSideEncryption: s3Context.sse.serverSideEncryption,
+	}, s3Context.retryOptions(ctx))
+	if err != nil {
+		return
+	}
+
+	putFileOutput = &putFileOutputStruct{}
+	if s3PutObjectOutput.ETag != nil {
+		putFileOutput.eTag = strings.TrimLeft(strings.TrimRight(*s3PutObjectOutput.ETag, "\""), "\"")
+	}
+
+	return
+}
+
 // `readFile` is called to read a range of a `file` at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (s3Context *s3ContextStruct) readFile(readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
+func (s3Context *s3ContextStruct) readFile(ctx context.Context, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
 	var (
-		backend           = s3Context.backend
-		fullFilePath      = backend.prefix + readFileInput.filePath
-		rangeBegin        = readFileInput.offsetCacheLine * globals.config.cacheLineSize
-		rangeEnd          = rangeBegin + globals.config.cacheLineSize - 1
-		s3GetObjectInput  *s3.GetObjectInput
-		s3GetObjectOutput *s3.GetObjectOutput
+		bucket               string
+		fullFilePath         string
+		rangeBegin           uint64
+		rangeEnd             uint64
+		rangeLength          uint64
+		s3GetObjectInput     *s3.GetObjectInput
+		s3GetObjectOutput    *s3.GetObjectOutput
+		s3InvalidObjectState *types.InvalidObjectState
+		versionID            *string
 	)
 
+	bucket, fullFilePath, versionID, err = s3Context.resolveFile(ctx, readFileInput.filePath)
+	if err != nil {
+		return
+	}
+
+	rangeBegin = readFileInput.offset
+	rangeLength = readFileInput.length
+	rangeEnd = rangeBegin + rangeLength - 1
+
 	s3GetObjectInput = &s3.GetObjectInput{
-		Bucket: aws.String(backend.bucketContainerName),
-		Key:    aws.String(fullFilePath),
-		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", rangeBegin, rangeEnd)),
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(fullFilePath),
+		Range:                aws.String(fmt.Sprintf("bytes=%d-%d", rangeBegin, rangeEnd)),
+		SSECustomerAlgorithm: s3Context.sse.customerAlgorithm,
+		SSECustomerKey:       s3Context.sse.customerKey,
+		SSECustomerKeyMD5:    s3Context.sse.customerKeyMD5,
+		VersionId:            versionID,
 	}
 	if readFileInput.ifMatch != "" {
 		s3GetObjectInput.IfMatch = aws.String(readFileInput.ifMatch)
 	}
+	if readFileInput.ifNoneMatch != "" {
+		s3GetObjectInput.IfNoneMatch = aws.String(readFileInput.ifNoneMatch)
+	}
 
-	s3GetObjectOutput, err = s3Context.s3Client.GetObject(context.Background(), s3GetObjectInput)
+	s3GetObjectOutput, err = s3Context.s3Client.GetObject(ctx, s3GetObjectInput, s3Context.retryOptions(ctx))
+	if (err != nil) && errors.As(err, &s3InvalidObjectState) {
+		err = fmt.Errorf("%w: %w", errObjectArchived, err)
+		return
+	}
+	if (err != nil) && s3IsNotFound(err) {
+		err = fmt.Errorf("%w: %w", errFileNotFound, err)
+		return
+	}
+	if (err != nil) && (readFileInput.ifNoneMatch != "") && s3IsNotModified(err) {
+		readFileOutput = &readFileOutputStruct{
+			eTag:        readFileInput.ifNoneMatch,
+			notModified: true,
+		}
+		err = nil
+		return
+	}
 	if err == nil {
 		readFileOutput = &readFileOutputStruct{}
 		if s3GetObjectOutput.ETag == nil {
 			readFileOutput.eTag = ""
 		} else {
-			readFileOutput.eTag = *s3GetObjectOutput.ETag
+			readFileOutput.eTag = strings.TrimLeft(strings.TrimRight(*s3GetObjectOutput.ETag, "\""), "\"")
+		}
+		if s3GetObjectOutput.ContentLength == nil {
+			readFileOutput.buf, err = readAllSized(s3GetObjectOutput.Body, -1)
+		} else {
+			readFileOutput.buf, err = readAllSized(s3GetObjectOutput.Body, *s3GetObjectOutput.ContentLength)
+		}
+	}
+
+	return
+}
+
+// `restoreFile` is called to request that the archived content of the `file` at the specified
+// path be restored for restore_days using the restore_tier retrieval tier. Unless
+// restore_archived_objects is set, errRestoreFileNotSupported is returned.
+func (s3Context *s3ContextStruct) restoreFile(ctx context.Context, restoreFileInput *restoreFileInputStruct) (restoreFileOutput *restoreFileOutputStruct, err error) {
+	var (
+		backendS3            = s3Context.backend.backendTypeSpecifics.(*backendConfigS3Struct)
+		bucket               string
+		fullFilePath         string
+		s3APIError           smithy.APIError
+		s3RestoreObjectInput *s3.RestoreObjectInput
+		versionID            *string
+	)
+
+	if !backendS3.restoreArchivedObjects {
+		err = errRestoreFileNotSupported
+		return
+	}
+
+	bucket, fullFilePath, versionID, err = s3Context.resolveFile(ctx, restoreFileInput.filePath)
+	if err != nil {
+		return
+	}
+
+	s3RestoreObjectInput = &s3.RestoreObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fullFilePath),
+		RestoreRequest: &types.RestoreRequest{
+			Days: aws.Int32(int32(backendS3.restoreDays)),
+			GlacierJobParameters: &types.GlacierJobParameters{
+				Tier: types.Tier(backendS3.restoreTier),
+			},
+		},
+		VersionId: versionID,
+	}
+
+	_, err = s3Context.s3Client.RestoreObject(ctx, s3RestoreObjectInput, s3Context.retryOptions(ctx))
+	if err != nil {
+		if errors.As(err, &s3APIError) && (s3APIError.ErrorCode() == "RestoreAlreadyInProgress") {
+			restoreFileOutput = &restoreFileOutputStruct{
+				alreadyInProgress: true,
+			}
+			err = nil
 		}
-		readFileOutput.buf, err = io.ReadAll(s3GetObjectOutput.Body)
+		return
+	}
+
+	restoreFileOutput = &restoreFileOutputStruct{
+		alreadyInProgress: false,
+	}
+
+	return
+}
+
+// `s3RestoreStatus` derives a statFileOutput.restoreStatus from the storage class, archive
+// status, and x-amz-restore header of a HeadObject response. Content in the GLACIER and
+// DEEP_ARCHIVE storage classes (or archived by S3 Intelligent-Tiering) must be restored
+// before it may be read. While such a restore is in progress, x-amz-restore reports
+// ongoing-request="true" and, once complete, ongoing-request="false" (with an expiry-date).
+func s3RestoreStatus(storageClass types.StorageClass, archiveStatus types.ArchiveStatus, restore *string) (restoreStatus string) {
+	switch {
+	case (restore != nil) && strings.Contains(*restore, `ongoing-request="true"`):
+		restoreStatus = restoreStatusOngoing
+	case (restore != nil) && strings.Contains(*restore, `ongoing-request="false"`):
+		restoreStatus = restoreStatusRestored
+	case (storageClass == types.StorageClassGlacier) || (storageClass == types.StorageClassDeepArchive) || (archiveStatus != ""):
+		restoreStatus = restoreStatusArchived
+	default:
+		restoreStatus = ""
 	}
 
 	return
@@ -431,21 +1249,44 @@ func (s3Context *s3ContextStruct) readFile(readFileInput *readFileInputStruct) (
 
 // `statDirectory` is called to verify that the specified path refers to a `directory`.
 // An error is returned if either the specified path is not a `directory` or non-existent.
-func (s3Context *s3ContextStruct) statDirectory(statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
+func (s3Context *s3ContextStruct) statDirectory(ctx context.Context, statDirectoryInput *statDirectoryInputStruct) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
 	var (
-		backend               = s3Context.backend
-		fullDirPath           = backend.prefix + statDirectoryInput.dirPath
+		bucket                string
+		fullDirPath           string
+		isVersionsDir         bool
 		s3ListObjectsV2Input  *s3.ListObjectsV2Input
 		s3ListObjectsV2Output *s3.ListObjectsV2Output
+		versionsFilePath      string
 	)
 
+	if (statDirectoryInput.dirPath == "") && s3Context.backend.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets {
+		statDirectoryOutput = &statDirectoryOutputStruct{}
+		return
+	}
+
+	versionsFilePath, _, isVersionsDir = s3Context.versionsPath(statDirectoryInput.dirPath)
+	if isVersionsDir {
+		statDirectoryOutput, err = s3Context.statVersionsDirectory(ctx, versionsFilePath)
+		return
+	}
+
+	bucket, fullDirPath, err = s3Context.bucketAndKey(ctx, statDirectoryInput.dirPath)
+	if err != nil {
+		return
+	}
+
+	if s3Context.snapshot != nil {
+		statDirectoryOutput, err = s3Context.statSnapshotDirectory(ctx, bucket, fullDirPath)
+		return
+	}
+
 	s3ListObjectsV2Input = &s3.ListObjectsV2Input{
-		Bucket:  aws.String(backend.bucketContainerName),
+		Bucket:  aws.String(bucket),
 		MaxKeys: aws.Int32(1),
 		Prefix:  aws.String(fullDirPath),
 	}
 
-	s3ListObjectsV2Output, err = s3Context.s3Client.ListObjectsV2(context.Background(), s3ListObjectsV2Input)
+	s3ListObjectsV2Output, err = s3Context.s3Client.ListObjectsV2(ctx, s3ListObjectsV2Input, s3Context.retryOptions(ctx))
 	if err == nil {
 		if (fullDirPath != "") && ((len(s3ListObjectsV2Output.CommonPrefixes) + len(s3ListObjectsV2Output.Contents)) == 0) {
 			err = errors.New("missing directory")
@@ -460,31 +1301,57 @@ func (s3Context *s3ContextStruct) statDirectory(statDirectoryInput *statDirector
 
 // `statFile` is called to fetch the `file` metadata at the specified path.
 // An error is returned if either the specified path is not a `file` or non-existent.
-func (s3Context *s3ContextStruct) statFile(statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
+func (s3Context *s3ContextStruct) statFile(ctx context.Context, statFileInput *statFileInputStruct) (statFileOutput *statFileOutputStruct, err error) {
 	var (
-		backend            = s3Context.backend
-		fullFilePath       = backend.prefix + statFileInput.filePath
+		bucket             string
+		fullFilePath       string
 		s3HeadObjectInput  *s3.HeadObjectInput
 		s3HeadObjectOutput *s3.HeadObjectOutput
+		versionID          *string
 	)
 
+	bucket, fullFilePath, versionID, err = s3Context.resolveFile(ctx, statFileInput.filePath)
+	if err != nil {
+		return
+	}
+
 	s3HeadObjectInput = &s3.HeadObjectInput{
-		Bucket: aws.String(backend.bucketContainerName),
-		Key:    aws.String(fullFilePath),
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(fullFilePath),
+		SSECustomerAlgorithm: s3Context.sse.customerAlgorithm,
+		SSECustomerKey:       s3Context.sse.customerKey,
+		SSECustomerKeyMD5:    s3Context.sse.customerKeyMD5,
+		VersionId:            versionID,
 	}
 	if statFileInput.ifMatch != "" {
 		s3HeadObjectInput.IfMatch = aws.String(statFileInput.ifMatch)
 	}
+	if statFileInput.ifNoneMatch != "" {
+		s3HeadObjectInput.IfNoneMatch = aws.String(statFileInput.ifNoneMatch)
+	}
 
-	s3HeadObjectOutput, err = s3Context.s3Client.HeadObject(context.Background(), s3HeadObjectInput)
+	s3HeadObjectOutput, err = s3Context.s3Client.HeadObject(ctx, s3HeadObjectInput, s3Context.retryOptions(ctx))
 	if err != nil {
+		if (statFileInput.ifNoneMatch != "") && s3IsNotModified(err) {
+			statFileOutput = &statFileOutputStruct{
+				eTag:        statFileInput.ifNoneMatch,
+				notModified: true,
+			}
+			err = nil
+		} else if s3IsNotFound(err) {
+			err = fmt.Errorf("%w: %w", errFileNotFound, err)
+		}
 		return
 	}
 
 	statFileOutput = &statFileOutputStruct{
-		eTag:  strings.TrimLeft(strings.TrimRight(*s3HeadObjectOutput.ETag, "\""), "\""),
-		mTime: *s3HeadObjectOutput.LastModified,
-		size:  uint64(*s3HeadObjectOutput.ContentLength),
+		eTag:          strings.TrimLeft(strings.TrimRight(*s3HeadObjectOutput.ETag, "\""), "\""),
+		mTime:         *s3HeadObjectOutput.LastModified,
+		size:          uint64(*s3HeadObjectOutput.ContentLength),
+		storageClass:  string(s3HeadObjectOutput.StorageClass),
+		restoreStatus: s3RestoreStatus(s3HeadObjectOutput.StorageClass, s3HeadObjectOutput.ArchiveStatus, s3HeadObjectOutput.Restore),
+		metadata:      s3HeadObjectOutput.Metadata,
+		contentType:   aws.ToString(s3HeadObjectOutput.ContentType),
 	}
 
 	return
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// `s3DiscoveredBucketsStruct` caches the names of the buckets accessible to the credentials
+// of an S3 backend with discover_buckets set. Each such bucket is presented as a subdirectory
+// of the backend's directory (with prefix, if any, applied within each bucket).
+type s3DiscoveredBucketsStruct struct {
+	sync.Mutex
+	names     []string  // Sorted
+	fetchTime time.Time // If zero, names must be (re)fetched via ListBuckets
+}
+
+// `invalidate` causes the next use of discoveredBuckets to refetch the list of buckets.
+// It is called upon each SIGHUP.
+func (discoveredBuckets *s3DiscoveredBucketsStruct) invalidate() {
+	discoveredBuckets.Lock()
+	discoveredBuckets.fetchTime = time.Time{}
+	discoveredBuckets.Unlock()
+}
+
+// `buckets` returns the sorted names of the buckets accessible to the backend fetching them
+// via ListBuckets if not yet fetched, invalidated by a SIGHUP, or older than bucket_discovery_ttl.
+func (s3Context *s3ContextStruct) buckets(ctx context.Context) (names []string, err error) {
+	var (
+		backend             = s3Context.backend
+		backendS3           = backend.backendTypeSpecifics.(*backendConfigS3Struct)
+		discoveredBuckets   = backendS3.discoveredBuckets
+		s3Bucket            types.Bucket
+		s3ListBucketsInput  *s3.ListBucketsInput
+		s3ListBucketsOutput *s3.ListBucketsOutput
+	)
+
+	discoveredBuckets.Lock()
+	defer discoveredBuckets.Unlock()
+
+	if !discoveredBuckets.fetchTime.IsZero() && ((backendS3.bucketDiscoveryTTL == 0) || (time.Since(discoveredBuckets.fetchTime) < backendS3.bucketDiscoveryTTL)) {
+		names = discoveredBuckets.names
+		return
+	}
+
+	names = make([]string, 0)
+
+	s3ListBucketsInput = &s3.ListBucketsInput{}
+
+	for {
+		s3ListBucketsOutput, err = s3Context.s3Client.ListBuckets(ctx, s3ListBucketsInput, s3Context.retryOptions(ctx))
+		if err != nil {
+			err = fmt.Errorf("[S3] ListBuckets failed: %v", err)
+			return
+		}
+
+		for _, s3Bucket = range s3ListBucketsOutput.Buckets {
+			if (s3Bucket.Name != nil) && (*s3Bucket.Name != "") {
+				names = append(names, *s3Bucket.Name)
+			}
+		}
+
+		if (s3ListBucketsOutput.ContinuationToken == nil) || (*s3ListBucketsOutput.ContinuationToken == "") {
+			break
+		}
+
+		s3ListBucketsInput.ContinuationToken = s3ListBucketsOutput.ContinuationToken
+	}
+
+	slices.Sort(names)
+
+	if !slices.Equal(names, discoveredBuckets.names) {
+		backend.logf(slog.LevelInfo, "%s discovered %d bucket(s)", backend.dirName, len(names))
+	}
+
+	discoveredBuckets.names = names
+	discoveredBuckets.fetchTime = time.Now()
+
+	return
+}
+
+// `bucketAndKey` maps path (relative to backend.prefix) to the bucket and object key it
+// references. Unless discover_buckets is set, that is simply bucket_container_name and
+// backend.prefix + path. Otherwise, the first element of path names a discovered bucket
+// within which the remainder of path (following backend.prefix) is the key.
+func (s3Context *s3ContextStruct) bucketAndKey(ctx context.Context, path string) (bucket string, key string, err error) {
+	var (
+		backend = s3Context.backend
+		found   bool
+		names   []string
+	)
+
+	if !backend.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets {
+		bucket = backend.bucketContainerName
+		key = backend.prefix + path
+		return
+	}
+
+	bucket, path, found = strings.Cut(path, "/")
+	if !found {
+		err = fmt.Errorf("%w: \"%s\" is not within a bucket", errFileNotFound, bucket)
+		return
+	}
+
+	names, err = s3Context.buckets(ctx)
+	if err != nil {
+		return
+	}
+
+	_, found = slices.BinarySearch(names, bucket)
+	if !found {
+		err = fmt.Errorf("%w: bucket \"%s\" not discovered", errFileNotFound, bucket)
+		return
+	}
+
+	key = backend.prefix + path
+
+	return
+}
+
+// `listBuckets` implements listDirectory() of the backend's root directory when discover_buckets
+// is set by returning each discovered bucket as a subdirectory in a single page.
+func (s3Context *s3ContextStruct) listBuckets(ctx context.Context, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+	var (
+		name  string
+		names []string
+	)
+
+	names, err = s3Context.buckets(ctx)
+	if err != nil {
+		return
+	}
+
+	listDirectoryOutput = &listDirectoryOutputStruct{
+		subdirectory:          make([]string, 0, len(names)),
+		file:                  make([]listDirectoryOutputFileStruct, 0),
+		nextContinuationToken: "",
+		isTruncated:           false,
+	}
+
+	if listDirectoryInput.continuationToken != "" {
+		// Since all buckets were returned in the first page, there are no more
+		return
+	}
+
+	for _, name = range names {
+		if (name + "/") > listDirectoryInput.startAfter {
+			listDirectoryOutput.subdirectory = append(listDirectoryOutput.subdirectory, name)
+		}
+	}
+
+	return
+}
+
+// `listDiscoveredObjects` implements listObjects() when discover_buckets is set by enumerating
+// each discovered bucket matching listObjectsInput.prefix in turn. Returned paths (and the
+// continuation token) begin with the bucket's name. A page ending one bucket's objects yields
+// a continuation token naming only the next bucket so that enumeration resumes at its start.
+func (s3Context *s3ContextStruct) listDiscoveredObjects(ctx context.Context, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+	var (
+		bucket            string
+		bucketIndex       int
+		bucketInput       *listObjectsInputStruct
+		bucketPrefix      string
+		names             []string
+		objectIndex       int
+		resumeBucket      string
+		resumeWithinToken string
+		resumeWithinAfter string
+	)
+
+	names, err = s3Context.buckets(ctx)
+	if err != nil {
+		return
+	}
+
+	if listObjectsInput.continuationToken != "" {
+		resumeBucket, resumeWithinToken, _ = strings.Cut(listObjectsInput.continuationToken, "/")
+	} else if listObjectsInput.startAfter != "" {
+		resumeBucket, resumeWithinAfter, _ = strings.Cut(listObjectsInput.startAfter, "/")
+	}
+
+	for bucketIndex, bucket = range names {
+		if bucket < resumeBucket {
+			continue
+		}
+
+		bucketPrefix = bucket + "/"
+
+		bucketInput = &listObjectsInputStruct{
+			maxItems: listObjectsInput.maxItems,
+		}
+
+		switch {
+		case strings.HasPrefix(listObjectsInput.prefix, bucketPrefix):
+			bucketInput.prefix = strings.TrimPrefix(listObjectsInput.prefix, bucketPrefix)
+		case strings.HasPrefix(bucketPrefix, listObjectsInput.prefix):
+			bucketInput.prefix = ""
+		default:
+			continue
+		}
+
+		if bucket == resumeBucket {
+			bucketInput.continuationToken = resumeWithinToken
+			bucketInput.startAfter = resumeWithinAfter
+		}
+
+		listObjectsOutput, err = s3Context.listBucketObjects(ctx, bucket, bucketInput)
+		if err != nil {
+			return
+		}
+
+		for objectIndex = range listObjectsOutput.object {
+			listObjectsOutput.object[objectIndex].path = bucketPrefix + listObjectsOutput.object[objectIndex].path
+		}
+
+		if listObjectsOutput.isTruncated {
+			listObjectsOutput.nextContinuationToken = bucketPrefix + listObjectsOutput.nextContinuationToken
+			return
+		}
+
+		if len(listObjectsOutput.object) > 0 {
+			if (bucketIndex + 1) < len(names) {
+				listObjectsOutput.nextContinuationToken = names[bucketIndex+1] + "/"
+				listObjectsOutput.isTruncated = true
+			}
+			return
+		}
+	}
+
+	listObjectsOutput = &listObjectsOutputStruct{
+		object:                make([]listObjectsOutputObjectStruct, 0),
+		nextContinuationToken: "",
+		isTruncated:           false,
+	}
+
+	return
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestS3BackendDiscoverBuckets verifies that an S3 backend with discover_buckets set presents
+// each accessible bucket as a subdirectory and rediscovers them only once invalidated (as
+// by a SIGHUP) when bucket_discovery_ttl is 0.
+func TestS3BackendDiscoverBuckets(t *testing.T) {
+	var (
+		backend             *backendStruct
+		continuationToken   string
+		err                 error
+		listDirectoryOutput *listDirectoryOutputStruct
+		listObjectsOutput   *listObjectsOutputStruct
+		object              listObjectsOutputObjectStruct
+		paths               []string
+		readFileOutput      *readFileOutputStruct
+		statFileOutput      *statFileOutputStruct
+	)
+
+	fissionS3TestUpWithSettings(t, "", `
+					"discover_buckets": true,
+					"bucket_discovery_ttl": 0,`)
+	defer fissionS3TestDown(t)
+
+	testGlobals.testS3Server.putObject("other", "dir2/fileD", []byte("/dir2/fileD\n"))
+
+	backend = globals.config.backends["s3"]
+
+	listDirectoryOutput, err = backend.context.listDirectory(context.Background(), &listDirectoryInputStruct{dirPath: ""})
+	if err != nil {
+		t.Fatalf("listDirectory(\"\") failed: %v", err)
+	}
+	if !slices.Equal(listDirectoryOutput.subdirectory, []string{"other", testFissionS3Bucket}) || (len(listDirectoryOutput.file) != 0) {
+		t.Fatalf("listDirectory(\"\") returned subdirectory %v and %d file(s)", listDirectoryOutput.subdirectory, len(listDirectoryOutput.file))
+	}
+
+	listDirectoryOutput, err = backend.context.listDirectory(context.Background(), &listDirectoryInputStruct{dirPath: testFissionS3Bucket + "/"})
+	if err != nil {
+		t.Fatalf("listDirectory(\"%s/\") failed: %v", testFissionS3Bucket, err)
+	}
+	if !slices.Equal(listDirectoryOutput.subdirectory, []string{"dir1"}) || (len(listDirectoryOutput.file) != 2) || (listDirectoryOutput.file[0].basename != "fileA") {
+		t.Fatalf("listDirectory(\"%s/\") returned unexpected content: %#v", testFissionS3Bucket, listDirectoryOutput)
+	}
+
+	statFileOutput, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "other/dir2/fileD"})
+	if err != nil {
+		t.Fatalf("statFile(\"other/dir2/fileD\") failed: %v", err)
+	}
+	if statFileOutput.size != uint64(len("/dir2/fileD\n")) {
+		t.Fatalf("statFile(\"other/dir2/fileD\") returned size %d", statFileOutput.size)
+	}
+
+	readFileOutput, err = backend.context.readFile(context.Background(), &readFileInputStruct{filePath: testFissionS3Bucket + "/fileA", offset: 0, length: 7})
+	if err != nil {
+		t.Fatalf("readFile(\"%s/fileA\") failed: %v", testFissionS3Bucket, err)
+	}
+	if string(readFileOutput.buf) != "/fileA\n" {
+		t.Fatalf("readFile(\"%s/fileA\") returned %q", testFissionS3Bucket, readFileOutput.buf)
+	}
+
+	// Neither files alongside the buckets nor undiscovered buckets exist
+
+	_, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "fileA"})
+	if !errors.Is(err, errFileNotFound) {
+		t.Fatalf("statFile(\"fileA\") should have failed with errFileNotFound (err: %v)", err)
+	}
+
+	_, err = backend.context.statDirectory(context.Background(), &statDirectoryInputStruct{dirPath: "third/"})
+	if err == nil {
+		t.Fatalf("statDirectory(\"third/\") should have failed")
+	}
+
+	// A new bucket only appears once the discovered buckets are invalidated
+
+	testGlobals.testS3Server.putObject("third", "fileE", []byte("/fileE\n"))
+
+	listDirectoryOutput, err = backend.context.listDirectory(context.Background(), &listDirectoryInputStruct{dirPath: ""})
+	if (err != nil) || (len(listDirectoryOutput.subdirectory) != 2) {
+		t.Fatalf("listDirectory(\"\") should have still returned 2 buckets (err: %v)", err)
+	}
+
+	backend.backendTypeSpecifics.(*backendConfigS3Struct).discoveredBuckets.invalidate()
+
+	_, err = backend.context.statDirectory(context.Background(), &statDirectoryInputStruct{dirPath: "third/"})
+	if err != nil {
+		t.Fatalf("statDirectory(\"third/\") failed: %v", err)
+	}
+
+	// listObjects() pages across all of the buckets
+
+	for {
+		listObjectsOutput, err = backend.context.listObjects(context.Background(), &listObjectsInputStruct{continuationToken: continuationToken, maxItems: 2})
+		if err != nil {
+			t.Fatalf("listObjects(continuationToken:\"%s\") failed: %v", continuationToken, err)
+		}
+		for _, object = range listObjectsOutput.object {
+			paths = append(paths, object.path)
+		}
+		if !listObjectsOutput.isTruncated {
+			break
+		}
+		continuationToken = listObjectsOutput.nextContinuationToken
+	}
+
+	if !slices.Equal(paths, []string{"other/dir2/fileD", testFissionS3Bucket + "/dir1/fileC", testFissionS3Bucket + "/fileA", testFissionS3Bucket + "/fileB", "third/fileE"}) {
+		t.Fatalf("listObjects() returned paths %v", paths)
+	}
+}
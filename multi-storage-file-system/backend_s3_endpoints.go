@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// `s3EndpointPoolMiddlewareID` identifies the Finalize step middleware that directs each
+// request attempt to one of the endpoints of an s3EndpointPoolStruct.
+const s3EndpointPoolMiddlewareID = "MSFSEndpointPool"
+
+const (
+	s3ReadEndpointSelectionRoundRobin = "round-robin" // Reads are spread round-robin like every other request
+	s3ReadEndpointSelectionFastest    = "fastest"     // Each read goes to the healthy endpoint with the lowest read latency
+
+	s3EndpointExploreInterval = 16 // With "fastest", every this many reads go round-robin to refresh each endpoint's latency
+	s3EndpointLatencyWeight   = 8  // Each read latency sample contributes 1/this to an endpoint's moving average
+)
+
+// `s3EndpointStruct` tracks the health of one of an S3 backend's endpoints.
+type s3EndpointStruct struct {
+	url     *url.URL     // Only .Scheme and .Host are substituted into each request
+	healthy atomic.Bool  // Cleared when a request (or health check) fails to reach the endpoint
+	latency atomic.Int64 // Moving average (in nanoseconds) of successful GetObject attempts' time to response; 0 until sampled
+}
+
+// `s3EndpointPoolStruct` spreads the requests of an S3 backend configured with multiple
+// endpoints round-robin across those that are healthy. An endpoint is deemed unhealthy
+// once a request to it fails without a response (e.g. connection refused) and healthy
+// again once either a request or a periodic health check receives a response. Should no
+// endpoint be healthy, requests are spread across all of them.
+type s3EndpointPoolStruct struct {
+	endpoints           []*s3EndpointStruct //
+	primaryHost         string              // endpoints[0].url.Host (to which the SDK resolves each request)
+	next                atomic.Uint64       // Index (modulo len(endpoints)) at which the next pick() begins
+	healthCheckClient   aws.HTTPClient      // Shares the S3 client's TLS and proxy settings
+	healthCheckInterval time.Duration       // If == 0, only requests update endpoint health
+	readSelectFastest   bool                // If true, reads (GetObject) use pickFastest() rather than pick()
+}
+
+// `newS3EndpointPool` returns the endpoint pool for backendS3 (whose endpoints were
+// validated by checkConfigFile()) or nil if fewer than two endpoints are configured.
+func newS3EndpointPool(backendS3 *backendConfigS3Struct, healthCheckClient aws.HTTPClient) (pool *s3EndpointPoolStruct) {
+	var (
+		endpoint    *s3EndpointStruct
+		endpointURL string
+	)
+
+	if len(backendS3.endpoints) < 2 {
+		return nil
+	}
+
+	pool = &s3EndpointPoolStruct{
+		endpoints:           make([]*s3EndpointStruct, 0, len(backendS3.endpoints)),
+		healthCheckClient:   healthCheckClient,
+		healthCheckInterval: backendS3.endpointCheckInterval,
+		readSelectFastest:   backendS3.readEndpointSelection == s3ReadEndpointSelectionFastest,
+	}
+
+	for _, endpointURL = range backendS3.endpoints {
+		endpoint = &s3EndpointStruct{}
+		endpoint.url, _ = url.Parse(endpointURL)
+		endpoint.healthy.Store(true)
+		pool.endpoints = append(pool.endpoints, endpoint)
+	}
+
+	pool.primaryHost = pool.endpoints[0].url.Host
+
+	return
+}
+
+// `pick` returns the next healthy endpoint in round-robin order or, if none are
+// healthy, simply the next endpoint.
+func (pool *s3EndpointPoolStruct) pick() (endpoint *s3EndpointStruct) {
+	var (
+		endpointsLen = uint64(len(pool.endpoints))
+		i            uint64
+		start        = pool.next.Add(1) - 1
+	)
+
+	for i = range endpointsLen {
+		endpoint = pool.endpoints[(start+i)%endpointsLen]
+		if endpoint.healthy.Load() {
+			return
+		}
+	}
+
+	endpoint = pool.endpoints[start%endpointsLen]
+
+	return
+}
+
+// `pickFastest` returns the healthy endpoint with the lowest read latency (preferring any not
+// yet sampled) except that every s3EndpointExploreInterval-th call defers to pick() so that
+// the latencies of the other endpoints continue to be sampled.
+func (pool *s3EndpointPoolStruct) pickFastest() (endpoint *s3EndpointStruct) {
+	var (
+		candidate        *s3EndpointStruct
+		candidateLatency int64
+		fastestLatency   int64
+	)
+
+	if (pool.next.Load() % s3EndpointExploreInterval) == 0 {
+		endpoint = pool.pick()
+		return
+	}
+
+	pool.next.Add(1)
+
+	for _, candidate = range pool.endpoints {
+		if !candidate.healthy.Load() {
+			continue
+		}
+		candidateLatency = candidate.latency.Load()
+		if (endpoint == nil) || (candidateLatency < fastestLatency) {
+			endpoint = candidate
+			fastestLatency = candidateLatency
+		}
+	}
+
+	if endpoint == nil {
+		endpoint = pool.pick()
+	}
+
+	return
+}
+
+// `recordLatency` folds a successful read's latency into endpoint's moving average.
+func (endpoint *s3EndpointStruct) recordLatency(latency time.Duration) {
+	var (
+		averageLatency = endpoint.latency.Load()
+	)
+
+	if averageLatency == 0 {
+		endpoint.latency.Store(max(int64(latency), 1))
+	} else {
+		endpoint.latency.Store(max(averageLatency+((int64(latency)-averageLatency)/s3EndpointLatencyWeight), 1))
+	}
+}
+
+// `setHealthy` records the health of endpoint logging any change.
+func (endpoint *s3EndpointStruct) setHealthy(dirName string, healthy bool, cause error) {
+	if endpoint.healthy.Swap(healthy) == healthy {
+		return
+	}
+
+	if healthy {
+		globals.logger.Printf("[INFO] [S3] endpoint \"%s\" of backends[\"%s\"] is healthy", endpoint.url.Host, dirName)
+	} else {
+		globals.logger.Printf("[WARN] [S3] endpoint \"%s\" of backends[\"%s\"] is unhealthy: %v", endpoint.url.Host, dirName, cause)
+	}
+}
+
+// `apiOption` returns the s3.Client API option installing the pool's middleware. As the
+// middleware sits between the Retry and Signing middleware, each attempt (including each
+// retry) is directed to the endpoint then picked and is signed for that endpoint.
+func (pool *s3EndpointPoolStruct) apiOption(dirName string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc(s3EndpointPoolMiddlewareID, func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (out middleware.FinalizeOutput, metadata middleware.Metadata, err error) {
+			var (
+				endpoint         *s3EndpointStruct
+				isRead           bool
+				ok               bool
+				req              *smithyhttp.Request
+				requestSendError *smithyhttp.RequestSendError
+				startTime        time.Time
+			)
+
+			req, ok = in.Request.(*smithyhttp.Request)
+			if !ok {
+				return next.HandleFinalize(ctx, in)
+			}
+
+			isRead = (awsmiddleware.GetOperationName(ctx) == "GetObject")
+
+			if isRead && pool.readSelectFastest {
+				endpoint = pool.pickFastest()
+			} else {
+				endpoint = pool.pick()
+			}
+
+			// Preserve any virtual-hosted-style bucket prefix of the resolved host
+
+			req.URL.Scheme = endpoint.url.Scheme
+			req.URL.Host = strings.TrimSuffix(req.URL.Host, pool.primaryHost) + endpoint.url.Host
+
+			startTime = time.Now()
+
+			out, metadata, err = next.HandleFinalize(ctx, in)
+
+			if isRead && (err == nil) {
+				endpoint.recordLatency(time.Since(startTime))
+			}
+
+			// Only a failure to obtain a response (not merely an error response) marks
+			// the endpoint unhealthy...and not if the request was simply canceled
+
+			if !errors.As(err, &requestSendError) {
+				endpoint.setHealthy(dirName, true, nil)
+			} else if ctx.Err() == nil {
+				endpoint.setHealthy(dirName, false, err)
+			}
+
+			return
+		}), "Signing", middleware.Before)
+	}
+}
+
+// `checkHealth` probes endpoint with an unauthenticated GET. Any response other than a
+// 5xx (e.g. the expected 403 for an anonymous request) indicates the endpoint is healthy.
+func (pool *s3EndpointPoolStruct) checkHealth(dirName string, endpoint *s3EndpointStruct) {
+	var (
+		cancel   context.CancelFunc
+		ctx      context.Context
+		err      error
+		req      *http.Request
+		response *http.Response
+	)
+
+	ctx, cancel = context.WithTimeout(context.Background(), pool.healthCheckInterval)
+	defer cancel()
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint.url.String(), nil)
+	if err != nil {
+		endpoint.setHealthy(dirName, false, err)
+		return
+	}
+
+	response, err = pool.healthCheckClient.Do(req)
+	if err != nil {
+		endpoint.setHealthy(dirName, false, err)
+		return
+	}
+
+	_ = response.Body.Close()
+
+	if response.StatusCode >= 500 {
+		endpoint.setHealthy(dirName, false, errors.New(response.Status))
+	} else {
+		endpoint.setHealthy(dirName, true, nil)
+	}
+}
+
+// `healthCheckLoop` periodically probes each of the pool's endpoints for as long as
+// s3Context remains backend's context. It is launched by setupS3Context() if
+// endpoint_check_interval != 0.
+func (pool *s3EndpointPoolStruct) healthCheckLoop(backend *backendStruct, s3Context *s3ContextStruct) {
+	var (
+		endpoint *s3EndpointStruct
+		inUse    bool
+		ticker   = time.NewTicker(pool.healthCheckInterval)
+	)
+
+	defer ticker.Stop()
+
+	for range ticker.C {
+		globalsLock("backend_s3_endpoints.go:267:3:(*s3EndpointPoolStruct).healthCheckLoop")
+		inUse = (backend.context == s3Context) && ((backend.nonce == 0) || (globals.backendMap[backend.nonce] == backend))
+		globalsUnlock()
+
+		if !inUse {
+			return
+		}
+
+		for _, endpoint = range pool.endpoints {
+			pool.checkHealth(backend.dirName, endpoint)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestS3BackendEndpointFailover verifies that requests to an S3 backend listing an
+// unreachable endpoint fail over to (and thereafter favor) the reachable one.
+func TestS3BackendEndpointFailover(t *testing.T) {
+	var (
+		backend        *backendStruct
+		err            error
+		readFileOutput *readFileOutputStruct
+		requestIndex   int
+		s3Context      *s3ContextStruct
+	)
+
+	// Port 1 (tcpmux) is reliably closed so connections to it are refused
+
+	fissionS3TestUpWithS3Settings(t, `
+					"endpoints": ["http://127.0.0.1:1", "`+testGlobals.testS3Server.endpoint()+`"],
+					"endpoint_check_interval": 0,`)
+	defer fissionS3TestDown(t)
+
+	backend = globals.config.backends["s3"]
+	s3Context = backend.context.(*s3ContextStruct)
+
+	if (s3Context.endpointPool == nil) || (len(s3Context.endpointPool.endpoints) != 2) {
+		t.Fatalf("S3 backend should have had an endpoint pool of 2 endpoints")
+	}
+
+	for requestIndex = range 4 {
+		readFileOutput, err = backend.context.readFile(context.Background(), &readFileInputStruct{filePath: "fileA", offset: 0, length: 7})
+		if err != nil {
+			t.Fatalf("readFile(\"fileA\") #%d failed: %v", requestIndex, err)
+		}
+		if string(readFileOutput.buf) != "/fileA\n" {
+			t.Fatalf("readFile(\"fileA\") #%d returned %q", requestIndex, readFileOutput.buf)
+		}
+	}
+
+	if s3Context.endpointPool.endpoints[0].healthy.Load() {
+		t.Fatalf("unreachable endpoint should have been marked unhealthy")
+	}
+	if !s3Context.endpointPool.endpoints[1].healthy.Load() {
+		t.Fatalf("reachable endpoint should have remained healthy")
+	}
+
+	// An explicit health check of the reachable endpoint succeeds while that of the
+	// unreachable one leaves it unhealthy
+
+	s3Context.endpointPool.healthCheckInterval = time.Second
+	s3Context.endpointPool.checkHealth(backend.dirName, s3Context.endpointPool.endpoints[0])
+	s3Context.endpointPool.checkHealth(backend.dirName, s3Context.endpointPool.endpoints[1])
+
+	if s3Context.endpointPool.endpoints[0].healthy.Load() || !s3Context.endpointPool.endpoints[1].healthy.Load() {
+		t.Fatalf("checkHealth() recorded unexpected endpoint health")
+	}
+}
+
+// TestS3ParseEndpoints verifies the validation of an S3 section's endpoints list.
+func TestS3ParseEndpoints(t *testing.T) {
+	var (
+		endpoints            []string
+		endpointsAsInterface interface{}
+		err                  error
+	)
+
+	endpoints, err = parseEndpoints(map[string]interface{}{"endpoints": []interface{}{"http://a:9000/s3", "https://b:9000/s3/"}})
+	if (err != nil) || (len(endpoints) != 2) {
+		t.Fatalf("parseEndpoints() of valid endpoints returned (%v, %v)", endpoints, err)
+	}
+
+	for _, endpointsAsInterface = range []interface{}{
+		"http://a:9000",
+		[]interface{}{"ftp://a"},
+		[]interface{}{"http://a:9000/x", "http://b:9000/y"},
+		[]interface{}{"http://a:9000", "http://a:9000"},
+	} {
+		_, err = parseEndpoints(map[string]interface{}{"endpoints": endpointsAsInterface})
+		if err == nil {
+			t.Fatalf("parseEndpoints(%v) should have failed", endpointsAsInterface)
+		}
+	}
+}
+
+// TestS3EndpointPickFastest verifies that, with read_endpoint_selection "fastest", reads
+// favor the healthy endpoint with the lowest latency while still periodically exploring.
+func TestS3EndpointPickFastest(t *testing.T) {
+	var (
+		fastCount int
+		pool      *s3EndpointPoolStruct
+		slowCount int
+	)
+
+	pool = newS3EndpointPool(&backendConfigS3Struct{
+		endpoints:             []string{"http://slow:9000", "http://fast:9000"},
+		readEndpointSelection: s3ReadEndpointSelectionFastest,
+	}, nil)
+
+	if !pool.readSelectFastest {
+		t.Fatalf("newS3EndpointPool() should have enabled readSelectFastest")
+	}
+
+	pool.endpoints[0].recordLatency(100 * time.Millisecond)
+	pool.endpoints[1].recordLatency(10 * time.Millisecond)
+
+	for range 4 * s3EndpointExploreInterval {
+		switch pool.pickFastest() {
+		case pool.endpoints[0]:
+			slowCount++
+		case pool.endpoints[1]:
+			fastCount++
+		}
+	}
+
+	if (slowCount == 0) || (slowCount > 4) || (fastCount < 4*(s3EndpointExploreInterval-1)) {
+		t.Fatalf("pickFastest() chose slow:%d fast:%d", slowCount, fastCount)
+	}
+
+	// A moving average tracks, but lags, a change in latency
+
+	pool.endpoints[1].recordLatency(1000 * time.Millisecond)
+	if (pool.endpoints[1].latency.Load() <= int64(10*time.Millisecond)) || (pool.endpoints[1].latency.Load() >= int64(1000*time.Millisecond)) {
+		t.Fatalf("recordLatency() produced unexpected moving average %v", time.Duration(pool.endpoints[1].latency.Load()))
+	}
+
+	// An unhealthy endpoint is avoided however fast
+
+	pool.endpoints[0].latency.Store(int64(time.Hour))
+	pool.endpoints[1].latency.Store(int64(time.Millisecond))
+	pool.endpoints[1].healthy.Store(false)
+
+	for range 2 * s3EndpointExploreInterval {
+		if pool.pickFastest() != pool.endpoints[0] {
+			t.Fatalf("pickFastest() chose an unhealthy endpoint")
+		}
+	}
+}
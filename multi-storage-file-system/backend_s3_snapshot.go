@@ -0,0 +1,413 @@
+package main
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// `errS3SnapshotReadOnly` is returned by any attempt to modify an S3 backend pinned to a snapshot_time.
+var errS3SnapshotReadOnly = errors.New("[S3] backend is pinned to snapshot_time")
+
+// `s3SnapshotVersionIDsMax` is the default number of resolved versionIds an s3SnapshotStruct
+// remembers before forgetting those least recently used.
+const s3SnapshotVersionIDsMax = 1 << 18
+
+// `s3SnapshotStruct` pins an S3 backend with snapshot_time set to the object versions that
+// were current as of that time. Since those versions never change, each key's resolved
+// versionId may be remembered indefinitely. To bound memory, however, only the most recently
+// used maxVersionIDs are retained; any other key is simply resolved again when next needed.
+type s3SnapshotStruct struct {
+	sync.Mutex
+	time          time.Time
+	maxVersionIDs int
+	versionIDs    map[string]*list.Element // Key is bucket + "/" + key; Value.Value is a *s3SnapshotVersionIDStruct
+	versionIDLRU  *list.List               // Front is the most recently used element of .versionIDs
+}
+
+// `s3SnapshotVersionIDStruct` is an element of s3SnapshotStruct.versionIDLRU.
+type s3SnapshotVersionIDStruct struct {
+	bucketKey string // bucket + "/" + key
+	versionID string // "" if the key did not exist as of s3SnapshotStruct.time
+}
+
+// `s3SnapshotEntryStruct` describes each object version or delete marker in a ListObjectVersions page.
+type s3SnapshotEntryStruct struct {
+	key          string
+	versionID    string
+	deleteMarker bool
+	eTag         string
+	mTime        time.Time
+	size         uint64
+}
+
+// `s3SnapshotPageStruct` is the result of listSnapshot() fetching one page of ListObjectVersions.
+type s3SnapshotPageStruct struct {
+	current               []s3SnapshotEntryStruct // Versions current as of snapshot.time (never delete markers)
+	commonPrefixes        []string                // Note: present if any version beneath them exists (even if not yet, or no longer, current)
+	lastKey               string                  // Key of the last version or delete marker in the page (if any)
+	nextContinuationToken string
+	isTruncated           bool
+}
+
+// `newS3Snapshot` returns the s3SnapshotStruct for an S3 backend whose snapshot_time is
+// snapshotTime or, if snapshot_time was not specified, nil.
+func newS3Snapshot(snapshotTime time.Time) (snapshot *s3SnapshotStruct) {
+	if snapshotTime.IsZero() {
+		return
+	}
+
+	snapshot = &s3SnapshotStruct{
+		time:          snapshotTime,
+		maxVersionIDs: s3SnapshotVersionIDsMax,
+		versionIDs:    make(map[string]*list.Element),
+		versionIDLRU:  list.New(),
+	}
+
+	return
+}
+
+// `remember` records that key in bucket resolved to versionID ("" if it did not then exist),
+// forgetting the least recently used resolution should more than maxVersionIDs be retained.
+func (snapshot *s3SnapshotStruct) remember(bucket, key, versionID string) {
+	var (
+		bucketKey = bucket + "/" + key
+		element   *list.Element
+		ok        bool
+	)
+
+	snapshot.Lock()
+
+	element, ok = snapshot.versionIDs[bucketKey]
+	if ok {
+		element.Value.(*s3SnapshotVersionIDStruct).versionID = versionID
+		snapshot.versionIDLRU.MoveToFront(element)
+	} else {
+		snapshot.versionIDs[bucketKey] = snapshot.versionIDLRU.PushFront(&s3SnapshotVersionIDStruct{
+			bucketKey: bucketKey,
+			versionID: versionID,
+		})
+
+		for snapshot.versionIDLRU.Len() > snapshot.maxVersionIDs {
+			element = snapshot.versionIDLRU.Back()
+			delete(snapshot.versionIDs, element.Value.(*s3SnapshotVersionIDStruct).bucketKey)
+			snapshot.versionIDLRU.Remove(element)
+		}
+	}
+
+	snapshot.Unlock()
+}
+
+// `recall` returns what key in bucket was previously resolved to (if it is still remembered).
+func (snapshot *s3SnapshotStruct) recall(bucket, key string) (versionID string, ok bool) {
+	var (
+		element *list.Element
+	)
+
+	snapshot.Lock()
+
+	element, ok = snapshot.versionIDs[bucket+"/"+key]
+	if ok {
+		versionID = element.Value.(*s3SnapshotVersionIDStruct).versionID
+		snapshot.versionIDLRU.MoveToFront(element)
+	}
+
+	snapshot.Unlock()
+
+	return
+}
+
+// `s3SnapshotEntries` merges the object versions and delete markers of a ListObjectVersions
+// page sorted as S3 returns them: by key and, for each key, newest first.
+func s3SnapshotEntries(s3ListObjectVersionsOutput *s3.ListObjectVersionsOutput) (entries []s3SnapshotEntryStruct) {
+	var (
+		s3DeleteMarker types.DeleteMarkerEntry
+		s3Version      types.ObjectVersion
+	)
+
+	entries = make([]s3SnapshotEntryStruct, 0, len(s3ListObjectVersionsOutput.Versions)+len(s3ListObjectVersionsOutput.DeleteMarkers))
+
+	for _, s3Version = range s3ListObjectVersionsOutput.Versions {
+		entries = append(entries, s3SnapshotEntryStruct{
+			key:       aws.ToString(s3Version.Key),
+			versionID: aws.ToString(s3Version.VersionId),
+			eTag:      strings.TrimLeft(strings.TrimRight(aws.ToString(s3Version.ETag), "\""), "\""),
+			mTime:     aws.ToTime(s3Version.LastModified),
+			size:      uint64(aws.ToInt64(s3Version.Size)),
+		})
+	}
+
+	for _, s3DeleteMarker = range s3ListObjectVersionsOutput.DeleteMarkers {
+		entries = append(entries, s3SnapshotEntryStruct{
+			key:          aws.ToString(s3DeleteMarker.Key),
+			versionID:    aws.ToString(s3DeleteMarker.VersionId),
+			deleteMarker: true,
+			mTime:        aws.ToTime(s3DeleteMarker.LastModified),
+		})
+	}
+
+	slices.SortStableFunc(entries, func(a, b s3SnapshotEntryStruct) int {
+		return cmp.Or(strings.Compare(a.key, b.key), b.mTime.Compare(a.mTime))
+	})
+
+	return
+}
+
+// `listSnapshot` fetches a page of the object versions and delete markers in bucket beneath
+// keyPrefix and reduces them to those current as of snapshot.time. For each key, that is
+// the newest version or delete marker no newer than snapshot.time. As a key's versions may
+// span pages, the continuation token records (following ListObjectVersions's key and versionId
+// markers) the last key of the page if it was already resolved so that its older versions
+// on the next page are skipped.
+func (s3Context *s3ContextStruct) listSnapshot(ctx context.Context, bucket, keyPrefix, delimiter, continuationToken, startAfter string, maxItems uint64) (page *s3SnapshotPageStruct, err error) {
+	var (
+		continuationTokenSplit     []string
+		entries                    []s3SnapshotEntryStruct
+		entry                      s3SnapshotEntryStruct
+		resolvedKey                string
+		s3CommonPrefix             types.CommonPrefix
+		s3ListObjectVersionsInput  *s3.ListObjectVersionsInput
+		s3ListObjectVersionsOutput *s3.ListObjectVersionsOutput
+		snapshot                   = s3Context.snapshot
+	)
+
+	s3ListObjectVersionsInput = &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(keyPrefix),
+	}
+	if delimiter != "" {
+		s3ListObjectVersionsInput.Delimiter = aws.String(delimiter)
+	}
+	if continuationToken != "" {
+		continuationTokenSplit = strings.SplitN(continuationToken, "\x00", 3)
+		if len(continuationTokenSplit) != 3 {
+			err = errors.New("[S3] bad snapshot continuation token")
+			return
+		}
+		s3ListObjectVersionsInput.KeyMarker = aws.String(continuationTokenSplit[0])
+		if continuationTokenSplit[1] != "" {
+			s3ListObjectVersionsInput.VersionIdMarker = aws.String(continuationTokenSplit[1])
+		}
+		resolvedKey = continuationTokenSplit[2]
+	} else if startAfter != "" {
+		s3ListObjectVersionsInput.KeyMarker = aws.String(startAfter)
+	}
+	if maxItems != 0 {
+		s3ListObjectVersionsInput.MaxKeys = aws.Int32(int32(maxItems))
+	}
+
+	s3ListObjectVersionsOutput, err = s3Context.s3Client.ListObjectVersions(ctx, s3ListObjectVersionsInput, s3Context.retryOptions(ctx))
+	if err != nil {
+		err = fmt.Errorf("[S3] ListObjectVersions failed: %v", err)
+		return
+	}
+
+	entries = s3SnapshotEntries(s3ListObjectVersionsOutput)
+
+	page = &s3SnapshotPageStruct{
+		current:        make([]s3SnapshotEntryStruct, 0, len(entries)),
+		commonPrefixes: make([]string, 0, len(s3ListObjectVersionsOutput.CommonPrefixes)),
+		isTruncated:    aws.ToBool(s3ListObjectVersionsOutput.IsTruncated),
+	}
+
+	for _, entry = range entries {
+		page.lastKey = entry.key
+
+		if (entry.key == resolvedKey) || entry.mTime.After(snapshot.time) {
+			continue
+		}
+
+		resolvedKey = entry.key
+
+		if entry.deleteMarker {
+			snapshot.remember(bucket, entry.key, "")
+		} else {
+			snapshot.remember(bucket, entry.key, entry.versionID)
+			page.current = append(page.current, entry)
+		}
+	}
+
+	for _, s3CommonPrefix = range s3ListObjectVersionsOutput.CommonPrefixes {
+		page.commonPrefixes = append(page.commonPrefixes, aws.ToString(s3CommonPrefix.Prefix))
+	}
+
+	if page.isTruncated {
+		page.nextContinuationToken = strings.Join([]string{
+			aws.ToString(s3ListObjectVersionsOutput.NextKeyMarker),
+			aws.ToString(s3ListObjectVersionsOutput.NextVersionIdMarker),
+			resolvedKey,
+		}, "\x00")
+	}
+
+	return
+}
+
+// `snapshotVersionID` returns the versionId of key in bucket current as of snapshot_time to
+// be applied to each request reading it. If snapshot_time is not set, versionID is nil. If
+// key did not exist as of snapshot_time, an errFileNotFound-wrapped error is returned.
+func (s3Context *s3ContextStruct) snapshotVersionID(ctx context.Context, bucket, key string) (versionID *string, err error) {
+	var (
+		continuationToken string
+		ok                bool
+		page              *s3SnapshotPageStruct
+		snapshot          = s3Context.snapshot
+		versionIDAsString string
+	)
+
+	if snapshot == nil {
+		return
+	}
+
+	versionIDAsString, ok = snapshot.recall(bucket, key)
+
+	for !ok {
+		page, err = s3Context.listSnapshot(ctx, bucket, key, "", continuationToken, "", 0)
+		if err != nil {
+			return
+		}
+
+		versionIDAsString, ok = snapshot.recall(bucket, key)
+		if ok {
+			break
+		}
+
+		// Keys sharing key as a prefix sort after it, so once one is seen key has no more versions
+
+		if !page.isTruncated || (page.lastKey > key) {
+			versionIDAsString = ""
+			snapshot.remember(bucket, key, versionIDAsString)
+			break
+		}
+
+		continuationToken = page.nextContinuationToken
+	}
+
+	if versionIDAsString == "" {
+		err = fmt.Errorf("%w: \"%s\" did not exist as of snapshot_time", errFileNotFound, key)
+		return
+	}
+
+	versionID = aws.String(versionIDAsString)
+
+	return
+}
+
+// `listSnapshotDirectory` implements listDirectory() of fullDirPath in bucket for an S3 backend
+// with snapshot_time set.
+func (s3Context *s3ContextStruct) listSnapshotDirectory(ctx context.Context, bucket, fullDirPath, startAfter string, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+	var (
+		commonPrefix string
+		entry        s3SnapshotEntryStruct
+		page         *s3SnapshotPageStruct
+	)
+
+	page, err = s3Context.listSnapshot(ctx, bucket, fullDirPath, "/", listDirectoryInput.continuationToken, startAfter, listDirectoryInput.maxItems)
+	if err != nil {
+		err = fmt.Errorf("[S3] listDirectory failed: %v", err)
+		return
+	}
+
+	listDirectoryOutput = &listDirectoryOutputStruct{
+		subdirectory:          make([]string, 0, len(page.commonPrefixes)),
+		file:                  make([]listDirectoryOutputFileStruct, 0, len(page.current)),
+		nextContinuationToken: page.nextContinuationToken,
+		isTruncated:           page.isTruncated,
+	}
+
+	for _, commonPrefix = range page.commonPrefixes {
+		listDirectoryOutput.subdirectory = append(listDirectoryOutput.subdirectory, strings.TrimSuffix(strings.TrimPrefix(commonPrefix, fullDirPath), "/"))
+	}
+
+	for _, entry = range page.current {
+		listDirectoryOutput.file = append(listDirectoryOutput.file, listDirectoryOutputFileStruct{
+			basename: strings.TrimPrefix(entry.key, fullDirPath),
+			eTag:     entry.eTag,
+			mTime:    entry.mTime,
+			size:     entry.size,
+		})
+	}
+
+	return
+}
+
+// `listSnapshotObjects` implements listBucketObjects() for an S3 backend with snapshot_time set.
+func (s3Context *s3ContextStruct) listSnapshotObjects(ctx context.Context, bucket string, listObjectsInput *listObjectsInputStruct) (listObjectsOutput *listObjectsOutputStruct, err error) {
+	var (
+		backend    = s3Context.backend
+		entry      s3SnapshotEntryStruct
+		page       *s3SnapshotPageStruct
+		startAfter string
+	)
+
+	if listObjectsInput.startAfter != "" {
+		startAfter = backend.prefix + listObjectsInput.startAfter
+	}
+
+	page, err = s3Context.listSnapshot(ctx, bucket, backend.prefix+listObjectsInput.prefix, "", listObjectsInput.continuationToken, startAfter, listObjectsInput.maxItems)
+	if err != nil {
+		err = fmt.Errorf("[S3] listObjects failed: %v", err)
+		return
+	}
+
+	listObjectsOutput = &listObjectsOutputStruct{
+		object:                make([]listObjectsOutputObjectStruct, 0, len(page.current)),
+		nextContinuationToken: page.nextContinuationToken,
+		isTruncated:           page.isTruncated,
+	}
+
+	for _, entry = range page.current {
+		listObjectsOutput.object = append(listObjectsOutput.object, listObjectsOutputObjectStruct{
+			path:  strings.TrimPrefix(entry.key, backend.prefix),
+			eTag:  entry.eTag,
+			mTime: entry.mTime,
+			size:  entry.size,
+		})
+	}
+
+	return
+}
+
+// `statSnapshotDirectory` implements statDirectory() of fullDirPath in bucket for an S3 backend
+// with snapshot_time set. The directory exists if any object beneath it was then current.
+// As the common prefixes of a (delimited) listing are not reduced to those containing a current
+// object, the directory's immediate subdirectories are taken at face value.
+func (s3Context *s3ContextStruct) statSnapshotDirectory(ctx context.Context, bucket, fullDirPath string) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
+	var (
+		continuationToken string
+		page              *s3SnapshotPageStruct
+	)
+
+	if fullDirPath == "" {
+		statDirectoryOutput = &statDirectoryOutputStruct{}
+		return
+	}
+
+	for {
+		page, err = s3Context.listSnapshot(ctx, bucket, fullDirPath, "/", continuationToken, "", 0)
+		if err != nil {
+			return
+		}
+
+		if (len(page.current) + len(page.commonPrefixes)) > 0 {
+			statDirectoryOutput = &statDirectoryOutputStruct{}
+			return
+		}
+
+		if !page.isTruncated {
+			err = errors.New("missing directory")
+			return
+		}
+
+		continuationToken = page.nextContinuationToken
+	}
+}
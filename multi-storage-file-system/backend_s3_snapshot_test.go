@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestS3BackendSnapshot verifies that an S3 backend with snapshot_time set presents (and reads)
+// the object versions current as of that time regardless of subsequent overwrites and deletes.
+func TestS3BackendSnapshot(t *testing.T) {
+	var (
+		backend             *backendStruct
+		continuationToken   string
+		err                 error
+		listDirectoryOutput *listDirectoryOutputStruct
+		listObjectsOutput   *listObjectsOutputStruct
+		now                 = time.Now()
+		object              listObjectsOutputObjectStruct
+		paths               []string
+		readFileOutput      *readFileOutputStruct
+		statFileOutput      *statFileOutputStruct
+	)
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	// Relative to a snapshot_time of now - 1h (with fileA, fileB, and dir1/fileC all since written):
+	//
+	//   fileA      was first written at now - 2h
+	//   fileB      was first written at now - 3h but deleted at now - 90m
+	//   dir2/fileD was first written at now - 2h and is deleted now
+
+	testGlobals.testS3Server.putObjectVersion(testFissionS3Bucket, "fileA", []byte("/fileA (old)\n"), now.Add(-2*time.Hour))
+	testGlobals.testS3Server.putObjectVersion(testFissionS3Bucket, "fileB", []byte("/fileB (old)\n"), now.Add(-3*time.Hour))
+	testGlobals.testS3Server.putDeleteMarker(testFissionS3Bucket, "fileB", now.Add(-90*time.Minute))
+	testGlobals.testS3Server.putObjectVersion(testFissionS3Bucket, "dir2/fileD", []byte("/dir2/fileD\n"), now.Add(-2*time.Hour))
+	testGlobals.testS3Server.putDeleteMarker(testFissionS3Bucket, "dir2/fileD", now)
+
+	globalsLock("backend_s3_snapshot_test.go:42:2:TestS3BackendSnapshot")
+	backend = globals.config.backends["s3"]
+	backend.context.(*s3ContextStruct).snapshot = newS3Snapshot(now.Add(-time.Hour))
+	globalsUnlock()
+
+	listDirectoryOutput, err = backend.context.listDirectory(context.Background(), &listDirectoryInputStruct{dirPath: ""})
+	if err != nil {
+		t.Fatalf("listDirectory(\"\") failed: %v", err)
+	}
+	if !slices.Equal(listDirectoryOutput.subdirectory, []string{"dir1", "dir2"}) || (len(listDirectoryOutput.file) != 1) || (listDirectoryOutput.file[0].basename != "fileA") || (listDirectoryOutput.file[0].size != uint64(len("/fileA (old)\n"))) {
+		t.Fatalf("listDirectory(\"\") returned unexpected content: %#v", listDirectoryOutput)
+	}
+
+	statFileOutput, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "fileA"})
+	if err != nil {
+		t.Fatalf("statFile(\"fileA\") failed: %v", err)
+	}
+	if statFileOutput.size != uint64(len("/fileA (old)\n")) {
+		t.Fatalf("statFile(\"fileA\") returned size %d", statFileOutput.size)
+	}
+
+	readFileOutput, err = backend.context.readFile(context.Background(), &readFileInputStruct{filePath: "fileA", offset: 0, length: statFileOutput.size})
+	if err != nil {
+		t.Fatalf("readFile(\"fileA\") failed: %v", err)
+	}
+	if string(readFileOutput.buf) != "/fileA (old)\n" {
+		t.Fatalf("readFile(\"fileA\") returned %q", readFileOutput.buf)
+	}
+
+	readFileOutput, err = backend.context.readFile(context.Background(), &readFileInputStruct{filePath: "dir2/fileD", offset: 0, length: uint64(len("/dir2/fileD\n"))})
+	if err != nil {
+		t.Fatalf("readFile(\"dir2/fileD\") failed: %v", err)
+	}
+	if string(readFileOutput.buf) != "/dir2/fileD\n" {
+		t.Fatalf("readFile(\"dir2/fileD\") returned %q", readFileOutput.buf)
+	}
+
+	// Neither the deleted fileB nor the since written dir1/fileC (nor, thus, dir1) exist
+
+	_, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "fileB"})
+	if !errors.Is(err, errFileNotFound) {
+		t.Fatalf("statFile(\"fileB\") should have failed with errFileNotFound (err: %v)", err)
+	}
+
+	_, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "dir1/fileC"})
+	if !errors.Is(err, errFileNotFound) {
+		t.Fatalf("statFile(\"dir1/fileC\") should have failed with errFileNotFound (err: %v)", err)
+	}
+
+	_, err = backend.context.statDirectory(context.Background(), &statDirectoryInputStruct{dirPath: "dir1/"})
+	if err == nil {
+		t.Fatalf("statDirectory(\"dir1/\") should have failed")
+	}
+
+	_, err = backend.context.statDirectory(context.Background(), &statDirectoryInputStruct{dirPath: "dir2/"})
+	if err != nil {
+		t.Fatalf("statDirectory(\"dir2/\") failed: %v", err)
+	}
+
+	// listObjects() pages (one version at a time) yield only the current versions
+
+	for {
+		listObjectsOutput, err = backend.context.listObjects(context.Background(), &listObjectsInputStruct{continuationToken: continuationToken, maxItems: 1})
+		if err != nil {
+			t.Fatalf("listObjects(continuationToken:\"%s\") failed: %v", continuationToken, err)
+		}
+		for _, object = range listObjectsOutput.object {
+			paths = append(paths, object.path)
+		}
+		if !listObjectsOutput.isTruncated {
+			break
+		}
+		continuationToken = listObjectsOutput.nextContinuationToken
+	}
+
+	if !slices.Equal(paths, []string{"dir2/fileD", "fileA"}) {
+		t.Fatalf("listObjects() returned paths %v", paths)
+	}
+
+	_, err = backend.context.putFile(context.Background(), &putFileInputStruct{filePath: "fileA", buf: []byte("/fileA (new)\n")})
+	if !errors.Is(err, errS3SnapshotReadOnly) {
+		t.Fatalf("putFile(\"fileA\") should have failed with errS3SnapshotReadOnly (err: %v)", err)
+	}
+}
+
+// TestS3SnapshotVersionIDsBounded verifies that an s3SnapshotStruct forgets its least recently
+// used resolved versionIds once more than maxVersionIDs are remembered.
+func TestS3SnapshotVersionIDsBounded(t *testing.T) {
+	var (
+		ok        bool
+		snapshot  = newS3Snapshot(time.Now())
+		versionID string
+	)
+
+	snapshot.maxVersionIDs = 2
+
+	snapshot.remember("bucket", "a", "1")
+	snapshot.remember("bucket", "b", "")
+	_, _ = snapshot.recall("bucket", "a")
+	snapshot.remember("bucket", "c", "3")
+
+	if _, ok = snapshot.recall("bucket", "b"); ok {
+		t.Fatalf("recall(\"b\") should have been forgotten as least recently used")
+	}
+	if versionID, ok = snapshot.recall("bucket", "a"); !ok || (versionID != "1") {
+		t.Fatalf("recall(\"a\") returned %q, %v (expected \"1\", true)", versionID, ok)
+	}
+	if versionID, ok = snapshot.recall("bucket", "c"); !ok || (versionID != "3") {
+		t.Fatalf("recall(\"c\") returned %q, %v (expected \"3\", true)", versionID, ok)
+	}
+	if (len(snapshot.versionIDs) != 2) || (snapshot.versionIDLRU.Len() != 2) {
+		t.Fatalf("snapshot retains %v versionIDs and %v LRU elements (expected 2)", len(snapshot.versionIDs), snapshot.versionIDLRU.Len())
+	}
+}
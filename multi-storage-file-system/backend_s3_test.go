@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// `testS3ResponseError` returns an awshttp.ResponseError for a response of statusCode
+// carrying, if != "", the supplied Retry-After header.
+func testS3ResponseError(statusCode int, retryAfter string) error {
+	var (
+		header = http.Header{}
+	)
+
+	if retryAfter != "" {
+		header.Set("Retry-After", retryAfter)
+	}
+
+	return &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: statusCode, Header: header}},
+			Err:      errors.New("http error"),
+		},
+	}
+}
+
+func TestS3RetryAfter(t *testing.T) {
+	var (
+		now        = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+		ok         bool
+		retryAfter time.Duration
+	)
+
+	if _, ok = s3RetryAfter(errors.New("not an HTTP error"), now); ok {
+		t.Fatalf("s3RetryAfter() of a non-HTTP error should have returned !ok")
+	}
+	if _, ok = s3RetryAfter(testS3ResponseError(503, ""), now); ok {
+		t.Fatalf("s3RetryAfter() without a Retry-After header should have returned !ok")
+	}
+	if _, ok = s3RetryAfter(testS3ResponseError(503, "soon"), now); ok {
+		t.Fatalf("s3RetryAfter() of a malformed Retry-After header should have returned !ok")
+	}
+
+	retryAfter, ok = s3RetryAfter(testS3ResponseError(429, "3"), now)
+	if !ok || (retryAfter != 3*time.Second) {
+		t.Fatalf("s3RetryAfter() of Retry-After: 3 returned (%v, %v)", retryAfter, ok)
+	}
+
+	retryAfter, ok = s3RetryAfter(testS3ResponseError(503, now.Add(5*time.Second).Format(http.TimeFormat)), now)
+	if !ok || (retryAfter != 5*time.Second) {
+		t.Fatalf("s3RetryAfter() of an HTTP-date Retry-After returned (%v, %v)", retryAfter, ok)
+	}
+}
+
+func TestS3RetryDelay(t *testing.T) {
+	var (
+		backend = &backendStruct{
+			backendTypeSpecifics: &backendConfigS3Struct{
+				retryMaxDelay: 2 * time.Second,
+				retryDelay:    []time.Duration{10 * time.Millisecond, 20 * time.Millisecond},
+			},
+		}
+		err            error
+		lastHTTPStatus int
+		recordedDelay  time.Duration
+		retryDelay     time.Duration
+		retryHistory   *retryHistoryStruct
+		s3Retryer      *s3RetryerStruct
+	)
+
+	if _, err = backend.RetryDelay(3, nil); err == nil {
+		t.Fatalf("RetryDelay() beyond MaxAttempts() should have failed")
+	}
+
+	// Full jitter never exceeds the configured delay
+
+	for range 100 {
+		retryDelay, err = backend.RetryDelay(2, testS3ResponseError(503, ""))
+		if (err != nil) || (retryDelay < 0) || (retryDelay > 20*time.Millisecond) {
+			t.Fatalf("RetryDelay(2) returned (%v, %v)", retryDelay, err)
+		}
+	}
+
+	// Retry-After is honored up to retry_max_delay
+
+	retryDelay, _ = backend.RetryDelay(1, testS3ResponseError(503, "1"))
+	if retryDelay != time.Second {
+		t.Fatalf("RetryDelay() with Retry-After: 1 returned %v", retryDelay)
+	}
+
+	retryDelay, _ = backend.RetryDelay(1, testS3ResponseError(429, "60"))
+	if retryDelay != 2*time.Second {
+		t.Fatalf("RetryDelay() with Retry-After: 60 should have been limited to retry_max_delay but returned %v", retryDelay)
+	}
+
+	// The per-request s3RetryerStruct records each chosen delay
+
+	retryHistory = &retryHistoryStruct{}
+	s3Retryer = &s3RetryerStruct{backendStruct: backend, retryHistory: retryHistory}
+
+	retryDelay, _ = s3Retryer.RetryDelay(1, testS3ResponseError(503, "1"))
+
+	_, recordedDelay, lastHTTPStatus = retryHistory.snapshot()
+	if (recordedDelay != retryDelay) || (lastHTTPStatus != 503) {
+		t.Fatalf("s3RetryerStruct.RetryDelay() recorded (%v, %v) but should have recorded (%v, 503)", recordedDelay, lastHTTPStatus, retryDelay)
+	}
+}
+
+// `testS3WriteClientCert` writes a self-signed client certificate (usable as its own CA) and
+// its private key as PEM files in dirPath, returning the parsed certificate.
+func testS3WriteClientCert(t *testing.T, dirPath string) (clientCert *x509.Certificate, clientCertFile, clientKeyFile string) {
+	var (
+		certDER     []byte
+		certificate = &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "msfs-test-client"},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+		err        error
+		keyDER     []byte
+		privateKey *ecdsa.PrivateKey
+	)
+
+	privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() failed: %v", err)
+	}
+
+	certDER, err = x509.CreateCertificate(rand.Reader, certificate, certificate, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate() failed: %v", err)
+	}
+
+	clientCert, err = x509.ParseCertificate(certDER)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate() failed: %v", err)
+	}
+
+	keyDER, err = x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey() failed: %v", err)
+	}
+
+	clientCertFile = filepath.Join(dirPath, "client.crt")
+	clientKeyFile = filepath.Join(dirPath, "client.key")
+
+	err = os.WriteFile(clientCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile(clientCertFile) failed: %v", err)
+	}
+	err = os.WriteFile(clientKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile(clientKeyFile) failed: %v", err)
+	}
+
+	return
+}
+
+// TestS3BackendMutualTLS verifies that an S3 endpoint presenting a certificate from a private
+// PKI and requiring a client certificate is reachable only via ca_bundle_file and
+// client_cert_file/client_key_file.
+func TestS3BackendMutualTLS(t *testing.T) {
+	var (
+		caBundleFile   string
+		clientCAs      = x509.NewCertPool()
+		clientCert     *x509.Certificate
+		clientCertFile string
+		clientKeyFile  string
+		dirPath        = t.TempDir()
+		err            error
+		tlsServer      *httptest.Server
+	)
+
+	clientCert, clientCertFile, clientKeyFile = testS3WriteClientCert(t, dirPath)
+	clientCAs.AddCert(clientCert)
+
+	testGlobals.testS3Server.reset()
+	defer testGlobals.testS3Server.reset()
+	testGlobals.testS3Server.putObject("test", "fileA", []byte("/fileA\n"))
+
+	tlsServer = httptest.NewUnstartedServer(testGlobals.testS3Server)
+	tlsServer.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+
+	caBundleFile = filepath.Join(dirPath, "ca.pem")
+	err = os.WriteFile(caBundleFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw}), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile(caBundleFile) failed: %v", err)
+	}
+
+	statFileA := func(caBundleFile, clientCertFile, clientKeyFile string) (err error) {
+		var (
+			backend = &backendStruct{
+				dirName:             "s3",
+				bucketContainerName: "test",
+				backendType:         "S3",
+				backendTypeSpecifics: &backendConfigS3Struct{
+					region:          "us-east-1",
+					endpoint:        tlsServer.URL,
+					accessKeyID:     "test",
+					secretAccessKey: "test",
+					caBundleFile:    caBundleFile,
+					clientCertFile:  clientCertFile,
+					clientKeyFile:   clientKeyFile,
+					retryDelay:      []time.Duration{},
+				},
+			}
+		)
+
+		err = backend.setupS3Context()
+		if err != nil {
+			t.Fatalf("setupS3Context() failed: %v", err)
+		}
+
+		_, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "fileA"})
+
+		return
+	}
+
+	err = statFileA(caBundleFile, clientCertFile, clientKeyFile)
+	if err != nil {
+		t.Fatalf("statFile(\"fileA\") with ca_bundle_file and client certificate failed: %v", err)
+	}
+
+	err = statFileA("", clientCertFile, clientKeyFile)
+	if err == nil {
+		t.Fatalf("statFile(\"fileA\") without ca_bundle_file should have failed to verify the server certificate")
+	}
+
+	err = statFileA(caBundleFile, "", "")
+	if err == nil {
+		t.Fatalf("statFile(\"fileA\") without a client certificate should have been refused")
+	}
+
+	_, err = newBackendTLSConfig(false, clientKeyFile, "", "")
+	if err == nil {
+		t.Fatalf("newBackendTLSConfig() should have rejected a ca_bundle_file lacking certificates")
+	}
+}
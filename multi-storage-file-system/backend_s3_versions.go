@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// `s3VersionsDirSuffix` is appended to a file's basename to name the synthetic directory
+// presenting each of its versions when S3.expose_versions is set.
+const s3VersionsDirSuffix = ".versions"
+
+// `errS3VersionsReadOnly` is returned by any attempt to modify a file within a "<name>.versions/" directory.
+var errS3VersionsReadOnly = errors.New("[S3] object versions are read-only")
+
+// `versionsPath` reports whether path (relative to backend.prefix) is a "<name>.versions/"
+// directory (in which case versionID is returned as "") or a "<name>.versions/<versionId>"
+// file within one. In either case, filePath is that of "<name>". Unless S3.expose_versions is
+// set, ok is always false.
+func (s3Context *s3ContextStruct) versionsPath(path string) (filePath string, versionID string, ok bool) {
+	var (
+		dirPath    string
+		slashIndex int
+	)
+
+	if !s3Context.backend.backendTypeSpecifics.(*backendConfigS3Struct).exposeVersions {
+		return
+	}
+
+	if strings.HasSuffix(path, "/") {
+		dirPath = strings.TrimSuffix(path, "/")
+	} else {
+		slashIndex = strings.LastIndex(path, "/")
+		if slashIndex < 0 {
+			return
+		}
+		dirPath = path[:slashIndex]
+		versionID = path[slashIndex+1:]
+	}
+
+	if !strings.HasSuffix(dirPath, s3VersionsDirSuffix) {
+		versionID = ""
+		return
+	}
+
+	filePath = strings.TrimSuffix(dirPath, s3VersionsDirSuffix)
+	if (filePath == "") || strings.HasSuffix(filePath, "/") {
+		filePath = ""
+		versionID = ""
+		return
+	}
+
+	ok = true
+
+	return
+}
+
+// `resolveFile` maps the file at path (relative to backend.prefix) to the bucket, object key,
+// and (if any) versionId to read. That versionId is either the one named by a path within a
+// "<name>.versions/" directory or, if snapshot_time is set, the one current as of then.
+func (s3Context *s3ContextStruct) resolveFile(ctx context.Context, path string) (bucket string, key string, versionID *string, err error) {
+	var (
+		filePath          string
+		isVersion         bool
+		versionIDAsString string
+	)
+
+	filePath, versionIDAsString, isVersion = s3Context.versionsPath(path)
+	if isVersion && (versionIDAsString != "") {
+		bucket, key, err = s3Context.bucketAndKey(ctx, filePath)
+		if err == nil {
+			versionID = aws.String(versionIDAsString)
+		}
+		return
+	}
+
+	bucket, key, err = s3Context.bucketAndKey(ctx, path)
+	if err != nil {
+		return
+	}
+
+	versionID, err = s3Context.snapshotVersionID(ctx, bucket, key)
+
+	return
+}
+
+// `inVersionsDir` reports whether objectPath (relative to backend.prefix) names, or lies within,
+// a "<name>.versions/" directory of an S3 backend with S3.expose_versions set. FUSE ops that would
+// modify such a path thus fail with EROFS up front rather than upon reaching the backend (see
+// rejectVersionsPath()).
+func (backend *backendStruct) inVersionsDir(objectPath string) bool {
+	var (
+		ok         bool
+		s3Context  *s3ContextStruct
+		slashIndex int
+	)
+
+	s3Context, ok = unwrapBackendContext(backend.context).(*s3ContextStruct)
+	if !ok || !backend.backendTypeSpecifics.(*backendConfigS3Struct).exposeVersions {
+		return false
+	}
+
+	if !strings.HasSuffix(objectPath, "/") {
+		objectPath += "/"
+	}
+
+	for objectPath != "" {
+		_, _, ok = s3Context.versionsPath(objectPath)
+		if ok {
+			return true
+		}
+
+		slashIndex = strings.LastIndex(strings.TrimSuffix(objectPath, "/"), "/")
+		objectPath = objectPath[:slashIndex+1]
+	}
+
+	return false
+}
+
+// `rejectVersionsPath` returns errS3VersionsReadOnly if any of paths is within a "<name>.versions/" directory.
+func (s3Context *s3ContextStruct) rejectVersionsPath(paths ...string) (err error) {
+	var (
+		isVersion bool
+		path      string
+	)
+
+	for _, path = range paths {
+		_, _, isVersion = s3Context.versionsPath(path)
+		if isVersion {
+			err = errS3VersionsReadOnly
+			return
+		}
+	}
+
+	return
+}
+
+// `listFileVersions` returns the versions (excluding delete markers) of key in bucket. If
+// snapshot_time is set, versions newer than it are excluded.
+func (s3Context *s3ContextStruct) listFileVersions(ctx context.Context, bucket, key string) (versions []s3SnapshotEntryStruct, err error) {
+	var (
+		entries                    []s3SnapshotEntryStruct
+		entry                      s3SnapshotEntryStruct
+		s3ListObjectVersionsInput  *s3.ListObjectVersionsInput
+		s3ListObjectVersionsOutput *s3.ListObjectVersionsOutput
+	)
+
+	versions = make([]s3SnapshotEntryStruct, 0)
+
+	s3ListObjectVersionsInput = &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(key),
+	}
+
+	for {
+		s3ListObjectVersionsOutput, err = s3Context.s3Client.ListObjectVersions(ctx, s3ListObjectVersionsInput, s3Context.retryOptions(ctx))
+		if err != nil {
+			err = fmt.Errorf("[S3] ListObjectVersions failed: %v", err)
+			return
+		}
+
+		entries = s3SnapshotEntries(s3ListObjectVersionsOutput)
+
+		for _, entry = range entries {
+			if (entry.key == key) && !entry.deleteMarker && ((s3Context.snapshot == nil) || !entry.mTime.After(s3Context.snapshot.time)) {
+				versions = append(versions, entry)
+			}
+		}
+
+		// Keys sharing key as a prefix sort after it, so once one is seen key has no more versions
+
+		if !aws.ToBool(s3ListObjectVersionsOutput.IsTruncated) || ((len(entries) > 0) && (entries[len(entries)-1].key > key)) {
+			return
+		}
+
+		s3ListObjectVersionsInput.KeyMarker = s3ListObjectVersionsOutput.NextKeyMarker
+		s3ListObjectVersionsInput.VersionIdMarker = s3ListObjectVersionsOutput.NextVersionIdMarker
+	}
+}
+
+// `listVersionsDirectory` implements listDirectory() of the "<filePath>.versions/" directory at
+// dirPath by returning (in a single page) each version of filePath as a file named by its versionId.
+func (s3Context *s3ContextStruct) listVersionsDirectory(ctx context.Context, dirPath, filePath string, listDirectoryInput *listDirectoryInputStruct) (listDirectoryOutput *listDirectoryOutputStruct, err error) {
+	var (
+		bucket   string
+		key      string
+		version  s3SnapshotEntryStruct
+		versions []s3SnapshotEntryStruct
+	)
+
+	listDirectoryOutput = &listDirectoryOutputStruct{
+		subdirectory:          make([]string, 0),
+		file:                  make([]listDirectoryOutputFileStruct, 0),
+		nextContinuationToken: "",
+		isTruncated:           false,
+	}
+
+	if listDirectoryInput.continuationToken != "" {
+		// Since all versions were returned in the first page, there are no more
+		return
+	}
+
+	bucket, key, err = s3Context.bucketAndKey(ctx, filePath)
+	if err != nil {
+		listDirectoryOutput = nil
+		return
+	}
+
+	versions, err = s3Context.listFileVersions(ctx, bucket, key)
+	if err != nil {
+		listDirectoryOutput = nil
+		err = fmt.Errorf("[S3] listDirectory failed: %v", err)
+		return
+	}
+
+	slices.SortFunc(versions, func(a, b s3SnapshotEntryStruct) int {
+		return strings.Compare(a.versionID, b.versionID)
+	})
+
+	for _, version = range versions {
+		if (dirPath + version.versionID) > listDirectoryInput.startAfter {
+			listDirectoryOutput.file = append(listDirectoryOutput.file, listDirectoryOutputFileStruct{
+				basename: version.versionID,
+				eTag:     version.eTag,
+				mTime:    version.mTime,
+				size:     version.size,
+			})
+		}
+	}
+
+	return
+}
+
+// `statVersionsDirectory` implements statDirectory() of the "<filePath>.versions/" directory
+// which exists if filePath has at least one version.
+func (s3Context *s3ContextStruct) statVersionsDirectory(ctx context.Context, filePath string) (statDirectoryOutput *statDirectoryOutputStruct, err error) {
+	var (
+		bucket   string
+		key      string
+		versions []s3SnapshotEntryStruct
+	)
+
+	bucket, key, err = s3Context.bucketAndKey(ctx, filePath)
+	if err != nil {
+		return
+	}
+
+	versions, err = s3Context.listFileVersions(ctx, bucket, key)
+	if err != nil {
+		return
+	}
+
+	if len(versions) == 0 {
+		err = errors.New("missing directory")
+		return
+	}
+
+	statDirectoryOutput = &statDirectoryOutputStruct{}
+
+	return
+}
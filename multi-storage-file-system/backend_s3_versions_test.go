@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+// TestS3BackendExposeVersions verifies that, with S3.expose_versions set, each version of a
+// file is presented (read-only) within the synthetic "<name>.versions/" directory.
+func TestS3BackendExposeVersions(t *testing.T) {
+	var (
+		backend             *backendStruct
+		err                 error
+		errno               syscall.Errno
+		file                listDirectoryOutputFileStruct
+		fileAVersionsIno    uint64
+		listDirectoryOutput *listDirectoryOutputStruct
+		oldVersionID        string
+		openOut             *fission.OpenOut
+		readFileOutput      *readFileOutputStruct
+		readOut             *fission.ReadOut
+		s3DirIno            uint64
+		statFileOutput      *statFileOutputStruct
+		versionIno          uint64
+	)
+
+	fissionS3TestUpWithS3Settings(t, `
+					"expose_versions": true,`)
+	defer fissionS3TestDown(t)
+
+	testGlobals.testS3Server.putObject(testFissionS3Bucket, "fileA", []byte("/fileA (new)\n"))
+
+	backend = globals.config.backends["s3"]
+
+	listDirectoryOutput, err = backend.context.listDirectory(context.Background(), &listDirectoryInputStruct{dirPath: "fileA.versions/"})
+	if err != nil {
+		t.Fatalf("listDirectory(\"fileA.versions/\") failed: %v", err)
+	}
+	if (len(listDirectoryOutput.subdirectory) != 0) || (len(listDirectoryOutput.file) != 2) || listDirectoryOutput.isTruncated {
+		t.Fatalf("listDirectory(\"fileA.versions/\") returned unexpected content: %#v", listDirectoryOutput)
+	}
+	for _, file = range listDirectoryOutput.file {
+		if file.size == uint64(len("/fileA\n")) {
+			oldVersionID = file.basename
+		}
+	}
+	if oldVersionID == "" {
+		t.Fatalf("listDirectory(\"fileA.versions/\") did not return the original version of fileA")
+	}
+
+	statFileOutput, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "fileA.versions/" + oldVersionID})
+	if (err != nil) || (statFileOutput.size != uint64(len("/fileA\n"))) {
+		t.Fatalf("statFile(\"fileA.versions/%s\") returned unexpected result (err: %v)", oldVersionID, err)
+	}
+
+	readFileOutput, err = backend.context.readFile(context.Background(), &readFileInputStruct{filePath: "fileA.versions/" + oldVersionID, offset: 0, length: statFileOutput.size})
+	if (err != nil) || (string(readFileOutput.buf) != "/fileA\n") {
+		t.Fatalf("readFile(\"fileA.versions/%s\") returned unexpected result (err: %v)", oldVersionID, err)
+	}
+
+	// The latest version remains presented as fileA itself
+
+	readFileOutput, err = backend.context.readFile(context.Background(), &readFileInputStruct{filePath: "fileA", offset: 0, length: uint64(len("/fileA (new)\n"))})
+	if (err != nil) || (string(readFileOutput.buf) != "/fileA (new)\n") {
+		t.Fatalf("readFile(\"fileA\") returned unexpected result (err: %v)", err)
+	}
+
+	_, err = backend.context.statDirectory(context.Background(), &statDirectoryInputStruct{dirPath: "fileX.versions/"})
+	if err == nil {
+		t.Fatalf("statDirectory(\"fileX.versions/\") should have failed")
+	}
+
+	_, err = backend.context.putFile(context.Background(), &putFileInputStruct{filePath: "fileA.versions/" + oldVersionID, buf: []byte("overwritten\n")})
+	if !errors.Is(err, errS3VersionsReadOnly) {
+		t.Fatalf("putFile(\"fileA.versions/%s\") should have failed with errS3VersionsReadOnly (err: %v)", oldVersionID, err)
+	}
+
+	_, err = backend.context.deleteFile(context.Background(), &deleteFileInputStruct{filePath: "fileA.versions/" + oldVersionID})
+	if !errors.Is(err, errS3VersionsReadOnly) {
+		t.Fatalf("deleteFile(\"fileA.versions/%s\") should have failed with errS3VersionsReadOnly (err: %v)", oldVersionID, err)
+	}
+
+	// The "<name>.versions" directory and its files may be looked up and read via the mount
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+	fileAVersionsIno = fissionS3TestLookup(t, s3DirIno, "fileA.versions")
+	versionIno = fissionS3TestLookup(t, fileAVersionsIno, oldVersionID)
+
+	openOut, errno = globals.DoOpen(&fission.InHeader{NodeID: versionIno}, &fission.OpenIn{Flags: fission.FOpenRequestRDONLY})
+	if errno != 0 {
+		t.Fatalf("DoOpen(versionIno) unexpectedly failed (errno: %v)", errno)
+	}
+
+	readOut, errno = globals.DoRead(&fission.InHeader{NodeID: versionIno}, &fission.ReadIn{FH: openOut.FH, Offset: 0, Size: testFissionReadBufSize})
+	if (errno != 0) || (string(readOut.Data) != "/fileA\n") {
+		t.Fatalf("DoRead(versionIno) returned unexpected result (errno: %v)", errno)
+	}
+
+	errno = globals.DoRelease(&fission.InHeader{NodeID: versionIno}, &fission.ReleaseIn{FH: openOut.FH})
+	if errno != 0 {
+		t.Fatalf("DoRelease(versionIno) unexpectedly failed (errno: %v)", errno)
+	}
+
+	// ...but attempts to modify them via the mount fail up front with EROFS
+
+	_, errno = globals.DoCreate(&fission.InHeader{NodeID: fileAVersionsIno}, &fission.CreateIn{Name: []byte("newVersion")})
+	if errno != syscall.EROFS {
+		t.Fatalf("DoCreate(fileAVersionsIno) returned errno %v (expected: EROFS)", errno)
+	}
+
+	_, errno = globals.DoMkDir(&fission.InHeader{NodeID: fileAVersionsIno}, &fission.MkDirIn{Name: []byte("newDir")})
+	if errno != syscall.EROFS {
+		t.Fatalf("DoMkDir(fileAVersionsIno) returned errno %v (expected: EROFS)", errno)
+	}
+
+	_, errno = globals.DoMkDir(&fission.InHeader{NodeID: s3DirIno}, &fission.MkDirIn{Name: []byte("fileB.versions")})
+	if errno != syscall.EROFS {
+		t.Fatalf("DoMkDir(s3DirIno,\"fileB.versions\") returned errno %v (expected: EROFS)", errno)
+	}
+
+	_, errno = globals.DoSetAttr(&fission.InHeader{NodeID: versionIno}, &fission.SetAttrIn{})
+	if errno != syscall.EROFS {
+		t.Fatalf("DoSetAttr(versionIno) returned errno %v (expected: EROFS)", errno)
+	}
+
+	errno = globals.DoRename(&fission.InHeader{NodeID: fileAVersionsIno}, &fission.RenameIn{NewDir: s3DirIno, OldName: []byte(oldVersionID), NewName: []byte("fileC")})
+	if errno != syscall.EROFS {
+		t.Fatalf("DoRename(fileAVersionsIno,%q) returned errno %v (expected: EROFS)", oldVersionID, errno)
+	}
+
+	errno = globals.DoRename(&fission.InHeader{NodeID: s3DirIno}, &fission.RenameIn{NewDir: fileAVersionsIno, OldName: []byte("fileA"), NewName: []byte("fileC")})
+	if errno != syscall.EROFS {
+		t.Fatalf("DoRename(s3DirIno,\"fileA\") into fileAVersionsIno returned errno %v (expected: EROFS)", errno)
+	}
+}
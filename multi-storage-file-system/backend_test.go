@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestBackendRequestSlots(t *testing.T) {
+	var (
+		acquired = make(chan struct{})
+		backend  = &backendStruct{maxConcurrentRequests: 2}
+		cancel   context.CancelFunc
+		ctx      context.Context
+		err      error
+	)
+
+	// An unlimited backend never blocks
+
+	if err = (&backendStruct{}).acquireRequestSlot(context.Background()); err != nil {
+		t.Fatalf("acquireRequestSlot() of an unlimited backend unexpectedly failed: %v", err)
+	}
+
+	backend.requestSlots = make(chan struct{}, backend.maxConcurrentRequests)
+
+	for range backend.maxConcurrentRequests {
+		if err = backend.acquireRequestSlot(context.Background()); err != nil {
+			t.Fatalf("acquireRequestSlot() unexpectedly failed: %v", err)
+		}
+	}
+
+	// With every slot taken, acquisition honors ctx
+
+	ctx, cancel = withBackendOpTimeout(context.Background(), 10*time.Millisecond)
+	err = backend.acquireRequestSlot(ctx)
+	cancel()
+	if !errors.Is(err, errBackendOpTimedOut) {
+		t.Fatalf("acquireRequestSlot() should have failed with errBackendOpTimedOut but returned: %v", err)
+	}
+
+	// ...and otherwise awaits a releaseRequestSlot()
+
+	go func() {
+		_ = backend.acquireRequestSlot(context.Background())
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("acquireRequestSlot() should have blocked")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	backend.releaseRequestSlot()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatalf("acquireRequestSlot() should have proceeded following releaseRequestSlot()")
+	}
+}
+
+func TestBackendProxyFunc(t *testing.T) {
+	var (
+		err       error
+		proxyFunc func(*http.Request) (*url.URL, error)
+		proxyURL  *url.URL
+		req       *http.Request
+	)
+
+	for _, envName := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy", "REQUEST_METHOD"} {
+		t.Setenv(envName, "")
+	}
+
+	if newBackendProxyFunc("", "") != nil {
+		t.Fatalf("newBackendProxyFunc(\"\", \"\") should have returned nil")
+	}
+
+	proxyFunc = newBackendProxyFunc("http://proxy.example.com:3128", ".example.org")
+
+	req, _ = http.NewRequest(http.MethodGet, "https://bucket.example.com/object", nil)
+	proxyURL, err = proxyFunc(req)
+	if err != nil {
+		t.Fatalf("proxyFunc() unexpectedly failed: %v", err)
+	}
+	if (proxyURL == nil) || (proxyURL.Host != "proxy.example.com:3128") {
+		t.Fatalf("proxyFunc() returned %v but expected proxy.example.com:3128", proxyURL)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://bucket.example.org/object", nil)
+	proxyURL, err = proxyFunc(req)
+	if err != nil {
+		t.Fatalf("proxyFunc() unexpectedly failed: %v", err)
+	}
+	if proxyURL != nil {
+		t.Fatalf("proxyFunc() returned %v but no_proxy should have bypassed the proxy", proxyURL)
+	}
+}
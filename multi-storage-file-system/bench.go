@@ -0,0 +1,503 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	benchPatternRandom     = "random"     // Each read is of a random readSize-aligned range of a random file
+	benchPatternSequential = "sequential" // Each worker reads whole files (in readSize chunks) in turn
+
+	defaultBenchConcurrency = 16
+	defaultBenchDuration    = 30 * time.Second
+	defaultBenchFiles       = uint64(64)
+
+	benchMountTimeout   = 60 * time.Second // Time allowed for the temporary mount to appear (or disappear)
+	benchMountPollDelay = 100 * time.Millisecond
+)
+
+// `benchFileStruct` describes each file read by a benchmark workload.
+type benchFileStruct struct {
+	path string // Relative to backend.prefix
+	size uint64
+}
+
+// `benchConfigStruct` holds the parsed "bench" subcommand options.
+type benchConfigStruct struct {
+	backend     *backendStruct
+	files       []*benchFileStruct
+	concurrency int
+	duration    time.Duration
+	pattern     string // One of benchPatternRandom or benchPatternSequential
+	readSize    uint64
+}
+
+// `benchResultStruct` summarizes a benchmark workload run.
+type benchResultStruct struct {
+	reads     uint64
+	bytes     uint64
+	elapsed   time.Duration
+	latencies []time.Duration // Sorted
+	errors    uint64
+	firstErr  error
+}
+
+// `benchReadFunc` reads up to length bytes at offset of file returning the number read.
+type benchReadFunc func(file *benchFileStruct, offset uint64, length uint64) (n uint64, err error)
+
+// `runBench` handles the "bench" subcommand.
+// It drives a synthetic read workload against the files under a prefix of the specified
+// backend directly via its backendContextIf and, if --mount is specified, through a
+// temporary FUSE mount of the config-file (served by a child daemon) reporting throughput,
+// p50/p99 latency, and (for the mount) the data cache hit ratio so that cache_line_size and
+// cache_lines may be sized empirically.
+func runBench(osArgs []string) {
+	var (
+		args        []string
+		benchConfig = &benchConfigStruct{}
+		err         error
+		fs          = flag.NewFlagSet("bench", flag.ExitOnError)
+		maxFiles    = fs.Uint64("files", defaultBenchFiles, "maximum number of (non-empty) files under the prefix to read")
+		mount       = fs.Bool("mount", false, "also run the workload through a temporary mount")
+		prefix      string
+		result      *benchResultStruct
+	)
+
+	fs.IntVar(&benchConfig.concurrency, "concurrency", defaultBenchConcurrency, "number of concurrent readers")
+	fs.DurationVar(&benchConfig.duration, "duration", defaultBenchDuration, "duration of each workload run")
+	fs.StringVar(&benchConfig.pattern, "pattern", benchPatternSequential, "read pattern (\""+benchPatternSequential+"\" or \""+benchPatternRandom+"\")")
+	fs.Uint64Var(&benchConfig.readSize, "read-size", 0, "size of each read (default: cache_line_size)")
+
+	args = cliParseArgs(osArgs, fs, "[--files N] [--concurrency N] [--duration D] [--pattern {sequential|random}] [--read-size B] [--mount] <backend>[/<prefix>] [<config-file>]", 1)
+
+	if ((benchConfig.pattern != benchPatternSequential) && (benchConfig.pattern != benchPatternRandom)) || (benchConfig.concurrency < 1) || (benchConfig.duration <= 0) || (*maxFiles == 0) {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if benchConfig.readSize == 0 {
+		benchConfig.readSize = globals.config.cacheLineSize
+	}
+
+	benchConfig.backend, prefix = cliBackend(args[0])
+
+	benchConfig.files, err = benchListFiles(benchConfig.backend, prefix, *maxFiles)
+	if err != nil {
+		globals.logger.Fatalf("[FATAL] bench listing of \"%s\" failed: %s", args[0], redactSecrets(benchConfig.backend, err.Error()))
+	}
+	if len(benchConfig.files) == 0 {
+		globals.logger.Fatalf("[FATAL] bench found no non-empty files under \"%s\"", args[0])
+	}
+
+	fmt.Printf("bench: %d files, %d readers, %s reads (%s), %v per run\n", len(benchConfig.files), benchConfig.concurrency, formatBytes(benchConfig.readSize), benchConfig.pattern, benchConfig.duration)
+
+	result = benchConfig.run(benchConfig.backendRead)
+	result.report("backend", "")
+
+	if *mount {
+		err = benchConfig.runMount(globals.configFilePath)
+		if err != nil {
+			globals.logger.Fatalf("[FATAL] bench through a temporary mount failed: %s", redactSecrets(benchConfig.backend, err.Error()))
+		}
+	}
+
+	os.Exit(0)
+}
+
+// `benchListFiles` returns up to maxFiles non-empty files under prefix (relative to backend.prefix).
+func benchListFiles(backend *backendStruct, prefix string, maxFiles uint64) (files []*benchFileStruct, err error) {
+	var (
+		listObjectsInput  = &listObjectsInputStruct{prefix: prefix}
+		listObjectsOutput *listObjectsOutputStruct
+		object            listObjectsOutputObjectStruct
+	)
+
+	for {
+		listObjectsOutput, err = listObjectsWrapper(context.Background(), backend.context, listObjectsInput)
+		if err != nil {
+			return
+		}
+
+		for _, object = range listObjectsOutput.object {
+			if (object.size == 0) || strings.HasSuffix(object.path, "/") {
+				continue
+			}
+			files = append(files, &benchFileStruct{path: object.path, size: object.size})
+			if uint64(len(files)) == maxFiles {
+				return
+			}
+		}
+
+		if !listObjectsOutput.isTruncated || (listObjectsOutput.nextContinuationToken == "") {
+			return
+		}
+
+		listObjectsInput.continuationToken = listObjectsOutput.nextContinuationToken
+	}
+}
+
+// `backendRead` is the benchReadFunc reading directly from the backend.
+func (benchConfig *benchConfigStruct) backendRead(file *benchFileStruct, offset uint64, length uint64) (n uint64, err error) {
+	var (
+		readFileOutput *readFileOutputStruct
+	)
+
+	readFileOutput, err = readFileWrapper(context.Background(), benchConfig.backend.context, &readFileInputStruct{
+		filePath: file.path,
+		offset:   offset,
+		length:   length,
+	})
+	if err == nil {
+		n = uint64(len(readFileOutput.buf))
+	}
+
+	return
+}
+
+// `run` drives the configured workload (for benchConfig.duration) issuing each read via read.
+func (benchConfig *benchConfigStruct) run(read benchReadFunc) (result *benchResultStruct) {
+	var (
+		deadline  time.Time
+		mutex     sync.Mutex
+		startTime time.Time
+		worker    int
+		workerWG  sync.WaitGroup
+	)
+
+	result = &benchResultStruct{}
+
+	startTime = time.Now()
+	deadline = startTime.Add(benchConfig.duration)
+
+	for worker = range benchConfig.concurrency {
+		workerWG.Add(1)
+		go func(worker int) {
+			var (
+				bytes     uint64
+				err       error
+				errs      uint64
+				file      *benchFileStruct
+				fileIndex = worker % len(benchConfig.files)
+				firstErr  error
+				latencies []time.Duration
+				n         uint64
+				offset    uint64
+				readStart time.Time
+				reads     uint64
+			)
+
+			defer workerWG.Done()
+
+			file = benchConfig.files[fileIndex]
+
+			for time.Now().Before(deadline) {
+				if benchConfig.pattern == benchPatternRandom {
+					file = benchConfig.files[rand.IntN(len(benchConfig.files))]
+					offset = rand.Uint64N(((file.size-1)/benchConfig.readSize)+1) * benchConfig.readSize
+				} else if offset >= file.size {
+					fileIndex = (fileIndex + benchConfig.concurrency) % len(benchConfig.files)
+					file = benchConfig.files[fileIndex]
+					offset = 0
+				}
+
+				readStart = time.Now()
+				n, err = read(file, offset, min(benchConfig.readSize, file.size-offset))
+				if err == nil {
+					latencies = append(latencies, time.Since(readStart))
+					reads++
+					bytes += n
+				} else {
+					errs++
+					if firstErr == nil {
+						firstErr = err
+					}
+				}
+
+				offset += benchConfig.readSize
+			}
+
+			mutex.Lock()
+			result.latencies = append(result.latencies, latencies...)
+			result.reads += reads
+			result.bytes += bytes
+			result.errors += errs
+			if result.firstErr == nil {
+				result.firstErr = firstErr
+			}
+			mutex.Unlock()
+		}(worker)
+	}
+
+	workerWG.Wait()
+
+	result.elapsed = time.Since(startTime)
+
+	slices.Sort(result.latencies)
+
+	return
+}
+
+// `percentile` returns the p-th (0.0 < p < 1.0) percentile of the result's read latencies.
+func (result *benchResultStruct) percentile(p float64) time.Duration {
+	if len(result.latencies) == 0 {
+		return 0
+	}
+
+	return result.latencies[int(p*float64(len(result.latencies)-1))]
+}
+
+// `report` outputs a line summarizing the result labeled by label and followed by suffix.
+func (result *benchResultStruct) report(label string, suffix string) {
+	fmt.Printf("%-8s %d reads, %s in %v: %s/s, p50 %v, p99 %v%s\n",
+		label+":",
+		result.reads,
+		formatBytes(result.bytes),
+		result.elapsed.Round(time.Millisecond),
+		formatBytes(uint64(float64(result.bytes)/result.elapsed.Seconds())),
+		result.percentile(0.50).Round(time.Microsecond),
+		result.percentile(0.99).Round(time.Microsecond),
+		suffix)
+
+	if result.errors > 0 {
+		fmt.Printf("%-8s %d reads failed (first: %s)\n", "", result.errors, redactSecrets(nil, result.firstErr.Error()))
+	}
+}
+
+// `runMount` runs the configured workload through a temporary mount of configFilePath served
+// by a child daemon (this same binary) with an HTTP endpoint from which the backend's data
+// cache hit ratio is obtained. Note that the temporary mount directory is only ever removed
+// with os.Remove() so that a mount failing to unmount is never recursively deleted through.
+func (benchConfig *benchConfigStruct) runMount(configFilePath string) (err error) {
+	var (
+		cacheHitsAfter    float64
+		cacheHitsBefore   float64
+		cacheMissesAfter  float64
+		cacheMissesBefore float64
+		cmd               *exec.Cmd
+		cmdDone           = make(chan error, 1)
+		endpoint          string
+		executable        string
+		listener          net.Listener
+		logFile           *os.File
+		mountPoint        string
+		overrides         []string
+		result            *benchResultStruct
+		suffix            string
+		tempDir           string
+	)
+
+	executable, err = os.Executable()
+	if err != nil {
+		return
+	}
+
+	tempDir, err = os.MkdirTemp("", "msfs-bench-")
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = os.Remove(tempDir)
+	}()
+
+	mountPoint = filepath.Join(tempDir, "mnt")
+	err = os.Mkdir(mountPoint, 0o700)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = os.Remove(mountPoint)
+	}()
+
+	logFile, err = os.Create(filepath.Join(tempDir, "msfs.log"))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = logFile.Close()
+		if err == nil {
+			_ = os.Remove(logFile.Name())
+		} else {
+			err = fmt.Errorf("%v (see \"%s\")", err, logFile.Name())
+		}
+	}()
+
+	// Select an unused port for the child daemon's HTTP endpoint
+
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return
+	}
+	endpoint = "http://" + listener.Addr().String()
+	_ = listener.Close()
+
+	overrides = []string{"endpoint=" + endpoint}
+	if globals.config.adminListen != "" {
+		overrides = append(overrides, "admin_listen=") // Avoid colliding with an already running daemon
+	}
+	if os.Getenv(EnvMSFSConfigOverrides) != "" {
+		overrides = append([]string{os.Getenv(EnvMSFSConfigOverrides)}, overrides...)
+	}
+
+	cmd = exec.Command(executable, configFilePath)
+	cmd.Env = append(os.Environ(), EnvMSFSMountPoint+"="+mountPoint, EnvMSFSConfigOverrides+"="+strings.Join(overrides, ","))
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	err = cmd.Start()
+	if err != nil {
+		return
+	}
+
+	go func() {
+		cmdDone <- cmd.Wait()
+	}()
+
+	defer func() {
+		_ = cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-cmdDone:
+		case <-time.After(benchMountTimeout):
+			_ = cmd.Process.Kill()
+			<-cmdDone
+		}
+	}()
+
+	err = benchWaitForMount(filepath.Join(mountPoint, benchConfig.backend.dirName), cmdDone)
+	if err != nil {
+		return
+	}
+
+	cacheHitsBefore, cacheMissesBefore, err = benchCacheCounters(endpoint, benchConfig.backend.dirName)
+	if err != nil {
+		return
+	}
+
+	result = benchConfig.run(func(file *benchFileStruct, offset uint64, length uint64) (n uint64, err error) {
+		var (
+			buf        = make([]byte, length)
+			nAsInt     int
+			osFile     *os.File
+			osFilePath = filepath.Join(mountPoint, benchConfig.backend.dirName, file.path)
+		)
+
+		osFile, err = os.Open(osFilePath)
+		if err != nil {
+			return
+		}
+
+		nAsInt, err = osFile.ReadAt(buf, int64(offset))
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+		n = uint64(nAsInt)
+
+		_ = osFile.Close()
+
+		return
+	})
+
+	cacheHitsAfter, cacheMissesAfter, err = benchCacheCounters(endpoint, benchConfig.backend.dirName)
+	if err == nil {
+		cacheHitsAfter -= cacheHitsBefore
+		cacheMissesAfter -= cacheMissesBefore
+		if (cacheHitsAfter + cacheMissesAfter) > 0 {
+			suffix = fmt.Sprintf(", cache hit ratio %.1f%% (%.0f hits, %.0f misses)", 100*cacheHitsAfter/(cacheHitsAfter+cacheMissesAfter), cacheHitsAfter, cacheMissesAfter)
+		}
+	}
+
+	result.report("mount", suffix)
+
+	err = nil
+
+	return
+}
+
+// `benchWaitForMount` waits for backendDirPath to appear (i.e. for the child daemon to have
+// mounted the file system) or for the child daemon to exit.
+func benchWaitForMount(backendDirPath string, cmdDone chan error) (err error) {
+	var (
+		deadline = time.Now().Add(benchMountTimeout)
+	)
+
+	for time.Now().Before(deadline) {
+		select {
+		case err = <-cmdDone:
+			cmdDone <- err
+			err = fmt.Errorf("daemon exited before mounting: %v", err)
+			return
+		default:
+		}
+
+		if _, err = os.Stat(backendDirPath); err == nil {
+			return
+		}
+
+		time.Sleep(benchMountPollDelay)
+	}
+
+	err = fmt.Errorf("timed out awaiting mount of \"%s\"", backendDirPath)
+
+	return
+}
+
+// `benchCacheCounters` scrapes the data cache hit and miss counters of backend dirName from
+// the Prometheus metrics served at endpoint.
+func benchCacheCounters(endpoint string, dirName string) (hits float64, misses float64, err error) {
+	var (
+		line     string
+		name     string
+		response *http.Response
+		scanner  *bufio.Scanner
+		value    string
+	)
+
+	response, err = http.Get(endpoint + "/metrics/" + dirName)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("GET %s/metrics/%s returned %s", endpoint, dirName, response.Status)
+		return
+	}
+
+	scanner = bufio.NewScanner(response.Body)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+		name, value, _ = strings.Cut(line, " ")
+		switch name {
+		case "fission_read_cache_hits_total":
+			hits, err = strconv.ParseFloat(value, 64)
+		case "fission_read_cache_misses_total":
+			misses, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	err = scanner.Err()
+
+	return
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestBenchRun verifies that a bench workload reads the listed files of a backend
+// and that its latencies are summarized.
+func TestBenchRun(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    readonly: false,
+    backend_type: RAM,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	backend, prefix := cliBackend("ram/bench")
+
+	for i := range 4 {
+		_, err = putFileWrapper(context.Background(), backend.context, &putFileInputStruct{filePath: fmt.Sprintf("bench/file%d", i), buf: make([]byte, 10000)})
+		if err != nil {
+			t.Fatalf("putFileWrapper() failed: %v", err)
+		}
+	}
+
+	files, err := benchListFiles(backend, prefix, 3)
+	if err != nil {
+		t.Fatalf("benchListFiles() failed: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("benchListFiles() returned %v files, expected 3", len(files))
+	}
+
+	for _, pattern := range []string{benchPatternSequential, benchPatternRandom} {
+		benchConfig := &benchConfigStruct{
+			backend:     backend,
+			files:       files,
+			concurrency: 2,
+			duration:    100 * time.Millisecond,
+			pattern:     pattern,
+			readSize:    4096,
+		}
+
+		result := benchConfig.run(benchConfig.backendRead)
+		if result.errors != 0 {
+			t.Fatalf("run() [%s] encountered %v errors (first: %v)", pattern, result.errors, result.firstErr)
+		}
+		if (result.reads == 0) || (uint64(len(result.latencies)) != result.reads) {
+			t.Fatalf("run() [%s] returned %v reads with %v latencies", pattern, result.reads, len(result.latencies))
+		}
+		if result.bytes > (result.reads * benchConfig.readSize) {
+			t.Fatalf("run() [%s] returned %v bytes for %v reads", pattern, result.bytes, result.reads)
+		}
+		if result.percentile(0.50) > result.percentile(0.99) {
+			t.Fatalf("run() [%s] returned p50 (%v) > p99 (%v)", pattern, result.percentile(0.50), result.percentile(0.99))
+		}
+	}
+}
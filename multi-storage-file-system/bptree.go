@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/NVIDIA/sortedmap"
@@ -402,6 +403,7 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) PackValue(
 
 	packedValueLen = 0 +
 		8 + //                              inodeNumber
+		8 + //                              generation
 		4 + //                              inodeType
 		8 + //                              backendNonce
 		8 + //                              parentInodeNumber
@@ -413,7 +415,11 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) PackValue(
 		8 + len(eTagAsByteSlice) + //       eTag
 		4 + //                              mode
 		8 + //                              mTime
+		8 + //                              times.aTime
+		8 + //                              times.bTime
+		8 + //                              times.cTime
 		8 + //                              xTime
+		8 + //                              vTime
 		1 + //                              isPrefetchInProgress
 		8 + (cacheMapLen * (8 + 8)) + //    cacheMap
 		8 + //                              inboundCacheLineCount
@@ -429,6 +435,9 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) PackValue(
 	binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], inode.inodeNumber)
 	packedValuePos += 8
 
+	binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], inode.generation)
+	packedValuePos += 8
+
 	binary.BigEndian.PutUint32(packedValue[packedValuePos:packedValuePos+4], inode.inodeType)
 	packedValuePos += 4
 
@@ -476,6 +485,27 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) PackValue(
 	}
 	packedValuePos += 8
 
+	if inode.times.aTime.IsZero() {
+		binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(0))
+	} else {
+		binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(inode.times.aTime.UnixNano()))
+	}
+	packedValuePos += 8
+
+	if inode.times.bTime.IsZero() {
+		binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(0))
+	} else {
+		binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(inode.times.bTime.UnixNano()))
+	}
+	packedValuePos += 8
+
+	if inode.times.cTime.IsZero() {
+		binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(0))
+	} else {
+		binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(inode.times.cTime.UnixNano()))
+	}
+	packedValuePos += 8
+
 	if inode.xTime.IsZero() {
 		binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(0))
 	} else {
@@ -483,6 +513,13 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) PackValue(
 	}
 	packedValuePos += 8
 
+	if inode.vTime.IsZero() {
+		binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(0))
+	} else {
+		binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(inode.vTime.UnixNano()))
+	}
+	packedValuePos += 8
+
 	if inode.isPrefetchInProgress {
 		packedValue[packedValuePos] = 1
 	} else {
@@ -534,7 +571,10 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) PackValue(
 // `UnpackValue` is here to satisfy sortedmap.BPlusTreeCallbacks interface for inodeNumberToInodeStructMapStruct.
 func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) UnpackValue(payloadData []byte) (value sortedmap.Value, bytesConsumed uint64, err error) {
 	var (
+		aTimeAsUint64            uint64
+		bTimeAsUint64            uint64
 		basenameAsByteSliceLen   uint64
+		cTimeAsUint64            uint64
 		cacheMapElementKey       uint64
 		cacheMapElementValue     uint64
 		cacheMapLen              uint64
@@ -544,6 +584,7 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) UnpackValu
 		inode                    *inodeStruct
 		mTimeAsUint64            uint64
 		objectPathAsByteSliceLen uint64
+		vTimeAsUint64            uint64
 		xTimeAsUint64            uint64
 	)
 
@@ -558,6 +599,13 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) UnpackValu
 	inode.inodeNumber = binary.BigEndian.Uint64(payloadData[bytesConsumed : bytesConsumed+8])
 	bytesConsumed += 8
 
+	if uint64(len(payloadData)) < (bytesConsumed + 8) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .generation", len(payloadData))
+		return
+	}
+	inode.generation = binary.BigEndian.Uint64(payloadData[bytesConsumed : bytesConsumed+8])
+	bytesConsumed += 8
+
 	if uint64(len(payloadData)) < (bytesConsumed + 4) {
 		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .inodeType", len(payloadData))
 		return
@@ -658,6 +706,42 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) UnpackValu
 		inode.mTime = time.Unix(0, int64(mTimeAsUint64))
 	}
 
+	if uint64(len(payloadData)) < (bytesConsumed + 8) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .times.aTime", len(payloadData))
+		return
+	}
+	aTimeAsUint64 = binary.BigEndian.Uint64(payloadData[bytesConsumed : bytesConsumed+8])
+	bytesConsumed += 8
+	if aTimeAsUint64 == 0 {
+		inode.times.aTime = time.Time{}
+	} else {
+		inode.times.aTime = time.Unix(0, int64(aTimeAsUint64))
+	}
+
+	if uint64(len(payloadData)) < (bytesConsumed + 8) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .times.bTime", len(payloadData))
+		return
+	}
+	bTimeAsUint64 = binary.BigEndian.Uint64(payloadData[bytesConsumed : bytesConsumed+8])
+	bytesConsumed += 8
+	if bTimeAsUint64 == 0 {
+		inode.times.bTime = time.Time{}
+	} else {
+		inode.times.bTime = time.Unix(0, int64(bTimeAsUint64))
+	}
+
+	if uint64(len(payloadData)) < (bytesConsumed + 8) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .times.cTime", len(payloadData))
+		return
+	}
+	cTimeAsUint64 = binary.BigEndian.Uint64(payloadData[bytesConsumed : bytesConsumed+8])
+	bytesConsumed += 8
+	if cTimeAsUint64 == 0 {
+		inode.times.cTime = time.Time{}
+	} else {
+		inode.times.cTime = time.Unix(0, int64(cTimeAsUint64))
+	}
+
 	if uint64(len(payloadData)) < (bytesConsumed + 8) {
 		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .xTime", len(payloadData))
 		return
@@ -670,6 +754,18 @@ func (inodeNumberToInodeStructMap *inodeNumberToInodeStructMapStruct) UnpackValu
 		inode.xTime = time.Unix(0, int64(xTimeAsUint64))
 	}
 
+	if uint64(len(payloadData)) < (bytesConsumed + 8) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .vTime", len(payloadData))
+		return
+	}
+	vTimeAsUint64 = binary.BigEndian.Uint64(payloadData[bytesConsumed : bytesConsumed+8])
+	bytesConsumed += 8
+	if vTimeAsUint64 == 0 {
+		inode.vTime = time.Time{}
+	} else {
+		inode.vTime = time.Unix(0, int64(vTimeAsUint64))
+	}
+
 	if uint64(len(payloadData)) < (bytesConsumed + 1) {
 		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .isPrefetchInProgress", len(payloadData))
 		return
@@ -1047,6 +1143,355 @@ func (xTimeInodeNumberSet *xTimeInodeNumberSetStruct) UnpackValue(payloadData []
 	return
 }
 
+// `inodeNumberToInodeHandleMapStruct` is used to maintain a sortedmap.BPlusTree used
+// to map an inodeStruct.inodeNumber to the inodeHandleStruct from which an evicted
+// inode may be re-materialized. Unlike globals.inodeMap, entries are retained after
+// the inode is evicted (until pruned by pruneInodeHandle()). An instance of this struct is used to provide globals.inodeHandleMap
+// functionality. As inodes may be created without holding globals.Lock() (see
+// manifest_ingest.go), access is serialized by the embedded sync.Mutex.
+type inodeNumberToInodeHandleMapStruct struct {
+	sync.Mutex
+	name                  string
+	bpTree                sortedmap.BPlusTree // Key: inodeStruct.inodeNumber; Value: inodeHandleStruct
+	bpTreeCache           sortedmap.BPlusTreeCache
+	pageDirtyFlushTrigger uint64
+	flushesSinceLastGC    uint64
+	flushesSinceLastGCMax uint64
+}
+
+// `inodeNumberToInodeHandleMapStructCreate` is called to instantiate a `inodeNumberToInodeHandleMapStruct`.
+func inodeNumberToInodeHandleMapStructCreate(name string, maxKeysPerNode, evictLowLimit, evictHighLimit, pageDirtyFlushTrigger, flushesSinceLastGCMax uint64) (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) {
+	inodeNumberToInodeHandleMap = &inodeNumberToInodeHandleMapStruct{
+		name:                  name,
+		bpTreeCache:           sortedmap.NewBPlusTreeCache(evictLowLimit, evictHighLimit),
+		pageDirtyFlushTrigger: pageDirtyFlushTrigger,
+		flushesSinceLastGC:    0,
+		flushesSinceLastGCMax: flushesSinceLastGCMax,
+	}
+	inodeNumberToInodeHandleMap.bpTree = sortedmap.NewBPlusTree(maxKeysPerNode, sortedmap.CompareUint64, inodeNumberToInodeHandleMap, inodeNumberToInodeHandleMap.bpTreeCache)
+	return
+}
+
+// `discard` is called to discard a `inodeNumberToInodeHandleMapStruct`.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) discard() {
+	var (
+		err error
+	)
+
+	inodeNumberToInodeHandleMap.Lock()
+	defer inodeNumberToInodeHandleMap.Unlock()
+
+	err = inodeNumberToInodeHandleMap.bpTree.Discard()
+	if err != nil {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] inodeNumberToInodeHandleMap.bpTree.Discard() failed: %v", err)
+	}
+}
+
+// `delete` is called to remove the inodeHandleStruct (if any) for an `inodeNumber` from a `inodeNumberToInodeHandleMapStruct`.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) delete(inodeNumber uint64) (ok bool) {
+	var (
+		err error
+	)
+
+	inodeNumberToInodeHandleMap.Lock()
+	defer inodeNumberToInodeHandleMap.Unlock()
+
+	ok, err = inodeNumberToInodeHandleMap.bpTree.DeleteByKey(inodeNumber)
+	if err != nil {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] inodeNumberToInodeHandleMap.bpTree.DeleteByKey(inodeNumber) failed: %v", err)
+	}
+
+	inodeNumberToInodeHandleMap.flushIfNecessary()
+
+	return
+}
+
+// `get` is called to, given an `inodeNumber`, fetch an inodeHandleStruct from a `inodeNumberToInodeHandleMapStruct`.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) get(inodeNumber uint64) (inodeHandle inodeHandleStruct, ok bool) {
+	var (
+		err                error
+		inodeHandleAsValue sortedmap.Value
+	)
+
+	inodeNumberToInodeHandleMap.Lock()
+	defer inodeNumberToInodeHandleMap.Unlock()
+
+	inodeHandleAsValue, ok, err = inodeNumberToInodeHandleMap.bpTree.GetByKey(inodeNumber)
+	if err != nil {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] inodeNumberToInodeHandleMap.bpTree.GetByKey(inodeNumber) failed: %v", err)
+	}
+
+	if ok {
+		inodeHandle, ok = inodeHandleAsValue.(inodeHandleStruct)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] inodeHandleAsValue.(inodeHandleStruct) returned !ok")
+		}
+	}
+
+	return
+}
+
+// `put` is called to add (or replace) the `inodeHandle` for an `inodeNumber` in a `inodeNumberToInodeHandleMapStruct`.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) put(inodeNumber uint64, inodeHandle inodeHandleStruct) {
+	var (
+		err error
+		ok  bool
+	)
+
+	inodeNumberToInodeHandleMap.Lock()
+	defer inodeNumberToInodeHandleMap.Unlock()
+
+	ok, err = inodeNumberToInodeHandleMap.bpTree.Put(inodeNumber, inodeHandle)
+	if err != nil {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] inodeNumberToInodeHandleMap.bpTree.Put(inodeNumber, inodeHandle) failed: %v", err)
+	}
+
+	if !ok {
+		ok, err = inodeNumberToInodeHandleMap.bpTree.PatchByKey(inodeNumber, inodeHandle)
+		if err != nil {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] inodeNumberToInodeHandleMap.bpTree.PatchByKey(inodeNumber, inodeHandle) failed: %v", err)
+		}
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] inodeNumberToInodeHandleMap.bpTree.PatchByKey(inodeNumber, inodeHandle) returned !ok")
+		}
+	}
+
+	inodeNumberToInodeHandleMap.flushIfNecessary()
+}
+
+// `flushIfNecessary` will track the number of updates (one is assumed per call to this function)
+// and use the configured updates per flush to decide when to trigger a flush.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) flushIfNecessary() {
+	var (
+		bpTreeCacheStats *sortedmap.BPlusTreeCacheStats
+		err              error
+	)
+
+	bpTreeCacheStats = inodeNumberToInodeHandleMap.bpTreeCache.Stats()
+
+	if bpTreeCacheStats.DirtyLRUItems > inodeNumberToInodeHandleMap.pageDirtyFlushTrigger {
+		_, _, _, err = inodeNumberToInodeHandleMap.bpTree.Flush(false)
+		if err != nil {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] inodeNumberToInodeHandleMap.bpTree.Flush(false) failed: %v", err)
+		}
+		err = inodeNumberToInodeHandleMap.bpTree.Prune()
+		if err != nil {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] inodeNumberToInodeHandleMap.bpTree.Prune() failed: %v", err)
+		}
+
+		if inodeNumberToInodeHandleMap.flushesSinceLastGCMax > 0 {
+			inodeNumberToInodeHandleMap.flushesSinceLastGC++
+
+			if inodeNumberToInodeHandleMap.flushesSinceLastGC >= inodeNumberToInodeHandleMap.flushesSinceLastGCMax {
+				runtime.GC()
+
+				inodeNumberToInodeHandleMap.flushesSinceLastGC = 0
+			}
+		}
+	}
+}
+
+// `DumpKey` is here to satisfy sortedmap.DumpCallbacks interface for inodeNumberToInodeHandleMapStruct.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) DumpKey(key sortedmap.Key) (keyAsString string, err error) {
+	var (
+		keyAsU64 uint64
+		ok       bool
+	)
+
+	keyAsU64, ok = key.(uint64)
+	if !ok {
+		err = errors.New("key.(uint64) returned !ok")
+		return
+	}
+
+	keyAsString = fmt.Sprintf("%016X", keyAsU64)
+
+	err = nil
+	return
+}
+
+// `DumpValue` is here to satisfy sortedmap.DumpCallbacks interface for inodeNumberToInodeHandleMapStruct.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) DumpValue(value sortedmap.Value) (valueAsString string, err error) {
+	var (
+		inodeHandle inodeHandleStruct
+		ok          bool
+	)
+
+	inodeHandle, ok = value.(inodeHandleStruct)
+	if !ok {
+		err = errors.New("value.(inodeHandleStruct) returned !ok")
+		return
+	}
+
+	valueAsString = fmt.Sprintf("%#v", inodeHandle)
+
+	err = nil
+	return
+}
+
+// `GetNode` is here to satisfy sortedmap.BPlusTreeCallbacks interface for inodeNumberToInodeHandleMapStruct.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) GetNode(objectNumber, objectOffset, objectLength uint64) (nodeByteSlice []byte, err error) {
+	if objectOffset != 0 {
+		err = fmt.Errorf("objectOffset(%v) != 0", objectOffset)
+		return
+	}
+	nodeByteSlice, err = bptreePages.get(objectNumber, objectLength)
+	return
+}
+
+// `PutNode` is here to satisfy sortedmap.BPlusTreeCallbacks interface for inodeNumberToInodeHandleMapStruct.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) PutNode(nodeByteSlice []byte) (objectNumber, objectOffset uint64, err error) {
+	objectNumber = fetchNonce()
+	objectOffset = 0
+	err = bptreePages.put(objectNumber, nodeByteSlice)
+	return
+}
+
+// `DiscardNode` is here to satisfy sortedmap.BPlusTreeCallbacks interface for inodeNumberToInodeHandleMapStruct.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) DiscardNode(objectNumber, objectOffset, objectLength uint64) (err error) {
+	if objectOffset != 0 {
+		err = fmt.Errorf("objectOffset(%v) != 0", objectOffset)
+		return
+	}
+	err = bptreePages.delete(objectNumber)
+	return
+}
+
+// `PackKey` is here to satisfy sortedmap.BPlusTreeCallbacks interface for inodeNumberToInodeHandleMapStruct.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) PackKey(key sortedmap.Key) (packedKey []byte, err error) {
+	var (
+		keyAsU64 uint64
+		ok       bool
+	)
+
+	keyAsU64, ok = key.(uint64)
+	if !ok {
+		err = errors.New("key.(uint64) returned !ok")
+		return
+	}
+
+	packedKey = make([]byte, 8)
+
+	binary.BigEndian.PutUint64(packedKey, keyAsU64)
+
+	err = nil
+	return
+}
+
+// `UnpackKey` is here to satisfy sortedmap.BPlusTreeCallbacks interface for inodeNumberToInodeHandleMapStruct.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) UnpackKey(payloadData []byte) (key sortedmap.Key, bytesConsumed uint64, err error) {
+	if len(payloadData) < 8 {
+		err = errors.New("len(payloadData) < 8")
+		return
+	}
+
+	key = binary.BigEndian.Uint64(payloadData[:8])
+	bytesConsumed = 8
+
+	err = nil
+	return
+}
+
+// `PackValue` is here to satisfy sortedmap.BPlusTreeCallbacks interface for inodeNumberToInodeHandleMapStruct.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) PackValue(value sortedmap.Value) (packedValue []byte, err error) {
+	var (
+		dirNameAsByteSlice    []byte
+		inodeHandle           inodeHandleStruct
+		objectPathAsByteSlice []byte
+		ok                    bool
+		packedValuePos        int
+	)
+
+	inodeHandle, ok = value.(inodeHandleStruct)
+	if !ok {
+		err = errors.New("value.(inodeHandleStruct) returned !ok")
+		return
+	}
+
+	dirNameAsByteSlice = []byte(inodeHandle.dirName)
+	objectPathAsByteSlice = []byte(inodeHandle.objectPath)
+
+	packedValue = make([]byte, 0+
+		8+len(dirNameAsByteSlice)+ //    dirName
+		8+len(objectPathAsByteSlice)+ // objectPath
+		8) //                            generation
+
+	packedValuePos = 0
+
+	binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(len(dirNameAsByteSlice)))
+	packedValuePos += 8
+	copy(packedValue[packedValuePos:packedValuePos+len(dirNameAsByteSlice)], dirNameAsByteSlice)
+	packedValuePos += len(dirNameAsByteSlice)
+
+	binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], uint64(len(objectPathAsByteSlice)))
+	packedValuePos += 8
+	copy(packedValue[packedValuePos:packedValuePos+len(objectPathAsByteSlice)], objectPathAsByteSlice)
+	packedValuePos += len(objectPathAsByteSlice)
+
+	binary.BigEndian.PutUint64(packedValue[packedValuePos:packedValuePos+8], inodeHandle.generation)
+
+	err = nil
+	return
+}
+
+// `UnpackValue` is here to satisfy sortedmap.BPlusTreeCallbacks interface for inodeNumberToInodeHandleMapStruct.
+func (inodeNumberToInodeHandleMap *inodeNumberToInodeHandleMapStruct) UnpackValue(payloadData []byte) (value sortedmap.Value, bytesConsumed uint64, err error) {
+	var (
+		dirNameAsByteSliceLen    uint64
+		inodeHandle              inodeHandleStruct
+		objectPathAsByteSliceLen uint64
+	)
+
+	bytesConsumed = 0
+
+	if uint64(len(payloadData)) < (bytesConsumed + 8) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .dirName [case 1]", len(payloadData))
+		return
+	}
+	dirNameAsByteSliceLen = binary.BigEndian.Uint64(payloadData[bytesConsumed : bytesConsumed+8])
+	bytesConsumed += 8
+	if uint64(len(payloadData)) < (bytesConsumed + dirNameAsByteSliceLen) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .dirName [case 2]", len(payloadData))
+		return
+	}
+	inodeHandle.dirName = string(payloadData[bytesConsumed : bytesConsumed+dirNameAsByteSliceLen])
+	bytesConsumed += dirNameAsByteSliceLen
+
+	if uint64(len(payloadData)) < (bytesConsumed + 8) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .objectPath [case 1]", len(payloadData))
+		return
+	}
+	objectPathAsByteSliceLen = binary.BigEndian.Uint64(payloadData[bytesConsumed : bytesConsumed+8])
+	bytesConsumed += 8
+	if uint64(len(payloadData)) < (bytesConsumed + objectPathAsByteSliceLen) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .objectPath [case 2]", len(payloadData))
+		return
+	}
+	inodeHandle.objectPath = string(payloadData[bytesConsumed : bytesConsumed+objectPathAsByteSliceLen])
+	bytesConsumed += objectPathAsByteSliceLen
+
+	if uint64(len(payloadData)) < (bytesConsumed + 8) {
+		err = fmt.Errorf("len(payloadData) [%v] insufficient to decode .generation", len(payloadData))
+		return
+	}
+	inodeHandle.generation = binary.BigEndian.Uint64(payloadData[bytesConsumed : bytesConsumed+8])
+	bytesConsumed += 8
+
+	value = inodeHandle
+
+	err = nil
+	return
+}
+
 // `parentInodeNumberChildBasenameToChildInodeNumberStruct` is used to maintain a
 // sortedmap.BPlusTree used to map the tuple made up of a parent inodeStruct.inodeNumber
 // and a child inodeStruct.basename to the child inodeStruct.inodeNumber. An instance of
@@ -1581,16 +2026,29 @@ func (m *shardedDirEntryMap) getFlushesSinceLastGCMax() uint64 {
 }
 
 // --- Sharded inodeMap ---
+//
+// Note that each shard's mutex protects that shard's B+Tree such that get() may be called by
+// any number of goroutines holding globals only shared (see globalsRLock()). An *inodeStruct
+// returned by get() is only stable while globals is held as, once released, a shard may evict
+// (pack) it and later return a freshly unpacked copy. Hence inodeStruct fields may only be
+// modified while holding globals exclusively. Shared holders instead record the updates they
+// would have made (inode and cache line touches) with deferTouch() and deferCacheLineTouch()
+// under only the inode's shard mutex. These are keyed by inode number (or cache line position)
+// rather than held in the inodeStruct so they survive such eviction. The next exclusive holder
+// applies them (see applyDeferredTouches()).
 
 const inodeMapShardCount = 64
 
 type inodeMapShard struct {
-	mu   sync.Mutex
-	tree *inodeNumberToInodeStructMapStruct
+	mu                  sync.Mutex
+	tree                *inodeNumberToInodeStructMapStruct
+	deferredTouches     map[uint64]time.Time // Key: inodeNumber touched while holding globals only shared; Value: latest read time to apply to .times.aTime (or zero)
+	deferredLineTouches map[uint64]uint64    // Key: dataCacheLineTrackerStruct.pos touched while holding globals only shared; Value: its .contentGeneration at that time
 }
 
 type shardedInodeMap struct {
-	shards [inodeMapShardCount]inodeMapShard
+	shards          [inodeMapShardCount]inodeMapShard
+	deferredPending atomic.Bool // Set once any shard has deferred touches not yet taken by takeDeferredTouches()
 }
 
 func newShardedInodeMap(namePrefix string, maxKeysPerNode, evictLowLimit, evictHighLimit, pageDirtyFlushTrigger, flushesSinceLastGCMax uint64) *shardedInodeMap {
@@ -1599,6 +2057,8 @@ func newShardedInodeMap(namePrefix string, maxKeysPerNode, evictLowLimit, evictH
 		name := fmt.Sprintf("%s.shard%02d", namePrefix, i)
 		m.shards[i].tree = inodeNumberToInodeStructMapStructCreate(
 			name, maxKeysPerNode, evictLowLimit, evictHighLimit, pageDirtyFlushTrigger, flushesSinceLastGCMax)
+		m.shards[i].deferredTouches = make(map[uint64]time.Time)
+		m.shards[i].deferredLineTouches = make(map[uint64]uint64)
 	}
 	return m
 }
@@ -1631,6 +2091,65 @@ func (m *shardedInodeMap) touch(inode *inodeStruct) (ok bool) {
 	return
 }
 
+// `deferTouch` records that inodeNumber should be touched (and, if aTime is not zero,
+// have its .times.aTime advanced to aTime) by the next holder of globals exclusively.
+func (m *shardedInodeMap) deferTouch(inodeNumber uint64, aTime time.Time) {
+	s := m.shardFor(inodeNumber)
+	s.mu.Lock()
+	if prevATime, ok := s.deferredTouches[inodeNumber]; !ok || aTime.After(prevATime) {
+		s.deferredTouches[inodeNumber] = aTime
+	}
+	s.mu.Unlock()
+	m.deferredPending.Store(true)
+}
+
+// `deferCacheLineTouch` records that the data cache line at dataCacheLinePos, if its content is
+// still at contentGeneration, should be touched by the next holder of globals exclusively. It is
+// recorded in the shard of inodeNumber (the inode whose content the line holds).
+func (m *shardedInodeMap) deferCacheLineTouch(inodeNumber, dataCacheLinePos, contentGeneration uint64) {
+	s := m.shardFor(inodeNumber)
+	s.mu.Lock()
+	s.deferredLineTouches[dataCacheLinePos] = contentGeneration
+	s.mu.Unlock()
+	m.deferredPending.Store(true)
+}
+
+// `deferredATime` returns the latest read time recorded for inodeNumber by deferTouch()
+// but not yet applied to its .times.aTime (or zero if there is none).
+func (m *shardedInodeMap) deferredATime(inodeNumber uint64) (aTime time.Time) {
+	if !m.deferredPending.Load() {
+		return
+	}
+	s := m.shardFor(inodeNumber)
+	s.mu.Lock()
+	aTime = s.deferredTouches[inodeNumber]
+	s.mu.Unlock()
+	return
+}
+
+// `takeDeferredTouches` returns (and forgets) the touches recorded in every shard by
+// deferTouch() and deferCacheLineTouch(). The caller must hold globals exclusively.
+func (m *shardedInodeMap) takeDeferredTouches() (touches map[uint64]time.Time, lineTouches map[uint64]uint64) {
+	if !m.deferredPending.Swap(false) {
+		return
+	}
+	touches = make(map[uint64]time.Time)
+	lineTouches = make(map[uint64]uint64)
+	for i := range m.shards {
+		m.shards[i].mu.Lock()
+		for inodeNumber, aTime := range m.shards[i].deferredTouches {
+			touches[inodeNumber] = aTime
+		}
+		clear(m.shards[i].deferredTouches)
+		for dataCacheLinePos, contentGeneration := range m.shards[i].deferredLineTouches {
+			lineTouches[dataCacheLinePos] = contentGeneration
+		}
+		clear(m.shards[i].deferredLineTouches)
+		m.shards[i].mu.Unlock()
+	}
+	return
+}
+
 func (m *shardedInodeMap) delete(inodeNumber uint64) (ok bool) {
 	s := m.shardFor(inodeNumber)
 	s.mu.Lock()
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"sync"
+)
+
+// `readBufPool` recycles the buffers into which backends read object content (see
+// readAllSized()). As nearly every backend read is of a full data cache line, only
+// buffers with a capacity of exactly globals.config.cacheLineSize are pooled. A steady
+// stream of data cache line fetches thus reuses the same few buffers rather than
+// allocating (and having the GC reclaim) a fresh one per fetch.
+//
+// Note that the fission.ReadOut.Data buffers returned by DoRead() are not pooled as
+// package fission only writes them to /dev/fuse after DoRead() has returned (with no
+// indication as to when that write has completed).
+var readBufPool sync.Pool
+
+// `getReadBuf` returns a buffer of length size, reusing one from readBufPool if possible.
+func getReadBuf(size uint64) (buf []byte) {
+	var (
+		bufPtr *[]byte
+		ok     bool
+	)
+
+	bufPtr, ok = readBufPool.Get().(*[]byte)
+	if ok && (uint64(cap(*bufPtr)) >= size) {
+		buf = (*bufPtr)[:size]
+		return
+	}
+
+	buf = make([]byte, size)
+
+	return
+}
+
+// `putReadBuf` returns buf (obtained via getReadBuf()) to readBufPool if it is the
+// size of a data cache line. The caller must no longer reference buf's content.
+func putReadBuf(buf []byte) {
+	if uint64(cap(buf)) == globals.config.cacheLineSize {
+		buf = buf[:0]
+		readBufPool.Put(&buf)
+	}
+}
+
+// `readAllSized` reads r to EOF. If size (e.g. a response's Content-Length) is known
+// (i.e. >= 0), the content is read into a single buffer from getReadBuf() rather than
+// one repeatedly grown (and copied) by io.ReadAll().
+func readAllSized(r io.Reader, size int64) (buf []byte, err error) {
+	if size < 0 {
+		buf, err = io.ReadAll(r)
+		return
+	}
+
+	buf = getReadBuf(uint64(size))
+
+	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		putReadBuf(buf)
+		buf = nil
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+const (
+	testBufPoolCacheLineSize = uint64(1024 * 1024)
+)
+
+// TestReadAllSized verifies that readAllSized() returns the full content of a reader whose
+// size is known (reusing pooled buffers) or unknown, and fails should the reader fall short.
+func TestReadAllSized(t *testing.T) {
+	var (
+		buf     []byte
+		content = bytes.Repeat([]byte("0123456789abcdef"), int(testBufPoolCacheLineSize/16))
+		err     error
+	)
+
+	globals.config = &configStruct{
+		cacheLineSize: testBufPoolCacheLineSize,
+	}
+
+	buf, err = readAllSized(bytes.NewReader(content), int64(len(content)))
+	if (err != nil) || !bytes.Equal(buf, content) {
+		t.Fatalf("readAllSized(content,len(content)) returned unexpected result (err: %v)", err)
+	}
+
+	putReadBuf(buf)
+
+	buf, err = readAllSized(bytes.NewReader(content[:7]), 7)
+	if (err != nil) || !bytes.Equal(buf, content[:7]) {
+		t.Fatalf("readAllSized(content[:7],7) returned unexpected result (err: %v)", err)
+	}
+
+	buf, err = readAllSized(bytes.NewReader(content), -1)
+	if (err != nil) || !bytes.Equal(buf, content) {
+		t.Fatalf("readAllSized(content,-1) returned unexpected result (err: %v)", err)
+	}
+
+	_, err = readAllSized(bytes.NewReader(content[:7]), 8)
+	if err == nil {
+		t.Fatalf("readAllSized(content[:7],8) should have failed")
+	}
+}
+
+// BenchmarkReadAllSized compares the allocations made reading data cache line sized responses
+// via io.ReadAll() against those made via readAllSized() with each buffer recycled via putReadBuf().
+func BenchmarkReadAllSized(b *testing.B) {
+	var (
+		content = make([]byte, testBufPoolCacheLineSize)
+	)
+
+	globals.config = &configStruct{
+		cacheLineSize: testBufPoolCacheLineSize,
+	}
+
+	b.Run("io.ReadAll", func(b *testing.B) {
+		var (
+			err error
+		)
+
+		b.ReportAllocs()
+		b.SetBytes(int64(len(content)))
+
+		for b.Loop() {
+			_, err = io.ReadAll(bytes.NewReader(content))
+			if err != nil {
+				b.Fatalf("io.ReadAll() failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("readAllSized", func(b *testing.B) {
+		var (
+			buf []byte
+			err error
+		)
+
+		b.ReportAllocs()
+		b.SetBytes(int64(len(content)))
+
+		for b.Loop() {
+			buf, err = readAllSized(bytes.NewReader(content), int64(len(content)))
+			if err != nil {
+				b.Fatalf("readAllSized() failed: %v", err)
+			}
+			putReadBuf(buf)
+		}
+	})
+}
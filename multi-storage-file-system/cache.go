@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 )
 
 const (
@@ -20,6 +26,12 @@ const (
 	cacheStoragePerInodeFile = "per-inode-file" // per-inode files served via pread
 )
 
+// cache_partition_by values — how a requester's data cache lines are charged to a partition.
+const (
+	cachePartitionByUID    = "uid"    // decimal uid of the requesting process
+	cachePartitionByCgroup = "cgroup" // cgroup path (from /proc/<pid>/cgroup) of the requesting process
+)
+
 func dataCacheUp() (err error) {
 	var (
 		dataCacheLineContentSize uint64
@@ -86,12 +98,16 @@ func dataCacheUp() (err error) {
 	}
 
 	globals.dataCacheLinesTracker = make([]dataCacheLineTrackerStruct, globals.config.cacheLines)
+	globals.dataCachePartitionLines = make(map[string]uint64)
+	globals.dataCachePartitionLRUs = make(map[string]*dataCacheLinePartitionLRUStruct)
+	globals.dataCacheDedupMap = make(map[[sha256.Size]byte]uint64)
+	globals.dataCacheDedupLines = 0
+	globals.cacheLineFetches = make(map[cacheLineFetchKeyStruct]*cacheLineFetchStruct)
 
 	globals.dataCacheLineFreeLRU = dataCacheLineLRUStruct{
-		head:     0, // not yet applicable
-		tail:     0, // not yet applicable
-		lruCount: 0,
-		state:    CacheLineFree,
+		head:  0, // not yet applicable
+		tail:  0, // not yet applicable
+		state: CacheLineFree,
 	}
 
 	for dataCacheLineIndex = range globals.config.cacheLines {
@@ -101,41 +117,39 @@ func dataCacheUp() (err error) {
 		dataCacheLineTracker.state = CacheLineNotNotOnLRU
 		dataCacheLineTracker.waiters = make([]*sync.WaitGroup, 0, 1)
 		dataCacheLineTracker.contentStart = dataCacheLineIndex * globals.config.cacheLineSize
+		dataCacheLineTracker.contentSlot = dataCacheLineTracker.contentStart
 		dataCacheLineTracker.contentLength = 0 // not yet applicable
 		dataCacheLineTracker.contentGeneration.Store(0)
 		dataCacheLineTracker.inodeNumber = 0 // not yet applicable
 		dataCacheLineTracker.lineNumber = 0  // not yet applicable
 		dataCacheLineTracker.eTag = ""       // not yet applicable
+		dataCacheLineTracker.partition = ""  // not yet applicable
 
 		globals.dataCacheLineFreeLRU.pushTail(dataCacheLineTracker)
 	}
 
 	globals.dataCacheLineInboundLRU = dataCacheLineLRUStruct{
-		head:     0, // not yet applicable
-		tail:     0, // not yet applicable
-		lruCount: 0,
-		state:    CacheLineInbound,
+		head:  0, // not yet applicable
+		tail:  0, // not yet applicable
+		state: CacheLineInbound,
 	}
 
 	globals.dataCacheLineCleanLRU = dataCacheLineLRUStruct{
-		head:     0, // not yet applicable
-		tail:     0, // not yet applicable
-		lruCount: 0,
-		state:    CacheLineClean,
+		head:  0, // not yet applicable
+		tail:  0, // not yet applicable
+		state: CacheLineClean,
 	}
 
 	globals.dataCacheLineOutboundLRU = dataCacheLineLRUStruct{
-		head:     0, // not yet applicable
-		tail:     0, // not yet applicable
-		lruCount: 0,
-		state:    CacheLineOutbound,
+		head:  0, // not yet applicable
+		tail:  0, // not yet applicable
+		state: CacheLineOutbound,
 	}
 
 	globals.dataCacheLineDirtyLRU = dataCacheLineLRUStruct{
-		head:     0, // not yet applicable
-		tail:     0, // not yet applicable
-		lruCount: 0,
-		state:    CacheLineDirty,
+		head:  0, // not yet applicable
+		tail:  0, // not yet applicable
+		state: CacheLineDirty,
 	}
 
 	return
@@ -170,6 +184,10 @@ func dataCacheDown() (err error) {
 	globals.dataCacheLinesContent = nil
 	globals.dataCacheLinesFile = nil
 	globals.dataCacheLinesTracker = nil
+	globals.dataCachePartitionLines = nil
+	globals.dataCachePartitionLRUs = nil
+	globals.dataCacheDedupMap = nil
+	globals.cacheLineFetches = nil
 
 	return
 }
@@ -183,24 +201,28 @@ func (dataCacheLineLRU *dataCacheLineLRUStruct) pushTail(dataCacheLineTracker *d
 	dataCacheLineTracker.next = 0 // not yet applicable
 	dataCacheLineTracker.state = dataCacheLineLRU.state
 
-	if dataCacheLineLRU.lruCount == 0 {
+	if dataCacheLineLRU.lruCount.Load() == 0 {
 		dataCacheLineTracker.prev = 0 // not yet applicable
 
 		dataCacheLineLRU.head = dataCacheLineTracker.pos
 		dataCacheLineLRU.tail = dataCacheLineTracker.pos
-		dataCacheLineLRU.lruCount = 1
+		dataCacheLineLRU.lruCount.Store(1)
 	} else {
 		globals.dataCacheLinesTracker[dataCacheLineLRU.tail].next = dataCacheLineTracker.pos
 
 		dataCacheLineTracker.prev = dataCacheLineLRU.tail
 
 		dataCacheLineLRU.tail = dataCacheLineTracker.pos
-		dataCacheLineLRU.lruCount++
+		dataCacheLineLRU.lruCount.Add(1)
+	}
+
+	if (dataCacheLineLRU.state == CacheLineClean) && (globals.config.cachePartitionBy != "") {
+		dataCacheLineTracker.partitionPushTail()
 	}
 }
 
 func (dataCacheLineLRU *dataCacheLineLRUStruct) peekHead() (dataCacheLineTracker *dataCacheLineTrackerStruct) {
-	if dataCacheLineLRU.lruCount == 0 {
+	if dataCacheLineLRU.lruCount.Load() == 0 {
 		dataCacheLineTracker = nil
 		return
 	}
@@ -219,8 +241,108 @@ func (dataCacheLineLRU *dataCacheLineLRUStruct) peekHead() (dataCacheLineTracker
 	return
 }
 
+// `peekOldestOfPartition` returns the least recently used unpinned data cache line on the
+// Clean LRU currently charged to `partition` (or nil if there is none) without removing it.
+// Only that partition's own LRU (see partitionPushTail()) is walked, so the cost does not
+// grow with the number of lines charged to other partitions.
+func (dataCacheLineLRU *dataCacheLineLRUStruct) peekOldestOfPartition(partition string) (dataCacheLineTracker *dataCacheLineTrackerStruct) {
+	var (
+		lruIndex     uint64
+		ok           bool
+		partitionLRU *dataCacheLinePartitionLRUStruct
+		pos          uint64
+	)
+
+	partitionLRU, ok = globals.dataCachePartitionLRUs[partition]
+	if !ok {
+		dataCacheLineTracker = nil
+		return
+	}
+
+	pos = partitionLRU.head
+
+	for lruIndex = 0; lruIndex < partitionLRU.lruCount; lruIndex++ {
+		dataCacheLineTracker = &globals.dataCacheLinesTracker[pos]
+		if dataCacheLineTracker.pins.Load() == 0 {
+			return
+		}
+		pos = dataCacheLineTracker.partitionNext
+	}
+
+	dataCacheLineTracker = nil
+	return
+}
+
+// `partitionPushTail` is called while holding the globals lock as a data cache line is
+// appended to the Clean LRU to also append it to the LRU of lines charged to its partition.
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) partitionPushTail() {
+	var (
+		ok           bool
+		partitionLRU *dataCacheLinePartitionLRUStruct
+	)
+
+	dataCacheLineTracker.partitionNext = 0 // not yet applicable
+
+	partitionLRU, ok = globals.dataCachePartitionLRUs[dataCacheLineTracker.partition]
+	if !ok {
+		dataCacheLineTracker.partitionPrev = 0 // not yet applicable
+
+		globals.dataCachePartitionLRUs[dataCacheLineTracker.partition] = &dataCacheLinePartitionLRUStruct{
+			head:     dataCacheLineTracker.pos,
+			tail:     dataCacheLineTracker.pos,
+			lruCount: 1,
+		}
+
+		return
+	}
+
+	globals.dataCacheLinesTracker[partitionLRU.tail].partitionNext = dataCacheLineTracker.pos
+
+	dataCacheLineTracker.partitionPrev = partitionLRU.tail
+
+	partitionLRU.tail = dataCacheLineTracker.pos
+	partitionLRU.lruCount++
+}
+
+// `partitionPopThis` is called while holding the globals lock as a data cache line leaves
+// the Clean LRU to also remove it from the LRU of lines charged to its partition. The
+// partition's LRU header is discarded once empty.
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) partitionPopThis() {
+	var (
+		ok           bool
+		partitionLRU *dataCacheLinePartitionLRUStruct
+	)
+
+	partitionLRU, ok = globals.dataCachePartitionLRUs[dataCacheLineTracker.partition]
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.dataCachePartitionLRUs[dataCacheLineTracker.partition] returned !ok")
+	}
+
+	if partitionLRU.lruCount == 1 {
+		delete(globals.dataCachePartitionLRUs, dataCacheLineTracker.partition)
+	} else {
+		switch dataCacheLineTracker.pos {
+		case partitionLRU.head:
+			partitionLRU.head = dataCacheLineTracker.partitionNext
+			globals.dataCacheLinesTracker[partitionLRU.head].partitionPrev = 0 // not yet applicable
+		case partitionLRU.tail:
+			partitionLRU.tail = dataCacheLineTracker.partitionPrev
+			globals.dataCacheLinesTracker[partitionLRU.tail].partitionNext = 0 // not yet applicable
+		default:
+			globals.dataCacheLinesTracker[dataCacheLineTracker.partitionPrev].partitionNext = dataCacheLineTracker.partitionNext
+			globals.dataCacheLinesTracker[dataCacheLineTracker.partitionNext].partitionPrev = dataCacheLineTracker.partitionPrev
+		}
+
+		partitionLRU.lruCount--
+	}
+
+	dataCacheLineTracker.partitionNext = 0 // not yet applicable
+	dataCacheLineTracker.partitionPrev = 0 // not yet applicable
+}
+
 func (dataCacheLineLRU *dataCacheLineLRUStruct) popHead() (dataCacheLineTracker *dataCacheLineTrackerStruct) {
-	if dataCacheLineLRU.lruCount == 0 {
+	if dataCacheLineLRU.lruCount.Load() == 0 {
 		dataCacheLineTracker = nil
 		return
 	}
@@ -236,25 +358,32 @@ func (dataCacheLineLRU *dataCacheLineLRUStruct) popHead() (dataCacheLineTracker
 	// 	globals.logger.Fatalf("dataCacheLineTracker.state(%v) != dataCacheLineLRU.state(%v)", dataCacheLineTracker.state, dataCacheLineLRU.state)
 	// }
 
-	if dataCacheLineLRU.lruCount == 1 {
+	if dataCacheLineLRU.lruCount.Load() == 1 {
 		dataCacheLineLRU.head = 0 // not yet applicable
 		dataCacheLineLRU.tail = 0 // not yet applicable
-		dataCacheLineLRU.lruCount = 0
+		dataCacheLineLRU.lruCount.Store(0)
 	} else {
 		dataCacheLineLRU.head = dataCacheLineTracker.next
 		globals.dataCacheLinesTracker[dataCacheLineLRU.head].prev = 0 // not yet applicable
-		dataCacheLineLRU.lruCount--
+		dataCacheLineLRU.lruCount.Add(^uint64(0))
 	}
 
 	dataCacheLineTracker.next = 0 // not yet applicable
 	dataCacheLineTracker.prev = 0 // not yet applicable
 	dataCacheLineTracker.state = CacheLineNotNotOnLRU
 
+	if dataCacheLineLRU.state == CacheLineClean {
+		if globals.config.cachePartitionBy != "" {
+			dataCacheLineTracker.partitionPopThis()
+		}
+		dataCacheLineTracker.undedup() // Lines leave the Clean LRU only to be evicted or freed
+	}
+
 	return
 }
 
 func (dataCacheLineLRU *dataCacheLineLRUStruct) popTail() (dataCacheLineTracker *dataCacheLineTrackerStruct) {
-	if dataCacheLineLRU.lruCount == 0 {
+	if dataCacheLineLRU.lruCount.Load() == 0 {
 		dataCacheLineTracker = nil
 		return
 	}
@@ -270,37 +399,44 @@ func (dataCacheLineLRU *dataCacheLineLRUStruct) popTail() (dataCacheLineTracker
 	// 	globals.logger.Fatalf("dataCacheLineTracker.state(%v) != dataCacheLineLRU.state(%v)", dataCacheLineTracker.state, dataCacheLineLRU.state)
 	// }
 
-	if dataCacheLineLRU.lruCount == 1 {
+	if dataCacheLineLRU.lruCount.Load() == 1 {
 		dataCacheLineLRU.head = 0 // not yet applicable
 		dataCacheLineLRU.tail = 0 // not yet applicable
-		dataCacheLineLRU.lruCount = 0
+		dataCacheLineLRU.lruCount.Store(0)
 	} else {
 		dataCacheLineLRU.tail = dataCacheLineTracker.prev
 		globals.dataCacheLinesTracker[dataCacheLineLRU.tail].next = 0 // not yet applicable
-		dataCacheLineLRU.lruCount--
+		dataCacheLineLRU.lruCount.Add(^uint64(0))
 	}
 
 	dataCacheLineTracker.next = 0 // not yet applicable
 	dataCacheLineTracker.prev = 0 // not yet applicable
 	dataCacheLineTracker.state = CacheLineNotNotOnLRU
 
+	if dataCacheLineLRU.state == CacheLineClean {
+		if globals.config.cachePartitionBy != "" {
+			dataCacheLineTracker.partitionPopThis()
+		}
+		dataCacheLineTracker.undedup() // Lines leave the Clean LRU only to be evicted or freed
+	}
+
 	return
 }
 
 func (dataCacheLineLRU *dataCacheLineLRUStruct) popThis(dataCacheLineTracker *dataCacheLineTrackerStruct) {
-	// if dataCacheLineLRU.lruCount == 0 {
+	// if dataCacheLineLRU.lruCount.Load() == 0 {
 	// 	dumpStack()
-	// 	globals.logger.Fatalf("dataCacheLineLRU.lruCount == 0")
+	// 	globals.logger.Fatalf("dataCacheLineLRU.lruCount.Load() == 0")
 	// }
 	// if dataCacheLineTracker.state != dataCacheLineLRU.state {
 	// 	dumpStack()
 	// 	globals.logger.Fatalf("dataCacheLineTracker.state(%v) != dataCacheLineLRU.state(%v)", dataCacheLineTracker.state, dataCacheLineLRU.state)
 	// }
 
-	if dataCacheLineLRU.lruCount == 1 {
+	if dataCacheLineLRU.lruCount.Load() == 1 {
 		dataCacheLineLRU.head = 0 // not yet applicable
 		dataCacheLineLRU.tail = 0 // not yet applicable
-		dataCacheLineLRU.lruCount = 0
+		dataCacheLineLRU.lruCount.Store(0)
 	} else {
 		switch dataCacheLineTracker.pos {
 		case dataCacheLineLRU.head:
@@ -314,18 +450,25 @@ func (dataCacheLineLRU *dataCacheLineLRUStruct) popThis(dataCacheLineTracker *da
 			globals.dataCacheLinesTracker[dataCacheLineTracker.next].prev = dataCacheLineTracker.prev
 		}
 
-		dataCacheLineLRU.lruCount--
+		dataCacheLineLRU.lruCount.Add(^uint64(0))
 	}
 
 	dataCacheLineTracker.next = 0 // not yet applicable
 	dataCacheLineTracker.prev = 0 // not yet applicable
 	dataCacheLineTracker.state = CacheLineNotNotOnLRU
+
+	if dataCacheLineLRU.state == CacheLineClean {
+		if globals.config.cachePartitionBy != "" {
+			dataCacheLineTracker.partitionPopThis()
+		}
+		dataCacheLineTracker.undedup() // Lines leave the Clean LRU only to be evicted or freed
+	}
 }
 
 func (dataCacheLineLRU *dataCacheLineLRUStruct) touchThis(dataCacheLineTracker *dataCacheLineTrackerStruct) {
-	// if dataCacheLineLRU.lruCount == 0 {
+	// if dataCacheLineLRU.lruCount.Load() == 0 {
 	// 	dumpStack()
-	// 	globals.logger.Fatalf("dataCacheLineLRU.lruCount == 0")
+	// 	globals.logger.Fatalf("dataCacheLineLRU.lruCount.Load() == 0")
 	// }
 	// if dataCacheLineTracker.state != dataCacheLineLRU.state {
 	// 	dumpStack()
@@ -350,8 +493,40 @@ func (dataCacheLineLRU *dataCacheLineLRUStruct) touchThis(dataCacheLineTracker *
 	dataCacheLineTracker.prev = dataCacheLineLRU.tail
 
 	dataCacheLineLRU.tail = dataCacheLineTracker.pos
+
+	if (dataCacheLineLRU.state == CacheLineClean) && (globals.config.cachePartitionBy != "") {
+		dataCacheLineTracker.partitionPopThis()
+		dataCacheLineTracker.partitionPushTail()
+	}
+}
+
+// `popHeadUnpinned` is like popHead() except that data cache lines pinned by a reader
+// (see pin()) are skipped by moving them to the tail of the LRU. The count of lines so
+// skipped is also returned.
+func (dataCacheLineLRU *dataCacheLineLRUStruct) popHeadUnpinned() (dataCacheLineTracker *dataCacheLineTrackerStruct, pinned uint64) {
+	var (
+		lruCount = dataCacheLineLRU.lruCount.Load()
+	)
+
+	for pinned = 0; pinned < lruCount; pinned++ {
+		dataCacheLineTracker = dataCacheLineLRU.peekHead()
+		if dataCacheLineTracker.pins.Load() == 0 {
+			dataCacheLineTracker = dataCacheLineLRU.popHead()
+			return
+		}
+
+		dataCacheLineLRU.touchThis(dataCacheLineTracker)
+	}
+
+	dataCacheLineTracker = nil
+	return
 }
 
+// `dataCacheLinePinnedBackoff` is how long allocateDataCacheLines() pauses when the only
+// data cache lines it could otherwise recycle are pinned. As pins are only held for the
+// duration of a single copy, such waits are both rare and brief.
+const dataCacheLinePinnedBackoff = 100 * time.Microsecond
+
 // `allocateDataCacheLines` is called while holding the globals lock to provision the
 // specified `count` data cache lines. Ideally, the required number of data cache lines
 // are available (i.e. from either the Free or Clean LRUs) in which case `neededToBlock`
@@ -359,49 +534,67 @@ func (dataCacheLineLRU *dataCacheLineLRUStruct) touchThis(dataCacheLineTracker *
 // availability (as in the case where Inbound data cache lines transition to Clean) or
 // more extensive efforts are needed, the value of `neededToBlock` will be true and the
 // caller's globals lock will have been released requiring their logic to restart.
-func allocateDataCacheLines(count uint64) (cacheLineNumbers []uint64, neededToBlock bool) {
+//
+// If cache partitioning is enabled, the allocated data cache lines are charged to the
+// specified `partition`. A partition already at its limit first recycles its own least
+// recently used Clean data cache lines so that it does not evict those of co-tenants.
+// Should it have none, allocation falls back to the Free and Clean LRUs as usual (i.e.
+// the limit is enforced on a best-effort basis rather than by blocking the requester).
+//
+// Data cache lines pinned by a reader copying their content (see pin()) are never
+// allocated, even if they have been freed, so that their content cannot be overwritten
+// mid-copy.
+func allocateDataCacheLines(count uint64, partition string) (cacheLineNumbers []uint64, neededToBlock bool) {
 	var (
 		cacheLineWaiter      sync.WaitGroup
 		dataCacheLineTracker *dataCacheLineTrackerStruct
-		inode                *inodeStruct
-		ok                   bool
+		partitionLimit       uint64
+		pinned               uint64
+		pinnedSkipped        uint64
 	)
 
 	cacheLineNumbers = make([]uint64, 0, count)
 	neededToBlock = false
 
+	partitionLimit = cachePartitionLimit(partition)
+
 	for {
-		for uint64(len(cacheLineNumbers)) < count {
-			dataCacheLineTracker = globals.dataCacheLineFreeLRU.popHead()
+		for (uint64(len(cacheLineNumbers)) < count) && (globals.dataCachePartitionLines[partition] >= partitionLimit) {
+			dataCacheLineTracker = globals.dataCacheLineCleanLRU.peekOldestOfPartition(partition)
 			if dataCacheLineTracker == nil {
 				break
 			}
 
+			globals.dataCacheLineCleanLRU.popThis(dataCacheLineTracker)
+			dataCacheLineTracker.evict()
+			dataCacheLineTracker.charge(partition)
+
 			cacheLineNumbers = append(cacheLineNumbers, dataCacheLineTracker.pos)
 		}
 
+		pinnedSkipped = 0
+
 		for uint64(len(cacheLineNumbers)) < count {
-			dataCacheLineTracker = globals.dataCacheLineCleanLRU.popHead()
+			dataCacheLineTracker, pinned = globals.dataCacheLineFreeLRU.popHeadUnpinned()
+			pinnedSkipped += pinned
 			if dataCacheLineTracker == nil {
 				break
 			}
 
-			inode, ok = globals.inodeMap.get(dataCacheLineTracker.inodeNumber)
-			if !ok {
-				dumpStack()
-				globals.logger.Fatalf("[FATAL] globals.inodeMap.get(dataCacheLineTracker.inodeNumber[%v]) returned !ok", dataCacheLineTracker.inodeNumber)
-			}
+			dataCacheLineTracker.charge(partition)
 
-			if globals.config.cacheStorage == cacheStoragePerInodeFile {
-				// Slot is being recycled for a different (inode, line): release
-				// the evicted line's disk bytes. Bump the generation first so any
-				// in-flight unlocked optimistic reader fails its post-copy
-				// re-check and retries rather than accepting now-punched bytes.
-				dataCacheLineTracker.contentGeneration.Add(1)
-				dataCacheLineTracker.punchHoleDisk()
+			cacheLineNumbers = append(cacheLineNumbers, dataCacheLineTracker.pos)
+		}
+
+		for uint64(len(cacheLineNumbers)) < count {
+			dataCacheLineTracker, pinned = globals.dataCacheLineCleanLRU.popHeadUnpinned()
+			pinnedSkipped += pinned
+			if dataCacheLineTracker == nil {
+				break
 			}
 
-			delete(inode.cacheMap, dataCacheLineTracker.lineNumber)
+			dataCacheLineTracker.evict()
+			dataCacheLineTracker.charge(partition)
 
 			cacheLineNumbers = append(cacheLineNumbers, dataCacheLineTracker.pos)
 		}
@@ -419,8 +612,20 @@ func allocateDataCacheLines(count uint64) (cacheLineNumbers []uint64, neededToBl
 
 		dataCacheLineTracker = globals.dataCacheLineInboundLRU.peekHead()
 		if dataCacheLineTracker == nil {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.dataCacheLineInboundLRU.peekHead() returned nil")
+			if pinnedSkipped == 0 {
+				dumpStack()
+				globals.logger.Fatalf("[FATAL] globals.dataCacheLineInboundLRU.peekHead() returned nil")
+			}
+
+			// All we lack are data cache lines momentarily pinned by readers... so briefly pause for them
+
+			globalsUnlock()
+
+			time.Sleep(dataCacheLinePinnedBackoff)
+
+			globalsLock("cache.go:626:4:allocateDataCacheLines")
+
+			continue
 		}
 
 		cacheLineWaiter.Add(1)
@@ -430,8 +635,199 @@ func allocateDataCacheLines(count uint64) (cacheLineNumbers []uint64, neededToBl
 
 		cacheLineWaiter.Wait()
 
-		globalsLock("cache.go:433:3:allocateDataCacheLines")
+		globalsLock("cache.go:638:3:allocateDataCacheLines")
+	}
+}
+
+// `evict` is called while holding the globals lock to detach a data cache line just
+// popped from the Clean LRU from the inode whose content it held so that it may be
+// recycled. Any partition charge for the line is also released.
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) evict() {
+	var (
+		backend *backendStruct
+		inode   *inodeStruct
+		ok      bool
+	)
+
+	inode, ok = globals.inodeMap.get(dataCacheLineTracker.inodeNumber)
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.inodeMap.get(dataCacheLineTracker.inodeNumber[%v]) returned !ok", dataCacheLineTracker.inodeNumber)
 	}
+
+	globals.backendMetrics.CacheLineEvictions.Inc()
+	backend, ok = globals.backendMap[inode.backendNonce]
+	if ok {
+		backend.backendMetrics.CacheLineEvictions.Inc()
+	}
+
+	if globals.config.cacheStorage == cacheStoragePerInodeFile {
+		// Slot is being recycled for a different (inode, line): release
+		// the evicted line's disk bytes. Bump the generation first so any
+		// in-flight unlocked optimistic reader fails its post-copy
+		// re-check and retries rather than accepting now-punched bytes.
+		dataCacheLineTracker.contentGeneration.Add(1)
+		dataCacheLineTracker.punchHoleDisk()
+	}
+
+	delete(inode.cacheMap, dataCacheLineTracker.lineNumber)
+
+	dataCacheLineTracker.discharge()
+}
+
+// `charge` is called while holding the globals lock to account for an allocated data
+// cache line against `partition`. It is a no-op if cache partitioning is not enabled.
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) charge(partition string) {
+	if globals.config.cachePartitionBy == "" {
+		return
+	}
+
+	dataCacheLineTracker.partition = partition
+	globals.dataCachePartitionLines[partition]++
+}
+
+// `discharge` is the companion to `charge` called while holding the globals lock as a
+// data cache line is either freed or recycled.
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) discharge() {
+	var (
+		partitionLines uint64
+	)
+
+	if globals.config.cachePartitionBy == "" {
+		return
+	}
+
+	partitionLines = globals.dataCachePartitionLines[dataCacheLineTracker.partition]
+	if partitionLines <= 1 {
+		delete(globals.dataCachePartitionLines, dataCacheLineTracker.partition)
+	} else {
+		globals.dataCachePartitionLines[dataCacheLineTracker.partition] = partitionLines - 1
+	}
+
+	dataCacheLineTracker.partition = ""
+}
+
+// `cachePartitionLimit` returns the maximum number of data cache lines that `partition`
+// should hold. If cache partitioning is not enabled, this is simply the number of data
+// cache lines.
+func cachePartitionLimit(partition string) (limit uint64) {
+	var (
+		cachePartition cachePartitionStruct
+	)
+
+	if globals.config.cachePartitionBy == "" {
+		limit = globals.config.cacheLines
+		return
+	}
+
+	for _, cachePartition = range globals.config.cachePartitions {
+		if cachePartition.key == partition {
+			limit = cachePartition.limit
+			return
+		}
+	}
+
+	limit = globals.config.cachePartitionDefaultLimit
+	return
+}
+
+// `trimCachePartitions` is called while holding the globals lock once the cache partition
+// limits may have been lowered (i.e. upon a SIGHUP) to free the least recently used Clean
+// data cache lines of each partition now holding more than its limit. Lines of such a
+// partition that are not Clean (e.g. still Inbound) remain charged to it until recycled
+// by allocateDataCacheLines(). The number of data cache lines freed is returned.
+func trimCachePartitions() (freed uint64) {
+	var (
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		partition            string
+		partitionLimit       uint64
+		partitions           []string
+	)
+
+	if globals.config.cachePartitionBy == "" {
+		return
+	}
+
+	partitions = make([]string, 0, len(globals.dataCachePartitionLines))
+	for partition = range globals.dataCachePartitionLines {
+		partitions = append(partitions, partition)
+	}
+
+	for _, partition = range partitions {
+		partitionLimit = cachePartitionLimit(partition)
+
+		for globals.dataCachePartitionLines[partition] > partitionLimit {
+			dataCacheLineTracker = globals.dataCacheLineCleanLRU.peekOldestOfPartition(partition)
+			if dataCacheLineTracker == nil {
+				break
+			}
+
+			globals.dataCacheLineCleanLRU.popThis(dataCacheLineTracker)
+			dataCacheLineTracker.evict()
+			dataCacheLineTracker.free()
+
+			freed++
+		}
+	}
+
+	return
+}
+
+// `cachePartitionKey` returns the partition to which data cache lines allocated on
+// behalf of the process identified by `uid` and `pid` are charged. If cache
+// partitioning is not enabled or the partition cannot be determined, "" is returned.
+func cachePartitionKey(uid, pid uint32) (partition string) {
+	switch globals.config.cachePartitionBy {
+	case cachePartitionByUID:
+		partition = strconv.FormatUint(uint64(uid), 10)
+	case cachePartitionByCgroup:
+		partition = cgroupOfPID(pid)
+	default:
+		partition = ""
+	}
+
+	return
+}
+
+// `cgroupOfPID` returns the cgroup path of the process identified by `pid` as reported
+// by /proc/<pid>/cgroup. The unified (cgroup v2) hierarchy is preferred, falling back to
+// the first listed (cgroup v1) hierarchy. If it cannot be determined, "" is returned.
+func cgroupOfPID(pid uint32) (cgroup string) {
+	var (
+		cgroupFileContent []byte
+		err               error
+		line              string
+		lineFields        []string
+	)
+
+	cgroup = ""
+
+	if pid == 0 {
+		return
+	}
+
+	cgroupFileContent, err = os.ReadFile("/proc/" + strconv.FormatUint(uint64(pid), 10) + "/cgroup")
+	if err != nil {
+		return
+	}
+
+	for _, line = range strings.Split(string(cgroupFileContent), "\n") {
+		lineFields = strings.SplitN(line, ":", 3)
+		if len(lineFields) != 3 {
+			continue
+		}
+
+		if (lineFields[0] == "0") && (lineFields[1] == "") {
+			cgroup = lineFields[2]
+			return
+		}
+
+		if cgroup == "" {
+			cgroup = lineFields[2]
+		}
+	}
+
+	return
 }
 
 // `releaseDataCacheLines` is the companion to `allocateDataCacheLines` that anticipates
@@ -450,6 +846,23 @@ func releaseDataCacheLines(cacheLineNumbers []uint64) {
 	}
 }
 
+// `pin` is called while holding the globals lock by a reader about to copy the content
+// of a data cache line after releasing the globals lock. Until the matching unpin(), the
+// line will not be recycled by allocateDataCacheLines() (so its content will not be
+// overwritten), though it may still be evicted or freed (as detected by a change in its
+// .contentGeneration).
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) pin() {
+	dataCacheLineTracker.pins.Add(1)
+}
+
+// `unpin` releases a pin() once the reader's copy is complete. The globals lock need not be held.
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) unpin() {
+	if dataCacheLineTracker.pins.Add(-1) < 0 {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] dataCacheLineTracker.unpin() without a matching pin()")
+	}
+}
+
 // `free` resets a data cache line that is not currently on any LRU and returns
 // it to the Free LRU. The caller must hold the globals lock.
 func (dataCacheLineTracker *dataCacheLineTrackerStruct) free() {
@@ -462,27 +875,64 @@ func (dataCacheLineTracker *dataCacheLineTrackerStruct) free() {
 	dataCacheLineTracker.lineNumber = 0  // not yet applicable
 	dataCacheLineTracker.eTag = ""       // not yet applicable
 	dataCacheLineTracker.fetchFailed = false
+	dataCacheLineTracker.fetchArchived = false
 	dataCacheLineTracker.waiters = make([]*sync.WaitGroup, 0, 1)
+	dataCacheLineTracker.discharge()
 	globals.dataCacheLineFreeLRU.pushTail(dataCacheLineTracker)
 }
 
+// `cacheLineFetchKeyStruct` identifies the object content sought by a data cache line fetch().
+type cacheLineFetchKeyStruct struct {
+	inodeNumber uint64
+	lineNumber  uint64
+	eTag        string // inodeStruct.eTag when the fetch() was launched
+}
+
+// `cacheLineFetchStruct` tracks a backend GET issued by fetch() so that other fetch()'s
+// of the same cacheLineFetchKeyStruct may await and share its result rather than
+// issuing their own GET.
+type cacheLineFetchStruct struct {
+	sync.WaitGroup                       // Done() once readFileOutput and err have been set
+	readFileOutput *readFileOutputStruct // Shared by all waiters (hence .buf must not be modified)
+	err            error
+	users          uint64 // Protected by globals.Lock(); once the last user is done, .readFileOutput.buf is passed to putReadBuf()
+}
+
+// `startFetch` accounts for a pending fetch in globals.dataCacheActivityWG and
+// launches fetch() as a "cacheLineFetch" worker whose backend calls are traced
+// as children of any span in ctx.
+//
+// Note: Callers must hold globals.Lock()
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) startFetch(ctx context.Context) {
+	globals.dataCacheActivityWG.Add(1)
+	_ = startWorker("cacheLineFetch", func(workerCtx context.Context) {
+		dataCacheLineTracker.fetch(detachedSpanContext(workerCtx, ctx))
+	})
+}
+
 // `fetch` is run in a goroutine for an allocated dataCacheLineTrackerStruct that
 // is to be populated with a portion of the object's contents. Completion of the
 // fetch operation is indicated by notifying the tracker waiters.
-func (dataCacheLineTracker *dataCacheLineTrackerStruct) fetch() {
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) fetch(ctx context.Context) {
 	var (
 		backend        *backendStruct
+		cacheLineFetch *cacheLineFetchStruct
 		content        []byte
+		contentHash    [sha256.Size]byte
 		err            error
+		fetchKey       cacheLineFetchKeyStruct
 		inode          *inodeStruct
+		joined         bool
+		latency        float64
 		ok             bool
 		readFileInput  *readFileInputStruct
 		readFileOutput *readFileOutputStruct
+		startTime      time.Time
 	)
 
 	defer globals.dataCacheActivityWG.Done()
 
-	globalsLock("cache.go:485:2:(*dataCacheLineTrackerStruct).fetch")
+	globalsLock("cache.go:935:2:(*dataCacheLineTrackerStruct).fetch")
 
 	inode, ok = globals.inodeMap.get(dataCacheLineTracker.inodeNumber)
 	if !ok {
@@ -501,20 +951,64 @@ func (dataCacheLineTracker *dataCacheLineTrackerStruct) fetch() {
 	}
 
 	readFileInput = &readFileInputStruct{
-		filePath:        inode.objectPath,
-		offsetCacheLine: dataCacheLineTracker.lineNumber,
-		ifMatch:         "",
+		filePath: inode.objectPath,
+		offset:   dataCacheLineTracker.lineNumber * globals.config.cacheLineSize,
+		length:   globals.config.cacheLineSize,
+		ifMatch:  "",
+	}
+
+	// Join any identical GET already in flight (e.g. one launched on behalf of a data cache
+	// line since evicted or invalidated) rather than issuing another
+
+	fetchKey = cacheLineFetchKeyStruct{
+		inodeNumber: dataCacheLineTracker.inodeNumber,
+		lineNumber:  dataCacheLineTracker.lineNumber,
+		eTag:        inode.eTag,
+	}
+
+	cacheLineFetch, joined = globals.cacheLineFetches[fetchKey]
+	if joined {
+		globals.backendMetrics.CacheLineFetchesCoalesced.Inc()
+		backend.backendMetrics.CacheLineFetchesCoalesced.Inc()
+	} else {
+		cacheLineFetch = &cacheLineFetchStruct{}
+		cacheLineFetch.Add(1)
+		globals.cacheLineFetches[fetchKey] = cacheLineFetch
 	}
 
+	cacheLineFetch.users++
+
 	globalsUnlock()
 
-	readFileOutput, err = readFileWrapper(backend.context, readFileInput)
+	startTime = time.Now()
+
+	if joined {
+		cacheLineFetch.Wait()
+	} else {
+		cacheLineFetch.readFileOutput, cacheLineFetch.err = hedgedReadFileWrapper(ctx, backend, readFileInput)
+
+		globalsLock("cache.go:990:3:(*dataCacheLineTrackerStruct).fetch")
+		delete(globals.cacheLineFetches, fetchKey)
+		globalsUnlock()
+
+		cacheLineFetch.Done()
+	}
+
+	readFileOutput, err = cacheLineFetch.readFileOutput, cacheLineFetch.err
+
+	latency = time.Since(startTime).Seconds()
+
 	if err == nil && globals.config.cacheStorage != cacheStoragePerInodeFile {
 		content = globals.dataCacheLinesContent[dataCacheLineTracker.contentStart : dataCacheLineTracker.contentStart+globals.config.cacheLineSize]
 		dataCacheLineTracker.contentLength = uint64(copy(content, readFileOutput.buf))
+		if globals.config.cacheDedup && (dataCacheLineTracker.contentLength > 0) {
+			contentHash = sha256.Sum256(content[:dataCacheLineTracker.contentLength]) // Hashed here so as not to hold the globals lock while doing so
+		}
 	}
 
-	globalsLock("cache.go:517:2:(*dataCacheLineTrackerStruct).fetch")
+	globalsLock("cache.go:1009:2:(*dataCacheLineTrackerStruct).fetch")
+	globals.backendMetrics.CacheLineFetchLatencies.Observe(latency)
+	backend.backendMetrics.CacheLineFetchLatencies.Observe(latency)
 	inode, ok = globals.inodeMap.get(dataCacheLineTracker.inodeNumber)
 	if ok {
 		inode.inboundCacheLineCount--
@@ -523,6 +1017,7 @@ func (dataCacheLineTracker *dataCacheLineTrackerStruct) fetch() {
 		globals.dataCacheLineInboundLRU.popThis(dataCacheLineTracker)
 		dataCacheLineTracker.free()
 		dataCacheLineTracker.notifyWaiters()
+		cacheLineFetch.release()
 		globalsUnlock()
 		return
 	}
@@ -542,6 +1037,7 @@ func (dataCacheLineTracker *dataCacheLineTrackerStruct) fetch() {
 		dataCacheLineTracker.contentLength = 0
 		dataCacheLineTracker.eTag = ""
 		dataCacheLineTracker.fetchFailed = true
+		dataCacheLineTracker.fetchArchived = errors.Is(err, errObjectArchived)
 	case globals.config.cacheStorage == cacheStoragePerInodeFile:
 		// Write the fetched bytes through to the inode's backing file under
 		// the lock (matches the memory path, which set contentLength above).
@@ -551,20 +1047,39 @@ func (dataCacheLineTracker *dataCacheLineTrackerStruct) fetch() {
 			// treat as a fetch failure so DoRead surfaces EIO instead of EOF.
 			dataCacheLineTracker.eTag = ""
 			dataCacheLineTracker.fetchFailed = true
+			dataCacheLineTracker.fetchArchived = false
 		} else {
 			dataCacheLineTracker.eTag = readFileOutput.eTag
 			dataCacheLineTracker.fetchFailed = false
+			dataCacheLineTracker.fetchArchived = false
 		}
 	default:
 		dataCacheLineTracker.eTag = readFileOutput.eTag
 		dataCacheLineTracker.fetchFailed = false
+		dataCacheLineTracker.fetchArchived = false
 	}
 
 	globals.dataCacheLineCleanLRU.pushTail(dataCacheLineTracker)
+
+	if globals.config.cacheDedup && !dataCacheLineTracker.fetchFailed && (dataCacheLineTracker.contentLength > 0) {
+		dataCacheLineTracker.dedup(contentHash)
+	}
 	dataCacheLineTracker.notifyWaiters()
+	cacheLineFetch.release()
 	globalsUnlock()
 }
 
+// `release` is called while holding globals.Lock() by each user of a cacheLineFetchStruct
+// once done with its .readFileOutput. The last user to do so recycles .readFileOutput.buf.
+func (cacheLineFetch *cacheLineFetchStruct) release() {
+	cacheLineFetch.users--
+
+	if (cacheLineFetch.users == 0) && (cacheLineFetch.err == nil) {
+		putReadBuf(cacheLineFetch.readFileOutput.buf)
+		cacheLineFetch.readFileOutput.buf = nil
+	}
+}
+
 // `touch` is called while globals.Lock() is held to update the placement of
 // a dataCacheLineTrackerStruct on the state-corresponding LRU.
 func (dataCacheLineTracker *dataCacheLineTrackerStruct) touch() {
@@ -597,3 +1112,27 @@ func (dataCacheLineTracker *dataCacheLineTrackerStruct) notifyWaiters() {
 
 	dataCacheLineTracker.waiters = make([]*sync.WaitGroup, 0, 1)
 }
+
+// `awaitCacheLineWaiter` awaits cacheLineWaiter (previously appended to the .waiters
+// slice of a data cache line) unless ctx is first canceled, in which case the cause
+// is returned. Note that the data cache line fetch itself is not canceled as other
+// readers may also be awaiting it; cacheLineWaiter remains on the .waiters slice and
+// is simply notified (with nobody awaiting it) once the fetch completes.
+func awaitCacheLineWaiter(ctx context.Context, cacheLineWaiter *sync.WaitGroup) (err error) {
+	var (
+		doneChan = make(chan struct{})
+	)
+
+	go func() {
+		cacheLineWaiter.Wait()
+		close(doneChan)
+	}()
+
+	select {
+	case <-doneChan:
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	}
+
+	return
+}
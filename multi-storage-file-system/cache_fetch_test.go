@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/fission/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestCacheLineFetchCoalesced verifies that a fetch() launched while an identical backend
+// read is already in flight awaits and shares that read's result rather than issuing its own.
+func TestCacheLineFetchCoalesced(t *testing.T) {
+	var (
+		cacheLineFetch       *cacheLineFetchStruct
+		cacheLineWaiter      sync.WaitGroup
+		coalescedContent     = []byte("coalesced")
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		errno                syscall.Errno
+		fetchKey             cacheLineFetchKeyStruct
+		fileBIno             uint64
+		inode                *inodeStruct
+		lines                []uint64
+		lookupOut            *fission.LookupOut
+		ok                   bool
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.LookupIn{Name: []byte("ram")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") unexpectedly failed (errno: %v)", errno)
+	}
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: lookupOut.EntryOut.NodeID}, &fission.LookupIn{Name: []byte("fileB")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(ramDirIno,Name:\"fileB\") unexpectedly failed (errno: %v)", errno)
+	}
+	fileBIno = lookupOut.EntryOut.NodeID
+
+	globalsLock("cache_fetch_test.go:45:2:TestCacheLineFetchCoalesced")
+	lines, _ = allocateDataCacheLines(1, "")
+	globalsLock("cache_fetch_test.go:47:2:TestCacheLineFetchCoalesced")
+
+	inode, ok = globals.inodeMap.get(fileBIno)
+	if !ok {
+		globalsUnlock()
+		t.Fatalf("globals.inodeMap.get(fileBIno) returned !ok")
+	}
+
+	// Simulate a backend read of line 0 already in flight
+
+	fetchKey = cacheLineFetchKeyStruct{
+		inodeNumber: fileBIno,
+		lineNumber:  0,
+		eTag:        inode.eTag,
+	}
+
+	cacheLineFetch = &cacheLineFetchStruct{}
+	cacheLineFetch.Add(1)
+	globals.cacheLineFetches[fetchKey] = cacheLineFetch
+
+	dataCacheLineTracker = &globals.dataCacheLinesTracker[lines[0]]
+
+	cacheLineWaiter.Add(1)
+	dataCacheLineTracker.waiters = []*sync.WaitGroup{&cacheLineWaiter}
+	dataCacheLineTracker.contentLength = 0
+	dataCacheLineTracker.inodeNumber = fileBIno
+	dataCacheLineTracker.lineNumber = 0
+	dataCacheLineTracker.eTag = ""
+
+	inode.cacheMap[0] = dataCacheLineTracker.pos
+	inode.inboundCacheLineCount++
+	globals.dataCacheLineInboundLRU.pushTail(dataCacheLineTracker)
+
+	dataCacheLineTracker.startFetch(context.Background())
+
+	globalsUnlock()
+
+	for testutil.ToFloat64(globals.backendMetrics.CacheLineFetchesCoalesced) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Complete the simulated backend read
+
+	globalsLock("cache_fetch_test.go:90:2:TestCacheLineFetchCoalesced")
+	cacheLineFetch.readFileOutput = &readFileOutputStruct{eTag: inode.eTag, buf: coalescedContent}
+	delete(globals.cacheLineFetches, fetchKey)
+	globalsUnlock()
+
+	cacheLineFetch.Done()
+
+	cacheLineWaiter.Wait()
+
+	globalsLock("cache_fetch_test.go:99:2:TestCacheLineFetchCoalesced")
+	if (dataCacheLineTracker.state != CacheLineClean) || dataCacheLineTracker.fetchFailed {
+		globalsUnlock()
+		t.Fatalf("coalesced fetch() should have left the data cache line Clean (state: %v, fetchFailed: %v)", dataCacheLineTracker.state, dataCacheLineTracker.fetchFailed)
+	}
+	if !bytes.Equal(globals.dataCacheLinesContent[dataCacheLineTracker.contentStart:dataCacheLineTracker.contentStart+dataCacheLineTracker.contentLength], coalescedContent) {
+		globalsUnlock()
+		t.Fatalf("coalesced fetch() should have populated the data cache line with the shared read's content")
+	}
+	globalsUnlock()
+}
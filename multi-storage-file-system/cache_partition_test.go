@@ -0,0 +1,147 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestCachePartitionAllocation verifies that a partition at its limit recycles its own
+// Clean data cache lines rather than those charged to another partition.
+func TestCachePartitionAllocation(t *testing.T) {
+	var (
+		cacheLineNumber      uint64
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		lines1000            []uint64
+		lines2000            []uint64
+		linesRecycled        []uint64
+		linesUnlimited       []uint64
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	globals.config.cachePartitionBy = cachePartitionByUID
+	globals.config.cachePartitionDefaultLimit = globals.config.cacheLines
+	globals.config.cachePartitions = []cachePartitionStruct{{key: "1000", limit: 4}}
+
+	parkOnCleanLRU := func(cacheLineNumbers []uint64) {
+		for _, cacheLineNumber = range cacheLineNumbers {
+			dataCacheLineTracker = &globals.dataCacheLinesTracker[cacheLineNumber]
+			dataCacheLineTracker.inodeNumber = globals.config.backends["ram"].inode.inodeNumber
+			dataCacheLineTracker.lineNumber = cacheLineNumber
+			globals.dataCacheLineCleanLRU.pushTail(dataCacheLineTracker)
+		}
+	}
+
+	globalsLock("cache_partition_test.go:39:2:TestCachePartitionAllocation")
+	lines1000, _ = allocateDataCacheLines(4, "1000")
+	globalsLock("cache_partition_test.go:41:2:TestCachePartitionAllocation")
+	parkOnCleanLRU(lines1000)
+	lines2000, _ = allocateDataCacheLines(4, "2000")
+	globalsLock("cache_partition_test.go:44:2:TestCachePartitionAllocation")
+	parkOnCleanLRU(lines2000)
+	globalsUnlock()
+
+	if (globals.dataCachePartitionLines["1000"] != 4) || (globals.dataCachePartitionLines["2000"] != 4) {
+		t.Fatalf("unexpected partition charges: %v", globals.dataCachePartitionLines)
+	}
+	if (globals.dataCachePartitionLRUs["1000"].lruCount != 4) || (globals.dataCachePartitionLRUs["2000"].lruCount != 4) {
+		t.Fatalf("each partition's Clean LRU should hold its 4 lines")
+	}
+
+	globalsLock("cache_partition_test.go:55:2:TestCachePartitionAllocation")
+	linesRecycled, _ = allocateDataCacheLines(2, "1000")
+
+	if !slices.Equal(linesRecycled, lines1000[:2]) {
+		t.Fatalf("partition \"1000\" at its limit should have recycled %v but got %v", lines1000[:2], linesRecycled)
+	}
+	if (globals.dataCachePartitionLines["1000"] != 4) || (globals.dataCachePartitionLines["2000"] != 4) {
+		t.Fatalf("unexpected partition charges after recycling: %v", globals.dataCachePartitionLines)
+	}
+	if (globals.dataCachePartitionLRUs["1000"].lruCount != 2) || (globals.dataCachePartitionLRUs["1000"].head != lines1000[2]) {
+		t.Fatalf("partition \"1000\"'s Clean LRU should hold only its 2 unrecycled lines")
+	}
+	if testutil.ToFloat64(globals.config.backends["ram"].backendMetrics.CacheLineEvictions) != 2 {
+		t.Fatalf("recycling should have counted 2 evictions against backend \"ram\" but counted %v", testutil.ToFloat64(globals.config.backends["ram"].backendMetrics.CacheLineEvictions))
+	}
+
+	globalsLock("cache_partition_test.go:71:2:TestCachePartitionAllocation")
+	releaseDataCacheLines(linesRecycled)
+	linesUnlimited, _ = allocateDataCacheLines(2, "2000")
+
+	for _, cacheLineNumber = range linesUnlimited {
+		if slices.Contains(lines1000, cacheLineNumber) || slices.Contains(lines2000, cacheLineNumber) {
+			t.Fatalf("partition \"2000\" below its limit should have been allocated Free data cache lines but got %v", linesUnlimited)
+		}
+	}
+	if (globals.dataCachePartitionLines["1000"] != 2) || (globals.dataCachePartitionLines["2000"] != 6) {
+		t.Fatalf("unexpected partition charges after release: %v", globals.dataCachePartitionLines)
+	}
+
+	globalsLock("cache_partition_test.go:84:2:TestCachePartitionAllocation")
+	releaseDataCacheLines(linesUnlimited)
+	globalsUnlock()
+}
+
+// TestTrimCachePartitions verifies that lowering a partition's limit frees its least
+// recently used Clean data cache lines (and only those of partitions over their limit).
+func TestTrimCachePartitions(t *testing.T) {
+	var (
+		cacheLineNumber uint64
+		freed           uint64
+		lines1000       []uint64
+		lines2000       []uint64
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	globals.config.cachePartitionBy = cachePartitionByUID
+	globals.config.cachePartitionDefaultLimit = globals.config.cacheLines
+	globals.config.cachePartitions = []cachePartitionStruct{{key: "1000", limit: 4}}
+
+	globalsLock("cache_partition_test.go:106:2:TestTrimCachePartitions")
+	lines1000, _ = allocateDataCacheLines(4, "1000")
+	globalsLock("cache_partition_test.go:108:2:TestTrimCachePartitions")
+	lines2000, _ = allocateDataCacheLines(4, "2000")
+	globalsLock("cache_partition_test.go:110:2:TestTrimCachePartitions")
+	for _, cacheLineNumber = range slices.Concat(lines1000, lines2000) {
+		globals.dataCacheLinesTracker[cacheLineNumber].inodeNumber = globals.config.backends["ram"].inode.inodeNumber
+		globals.dataCacheLinesTracker[cacheLineNumber].lineNumber = cacheLineNumber
+		globals.dataCacheLineCleanLRU.pushTail(&globals.dataCacheLinesTracker[cacheLineNumber])
+	}
+
+	globals.config.cachePartitions = []cachePartitionStruct{{key: "1000", limit: 1}}
+
+	freed = trimCachePartitions()
+	globalsUnlock()
+
+	if freed != 3 {
+		t.Fatalf("trimCachePartitions() should have freed 3 lines but freed %v", freed)
+	}
+	if (globals.dataCachePartitionLines["1000"] != 1) || (globals.dataCachePartitionLines["2000"] != 4) {
+		t.Fatalf("unexpected partition charges after trimming: %v", globals.dataCachePartitionLines)
+	}
+	if (globals.dataCachePartitionLRUs["1000"].lruCount != 1) || (globals.dataCachePartitionLRUs["1000"].head != lines1000[3]) {
+		t.Fatalf("partition \"1000\"'s Clean LRU should hold only its most recently used line")
+	}
+}
+
+// TestCgroupOfPID verifies that the cgroup of the current process can be determined.
+func TestCgroupOfPID(t *testing.T) {
+	_, err := os.Stat("/proc/self/cgroup")
+	if err != nil {
+		t.Skipf("/proc/self/cgroup not available: %v", err)
+	}
+
+	if cgroup := cgroupOfPID(uint32(os.Getpid())); (cgroup == "") || (cgroup[0] != '/') {
+		t.Fatalf("cgroupOfPID(os.Getpid()) returned %q", cgroup)
+	}
+
+	if cgroup := cgroupOfPID(0); cgroup != "" {
+		t.Fatalf("cgroupOfPID(0) returned %q", cgroup)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"runtime"
+	"slices"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+// TestCacheLinePinnedNotRecycled verifies that allocateDataCacheLines() never recycles a
+// pinned data cache line and, should only pinned lines remain, awaits their unpin().
+func TestCacheLinePinnedNotRecycled(t *testing.T) {
+	var (
+		allocated            []uint64
+		cacheLineNumber      uint64
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		lines                []uint64
+		neededToBlock        bool
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	globalsLock("cache_pin_test.go:29:2:TestCacheLinePinnedNotRecycled")
+	lines, _ = allocateDataCacheLines(globals.dataCacheLineFreeLRU.lruCount.Load(), "")
+	globalsLock("cache_pin_test.go:31:2:TestCacheLinePinnedNotRecycled")
+	for _, cacheLineNumber = range lines {
+		dataCacheLineTracker = &globals.dataCacheLinesTracker[cacheLineNumber]
+		dataCacheLineTracker.inodeNumber = globals.config.backends["ram"].inode.inodeNumber
+		dataCacheLineTracker.lineNumber = cacheLineNumber
+		globals.dataCacheLineCleanLRU.pushTail(dataCacheLineTracker)
+	}
+
+	if len(lines) < 2 {
+		globalsUnlock()
+		t.Fatalf("expected at least 2 data cache lines but got %v", len(lines))
+	}
+
+	globals.dataCacheLinesTracker[lines[0]].pin()
+
+	allocated, neededToBlock = allocateDataCacheLines(1, "")
+	if neededToBlock || !slices.Equal(allocated, lines[1:2]) {
+		t.Fatalf("allocateDataCacheLines(1) should have skipped pinned %v returning %v but returned %v (neededToBlock: %v)", lines[0], lines[1], allocated, neededToBlock)
+	}
+
+	// Pin every remaining Clean data cache line such that allocation must await an unpin()
+
+	globalsLock("cache_pin_test.go:53:2:TestCacheLinePinnedNotRecycled")
+	for _, cacheLineNumber = range lines[2:] {
+		globals.dataCacheLinesTracker[cacheLineNumber].pin()
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		globals.dataCacheLinesTracker[lines[0]].unpin()
+	}()
+
+	allocated, neededToBlock = allocateDataCacheLines(1, "")
+	if !neededToBlock || !slices.Equal(allocated, lines[0:1]) {
+		t.Fatalf("allocateDataCacheLines(1) should have awaited unpin() of %v but returned %v (neededToBlock: %v)", lines[0], allocated, neededToBlock)
+	}
+
+	for _, cacheLineNumber = range lines[2:] {
+		globals.dataCacheLinesTracker[cacheLineNumber].unpin()
+	}
+}
+
+// TestCacheLineReadDuringEvict races DoRead()s of fileB against a goroutine continually
+// evicting (and freeing for reuse) the Clean data cache lines being read, verifying that
+// every DoRead() returns the expected content. It is most effective when run with -race.
+func TestCacheLineReadDuringEvict(t *testing.T) {
+	const (
+		testReaders         = 4
+		testReadsPerReader  = 200
+		testReadWindowLines = 4
+	)
+
+	var (
+		done      = make(chan struct{})
+		errno     syscall.Errno
+		evictorWG sync.WaitGroup
+		fileBFH   uint64
+		fileBIno  uint64
+		lookupOut *fission.LookupOut
+		openOut   *fission.OpenOut
+		readerWG  sync.WaitGroup
+		readLimit uint64
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.LookupIn{Name: []byte("ram")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") unexpectedly failed (errno: %v)", errno)
+	}
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: lookupOut.EntryOut.NodeID}, &fission.LookupIn{Name: []byte("fileB")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(ramDirIno,Name:\"fileB\") unexpectedly failed (errno: %v)", errno)
+	}
+	fileBIno = lookupOut.EntryOut.NodeID
+
+	openOut, errno = globals.DoOpen(&fission.InHeader{NodeID: fileBIno}, &fission.OpenIn{Flags: fission.FOpenRequestRDONLY})
+	if errno != 0 {
+		t.Fatalf("DoOpen(fileBIno, Flags: fission.FOpenRequestRDONLY) unexpectedly failed (errno: %v)", errno)
+	}
+	fileBFH = openOut.FH
+
+	readLimit = min(testReadWindowLines*globals.config.cacheLineSize, testFissionFileBLen)
+
+	evictorWG.Add(1)
+	go func() {
+		var (
+			dataCacheLineTracker *dataCacheLineTrackerStruct
+		)
+
+		defer evictorWG.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			globalsLock("cache_pin_test.go:131:4:funcLit@117")
+			for range 2 {
+				dataCacheLineTracker, _ = globals.dataCacheLineCleanLRU.popHeadUnpinned()
+				if dataCacheLineTracker == nil {
+					break
+				}
+				dataCacheLineTracker.evict()
+				dataCacheLineTracker.free()
+			}
+			globalsUnlock()
+
+			runtime.Gosched()
+		}
+	}()
+
+	for range testReaders {
+		readerWG.Add(1)
+		go func() {
+			var (
+				errno   syscall.Errno
+				offset  uint64
+				readOut *fission.ReadOut
+				size    uint64
+			)
+
+			defer readerWG.Done()
+
+			for readIndex := range testReadsPerReader {
+				offset = (uint64(readIndex) * 4093) % readLimit
+				size = min(uint64(testFissionReadBufSize), readLimit-offset)
+
+				readOut, errno = globals.DoRead(&fission.InHeader{NodeID: fileBIno}, &fission.ReadIn{FH: fileBFH, Offset: offset, Size: uint32(size)})
+				if errno != 0 {
+					t.Errorf("DoRead(FH: fileBFH, Offset: %v) unexpectedly failed (errno: %v)", offset, errno)
+					return
+				}
+				if !bytes.Equal(readOut.Data, testFissionFileBContent[offset:offset+uint64(len(readOut.Data))]) {
+					t.Errorf("DoRead(FH: fileBFH, Offset: %v) returned mismatched bytes", offset)
+					return
+				}
+			}
+		}()
+	}
+
+	readerWG.Wait()
+	close(done)
+	evictorWG.Wait()
+
+	errno = globals.DoRelease(&fission.InHeader{NodeID: fileBIno}, &fission.ReleaseIn{FH: fileBFH})
+	if errno != 0 {
+		t.Fatalf("DoRelease(fileBFH) unexpectedly failed (errno: %v)", errno)
+	}
+}
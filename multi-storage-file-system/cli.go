@@ -0,0 +1,519 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	cliCatChunkSize = uint64(8 * 1024 * 1024) // Byte range fetched by each readFile() issued by the "cat" subcommand
+)
+
+// `subcommandStruct` describes a subcommand (i.e. "<osArgs[0]> <name> ...") of the binary.
+type subcommandStruct struct {
+	name  string                // Matched against osArgs[1]
+	usage string                // Arguments following "<osArgs[0]> <name>" as displayed in help text
+	run   func(osArgs []string) // Passed the entire osArgs; if nil, handled instead by main() (e.g. "mount")
+}
+
+// `subcommands` lists the subcommands of the binary in the order displayed in help text.
+// Those that access backends (i.e. "cat", "ls", and "stat") do so via the same
+// backendContextIf implementations used by the FUSE file system but do not require
+// FUSE (e.g. /dev/fuse need not be available in a container).
+var subcommands = []subcommandStruct{
+	{name: "bench", usage: "[--files N] [--concurrency N] [--duration D] [--pattern {sequential|random}] [--read-size B] [--mount] <backend>[/<prefix>] [<config-file>]", run: runBench},
+	{name: "cat", usage: "<backend>/<path> [<config-file>]", run: runCat},
+	{name: "cleanup", usage: "[--older-than D] <backend> [<config-file>]", run: runCleanup},
+	{name: "generate-manifest", usage: "--backend <name> [--output <path>] [--workers N] [--temp-dir <dir>] [<config-file>]", run: runGenerateManifest},
+	{name: "ls", usage: "[-l] [-R] <backend>[/<path>] [<config-file>]", run: runLs},
+	{name: "mount", usage: "<config-file> <mountpoint> [-sfnv] [-o <options>]", run: nil},
+	{name: "stat", usage: "<backend>[/<path>] [<config-file>]", run: runStat},
+	{name: "usage", usage: "--backend <name> [--prefix <prefix>] [--parallelism N] [<config-file>]", run: runUsage},
+	{name: "validate", usage: "[<config-file>]", run: runValidate},
+}
+
+// `cliLogToStderr` directs all logging to os.Stderr such that the output of a subcommand
+// written to os.Stdout (e.g. the content of a file for "cat") is not interleaved with it.
+func cliLogToStderr() {
+	globals.logging.destinationWriter = &logDestinationWriterStruct{
+		writeAt: func(_ slog.Level, p []byte) (err error) {
+			_, err = os.Stderr.Write(p)
+			return
+		},
+	}
+}
+
+// `cliParseArgs` parses the flags of the subcommand osArgs[1] expecting pathArgs positional
+// arguments followed by an optional <config-file>. Upon success, the config-file is parsed
+// and the positional arguments returned. Otherwise, usage is displayed and the process exits.
+func cliParseArgs(osArgs []string, fs *flag.FlagSet, usage string, pathArgs int) (args []string) {
+	var (
+		configArgs []string
+		err        error
+	)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s %s %s\n", osArgs[0], osArgs[1], usage)
+		fs.PrintDefaults()
+	}
+
+	if err = fs.Parse(osArgs[2:]); err != nil {
+		os.Exit(1)
+	}
+
+	if (fs.NArg() < pathArgs) || (fs.NArg() > (pathArgs + 1)) {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Remaining non-flag args beyond pathArgs are treated as [config-file]
+	configArgs = []string{osArgs[0]}
+	if fs.NArg() > pathArgs {
+		configArgs = append(configArgs, fs.Arg(pathArgs))
+	}
+
+	cliLogToStderr()
+
+	initGlobals(configArgs)
+
+	err = checkConfigFile()
+	if err != nil {
+		dumpStack()
+		// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
+		globals.logger.Fatalf("[FATAL] parsing config-file (\"%s\") failed: %s", globals.configFilePath, redactSecrets(nil, err.Error()))
+	}
+
+	err = initLogging(globals.config)
+	if err != nil {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] unable to open log_file (\"%s\"): %v", globals.config.logFile, err)
+	}
+
+	args = fs.Args()[:pathArgs]
+
+	return
+}
+
+// `cliBackend` splits backendPath (i.e. "<backend>[/<path>]") and sets up the named backend
+// returning it along with the <path> (relative to the backend's prefix). Upon failure, the
+// process exits.
+func cliBackend(backendPath string) (backend *backendStruct, path string) {
+	var (
+		backendName string
+		err         error
+		ok          bool
+	)
+
+	backendName, path, _ = strings.Cut(backendPath, "/")
+
+	backend, ok = globals.backendsToMount[backendName]
+	if !ok {
+		globals.logger.Fatalf("[FATAL] backend %q not found in config (available: %v)", backendName, backendNames())
+	}
+
+	err = backend.setupContext()
+	if err != nil {
+		globals.logger.Fatalf("[FATAL] setup backend context for %q failed: %s", backendName, redactSecrets(backend, err.Error()))
+	}
+
+	return
+}
+
+// `runCat` handles the "cat" subcommand.
+// It writes the content of the specified file of a backend to os.Stdout.
+func runCat(osArgs []string) {
+	var (
+		args           []string
+		backend        *backendStruct
+		err            error
+		filePath       string
+		fs             = flag.NewFlagSet("cat", flag.ExitOnError)
+		offset         uint64
+		readFileOutput *readFileOutputStruct
+		statFileOutput *statFileOutputStruct
+	)
+
+	args = cliParseArgs(osArgs, fs, "<backend>/<path> [<config-file>]", 1)
+
+	backend, filePath = cliBackend(args[0])
+
+	statFileOutput, err = statFileWrapper(context.Background(), backend.context, &statFileInputStruct{filePath: filePath})
+	if err != nil {
+		globals.logger.Fatalf("[FATAL] cat of \"%s\" failed: %s", args[0], redactSecrets(backend, err.Error()))
+	}
+
+	// Each range is conditional upon the eTag such that a concurrently replaced file is detected
+
+	for offset = 0; offset < statFileOutput.size; offset += cliCatChunkSize {
+		readFileOutput, err = readFileWrapper(context.Background(), backend.context, &readFileInputStruct{
+			filePath: filePath,
+			offset:   offset,
+			length:   cliCatChunkSize,
+			ifMatch:  statFileOutput.eTag,
+		})
+		if err != nil {
+			globals.logger.Fatalf("[FATAL] cat of \"%s\" failed: %s", args[0], redactSecrets(backend, err.Error()))
+		}
+
+		_, err = os.Stdout.Write(readFileOutput.buf)
+		if err != nil {
+			os.Exit(1)
+		}
+	}
+
+	os.Exit(0)
+}
+
+// `runCleanup` handles the "cleanup" subcommand.
+// It aborts the multipart uploads of a backend initiated longer ago than --older-than (by
+// default, multipart_upload_max_age) displaying the path of each.
+func runCleanup(osArgs []string) {
+	var (
+		abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct
+		aborted                     abortedMultipartUploadStruct
+		args                        []string
+		backend                     *backendStruct
+		err                         error
+		fs                          = flag.NewFlagSet("cleanup", flag.ExitOnError)
+		olderThanSet                bool
+		olderThan                   = fs.Duration("older-than", 0, "abort multipart uploads initiated longer ago than this (default: multipart_upload_max_age)")
+	)
+
+	args = cliParseArgs(osArgs, fs, "[--older-than D] <backend> [<config-file>]", 1)
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "older-than" {
+			olderThanSet = true
+		}
+	})
+
+	if olderThanSet {
+		if *olderThan <= 0 {
+			fmt.Fprintf(os.Stderr, "error: --older-than must be > 0 (got %s)\n", *olderThan)
+			fs.Usage()
+			os.Exit(1)
+		}
+	} else {
+		*olderThan = globals.config.multipartUploadMaxAge
+	}
+
+	backend, _ = cliBackend(args[0])
+
+	abortMultipartUploadsOutput, err = abortMultipartUploadsWrapper(context.Background(), backend.context, &abortMultipartUploadsInputStruct{
+		initiatedBefore: time.Now().Add(-*olderThan),
+	})
+	if err != nil {
+		globals.logger.Fatalf("[FATAL] cleanup of \"%s\" failed: %s", args[0], redactSecrets(backend, err.Error()))
+	}
+
+	for _, aborted = range abortMultipartUploadsOutput.aborted {
+		fmt.Printf("aborted %s  %s\n", aborted.initiated.UTC().Format(time.RFC3339), aborted.filePath)
+	}
+
+	os.Exit(0)
+}
+
+// `runLs` handles the "ls" subcommand.
+// It lists the subdirectories (each displayed with a trailing "/") and files of the
+// specified directory of a backend or, if -R is specified, every file beneath it.
+func runLs(osArgs []string) {
+	var (
+		args                []string
+		backend             *backendStruct
+		dirPath             string
+		err                 error
+		file                listDirectoryOutputFileStruct
+		fs                  = flag.NewFlagSet("ls", flag.ExitOnError)
+		listDirectoryInput  *listDirectoryInputStruct
+		listDirectoryOutput *listDirectoryOutputStruct
+		listObjectsInput    *listObjectsInputStruct
+		listObjectsOutput   *listObjectsOutputStruct
+		long                = fs.Bool("l", false, "also display the size, modification time, and eTag of each file")
+		object              listObjectsOutputObjectStruct
+		recursive           = fs.Bool("R", false, "list every file beneath the directory (by their paths relative to it)")
+		subdirectory        string
+	)
+
+	args = cliParseArgs(osArgs, fs, "[-l] [-R] <backend>[/<path>] [<config-file>]", 1)
+
+	backend, dirPath = cliBackend(args[0])
+
+	if (dirPath != "") && !strings.HasSuffix(dirPath, "/") {
+		dirPath += "/"
+	}
+
+	if *recursive {
+		listObjectsInput = &listObjectsInputStruct{prefix: dirPath}
+
+		for {
+			listObjectsOutput, err = listObjectsWrapper(context.Background(), backend.context, listObjectsInput)
+			if err != nil {
+				globals.logger.Fatalf("[FATAL] ls of \"%s\" failed: %s", args[0], redactSecrets(backend, err.Error()))
+			}
+
+			for _, object = range listObjectsOutput.object {
+				cliLsPrint(*long, strings.TrimPrefix(object.path, dirPath), object.size, object.mTime, object.eTag)
+			}
+
+			if !listObjectsOutput.isTruncated || (listObjectsOutput.nextContinuationToken == "") {
+				break
+			}
+
+			listObjectsInput.continuationToken = listObjectsOutput.nextContinuationToken
+		}
+
+		os.Exit(0)
+	}
+
+	listDirectoryInput = &listDirectoryInputStruct{dirPath: dirPath}
+
+	for {
+		listDirectoryOutput, err = listDirectoryWrapper(context.Background(), backend.context, listDirectoryInput)
+		if err != nil {
+			globals.logger.Fatalf("[FATAL] ls of \"%s\" failed: %s", args[0], redactSecrets(backend, err.Error()))
+		}
+
+		sort.Strings(listDirectoryOutput.subdirectory)
+
+		for _, subdirectory = range listDirectoryOutput.subdirectory {
+			cliLsPrint(false, subdirectory+"/", 0, time.Time{}, "")
+		}
+
+		for _, file = range listDirectoryOutput.file {
+			cliLsPrint(*long, file.basename, file.size, file.mTime, file.eTag)
+		}
+
+		if !listDirectoryOutput.isTruncated || (listDirectoryOutput.nextContinuationToken == "") {
+			break
+		}
+
+		listDirectoryInput.continuationToken = listDirectoryOutput.nextContinuationToken
+	}
+
+	os.Exit(0)
+}
+
+// `cliLsPrint` outputs a line of "ls" output.
+func cliLsPrint(long bool, name string, size uint64, mTime time.Time, eTag string) {
+	if long {
+		fmt.Printf("%12d  %s  %-34s  %s\n", size, mTime.UTC().Format(time.RFC3339), eTag, name)
+	} else {
+		fmt.Println(name)
+	}
+}
+
+// `runStat` handles the "stat" subcommand.
+// It displays the metadata of the specified file of a backend or, should the path end
+// with a "/" or not refer to a file, confirms the path refers to a directory.
+func runStat(osArgs []string) {
+	var (
+		args           []string
+		backend        *backendStruct
+		err            error
+		fs             = flag.NewFlagSet("stat", flag.ExitOnError)
+		metadataKey    string
+		metadataKeys   []string
+		path           string
+		statFileOutput *statFileOutputStruct
+	)
+
+	args = cliParseArgs(osArgs, fs, "<backend>[/<path>] [<config-file>]", 1)
+
+	backend, path = cliBackend(args[0])
+
+	if (path != "") && !strings.HasSuffix(path, "/") {
+		statFileOutput, err = statFileWrapper(context.Background(), backend.context, &statFileInputStruct{filePath: path})
+		if err == nil {
+			fmt.Printf("path:          %s\n", path)
+			fmt.Printf("type:          file\n")
+			fmt.Printf("size:          %d (%s)\n", statFileOutput.size, formatBytes(statFileOutput.size))
+			fmt.Printf("mtime:         %s\n", statFileOutput.mTime.UTC().Format(time.RFC3339Nano))
+			fmt.Printf("etag:          %s\n", statFileOutput.eTag)
+			if statFileOutput.storageClass != "" {
+				fmt.Printf("storage_class: %s\n", statFileOutput.storageClass)
+			}
+			metadataKeys = make([]string, 0, len(statFileOutput.metadata))
+			for metadataKey = range statFileOutput.metadata {
+				metadataKeys = append(metadataKeys, metadataKey)
+			}
+			sort.Strings(metadataKeys)
+			for _, metadataKey = range metadataKeys {
+				fmt.Printf("metadata:      %s=%s\n", metadataKey, statFileOutput.metadata[metadataKey])
+			}
+			os.Exit(0)
+		}
+
+		path += "/"
+	}
+
+	_, err = statDirectoryWrapper(context.Background(), backend.context, &statDirectoryInputStruct{dirPath: path})
+	if err != nil {
+		globals.logger.Fatalf("[FATAL] stat of \"%s\" failed: no such file or directory (%s)", args[0], redactSecrets(backend, err.Error()))
+	}
+
+	fmt.Printf("path:          %s\n", path)
+	fmt.Printf("type:          directory\n")
+
+	os.Exit(0)
+}
+
+// `runGenerateManifest` handles the "generate-manifest" subcommand.
+// It parses the config, sets up the specified backend, runs the BFS manifest
+// generation pipeline, and exits.
+func runGenerateManifest(osArgs []string) {
+	fs := flag.NewFlagSet("generate-manifest", flag.ExitOnError)
+	backendName := fs.String("backend", "", "backend name from config (required)")
+	outputPath := fs.String("output", ".msfs_manifest", "output manifest directory path (per-directory format)")
+	workers := fs.Int("workers", defaultManifestGenWorkers, "number of parallel listing workers")
+	tempDir := fs.String("temp-dir", "", "directory for temporary shard files (default: system temp)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s generate-manifest --backend <name> [--output <path>] [--workers N] [--temp-dir <dir>] [<config-file>]\n", osArgs[0])
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(osArgs[2:]); err != nil {
+		os.Exit(1)
+	}
+
+	if *backendName == "" {
+		fmt.Fprintf(os.Stderr, "error: --backend is required\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Remaining non-flag args are treated as [config-file]
+	configArgs := []string{osArgs[0]}
+	if fs.NArg() > 0 {
+		configArgs = append(configArgs, fs.Arg(0))
+	}
+
+	initGlobals(configArgs)
+
+	err := checkConfigFile()
+	if err != nil {
+		dumpStack()
+		// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
+		globals.logger.Fatalf("[FATAL] parsing config-file (\"%s\") failed: %s", globals.configFilePath, redactSecrets(nil, err.Error()))
+	}
+
+	cfg := &manifestGenConfig{
+		workers:     *workers,
+		outputPath:  *outputPath,
+		tempDir:     *tempDir,
+		backendName: *backendName,
+	}
+
+	globals.logger.Printf("[INFO] manifest-gen: starting (backend=%q, workers=%d, output=%q)",
+		cfg.backendName, cfg.workers, cfg.outputPath)
+
+	err = generateManifest(cfg)
+	if err != nil {
+		// CodeQL [SM01413]: clear-text-logging false positive — manifest-gen errors
+		// originate from backend listings and writeFile, never embed credentials.
+		globals.logger.Fatalf("[FATAL] manifest-gen failed: %s", redactSecrets(cfg.backend, err.Error()))
+	}
+
+	os.Exit(0)
+}
+
+// `runUsage` handles the "usage" subcommand.
+// It parses the config, sets up the specified backend, totals the objects and
+// bytes under the specified prefix via a parallel sharded walk, and exits.
+func runUsage(osArgs []string) {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	backendName := fs.String("backend", "", "backend name from config (required)")
+	prefix := fs.String("prefix", "", "prefix (relative to the backend's prefix) to total")
+	parallelism := fs.Int("parallelism", defaultWalkParallelism, "maximum number of shards listed concurrently")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s usage --backend <name> [--prefix <prefix>] [--parallelism N] [<config-file>]\n", osArgs[0])
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(osArgs[2:]); err != nil {
+		os.Exit(1)
+	}
+
+	if *backendName == "" {
+		fmt.Fprintf(os.Stderr, "error: --backend is required\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Remaining non-flag args are treated as [config-file]
+	configArgs := []string{osArgs[0]}
+	if fs.NArg() > 0 {
+		configArgs = append(configArgs, fs.Arg(0))
+	}
+
+	initGlobals(configArgs)
+
+	err := checkConfigFile()
+	if err != nil {
+		dumpStack()
+		// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
+		globals.logger.Fatalf("[FATAL] parsing config-file (\"%s\") failed: %s", globals.configFilePath, redactSecrets(nil, err.Error()))
+	}
+
+	backend, ok := globals.backendsToMount[*backendName]
+	if !ok {
+		globals.logger.Fatalf("[FATAL] backend %q not found in config (available: %v)", *backendName, backendNames())
+	}
+	err = backend.setupContext()
+	if err != nil {
+		globals.logger.Fatalf("[FATAL] setup backend context for %q failed: %s", *backendName, redactSecrets(backend, err.Error()))
+	}
+
+	startTime := time.Now()
+
+	objects, bytes, shards, err := prefixUsage(context.Background(), backend, *prefix, *parallelism)
+	if err != nil {
+		globals.logger.Fatalf("[FATAL] usage failed: %s", redactSecrets(backend, err.Error()))
+	}
+
+	fmt.Printf("%d objects, %d bytes (%s) under %q [%d shards listed in %v]\n", objects, bytes, formatBytes(bytes), *prefix, shards, time.Since(startTime).Round(time.Millisecond))
+
+	os.Exit(0)
+}
+
+// `runValidate` handles the "validate" subcommand.
+// It parses the config (resolving any secret references) exactly as would be done
+// prior to mounting, reports every problem found, and exits non-zero if there were any.
+func runValidate(osArgs []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s validate [<config-file>]\n", osArgs[0])
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(osArgs[2:]); err != nil {
+		os.Exit(1)
+	}
+
+	// Remaining non-flag args are treated as [config-file]
+	configArgs := []string{osArgs[0]}
+	if fs.NArg() > 0 {
+		configArgs = append(configArgs, fs.Arg(0))
+	}
+
+	initGlobals(configArgs)
+
+	err := checkConfigFile()
+	if err != nil {
+		// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
+		fmt.Fprintf(os.Stderr, "config-file \"%s\" is invalid: %s\n", globals.configFilePath, redactSecrets(nil, err.Error()))
+		os.Exit(1)
+	}
+
+	fmt.Printf("config-file \"%s\" is valid (%d backends)\n", globals.configFilePath, len(globals.backendsToMount))
+
+	os.Exit(0)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+// TestSubcommands verifies that subcommand names are unique and that each (other than
+// "mount", which is handled by main()) has a run func.
+func TestSubcommands(t *testing.T) {
+	names := make(map[string]struct{})
+
+	for _, subcommand := range subcommands {
+		if _, ok := names[subcommand.name]; ok {
+			t.Fatalf("duplicate subcommand \"%s\"", subcommand.name)
+		}
+		names[subcommand.name] = struct{}{}
+
+		if (subcommand.run == nil) != (subcommand.name == "mount") {
+			t.Fatalf("subcommand \"%s\" has unexpected run func", subcommand.name)
+		}
+	}
+}
+
+// TestCLIBackend verifies that cliBackend() splits "<backend>/<path>" and sets up the
+// named backend such that its backendContextIf may be used directly.
+func TestCLIBackend(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    readonly: false,
+    backend_type: RAM,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	backend, path := cliBackend("ram/dir/file")
+	if backend.dirName != "ram" {
+		t.Fatalf("cliBackend() returned backend \"%s\", expected \"ram\"", backend.dirName)
+	}
+	if path != "dir/file" {
+		t.Fatalf("cliBackend() returned path \"%s\", expected \"dir/file\"", path)
+	}
+
+	_, err = putFileWrapper(context.Background(), backend.context, &putFileInputStruct{filePath: path, buf: []byte("content")})
+	if err != nil {
+		t.Fatalf("putFileWrapper() failed: %v", err)
+	}
+
+	statFileOutput, err := statFileWrapper(context.Background(), backend.context, &statFileInputStruct{filePath: path})
+	if err != nil {
+		t.Fatalf("statFileWrapper() failed: %v", err)
+	}
+	if statFileOutput.size != uint64(len("content")) {
+		t.Fatalf("statFileWrapper() returned size %v, expected %v", statFileOutput.size, len("content"))
+	}
+
+	backend, path = cliBackend("ram")
+	if (backend.dirName != "ram") || (path != "") {
+		t.Fatalf("cliBackend(\"ram\") returned (\"%s\", \"%s\")", backend.dirName, path)
+	}
+}
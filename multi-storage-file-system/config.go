@@ -4,15 +4,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"math"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/drone/envsubst"
 	"gopkg.in/yaml.v3"
+
+	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/msc"
+	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry"
+	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry/metrics/readers"
 )
 
 const (
@@ -37,6 +47,16 @@ const (
 	defaultRAMMaxTotalObjects     = uint64(10000)
 
 	minimumCacheLines = uint64(16)
+
+	defaultCacheLineSize = uint64(10485760) // 10Mi
+
+	cacheLineSizeAlignment          = uint64(4096)    // 4Ki
+	recommendedMinimumCacheLineSize = uint64(1048576) // 1Mi
+	minimumMultiPartUploadPartSize  = uint64(5242880) // 5Mi (S3's minimum for all but the last part)
+
+	maximumKeySaltWidth = uint64(8) // Number of hex digits in a CRC32
+
+	defaultReadDirSnapshotMaxEntries = uint64(100000)
 )
 
 // `parseAny` provides a convenient test for the existence of
@@ -288,6 +308,132 @@ func logDeprecation(msg string) {
 	}
 }
 
+// logConfigWarning emits a one-line warning about a legal but pathological
+// configuration when the logger is available (it may be nil during the very first parse).
+func logConfigWarning(msg string) {
+	if globals.logger != nil {
+		globals.logger.Printf("[WARN] %s", msg)
+	}
+}
+
+// `parseMSCSize` converts a Python MSC size setting into bytes (see msc.ParseSize()).
+func parseMSCSize(sizeAsInterface interface{}) (size uint64, ok bool) {
+	return msc.ParseSize(sizeAsInterface)
+}
+
+// `translateMSCCacheSection` maps the Python MSC `cache` section onto the equivalent MSFS settings
+// such that a single config file yields consistent caching between the library and the mount:
+//
+//	location (or cache_backend.cache_path) => cache_dir_path (with cache_storage "per-inode-file")
+//	cache_line_size                        => cache_line_size
+//	size (or the deprecated size_mb)       => cache_lines (i.e. size / cache_line_size)
+//
+// As MSFS only implements LRU eviction and always validates eTags, a warning is logged for an
+// eviction_policy.policy other than "lru" or a use_etag of false.
+func translateMSCCacheSection(cacheAsMap map[string]interface{}, configFileMapTranslated map[string]interface{}) (err error) {
+	var (
+		cacheBackendAsInterface   interface{}
+		cacheBackendAsMap         map[string]interface{}
+		cacheLineSize             = defaultCacheLineSize
+		cacheLineSizeAsInterface  interface{}
+		cacheLocation             string
+		cacheSize                 uint64
+		cacheSizeAsInterface      interface{}
+		cacheSizeMB               uint64
+		evictionPolicy            string
+		evictionPolicyAsInterface interface{}
+		evictionPolicyAsMap       map[string]interface{}
+		ok                        bool
+		useETag                   bool
+	)
+
+	switch {
+	case parseAny(cacheAsMap, "location"):
+		cacheLocation, ok = parseString(cacheAsMap, "location", nil)
+		if !ok {
+			err = errors.New("bad cache location")
+			return
+		}
+	case parseAny(cacheAsMap, "cache_backend"):
+		cacheBackendAsInterface = cacheAsMap["cache_backend"]
+		cacheBackendAsMap, ok = cacheBackendAsInterface.(map[string]interface{})
+		if !ok {
+			err = errors.New("bad cache cache_backend")
+			return
+		}
+		cacheLocation, ok = parseString(cacheBackendAsMap, "cache_path", "")
+		if !ok {
+			err = errors.New("bad cache cache_backend cache_path")
+			return
+		}
+	}
+
+	if cacheLocation != "" {
+		configFileMapTranslated["cache_dir_path"] = cacheLocation
+		configFileMapTranslated["cache_storage"] = cacheStoragePerInodeFile
+	}
+
+	cacheLineSizeAsInterface, ok = cacheAsMap["cache_line_size"]
+	if ok {
+		cacheLineSize, ok = parseMSCSize(cacheLineSizeAsInterface)
+		if !ok || (cacheLineSize == 0) {
+			err = errors.New("bad cache cache_line_size")
+			return
+		}
+
+		configFileMapTranslated["cache_line_size"] = cacheLineSize
+	}
+
+	cacheSizeAsInterface, ok = cacheAsMap["size"]
+	if ok {
+		cacheSize, ok = parseMSCSize(cacheSizeAsInterface)
+		if !ok {
+			err = errors.New("bad cache size")
+			return
+		}
+	} else if parseAny(cacheAsMap, "size_mb") {
+		cacheSizeMB, ok = parseUint64(cacheAsMap, "size_mb", nil)
+		if !ok {
+			err = errors.New("bad cache size_mb")
+			return
+		}
+		cacheSize = cacheSizeMB << 20
+	}
+
+	if cacheSize != 0 {
+		configFileMapTranslated["cache_lines"] = max((cacheSize+cacheLineSize-1)/cacheLineSize, minimumCacheLines)
+	}
+
+	evictionPolicyAsInterface, ok = cacheAsMap["eviction_policy"]
+	if ok {
+		evictionPolicyAsMap, ok = evictionPolicyAsInterface.(map[string]interface{})
+		if !ok {
+			err = errors.New("bad cache eviction_policy")
+			return
+		}
+		evictionPolicy, ok = parseString(evictionPolicyAsMap, "policy", "lru")
+		if !ok {
+			err = errors.New("bad cache eviction_policy policy")
+			return
+		}
+		if strings.ToLower(evictionPolicy) != "lru" {
+			logConfigWarning(fmt.Sprintf("cache eviction_policy policy \"%s\" not supported; LRU eviction will be used", evictionPolicy))
+		}
+	}
+
+	useETag, ok = parseBool(cacheAsMap, "use_etag", true)
+	if !ok {
+		err = errors.New("bad cache use_etag")
+		return
+	}
+	if !useETag {
+		logConfigWarning("cache use_etag false not supported; cached content will be validated against eTags")
+	}
+
+	err = nil
+	return
+}
+
 func parseString(m map[string]interface{}, key string, dflt interface{}) (s string, ok bool) {
 	var (
 		err error
@@ -320,6 +466,118 @@ func parseString(m map[string]interface{}, key string, dflt interface{}) (s stri
 	return
 }
 
+// `parseEndpoints` fetches the optional list of endpoints from an S3 section. Each must be
+// an "http://" or "https://" URL and all must share the same path (differing only in their
+// scheme and host) as requests are redirected among them by substituting just those parts.
+func parseEndpoints(backendConfigS3AsMap map[string]interface{}) (endpoints []string, err error) {
+	var (
+		endpoint             string
+		endpointAsInterface  interface{}
+		endpointParsed       *url.URL
+		endpointPath         string
+		endpointsAsInterface interface{}
+		endpointsAsSlice     []interface{}
+		ok                   bool
+	)
+
+	endpointsAsInterface, ok = backendConfigS3AsMap["endpoints"]
+	if !ok {
+		return
+	}
+
+	endpointsAsSlice, ok = endpointsAsInterface.([]interface{})
+	if !ok {
+		err = errors.New("endpoints must be a list")
+		return
+	}
+
+	endpoints = make([]string, 0, len(endpointsAsSlice))
+
+	for _, endpointAsInterface = range endpointsAsSlice {
+		endpoint, ok = endpointAsInterface.(string)
+		if !ok {
+			err = errors.New("each endpoint must be a string")
+			return
+		}
+
+		endpointParsed, err = url.Parse(endpoint)
+		if (err != nil) || ((endpointParsed.Scheme != "http") && (endpointParsed.Scheme != "https")) || (endpointParsed.Host == "") {
+			err = fmt.Errorf("endpoint \"%s\" must be an http:// or https:// URL", endpoint)
+			return
+		}
+
+		if len(endpoints) == 0 {
+			endpointPath = strings.TrimSuffix(endpointParsed.Path, "/")
+		} else if strings.TrimSuffix(endpointParsed.Path, "/") != endpointPath {
+			err = fmt.Errorf("endpoint \"%s\" path differs from that of endpoint \"%s\"", endpoint, endpoints[0])
+			return
+		}
+
+		if slices.Contains(endpoints, endpoint) {
+			err = fmt.Errorf("endpoint \"%s\" duplicated", endpoint)
+			return
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return
+}
+
+// `parseSecret` fetches what is expected to be a secret value (see msc.ParseSecret()).
+func parseSecret(m map[string]interface{}, key string, dflt interface{}) (s string, err error) {
+	return msc.ParseSecret(m, key, dflt)
+}
+
+// `parseRequestHeaders` fetches the optional map of static request headers from an
+// S3 section. Header names are canonicalized. Authorization and Host may not be set.
+func parseRequestHeaders(backendConfigS3AsMap map[string]interface{}) (requestHeaders map[string]string, err error) {
+	var (
+		ok                            bool
+		requestHeaderName             string
+		requestHeaderValue            string
+		requestHeaderValueAsInterface interface{}
+		requestHeadersAsInterface     interface{}
+		requestHeadersAsMap           map[string]interface{}
+	)
+
+	requestHeaders = make(map[string]string)
+
+	requestHeadersAsInterface, ok = backendConfigS3AsMap["request_headers"]
+	if !ok {
+		return
+	}
+
+	requestHeadersAsMap, ok = requestHeadersAsInterface.(map[string]interface{})
+	if !ok {
+		err = errors.New("request_headers must be a map")
+		return
+	}
+
+	for requestHeaderName, requestHeaderValueAsInterface = range requestHeadersAsMap {
+		if (requestHeaderName == "") || strings.ContainsAny(requestHeaderName, " \t\r\n:") {
+			err = fmt.Errorf("invalid header name \"%s\"", requestHeaderName)
+			return
+		}
+
+		requestHeaderName = http.CanonicalHeaderKey(requestHeaderName)
+		if (requestHeaderName == "Authorization") || (requestHeaderName == "Host") {
+			err = fmt.Errorf("header \"%s\" may not be overridden", requestHeaderName)
+			return
+		}
+
+		requestHeaderValue, ok = requestHeaderValueAsInterface.(string)
+		if !ok || strings.ContainsAny(requestHeaderValue, "\r\n") {
+			err = fmt.Errorf("bad value for header \"%s\"", requestHeaderName)
+			return
+		}
+
+		requestHeaders[requestHeaderName] = requestHeaderValue
+	}
+
+	return
+}
+
 // `parseUint64` fetches what is expected to be a uint64 value for the
 // specified key from the map. If the key is missing and a non-nil
 // dflt is provided, the func will return this dflt.
@@ -361,6 +619,354 @@ func parseUint64(m map[string]interface{}, key string, dflt interface{}) (u uint
 	return
 }
 
+// `metricsAttributeProviderTypes` lists the opentelemetry.metrics.attributes[].type values
+// supported by processAttributeProviders().
+var metricsAttributeProviderTypes = []string{"environment_variables", "host", "msc_config", "mount_instance", "process", "static"}
+
+// `metricsViewAggregations` lists the opentelemetry.metrics.views[].aggregation values
+// supported by telemetry.SetupMetricsDiperiodic().
+var metricsViewAggregations = []string{
+	telemetry.AggregationDefault,
+	telemetry.AggregationDrop,
+	telemetry.AggregationExplicitBucketHistogram,
+	telemetry.AggregationExponentialHistogram,
+	telemetry.AggregationLastValue,
+	telemetry.AggregationSum,
+}
+
+// `parseMetricsConfig` parses the optional opentelemetry.metrics section (matching the MSC
+// Python schema: opentelemetry.metrics.{attributes, reader, exporter} plus the MSFS-specific
+// backend_attributes) into obs. Malformed settings are reported via err such that they are
+// caught by checkConfigFile() (and the validate subcommand) rather than leaving initObservability()
+// to skip metrics initialization at startup. Attribute provider and exporter types valid for
+// MSC Python but not supported here (e.g. a "console" exporter) merely elicit a warning.
+func parseMetricsConfig(opentelemetryAsMap map[string]interface{}, obs *observabilityConfigStruct) (err error) {
+	var (
+		attributeProvider             attributeProviderStruct
+		attributeProviderAsInterface  interface{}
+		attributeProviderAsMap        map[string]interface{}
+		attributeProviderIndex        int
+		attributeProvidersAsInterface []interface{}
+		backendAttribute              string
+		backendAttributeAsInterface   interface{}
+		backendAttributesAsInterface  []interface{}
+		boundary                      float64
+		boundaryAsInterface           interface{}
+		boundaryIndex                 int
+		boundariesAsInterface         []interface{}
+		exporterAsMap                 map[string]interface{}
+		metricsAsMap                  map[string]interface{}
+		ok                            bool
+		readerAsMap                   map[string]interface{}
+		readerOptions                 *readerOptionsStruct
+		readerOptionsAsMap            map[string]interface{}
+		view                          metricsViewStruct
+		viewAsInterface               interface{}
+		viewAsMap                     map[string]interface{}
+		viewIndex                     int
+		viewsAsInterface              []interface{}
+	)
+
+	if !parseAny(opentelemetryAsMap, "metrics") {
+		return
+	}
+
+	metricsAsMap, ok = opentelemetryAsMap["metrics"].(map[string]interface{})
+	if !ok {
+		err = errors.New("bad opentelemetry.metrics section")
+		return
+	}
+
+	// Parse metrics.attributes (array of attribute providers)
+
+	if parseAny(metricsAsMap, "attributes") {
+		attributeProvidersAsInterface, ok = metricsAsMap["attributes"].([]interface{})
+		if !ok {
+			err = errors.New("bad opentelemetry.metrics.attributes (must be a list)")
+			return
+		}
+
+		for attributeProviderIndex, attributeProviderAsInterface = range attributeProvidersAsInterface {
+			attributeProviderAsMap, ok = attributeProviderAsInterface.(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("bad opentelemetry.metrics.attributes[%v]", attributeProviderIndex)
+				return
+			}
+
+			attributeProvider = attributeProviderStruct{
+				Options: make(map[string]interface{}),
+			}
+
+			attributeProvider.Type, ok = parseString(attributeProviderAsMap, "type", nil)
+			if !ok || (attributeProvider.Type == "") {
+				err = fmt.Errorf("bad opentelemetry.metrics.attributes[%v].type", attributeProviderIndex)
+				return
+			}
+			if !slices.Contains(metricsAttributeProviderTypes, attributeProvider.Type) {
+				logConfigWarning(fmt.Sprintf("opentelemetry.metrics.attributes[%v].type \"%s\" not supported (supported: %v); ignored", attributeProviderIndex, attributeProvider.Type, metricsAttributeProviderTypes))
+			}
+
+			if parseAny(attributeProviderAsMap, "options") {
+				attributeProvider.Options, ok = attributeProviderAsMap["options"].(map[string]interface{})
+				if !ok {
+					err = fmt.Errorf("bad opentelemetry.metrics.attributes[%v].options", attributeProviderIndex)
+					return
+				}
+			}
+
+			obs.metricsAttributes = append(obs.metricsAttributes, attributeProvider)
+		}
+	}
+
+	// Parse metrics.backend_attributes (an MSFS extension opting into per-backend attributes)
+
+	if parseAny(metricsAsMap, "backend_attributes") {
+		backendAttributesAsInterface, ok = metricsAsMap["backend_attributes"].([]interface{})
+		if !ok {
+			err = errors.New("bad opentelemetry.metrics.backend_attributes (must be a list)")
+			return
+		}
+
+		for _, backendAttributeAsInterface = range backendAttributesAsInterface {
+			backendAttribute, ok = backendAttributeAsInterface.(string)
+			if !ok || (backendAttribute != metricsBackendAttributeBucket) {
+				err = fmt.Errorf("bad opentelemetry.metrics.backend_attributes element (%v) (must be \"%s\")", backendAttributeAsInterface, metricsBackendAttributeBucket)
+				return
+			}
+			if !slices.Contains(obs.metricsBackendAttributes, backendAttribute) {
+				obs.metricsBackendAttributes = append(obs.metricsBackendAttributes, backendAttribute)
+			}
+		}
+	}
+
+	// Parse metrics.views (an MSFS extension overriding the aggregation of specific instruments)
+
+	if parseAny(metricsAsMap, "views") {
+		viewsAsInterface, ok = metricsAsMap["views"].([]interface{})
+		if !ok {
+			err = errors.New("bad opentelemetry.metrics.views (must be a list)")
+			return
+		}
+
+		for viewIndex, viewAsInterface = range viewsAsInterface {
+			viewAsMap, ok = viewAsInterface.(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("bad opentelemetry.metrics.views[%v]", viewIndex)
+				return
+			}
+
+			view = metricsViewStruct{}
+
+			view.Instrument, ok = parseString(viewAsMap, "instrument", nil)
+			if !ok || (view.Instrument == "") {
+				err = fmt.Errorf("bad opentelemetry.metrics.views[%v].instrument", viewIndex)
+				return
+			}
+
+			view.Aggregation, ok = parseString(viewAsMap, "aggregation", nil)
+			if !ok || !slices.Contains(metricsViewAggregations, view.Aggregation) {
+				err = fmt.Errorf("bad opentelemetry.metrics.views[%v].aggregation (must be one of %v)", viewIndex, metricsViewAggregations)
+				return
+			}
+
+			if parseAny(viewAsMap, "boundaries") {
+				if view.Aggregation != telemetry.AggregationExplicitBucketHistogram {
+					err = fmt.Errorf("bad opentelemetry.metrics.views[%v].boundaries (only supported for \"%s\" aggregation)", viewIndex, telemetry.AggregationExplicitBucketHistogram)
+					return
+				}
+
+				boundariesAsInterface, ok = viewAsMap["boundaries"].([]interface{})
+				if !ok {
+					err = fmt.Errorf("bad opentelemetry.metrics.views[%v].boundaries (must be a list)", viewIndex)
+					return
+				}
+
+				for boundaryIndex, boundaryAsInterface = range boundariesAsInterface {
+					switch boundaryAsType := boundaryAsInterface.(type) {
+					case float64:
+						boundary, ok = boundaryAsType, true
+					case int:
+						boundary, ok = float64(boundaryAsType), true
+					default:
+						ok = false
+					}
+					if !ok || ((boundaryIndex > 0) && (boundary <= view.Boundaries[boundaryIndex-1])) {
+						err = fmt.Errorf("bad opentelemetry.metrics.views[%v].boundaries[%v] (must be increasing numbers)", viewIndex, boundaryIndex)
+						return
+					}
+					view.Boundaries = append(view.Boundaries, boundary)
+				}
+			}
+
+			obs.metricsViews = append(obs.metricsViews, view)
+		}
+	}
+
+	// Parse metrics.reader.options
+
+	if parseAny(metricsAsMap, "reader") {
+		readerAsMap, ok = metricsAsMap["reader"].(map[string]interface{})
+		if !ok {
+			err = errors.New("bad opentelemetry.metrics.reader section")
+			return
+		}
+
+		readerOptionsAsMap = make(map[string]interface{})
+
+		if parseAny(readerAsMap, "options") {
+			readerOptionsAsMap, ok = readerAsMap["options"].(map[string]interface{})
+			if !ok {
+				err = errors.New("bad opentelemetry.metrics.reader.options section")
+				return
+			}
+		}
+
+		readerOptions = &readerOptionsStruct{}
+
+		readerOptions.CollectIntervalMillis, ok = parseUint64(readerOptionsAsMap, "collect_interval_millis", uint64(1000))
+		if !ok || (readerOptions.CollectIntervalMillis == 0) {
+			err = errors.New("bad opentelemetry.metrics.reader.options.collect_interval_millis (must be > 0)")
+			return
+		}
+
+		readerOptions.CollectTimeoutMillis, ok = parseUint64(readerOptionsAsMap, "collect_timeout_millis", uint64(10000))
+		if !ok || (readerOptions.CollectTimeoutMillis == 0) {
+			err = errors.New("bad opentelemetry.metrics.reader.options.collect_timeout_millis (must be > 0)")
+			return
+		}
+
+		readerOptions.ExportIntervalMillis, ok = parseUint64(readerOptionsAsMap, "export_interval_millis", uint64(60000))
+		if !ok || (readerOptions.ExportIntervalMillis == 0) {
+			err = errors.New("bad opentelemetry.metrics.reader.options.export_interval_millis (must be > 0)")
+			return
+		}
+
+		readerOptions.ExportTimeoutMillis, ok = parseUint64(readerOptionsAsMap, "export_timeout_millis", uint64(30000))
+		if !ok || (readerOptions.ExportTimeoutMillis == 0) {
+			err = errors.New("bad opentelemetry.metrics.reader.options.export_timeout_millis (must be > 0)")
+			return
+		}
+
+		readerOptions.MaxBufferedSnapshots, ok = parseUint64(readerOptionsAsMap, "max_buffered_snapshots", uint64(readers.DefaultMaxBufferedSnapshots))
+		if !ok || (readerOptions.MaxBufferedSnapshots == 0) {
+			err = errors.New("bad opentelemetry.metrics.reader.options.max_buffered_snapshots (must be > 0)")
+			return
+		}
+
+		readerOptions.Temporality, ok = parseString(readerOptionsAsMap, "temporality", readers.TemporalityCumulative)
+		if !ok || ((readerOptions.Temporality != readers.TemporalityCumulative) && (readerOptions.Temporality != readers.TemporalityDelta)) {
+			err = fmt.Errorf("bad opentelemetry.metrics.reader.options.temporality (must be \"%s\" or \"%s\")", readers.TemporalityCumulative, readers.TemporalityDelta)
+			return
+		}
+
+		obs.metricsReaderOptions = readerOptions
+	}
+
+	// Parse metrics.exporter (type + options)
+
+	if parseAny(metricsAsMap, "exporter") {
+		exporterAsMap, ok = metricsAsMap["exporter"].(map[string]interface{})
+		if !ok {
+			err = errors.New("bad opentelemetry.metrics.exporter section")
+			return
+		}
+
+		obs.metricsExporter = &exporterStruct{
+			Options: make(map[string]interface{}),
+		}
+
+		obs.metricsExporter.Type, ok = parseString(exporterAsMap, "type", nil)
+		if !ok || (obs.metricsExporter.Type == "") {
+			err = errors.New("bad opentelemetry.metrics.exporter.type")
+			return
+		}
+
+		if parseAny(exporterAsMap, "options") {
+			obs.metricsExporter.Options, ok = exporterAsMap["options"].(map[string]interface{})
+			if !ok {
+				err = errors.New("bad opentelemetry.metrics.exporter.options section")
+				return
+			}
+		}
+
+		err = checkMetricsExporter(obs.metricsExporter)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// `checkMetricsExporter` validates the options of a metrics exporter of a type supported by
+// initObservability(). For any other type, a warning is logged (as metrics will not be exported).
+func checkMetricsExporter(exporter *exporterStruct) (err error) {
+	var (
+		authAsMap          map[string]interface{}
+		exporterSubAsMap   map[string]interface{}
+		key                string
+		ok                 bool
+		scopeAsInterface   interface{}
+		scopesAsInterfaces []interface{}
+		value              string
+	)
+
+	switch exporter.Type {
+	case "otlp":
+		value, ok = exporter.Options["endpoint"].(string)
+		if !ok || (value == "") {
+			err = errors.New("bad opentelemetry.metrics.exporter.options.endpoint (required for an \"otlp\" exporter)")
+			return
+		}
+		if parseAny(exporter.Options, "insecure") {
+			_, ok = exporter.Options["insecure"].(bool)
+			if !ok {
+				err = errors.New("bad opentelemetry.metrics.exporter.options.insecure (must be a boolean)")
+				return
+			}
+		}
+	case "_otlp_msal":
+		authAsMap, ok = exporter.Options["auth"].(map[string]interface{})
+		if !ok {
+			err = errors.New("bad opentelemetry.metrics.exporter.options.auth section (required for an \"_otlp_msal\" exporter)")
+			return
+		}
+		for _, key = range []string{"client_id", "client_credential", "authority"} {
+			value, ok = authAsMap[key].(string)
+			if !ok || (value == "") {
+				err = fmt.Errorf("bad opentelemetry.metrics.exporter.options.auth.%s (required for an \"_otlp_msal\" exporter)", key)
+				return
+			}
+		}
+		scopesAsInterfaces, ok = authAsMap["scopes"].([]interface{})
+		if !ok || (len(scopesAsInterfaces) == 0) {
+			err = errors.New("bad opentelemetry.metrics.exporter.options.auth.scopes (a non-empty list is required for an \"_otlp_msal\" exporter)")
+			return
+		}
+		for _, scopeAsInterface = range scopesAsInterfaces {
+			value, ok = scopeAsInterface.(string)
+			if !ok || (value == "") {
+				err = fmt.Errorf("bad opentelemetry.metrics.exporter.options.auth.scopes element (%v)", scopeAsInterface)
+				return
+			}
+		}
+		exporterSubAsMap, ok = exporter.Options["exporter"].(map[string]interface{})
+		if !ok {
+			err = errors.New("bad opentelemetry.metrics.exporter.options.exporter section (required for an \"_otlp_msal\" exporter)")
+			return
+		}
+		value, ok = exporterSubAsMap["endpoint"].(string)
+		if !ok || (value == "") {
+			err = errors.New("bad opentelemetry.metrics.exporter.options.exporter.endpoint (required for an \"_otlp_msal\" exporter)")
+			return
+		}
+	default:
+		logConfigWarning(fmt.Sprintf("opentelemetry.metrics.exporter.type \"%s\" not supported (supported: \"otlp\", \"_otlp_msal\"); metrics will not be exported", exporter.Type))
+	}
+
+	return
+}
+
 // `checkConfigFile` parses globals.configFilePath in either JSON or YAML
 // format following either the MSC Python-compatible or MSFS-specific
 // specification. Upon success, it will also populate both the
@@ -372,9 +978,11 @@ func checkConfigFile() (err error) {
 		backendsAsInterface                   interface{}
 		backendsAsInterfaceSlice              []interface{}
 		backendsAsInterfaceSliceIndex         int
+		backendTraceLevel                     uint64
 		backendAsMap                          map[string]interface{}
 		backendAsStructNew                    *backendStruct
 		backendAsStructOld                    *backendStruct
+		backendLogLevel                       slog.Level
 		backendConfigAIStoreAsInterface       interface{}
 		backendConfigAIStoreAsMap             map[string]interface{}
 		backendConfigAIStoreAsStruct          *backendConfigAIStoreStruct
@@ -395,23 +1003,37 @@ func checkConfigFile() (err error) {
 		configFileMap                         map[string]interface{}
 		configFileMapTranslated               map[string]interface{}
 		configFilePathExt                     string
-		credentialsProviderAsInterface        interface{}
-		credentialsProviderAsMap              map[string]interface{}
-		credentialsProviderOptionsAsInterface interface{}
-		credentialsProviderOptionsAsMap       map[string]interface{}
-		credentialsProviderOptionsAccessKey   string
-		credentialsProviderOptionsSecretKey   string
-		credentialsProviderType               string
+		configSchemaProblems                  []error
+		configSchemaWarning                   string
+		configSchemaWarnings                  []string
 		dirName                               string
 		dirPerm                               string
 		dirtyCacheLinesFlushTriggerPercentage uint64
 		dirtyCacheLinesMaxPercentage          uint64
+		cachePartition                        cachePartitionStruct
+		cachePartitionAsInterface             interface{}
+		cachePartitionAsMap                   map[string]interface{}
+		cachePartitionDefaultLimitPercentage  uint64
+		cachePartitionLimitPercentage         uint64
+		cachePartitionsAsInterface            interface{}
+		cachePartitionsAsInterfaceSlice       []interface{}
+		cachePartitionsAsInterfaceSliceIndex  int
+		errorHint                             errorHintStruct
+		errorHintAsInterface                  interface{}
+		errorHintAsMap                        map[string]interface{}
+		errorHintsAsInterface                 interface{}
+		errorHintsAsInterfaceSlice            []interface{}
+		errorHintsAsInterfaceSliceIndex       int
 		filePerm                              string
 		inodeEvictionQueueKeysPerPageMin      uint64
 		inodeMapKeysPerPageMin                uint64
+		logLevelAsString                      string
 		nextRetryDelay                        time.Duration
 		ok                                    bool
 		physChildDirEntryMapKeysPerPageMin    uint64
+		cacheAsInterface                      interface{}
+		cacheAsMap                            map[string]interface{}
+		cacheLineSize                         uint64
 		posixAllowOther                       bool
 		posixAsInterface                      interface{}
 		posixAsMap                            map[string]interface{}
@@ -423,16 +1045,9 @@ func checkConfigFile() (err error) {
 		profileName                           string
 		profilesAsInterface                   interface{}
 		profilesAsMap                         map[string]interface{}
-		storageProviderAsInterface            interface{}
-		storageProviderAsMap                  map[string]interface{}
-		storageProviderOptionsAsInterface     interface{}
-		storageProviderOptionsAsMap           map[string]interface{}
-		storageProviderOptionsBasePath        string
-		storageProviderOptionsBasePathPrefix  string
-		storageProviderOptionsBasePathSplit   []string
-		storageProviderOptionsEndpointURL     string
-		storageProviderOptionsRegionName      string
-		storageProviderType                   string
+		proxyURLParsed                        *url.URL
+		skipReason                            string
+		snapshotTimeAsString                  string
 		virtChildDirEntryMapKeysPerPageMin    uint64
 	)
 
@@ -478,190 +1093,66 @@ func checkConfigFile() (err error) {
 
 	switch config.msfsVersion {
 	case MSFSVersionPythonCompatibility:
-		profilesAsInterface, ok = configFileMap["profiles"]
-		if ok && (profilesAsInterface != nil) {
-			profilesAsMap, ok = profilesAsInterface.(map[string]interface{})
+		configFileMapTranslated = make(map[string]interface{})
+
+		// The cache section is translated first as the profile storage_provider options
+		// translation depends upon the (translated) cache_line_size
+
+		cacheAsInterface, ok = configFileMap["cache"]
+		if ok && (cacheAsInterface != nil) {
+			cacheAsMap, ok = cacheAsInterface.(map[string]interface{})
 			if !ok {
-				err = errors.New("bad profiles section")
+				err = errors.New("bad cache section")
 				return
 			}
 
-			backendsAsInterfaceSlice = make([]interface{}, 0, len(profilesAsMap))
-
-			for profileName, profileAsInterface = range profilesAsMap {
-				profileAsMap, ok = profileAsInterface.(map[string]interface{})
-				if !ok {
-					err = fmt.Errorf("bad profile \"%s\"", profileName)
-					return
-				}
-
-				storageProviderAsInterface, ok = profileAsMap["storage_provider"]
-				if !ok {
-					// Skip this one as storageProvider not supported
-					_, ok = globals.backendsSkipped[profileName]
-					if !ok {
-						globals.logger.Printf("[INFO] skipping profile \"%s\" with no storage_provider", profileName)
-						globals.backendsSkipped[profileName] = struct{}{}
-					}
-					continue
-				}
-				storageProviderAsMap, ok = storageProviderAsInterface.(map[string]interface{})
-				if !ok {
-					err = fmt.Errorf("bad profile \"%s\" storage_provider", profileName)
-					return
-				}
-
-				storageProviderType, ok = parseString(storageProviderAsMap, "type", nil)
-				if !ok {
-					err = fmt.Errorf("missing or bad profile \"%s\" storage_provider type", profileName)
-					return
-				}
-				switch storageProviderType {
-				case "s3":
-					// This one is supported
-				case "s8k":
-					// This is compatible with "s3", so simply operate as if storageProviderType == "s3"
-				default:
-					// Skip this one as storageProviderType not currently supported
-					_, ok = globals.backendsSkipped[profileName]
-					if !ok {
-						globals.logger.Printf("[INFO] skipping profile \"%s\" with storage_provider \"%s\"", profileName, storageProviderType)
-						globals.backendsSkipped[profileName] = struct{}{}
-					}
-					continue
-				}
-
-				backendAsMap = make(map[string]interface{})
+			err = translateMSCCacheSection(cacheAsMap, configFileMapTranslated)
+			if err != nil {
+				return
+			}
+		}
 
-				backendAsMap["dir_name"] = profileName
+		cacheLineSize, ok = configFileMapTranslated["cache_line_size"].(uint64)
+		if !ok {
+			cacheLineSize = defaultCacheLineSize
+		}
 
-				backendConfigS3AsMap = make(map[string]interface{})
+		profilesAsInterface, ok = configFileMap["profiles"]
+		if ok && (profilesAsInterface != nil) {
+			profilesAsMap, ok = profilesAsInterface.(map[string]interface{})
+			if !ok {
+				err = errors.New("bad profiles section")
+				return
+			}
 
-				storageProviderOptionsAsInterface, ok = storageProviderAsMap["options"]
-				if !ok {
-					err = fmt.Errorf("missing profile \"%s\" storage_provider options", profileName)
-					return
-				}
-				storageProviderOptionsAsMap, ok = storageProviderOptionsAsInterface.(map[string]interface{})
-				if !ok {
-					err = fmt.Errorf("bad profile \"%s\" storage_provider options", profileName)
-					return
-				}
+			backendsAsInterfaceSlice = make([]interface{}, 0, len(profilesAsMap))
 
-				storageProviderOptionsBasePath, ok = parseString(storageProviderOptionsAsMap, "base_path", nil)
+			for profileName, profileAsInterface = range profilesAsMap {
+				profileAsMap, ok = profileAsInterface.(map[string]interface{})
 				if !ok {
-					err = fmt.Errorf("missing or bad profile \"%s\" storage_provider options base_path", profileName)
+					err = fmt.Errorf("bad profile \"%s\"", profileName)
 					return
 				}
 
-				storageProviderOptionsBasePathSplit = strings.Split(storageProviderOptionsBasePath, "/")
-				switch len(storageProviderOptionsBasePathSplit) {
-				case 0:
-					err = fmt.Errorf("bad profile \"%s\" storage_provider options base_path [empty]", profileName)
+				backendAsMap, skipReason, err = msc.TranslateProfile(profileName, profileAsMap, cacheLineSize)
+				if err != nil {
 					return
-				case 1:
-					backendAsMap["bucket_container_name"] = storageProviderOptionsBasePathSplit[0]
-					backendAsMap["prefix"] = ""
-				default:
-					backendAsMap["bucket_container_name"] = storageProviderOptionsBasePathSplit[0]
-					storageProviderOptionsBasePathPrefix = strings.Join(storageProviderOptionsBasePathSplit[1:], "/")
-					if !strings.HasSuffix(storageProviderOptionsBasePathPrefix, "/") {
-						storageProviderOptionsBasePathPrefix += "/"
-					}
-					backendAsMap["prefix"] = storageProviderOptionsBasePathPrefix
-				}
-
-				if parseAnyOf(storageProviderOptionsAsMap, []string{"endpoint_url", "region_name"}) {
-					backendConfigS3AsMap["use_config_env"] = false // The default
-
-					storageProviderOptionsRegionName, ok = parseString(storageProviderOptionsAsMap, "region_name", "")
-					if ok {
-						if storageProviderOptionsRegionName != "" {
-							backendConfigS3AsMap["region"] = storageProviderOptionsRegionName
-						}
-					} else {
-						err = fmt.Errorf("bad profile \"%s\" storage_provider options region_name", profileName)
-						return
-					}
-
-					storageProviderOptionsEndpointURL, ok = parseString(storageProviderOptionsAsMap, "endpoint_url", "${AWS_ENDPOINT}")
-					if ok {
-						if storageProviderOptionsEndpointURL != "" {
-							backendConfigS3AsMap["endpoint"] = storageProviderOptionsEndpointURL
-						}
-					} else {
-						err = fmt.Errorf("bad profile \"%s\" storage_provider options endpoint_url", profileName)
-						return
-					}
-				} else { // !parseAnyOf(storageProviderOptionsAsMap, []string{"endpoint_url", "region_name"})
-					backendConfigS3AsMap["use_config_env"] = true
 				}
-
-				credentialsProviderAsInterface, ok = profileAsMap["credentials_provider"]
-				if ok {
-					backendConfigS3AsMap["use_credentials_env"] = false // The default
-
-					credentialsProviderAsMap, ok = credentialsProviderAsInterface.(map[string]interface{})
-					if !ok {
-						err = fmt.Errorf("bad profile \"%s\" credentials_provider", profileName)
-						return
-					}
-
-					credentialsProviderType, ok = parseString(credentialsProviderAsMap, "type", nil)
-					if !ok {
-						err = fmt.Errorf("missing or bad profile \"%s\" credentials_provider type", profileName)
-						return
-					}
-					if credentialsProviderType != "S3Credentials" {
-						err = fmt.Errorf("bad profile \"%s\" storage_provider type (\"%s\") - must be \"S3Credentials\"", profileName, credentialsProviderType)
-						return
-					}
-
-					credentialsProviderOptionsAsInterface, ok = credentialsProviderAsMap["options"]
-					if !ok {
-						err = fmt.Errorf("missing profile \"%s\" credentials_provider options", profileName)
-						return
-					}
-					credentialsProviderOptionsAsMap, ok = credentialsProviderOptionsAsInterface.(map[string]interface{})
+				if skipReason != "" {
+					_, ok = globals.backendsSkipped[profileName]
 					if !ok {
-						err = fmt.Errorf("bad profile \"%s\" credentials_provider options", profileName)
-						return
-					}
-
-					credentialsProviderOptionsAccessKey, ok = parseString(credentialsProviderOptionsAsMap, "access_key", "")
-					if ok {
-						if credentialsProviderOptionsAccessKey != "" {
-							backendConfigS3AsMap["access_key_id"] = credentialsProviderOptionsAccessKey
-						}
-					} else {
-						err = fmt.Errorf("bad profile \"%s\" credentials_provider options access_key", profileName)
-						return
-					}
-
-					credentialsProviderOptionsSecretKey, ok = parseString(credentialsProviderOptionsAsMap, "secret_key", "")
-					if ok {
-						if credentialsProviderOptionsSecretKey != "" {
-							backendConfigS3AsMap["secret_access_key"] = credentialsProviderOptionsSecretKey
-						}
-					} else {
-						err = fmt.Errorf("bad profile \"%s\" credentials_provider options secret_key", profileName)
-						return
+						globals.logger.Printf("[INFO] skipping profile \"%s\" %s", profileName, skipReason)
+						globals.backendsSkipped[profileName] = struct{}{}
 					}
-				} else { // profileAsMap["credentials_provider"] returned !ok
-					backendConfigS3AsMap["use_credentials_env"] = true
+					continue
 				}
 
-				backendAsMap["backend_type"] = "S3"
-				backendAsMap["S3"] = backendConfigS3AsMap
-
 				backendsAsInterfaceSlice = append(backendsAsInterfaceSlice, backendAsMap)
 			}
 		} else { // (configFileMap["profiles"] returned !ok) || (profilesAsInterface == nil)
 			backendsAsInterfaceSlice = make([]interface{}, 0)
 		}
 
-		configFileMapTranslated = make(map[string]interface{})
-
 		configFileMapTranslated["msfs_version"] = MSFSVersionOne
 		configFileMapTranslated["backends"] = backendsAsInterfaceSlice
 
@@ -725,6 +1216,27 @@ func checkConfigFile() (err error) {
 		return
 	}
 
+	err = applyConfigOverrides(configFileMap)
+	if err != nil {
+		return
+	}
+
+	// Report all schema problems at once (a translated config-file's problems would
+	// already have been reported against its Python-compatible settings)
+
+	if config.msfsVersion == MSFSVersionOne {
+		configSchemaProblems, configSchemaWarnings = validateConfigSchema(configFileMap)
+
+		for _, configSchemaWarning = range configSchemaWarnings {
+			logConfigWarning(configSchemaWarning)
+		}
+
+		if len(configSchemaProblems) > 0 {
+			err = fmt.Errorf("%v problem(s) found:\n%w", len(configSchemaProblems), errors.Join(configSchemaProblems...))
+			return
+		}
+	}
+
 	config.mountName, ok = parseString(configFileMap, "mountname", "msfs")
 	if !ok {
 		err = errors.New("bad mountname value")
@@ -781,6 +1293,24 @@ func checkConfigFile() (err error) {
 		return
 	}
 
+	config.readOnly, ok = parseBool(configFileMap, "read_only", false)
+	if !ok {
+		err = errors.New("bad read_only value")
+		return
+	}
+
+	config.hideInaccessibleBackends, ok = parseBool(configFileMap, "hide_inaccessible_backends", false)
+	if !ok {
+		err = errors.New("bad hide_inaccessible_backends value")
+		return
+	}
+
+	config.defaultBackend, ok = parseString(configFileMap, "default_backend", "")
+	if !ok {
+		err = errors.New("bad default_backend value")
+		return
+	}
+
 	config.maxWrite, ok = parseUint64(configFileMap, "max_write", uint64(131072))
 	if !ok {
 		err = errors.New("bad max_write value")
@@ -803,6 +1333,26 @@ func checkConfigFile() (err error) {
 		return
 	}
 
+	config.entryTTL, ok = parseMilliseconds(configFileMap, "entry_ttl", config.entryAttrTTL)
+	if !ok {
+		err = errors.New("bad entry_ttl value")
+		return
+	}
+	if uint64(config.evictableInodeTTL) < uint64(config.entryTTL) {
+		err = fmt.Errorf("evictable_inode_ttl(%v) should be at least entry_ttl(%v)", config.evictableInodeTTL, config.entryTTL)
+		return
+	}
+
+	config.attrTTL, ok = parseMilliseconds(configFileMap, "attr_ttl", config.entryAttrTTL)
+	if !ok {
+		err = errors.New("bad attr_ttl value")
+		return
+	}
+	if uint64(config.evictableInodeTTL) < uint64(config.attrTTL) {
+		err = fmt.Errorf("evictable_inode_ttl(%v) should be at least attr_ttl(%v)", config.evictableInodeTTL, config.attrTTL)
+		return
+	}
+
 	config.virtualDirTTL, ok = parseMilliseconds(configFileMap, "virtual_dir_ttl", 1000000*time.Millisecond)
 	if !ok {
 		err = errors.New("bad virtual_dir_ttl value")
@@ -833,11 +1383,34 @@ func checkConfigFile() (err error) {
 		return
 	}
 
-	config.cacheLineSize, ok = parseUint64(configFileMap, "cache_line_size", uint64(10485760))
+	config.fuseOpTimeout, ok = parseMilliseconds(configFileMap, "fuse_op_timeout", time.Duration(0))
+	if !ok || (config.fuseOpTimeout < time.Duration(0)) {
+		err = errors.New("bad fuse_op_timeout value")
+		return
+	}
+
+	config.unmountDrainTimeout, ok = parseMilliseconds(configFileMap, "unmount_drain_timeout", 10*time.Second)
+	if !ok || (config.unmountDrainTimeout < time.Duration(0)) {
+		err = errors.New("bad unmount_drain_timeout value")
+		return
+	}
+
+	config.cacheLineSize, ok = parseUint64(configFileMap, "cache_line_size", defaultCacheLineSize)
 	if !ok {
 		err = errors.New("bad cache_line_size value")
 		return
 	}
+	if (config.cacheLineSize == 0) || ((config.cacheLineSize % cacheLineSizeAlignment) != 0) {
+		err = fmt.Errorf("cache_line_size (%v) must be a non-zero multiple of %v (4Ki)", config.cacheLineSize, cacheLineSizeAlignment)
+		return
+	}
+	if config.cacheLineSize < config.maxWrite {
+		err = fmt.Errorf("cache_line_size (%v) must be at least max_write (%v)", config.cacheLineSize, config.maxWrite)
+		return
+	}
+	if config.cacheLineSize < recommendedMinimumCacheLineSize {
+		logConfigWarning(fmt.Sprintf("cache_line_size (%v) is below the recommended minimum of %v (1Mi) - expect significant backend request amplification", config.cacheLineSize, recommendedMinimumCacheLineSize))
+	}
 
 	config.cacheLines, ok = parseUint64(configFileMap, "cache_lines", uint64(128))
 	if !ok {
@@ -895,6 +1468,89 @@ func checkConfigFile() (err error) {
 		return
 	}
 
+	config.cacheDedup, ok = parseBool(configFileMap, "cache_dedup", false)
+	if !ok {
+		err = errors.New("bad cache_dedup value")
+		return
+	}
+	if config.cacheDedup && (config.cacheStorage == cacheStoragePerInodeFile) {
+		err = fmt.Errorf("cache_dedup is not supported with cache_storage %q", cacheStoragePerInodeFile)
+		return
+	}
+
+	config.cachePartitionBy, ok = parseString(configFileMap, "cache_partition_by", "")
+	if !ok {
+		err = errors.New("bad cache_partition_by value")
+		return
+	}
+	switch config.cachePartitionBy {
+	case "":
+		// Data cache lines are shared without regard to requester
+	case cachePartitionByUID:
+		// Data cache lines are charged to the requesting process's uid
+	case cachePartitionByCgroup:
+		// Data cache lines are charged to the requesting process's cgroup
+	default:
+		err = fmt.Errorf("bad cache_partition_by value (%q): must be \"\", %q, or %q", config.cachePartitionBy, cachePartitionByUID, cachePartitionByCgroup)
+		return
+	}
+
+	cachePartitionDefaultLimitPercentage, ok = parseUint64(configFileMap, "cache_partition_default_limit", uint64(100))
+	if !ok {
+		err = errors.New("bad cache_partition_default_limit value")
+		return
+	}
+	if (cachePartitionDefaultLimitPercentage == 0) || (cachePartitionDefaultLimitPercentage > 100) {
+		err = errors.New("cache_partition_default_limit is a percentage so must be > 0 and <= 100")
+		return
+	}
+	config.cachePartitionDefaultLimit = (config.cacheLines * cachePartitionDefaultLimitPercentage) / uint64(100)
+
+	cachePartitionsAsInterface, ok = configFileMap["cache_partitions"]
+	if ok {
+		if config.cachePartitionBy == "" {
+			err = errors.New("cache_partitions requires cache_partition_by to be set")
+			return
+		}
+
+		cachePartitionsAsInterfaceSlice, ok = cachePartitionsAsInterface.([]interface{})
+		if !ok {
+			err = errors.New("bad cache_partitions section")
+			return
+		}
+
+		config.cachePartitions = make([]cachePartitionStruct, 0, len(cachePartitionsAsInterfaceSlice))
+
+		for cachePartitionsAsInterfaceSliceIndex, cachePartitionAsInterface = range cachePartitionsAsInterfaceSlice {
+			cachePartitionAsMap, ok = cachePartitionAsInterface.(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("bad cache_partitions[%v]", cachePartitionsAsInterfaceSliceIndex)
+				return
+			}
+
+			cachePartition = cachePartitionStruct{}
+
+			cachePartition.key, ok = parseString(cachePartitionAsMap, "key", nil)
+			if !ok || (cachePartition.key == "") {
+				err = fmt.Errorf("missing or bad key at cache_partitions[%v]", cachePartitionsAsInterfaceSliceIndex)
+				return
+			}
+			if slices.ContainsFunc(config.cachePartitions, func(other cachePartitionStruct) bool { return other.key == cachePartition.key }) {
+				err = fmt.Errorf("duplicate key (%q) at cache_partitions[%v]", cachePartition.key, cachePartitionsAsInterfaceSliceIndex)
+				return
+			}
+
+			cachePartitionLimitPercentage, ok = parseUint64(cachePartitionAsMap, "limit", nil)
+			if !ok || (cachePartitionLimitPercentage == 0) || (cachePartitionLimitPercentage > 100) {
+				err = fmt.Errorf("missing or bad limit at cache_partitions[%v] (a percentage so must be > 0 and <= 100)", cachePartitionsAsInterfaceSliceIndex)
+				return
+			}
+			cachePartition.limit = (config.cacheLines * cachePartitionLimitPercentage) / uint64(100)
+
+			config.cachePartitions = append(config.cachePartitions, cachePartition)
+		}
+	}
+
 	config.metadataCachePagingMode, ok = parseString(configFileMap, "metadata_cache_paging_mode", "pebble")
 	if !ok {
 		err = errors.New("bad metadata_cache_paging_mode value")
@@ -1080,86 +1736,228 @@ func checkConfigFile() (err error) {
 		return
 	}
 
+	config.memoryPressureInterval, ok = parseMilliseconds(configFileMap, "memory_pressure_interval", time.Duration(0))
+	if !ok {
+		err = errors.New("bad memory_pressure_interval value")
+		return
+	}
+
+	config.memoryPressureLimit, ok = parseUint64(configFileMap, "memory_pressure_limit", uint64(0))
+	if !ok {
+		err = errors.New("bad memory_pressure_limit value")
+		return
+	}
+
+	config.memoryPressureThreshold, ok = parseUint64(configFileMap, "memory_pressure_threshold", uint64(90))
+	if !ok || (config.memoryPressureThreshold == 0) || (config.memoryPressureThreshold > 100) {
+		err = errors.New("bad memory_pressure_threshold value (must be between 1 and 100)")
+		return
+	}
+
 	config.autoSIGHUPInterval, ok = parseSeconds(configFileMap, "auto_sighup_interval", time.Duration(0))
 	if !ok {
 		err = errors.New("bad auto_sighup_interval value")
 		return
 	}
 
+	config.backendReprobeInterval, ok = parseSeconds(configFileMap, "backend_reprobe_interval", 30*time.Second)
+	if !ok {
+		err = errors.New("bad backend_reprobe_interval value")
+		return
+	}
+
+	config.multipartUploadJanitorInterval, ok = parseSeconds(configFileMap, "multipart_upload_janitor_interval", time.Duration(0))
+	if !ok {
+		err = errors.New("bad multipart_upload_janitor_interval value")
+		return
+	}
+
+	config.multipartUploadMaxAge, ok = parseSeconds(configFileMap, "multipart_upload_max_age", 24*time.Hour)
+	if !ok || (config.multipartUploadMaxAge == 0) {
+		err = errors.New("bad multipart_upload_max_age value (must be > 0)")
+		return
+	}
+
+	config.presignedURLTTL, ok = parseSeconds(configFileMap, "presigned_url_ttl", time.Hour)
+	if !ok || (config.presignedURLTTL == 0) || (config.presignedURLTTL > 7*24*time.Hour) {
+		err = errors.New("bad presigned_url_ttl value (must be > 0 and <= 604800)")
+		return
+	}
+
+	config.treeSizeCacheTTL, ok = parseSeconds(configFileMap, "tree_size_cache_ttl", time.Minute)
+	if !ok {
+		err = errors.New("bad tree_size_cache_ttl value")
+		return
+	}
+
+	config.prefetchConcurrency, ok = parseUint64(configFileMap, "prefetch_concurrency", 16)
+	if !ok || (config.prefetchConcurrency == 0) {
+		err = errors.New("bad prefetch_concurrency value (must be > 0)")
+		return
+	}
+
 	// Parse observability configuration (optional) - matches MSC Python's "opentelemetry" key exactly
 	opentelemetryAsInterface, ok := configFileMap["opentelemetry"]
 	if ok {
-		opentelemetryAsMap, ok := opentelemetryAsInterface.(map[string]interface{})
+		opentelemetryAsMap, ok := opentelemetryAsInterface.(map[string]interface{})
+		if !ok {
+			err = errors.New("bad opentelemetry section")
+			return
+		}
+
+		obs := &observabilityConfigStruct{}
+
+		// Parse metrics section - matches Python schema: opentelemetry.metrics.{attributes, reader, exporter}
+		err = parseMetricsConfig(opentelemetryAsMap, obs)
+		if err != nil {
+			return
+		}
+
+		// Parse traces section: opentelemetry.traces.{exporter, sample_ratio}
+		obs.tracesSampleRatio = 1.0
+		tracesAsInterface, ok := opentelemetryAsMap["traces"]
+		if ok {
+			tracesAsMap, ok := tracesAsInterface.(map[string]interface{})
+			if !ok {
+				err = errors.New("bad opentelemetry.traces section")
+				return
+			}
+
+			obs.tracesSampleRatio, ok = parseFloat64(tracesAsMap, "sample_ratio", 1.0)
+			if !ok || (obs.tracesSampleRatio < 0.0) || (obs.tracesSampleRatio > 1.0) {
+				err = errors.New("bad opentelemetry.traces.sample_ratio value (must be between 0.0 and 1.0)")
+				return
+			}
+
+			if exporterAsInterface, ok := tracesAsMap["exporter"]; ok {
+				if exporterAsMap, ok := exporterAsInterface.(map[string]interface{}); ok {
+					exporter := &exporterStruct{}
+					exporter.Type, _ = parseString(exporterAsMap, "type", "")
+					if optionsAsInterface, ok := exporterAsMap["options"]; ok {
+						if optionsAsMap, ok := optionsAsInterface.(map[string]interface{}); ok {
+							exporter.Options = optionsAsMap
+						}
+					}
+					obs.tracesExporter = exporter
+				}
+			}
+		}
+
+		config.observability = obs
+	}
+
+	// Note: validation of endpoint, if != "", is performed in startHTTPHandler() rather than here.
+	config.endpoint, ok = parseString(configFileMap, "endpoint", "")
+	if !ok {
+		err = errors.New("bad endpoint value")
+		return
+	}
+
+	config.adminListen, ok = parseString(configFileMap, "admin_listen", "")
+	if ok && (config.adminListen != "") {
+		_, _, err = net.SplitHostPort(config.adminListen)
+		ok = (err == nil)
+	}
+	if !ok {
+		err = errors.New("bad admin_listen value (must be \"\" or \"<host>:<port>\")")
+		return
+	}
+
+	config.eventSink, ok = parseString(configFileMap, "event_sink", "")
+	if !ok || ((config.eventSink != "") && !strings.HasPrefix(config.eventSink, EventSinkFilePrefix) && !strings.HasPrefix(config.eventSink, EventSinkUnixPrefix)) {
+		err = errors.New("bad event_sink value (must be \"\", \"file:<path>\", or \"unix:<path>\")")
+		return
+	}
+
+	config.readyFile, ok = parseString(configFileMap, "ready_file", "")
+	if !ok {
+		err = errors.New("bad ready_file value")
+		return
+	}
+
+	config.readyProbeInterval, ok = parseMilliseconds(configFileMap, "ready_probe_interval", 1000*time.Millisecond)
+	if !ok || (config.readyProbeInterval == 0) {
+		err = errors.New("bad ready_probe_interval value (must be > 0)")
+		return
+	}
+
+	config.logFormat, ok = parseString(configFileMap, "log_format", LogFormatPlain)
+	if !ok || ((config.logFormat != LogFormatPlain) && (config.logFormat != LogFormatText) && (config.logFormat != LogFormatJSON)) {
+		err = errors.New("bad log_format value (must be \"plain\", \"text\", or \"json\")")
+		return
+	}
+
+	logLevelAsString, ok = parseString(configFileMap, "log_level", "trace")
+	if ok {
+		config.logLevel, ok = parseLogLevel(logLevelAsString)
+	}
+	if !ok {
+		err = errors.New("bad log_level value (must be one of \"trace\", \"debug\", \"info\", \"warn\", or \"error\")")
+		return
+	}
+
+	config.logFile, ok = parseString(configFileMap, "log_file", "")
+	if !ok {
+		err = errors.New("bad log_file value")
+		return
+	}
+
+	config.logFileMaxSize, ok = parseUint64(configFileMap, "log_file_max_size", uint64(0))
+	if !ok {
+		err = errors.New("bad log_file_max_size value")
+		return
+	}
+
+	config.logFileMaxBackups, ok = parseUint64(configFileMap, "log_file_max_backups", uint64(logFileMaxBackupsDefault))
+	if !ok {
+		err = errors.New("bad log_file_max_backups value")
+		return
+	}
+
+	errorHintsAsInterface, ok = configFileMap["error_hints"]
+	if ok {
+		errorHintsAsInterfaceSlice, ok = errorHintsAsInterface.([]interface{})
 		if !ok {
-			err = errors.New("bad opentelemetry section")
+			err = errors.New("bad error_hints section")
 			return
 		}
 
-		obs := &observabilityConfigStruct{}
+		config.errorHints = make([]errorHintStruct, 0, len(errorHintsAsInterfaceSlice))
 
-		// Parse metrics section - matches Python schema: opentelemetry.metrics.{attributes, reader, exporter}
-		metricsAsInterface, ok := opentelemetryAsMap["metrics"]
-		if ok {
-			metricsAsMap, ok := metricsAsInterface.(map[string]interface{})
-			if ok {
-				// Parse metrics.attributes (array of attribute providers)
-				if attributesAsInterface, ok := metricsAsMap["attributes"]; ok {
-					if attributesAsArray, ok := attributesAsInterface.([]interface{}); ok {
-						for _, attrAsInterface := range attributesAsArray {
-							if attrAsMap, ok := attrAsInterface.(map[string]interface{}); ok {
-								attrProvider := attributeProviderStruct{}
-								attrProvider.Type, _ = parseString(attrAsMap, "type", "")
-								if optionsAsInterface, ok := attrAsMap["options"]; ok {
-									if optionsAsMap, ok := optionsAsInterface.(map[string]interface{}); ok {
-										attrProvider.Options = optionsAsMap
-									}
-								}
-								obs.metricsAttributes = append(obs.metricsAttributes, attrProvider)
-							}
-						}
-					}
-				}
+		for errorHintsAsInterfaceSliceIndex, errorHintAsInterface = range errorHintsAsInterfaceSlice {
+			errorHintAsMap, ok = errorHintAsInterface.(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("bad error_hints[%v]", errorHintsAsInterfaceSliceIndex)
+				return
+			}
 
-				// Parse metrics.reader.options
-				if readerAsInterface, ok := metricsAsMap["reader"]; ok {
-					if readerAsMap, ok := readerAsInterface.(map[string]interface{}); ok {
-						if optionsAsInterface, ok := readerAsMap["options"]; ok {
-							if optionsAsMap, ok := optionsAsInterface.(map[string]interface{}); ok {
-								readerOpts := &readerOptionsStruct{}
-								readerOpts.CollectIntervalMillis, _ = parseUint64(optionsAsMap, "collect_interval_millis", 1000)
-								readerOpts.CollectTimeoutMillis, _ = parseUint64(optionsAsMap, "collect_timeout_millis", 10000)
-								readerOpts.ExportIntervalMillis, _ = parseUint64(optionsAsMap, "export_interval_millis", 60000)
-								readerOpts.ExportTimeoutMillis, _ = parseUint64(optionsAsMap, "export_timeout_millis", 30000)
-								obs.metricsReaderOptions = readerOpts
-							}
-						}
-					}
-				}
+			errorHint = errorHintStruct{}
 
-				// Parse metrics.exporter (type + options)
-				if exporterAsInterface, ok := metricsAsMap["exporter"]; ok {
-					if exporterAsMap, ok := exporterAsInterface.(map[string]interface{}); ok {
-						exporter := &exporterStruct{}
-						exporter.Type, _ = parseString(exporterAsMap, "type", "")
-						if optionsAsInterface, ok := exporterAsMap["options"]; ok {
-							if optionsAsMap, ok := optionsAsInterface.(map[string]interface{}); ok {
-								exporter.Options = optionsAsMap
-							}
-						}
-						obs.metricsExporter = exporter
-					}
-				}
+			errorHint.httpStatus, ok = parseUint64(errorHintAsMap, "http_status", uint64(0))
+			if !ok || ((errorHint.httpStatus != 0) && ((errorHint.httpStatus < 100) || (errorHint.httpStatus > 599))) {
+				err = fmt.Errorf("bad http_status at error_hints[%v]", errorHintsAsInterfaceSliceIndex)
+				return
 			}
-		}
 
-		config.observability = obs
-	}
+			errorHint.errorCode, ok = parseString(errorHintAsMap, "error_code", "")
+			if !ok {
+				err = fmt.Errorf("bad error_code at error_hints[%v]", errorHintsAsInterfaceSliceIndex)
+				return
+			}
 
-	// Note: validation of endpoint, if != "", is performed in startHTTPHandler() rather than here.
-	config.endpoint, ok = parseString(configFileMap, "endpoint", "")
-	if !ok {
-		err = errors.New("bad endpoint value")
-		return
+			if (errorHint.httpStatus == 0) && (errorHint.errorCode == "") {
+				err = fmt.Errorf("error_hints[%v] must specify http_status and/or error_code", errorHintsAsInterfaceSliceIndex)
+				return
+			}
+
+			errorHint.hint, ok = parseString(errorHintAsMap, "hint", nil)
+			if !ok || (errorHint.hint == "") {
+				err = fmt.Errorf("missing or bad hint at error_hints[%v]", errorHintsAsInterfaceSliceIndex)
+				return
+			}
+
+			config.errorHints = append(config.errorHints, errorHint)
+		}
 	}
 
 	backendsAsInterface, ok = configFileMap["backends"]
@@ -1194,6 +1992,9 @@ func checkConfigFile() (err error) {
 				err = fmt.Errorf("bad readonly at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 				return
 			}
+			if config.readOnly {
+				backendAsStructNew.readOnly = true
+			}
 
 			backendAsStructNew.flushOnClose, ok = parseBool(backendAsMap, "flush_on_close", true)
 			if !ok {
@@ -1267,7 +2068,18 @@ func checkConfigFile() (err error) {
 				return
 			}
 
-			backendAsStructNew.bucketContainerName, ok = parseString(backendAsMap, "bucket_container_name", nil)
+			if backendAsStructNew.uploadPartCacheLines == 0 {
+				err = fmt.Errorf("bad upload_part_cache_lines at backends[%v (\"%s\")] - must be at least 1", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+			if backendAsStructNew.uploadPartConcurrency == 0 {
+				err = fmt.Errorf("bad upload_part_concurrency at backends[%v (\"%s\")] - must be at least 1", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			// Presence of bucket_container_name is verified once S3.discover_buckets is known below
+
+			backendAsStructNew.bucketContainerName, ok = parseString(backendAsMap, "bucket_container_name", "")
 			if !ok {
 				err = fmt.Errorf("missing or bad bucket_container_name at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 				return
@@ -1283,10 +2095,26 @@ func checkConfigFile() (err error) {
 				return
 			}
 
-			backendAsStructNew.traceLevel, ok = parseUint64(backendAsMap, "trace_level", uint64(0))
-			if !ok {
-				err = fmt.Errorf("bad trace_level at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
-				return
+			if parseAny(backendAsMap, "log_level") {
+				logLevelAsString, ok = parseString(backendAsMap, "log_level", nil)
+				if ok {
+					backendLogLevel, ok = parseLogLevel(logLevelAsString)
+					backendAsStructNew.logLevel.Set(backendLogLevel)
+				}
+				if !ok {
+					err = fmt.Errorf("bad log_level at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+			} else if parseAny(backendAsMap, "trace_level") {
+				backendTraceLevel, ok = parseUint64(backendAsMap, "trace_level", nil)
+				if !ok {
+					err = fmt.Errorf("bad trace_level at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+				backendAsStructNew.logLevel.Set(traceLevelToLogLevel(backendTraceLevel))
+				logDeprecation(fmt.Sprintf("backends[\"%s\"] trace_level is deprecated; use log_level (\"%s\") instead", backendAsStructNew.dirName, strings.ToLower(logLevelString(backendAsStructNew.logLevel.Level()))))
+			} else {
+				backendAsStructNew.logLevel.Set(slog.LevelError)
 			}
 
 			backendAsStructNew.manifestPath, ok = parseString(backendAsMap, "manifest_path", "")
@@ -1295,6 +2123,54 @@ func checkConfigFile() (err error) {
 				return
 			}
 
+			backendAsStructNew.symLinkSuffix, ok = parseString(backendAsMap, "symlink_suffix", "")
+			if !ok || strings.Contains(backendAsStructNew.symLinkSuffix, "/") {
+				err = fmt.Errorf("bad symlink_suffix at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.cacheBypass, ok = parseBool(backendAsMap, "cache_bypass", false)
+			if !ok {
+				err = fmt.Errorf("bad cache_bypass at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.entryTTL, ok = parseMilliseconds(backendAsMap, "entry_ttl", config.entryTTL)
+			if !ok {
+				err = fmt.Errorf("bad entry_ttl at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+			if uint64(config.evictableInodeTTL) < uint64(backendAsStructNew.entryTTL) {
+				err = fmt.Errorf("evictable_inode_ttl(%v) should be at least entry_ttl(%v) at backends[%v (\"%s\")]", config.evictableInodeTTL, backendAsStructNew.entryTTL, backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.attrTTL, ok = parseMilliseconds(backendAsMap, "attr_ttl", config.attrTTL)
+			if !ok {
+				err = fmt.Errorf("bad attr_ttl at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+			if uint64(config.evictableInodeTTL) < uint64(backendAsStructNew.attrTTL) {
+				err = fmt.Errorf("evictable_inode_ttl(%v) should be at least attr_ttl(%v) at backends[%v (\"%s\")]", config.evictableInodeTTL, backendAsStructNew.attrTTL, backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.emulateFIFOs, ok = parseBool(backendAsMap, "emulate_fifos", false)
+			if !ok {
+				err = fmt.Errorf("bad emulate_fifos at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.keySaltWidth, ok = parseUint64(backendAsMap, "key_salt_width", uint64(0))
+			if !ok || (backendAsStructNew.keySaltWidth > maximumKeySaltWidth) {
+				err = fmt.Errorf("bad key_salt_width at backends[%v (\"%s\")] - must be 0 (disabled) or between 1 and %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, maximumKeySaltWidth)
+				return
+			}
+			if (backendAsStructNew.keySaltWidth != 0) && (backendAsStructNew.manifestPath != "") {
+				err = fmt.Errorf("key_salt_width and manifest_path are mutually exclusive at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
 			manifestGenWorkersU64, ok := parseUint64(backendAsMap, "manifest_gen_workers", uint64(defaultManifestGenWorkers))
 			if !ok {
 				err = fmt.Errorf("bad manifest_gen_workers at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
@@ -1319,12 +2195,140 @@ func checkConfigFile() (err error) {
 
 			backendAsStructNew.flatDirHints = parseFlatDirHints(backendAsMap)
 
+			backendAsStructNew.hedgeReadPercentile, ok = parseFloat64(backendAsMap, "hedge_read_percentile", float64(0))
+			if !ok || (backendAsStructNew.hedgeReadPercentile < 0) || (backendAsStructNew.hedgeReadPercentile >= 100) {
+				err = fmt.Errorf("bad hedge_read_percentile at backends[%v (\"%s\")] - must be 0 (disabled) or between 0 and 100", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.hedgeReadMinDelay, ok = parseMilliseconds(backendAsMap, "hedge_read_min_delay", 10*time.Millisecond)
+			if !ok {
+				err = fmt.Errorf("bad hedge_read_min_delay at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.hedgeReadBudget, ok = parseUint64(backendAsMap, "hedge_read_budget", uint64(5))
+			if !ok || (backendAsStructNew.hedgeReadBudget > 100) {
+				err = fmt.Errorf("bad hedge_read_budget at backends[%v (\"%s\")] - must be a percentage", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.readTimeout, ok = parseMilliseconds(backendAsMap, "read_timeout", time.Duration(0))
+			if !ok || (backendAsStructNew.readTimeout < time.Duration(0)) {
+				err = fmt.Errorf("bad read_timeout at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.listTimeout, ok = parseMilliseconds(backendAsMap, "list_timeout", time.Duration(0))
+			if !ok || (backendAsStructNew.listTimeout < time.Duration(0)) {
+				err = fmt.Errorf("bad list_timeout at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.headTimeout, ok = parseMilliseconds(backendAsMap, "head_timeout", time.Duration(0))
+			if !ok || (backendAsStructNew.headTimeout < time.Duration(0)) {
+				err = fmt.Errorf("bad head_timeout at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.writeTimeout, ok = parseMilliseconds(backendAsMap, "write_timeout", time.Duration(0))
+			if !ok || (backendAsStructNew.writeTimeout < time.Duration(0)) {
+				err = fmt.Errorf("bad write_timeout at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.readDirTimeBudget, ok = parseMilliseconds(backendAsMap, "readdir_time_budget", time.Duration(0))
+			if !ok || (backendAsStructNew.readDirTimeBudget < time.Duration(0)) {
+				err = fmt.Errorf("bad readdir_time_budget at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.readDirSnapshot, ok = parseBool(backendAsMap, "readdir_snapshot", false)
+			if !ok {
+				err = fmt.Errorf("bad readdir_snapshot at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.readDirSnapshotMaxEntries, ok = parseUint64(backendAsMap, "readdir_snapshot_max_entries", defaultReadDirSnapshotMaxEntries)
+			if !ok {
+				err = fmt.Errorf("bad readdir_snapshot_max_entries at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.maxConcurrentRequests, ok = parseUint64(backendAsMap, "max_concurrent_requests", uint64(0))
+			if !ok {
+				err = fmt.Errorf("bad max_concurrent_requests at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.readBandwidthLimit, ok = parseUint64(backendAsMap, "read_bandwidth_limit", uint64(0))
+			if !ok {
+				err = fmt.Errorf("bad read_bandwidth_limit at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.requestRateLimit, ok = parseUint64(backendAsMap, "request_rate_limit", uint64(0))
+			if !ok {
+				err = fmt.Errorf("bad request_rate_limit at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.chaos, err = parseChaos(backendAsMap)
+			if err != nil {
+				err = fmt.Errorf("bad chaos section at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
+				return
+			}
+
+			backendAsStructNew.acl, err = parseACL(backendAsMap)
+			if err != nil {
+				err = fmt.Errorf("bad access control list at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
+				return
+			}
+
+			backendAsStructNew.filter, err = parseFilter(backendAsMap)
+			if err != nil {
+				err = fmt.Errorf("bad include/exclude filter at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
+				return
+			}
+
+			backendAsStructNew.proxyURL, ok = parseString(backendAsMap, "proxy_url", "")
+			if ok && (backendAsStructNew.proxyURL != "") {
+				proxyURLParsed, err = url.Parse(backendAsStructNew.proxyURL)
+				ok = (err == nil) && slices.Contains([]string{"http", "https", "socks5"}, proxyURLParsed.Scheme) && (proxyURLParsed.Host != "")
+				err = nil
+			}
+			if !ok {
+				err = fmt.Errorf("bad proxy_url at backends[%v (\"%s\")] - must be an http://, https://, or socks5:// URL", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			backendAsStructNew.noProxy, ok = parseString(backendAsMap, "no_proxy", "")
+			if !ok {
+				err = fmt.Errorf("bad no_proxy at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
 			backendAsStructNew.backendType, ok = parseString(backendAsMap, "backend_type", nil)
 			if !ok {
 				err = fmt.Errorf("missing or bad bucket_container_name at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 				return
 			}
 
+			if ((backendAsStructNew.proxyURL != "") || (backendAsStructNew.noProxy != "")) && (backendAsStructNew.backendType != "AIStore") && (backendAsStructNew.backendType != "S3") {
+				err = fmt.Errorf("proxy_url and no_proxy not supported for backend_type \"%s\" at backends[%v (\"%s\")]", backendAsStructNew.backendType, backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
+			if !backendAsStructNew.readOnly {
+				if (backendAsStructNew.backendType == "S3") && ((backendAsStructNew.uploadPartCacheLines * config.cacheLineSize) < minimumMultiPartUploadPartSize) {
+					err = fmt.Errorf("upload_part_cache_lines (%v) * cache_line_size (%v) at backends[%v (\"%s\")] must be at least %v (5Mi)", backendAsStructNew.uploadPartCacheLines, config.cacheLineSize, backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, minimumMultiPartUploadPartSize)
+					return
+				}
+				if backendAsStructNew.multiPartCacheLineThreshold < backendAsStructNew.uploadPartCacheLines {
+					logConfigWarning(fmt.Sprintf("multipart_cache_line_threshold (%v) at backends[%v (\"%s\")] is below upload_part_cache_lines (%v) - files just over the threshold will be uploaded as a single Multi-Part Upload part", backendAsStructNew.multiPartCacheLineThreshold, backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, backendAsStructNew.uploadPartCacheLines))
+				}
+			}
+
 			switch backendAsStructNew.backendType {
 			case "AIStore":
 				backendConfigAIStoreAsInterface, ok = backendAsMap["AIStore"]
@@ -1349,9 +2353,9 @@ func checkConfigFile() (err error) {
 						return
 					}
 
-					backendConfigAIStoreAsStruct.authnToken, ok = parseString(backendConfigAIStoreAsMap, "authn_token", "${AIS_AUTHN_TOKEN}")
-					if !ok {
-						err = fmt.Errorf("bad AIStore.authn_token at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					backendConfigAIStoreAsStruct.authnToken, err = parseSecret(backendConfigAIStoreAsMap, "authn_token", "${AIS_AUTHN_TOKEN}")
+					if err != nil {
+						err = fmt.Errorf("bad AIStore.authn_token at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
 						return
 					}
 
@@ -1401,9 +2405,9 @@ func checkConfigFile() (err error) {
 
 					backendConfigGCSAsStruct = &backendConfigGCSStruct{}
 
-					backendConfigGCSAsStruct.apiKey, ok = parseString(backendConfigGCSAsMap, "api_key", "")
-					if !ok {
-						err = fmt.Errorf("bad GCS.api_key at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					backendConfigGCSAsStruct.apiKey, err = parseSecret(backendConfigGCSAsMap, "api_key", "")
+					if err != nil {
+						err = fmt.Errorf("bad GCS.api_key at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
 						return
 					}
 
@@ -1700,6 +2704,12 @@ func checkConfigFile() (err error) {
 						err = fmt.Errorf("bad RAM.max_total_objects at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 						return
 					}
+
+					backendConfigRAMAsStruct.snapshotFile, ok = parseString(backendConfigRAMAsMap, "snapshot_file", "")
+					if !ok {
+						err = fmt.Errorf("bad RAM.snapshot_file at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+						return
+					}
 				} else {
 					backendConfigRAMAsStruct = &backendConfigRAMStruct{
 						maxListPageSize:     defaultRAMMaxListPageSize,
@@ -1745,6 +2755,9 @@ func checkConfigFile() (err error) {
 
 					backendConfigS3AsStruct.region = ""
 					backendConfigS3AsStruct.endpoint = ""
+					backendConfigS3AsStruct.endpoints = nil
+					backendConfigS3AsStruct.endpointCheckInterval = time.Duration(0)
+					backendConfigS3AsStruct.readEndpointSelection = s3ReadEndpointSelectionRoundRobin
 				} else {
 					backendConfigS3AsStruct.configFilePath = ""
 
@@ -1759,6 +2772,27 @@ func checkConfigFile() (err error) {
 						err = fmt.Errorf("bad S3.endpoint at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 						return
 					}
+
+					backendConfigS3AsStruct.endpoints, err = parseEndpoints(backendConfigS3AsMap)
+					if err != nil {
+						err = fmt.Errorf("bad S3.endpoints at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
+						return
+					}
+					if len(backendConfigS3AsStruct.endpoints) > 0 {
+						backendConfigS3AsStruct.endpoint = backendConfigS3AsStruct.endpoints[0]
+					}
+
+					backendConfigS3AsStruct.endpointCheckInterval, ok = parseMilliseconds(backendConfigS3AsMap, "endpoint_check_interval", 5000*time.Millisecond)
+					if !ok {
+						err = fmt.Errorf("bad S3.endpoint_check_interval at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+						return
+					}
+
+					backendConfigS3AsStruct.readEndpointSelection, ok = parseString(backendConfigS3AsMap, "read_endpoint_selection", s3ReadEndpointSelectionRoundRobin)
+					if !ok || !slices.Contains([]string{s3ReadEndpointSelectionRoundRobin, s3ReadEndpointSelectionFastest}, backendConfigS3AsStruct.readEndpointSelection) {
+						err = fmt.Errorf("bad S3.read_endpoint_selection at backends[%v (\"%s\")] - must be one of \"%s\" or \"%s\"", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, s3ReadEndpointSelectionRoundRobin, s3ReadEndpointSelectionFastest)
+						return
+					}
 				}
 
 				backendConfigS3AsStruct.useCredentialsEnv, ok = parseBool(backendConfigS3AsMap, "use_credentials_env", false)
@@ -1796,9 +2830,9 @@ func checkConfigFile() (err error) {
 				default:
 					backendConfigS3AsStruct.credentialsFilePath = ""
 
-					backendConfigS3AsStruct.accessKeyID, ok = parseString(backendConfigS3AsMap, "access_key_id", "${AWS_ACCESS_KEY_ID}")
-					if !ok {
-						err = fmt.Errorf("bad S3.access_key_id at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					backendConfigS3AsStruct.accessKeyID, err = parseSecret(backendConfigS3AsMap, "access_key_id", "${AWS_ACCESS_KEY_ID}")
+					if err != nil {
+						err = fmt.Errorf("bad S3.access_key_id at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
 						return
 					}
 					if backendConfigS3AsStruct.accessKeyID == "" {
@@ -1806,50 +2840,199 @@ func checkConfigFile() (err error) {
 						return
 					}
 
-					backendConfigS3AsStruct.secretAccessKey, ok = parseString(backendConfigS3AsMap, "secret_access_key", "${AWS_SECRET_ACCESS_KEY}")
-					if !ok {
-						err = fmt.Errorf("bad S3.secret_access_key at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
-						return
-					}
-					if backendConfigS3AsStruct.secretAccessKey == "" {
-						err = fmt.Errorf("empty S3.secret_access_key at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					backendConfigS3AsStruct.secretAccessKey, err = parseSecret(backendConfigS3AsMap, "secret_access_key", "${AWS_SECRET_ACCESS_KEY}")
+					if err != nil {
+						err = fmt.Errorf("bad S3.secret_access_key at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
+						return
+					}
+					if backendConfigS3AsStruct.secretAccessKey == "" {
+						err = fmt.Errorf("empty S3.secret_access_key at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+						return
+					}
+				}
+
+				backendConfigS3AsStruct.skipTLSCertificateVerify, ok = parseBool(backendConfigS3AsMap, "skip_tls_certificate_verify", false)
+				if !ok {
+					err = fmt.Errorf("bad S3.skip_tls_certificate_verify at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.caBundleFile, ok = parseString(backendConfigS3AsMap, "ca_bundle_file", "")
+				if !ok {
+					err = fmt.Errorf("bad S3.ca_bundle_file at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.clientCertFile, ok = parseString(backendConfigS3AsMap, "client_cert_file", "")
+				if !ok {
+					err = fmt.Errorf("bad S3.client_cert_file at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.clientKeyFile, ok = parseString(backendConfigS3AsMap, "client_key_file", "")
+				if !ok {
+					err = fmt.Errorf("bad S3.client_key_file at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				if (backendConfigS3AsStruct.clientCertFile == "") != (backendConfigS3AsStruct.clientKeyFile == "") {
+					err = fmt.Errorf("S3.client_cert_file and S3.client_key_file must be specified together at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.virtualHostedStyleRequest, ok = parseBool(backendConfigS3AsMap, "virtual_hosted_style_request", false)
+				if !ok {
+					err = fmt.Errorf("bad S3.virtual_hosted_style_request at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.unsignedPayload, ok = parseBool(backendConfigS3AsMap, "unsigned_payload", false)
+				if !ok {
+					err = fmt.Errorf("bad S3.unsigned_payload at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.retryBaseDelay, ok = parseMilliseconds(backendConfigS3AsMap, "retry_base_delay", 10*time.Millisecond)
+				if !ok {
+					err = fmt.Errorf("bad S3.retry_base_delay at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.retryNextDelayMultiplier, ok = parseFloat64(backendConfigS3AsMap, "retry_next_delay_multiplier", float64(2.0))
+				if !ok || (backendConfigS3AsStruct.retryNextDelayMultiplier < float64(1.0)) {
+					err = fmt.Errorf("bad S3.retry_next_delay_multiplier at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.retryMaxDelay, ok = parseMilliseconds(backendConfigS3AsMap, "retry_max_delay", 2000*time.Millisecond)
+				if !ok {
+					err = fmt.Errorf("bad S3.retry_max_delay at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.maxIdleConns, ok = parseUint64(backendConfigS3AsMap, "max_idle_conns", uint64(0))
+				if !ok {
+					err = fmt.Errorf("bad S3.max_idle_conns at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.maxIdleConnsPerHost, ok = parseUint64(backendConfigS3AsMap, "max_idle_conns_per_host", uint64(0))
+				if !ok {
+					err = fmt.Errorf("bad S3.max_idle_conns_per_host at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.maxConnsPerHost, ok = parseUint64(backendConfigS3AsMap, "max_conns_per_host", uint64(0))
+				if !ok {
+					err = fmt.Errorf("bad S3.max_conns_per_host at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.requesterPays, ok = parseBool(backendConfigS3AsMap, "requester_pays", false)
+				if !ok {
+					err = fmt.Errorf("bad S3.requester_pays at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+
+				backendConfigS3AsStruct.requestHeaders, err = parseRequestHeaders(backendConfigS3AsMap)
+				if err != nil {
+					err = fmt.Errorf("bad S3.request_headers at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
+					return
+				}
+
+				backendConfigS3AsStruct.sseType, ok = parseString(backendConfigS3AsMap, "sse_type", "")
+				if !ok || !slices.Contains([]string{"", s3SSETypeAES256, s3SSETypeKMS, s3SSETypeCustomerKey}, backendConfigS3AsStruct.sseType) {
+					err = fmt.Errorf("bad S3.sse_type at backends[%v (\"%s\")] - must be one of \"%s\", \"%s\", or \"%s\"", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, s3SSETypeAES256, s3SSETypeKMS, s3SSETypeCustomerKey)
+					return
+				}
+
+				backendConfigS3AsStruct.kmsKeyID, ok = parseString(backendConfigS3AsMap, "kms_key_id", "")
+				if !ok || ((backendConfigS3AsStruct.kmsKeyID != "") && (backendConfigS3AsStruct.sseType != s3SSETypeKMS)) {
+					err = fmt.Errorf("bad S3.kms_key_id at backends[%v (\"%s\")] - only applicable if sse_type is \"%s\"", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, s3SSETypeKMS)
+					return
+				}
+
+				backendConfigS3AsStruct.sseCKey, err = parseSecret(backendConfigS3AsMap, "sse_c_key", "")
+				if err != nil {
+					err = fmt.Errorf("bad S3.sse_c_key at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
+					return
+				}
+				if (backendConfigS3AsStruct.sseCKey != "") != (backendConfigS3AsStruct.sseType == s3SSETypeCustomerKey) {
+					err = fmt.Errorf("bad S3.sse_c_key at backends[%v (\"%s\")] - required if and only if sse_type is \"%s\"", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, s3SSETypeCustomerKey)
+					return
+				}
+				if backendConfigS3AsStruct.sseCKey != "" {
+					_, err = decodeSSECKey(backendConfigS3AsStruct.sseCKey)
+					if err != nil {
+						err = fmt.Errorf("bad S3.sse_c_key at backends[%v (\"%s\")]: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
 						return
 					}
 				}
 
-				backendConfigS3AsStruct.skipTLSCertificateVerify, ok = parseBool(backendConfigS3AsMap, "skip_tls_certificate_verify", false)
+				backendConfigS3AsStruct.restoreArchivedObjects, ok = parseBool(backendConfigS3AsMap, "restore_archived_objects", false)
 				if !ok {
-					err = fmt.Errorf("bad S3.skip_tls_certificate_verify at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					err = fmt.Errorf("bad S3.restore_archived_objects at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 					return
 				}
 
-				backendConfigS3AsStruct.virtualHostedStyleRequest, ok = parseBool(backendConfigS3AsMap, "virtual_hosted_style_request", false)
-				if !ok {
-					err = fmt.Errorf("bad S3.virtual_hosted_style_request at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				backendConfigS3AsStruct.restoreDays, ok = parseUint64(backendConfigS3AsMap, "restore_days", uint64(1))
+				if !ok || (backendConfigS3AsStruct.restoreDays < 1) || (backendConfigS3AsStruct.restoreDays > math.MaxInt32) {
+					err = fmt.Errorf("bad S3.restore_days at backends[%v (\"%s\")] - must be at least 1", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 					return
 				}
 
-				backendConfigS3AsStruct.unsignedPayload, ok = parseBool(backendConfigS3AsMap, "unsigned_payload", false)
-				if !ok {
-					err = fmt.Errorf("bad S3.unsigned_payload at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				backendConfigS3AsStruct.restoreTier, ok = parseString(backendConfigS3AsMap, "restore_tier", s3RestoreTierStandard)
+				if !ok || !slices.Contains([]string{s3RestoreTierStandard, s3RestoreTierBulk, s3RestoreTierExpedited}, backendConfigS3AsStruct.restoreTier) {
+					err = fmt.Errorf("bad S3.restore_tier at backends[%v (\"%s\")] - must be one of \"%s\", \"%s\", or \"%s\"", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, s3RestoreTierStandard, s3RestoreTierBulk, s3RestoreTierExpedited)
 					return
 				}
 
-				backendConfigS3AsStruct.retryBaseDelay, ok = parseMilliseconds(backendConfigS3AsMap, "retry_base_delay", 10*time.Millisecond)
+				backendConfigS3AsStruct.discoverBuckets, ok = parseBool(backendConfigS3AsMap, "discover_buckets", false)
 				if !ok {
-					err = fmt.Errorf("bad S3.retry_base_delay at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					err = fmt.Errorf("bad S3.discover_buckets at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 					return
 				}
+				if backendConfigS3AsStruct.discoverBuckets {
+					if parseAny(backendAsMap, "bucket_container_name") {
+						err = fmt.Errorf("bad S3.discover_buckets at backends[%v (\"%s\")] - bucket_container_name must be omitted", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+						return
+					}
+					if backendConfigS3AsStruct.virtualHostedStyleRequest {
+						err = fmt.Errorf("bad S3.discover_buckets at backends[%v (\"%s\")] - not supported with virtual_hosted_style_request", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+						return
+					}
+					if backendAsStructNew.manifestPath != "" {
+						err = fmt.Errorf("bad S3.discover_buckets at backends[%v (\"%s\")] - not supported with manifest_path", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+						return
+					}
+				}
 
-				backendConfigS3AsStruct.retryNextDelayMultiplier, ok = parseFloat64(backendConfigS3AsMap, "retry_next_delay_multiplier", float64(2.0))
-				if !ok || (backendConfigS3AsStruct.retryNextDelayMultiplier < float64(1.0)) {
-					err = fmt.Errorf("bad S3.retry_next_delay_multiplier at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				backendConfigS3AsStruct.bucketDiscoveryTTL, ok = parseMilliseconds(backendConfigS3AsMap, "bucket_discovery_ttl", 60000*time.Millisecond)
+				if !ok || (backendConfigS3AsStruct.bucketDiscoveryTTL < time.Duration(0)) {
+					err = fmt.Errorf("bad S3.bucket_discovery_ttl at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 					return
 				}
 
-				backendConfigS3AsStruct.retryMaxDelay, ok = parseMilliseconds(backendConfigS3AsMap, "retry_max_delay", 2000*time.Millisecond)
+				snapshotTimeAsString, ok = parseString(backendConfigS3AsMap, "snapshot_time", "")
 				if !ok {
-					err = fmt.Errorf("bad S3.retry_max_delay at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					err = fmt.Errorf("bad S3.snapshot_time at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+					return
+				}
+				if snapshotTimeAsString != "" {
+					backendConfigS3AsStruct.snapshotTime, err = time.Parse(time.RFC3339, snapshotTimeAsString)
+					if err != nil {
+						err = fmt.Errorf("bad S3.snapshot_time at backends[%v (\"%s\")] - must be an RFC 3339 timestamp: %v", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName, err)
+						return
+					}
+					if !backendAsStructNew.readOnly {
+						err = fmt.Errorf("bad S3.snapshot_time at backends[%v (\"%s\")] - requires readonly", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+						return
+					}
+				}
+
+				backendConfigS3AsStruct.exposeVersions, ok = parseBool(backendConfigS3AsMap, "expose_versions", false)
+				if !ok {
+					err = fmt.Errorf("bad S3.expose_versions at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
 					return
 				}
 
@@ -1870,6 +3053,11 @@ func checkConfigFile() (err error) {
 				return
 			}
 
+			if !parseAny(backendAsMap, "bucket_container_name") && ((backendAsStructNew.backendType != "S3") || !backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets) {
+				err = fmt.Errorf("missing or bad bucket_container_name at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
+				return
+			}
+
 			_, ok = config.backends[backendAsStructNew.dirName]
 			if ok {
 				err = fmt.Errorf("duplicate backend at backends[%v (\"%s\")]", backendsAsInterfaceSliceIndex, backendAsStructNew.dirName)
@@ -1926,6 +3114,35 @@ func checkConfigFile() (err error) {
 		manifestPathOwner[manifestBackend.manifestPath] = manifestBackend.dirName
 	}
 
+	// The default_backend (if any) must be one in which mkdir(2) in the FUSE root may create prefixes
+
+	if config.defaultBackend != "" {
+		defaultBackend, found := config.backends[config.defaultBackend]
+		if !found {
+			err = fmt.Errorf("default_backend \"%s\" not found among configured backends", config.defaultBackend)
+			return
+		}
+		if defaultBackend.readOnly {
+			err = fmt.Errorf("default_backend \"%s\" must not be readonly", config.defaultBackend)
+			return
+		}
+		if defaultBackend.manifestPath != "" {
+			err = fmt.Errorf("default_backend \"%s\" must not specify manifest_path", config.defaultBackend)
+			return
+		}
+		if (defaultBackend.backendType == "S3") && defaultBackend.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets {
+			err = fmt.Errorf("default_backend \"%s\" must not specify S3.discover_buckets", config.defaultBackend)
+			return
+		}
+	}
+
+	// Each of the mounts (if any) presents its set of backends at its own mountpoint
+
+	err = parseMounts(configFileMap, config)
+	if err != nil {
+		return
+	}
+
 	if globals.config == nil {
 		// Move all (local) config.backends to globals.backendsToMount
 
@@ -1956,6 +3173,11 @@ func checkConfigFile() (err error) {
 			return
 		}
 
+		if !equalMounts(globals.config.mounts, config.mounts) {
+			err = errors.New("cannot change mounts via SIGHUP")
+			return
+		}
+
 		if globals.config.fuseWorkers != config.fuseWorkers {
 			err = errors.New("cannot change fuse_workers via SIGHUP")
 			return
@@ -1986,33 +3208,28 @@ func checkConfigFile() (err error) {
 			return
 		}
 
-		if globals.config.maxWrite != config.maxWrite {
-			err = errors.New("cannot change max_write via SIGHUP")
-			return
-		}
-
-		if globals.config.entryAttrTTL != config.entryAttrTTL {
-			err = errors.New("cannot change entry_attr_ttl via SIGHUP")
+		if globals.config.readOnly != config.readOnly {
+			err = errors.New("cannot change read_only via SIGHUP")
 			return
 		}
 
-		if globals.config.evictableInodeTTL != config.evictableInodeTTL {
-			err = errors.New("cannot change evictable_inode_ttl via SIGHUP")
+		if globals.config.maxWrite != config.maxWrite {
+			err = errors.New("cannot change max_write via SIGHUP")
 			return
 		}
 
-		if globals.config.virtualDirTTL != config.virtualDirTTL {
-			err = errors.New("cannot change virtual_dir_ttl via SIGHUP")
+		if globals.config.ttlCheckInterval != config.ttlCheckInterval {
+			err = errors.New("cannot change ttl_check_interval via SIGHUP")
 			return
 		}
 
-		if globals.config.virtualFileTTL != config.virtualFileTTL {
-			err = errors.New("cannot change virtual_file_ttl via SIGHUP")
+		if globals.config.fuseOpTimeout != config.fuseOpTimeout {
+			err = errors.New("cannot change fuse_op_timeout via SIGHUP")
 			return
 		}
 
-		if globals.config.ttlCheckInterval != config.ttlCheckInterval {
-			err = errors.New("cannot change ttl_check_interval via SIGHUP")
+		if globals.config.unmountDrainTimeout != config.unmountDrainTimeout {
+			err = errors.New("cannot change unmount_drain_timeout via SIGHUP")
 			return
 		}
 
@@ -2026,28 +3243,23 @@ func checkConfigFile() (err error) {
 			return
 		}
 
-		if globals.config.cacheLinesToPrefetch != config.cacheLinesToPrefetch {
-			err = errors.New("cannot change cache_lines_to_prefetch via SIGHUP")
-			return
-		}
-
-		if globals.config.dirtyCacheLinesFlushTrigger != config.dirtyCacheLinesFlushTrigger {
-			err = errors.New("cannot change dirty_cache_lines_flush_trigger via SIGHUP")
+		if globals.config.cacheDirPath != config.cacheDirPath {
+			err = errors.New("cannot change cache_dir_path via SIGHUP")
 			return
 		}
 
-		if globals.config.dirtyCacheLinesMax != config.dirtyCacheLinesMax {
-			err = errors.New("cannot change dirty_cache_lines_max via SIGHUP")
+		if globals.config.cacheStorage != config.cacheStorage {
+			err = errors.New("cannot change cache_storage via SIGHUP")
 			return
 		}
 
-		if globals.config.cacheDirPath != config.cacheDirPath {
-			err = errors.New("cannot change cache_dir_path via SIGHUP")
+		if globals.config.cacheDedup != config.cacheDedup {
+			err = errors.New("cannot change cache_dedup via SIGHUP")
 			return
 		}
 
-		if globals.config.cacheStorage != config.cacheStorage {
-			err = errors.New("cannot change cache_storage via SIGHUP")
+		if globals.config.cachePartitionBy != config.cachePartitionBy {
+			err = errors.New("cannot change cache_partition_by via SIGHUP")
 			return
 		}
 
@@ -2176,6 +3388,11 @@ func checkConfigFile() (err error) {
 			return
 		}
 
+		if globals.config.memoryPressureInterval != config.memoryPressureInterval {
+			err = errors.New("cannot change memory_pressure_interval via SIGHUP")
+			return
+		}
+
 		if globals.config.processMemoryLimit != config.processMemoryLimit {
 			err = errors.New("cannot change process_memory_limit via SIGHUP")
 			return
@@ -2186,11 +3403,61 @@ func checkConfigFile() (err error) {
 			return
 		}
 
+		if globals.config.backendReprobeInterval != config.backendReprobeInterval {
+			err = errors.New("cannot change backend_reprobe_interval via SIGHUP")
+			return
+		}
+
+		if globals.config.multipartUploadJanitorInterval != config.multipartUploadJanitorInterval {
+			err = errors.New("cannot change multipart_upload_janitor_interval via SIGHUP")
+			return
+		}
+
 		if globals.config.endpoint != config.endpoint {
 			err = errors.New("cannot change endpoint via SIGHUP")
 			return
 		}
 
+		if globals.config.adminListen != config.adminListen {
+			err = errors.New("cannot change admin_listen via SIGHUP")
+			return
+		}
+
+		if globals.config.eventSink != config.eventSink {
+			err = errors.New("cannot change event_sink via SIGHUP")
+			return
+		}
+
+		if globals.config.readyFile != config.readyFile {
+			err = errors.New("cannot change ready_file via SIGHUP")
+			return
+		}
+
+		if globals.config.logFormat != config.logFormat {
+			err = errors.New("cannot change log_format via SIGHUP")
+			return
+		}
+
+		if globals.config.logFile != config.logFile {
+			err = errors.New("cannot change log_file via SIGHUP")
+			return
+		}
+
+		if globals.config.logFileMaxSize != config.logFileMaxSize {
+			err = errors.New("cannot change log_file_max_size via SIGHUP")
+			return
+		}
+
+		if globals.config.logFileMaxBackups != config.logFileMaxBackups {
+			err = errors.New("cannot change log_file_max_backups via SIGHUP")
+			return
+		}
+
+		if !slices.Equal(globals.config.errorHints, config.errorHints) {
+			err = errors.New("cannot change error_hints via SIGHUP")
+			return
+		}
+
 		// Verify that all backends common to our (local) config.backends and globals.backends contain no changes
 
 		for dirName, backendAsStructOld = range globals.config.backends {
@@ -2256,8 +3523,108 @@ func checkConfigFile() (err error) {
 					return
 				}
 
-				if backendAsStructOld.traceLevel != backendAsStructNew.traceLevel {
-					err = fmt.Errorf("cannot change trace_level in backends[\"%s\"]", dirName)
+				if backendAsStructOld.symLinkSuffix != backendAsStructNew.symLinkSuffix {
+					err = fmt.Errorf("cannot change symlink_suffix in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if !backendAsStructOld.filter.equal(backendAsStructNew.filter) {
+					err = fmt.Errorf("cannot change include or exclude in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.cacheBypass != backendAsStructNew.cacheBypass {
+					err = fmt.Errorf("cannot change cache_bypass in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.emulateFIFOs != backendAsStructNew.emulateFIFOs {
+					err = fmt.Errorf("cannot change emulate_fifos in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.keySaltWidth != backendAsStructNew.keySaltWidth {
+					err = fmt.Errorf("cannot change key_salt_width in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.hedgeReadPercentile != backendAsStructNew.hedgeReadPercentile {
+					err = fmt.Errorf("cannot change hedge_read_percentile in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.hedgeReadMinDelay != backendAsStructNew.hedgeReadMinDelay {
+					err = fmt.Errorf("cannot change hedge_read_min_delay in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.hedgeReadBudget != backendAsStructNew.hedgeReadBudget {
+					err = fmt.Errorf("cannot change hedge_read_budget in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.readTimeout != backendAsStructNew.readTimeout {
+					err = fmt.Errorf("cannot change read_timeout in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.listTimeout != backendAsStructNew.listTimeout {
+					err = fmt.Errorf("cannot change list_timeout in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.headTimeout != backendAsStructNew.headTimeout {
+					err = fmt.Errorf("cannot change head_timeout in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.writeTimeout != backendAsStructNew.writeTimeout {
+					err = fmt.Errorf("cannot change write_timeout in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.readDirTimeBudget != backendAsStructNew.readDirTimeBudget {
+					err = fmt.Errorf("cannot change readdir_time_budget in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.readDirSnapshot != backendAsStructNew.readDirSnapshot {
+					err = fmt.Errorf("cannot change readdir_snapshot in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.readDirSnapshotMaxEntries != backendAsStructNew.readDirSnapshotMaxEntries {
+					err = fmt.Errorf("cannot change readdir_snapshot_max_entries in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.maxConcurrentRequests != backendAsStructNew.maxConcurrentRequests {
+					err = fmt.Errorf("cannot change max_concurrent_requests in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.readBandwidthLimit != backendAsStructNew.readBandwidthLimit {
+					err = fmt.Errorf("cannot change read_bandwidth_limit in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.requestRateLimit != backendAsStructNew.requestRateLimit {
+					err = fmt.Errorf("cannot change request_rate_limit in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if !backendAsStructOld.chaos.equal(backendAsStructNew.chaos) {
+					err = fmt.Errorf("cannot change chaos in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.proxyURL != backendAsStructNew.proxyURL {
+					err = fmt.Errorf("cannot change proxy_url in backends[\"%s\"]", dirName)
+					return
+				}
+
+				if backendAsStructOld.noProxy != backendAsStructNew.noProxy {
+					err = fmt.Errorf("cannot change no_proxy in backends[\"%s\"]", dirName)
 					return
 				}
 
@@ -2437,6 +3804,11 @@ func checkConfigFile() (err error) {
 						err = fmt.Errorf("cannot change RAM.max_total_objects in backends[\"%s\"]", dirName)
 						return
 					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigRAMStruct).snapshotFile != backendAsStructNew.backendTypeSpecifics.(*backendConfigRAMStruct).snapshotFile {
+						err = fmt.Errorf("cannot change RAM.snapshot_file in backends[\"%s\"]", dirName)
+						return
+					}
 				case "S3":
 					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).configCredentialsProfile != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).configCredentialsProfile {
 						err = fmt.Errorf("cannot change S3.config_credentials_profile in backends[\"%s\"]", dirName)
@@ -2488,6 +3860,21 @@ func checkConfigFile() (err error) {
 						return
 					}
 
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).caBundleFile != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).caBundleFile {
+						err = fmt.Errorf("cannot change S3.ca_bundle_file in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).clientCertFile != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).clientCertFile {
+						err = fmt.Errorf("cannot change S3.client_cert_file in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).clientKeyFile != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).clientKeyFile {
+						err = fmt.Errorf("cannot change S3.client_key_file in backends[\"%s\"]", dirName)
+						return
+					}
+
 					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).virtualHostedStyleRequest != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).virtualHostedStyleRequest {
 						err = fmt.Errorf("cannot change S3.virtual_hosted_style_request in backends[\"%s\"]", dirName)
 						return
@@ -2517,6 +3904,91 @@ func checkConfigFile() (err error) {
 						err = fmt.Errorf("cannot change S3.retry_max_delay in backends[\"%s\"]", dirName)
 						return
 					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).maxIdleConns != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).maxIdleConns {
+						err = fmt.Errorf("cannot change S3.max_idle_conns in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).maxIdleConnsPerHost != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).maxIdleConnsPerHost {
+						err = fmt.Errorf("cannot change S3.max_idle_conns_per_host in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).maxConnsPerHost != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).maxConnsPerHost {
+						err = fmt.Errorf("cannot change S3.max_conns_per_host in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).requesterPays != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).requesterPays {
+						err = fmt.Errorf("cannot change S3.requester_pays in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if !maps.Equal(backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).requestHeaders, backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).requestHeaders) {
+						err = fmt.Errorf("cannot change S3.request_headers in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).sseType != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).sseType {
+						err = fmt.Errorf("cannot change S3.sse_type in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).kmsKeyID != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).kmsKeyID {
+						err = fmt.Errorf("cannot change S3.kms_key_id in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).sseCKey != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).sseCKey {
+						err = fmt.Errorf("cannot change S3.sse_c_key in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).restoreArchivedObjects != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).restoreArchivedObjects {
+						err = fmt.Errorf("cannot change S3.restore_archived_objects in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).restoreDays != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).restoreDays {
+						err = fmt.Errorf("cannot change S3.restore_days in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).restoreTier != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).restoreTier {
+						err = fmt.Errorf("cannot change S3.restore_tier in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets {
+						err = fmt.Errorf("cannot change S3.discover_buckets in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if !backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).snapshotTime.Equal(backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).snapshotTime) {
+						err = fmt.Errorf("cannot change S3.snapshot_time in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).exposeVersions != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).exposeVersions {
+						err = fmt.Errorf("cannot change S3.expose_versions in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if !slices.Equal(backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).endpoints, backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).endpoints) {
+						err = fmt.Errorf("cannot change S3.endpoints in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).endpointCheckInterval != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).endpointCheckInterval {
+						err = fmt.Errorf("cannot change S3.endpoint_check_interval in backends[\"%s\"]", dirName)
+						return
+					}
+
+					if backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).readEndpointSelection != backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct).readEndpointSelection {
+						err = fmt.Errorf("cannot change S3.read_endpoint_selection in backends[\"%s\"]", dirName)
+						return
+					}
 				default:
 					err = fmt.Errorf("logic error comparing backend_type specifics in backends[\"%s\"] - backend_type \"%s\" unrecognized", dirName, backendAsStructOld.backendType)
 					return
@@ -2528,11 +4000,22 @@ func checkConfigFile() (err error) {
 
 		for dirName, backendAsStructOld = range globals.config.backends {
 			_, ok = config.backends[dirName]
-			if !ok {
+			if !ok && (backendAsStructOld.mkDirOf == "") {
 				globals.backendsToUnmount[dirName] = backendAsStructOld
 			}
 		}
 
+		// Forget any failed backends no longer in (local) config.backends (the rest are retried below)
+
+		globalsLock("config.go:4010:3:checkConfigFile")
+		for dirName = range globals.backendsFailed {
+			_, ok = config.backends[dirName]
+			if !ok {
+				delete(globals.backendsFailed, dirName)
+			}
+		}
+		globalsUnlock()
+
 		// Clone references to all (local) config.backends missing from globals.backends to globals.backendsToMount
 
 		for dirName, backendAsStructNew = range config.backends {
@@ -2541,6 +4024,10 @@ func checkConfigFile() (err error) {
 				globals.backendsToMount[dirName] = backendAsStructNew
 			}
 		}
+
+		// Finally, apply those settings that may safely change on a running mount
+
+		applyHotReloadableConfig(config)
 	}
 
 	// All done
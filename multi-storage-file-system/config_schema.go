@@ -0,0 +1,434 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// `configSchemaKind` enumerates the value types a config setting may be declared to hold.
+type configSchemaKind int
+
+const (
+	configSchemaKindAny     configSchemaKind = iota // Any value (including sections validated elsewhere)
+	configSchemaKindBool                            // As accepted by parseBool()
+	configSchemaKindFloat64                         // As accepted by parseFloat64()
+	configSchemaKindList                            // A list each element of which matches elem
+	configSchemaKindMap                             // A section whose keys are declared in fields (or, if fields == nil, each of whose values matches elem)
+	configSchemaKindSecret                          // As accepted by parseSecret()
+	configSchemaKindString                          // As accepted by parseString()
+	configSchemaKindUint64                          // As accepted by parseUint64() (and, hence, parseMilliseconds() and parseSeconds())
+)
+
+// `configSchemaStruct` declares the expected shape of a config setting or section.
+type configSchemaStruct struct {
+	kind     configSchemaKind               //
+	elem     *configSchemaStruct            // For configSchemaKindList and (if fields == nil) configSchemaKindMap
+	fields   map[string]*configSchemaStruct // For configSchemaKindMap
+	required []string                       // For configSchemaKindMap, keys that must be present
+	oneOf    []string                       // For configSchemaKindString, if non-empty, the permitted values
+}
+
+// `configSchemaProblemStruct` describes a single config-file problem found by validateConfigSchema().
+type configSchemaProblemStruct struct {
+	path    string // JSON/YAML path (e.g. "backends[0].S3.region") locating the problem
+	problem string //
+}
+
+func (configSchemaProblem configSchemaProblemStruct) Error() string {
+	return configSchemaProblem.path + ": " + configSchemaProblem.problem
+}
+
+var (
+	configSchemaAny     = &configSchemaStruct{kind: configSchemaKindAny}
+	configSchemaBool    = &configSchemaStruct{kind: configSchemaKindBool}
+	configSchemaFloat64 = &configSchemaStruct{kind: configSchemaKindFloat64}
+	configSchemaSecret  = &configSchemaStruct{kind: configSchemaKindSecret}
+	configSchemaString  = &configSchemaStruct{kind: configSchemaKindString}
+	configSchemaUint64  = &configSchemaStruct{kind: configSchemaKindUint64}
+
+	configSchemaStringList = &configSchemaStruct{kind: configSchemaKindList, elem: configSchemaString}
+	configSchemaUint64List = &configSchemaStruct{kind: configSchemaKindList, elem: configSchemaUint64}
+
+	configSchemaBackendAIStore = &configSchemaStruct{
+		kind: configSchemaKindMap,
+		fields: map[string]*configSchemaStruct{
+			"authn_token":                 configSchemaSecret,
+			"authn_token_file":            configSchemaString,
+			"endpoint":                    configSchemaString,
+			"manifest_gen_backend":        configSchemaString,
+			"provider":                    configSchemaString,
+			"skip_tls_certificate_verify": configSchemaBool,
+			"timeout":                     configSchemaUint64,
+		},
+	}
+
+	configSchemaBackendGCS = &configSchemaStruct{
+		kind: configSchemaKindMap,
+		fields: map[string]*configSchemaStruct{
+			"api_key":                     configSchemaSecret,
+			"endpoint":                    configSchemaString,
+			"retry_base_delay":            configSchemaUint64,
+			"retry_max_delay":             configSchemaUint64,
+			"retry_next_delay_multiplier": configSchemaFloat64,
+			"skip_tls_certificate_verify": configSchemaBool,
+		},
+	}
+
+	configSchemaBackendPSEUDO = &configSchemaStruct{
+		kind: configSchemaKindMap,
+		fields: map[string]*configSchemaStruct{
+			"dir_name_format":            configSchemaString,
+			"dir_starting_number":        configSchemaUint64,
+			"file_name_format":           configSchemaString,
+			"file_size":                  configSchemaUint64,
+			"file_starting_number":       configSchemaUint64,
+			"files_at_depth_0":           configSchemaUint64,
+			"files_at_depth_1":           configSchemaUint64,
+			"files_at_depth_2":           configSchemaUint64,
+			"files_at_depth_3":           configSchemaUint64,
+			"max_list_page_size":         configSchemaUint64,
+			"min_latency_delete_file":    configSchemaUint64,
+			"min_latency_list_directory": configSchemaUint64,
+			"min_latency_list_objects":   configSchemaUint64,
+			"min_latency_read_file":      configSchemaUint64,
+			"min_latency_stat_directory": configSchemaUint64,
+			"min_latency_stat_file":      configSchemaUint64,
+			"subdirectories_at_depth_0":  configSchemaUint64,
+			"subdirectories_at_depth_1":  configSchemaUint64,
+			"subdirectories_at_depth_2":  configSchemaUint64,
+		},
+	}
+
+	configSchemaBackendRAM = &configSchemaStruct{
+		kind: configSchemaKindMap,
+		fields: map[string]*configSchemaStruct{
+			"max_list_page_size":     configSchemaUint64,
+			"max_total_object_space": configSchemaUint64,
+			"max_total_objects":      configSchemaUint64,
+			"snapshot_file":          configSchemaString,
+		},
+	}
+
+	configSchemaBackendS3 = &configSchemaStruct{
+		kind: configSchemaKindMap,
+		fields: map[string]*configSchemaStruct{
+			"access_key_id":                configSchemaSecret,
+			"anonymous":                    configSchemaBool,
+			"bucket_discovery_ttl":         configSchemaUint64,
+			"ca_bundle_file":               configSchemaString,
+			"client_cert_file":             configSchemaString,
+			"client_key_file":              configSchemaString,
+			"config_credentials_profile":   configSchemaString,
+			"config_file_path":             configSchemaString,
+			"credentials_file_path":        configSchemaString,
+			"discover_buckets":             configSchemaBool,
+			"endpoint":                     configSchemaString,
+			"endpoint_check_interval":      configSchemaUint64,
+			"endpoints":                    {kind: configSchemaKindList, elem: configSchemaString},
+			"expose_versions":              configSchemaBool,
+			"kms_key_id":                   configSchemaString,
+			"max_conns_per_host":           configSchemaUint64,
+			"max_idle_conns":               configSchemaUint64,
+			"max_idle_conns_per_host":      configSchemaUint64,
+			"read_endpoint_selection":      {kind: configSchemaKindString, oneOf: []string{s3ReadEndpointSelectionRoundRobin, s3ReadEndpointSelectionFastest}},
+			"region":                       configSchemaString,
+			"request_headers":              {kind: configSchemaKindMap, elem: configSchemaString},
+			"requester_pays":               configSchemaBool,
+			"restore_archived_objects":     configSchemaBool,
+			"restore_days":                 configSchemaUint64,
+			"restore_tier":                 {kind: configSchemaKindString, oneOf: []string{s3RestoreTierStandard, s3RestoreTierBulk, s3RestoreTierExpedited}},
+			"retry_base_delay":             configSchemaUint64,
+			"retry_max_delay":              configSchemaUint64,
+			"retry_next_delay_multiplier":  configSchemaFloat64,
+			"secret_access_key":            configSchemaSecret,
+			"skip_tls_certificate_verify":  configSchemaBool,
+			"snapshot_time":                configSchemaString,
+			"sse_c_key":                    configSchemaSecret,
+			"sse_type":                     configSchemaString,
+			"unsigned_payload":             configSchemaBool,
+			"use_config_env":               configSchemaBool,
+			"use_credentials_env":          configSchemaBool,
+			"virtual_hosted_style_request": configSchemaBool,
+		},
+	}
+
+	configSchemaBackend = &configSchemaStruct{
+		kind: configSchemaKindMap,
+		fields: map[string]*configSchemaStruct{
+			"AIStore":                        configSchemaBackendAIStore,
+			"GCS":                            configSchemaBackendGCS,
+			"PSEUDO":                         configSchemaBackendPSEUDO,
+			"RAM":                            configSchemaBackendRAM,
+			"S3":                             configSchemaBackendS3,
+			"allow_gids":                     configSchemaUint64List,
+			"allow_uids":                     configSchemaUint64List,
+			"attr_ttl":                       configSchemaUint64,
+			"backend_type":                   {kind: configSchemaKindString, oneOf: []string{"AIStore", "GCS", "PSEUDO", "RAM", "S3"}},
+			"bucket_container_name":          configSchemaString,
+			"cache_bypass":                   configSchemaBool,
+			"chaos":                          configSchemaAny, // Validated by parseChaos()
+			"deny_gids":                      configSchemaUint64List,
+			"deny_uids":                      configSchemaUint64List,
+			"dir_name":                       configSchemaString,
+			"dir_perm":                       configSchemaString,
+			"directory_page_size":            configSchemaUint64,
+			"emulate_fifos":                  configSchemaBool,
+			"entry_ttl":                      configSchemaUint64,
+			"exclude":                        configSchemaStringList,
+			"file_perm":                      configSchemaString,
+			"flat_dir_confirmation_pages":    configSchemaUint64,
+			"flat_dir_hints":                 {kind: configSchemaKindList, elem: &configSchemaStruct{kind: configSchemaKindMap, fields: map[string]*configSchemaStruct{"key_prefix_chars": configSchemaString, "path": configSchemaString, "split_depth": configSchemaUint64}, required: []string{"path"}}},
+			"flush_on_close":                 configSchemaBool,
+			"gid":                            configSchemaUint64,
+			"head_timeout":                   configSchemaUint64,
+			"hedge_read_budget":              configSchemaUint64,
+			"hedge_read_min_delay":           configSchemaUint64,
+			"hedge_read_percentile":          configSchemaFloat64,
+			"include":                        configSchemaStringList,
+			"key_salt_width":                 configSchemaUint64,
+			"list_timeout":                   configSchemaUint64,
+			"log_level":                      configSchemaString,
+			"manifest_gen_workers":           configSchemaUint64,
+			"manifest_path":                  configSchemaString,
+			"max_concurrent_requests":        configSchemaUint64,
+			"multipart_cache_line_threshold": configSchemaUint64,
+			"no_proxy":                       configSchemaString,
+			"prefix":                         configSchemaString,
+			"proxy_url":                      configSchemaString,
+			"read_bandwidth_limit":           configSchemaUint64,
+			"read_timeout":                   configSchemaUint64,
+			"readdir_snapshot":               configSchemaBool,
+			"readdir_snapshot_max_entries":   configSchemaUint64,
+			"readdir_time_budget":            configSchemaUint64,
+			"readonly":                       configSchemaBool,
+			"request_rate_limit":             configSchemaUint64,
+			"symlink_suffix":                 configSchemaString,
+			"trace_level":                    configSchemaUint64,
+			"uid":                            configSchemaUint64,
+			"upload_part_cache_lines":        configSchemaUint64,
+			"upload_part_concurrency":        configSchemaUint64,
+			"write_timeout":                  configSchemaUint64,
+		},
+		required: []string{"backend_type", "dir_name"}, // bucket_container_name is required unless S3.discover_buckets is set (as checked by checkConfigFile())
+	}
+
+	// `configSchema` declares the expected shape of an MSFS-specific (i.e. msfs_version: 1)
+	// config-file. Each setting added to checkConfigFile() should be declared here as well.
+	configSchema = &configSchemaStruct{
+		kind: configSchemaKindMap,
+		fields: map[string]*configSchemaStruct{
+			"admin_listen":                        configSchemaString,
+			"allow_other":                         configSchemaBool,
+			"attr_ttl":                            configSchemaUint64,
+			"auto_sighup_interval":                configSchemaUint64,
+			"backend_reprobe_interval":            configSchemaUint64,
+			"backends":                            {kind: configSchemaKindList, elem: configSchemaBackend},
+			"cache_backend":                       configSchemaString,
+			"cache_dedup":                         configSchemaBool,
+			"cache_dir_path":                      configSchemaString,
+			"cache_line_size":                     configSchemaUint64,
+			"cache_lines":                         configSchemaUint64,
+			"cache_lines_to_prefetch":             configSchemaUint64,
+			"cache_partition_by":                  configSchemaString,
+			"cache_partition_default_limit":       configSchemaUint64,
+			"cache_partitions":                    {kind: configSchemaKindList, elem: &configSchemaStruct{kind: configSchemaKindMap, fields: map[string]*configSchemaStruct{"key": configSchemaString, "limit": configSchemaUint64}, required: []string{"key", "limit"}}},
+			"cache_storage":                       configSchemaString,
+			"default_backend":                     configSchemaString,
+			"dir_perm":                            configSchemaString,
+			"dirty_cache_lines_flush_trigger":     configSchemaUint64,
+			"dirty_cache_lines_max":               configSchemaUint64,
+			"endpoint":                            configSchemaString,
+			"entry_attr_ttl":                      configSchemaUint64,
+			"entry_ttl":                           configSchemaUint64,
+			"error_hints":                         {kind: configSchemaKindList, elem: &configSchemaStruct{kind: configSchemaKindMap, fields: map[string]*configSchemaStruct{"error_code": configSchemaString, "hint": configSchemaString, "http_status": configSchemaUint64}, required: []string{"hint"}}},
+			"event_sink":                          configSchemaString,
+			"evictable_inode_ttl":                 configSchemaUint64,
+			"fuse_fd_per_worker":                  configSchemaBool,
+			"fuse_op_timeout":                     configSchemaUint64,
+			"fuse_workers":                        configSchemaUint64,
+			"gid":                                 configSchemaUint64,
+			"hide_inaccessible_backends":          configSchemaBool,
+			"log_file":                            configSchemaString,
+			"log_file_max_backups":                configSchemaUint64,
+			"log_file_max_size":                   configSchemaUint64,
+			"log_format":                          configSchemaString,
+			"log_level":                           configSchemaString,
+			"mapped_cache":                        configSchemaBool,
+			"max_write":                           configSchemaUint64,
+			"memory_pressure_interval":            configSchemaUint64,
+			"memory_pressure_limit":               configSchemaUint64,
+			"memory_pressure_threshold":           configSchemaUint64,
+			"metadata_cache_paging_mode":          configSchemaString,
+			"mountname":                           configSchemaString,
+			"mountpoint":                          configSchemaString,
+			"mounts":                              {kind: configSchemaKindList, elem: &configSchemaStruct{kind: configSchemaKindMap, fields: map[string]*configSchemaStruct{"backends": {kind: configSchemaKindList, elem: configSchemaString}, "mountpoint": configSchemaString}, required: []string{"backends", "mountpoint"}}},
+			"msfs_version":                        configSchemaUint64,
+			"multipart_upload_janitor_interval":   configSchemaUint64,
+			"multipart_upload_max_age":            configSchemaUint64,
+			"opentelemetry":                       configSchemaAny, // Validated by checkConfigFile()'s observability parsing
+			"pebble_cache_size":                   configSchemaUint64,
+			"pebble_l0_compaction_file_threshold": configSchemaUint64,
+			"pebble_l0_stop_writes_threshold":     configSchemaUint64,
+			"pebble_mem_table_size":               configSchemaUint64,
+			"prefetch_concurrency":                configSchemaUint64,
+			"presigned_url_ttl":                   configSchemaUint64,
+			"process_memory_limit":                configSchemaUint64,
+			"read_only":                           configSchemaBool,
+			"ready_file":                          configSchemaString,
+			"ready_probe_interval":                configSchemaUint64,
+			"tree_size_cache_ttl":                 configSchemaUint64,
+			"ttl_check_interval":                  configSchemaUint64,
+			"uid":                                 configSchemaUint64,
+			"unmount_drain_timeout":               configSchemaUint64,
+			"virtual_dir_ttl":                     configSchemaUint64,
+			"virtual_file_ttl":                    configSchemaUint64,
+
+			"inode_eviction_queue_flushes_per_gc":               configSchemaUint64,
+			"inode_eviction_queue_keys_per_page_max":            configSchemaUint64,
+			"inode_eviction_queue_page_dirty_flush_trigger":     configSchemaUint64,
+			"inode_eviction_queue_page_evict_high_limit":        configSchemaUint64,
+			"inode_eviction_queue_page_evict_low_limit":         configSchemaUint64,
+			"inode_map_flushes_per_gc":                          configSchemaUint64,
+			"inode_map_keys_per_page_max":                       configSchemaUint64,
+			"inode_map_page_dirty_flush_trigger":                configSchemaUint64,
+			"inode_map_page_evict_high_limit":                   configSchemaUint64,
+			"inode_map_page_evict_low_limit":                    configSchemaUint64,
+			"phys_child_dir_entry_map_flushes_per_gc":           configSchemaUint64,
+			"phys_child_dir_entry_map_keys_per_page_max":        configSchemaUint64,
+			"phys_child_dir_entry_map_page_dirty_flush_trigger": configSchemaUint64,
+			"phys_child_dir_entry_map_page_evict_high_limit":    configSchemaUint64,
+			"phys_child_dir_entry_map_page_evict_low_limit":     configSchemaUint64,
+			"virt_child_dir_entry_map_flushes_per_gc":           configSchemaUint64,
+			"virt_child_dir_entry_map_keys_per_page_max":        configSchemaUint64,
+			"virt_child_dir_entry_map_page_dirty_flush_trigger": configSchemaUint64,
+			"virt_child_dir_entry_map_page_evict_high_limit":    configSchemaUint64,
+			"virt_child_dir_entry_map_page_evict_low_limit":     configSchemaUint64,
+		},
+	}
+)
+
+// `validateConfigSchema` checks configFileMap against configSchema collecting (rather than
+// stopping at the first of) any problems. Settings of the wrong type, missing required
+// settings, and disallowed values are returned as problems while unrecognized settings
+// (likely typos as they are otherwise silently ignored) are returned as warnings.
+func validateConfigSchema(configFileMap map[string]interface{}) (problems []error, warnings []string) {
+	configSchema.validate("", configFileMap, &problems, &warnings)
+	return
+}
+
+// `validate` checks v (found at path) against schema appending to problems and warnings.
+func (schema *configSchemaStruct) validate(path string, v interface{}, problems *[]error, warnings *[]string) {
+	var (
+		elemAsInterface  interface{}
+		elemIndex        int
+		fieldSchema      *configSchemaStruct
+		key              string
+		keys             []string
+		ok               bool
+		problem          string
+		s                string
+		vAsInterfaceList []interface{}
+		vAsMap           map[string]interface{}
+		vAsSingletonMap  = map[string]interface{}{"": v}
+	)
+
+	switch schema.kind {
+	case configSchemaKindAny:
+		// Nothing to check
+	case configSchemaKindBool:
+		_, ok = parseBool(vAsSingletonMap, "", nil)
+		if !ok {
+			problem = "must be a bool"
+		}
+	case configSchemaKindFloat64:
+		_, ok = parseFloat64(vAsSingletonMap, "", nil)
+		if !ok {
+			problem = "must be a number"
+		}
+	case configSchemaKindList:
+		if v == nil {
+			return
+		}
+		vAsInterfaceList, ok = v.([]interface{})
+		if !ok {
+			problem = "must be a list"
+			break
+		}
+		for elemIndex, elemAsInterface = range vAsInterfaceList {
+			schema.elem.validate(fmt.Sprintf("%s[%d]", path, elemIndex), elemAsInterface, problems, warnings)
+		}
+	case configSchemaKindMap:
+		if v == nil {
+			return
+		}
+		vAsMap, ok = v.(map[string]interface{})
+		if !ok {
+			problem = "must be a section (map)"
+			break
+		}
+		for _, key = range schema.required {
+			if !parseAny(vAsMap, key) {
+				*problems = append(*problems, configSchemaProblemStruct{path: configSchemaPathJoin(path, key), problem: "missing required setting"})
+			}
+		}
+		keys = make([]string, 0, len(vAsMap))
+		for key = range vAsMap {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key = range keys {
+			if schema.fields == nil {
+				schema.elem.validate(configSchemaPathJoin(path, key), vAsMap[key], problems, warnings)
+				continue
+			}
+			fieldSchema, ok = schema.fields[key]
+			if !ok {
+				*warnings = append(*warnings, fmt.Sprintf("%s: unrecognized setting (ignored)", configSchemaPathJoin(path, key)))
+				continue
+			}
+			fieldSchema.validate(configSchemaPathJoin(path, key), vAsMap[key], problems, warnings)
+		}
+	case configSchemaKindSecret:
+		// Secret references are only resolved (by parseSecret()) by checkConfigFile()
+
+		vAsMap, ok = v.(map[string]interface{})
+		if ok {
+			if (len(vAsMap) != 1) || !parseAnyOf(vAsMap, []string{"from_command", "from_env", "from_file"}) {
+				problem = "secret reference must have exactly one of from_env, from_file, or from_command"
+			}
+			break
+		}
+		_, ok = v.(string)
+		if !ok {
+			problem = "must be a string or a secret reference"
+		}
+	case configSchemaKindString:
+		s, ok = parseString(vAsSingletonMap, "", nil)
+		if !ok {
+			problem = "must be a string"
+			break
+		}
+		if (len(schema.oneOf) > 0) && !slices.Contains(schema.oneOf, s) {
+			problem = fmt.Sprintf("\"%s\" must be one of %q", s, schema.oneOf)
+		}
+	case configSchemaKindUint64:
+		_, ok = parseUint64(vAsSingletonMap, "", nil)
+		if !ok {
+			problem = "must be a non-negative integer"
+		}
+	}
+
+	if problem != "" {
+		*problems = append(*problems, configSchemaProblemStruct{path: path, problem: problem})
+	}
+}
+
+// `configSchemaPathJoin` returns the path of the setting key within the section at path.
+func configSchemaPathJoin(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -16,9 +16,17 @@
 package main
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry/attributes"
 )
 
 func activateBackendsToMountForTest() {
@@ -1280,3 +1288,878 @@ backends: [
 		t.Fatalf("checkConfigFile() unexpectedly failed for distinct manifest_path: %v", err)
 	}
 }
+
+// TestHedgeReadConfig verifies parsing and validation of the hedge_read_* backend settings.
+func TestHedgeReadConfig(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+    hedge_read_percentile: 95,
+    hedge_read_min_delay: 20,
+    hedge_read_budget: 10,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	backend := globals.backendsToMount["ram"]
+	if backend.hedgeReadPercentile != 95 {
+		t.Errorf("expected hedgeReadPercentile == 95, got %v", backend.hedgeReadPercentile)
+	}
+	if backend.hedgeReadMinDelay != 20*time.Millisecond {
+		t.Errorf("expected hedgeReadMinDelay == 20ms, got %v", backend.hedgeReadMinDelay)
+	}
+	if backend.hedgeReadBudget != 10 {
+		t.Errorf("expected hedgeReadBudget == 10, got %v", backend.hedgeReadBudget)
+	}
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+    hedge_read_percentile: 100,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err == nil {
+		t.Fatalf("checkConfigFile() unexpectedly allowed hedge_read_percentile == 100")
+	}
+}
+
+// TestEntryAndAttrTTLConfig verifies that the top-level entry_ttl and attr_ttl default to
+// entry_attr_ttl and, in turn, provide the defaults for each backend's entry_ttl and attr_ttl.
+func TestEntryAndAttrTTLConfig(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+entry_attr_ttl: 4000
+attr_ttl: 1000
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+  },
+  {
+    dir_name: ram2,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+    entry_ttl: 2000,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	if (globals.config.entryTTL != 4000*time.Millisecond) || (globals.config.attrTTL != 1000*time.Millisecond) {
+		t.Errorf("expected entry_ttl == 4s and attr_ttl == 1s, got %v and %v", globals.config.entryTTL, globals.config.attrTTL)
+	}
+	backend := globals.backendsToMount["ram"]
+	if (backend.entryTTL != 4000*time.Millisecond) || (backend.attrTTL != 1000*time.Millisecond) {
+		t.Errorf("expected backends[\"ram\"] entry_ttl == 4s and attr_ttl == 1s, got %v and %v", backend.entryTTL, backend.attrTTL)
+	}
+
+	backend = globals.backendsToMount["ram2"]
+	if (backend.entryTTL != 2000*time.Millisecond) || (backend.attrTTL != 1000*time.Millisecond) {
+		t.Errorf("expected backends[\"ram2\"] entry_ttl == 2s and attr_ttl == 1s, got %v and %v", backend.entryTTL, backend.attrTTL)
+	}
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+entry_attr_ttl: 1000
+evictable_inode_ttl: 5000
+entry_ttl: 6000
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err == nil {
+		t.Fatalf("checkConfigFile() unexpectedly allowed entry_ttl > evictable_inode_ttl")
+	}
+}
+
+// TestCacheLineSizeGuardRails verifies that pathological combinations of cache_line_size,
+// max_write, and upload_part_cache_lines are rejected.
+func TestCacheLineSizeGuardRails(t *testing.T) {
+	for _, testCase := range []struct {
+		name          string
+		config        string
+		errorContains string
+	}{
+		{
+			name:          "unaligned",
+			config:        "cache_line_size: 1000000\n",
+			errorContains: "multiple of 4096",
+		},
+		{
+			name:          "below max_write",
+			config:        "cache_line_size: 65536\nmax_write: 131072\n",
+			errorContains: "at least max_write",
+		},
+		{
+			name:          "small S3 upload parts",
+			config:        "cache_line_size: 1048576\nbackends: [{dir_name: s3, bucket_container_name: test, readonly: false, upload_part_cache_lines: 2, backend_type: S3, S3: {region: us-east-1, endpoint: \"http://minio:9000\", anonymous: true}}]\n",
+			errorContains: "upload_part_cache_lines",
+		},
+	} {
+		initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+		err := os.WriteFile(globals.configFilePath, []byte("msfs_version: 1\n"+testCase.config), 0o600)
+		if err != nil {
+			t.Fatalf("os.WriteFile() failed: %v", err)
+		}
+
+		err = checkConfigFile()
+		if err == nil {
+			t.Fatalf("checkConfigFile() unexpectedly succeeded [%s]", testCase.name)
+		}
+		if !strings.Contains(err.Error(), testCase.errorContains) {
+			t.Fatalf("checkConfigFile() error should mention %q, got: %v [%s]", testCase.errorContains, err, testCase.name)
+		}
+	}
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte("msfs_version: 1\ncache_line_size: 131072\nmax_write: 131072\n"), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed for cache_line_size == max_write: %v", err)
+	}
+}
+
+// TestErrorHints verifies parsing of error_hints and their application to backend errors.
+func TestErrorHints(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+error_hints: [
+  {
+    error_code: QuotaExceeded,
+    hint: "contact storage team",
+  },
+  {
+    http_status: 403,
+    hint: "check bucket policy",
+  },
+]
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	if len(globals.config.errorHints) != 2 {
+		t.Fatalf("expected 2 error_hints, got %d", len(globals.config.errorHints))
+	}
+
+	for _, testCase := range []struct {
+		errString string
+		hint      string
+	}{
+		{"operation error S3: PutObject, https response error StatusCode: 403, RequestID: x, api error QuotaExceeded: quota", "contact storage team"},
+		{"operation error S3: GetObject, https response error StatusCode: 403, RequestID: x, api error AccessDenied: denied", "check bucket policy"},
+		{"googleapi: Error 403: forbidden", "check bucket policy"},
+		{"operation error S3: GetObject, https response error StatusCode: 404, RequestID: x, api error NoSuchKey: missing", ""},
+	} {
+		annotatedErr := annotateBackendError(errors.New(testCase.errString))
+		if testCase.hint == "" {
+			if annotatedErr.Error() != testCase.errString {
+				t.Errorf("expected %q to be left unannotated, got %q", testCase.errString, annotatedErr.Error())
+			}
+		} else if !strings.HasSuffix(annotatedErr.Error(), "[hint: "+testCase.hint+"]") {
+			t.Errorf("expected %q to be annotated with hint %q, got %q", testCase.errString, testCase.hint, annotatedErr.Error())
+		}
+	}
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+error_hints: [
+  {
+    hint: "matches nothing",
+  },
+]
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err == nil {
+		t.Fatalf("checkConfigFile() unexpectedly allowed an error_hints entry without http_status or error_code")
+	}
+}
+
+// TestCachePartitions verifies parsing and validation of cache_partition_by, cache_partition_default_limit, and cache_partitions.
+func TestCachePartitions(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+cache_lines: 200
+cache_partition_by: uid
+cache_partition_default_limit: 50
+cache_partitions: [
+  {
+    key: "1000",
+    limit: 10,
+  },
+]
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	if globals.config.cachePartitionBy != cachePartitionByUID {
+		t.Fatalf("expected cache_partition_by %q, got %q", cachePartitionByUID, globals.config.cachePartitionBy)
+	}
+	if globals.config.cachePartitionDefaultLimit != 100 {
+		t.Fatalf("expected cache_partition_default_limit to resolve to 100 cache lines, got %v", globals.config.cachePartitionDefaultLimit)
+	}
+	if (len(globals.config.cachePartitions) != 1) || (globals.config.cachePartitions[0] != cachePartitionStruct{key: "1000", limit: 20}) {
+		t.Fatalf("unexpected cache_partitions: %+v", globals.config.cachePartitions)
+	}
+
+	for _, testCase := range []struct {
+		name   string
+		config string
+	}{
+		{"bad cache_partition_by", "cache_partition_by: pid\n"},
+		{"zero cache_partition_default_limit", "cache_partition_by: uid\ncache_partition_default_limit: 0\n"},
+		{"cache_partitions without cache_partition_by", "cache_partitions: [{key: \"1000\", limit: 10}]\n"},
+		{"missing limit", "cache_partition_by: uid\ncache_partitions: [{key: \"1000\"}]\n"},
+		{"limit over 100", "cache_partition_by: uid\ncache_partitions: [{key: \"1000\", limit: 101}]\n"},
+		{"duplicate key", "cache_partition_by: cgroup\ncache_partitions: [{key: /a, limit: 10}, {key: /a, limit: 20}]\n"},
+	} {
+		initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+		err = os.WriteFile(globals.configFilePath, []byte("msfs_version: 1\n"+testCase.config+"backends: [{dir_name: ram, bucket_container_name: ignored, backend_type: RAM}]\n"), 0o600)
+		if err != nil {
+			t.Fatalf("os.WriteFile() failed: %v", err)
+		}
+
+		if err = checkConfigFile(); err == nil {
+			t.Errorf("[%s] checkConfigFile() unexpectedly succeeded", testCase.name)
+		}
+	}
+}
+
+// TestMSCCacheSection verifies that the Python MSC cache section is honored in compatibility mode.
+func TestMSCCacheSection(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	cacheDirPath := t.TempDir()
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+profiles:
+  s3:
+    storage_provider:
+      type: s3
+      options:
+        base_path: test
+cache:
+  size: 1G
+  cache_line_size: 64M
+  location: `+cacheDirPath+`
+  eviction_policy:
+    policy: fifo
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	if globals.config.cacheDirPath != cacheDirPath {
+		t.Errorf("expected cacheDirPath == %q, got %q", cacheDirPath, globals.config.cacheDirPath)
+	}
+	if globals.config.cacheStorage != cacheStoragePerInodeFile {
+		t.Errorf("expected cacheStorage == %q, got %q", cacheStoragePerInodeFile, globals.config.cacheStorage)
+	}
+	if globals.config.cacheLineSize != 64<<20 {
+		t.Errorf("expected cacheLineSize == 64Mi, got %v", globals.config.cacheLineSize)
+	}
+	if globals.config.cacheLines != 16 {
+		t.Errorf("expected cacheLines == 16 (1Gi / 64Mi), got %v", globals.config.cacheLines)
+	}
+
+	for _, testCase := range []struct {
+		sizeAsInterface interface{}
+		size            uint64
+		ok              bool
+	}{
+		{"500M", 500 << 20, true},
+		{"10G", 10 << 30, true},
+		{"1TB", 1 << 40, true},
+		{"2GiB", 2 << 30, true},
+		{"4096", 4096, true},
+		{4096, 4096, true},
+		{float64(8192), 8192, true},
+		{"10X", 0, false},
+		{"G10", 0, false},
+		{-1, 0, false},
+	} {
+		size, ok := parseMSCSize(testCase.sizeAsInterface)
+		if (ok != testCase.ok) || (ok && (size != testCase.size)) {
+			t.Errorf("parseMSCSize(%v) returned (%v, %v); expected (%v, %v)", testCase.sizeAsInterface, size, ok, testCase.size, testCase.ok)
+		}
+	}
+}
+
+// TestMSCStorageProviderOptions verifies that Python MSC transfer tuning options (including
+// those of a rust_client sub-section) are honored in compatibility mode.
+func TestMSCStorageProviderOptions(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+profiles:
+  s3:
+    storage_provider:
+      type: s3
+      options:
+        base_path: test
+        multipart_threshold: 100M
+        multipart_chunksize: 16M
+        max_concurrency: 8
+        rust_client:
+          multipart_chunksize: 20M
+cache:
+  cache_line_size: 8M
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	backend := globals.config.backends["s3"]
+
+	if backend.multiPartCacheLineThreshold != 13 {
+		t.Errorf("expected multiPartCacheLineThreshold == 13 (100Mi / 8Mi rounded up), got %v", backend.multiPartCacheLineThreshold)
+	}
+	if backend.uploadPartCacheLines != 3 {
+		t.Errorf("expected uploadPartCacheLines == 3 (rust_client's 20Mi / 8Mi rounded up), got %v", backend.uploadPartCacheLines)
+	}
+	if backend.uploadPartConcurrency != 8 {
+		t.Errorf("expected uploadPartConcurrency == 8, got %v", backend.uploadPartConcurrency)
+	}
+}
+
+// TestMSCGCSProfile verifies that Python MSC "gcs" profiles are translated into GCS backends
+// while profiles of types lacking a corresponding backend are skipped and "gcs" profiles
+// specifying a credentials_provider are rejected.
+func TestMSCGCSProfile(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+profiles:
+  gcs:
+    storage_provider:
+      type: gcs
+      options:
+        project_id: test-project
+        base_path: bucket/dir
+        endpoint_url: http://localhost:4443
+  azure:
+    storage_provider:
+      type: azure
+      options:
+        base_path: container
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	if len(globals.config.backends) != 1 {
+		t.Fatalf("expected only the \"gcs\" profile to yield a backend, got %v", len(globals.config.backends))
+	}
+
+	backend, ok := globals.config.backends["gcs"]
+	if !ok {
+		t.Fatalf("expected a \"gcs\" backend")
+	}
+	if (backend.backendType != "GCS") || (backend.bucketContainerName != "bucket") || (backend.prefix != "dir/") {
+		t.Errorf("\"gcs\" backend mis-translated: backendType %q bucketContainerName %q prefix %q", backend.backendType, backend.bucketContainerName, backend.prefix)
+	}
+	if backend.backendTypeSpecifics.(*backendConfigGCSStruct).endpoint != "http://localhost:4443" {
+		t.Errorf("expected GCS endpoint \"http://localhost:4443\", got %q", backend.backendTypeSpecifics.(*backendConfigGCSStruct).endpoint)
+	}
+
+	if _, ok = globals.backendsSkipped["azure"]; !ok {
+		t.Errorf("expected profile \"azure\" to have been skipped")
+	}
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+profiles:
+  gcs-sa:
+    storage_provider:
+      type: gcs
+      options:
+        base_path: bucket
+    credentials_provider:
+      type: GoogleServiceAccountCredentialsProvider
+      options:
+        file: /path/to/key.json
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err == nil {
+		t.Fatalf("checkConfigFile() unexpectedly succeeded with a \"gcs\" profile specifying a credentials_provider")
+	}
+}
+
+// TestParseSecret verifies the resolution of plaintext and from_env, from_file, and
+// from_command secret references as well as the rejection of malformed references.
+func TestParseSecret(t *testing.T) {
+	secretFilePath := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFilePath, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	t.Setenv("MSFS_TEST_SECRET", "env-secret")
+
+	m := map[string]interface{}{
+		"plain":        "${MSFS_TEST_SECRET}-plain",
+		"env":          map[string]interface{}{"from_env": "MSFS_TEST_SECRET"},
+		"file":         map[string]interface{}{"from_file": secretFilePath},
+		"shell":        map[string]interface{}{"from_command": "echo shell-secret"},
+		"argv":         map[string]interface{}{"from_command": []interface{}{"echo", "argv-secret"}},
+		"unsetEnv":     map[string]interface{}{"from_env": "MSFS_TEST_SECRET_UNSET"},
+		"missingFile":  map[string]interface{}{"from_file": secretFilePath + ".missing"},
+		"failCommand":  map[string]interface{}{"from_command": "exit 1"},
+		"badSource":    map[string]interface{}{"from_keyring": "s3"},
+		"twoSources":   map[string]interface{}{"from_env": "MSFS_TEST_SECRET", "from_file": secretFilePath},
+		"notString":    map[string]interface{}{"from_env": 1},
+		"notStringArg": map[string]interface{}{"from_command": []interface{}{"echo", 1}},
+		"number":       1,
+	}
+
+	expected := map[string]string{
+		"plain":   "env-secret-plain",
+		"env":     "env-secret",
+		"file":    "file-secret",
+		"shell":   "shell-secret",
+		"argv":    "argv-secret",
+		"missing": "dflt",
+	}
+
+	for key, value := range expected {
+		s, err := parseSecret(m, key, "dflt")
+		if err != nil {
+			t.Fatalf("parseSecret(\"%s\") unexpectedly failed: %v", key, err)
+		}
+		if s != value {
+			t.Fatalf("parseSecret(\"%s\") returned \"%s\", expected \"%s\"", key, s, value)
+		}
+	}
+
+	for _, key := range []string{"unsetEnv", "missingFile", "failCommand", "badSource", "twoSources", "notString", "notStringArg", "number"} {
+		if _, err := parseSecret(m, key, "dflt"); err == nil {
+			t.Fatalf("parseSecret(\"%s\") unexpectedly succeeded", key)
+		}
+	}
+
+	if _, err := parseSecret(m, "missing", nil); err == nil {
+		t.Fatalf("parseSecret(\"missing\") without a default unexpectedly succeeded")
+	}
+}
+
+// TestConfigSchemaValidation verifies that every schema problem in an MSFS-specific
+// config-file is reported at once (each located by its path) and that unrecognized
+// settings are merely warned about.
+func TestConfigSchemaValidation(t *testing.T) {
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+fuse_workers: many
+allow_other: "yes"
+backends: [
+  {
+    dir_name: ram,
+    backend_type: RAM,
+    readonly: 1,
+    RAM: {max_total_objects: lots},
+  },
+  {
+    dir_name: s3,
+    bucket_container_name: test,
+    backend_type: S4,
+    S3: {region: 1, secret_access_key: {from_vault: key}, endpoints: http://s3:9000},
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	err = checkConfigFile()
+	if err == nil {
+		t.Fatalf("checkConfigFile() unexpectedly succeeded")
+	}
+
+	for _, path := range []string{
+		"fuse_workers",
+		"allow_other",
+		"backends[0].bucket_container_name",
+		"backends[0].readonly",
+		"backends[0].RAM.max_total_objects",
+		"backends[1].backend_type",
+		"backends[1].S3.region",
+		"backends[1].S3.secret_access_key",
+		"backends[1].S3.endpoints",
+	} {
+		if !strings.Contains(err.Error(), "\n"+path+": ") {
+			t.Errorf("checkConfigFile() error should locate a problem at %s, got: %v", path, err)
+		}
+	}
+
+	if !strings.HasPrefix(err.Error(), "9 problem(s) found:") {
+		t.Errorf("checkConfigFile() error should count 9 problems, got: %v", err)
+	}
+
+	problems, warnings := validateConfigSchema(map[string]interface{}{
+		"msfs_version": 1,
+		"max_wrte":     131072,
+		"backends": []interface{}{
+			map[string]interface{}{
+				"dir_name":              "ram",
+				"bucket_container_name": "ignored",
+				"backend_type":          "RAM",
+				"RAM":                   map[string]interface{}{"max_list_pagesize": 10},
+			},
+		},
+	})
+	if len(problems) != 0 {
+		t.Fatalf("validateConfigSchema() unexpectedly found problems: %v", problems)
+	}
+	if (len(warnings) != 2) || !strings.HasPrefix(warnings[0], "backends[0].RAM.max_list_pagesize: ") || !strings.HasPrefix(warnings[1], "max_wrte: ") {
+		t.Fatalf("validateConfigSchema() returned unexpected warnings: %v", warnings)
+	}
+}
+
+// TestObservabilityMetricsBackendAttributes verifies the parsing of opentelemetry.metrics.backend_attributes
+// and the attributes backendMetricsAttributes() consequently returns.
+func TestObservabilityMetricsBackendAttributes(t *testing.T) {
+	var (
+		backendAttributes []attribute.KeyValue
+		err               error
+	)
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".json"]))
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+	{
+		"msfs_version": 1,
+		"backends": [
+			{
+				"dir_name": "ram",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM"
+			}
+		],
+		"opentelemetry": {
+			"metrics": {
+				"backend_attributes": ["bucket", "bucket"]
+			}
+		}
+	}
+	`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	err = checkConfigFile()
+	if err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	if !slices.Equal(globals.config.observability.metricsBackendAttributes, []string{metricsBackendAttributeBucket}) {
+		t.Fatalf("metricsBackendAttributes == %v (expected: [bucket])", globals.config.observability.metricsBackendAttributes)
+	}
+
+	backendAttributes = backendMetricsAttributes(globals.config.backends["ram"])
+	if !slices.Equal(backendAttributes, []attribute.KeyValue{attribute.String("msfs.bucket", "ignored")}) {
+		t.Fatalf("backendMetricsAttributes() returned %v", backendAttributes)
+	}
+
+	backendAttributes = backendMetricsAttributes(&backendStruct{dirName: "discovered"})
+	if len(backendAttributes) != 0 {
+		t.Fatalf("backendMetricsAttributes() of a backend without a bucket_container_name returned %v", backendAttributes)
+	}
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+	{
+		"msfs_version": 1,
+		"backends": [
+			{
+				"dir_name": "ram",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM"
+			}
+		],
+		"opentelemetry": {
+			"metrics": {
+				"backend_attributes": ["dir_name"]
+			}
+		}
+	}
+	`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	err = checkConfigFile()
+	if err == nil {
+		t.Fatalf("checkConfigFile() with backend_attributes: [\"dir_name\"] should have failed")
+	}
+}
+
+// TestObservabilityMetricsConfigValidation verifies that checkConfigFile() rejects malformed
+// opentelemetry.metrics settings and applies reader option defaults.
+func TestObservabilityMetricsConfigValidation(t *testing.T) {
+	var (
+		err         error
+		metricsJSON string
+	)
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".json"]))
+
+	writeConfig := func(metricsJSON string) {
+		err = os.WriteFile(globals.configFilePath, []byte(`
+		{
+			"msfs_version": 1,
+			"backends": [
+				{
+					"dir_name": "ram",
+					"bucket_container_name": "ignored",
+					"backend_type": "RAM"
+				}
+			],
+			"opentelemetry": {
+				"metrics": `+metricsJSON+`
+			}
+		}
+		`), 0o600)
+		if err != nil {
+			t.Fatalf("os.WriteFile() failed: %v", err)
+		}
+	}
+
+	writeConfig(`{"reader": {"options": {"collect_interval_millis": 500}}, "exporter": {"type": "otlp", "options": {"endpoint": "otel-collector:4318"}}}`)
+
+	err = checkConfigFile()
+	if err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+	if (globals.config.observability.metricsReaderOptions.CollectIntervalMillis != 500) || (globals.config.observability.metricsReaderOptions.ExportIntervalMillis != 60000) {
+		t.Fatalf("metricsReaderOptions == %+v (expected collect_interval_millis 500 & export_interval_millis default 60000)", *globals.config.observability.metricsReaderOptions)
+	}
+	if globals.config.observability.metricsReaderOptions.MaxBufferedSnapshots != 600 {
+		t.Fatalf("metricsReaderOptions.MaxBufferedSnapshots == %v (expected default 600)", globals.config.observability.metricsReaderOptions.MaxBufferedSnapshots)
+	}
+
+	writeConfig(`{"reader": {"options": {"temporality": "delta"}}, "views": [{"instrument": "multistorageclient.latency", "aggregation": "explicit_bucket_histogram", "boundaries": [0.001, 0.01, 1]}, {"instrument": "multistorageclient.data_*", "aggregation": "drop"}]}`)
+
+	err = checkConfigFile()
+	if err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+	if globals.config.observability.metricsReaderOptions.Temporality != "delta" {
+		t.Fatalf("metricsReaderOptions.Temporality == \"%s\" (expected \"delta\")", globals.config.observability.metricsReaderOptions.Temporality)
+	}
+	if (len(globals.config.observability.metricsViews) != 2) ||
+		!slices.Equal(globals.config.observability.metricsViews[0].Boundaries, []float64{0.001, 0.01, 1}) ||
+		(globals.config.observability.metricsViews[1].Aggregation != "drop") {
+		t.Fatalf("metricsViews == %+v (expected latency histogram & data_* drop)", globals.config.observability.metricsViews)
+	}
+
+	writeConfig(`{"exporter": {"type": "console"}}`)
+
+	err = checkConfigFile()
+	if err != nil {
+		t.Fatalf("checkConfigFile() with an unsupported exporter type should have only warned: %v", err)
+	}
+
+	for _, metricsJSON = range []string{
+		`[]`,
+		`{"attributes": {}}`,
+		`{"attributes": [{"options": {}}]}`,
+		`{"reader": {"options": {"export_interval_millis": 0}}}`,
+		`{"reader": {"options": {"temporality": "instant"}}}`,
+		`{"reader": {"options": {"max_buffered_snapshots": 0}}}`,
+		`{"views": {}}`,
+		`{"views": [{"aggregation": "sum"}]}`,
+		`{"views": [{"instrument": "multistorageclient.latency", "aggregation": "histogram"}]}`,
+		`{"views": [{"instrument": "multistorageclient.latency", "aggregation": "sum", "boundaries": [1]}]}`,
+		`{"views": [{"instrument": "multistorageclient.latency", "aggregation": "explicit_bucket_histogram", "boundaries": [1, 1]}]}`,
+		`{"exporter": {"type": "otlp"}}`,
+		`{"exporter": {"type": "otlp", "options": {"endpoint": "otel-collector:4318", "insecure": "yes"}}}`,
+		`{"exporter": {"type": "_otlp_msal", "options": {"auth": {"client_id": "id", "client_credential": "secret", "authority": "https://login"}, "exporter": {"endpoint": "e"}}}}`,
+	} {
+		writeConfig(metricsJSON)
+
+		err = checkConfigFile()
+		if err == nil {
+			t.Fatalf("checkConfigFile() with opentelemetry.metrics == %s should have failed", metricsJSON)
+		}
+	}
+}
+
+// TestObservabilityMountInstanceAttributes verifies the "mount_instance" attribute provider
+// distinguishes this daemon and omits the FUSE connection ID while not (yet) mounted.
+func TestObservabilityMountInstanceAttributes(t *testing.T) {
+	var (
+		attributeProviders []attributes.AttributesProvider
+		err                error
+		keyValue           attribute.KeyValue
+		keyValues          []attribute.KeyValue
+		startTime          time.Time
+	)
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".json"]))
+
+	if globals.mountInstanceID == "" {
+		t.Fatalf("initGlobals() should have set globals.mountInstanceID")
+	}
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+	{
+		"msfs_version": 1,
+		"mountpoint": "`+t.TempDir()+`",
+		"backends": [
+			{
+				"dir_name": "ram",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM"
+			}
+		],
+		"opentelemetry": {
+			"metrics": {
+				"attributes": [
+					{
+						"type": "mount_instance",
+						"options": {
+							"attributes": {
+								"msfs.instance": "instance_id",
+								"msfs.start_time": "start_time",
+								"msfs.fuse_connection": "fuse_connection_id"
+							}
+						}
+					}
+				]
+			}
+		}
+	}
+	`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	err = checkConfigFile()
+	if err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	attributeProviders = processAttributeProviders(globals.config.observability.metricsAttributes)
+	if len(attributeProviders) != 1 {
+		t.Fatalf("processAttributeProviders() returned %d providers (expected 1)", len(attributeProviders))
+	}
+
+	keyValues = attributes.CollectAttributes(attributeProviders)
+	if len(keyValues) != 2 {
+		t.Fatalf("attributes.CollectAttributes() returned %v (expected msfs.instance and msfs.start_time only)", keyValues)
+	}
+
+	for _, keyValue = range keyValues {
+		switch keyValue.Key {
+		case "msfs.instance":
+			if keyValue.Value.AsString() != globals.mountInstanceID {
+				t.Fatalf("msfs.instance == \"%s\" (expected \"%s\")", keyValue.Value.AsString(), globals.mountInstanceID)
+			}
+		case "msfs.start_time":
+			startTime, err = time.Parse(time.RFC3339, keyValue.Value.AsString())
+			if err != nil {
+				t.Fatalf("msfs.start_time == \"%s\" is not RFC 3339: %v", keyValue.Value.AsString(), err)
+			}
+			if startTime.Unix() != globals.mountInstanceStartTime.Unix() {
+				t.Fatalf("msfs.start_time == %v (expected %v)", startTime, globals.mountInstanceStartTime)
+			}
+		default:
+			t.Fatalf("unexpected attribute %s (FUSE connection ID should be omitted while not mounted)", keyValue.Key)
+		}
+	}
+}
@@ -449,6 +449,7 @@ type volumeBackendJSON struct {
 	ManifestPath                string `json:"manifestPath"`
 	ManifestGenWorkers          string `json:"manifestGenWorkers"`
 	FlatDirConfirmationPages    string `json:"flatDirConfirmationPages"`
+	LogLevel                    string `json:"logLevel"`
 	TraceLevel                  string `json:"traceLevel"`
 	DirectoryPageSize           string `json:"directoryPageSize"`
 	Uid                         string `json:"uid"`
@@ -560,6 +561,7 @@ func renderBackendTuningExtra(get func(key string) string) string {
 	optionalQuoted("manifestPath", "manifest_path")
 	optionalStr("manifestGenWorkers", "manifest_gen_workers")
 	optionalStr("flatDirConfirmationPages", "flat_dir_confirmation_pages")
+	optionalQuoted("logLevel", "log_level")
 	optionalStr("traceLevel", "trace_level")
 	optionalStr("directoryPageSize", "directory_page_size")
 	optionalStr("uid", "uid")
@@ -620,6 +622,7 @@ func backendFromJSON(e volumeBackendJSON, volumeReadonly bool) (csiBackend, erro
 		"manifestPath":                e.ManifestPath,
 		"manifestGenWorkers":          e.ManifestGenWorkers,
 		"flatDirConfirmationPages":    e.FlatDirConfirmationPages,
+		"logLevel":                    e.LogLevel,
 		"traceLevel":                  e.TraceLevel,
 		"directoryPageSize":           e.DirectoryPageSize,
 		"uid":                         e.Uid,
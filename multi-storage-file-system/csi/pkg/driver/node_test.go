@@ -408,7 +408,7 @@ func TestWriteConfig_BackendsJsonTuningFields(t *testing.T) {
 	ns := newNodeServer("node-test", "/usr/local/bin/msfs")
 	backendsJSON := `[
 	  {"dirName":"a","backendType":"S3","bucketName":"bucket-a","manifestPath":"/var/lib/msfs/m-a","manifestGenWorkers":"200","uid":"1000","gid":"1001","dirPerm":"775","traceLevel":"2"},
-	  {"dirName":"b","backendType":"S3","bucketName":"bucket-b","manifestPath":"/var/lib/msfs/m-b","flushOnClose":"false","filePerm":"640"}
+	  {"dirName":"b","backendType":"S3","bucketName":"bucket-b","manifestPath":"/var/lib/msfs/m-b","flushOnClose":"false","filePerm":"640","logLevel":"debug"}
 	]`
 	dir, configPath := writeConfigOrFatal(t, ns,
 		map[string]string{"backendsJson": backendsJSON},
@@ -428,6 +428,7 @@ func TestWriteConfig_BackendsJsonTuningFields(t *testing.T) {
 		`manifest_path: "/var/lib/msfs/m-b"`,
 		"flush_on_close: false",
 		`file_perm: "640"`,
+		`log_level: "debug"`,
 	} {
 		if !strings.Contains(body, want) {
 			t.Fatalf("tuning-fields config missing %q; got:\n%s", want, body)
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	LogDestinationStdout   = "stdout"   // Log to os.Stdout (the default)
+	LogDestinationSyslog   = "syslog"   // Log to the local syslog daemon (facility LOG_DAEMON) at the priority of each line's level
+	LogDestinationJournald = "journald" // Log to os.Stderr with each line prefixed by its "<priority>" (see sd-daemon(3)) for systemd-journald
+)
+
+const (
+	daemonChildEnv      = "MSFS_DAEMON_CHILD" // Set (to "1") in the environment of the background process started by daemonize()
+	daemonNotifyFD      = 3                   // File descriptor of the pipe to the parent in the background process (i.e. exec.Cmd.ExtraFiles[0])
+	daemonNotifyMounted = "mounted\n"         // Written to the parent by daemonNotify() upon a successful mount
+)
+
+// `daemonStruct` holds the --daemon, --pidfile, and --log-destination command line options.
+type daemonStruct struct {
+	enabled        bool     // If true, run in the background once the FUSE file system is mounted
+	pidFile        string   // If != "", path to which the daemon's PID is written once the FUSE file system is mounted
+	logDestination string   // One of LogDestination*
+	notifyFile     *os.File // [enabled] If != nil, the pipe to the parent process awaiting daemonNotify()
+}
+
+// `parseDaemonArgs` removes the --daemon, --pidfile, and --log-destination options (each
+// of which may be anywhere on the command line) from osArgs, recording them in
+// globals.daemon, returning the remaining arguments. Unless explicitly specified,
+// --daemon implies --log-destination syslog.
+func parseDaemonArgs(osArgs []string) (remainingArgs []string, err error) {
+	var (
+		argIndex               int
+		hasValue               bool
+		logDestinationExplicit bool
+		name                   string
+		value                  string
+	)
+
+	globals.daemon = daemonStruct{
+		logDestination: LogDestinationStdout,
+	}
+
+	remainingArgs = make([]string, 0, len(osArgs))
+
+	for argIndex = 0; argIndex < len(osArgs); argIndex++ {
+		if argIndex == 0 {
+			remainingArgs = append(remainingArgs, osArgs[argIndex])
+			continue
+		}
+
+		name, value, hasValue = strings.Cut(osArgs[argIndex], "=")
+
+		switch name {
+		case "--daemon":
+			if hasValue {
+				err = errors.New("--daemon does not take a value")
+				return
+			}
+			globals.daemon.enabled = true
+			continue
+		case "--pidfile", "--log-destination":
+			if !hasValue {
+				argIndex++
+				if argIndex == len(osArgs) {
+					err = fmt.Errorf("%s requires a value", name)
+					return
+				}
+				value = osArgs[argIndex]
+			}
+		default:
+			remainingArgs = append(remainingArgs, osArgs[argIndex])
+			continue
+		}
+
+		if name == "--pidfile" {
+			if value == "" {
+				err = errors.New("--pidfile requires a non-empty path")
+				return
+			}
+			globals.daemon.pidFile = value
+			continue
+		}
+
+		switch value {
+		case LogDestinationStdout, LogDestinationSyslog, LogDestinationJournald:
+			globals.daemon.logDestination = value
+			logDestinationExplicit = true
+		default:
+			err = fmt.Errorf("bad --log-destination value \"%s\" (must be \"%s\", \"%s\", or \"%s\")", value, LogDestinationStdout, LogDestinationSyslog, LogDestinationJournald)
+			return
+		}
+	}
+
+	// A daemon's os.Stdout is discarded, so default to LogDestinationSyslog
+
+	if globals.daemon.enabled && !logDestinationExplicit {
+		globals.daemon.logDestination = LogDestinationSyslog
+	}
+
+	return
+}
+
+// `daemonize` is called (if --daemon was specified) before anything else is initialized. In the
+// original process, it re-executes the current binary (with osArgs[1:]) as the leader of
+// a new session detached from the terminal and awaits that background process either reporting
+// (see daemonNotify()) a successful mount, whereupon it exits with status 0, or exiting, whereupon
+// it exits with a non-zero status. In the background process, it simply returns.
+func daemonize(osArgs []string) {
+	var (
+		cmd          *exec.Cmd
+		devNull      *os.File
+		err          error
+		executable   string
+		exitErr      *exec.ExitError
+		notification []byte
+		notifyReader *os.File
+		notifyWriter *os.File
+	)
+
+	if os.Getenv(daemonChildEnv) == "1" {
+		globals.daemon.notifyFile = os.NewFile(daemonNotifyFD, "daemon-notify")
+		return
+	}
+
+	executable, err = os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to locate executable for --daemon: %v\n", err)
+		os.Exit(1)
+	}
+
+	devNull, err = os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to open %s for --daemon: %v\n", os.DevNull, err)
+		os.Exit(1)
+	}
+
+	notifyReader, notifyWriter, err = os.Pipe()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create pipe for --daemon: %v\n", err)
+		os.Exit(1)
+	}
+
+	cmd = exec.Command(executable, osArgs[1:]...)
+	cmd.Env = append(os.Environ(), daemonChildEnv+"=1")
+	cmd.Stdin = devNull
+	cmd.Stdout = devNull
+	cmd.Stderr = devNull
+	cmd.ExtraFiles = []*os.File{notifyWriter}
+	cmd.SysProcAttr = daemonSysProcAttr()
+
+	err = cmd.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to start background process for --daemon: %v\n", err)
+		os.Exit(1)
+	}
+
+	_ = notifyWriter.Close()
+	_ = devNull.Close()
+
+	notification, _ = io.ReadAll(notifyReader)
+
+	if string(notification) == daemonNotifyMounted {
+		os.Exit(0)
+	}
+
+	err = cmd.Wait()
+	if errors.As(err, &exitErr) && (exitErr.ExitCode() > 0) {
+		fmt.Fprintf(os.Stderr, "mount failed (exit status %d); see the log (--log-destination \"%s\") for details\n", exitErr.ExitCode(), globals.daemon.logDestination)
+		os.Exit(exitErr.ExitCode())
+	}
+
+	fmt.Fprintf(os.Stderr, "mount failed (%v); see the log (--log-destination \"%s\") for details\n", err, globals.daemon.logDestination)
+	os.Exit(1)
+}
+
+// `daemonNotify` is called once the FUSE file system has been mounted to write any --pidfile
+// and, if running as the background process started by daemonize(), release the parent.
+func daemonNotify() {
+	var (
+		err error
+	)
+
+	if globals.daemon.pidFile != "" {
+		err = os.WriteFile(globals.daemon.pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644)
+		if err != nil {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] unable to write pidfile (\"%s\"): %v", globals.daemon.pidFile, err)
+		}
+	}
+
+	if globals.daemon.notifyFile != nil {
+		_, err = globals.daemon.notifyFile.WriteString(daemonNotifyMounted)
+		if err != nil {
+			globals.logger.Printf("[WARN] unable to notify --daemon parent process: %v", err)
+		}
+		_ = globals.daemon.notifyFile.Close()
+		globals.daemon.notifyFile = nil
+	}
+}
+
+// `removePIDFile` is called upon normal termination to remove any --pidfile.
+func removePIDFile() {
+	var (
+		err error
+	)
+
+	if globals.daemon.pidFile == "" {
+		return
+	}
+
+	err = os.Remove(globals.daemon.pidFile)
+	if (err != nil) && !errors.Is(err, os.ErrNotExist) {
+		globals.logger.Printf("[WARN] unable to remove pidfile (\"%s\"): %v", globals.daemon.pidFile, err)
+	}
+}
+
+// `logDestinationWriterStruct` is the io.Writer supplied to newLogHandler() when logging to
+// LogDestinationSyslog or LogDestinationJournald. Each Write() is made (by the wrapped handler
+// of a logDestinationHandlerStruct) with .level set to that of the record being written.
+type logDestinationWriterStruct struct {
+	sync.Mutex
+	level   slog.Level                                   // Level of the record currently being written
+	writeAt func(level slog.Level, p []byte) (err error) // Writes p at the priority corresponding to level
+}
+
+func (logDestinationWriter *logDestinationWriterStruct) Write(p []byte) (n int, err error) {
+	err = logDestinationWriter.writeAt(logDestinationWriter.level, p)
+	if err == nil {
+		n = len(p)
+	}
+	return
+}
+
+// `logDestinationHandlerStruct` wraps the slog.Handler returned by newLogHandler() in order to
+// convey each record's level to the logDestinationWriterStruct it writes to.
+type logDestinationHandlerStruct struct {
+	slog.Handler
+	writer *logDestinationWriterStruct
+}
+
+func (logDestinationHandler *logDestinationHandlerStruct) Handle(ctx context.Context, record slog.Record) (err error) {
+	logDestinationHandler.writer.Lock()
+	logDestinationHandler.writer.level = record.Level
+	err = logDestinationHandler.Handler.Handle(ctx, record)
+	logDestinationHandler.writer.Unlock()
+	return
+}
+
+func (logDestinationHandler *logDestinationHandlerStruct) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logDestinationHandlerStruct{Handler: logDestinationHandler.Handler.WithAttrs(attrs), writer: logDestinationHandler.writer}
+}
+
+func (logDestinationHandler *logDestinationHandlerStruct) WithGroup(name string) slog.Handler {
+	return &logDestinationHandlerStruct{Handler: logDestinationHandler.Handler.WithGroup(name), writer: logDestinationHandler.writer}
+}
+
+// `openLogDestination` is called (once the command line has been parsed) to connect to the
+// --log-destination (if other than LogDestinationStdout) to be used by initLogging().
+func openLogDestination() (err error) {
+	switch globals.daemon.logDestination {
+	case LogDestinationSyslog:
+		globals.logging.destinationWriter = &logDestinationWriterStruct{}
+		globals.logging.destinationWriter.writeAt, err = newSyslogWriteAt()
+		if err != nil {
+			globals.logging.destinationWriter = nil
+		}
+	case LogDestinationJournald:
+		globals.logging.destinationWriter = &logDestinationWriterStruct{writeAt: journaldWriteAt}
+	default:
+		globals.logging.destinationWriter = nil
+	}
+
+	return
+}
+
+// `journaldWriteAt` writes each line of p to os.Stderr prefixed by the "<priority>" of level.
+func journaldWriteAt(level slog.Level, p []byte) (err error) {
+	var (
+		line   string
+		prefix = "<" + strconv.Itoa(logLevelPriority(level)) + ">"
+		sb     strings.Builder
+	)
+
+	for line = range strings.Lines(string(p)) {
+		sb.WriteString(prefix)
+		sb.WriteString(line)
+	}
+
+	_, err = os.Stderr.WriteString(sb.String())
+	return
+}
+
+// `logLevelPriority` returns the syslog(3) priority (e.g. 4 for LOG_WARNING) corresponding to level.
+func logLevelPriority(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return 7 // LOG_DEBUG
+	case level < slog.LevelWarn:
+		return 6 // LOG_INFO
+	case level < slog.LevelError:
+		return 4 // LOG_WARNING
+	case level < logLevelFatal:
+		return 3 // LOG_ERR
+	default:
+		return 2 // LOG_CRIT
+	}
+}
@@ -0,0 +1,46 @@
+//go:build linux
+
+package main
+
+import (
+	"log/slog"
+	"log/syslog"
+	"syscall"
+)
+
+// `daemonSysProcAttr` returns the attributes of the background process started by daemonize()
+// making it the leader of a new session (and hence detached from any controlling terminal).
+func daemonSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// `newSyslogWriteAt` connects to the local syslog daemon returning a func writing at the
+// priority corresponding to each supplied level.
+func newSyslogWriteAt() (writeAt func(level slog.Level, p []byte) (err error), err error) {
+	var (
+		syslogWriter *syslog.Writer
+	)
+
+	syslogWriter, err = syslog.New(syslog.LOG_DAEMON|syslog.LOG_INFO, "msfs")
+	if err != nil {
+		return
+	}
+
+	writeAt = func(level slog.Level, p []byte) (err error) {
+		switch logLevelPriority(level) {
+		case 7:
+			err = syslogWriter.Debug(string(p))
+		case 6:
+			err = syslogWriter.Info(string(p))
+		case 4:
+			err = syslogWriter.Warning(string(p))
+		case 3:
+			err = syslogWriter.Err(string(p))
+		default:
+			err = syslogWriter.Crit(string(p))
+		}
+		return
+	}
+
+	return
+}
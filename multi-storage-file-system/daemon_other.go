@@ -0,0 +1,21 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"syscall"
+)
+
+// `daemonSysProcAttr` returns no special attributes on non-Linux platforms. MSFS production
+// targets are Linux, so this path is for local dev/test builds (e.g. macOS) only.
+func daemonSysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// `newSyslogWriteAt` is unsupported on non-Linux platforms.
+func newSyslogWriteAt() (writeAt func(level slog.Level, p []byte) (err error), err error) {
+	err = errors.New("--log-destination \"syslog\" not supported on this platform")
+	return
+}
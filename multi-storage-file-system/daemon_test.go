@@ -0,0 +1,131 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestParseDaemonArgs(t *testing.T) {
+	var (
+		err           error
+		remainingArgs []string
+	)
+
+	defer func() { globals.daemon = daemonStruct{} }()
+
+	remainingArgs, err = parseDaemonArgs([]string{"msfs", "config.yaml"})
+	if (err != nil) || !slices.Equal(remainingArgs, []string{"msfs", "config.yaml"}) {
+		t.Fatalf("parseDaemonArgs() without options returned (%v, %v)", remainingArgs, err)
+	}
+	if globals.daemon.enabled || (globals.daemon.pidFile != "") || (globals.daemon.logDestination != LogDestinationStdout) {
+		t.Fatalf("parseDaemonArgs() without options set unexpected %+v", globals.daemon)
+	}
+
+	remainingArgs, err = parseDaemonArgs([]string{"msfs", "--daemon", "config.yaml", "--pidfile", "/run/msfs.pid"})
+	if (err != nil) || !slices.Equal(remainingArgs, []string{"msfs", "config.yaml"}) {
+		t.Fatalf("parseDaemonArgs() with --daemon returned (%v, %v)", remainingArgs, err)
+	}
+	if !globals.daemon.enabled || (globals.daemon.pidFile != "/run/msfs.pid") || (globals.daemon.logDestination != LogDestinationSyslog) {
+		t.Fatalf("parseDaemonArgs() with --daemon set unexpected %+v", globals.daemon)
+	}
+
+	remainingArgs, err = parseDaemonArgs([]string{"msfs", "--daemon", "--log-destination=journald", "config.yaml"})
+	if (err != nil) || !slices.Equal(remainingArgs, []string{"msfs", "config.yaml"}) {
+		t.Fatalf("parseDaemonArgs() with --log-destination=journald returned (%v, %v)", remainingArgs, err)
+	}
+	if globals.daemon.logDestination != LogDestinationJournald {
+		t.Fatalf("parseDaemonArgs() with --log-destination=journald set unexpected %+v", globals.daemon)
+	}
+
+	for _, badArgs := range [][]string{
+		{"msfs", "--pidfile"},
+		{"msfs", "--pidfile="},
+		{"msfs", "--daemon=yes"},
+		{"msfs", "--log-destination", "file"},
+	} {
+		_, err = parseDaemonArgs(badArgs)
+		if err == nil {
+			t.Fatalf("parseDaemonArgs(%v) should have failed", badArgs)
+		}
+	}
+}
+
+func TestLogLevelPriority(t *testing.T) {
+	for level, priority := range map[slog.Level]int{
+		logLevelTrace:   7,
+		slog.LevelDebug: 7,
+		slog.LevelInfo:  6,
+		slog.LevelWarn:  4,
+		slog.LevelError: 3,
+		logLevelFatal:   2,
+	} {
+		if logLevelPriority(level) != priority {
+			t.Fatalf("logLevelPriority(%v) returned %v (expected: %v)", level, logLevelPriority(level), priority)
+		}
+	}
+}
+
+func TestJournaldWriteAt(t *testing.T) {
+	var (
+		content     []byte
+		err         error
+		savedStderr = os.Stderr
+		stderrFile  *os.File
+	)
+
+	stderrFile, err = os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatalf("os.Create() failed: %v", err)
+	}
+
+	os.Stderr = stderrFile
+	err = journaldWriteAt(slog.LevelWarn, []byte("first\nsecond\n"))
+	os.Stderr = savedStderr
+	if err != nil {
+		t.Fatalf("journaldWriteAt() failed: %v", err)
+	}
+
+	content, err = os.ReadFile(stderrFile.Name())
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	if string(content) != "<4>first\n<4>second\n" {
+		t.Fatalf("journaldWriteAt() wrote unexpected %q", content)
+	}
+
+	_ = stderrFile.Close()
+}
+
+func TestDaemonNotifyPIDFile(t *testing.T) {
+	var (
+		content []byte
+		err     error
+	)
+
+	defer func() { globals.daemon = daemonStruct{} }()
+
+	_ = initLogging(nil)
+
+	globals.daemon = daemonStruct{pidFile: filepath.Join(t.TempDir(), "msfs.pid")}
+
+	daemonNotify()
+
+	content, err = os.ReadFile(globals.daemon.pidFile)
+	if err != nil {
+		t.Fatalf("os.ReadFile(pidFile) failed: %v", err)
+	}
+	if string(content) != strconv.Itoa(os.Getpid())+"\n" {
+		t.Fatalf("pidFile contained unexpected %q", content)
+	}
+
+	removePIDFile()
+
+	_, err = os.Stat(globals.daemon.pidFile)
+	if !os.IsNotExist(err) {
+		t.Fatalf("removePIDFile() should have removed the pidfile (err: %v)", err)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto/sha256"
+	"slices"
+)
+
+// `dedup` is called while holding the globals lock as a just fetched data cache line
+// (whose content hashes to contentHash) is placed on the Clean LRU. Should another Clean
+// data cache line already hold identical content, this line is made to share that line's
+// content (i.e. its .contentStart) and the memory of its own slot is released. Otherwise,
+// this line is recorded on globals.dataCacheDedupMap for subsequent lines to share.
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) dedup(contentHash [sha256.Size]byte) {
+	var (
+		ok       bool
+		owner    *dataCacheLineTrackerStruct
+		ownerPos uint64
+	)
+
+	dataCacheLineTracker.contentHash = contentHash
+	dataCacheLineTracker.dedupIndexed = true
+
+	ownerPos, ok = globals.dataCacheDedupMap[contentHash]
+	if !ok {
+		dataCacheLineTracker.dedupOwner = dataCacheLineTracker.pos
+		globals.dataCacheDedupMap[contentHash] = dataCacheLineTracker.pos
+		return
+	}
+
+	owner = &globals.dataCacheLinesTracker[ownerPos]
+
+	dataCacheLineTracker.dedupOwner = ownerPos
+	dataCacheLineTracker.contentStart = owner.contentStart
+	owner.dedupSharers = append(owner.dedupSharers, dataCacheLineTracker.pos)
+
+	releaseDataCacheSlot(dataCacheLineTracker.contentSlot)
+
+	globals.dataCacheDedupLines++
+}
+
+// `undedup` is called while holding the globals lock as a data cache line leaves the Clean
+// LRU (to be evicted or freed) to withdraw it from any content sharing established by
+// dedup(). A line sharing another's content simply reverts to its own (released) slot.
+// A line whose slot is shared by others trades slots with one of them, that line becoming
+// the new owner of the content, such that the shared content is never overwritten.
+func (dataCacheLineTracker *dataCacheLineTrackerStruct) undedup() {
+	var (
+		newOwner *dataCacheLineTrackerStruct
+		owner    *dataCacheLineTrackerStruct
+		sharer   uint64
+	)
+
+	if !dataCacheLineTracker.dedupIndexed {
+		return
+	}
+
+	if dataCacheLineTracker.dedupOwner != dataCacheLineTracker.pos {
+		owner = &globals.dataCacheLinesTracker[dataCacheLineTracker.dedupOwner]
+		owner.dedupSharers = slices.DeleteFunc(owner.dedupSharers, func(pos uint64) bool { return pos == dataCacheLineTracker.pos })
+
+		globals.dataCacheDedupLines--
+	} else if len(dataCacheLineTracker.dedupSharers) == 0 {
+		delete(globals.dataCacheDedupMap, dataCacheLineTracker.contentHash)
+	} else {
+		newOwner = &globals.dataCacheLinesTracker[dataCacheLineTracker.dedupSharers[0]]
+
+		newOwner.contentSlot, dataCacheLineTracker.contentSlot = dataCacheLineTracker.contentSlot, newOwner.contentSlot
+		newOwner.dedupOwner = newOwner.pos
+		newOwner.dedupSharers = dataCacheLineTracker.dedupSharers[1:]
+
+		for _, sharer = range newOwner.dedupSharers {
+			globals.dataCacheLinesTracker[sharer].dedupOwner = newOwner.pos
+		}
+
+		globals.dataCacheDedupMap[dataCacheLineTracker.contentHash] = newOwner.pos
+
+		globals.dataCacheDedupLines--
+	}
+
+	dataCacheLineTracker.contentStart = dataCacheLineTracker.contentSlot
+	dataCacheLineTracker.contentHash = [sha256.Size]byte{}
+	dataCacheLineTracker.dedupIndexed = false
+	dataCacheLineTracker.dedupOwner = 0 // not yet applicable
+	dataCacheLineTracker.dedupSharers = nil
+}
+
+// `releaseDataCacheSlot` returns the memory holding the data cache line slot starting at
+// contentStart in globals.dataCacheLinesContent to the kernel. With cache_storage
+// "mapped-file", the slot's range of the backing file is punched out (as dropping the
+// mapping's pages would leave them in the page cache); with "ram", its pages are dropped.
+func releaseDataCacheSlot(contentStart uint64) {
+	var (
+		err error
+	)
+
+	if globals.dataCacheLinesFile != nil {
+		err = punchHoleSyscall(globals.dataCacheLinesFile, int64(contentStart), int64(globals.config.cacheLineSize))
+		if err != nil {
+			globals.logger.Printf("[WARN] punchHoleSyscall() of a data cache line slot failed: %v", err)
+		}
+		return
+	}
+
+	releaseCacheLineMemory(globals.dataCacheLinesContent[contentStart : contentStart+globals.config.cacheLineSize])
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+// TestCacheDedup verifies that dedup() makes a Clean data cache line with content identical
+// to that of another share it, and that the shared content survives the owner's departure
+// from the Clean LRU by being handed over to the remaining sharer.
+func TestCacheDedup(t *testing.T) {
+	var (
+		cacheLineNumber      uint64
+		content              [][]byte
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		lines                []uint64
+		ok                   bool
+		ownerPos             uint64
+		trackers             []*dataCacheLineTrackerStruct
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	content = [][]byte{
+		bytes.Repeat([]byte("A"), 100),
+		bytes.Repeat([]byte("A"), 100),
+		bytes.Repeat([]byte("B"), 100),
+	}
+
+	globalsLock("dedup_test.go:32:2:TestCacheDedup")
+	lines, _ = allocateDataCacheLines(uint64(len(content)), "")
+	globalsLock("dedup_test.go:34:2:TestCacheDedup")
+	if len(lines) != len(content) {
+		globalsUnlock()
+		t.Fatalf("expected %v data cache lines but got %v", len(content), len(lines))
+	}
+	for _, cacheLineNumber = range lines {
+		dataCacheLineTracker = &globals.dataCacheLinesTracker[cacheLineNumber]
+		dataCacheLineTracker.inodeNumber = globals.config.backends["ram"].inode.inodeNumber
+		dataCacheLineTracker.lineNumber = cacheLineNumber
+		dataCacheLineTracker.contentLength = uint64(copy(globals.dataCacheLinesContent[dataCacheLineTracker.contentStart:], content[len(trackers)]))
+		globals.dataCacheLineCleanLRU.pushTail(dataCacheLineTracker)
+		dataCacheLineTracker.dedup(sha256.Sum256(content[len(trackers)]))
+		trackers = append(trackers, dataCacheLineTracker)
+	}
+
+	if (trackers[1].contentStart != trackers[0].contentStart) || (trackers[1].contentSlot == trackers[0].contentSlot) {
+		globalsUnlock()
+		t.Fatalf("identical data cache lines should share content")
+	}
+	if trackers[2].contentStart != trackers[2].contentSlot {
+		globalsUnlock()
+		t.Fatalf("distinct data cache line should not share content")
+	}
+	if globals.dataCacheDedupLines != 1 {
+		globalsUnlock()
+		t.Fatalf("globals.dataCacheDedupLines == %v (expected 1)", globals.dataCacheDedupLines)
+	}
+
+	// Remove the owner... the sharer should take over its slot (and content)
+
+	globals.dataCacheLineCleanLRU.popThis(trackers[0])
+	trackers[0].free()
+
+	if (trackers[1].contentStart != trackers[1].contentSlot) || (trackers[0].contentStart != trackers[0].contentSlot) || (trackers[0].contentStart == trackers[1].contentStart) {
+		globalsUnlock()
+		t.Fatalf("departing owner should have traded slots with its sharer")
+	}
+	if !bytes.Equal(globals.dataCacheLinesContent[trackers[1].contentStart:trackers[1].contentStart+trackers[1].contentLength], content[1]) {
+		globalsUnlock()
+		t.Fatalf("sharer lost its content upon the owner's departure")
+	}
+	ownerPos, ok = globals.dataCacheDedupMap[sha256.Sum256(content[1])]
+	if !ok || (ownerPos != trackers[1].pos) || (globals.dataCacheDedupLines != 0) {
+		globalsUnlock()
+		t.Fatalf("sharer should have become the owner of the content")
+	}
+
+	globals.dataCacheLineCleanLRU.popThis(trackers[1])
+	trackers[1].free()
+	globals.dataCacheLineCleanLRU.popThis(trackers[2])
+	trackers[2].free()
+
+	if len(globals.dataCacheDedupMap) != 0 {
+		globalsUnlock()
+		t.Fatalf("globals.dataCacheDedupMap should be empty but has %v entries", len(globals.dataCacheDedupMap))
+	}
+	globalsUnlock()
+}
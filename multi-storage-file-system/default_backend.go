@@ -0,0 +1,150 @@
+package main
+
+import (
+	"strings"
+	"syscall"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+// `cloneAsPrefix` returns a backendStruct configured just as backend except that it
+// is named dirName and presents the dirName "subdirectory" of backend's prefix. The
+// runtime state of the returned backendStruct is left for processToMountList() to set up.
+func (backend *backendStruct) cloneAsPrefix(dirName string) (clone *backendStruct) {
+	var (
+		prefix = backend.prefix
+	)
+
+	if (prefix != "") && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	clone = &backendStruct{
+		dirName:                     dirName,
+		readOnly:                    backend.readOnly,
+		flushOnClose:                backend.flushOnClose,
+		uid:                         backend.uid,
+		gid:                         backend.gid,
+		dirPerm:                     backend.dirPerm,
+		filePerm:                    backend.filePerm,
+		directoryPageSize:           backend.directoryPageSize,
+		multiPartCacheLineThreshold: backend.multiPartCacheLineThreshold,
+		uploadPartCacheLines:        backend.uploadPartCacheLines,
+		uploadPartConcurrency:       backend.uploadPartConcurrency,
+		bucketContainerName:         backend.bucketContainerName,
+		prefix:                      prefix + dirName + "/",
+		keySaltWidth:                backend.keySaltWidth,
+		manifestPath:                "",
+		symLinkSuffix:               backend.symLinkSuffix,
+		cacheBypass:                 backend.cacheBypass,
+		emulateFIFOs:                backend.emulateFIFOs,
+		entryTTL:                    backend.entryTTL,
+		attrTTL:                     backend.attrTTL,
+		manifestGenWorkers:          backend.manifestGenWorkers,
+		flatDirConfirmationPages:    backend.flatDirConfirmationPages,
+		flatDirHints:                backend.flatDirHints,
+		hedgeReadPercentile:         backend.hedgeReadPercentile,
+		hedgeReadMinDelay:           backend.hedgeReadMinDelay,
+		hedgeReadBudget:             backend.hedgeReadBudget,
+		readTimeout:                 backend.readTimeout,
+		listTimeout:                 backend.listTimeout,
+		headTimeout:                 backend.headTimeout,
+		writeTimeout:                backend.writeTimeout,
+		readDirTimeBudget:           backend.readDirTimeBudget,
+		readDirSnapshot:             backend.readDirSnapshot,
+		readDirSnapshotMaxEntries:   backend.readDirSnapshotMaxEntries,
+		maxConcurrentRequests:       backend.maxConcurrentRequests,
+		readBandwidthLimit:          backend.readBandwidthLimit,
+		requestRateLimit:            backend.requestRateLimit,
+		chaos:                       backend.chaos,
+		acl:                         backend.acl,
+		filter:                      backend.filter,
+		proxyURL:                    backend.proxyURL,
+		noProxy:                     backend.noProxy,
+		backendType:                 backend.backendType,
+		backendTypeSpecifics:        backend.backendTypeSpecifics,
+		mkDirOf:                     backend.dirName,
+	}
+
+	clone.logLevel.Set(backend.logLevel.Level())
+
+	return
+}
+
+// `mkDirDefaultBackend` is called without holding globals.Lock() to implement mkdir(2) of
+// dirName in the FUSE root directory. If a default_backend is configured, a backend named
+// dirName presenting the dirName prefix of the default_backend is created and mounted. As
+// with any other new directory, nothing is written to the backend until a file is created
+// in it. Such backends last until unmounted (i.e. they are not removed by a SIGHUP).
+func mkDirDefaultBackend(inHeader *fission.InHeader, dirName string) (backend *backendStruct, errno syscall.Errno) {
+	var (
+		defaultBackend *backendStruct
+		mounted        bool
+		ok             bool
+	)
+
+	// Serialize with reloadConfigFile() as both manipulate globals.backendsToMount
+
+	globals.reload.Lock()
+	defer globals.reload.Unlock()
+
+	globalsLock("default_backend.go:91:2:mkDirDefaultBackend")
+
+	if globals.mountReadOnly || (globals.config.defaultBackend == "") {
+		globalsUnlock()
+		errno = fuseRootDirWriteErrno()
+		return
+	}
+
+	defaultBackend, ok = globals.config.backends[globals.config.defaultBackend]
+	if !ok || !defaultBackend.mounted {
+		globalsUnlock()
+		errno = syscall.EPERM
+		return
+	}
+
+	if defaultBackend.readOnly {
+		globalsUnlock()
+		errno = syscall.EROFS
+		return
+	}
+
+	if !defaultBackend.acl.permits(inHeader.UID, inHeader.GID) {
+		globalsUnlock()
+		errno = syscall.EACCES
+		return
+	}
+
+	_, ok = globals.config.backends[dirName]
+	if !ok {
+		_, ok = globals.backendsToMount[dirName]
+	}
+	if ok {
+		globalsUnlock()
+		errno = syscall.EEXIST
+		return
+	}
+
+	backend = defaultBackend.cloneAsPrefix(dirName)
+
+	globals.backendsToMount[dirName] = backend
+
+	globalsUnlock()
+
+	processToMountList()
+
+	globalsLock("default_backend.go:136:2:mkDirDefaultBackend")
+	mounted = backend.mounted
+	globalsUnlock()
+
+	if !mounted {
+		globals.logger.Printf("[WARN] mkdir of \"%s\" unable to mount a prefix of default_backend \"%s\"", dirName, defaultBackend.dirName)
+		errno = syscall.EIO
+		return
+	}
+
+	globals.logger.Printf("[INFO] mkdir of \"%s\" mounted a prefix of default_backend \"%s\"", dirName, defaultBackend.dirName)
+
+	errno = 0
+	return
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+func TestFissionMkDirDefaultBackend(t *testing.T) {
+	var (
+		backend     *backendStruct
+		errno       syscall.Errno
+		lookupOut   *fission.LookupOut
+		mkDirOut    *fission.MkDirOut
+		ok          bool
+		scratchIno  uint64
+		subdirIno   uint64
+		subdirMkDir *fission.MkDirOut
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	// Without a default_backend, mkdir in the FUSE root is not permitted
+
+	_, errno = globals.DoMkDir(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.MkDirIn{Name: []byte("scratch")})
+	if errno != syscall.EPERM {
+		t.Fatalf("DoMkDir(FUSERootDirInodeNumber,Name:\"scratch\") without default_backend returned errno %v (expected: EPERM)", errno)
+	}
+
+	globalsLock("default_backend_test.go:32:2:TestFissionMkDirDefaultBackend")
+	globals.config.defaultBackend = "ram"
+	globalsUnlock()
+
+	mkDirOut, errno = globals.DoMkDir(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.MkDirIn{Name: []byte("scratch")})
+	if errno != 0 {
+		t.Fatalf("DoMkDir(FUSERootDirInodeNumber,Name:\"scratch\") unexpectedly failed (errno: %v)", errno)
+	}
+	scratchIno = mkDirOut.EntryOut.NodeID
+	if scratchIno != stableInodeNumber("scratch", "") {
+		t.Fatalf("DoMkDir(FUSERootDirInodeNumber,Name:\"scratch\") returned NodeID %v (expected: %v)", scratchIno, stableInodeNumber("scratch", ""))
+	}
+
+	globalsLock("default_backend_test.go:45:2:TestFissionMkDirDefaultBackend")
+	backend, ok = globals.config.backends["scratch"]
+	if !ok || !backend.mounted {
+		globalsUnlock()
+		t.Fatalf("DoMkDir(FUSERootDirInodeNumber,Name:\"scratch\") did not mount backends[\"scratch\"]")
+	}
+	if (backend.prefix != "scratch/") || (backend.mkDirOf != "ram") || backend.readOnly {
+		globalsUnlock()
+		t.Fatalf("backends[\"scratch\"] has prefix \"%s\", mkDirOf \"%s\", readOnly %v (expected: \"scratch/\", \"ram\", false)", backend.prefix, backend.mkDirOf, backend.readOnly)
+	}
+	globalsUnlock()
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.LookupIn{Name: []byte("scratch")})
+	if (errno != 0) || (lookupOut.EntryOut.NodeID != scratchIno) {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"scratch\") did not return NodeID %v (errno: %v)", scratchIno, errno)
+	}
+
+	subdirMkDir, errno = globals.DoMkDir(&fission.InHeader{NodeID: scratchIno}, &fission.MkDirIn{Name: []byte("subdir")})
+	if errno != 0 {
+		t.Fatalf("DoMkDir(scratchIno,Name:\"subdir\") unexpectedly failed (errno: %v)", errno)
+	}
+	subdirIno = subdirMkDir.EntryOut.NodeID
+	if subdirIno != stableInodeNumber("scratch", "subdir/") {
+		t.Fatalf("DoMkDir(scratchIno,Name:\"subdir\") returned NodeID %v (expected: %v)", subdirIno, stableInodeNumber("scratch", "subdir/"))
+	}
+
+	// Neither an existing backend nor an already created one may be created again
+
+	_, errno = globals.DoMkDir(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.MkDirIn{Name: []byte("scratch")})
+	if errno != syscall.EEXIST {
+		t.Fatalf("DoMkDir(FUSERootDirInodeNumber,Name:\"scratch\") [again] returned errno %v (expected: EEXIST)", errno)
+	}
+
+	_, errno = globals.DoMkDir(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.MkDirIn{Name: []byte("pseudo")})
+	if errno != syscall.EEXIST {
+		t.Fatalf("DoMkDir(FUSERootDirInodeNumber,Name:\"pseudo\") returned errno %v (expected: EEXIST)", errno)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EventBackendMounted   = "backend_mounted"   // A backend has been mounted (at startup or following a SIGHUP)
+	EventBackendUnmounted = "backend_unmounted" // A backend has been unmounted following a SIGHUP
+	EventMountDegraded    = "mount_degraded"    // A configured backend could not be mounted and has been skipped
+	EventReloadApplied    = "reload_applied"    // The config file has been successfully re-read
+	EventReloadFailed     = "reload_failed"     // The config file could not be re-read (the previous config remains in effect)
+	EventFlushFailed      = "flush_failed"      // Modified metadata or content could not be flushed to a backend
+	EventMountReady       = "mount_ready"       // The file system is mounted and each mounted backend has passed its initial probe
+)
+
+const (
+	EventSinkFilePrefix = "file:" // event_sink of the form "file:<path>" appends each event to <path>
+	EventSinkUnixPrefix = "unix:" // event_sink of the form "unix:<path>" writes each event to the stream socket at <path>
+
+	eventSinkChanDepth       = 1024 // Number of events buffered for the event_sink before events are dropped
+	eventSubscriberChanDepth = 64   // Number of events buffered for each /events subscriber before events are dropped
+)
+
+// `eventStruct` is the JSON-encoded form of each event published, one per line, to the
+// event_sink and each /events subscriber.
+type eventStruct struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Backend string    `json:"backend,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+}
+
+// `eventsStruct` tracks the destinations of published events. As events are published
+// while globals.Lock() may or may not be held, eventsStruct is protected by its own lock.
+type eventsStruct struct {
+	sync.Mutex
+	sinkChan    chan []byte              // If != nil, each event is sent here for eventSinkWorker() to write to the event_sink
+	sinkWG      sync.WaitGroup           // Tracks eventSinkWorker()
+	subscribers map[chan []byte]struct{} // Each /events subscriber's channel
+}
+
+// `startEventSink` launches the worker writing published events to globals.config.eventSink (if any).
+func startEventSink() {
+	var (
+		eventSink string
+		sinkChan  chan []byte
+	)
+
+	globals.events.Lock()
+	defer globals.events.Unlock()
+
+	if (globals.config.eventSink == "") || (globals.events.sinkChan != nil) {
+		return
+	}
+
+	globals.events.sinkChan = make(chan []byte, eventSinkChanDepth)
+	globals.events.sinkWG.Add(1)
+
+	eventSink, sinkChan = globals.config.eventSink, globals.events.sinkChan
+
+	_ = startWorker("eventSink", func(_ context.Context) { eventSinkWorker(eventSink, sinkChan) })
+}
+
+// `stopEventSink` waits for all previously published events to be written to
+// globals.config.eventSink (if any) and then stops the worker.
+func stopEventSink() {
+	globals.events.Lock()
+	if globals.events.sinkChan == nil {
+		globals.events.Unlock()
+		return
+	}
+	close(globals.events.sinkChan)
+	globals.events.sinkChan = nil
+	globals.events.Unlock()
+
+	globals.events.sinkWG.Wait()
+}
+
+// `eventSinkWorker` writes each event received on sinkChan to eventSink. For a "unix:" event_sink,
+// the connection is (re)established as needed and events published while it is unavailable are lost.
+func eventSinkWorker(eventSink string, sinkChan chan []byte) {
+	var (
+		conn      net.Conn
+		err       error
+		eventLine []byte
+		file      *os.File
+		lastErr   string
+	)
+
+	defer globals.events.sinkWG.Done()
+
+	if strings.HasPrefix(eventSink, EventSinkFilePrefix) {
+		file, err = os.OpenFile(strings.TrimPrefix(eventSink, EventSinkFilePrefix), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			globals.logger.Printf("[WARN] unable to open event_sink \"%s\": %v", eventSink, err)
+		}
+	}
+
+	for eventLine = range sinkChan {
+		switch {
+		case file != nil:
+			_, err = file.Write(eventLine)
+		case strings.HasPrefix(eventSink, EventSinkUnixPrefix):
+			if conn == nil {
+				conn, err = net.DialTimeout("unix", strings.TrimPrefix(eventSink, EventSinkUnixPrefix), time.Second)
+				if err != nil {
+					conn = nil
+					break
+				}
+			}
+			_, err = conn.Write(eventLine)
+			if err != nil {
+				_ = conn.Close()
+				conn = nil
+			}
+		default:
+			continue
+		}
+
+		if err == nil {
+			lastErr = ""
+		} else if err.Error() != lastErr {
+			// Avoid flooding the log while the event_sink remains unavailable
+			globals.logger.Printf("[WARN] unable to write to event_sink \"%s\": %v", eventSink, err)
+			lastErr = err.Error()
+		}
+	}
+
+	if file != nil {
+		_ = file.Close()
+	}
+	if conn != nil {
+		_ = conn.Close()
+	}
+}
+
+// `subscribeEvents` returns a channel on which each subsequently published event will be delivered.
+func subscribeEvents() (subscriberChan chan []byte) {
+	subscriberChan = make(chan []byte, eventSubscriberChanDepth)
+
+	globals.events.Lock()
+	if globals.events.subscribers == nil {
+		globals.events.subscribers = make(map[chan []byte]struct{})
+	}
+	globals.events.subscribers[subscriberChan] = struct{}{}
+	globals.events.Unlock()
+
+	return
+}
+
+// `unsubscribeEvents` stops the delivery of events to a channel returned by subscribeEvents().
+func unsubscribeEvents(subscriberChan chan []byte) {
+	globals.events.Lock()
+	delete(globals.events.subscribers, subscriberChan)
+	globals.events.Unlock()
+}
+
+// `publishEvent` delivers an event to the event_sink (if any) and each /events subscriber.
+// It never blocks: should a destination be unable to keep up, the event is dropped for it.
+func publishEvent(eventType string, backendName string, detail string) {
+	var (
+		err            error
+		eventLine      []byte
+		subscriberChan chan []byte
+	)
+
+	eventLine, err = json.Marshal(&eventStruct{
+		Time:    time.Now().UTC(),
+		Type:    eventType,
+		Backend: backendName,
+		Detail:  detail,
+	})
+	if err != nil {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] json.Marshal(&eventStruct{}) failed: %v", err)
+	}
+	eventLine = append(eventLine, '\n')
+
+	globals.events.Lock()
+	defer globals.events.Unlock()
+
+	if globals.events.sinkChan != nil {
+		select {
+		case globals.events.sinkChan <- eventLine:
+		default:
+			// Drop rather than block
+		}
+	}
+
+	for subscriberChan = range globals.events.subscribers {
+		select {
+		case subscriberChan <- eventLine:
+		default:
+			// Drop rather than block
+		}
+	}
+}
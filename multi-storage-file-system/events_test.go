@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEventSinkFile(t *testing.T) {
+	var (
+		event      eventStruct
+		eventFile  *os.File
+		eventTypes []string
+		scanner    *bufio.Scanner
+		sinkPath   = filepath.Join(t.TempDir(), "events.jsonl")
+	)
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+event_sink: "file:`+sinkPath+`"
+backends: [
+  {
+    dir_name: ram,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	startEventSink()
+
+	publishEvent(EventReloadApplied, "", "")
+	publishEvent(EventBackendMounted, "ram", "")
+
+	stopEventSink()
+
+	eventFile, err = os.Open(sinkPath)
+	if err != nil {
+		t.Fatalf("os.Open(sinkPath) failed: %v", err)
+	}
+	defer func() {
+		_ = eventFile.Close()
+	}()
+
+	scanner = bufio.NewScanner(eventFile)
+	for scanner.Scan() {
+		if err = json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("json.Unmarshal(%q) failed: %v", scanner.Text(), err)
+		}
+		eventTypes = append(eventTypes, event.Type)
+	}
+
+	if (len(eventTypes) != 2) || (eventTypes[0] != EventReloadApplied) || (eventTypes[1] != EventBackendMounted) {
+		t.Fatalf("unexpected events written to event_sink: %v", eventTypes)
+	}
+	if event.Backend != "ram" {
+		t.Fatalf("expected last event's backend == \"ram\", got %q", event.Backend)
+	}
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+event_sink: "tcp:localhost:9999"
+backends: []
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err == nil {
+		t.Fatalf("checkConfigFile() unexpectedly allowed event_sink \"tcp:localhost:9999\"")
+	}
+}
+
+func TestEventSubscribers(t *testing.T) {
+	var (
+		event          eventStruct
+		eventLine      []byte
+		subscriberChan = subscribeEvents()
+	)
+
+	publishEvent(EventMountDegraded, "s3", "unable to set up a backend context")
+
+	select {
+	case eventLine = <-subscriberChan:
+		if err := json.Unmarshal(eventLine, &event); err != nil {
+			t.Fatalf("json.Unmarshal(%q) failed: %v", eventLine, err)
+		}
+		if (event.Type != EventMountDegraded) || (event.Backend != "s3") {
+			t.Fatalf("unexpected event delivered: %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("event not delivered to subscriber")
+	}
+
+	unsubscribeEvents(subscriberChan)
+
+	publishEvent(EventBackendUnmounted, "s3", "")
+
+	select {
+	case eventLine = <-subscriberChan:
+		t.Fatalf("event %q unexpectedly delivered after unsubscribeEvents()", eventLine)
+	default:
+	}
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"math"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -41,6 +45,12 @@ const (
 
 	maxNameLen = uint32(4096)
 
+	accessMaskR = uint32(4) // R_OK
+	accessMaskW = uint32(2) // W_OK
+	accessMaskX = uint32(1) // X_OK
+
+	mkNodUnsupportedLogInterval = time.Minute // Minimum interval between DoMkNod() logs of unsupported special file requests
+
 	openOutFlags = uint32(0) |
 		fission.FOpenResponseDirectIO
 )
@@ -50,15 +60,20 @@ const (
 // dropped so the kernel page cache can absorb repeated warm reads of the FUSE
 // replies (matching s3fs, which sets no FOPEN flags). In the arena modes (ram /
 // mapped-file) DirectIO stays on so every read round-trips to MSFS and the
-// in-process cache is the sole authority.
-func computeOpenOutFlags() uint32 {
+// in-process cache is the sole authority. A cacheBypass file handle always
+// uses DirectIO so that neither cache is populated by its reads.
+func computeOpenOutFlags(cacheBypass bool) uint32 {
+	if cacheBypass {
+		return openOutFlags
+	}
 	if globals.config != nil && globals.config.cacheStorage == cacheStoragePerInodeFile {
 		return uint32(0)
 	}
 	return openOutFlags
 }
 
-// `performFissionMount` is called to do the single FUSE mount at startup.
+// `performFissionMount` is called to do the FUSE mount (at config.mountPoint as well
+// as at that of each of config.mounts) at startup.
 func performFissionMount() (err error) {
 	var (
 		fissionLogger       = log.New(globals.logger.Writer(), "[FISSION] ", globals.logger.Flags()) // set prefix to differentiate package fission logging
@@ -83,6 +98,11 @@ func performFissionMount() (err error) {
 	globals.fissionVolume = fission.NewVolume(fissionVolumeConfig)
 
 	err = globals.fissionVolume.DoMount()
+	if err != nil {
+		return
+	}
+
+	err = performMountsFissionMount(fissionLogger)
 
 	return
 }
@@ -94,6 +114,97 @@ func performFissionUnmount() (err error) {
 	return
 }
 
+// `updateMountReadOnly` is called once the FUSE mount is in place and following each config
+// reload to mark the mount read-only (MS_RDONLY, also reported as ST_RDONLY by statfs(2)) when
+// either read_only is set or every mounted backend is readonly. Should a writable backend later
+// be mounted, the mount is made writable again. A failure to (re)mark the mount is only logged
+// as the FUSE callbacks reject modifications regardless.
+func updateMountReadOnly() {
+	var (
+		backend       *backendStruct
+		err           error
+		mountPoint    string
+		mountReadOnly bool
+	)
+
+	globalsLock("fission.go:130:2:updateMountReadOnly")
+
+	mountReadOnly = true
+	if !globals.config.readOnly {
+		for _, backend = range globals.config.backends {
+			if !backend.readOnly {
+				mountReadOnly = false
+				break
+			}
+		}
+	}
+
+	if mountReadOnly == globals.mountReadOnly {
+		globalsUnlock()
+		return
+	}
+
+	globals.mountReadOnly = mountReadOnly
+	mountPoint = globals.config.mountPoint
+
+	globalsUnlock()
+
+	err = remountReadOnlySyscall(mountPoint, mountReadOnly)
+	if err == nil {
+		globals.logger.Printf("[INFO] FUSE mount (\"%s\") marked read-only: %v", mountPoint, mountReadOnly)
+	} else {
+		globals.logger.Printf("[WARN] unable to mark FUSE mount (\"%s\") read-only: %v: %v", mountPoint, mountReadOnly, err)
+	}
+}
+
+// `fuseRootDirWriteErrno` returns the errno for an attempt to modify the (never modifiable)
+// FUSE root directory: EROFS if the mount is read-only (see updateMountReadOnly()) or EPERM
+// otherwise. It must be called while holding globals.Lock().
+func fuseRootDirWriteErrno() syscall.Errno {
+	if globals.mountReadOnly {
+		return syscall.EROFS
+	}
+
+	return syscall.EPERM
+}
+
+// `readOnlyErrno` returns the errno for an unsupported modification of inodeNumber: EROFS if it
+// resides in a readonly backend or a "<name>.versions/" directory (or is the FUSE root directory
+// of a read-only mount) so that every write-class op is rejected consistently, otherwise
+// errnoIfWritable.
+func readOnlyErrno(inodeNumber uint64, errnoIfWritable syscall.Errno) (errno syscall.Errno) {
+	var (
+		backend *backendStruct
+		inode   *inodeStruct
+		ok      bool
+	)
+
+	globalsLock("fission.go:182:2:readOnlyErrno")
+
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	switch {
+	case !ok:
+		errno = errnoIfWritable
+	case inode.backendNonce == 0:
+		if globals.mountReadOnly {
+			errno = syscall.EROFS
+		} else {
+			errno = errnoIfWritable
+		}
+	default:
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if ok && (backend.readOnly || backend.inVersionsDir(inode.objectPath)) {
+			errno = syscall.EROFS
+		} else {
+			errno = errnoIfWritable
+		}
+	}
+
+	globalsUnlock()
+
+	return
+}
+
 // `fixAttrSizes` is called to leverage the .Size field of a fission.Attr
 // struct to compute and fill in the related .Blocks field. The .BlkSize
 // and .NLink fields are also set to their hard-coded values noting that
@@ -101,7 +212,7 @@ func performFissionUnmount() (err error) {
 // as is will not account for the ".." directory entries in each of those
 // subdirectories.
 func fixAttrSizes(attr *fission.Attr) {
-	if syscall.S_IFREG == (attr.Mode & syscall.S_IFMT) {
+	if (syscall.S_IFREG == (attr.Mode & syscall.S_IFMT)) || (syscall.S_IFLNK == (attr.Mode & syscall.S_IFMT)) || (syscall.S_IFIFO == (attr.Mode & syscall.S_IFMT)) {
 		attr.Blocks = attr.Size + (uint64(attrBlkSize) - 1)
 		attr.Blocks /= uint64(attrBlkSize)
 		attr.BlkSize = attrBlkSize
@@ -121,7 +232,7 @@ func fixAttrSizes(attr *fission.Attr) {
 // as is will not account for the ".." directory entries in each of those
 // subdirectories.
 func fixStatXSizes(statX *fission.StatX) {
-	if syscall.S_IFREG == (statX.Mode & syscall.S_IFMT) {
+	if (syscall.S_IFREG == (statX.Mode & syscall.S_IFMT)) || (syscall.S_IFLNK == (statX.Mode & syscall.S_IFMT)) || (syscall.S_IFIFO == (statX.Mode & syscall.S_IFMT)) {
 		statX.Blocks = statX.Size + (uint64(attrBlkSize) - 1)
 		statX.Blocks /= uint64(attrBlkSize)
 		statX.BlkSize = attrBlkSize
@@ -134,10 +245,41 @@ func fixStatXSizes(statX *fission.StatX) {
 	}
 }
 
+// `fixAttrTimes` is called to fill in the .{A|M|C}Time{|N}Sec fields of a
+// fission.Attr struct from inode's .mTime and .times.
+func fixAttrTimes(attr *fission.Attr, inode *inodeStruct) {
+	attr.ATimeSec, attr.ATimeNSec = timeTimeToAttrTime(inode.aTime())
+	attr.MTimeSec, attr.MTimeNSec = timeTimeToAttrTime(inode.mTime)
+	attr.CTimeSec, attr.CTimeNSec = timeTimeToAttrTime(inode.times.cTime)
+}
+
+// `aTime` returns inode's .times.aTime advanced to any later read time deferred
+// by a DoRead() holding globals only shared (see readResidentCacheLine()).
+func (inode *inodeStruct) aTime() (aTime time.Time) {
+	aTime = globals.inodeMap.deferredATime(inode.inodeNumber)
+	if inode.times.aTime.After(aTime) {
+		aTime = inode.times.aTime
+	}
+	return
+}
+
+// `fixStatXTimes` is called to fill in the .{A|B|C|M}Time fields of a
+// fission.StatX struct from inode's .mTime and .times.
+func fixStatXTimes(statX *fission.StatX, inode *inodeStruct) {
+	statX.ATime.TVSec, statX.ATime.TVNSec = timeTimeToAttrTime(inode.aTime())
+	statX.BTime.TVSec, statX.BTime.TVNSec = timeTimeToAttrTime(inode.times.bTime)
+	statX.CTime.TVSec, statX.CTime.TVNSec = timeTimeToAttrTime(inode.times.cTime)
+	statX.MTime.TVSec, statX.MTime.TVNSec = timeTimeToAttrTime(inode.mTime)
+}
+
 // `dirEntType` computes the directory entry type returned by DoReadDir{|Plus}()
 // for each directory entry.
 func (inode *inodeStruct) dirEntType() (dirEntType uint32) {
-	if inode.inodeType == FileObject {
+	if inode.isSymLink() {
+		dirEntType = syscall.DT_LNK
+	} else if inode.isFIFO() {
+		dirEntType = syscall.DT_FIFO
+	} else if inode.inodeType == FileObject {
 		dirEntType = syscall.DT_REG
 	} else {
 		dirEntType = syscall.DT_DIR
@@ -153,47 +295,89 @@ func bpTreeDirEntType(inodeType uint32) uint32 {
 	return syscall.DT_DIR
 }
 
+// `entryAndAttrValid` returns the EntryValid and AttrValid durations, in the form used by
+// package fission, to report for inodes of backend (or, if nil, the FUSE root directory).
+func entryAndAttrValid(backend *backendStruct) (entryValidSec uint64, entryValidNSec uint32, attrValidSec uint64, attrValidNSec uint32) {
+	if backend == nil {
+		entryValidSec, entryValidNSec = timeDurationToAttrDuration(globals.config.entryTTL)
+		attrValidSec, attrValidNSec = timeDurationToAttrDuration(globals.config.attrTTL)
+	} else {
+		entryValidSec, entryValidNSec = timeDurationToAttrDuration(backend.entryTTL)
+		attrValidSec, attrValidNSec = timeDurationToAttrDuration(backend.attrTTL)
+	}
+
+	return
+}
+
+// `negativeLookupOut` is called while globals.Lock() is held when a lookup in parentInode finds
+// nothing. As each such miss in a backend emulating symlinks costs an additional HEAD (of the name
+// with symlink_suffix appended), a LookupOut with a zero NodeID is returned that the kernel caches
+// as a negative entry for entry_ttl rather than repeat both (e.g. as names are searched for along
+// a path). Otherwise, nil is returned and the lookup should simply fail with ENOENT.
+func negativeLookupOut(parentInode *inodeStruct) (lookupOut *fission.LookupOut) {
+	var (
+		backend        *backendStruct
+		entryValidNSec uint32
+		entryValidSec  uint64
+		ok             bool
+	)
+
+	backend, ok = globals.backendMap[parentInode.backendNonce]
+	if !ok || (backend.symLinkSuffix == "") {
+		return
+	}
+
+	entryValidSec, entryValidNSec, _, _ = entryAndAttrValid(backend)
+	if (entryValidSec == 0) && (entryValidNSec == 0) {
+		return
+	}
+
+	lookupOut = &fission.LookupOut{
+		EntryOut: fission.EntryOut{
+			NodeID:         0,
+			EntryValidSec:  entryValidSec,
+			EntryValidNSec: entryValidNSec,
+		},
+	}
+
+	return
+}
+
 // `DoLookup` implements the package fission callback to fetch metadata
 // information about a directory entry (if present).
 func (*globalsStruct) DoLookup(inHeader *fission.InHeader, lookupIn *fission.LookupIn) (lookupOut *fission.LookupOut, errno syscall.Errno) {
 	var (
-		backend            *backendStruct
-		childInode         *inodeStruct
-		childDirInfo       DirEntryInfo
-		entryAttrValidNSec uint32
-		entryAttrValidSec  uint64
-		latency            float64
-		mTimeNSec          uint32
-		mTimeSec           uint64
-		ok                 bool
-		parentInode        *inodeStruct
-		startTime          = time.Now()
+		backend      *backendStruct
+		childInode   *inodeStruct
+		childDirInfo DirEntryInfo
+		inFlightOp   = beginInFlightOp("Lookup", inHeader)
+		ok           bool
+		parentInode  *inodeStruct
+		startTime    = time.Now()
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
-		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:175:3:funcLit@173")
-		if errno == 0 {
-			globals.fissionMetrics.LookupSuccesses.Inc()
-			globals.fissionMetrics.LookupSuccessLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.LookupSuccesses.Inc()
-				backend.fissionMetrics.LookupSuccessLatencies.Observe(latency)
-			}
-		} else {
-			globals.fissionMetrics.LookupFailures.Inc()
-			globals.fissionMetrics.LookupFailureLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.LookupFailures.Inc()
-				backend.fissionMetrics.LookupFailureLatencies.Observe(latency)
-			}
-		}
-		globalsUnlock()
+		observeFissionOp(backend, errno, startTime, func(fissionMetrics *fissionMetricsStruct) fissionOpMetricsStruct {
+			return fissionOpMetricsStruct{fissionMetrics.LookupSuccesses, fissionMetrics.LookupFailures, fissionMetrics.LookupSuccessLatencies, fissionMetrics.LookupFailureLatencies}
+		})
 	}()
 
-	globalsLock("fission.go:194:2:(*globalsStruct).DoLookup")
+	lookupOut, backend, errno, ok = lookupKnownChild(inHeader, string(lookupIn.Name))
+	if ok {
+		return
+	}
+
+	globalsLock("fission.go:372:2:(*globalsStruct).DoLookup")
 
 	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok && (string(lookupIn.Name) == DotDirEntryBasename) {
+		// The kernel resolves a file handle (e.g. of an NFS re-export) whose inode it no longer
+		// caches by looking up "." in it...so attempt to re-materialize the evicted inode
+
+		parentInode, ok = rematerializeInode(inFlightOp.ctx, inHeader.NodeID)
+	}
 	if !ok {
 		// We no longer know how to map inHeader.NodeID (an inodeNumber) to the parentInode
 		backend = nil
@@ -212,14 +396,16 @@ func (*globalsStruct) DoLookup(inHeader *fission.InHeader, lookupIn *fission.Loo
 		}
 	}
 
-	if parentInode.inodeType == FileObject {
+	if (string(lookupIn.Name) == DotDirEntryBasename) && (parentInode.inodeType != FUSERootDir) {
+		// Looking up "." (of any inodeType) simply returns parentInode itself
+
+		childInode = parentInode
+	} else if parentInode.inodeType == FileObject {
 		// The parentInode must be a directory of some sort... not a FileObject
 		globalsUnlock()
 		errno = syscall.ENOTDIR
 		return
-	}
-
-	if parentInode.inodeType == FUSERootDir {
+	} else if parentInode.inodeType == FUSERootDir {
 		// If lookupIn.Name exists, it is in parentInode's portion of the global {phys|virt}ChildDirEntryMap
 
 		childDirInfo, ok = globals.physChildDirEntryMap.getByBasename(parentInode.inodeNumber, string(lookupIn.Name))
@@ -240,10 +426,15 @@ func (*globalsStruct) DoLookup(inHeader *fission.InHeader, lookupIn *fission.Loo
 	} else {
 		// We only know parentInode is a BackendRootDir or a PseudoDir
 
-		childInode, ok = parentInode.findChildInode(string(lookupIn.Name))
+		childInode, ok = parentInode.findChildInode(inFlightOp.ctx, string(lookupIn.Name))
 		if !ok || childInode.pendingDelete {
+			lookupOut = negativeLookupOut(parentInode)
 			globalsUnlock()
-			errno = syscall.ENOENT
+			if lookupOut == nil {
+				errno = syscall.ENOENT
+			} else {
+				errno = 0
+			}
 			return
 		}
 	}
@@ -254,39 +445,117 @@ func (*globalsStruct) DoLookup(inHeader *fission.InHeader, lookupIn *fission.Loo
 		globals.logger.Fatalf("[FATAL] globals.backendMap[childInode.backendNonce]")
 	}
 
-	entryAttrValidSec, entryAttrValidNSec = timeDurationToAttrDuration(globals.config.entryAttrTTL)
-	mTimeSec, mTimeNSec = timeTimeToAttrTime(childInode.mTime)
+	if !backend.acl.permits(inHeader.UID, inHeader.GID) {
+		globalsUnlock()
+		errno = syscall.EACCES
+		return
+	}
+
+	lookupOut = childInode.lookupOut(backend)
+
+	globalsUnlock()
+
+	errno = 0
+	return
+}
+
+// `lookupOut` returns the fission.LookupOut reporting childInode (of backend) to DoLookup().
+func (childInode *inodeStruct) lookupOut(backend *backendStruct) (lookupOut *fission.LookupOut) {
+	var (
+		attrValidNSec  uint32
+		attrValidSec   uint64
+		entryValidNSec uint32
+		entryValidSec  uint64
+	)
+
+	entryValidSec, entryValidNSec, attrValidSec, attrValidNSec = entryAndAttrValid(backend)
 
 	lookupOut = &fission.LookupOut{
 		EntryOut: fission.EntryOut{
 			NodeID:         childInode.inodeNumber,
-			Generation:     0,
-			EntryValidSec:  entryAttrValidSec,
-			AttrValidSec:   entryAttrValidSec,
-			EntryValidNSec: entryAttrValidNSec,
-			AttrValidNSec:  entryAttrValidNSec,
+			Generation:     childInode.generation,
+			EntryValidSec:  entryValidSec,
+			AttrValidSec:   attrValidSec,
+			EntryValidNSec: entryValidNSec,
+			AttrValidNSec:  attrValidNSec,
 			Attr: fission.Attr{
-				Ino:       childInode.inodeNumber,
-				Size:      childInode.sizeInMemory,
-				ATimeSec:  mTimeSec,
-				MTimeSec:  mTimeSec,
-				CTimeSec:  mTimeSec,
-				ATimeNSec: mTimeNSec,
-				MTimeNSec: mTimeNSec,
-				CTimeNSec: mTimeNSec,
-				Mode:      childInode.mode,
-				UID:       uint32(backend.uid),
-				GID:       uint32(backend.gid),
-				RDev:      0,
-				Padding:   0,
+				Ino:     childInode.inodeNumber,
+				Size:    childInode.sizeInMemory,
+				Mode:    childInode.mode,
+				UID:     uint32(backend.uid),
+				GID:     uint32(backend.gid),
+				RDev:    0,
+				Padding: 0,
 			},
 		},
 	}
 	fixAttrSizes(&lookupOut.Attr)
+	fixAttrTimes(&lookupOut.Attr, childInode)
 
-	globalsUnlock()
+	return
+}
 
-	errno = 0
+// `lookupKnownChild` is called by DoLookup() to attempt, while holding globals only shared
+// (see globalsRLock()), to resolve name in the directory inode inHeader.NodeID from what is
+// already known: either "." or a child already in globals.physChildDirEntryMap. The touches
+// of the parent and child inodes are deferred (see shardedInodeMap.deferTouch()). If anything
+// more is required (e.g. re-materializing the parent, consulting globals.virtChildDirEntryMap
+// or the backend, or reporting a pending delete or error other than EACCES), ok == false and
+// DoLookup() takes its exclusive path.
+func lookupKnownChild(inHeader *fission.InHeader, name string) (lookupOut *fission.LookupOut, backend *backendStruct, errno syscall.Errno, ok bool) {
+	var (
+		childDirInfo DirEntryInfo
+		childInode   *inodeStruct
+		parentInode  *inodeStruct
+	)
+
+	globalsRLock()
+
+	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok || (parentInode.inodeType == FileObject) {
+		globalsRUnlock()
+		ok = false
+		return
+	}
+
+	if (name == DotDirEntryBasename) && (parentInode.inodeType != FUSERootDir) {
+		childInode = parentInode
+	} else {
+		childDirInfo, ok = globals.physChildDirEntryMap.getByBasename(parentInode.inodeNumber, name)
+		if !ok {
+			globalsRUnlock()
+			return
+		}
+
+		childInode, ok = globals.inodeMap.get(childDirInfo.InodeNumber)
+		if !ok || childInode.pendingDelete {
+			globalsRUnlock()
+			ok = false
+			return
+		}
+
+		if parentInode.inodeType != FUSERootDir {
+			globals.inodeMap.deferTouch(parentInode.inodeNumber, time.Time{})
+			globals.inodeMap.deferTouch(childInode.inodeNumber, time.Time{})
+		}
+	}
+
+	backend, ok = globals.backendMap[childInode.backendNonce]
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.backendMap[childInode.backendNonce]")
+	}
+
+	if backend.acl.permits(inHeader.UID, inHeader.GID) {
+		lookupOut = childInode.lookupOut(backend)
+		errno = 0
+	} else {
+		errno = syscall.EACCES
+	}
+
+	globalsRUnlock()
+
+	ok = true
 	return
 }
 
@@ -295,49 +564,39 @@ func (*globalsStruct) DoLookup(inHeader *fission.InHeader, lookupIn *fission.Loo
 func (*globalsStruct) DoForget(inHeader *fission.InHeader, forgetIn *fission.ForgetIn) {}
 
 // `DoGetAttr` implements the package fission callback to fetch metadata
-// information about an inode.
+// information about an inode. As this only reads globals, it holds globals
+// only shared and defers its touch of the inode (see globalsRLock()).
 func (*globalsStruct) DoGetAttr(inHeader *fission.InHeader, getAttrIn *fission.GetAttrIn) (getAttrOut *fission.GetAttrOut, errno syscall.Errno) {
 	var (
 		attrValidNSec uint32
 		attrValidSec  uint64
 		backend       *backendStruct
 		gid           uint32
-		latency       float64
-		mTimeNSec     uint32
-		mTimeSec      uint64
 		ok            bool
 		thisInode     *inodeStruct
 		startTime     = time.Now()
 		uid           uint32
 	)
 
+	defer endInFlightOp(beginInFlightOp("GetAttr", inHeader), &errno)
+
 	defer func() {
-		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:316:3:funcLit@314")
-		if errno == 0 {
-			globals.fissionMetrics.GetAttrSuccesses.Inc()
-			globals.fissionMetrics.GetAttrSuccessLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.GetAttrSuccesses.Inc()
-				backend.fissionMetrics.GetAttrSuccessLatencies.Observe(latency)
-			}
-		} else {
-			globals.fissionMetrics.GetAttrFailures.Inc()
-			globals.fissionMetrics.GetAttrFailureLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.GetAttrFailures.Inc()
-				backend.fissionMetrics.GetAttrFailureLatencies.Observe(latency)
-			}
-		}
-		globalsUnlock()
+		observeFissionOp(backend, errno, startTime, func(fissionMetrics *fissionMetricsStruct) fissionOpMetricsStruct {
+			return fissionOpMetricsStruct{fissionMetrics.GetAttrSuccesses, fissionMetrics.GetAttrFailures, fissionMetrics.GetAttrSuccessLatencies, fissionMetrics.GetAttrFailureLatencies}
+		})
 	}()
 
-	globalsLock("fission.go:335:2:(*globalsStruct).DoGetAttr")
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsRLock()
 
 	thisInode, ok = globals.inodeMap.get(inHeader.NodeID)
 	if !ok {
 		backend = nil
-		globalsUnlock()
+		globalsRUnlock()
 		errno = syscall.ENOENT
 		return
 	}
@@ -353,12 +612,12 @@ func (*globalsStruct) DoGetAttr(inHeader *fission.InHeader, getAttrIn *fission.G
 	}
 
 	if thisInode.pendingDelete {
-		globalsUnlock()
+		globalsRUnlock()
 		errno = syscall.ENOENT
 		return
 	}
 
-	thisInode.touch(nil)
+	globals.inodeMap.deferTouch(thisInode.inodeNumber, time.Time{})
 
 	switch thisInode.inodeType {
 	case FileObject:
@@ -378,32 +637,26 @@ func (*globalsStruct) DoGetAttr(inHeader *fission.InHeader, getAttrIn *fission.G
 		globals.logger.Fatalf("[FATAL] unrecognized inodeType (%v)", thisInode.inodeType)
 	}
 
-	attrValidSec, attrValidNSec = timeDurationToAttrDuration(globals.config.entryAttrTTL)
-	mTimeSec, mTimeNSec = timeTimeToAttrTime(thisInode.mTime)
+	_, _, attrValidSec, attrValidNSec = entryAndAttrValid(backend)
 
 	getAttrOut = &fission.GetAttrOut{
 		AttrValidSec:  attrValidSec,
 		AttrValidNSec: attrValidNSec,
 		Dummy:         0,
 		Attr: fission.Attr{
-			Ino:       thisInode.inodeNumber,
-			Size:      thisInode.sizeInMemory,
-			ATimeSec:  mTimeSec,
-			MTimeSec:  mTimeSec,
-			CTimeSec:  mTimeSec,
-			ATimeNSec: mTimeNSec,
-			MTimeNSec: mTimeNSec,
-			CTimeNSec: mTimeNSec,
-			Mode:      thisInode.mode,
-			UID:       uid,
-			GID:       gid,
-			RDev:      0,
-			Padding:   0,
+			Ino:     thisInode.inodeNumber,
+			Size:    thisInode.sizeInMemory,
+			Mode:    thisInode.mode,
+			UID:     uid,
+			GID:     gid,
+			RDev:    0,
+			Padding: 0,
 		},
 	}
 	fixAttrSizes(&getAttrOut.Attr)
+	fixAttrTimes(&getAttrOut.Attr, thisInode)
 
-	globalsUnlock()
+	globalsRUnlock()
 
 	errno = 0
 	return
@@ -411,197 +664,166 @@ func (*globalsStruct) DoGetAttr(inHeader *fission.InHeader, getAttrIn *fission.G
 
 // `DoSetAttr` implements the package fission callback to set attributes of an inode.
 func (*globalsStruct) DoSetAttr(inHeader *fission.InHeader, setAttrIn *fission.SetAttrIn) (setAttrOut *fission.SetAttrOut, errno syscall.Errno) {
-	fmt.Println("[TODO] fission.go::DoSetAttr()")
-	errno = syscall.ENOSYS
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	errno = readOnlyErrno(inHeader.NodeID, syscall.ENOSYS)
+	if errno == syscall.ENOSYS {
+		fmt.Println("[TODO] fission.go::DoSetAttr()")
+	}
 	return
 }
 
 // `DoReadLink` implements the package fission callback to read the target
-// of a symlink inode (not supported)
+// of a symlink inode. Symlinks are emulated by objects whose basename ends
+// with the backend's symlink_suffix and whose content is the target.
 func (*globalsStruct) DoReadLink(inHeader *fission.InHeader) (readLinkOut *fission.ReadLinkOut, errno syscall.Errno) {
-	errno = syscall.ENOSYS
-	return
-}
-
-// `DoSymLink` implements the package fission callback to create a symlink inode (not supported)
-func (*globalsStruct) DoSymLink(inHeader *fission.InHeader, symLinkIn *fission.SymLinkIn) (symLinkOut *fission.SymLinkOut, errno syscall.Errno) {
-	errno = syscall.ENOSYS
-	return
-}
-
-// `DoMkNod` implements the package fission callback to create a file inode.
-func (*globalsStruct) DoMkNod(inHeader *fission.InHeader, mkNodIn *fission.MkNodIn) (mkNodOut *fission.MkNodOut, errno syscall.Errno) {
-	errno = syscall.ENOSYS
-	return
-}
-
-// `DoMkDir` implements the package fission callback to create a directory inode.
-func (*globalsStruct) DoMkDir(inHeader *fission.InHeader, mkDirIn *fission.MkDirIn) (mkDirOut *fission.MkDirOut, errno syscall.Errno) {
 	var (
-		backend            *backendStruct
-		basename           = string(mkDirIn.Name)
-		childInode         *inodeStruct
-		entryAttrValidNSec uint32
-		entryAttrValidSec  uint64
-		latency            float64
-		mTimeNSec          uint32
-		mTimeSec           uint64
-		ok                 bool
-		parentInode        *inodeStruct
-		startTime          = time.Now()
+		backend        *backendStruct
+		err            error
+		inFlightOp     = beginInFlightOp("ReadLink", inHeader)
+		inode          *inodeStruct
+		latency        float64
+		ok             bool
+		readFileInput  *readFileInputStruct
+		readFileOutput *readFileOutputStruct
+		startTime      = time.Now()
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
 		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:456:3:funcLit@454")
+		globalsLock("fission.go:699:3:funcLit@697")
 		if errno == 0 {
-			globals.fissionMetrics.MkDirSuccesses.Inc()
-			globals.fissionMetrics.MkDirSuccessLatencies.Observe(latency)
+			globals.fissionMetrics.ReadLinkSuccesses.Inc()
+			globals.fissionMetrics.ReadLinkSuccessLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.MkDirSuccesses.Inc()
-				backend.fissionMetrics.MkDirSuccessLatencies.Observe(latency)
+				backend.fissionMetrics.ReadLinkSuccesses.Inc()
+				backend.fissionMetrics.ReadLinkSuccessLatencies.Observe(latency)
 			}
 		} else {
-			globals.fissionMetrics.MkDirFailures.Inc()
-			globals.fissionMetrics.MkDirFailureLatencies.Observe(latency)
+			globals.fissionMetrics.ReadLinkFailures.Inc()
+			globals.fissionMetrics.ReadLinkFailureLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.MkDirFailures.Inc()
-				backend.fissionMetrics.MkDirFailureLatencies.Observe(latency)
+				backend.fissionMetrics.ReadLinkFailures.Inc()
+				backend.fissionMetrics.ReadLinkFailureLatencies.Observe(latency)
 			}
 		}
 		globalsUnlock()
 	}()
 
-	globalsLock("fission.go:475:2:(*globalsStruct).DoMkDir")
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
 
-	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
+	globalsLock("fission.go:723:2:(*globalsStruct).DoReadLink")
+
+	inode, ok = globals.inodeMap.get(inHeader.NodeID)
 	if !ok {
-		// We no longer know how to map inHeader.NodeID (an inodeNumber) to the parentInode
-		backend = nil
 		globalsUnlock()
 		errno = syscall.ENOENT
 		return
 	}
 
-	if parentInode.backendNonce == 0 {
-		backend = nil
-	} else {
-		backend, ok = globals.backendMap[parentInode.backendNonce]
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.backendMap[parentInode.backendNonce]")
-		}
-	}
-
-	if parentInode.inodeType == FileObject {
-		// The parentInode must be a directory of some sort... not a FileObject
+	if !inode.isSymLink() {
 		globalsUnlock()
-		errno = syscall.ENOTDIR
+		errno = syscall.EINVAL
 		return
 	}
-	if parentInode.inodeType == FUSERootDir {
-		// Never allowed in FUSERootDir
-		globalsUnlock()
-		errno = syscall.EPERM
-		return
+
+	backend, ok = globals.backendMap[inode.backendNonce]
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce] returned !ok")
 	}
-	if backend.readOnly {
-		// Never allowed in a readOnly backend
-		globalsUnlock()
-		errno = syscall.EPERM
-		return
+
+	inode.touch(nil)
+
+	readFileInput = &readFileInputStruct{
+		filePath: inode.objectPath,
+		offset:   0,
+		length:   globals.config.cacheLineSize,
+		ifMatch:  inode.eTag,
 	}
 
-	_, ok = parentInode.findChildInode(basename)
-	if ok {
-		// We just return EEXIST if we find a phys or virt child dir entry (whether or not it is a dir or a file)
-		globalsUnlock()
-		errno = syscall.EEXIST
+	globalsUnlock()
+
+	readFileOutput, err = readFileWrapper(inFlightOp.ctx, backend.context, readFileInput)
+	if err != nil {
+		errno = syscall.EIO
 		return
 	}
 
-	// From here, we know we will succeed
-
-	childInode = parentInode.createPseudoDirInode(true, basename)
-
-	entryAttrValidSec, entryAttrValidNSec = timeDurationToAttrDuration(globals.config.entryAttrTTL)
-	mTimeSec, mTimeNSec = timeTimeToAttrTime(childInode.mTime)
-
-	mkDirOut = &fission.MkDirOut{
-		EntryOut: fission.EntryOut{
-			NodeID:         childInode.inodeNumber,
-			Generation:     0,
-			EntryValidSec:  entryAttrValidSec,
-			AttrValidSec:   entryAttrValidSec,
-			EntryValidNSec: entryAttrValidNSec,
-			AttrValidNSec:  entryAttrValidNSec,
-			Attr: fission.Attr{
-				Ino:       childInode.inodeNumber,
-				Size:      childInode.sizeInMemory,
-				ATimeSec:  mTimeSec,
-				MTimeSec:  mTimeSec,
-				CTimeSec:  mTimeSec,
-				ATimeNSec: mTimeNSec,
-				MTimeNSec: mTimeNSec,
-				CTimeNSec: mTimeNSec,
-				Mode:      childInode.mode,
-				UID:       uint32(backend.uid),
-				GID:       uint32(backend.gid),
-				RDev:      0,
-				Padding:   0,
-			},
-		},
-	}
-	fixAttrSizes(&mkDirOut.Attr)
-
-	globalsUnlock()
+	readLinkOut = &fission.ReadLinkOut{
+		Data: readFileOutput.buf,
+	}
 
 	errno = 0
 	return
 }
 
-// `DoUnlink` implements the package fission callback to remove a directory entry of a
-// file inode that, since hardlinks are not supported, also removes the file inode itself.
-func (*globalsStruct) DoUnlink(inHeader *fission.InHeader, unlinkIn *fission.UnlinkIn) (errno syscall.Errno) {
+// `DoSymLink` implements the package fission callback to create a symlink inode. The
+// symlink is stored as an object whose basename is suffixed by the backend's symlink_suffix
+// and whose content is the target. Should symlink_suffix not be set, symlinks are not supported.
+func (*globalsStruct) DoSymLink(inHeader *fission.InHeader, symLinkIn *fission.SymLinkIn) (symLinkOut *fission.SymLinkOut, errno syscall.Errno) {
 	var (
-		backend     *backendStruct
-		basename    = string(unlinkIn.Name)
-		childInode  *inodeStruct
-		latency     float64
-		ok          bool
-		parentInode *inodeStruct
-		startTime   = time.Now()
+		attrValidNSec  uint32
+		attrValidSec   uint64
+		backend        *backendStruct
+		basename       = string(symLinkIn.Name)
+		childDirInfo   DirEntryInfo
+		childInode     *inodeStruct
+		entryValidNSec uint32
+		entryValidSec  uint64
+		err            error
+		inFlightOp     = beginInFlightOp("SymLink", inHeader)
+		latency        float64
+		ok             bool
+		parentInode    *inodeStruct
+		putFileInput   *putFileInputStruct
+		putFileOutput  *putFileOutputStruct
+		startTime      = time.Now()
 	)
 
-	// Record metrics on function exit
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
 		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:579:3:funcLit@577")
+		globalsLock("fission.go:796:3:funcLit@794")
 		if errno == 0 {
-			globals.fissionMetrics.UnlinkSuccesses.Inc()
-			globals.fissionMetrics.UnlinkSuccessLatencies.Observe(latency)
+			globals.fissionMetrics.SymLinkSuccesses.Inc()
+			globals.fissionMetrics.SymLinkSuccessLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.UnlinkSuccesses.Inc()
-				backend.fissionMetrics.UnlinkSuccessLatencies.Observe(latency)
+				backend.fissionMetrics.SymLinkSuccesses.Inc()
+				backend.fissionMetrics.SymLinkSuccessLatencies.Observe(latency)
 			}
 		} else {
-			globals.fissionMetrics.UnlinkFailures.Inc()
-			globals.fissionMetrics.UnlinkFailureLatencies.Observe(latency)
+			globals.fissionMetrics.SymLinkFailures.Inc()
+			globals.fissionMetrics.SymLinkFailureLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.UnlinkFailures.Inc()
-				backend.fissionMetrics.UnlinkFailureLatencies.Observe(latency)
+				backend.fissionMetrics.SymLinkFailures.Inc()
+				backend.fissionMetrics.SymLinkFailureLatencies.Observe(latency)
 			}
 		}
 		globalsUnlock()
 	}()
 
-	globalsLock("fission.go:598:2:(*globalsStruct).DoUnlink")
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsLock("fission.go:820:2:(*globalsStruct).DoSymLink")
 
 	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
 	if !ok {
 		backend = nil
 		globalsUnlock()
-		errno = syscall.ENOTDIR
+		errno = syscall.ENOENT
 		return
 	}
 
@@ -615,107 +837,181 @@ func (*globalsStruct) DoUnlink(inHeader *fission.InHeader, unlinkIn *fission.Unl
 		}
 	}
 
-	parentInode.touch(nil)
-
-	if parentInode.inodeType == FUSERootDir {
+	if parentInode.inodeType == FileObject {
 		globalsUnlock()
-		errno = syscall.EPERM
+		errno = syscall.ENOTDIR
 		return
 	}
-	if parentInode.inodeType == FileObject {
+	if parentInode.inodeType == FUSERootDir {
 		globalsUnlock()
-		errno = syscall.ENOTDIR
+		errno = fuseRootDirWriteErrno()
 		return
 	}
 	if backend.readOnly {
 		globalsUnlock()
-		errno = syscall.EPERM
+		errno = syscall.EROFS
+		return
+	}
+	if backend.symLinkSuffix == "" {
+		globalsUnlock()
+		errno = syscall.ENOSYS
 		return
 	}
 
-	childInode, ok = parentInode.findChildInode(basename)
-	if !ok {
+	_, ok = parentInode.findChildInode(inFlightOp.ctx, basename)
+	if ok {
 		globalsUnlock()
-		errno = syscall.ENOENT
+		errno = syscall.EEXIST
 		return
 	}
 
-	if childInode.pendingDelete {
+	putFileInput = &putFileInputStruct{
+		buf: symLinkIn.Data,
+	}
+	if parentInode.objectPath == "" {
+		putFileInput.filePath = basename + backend.symLinkSuffix
+	} else {
+		putFileInput.filePath = parentInode.objectPath + basename + backend.symLinkSuffix
+	}
+
+	globalsUnlock()
+
+	putFileOutput, err = putFileWrapper(inFlightOp.ctx, backend.context, putFileInput)
+	if err != nil {
+		globals.logger.Printf("[WARN] DoSymLink() got putFileWrapper(backend.context, \"%s\") err: %v", putFileInput.filePath, err)
+		errno = syscall.EIO
+		return
+	}
+
+	globalsLock("fission.go:886:2:(*globalsStruct).DoSymLink")
+
+	// Re-fetch parentInode as it may have been paged out (or even evicted) while unlocked
+
+	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok {
 		globalsUnlock()
 		errno = syscall.ENOENT
 		return
 	}
-	if childInode.inodeType != FileObject {
-		childInode.touch(nil)
+
+	// Only consult what is already known (a findChildInode() would now find the object just put)
+
+	_, ok = globals.physChildDirEntryMap.getByBasename(parentInode.inodeNumber, basename)
+	if !ok {
+		_, ok = globals.virtChildDirEntryMap.getByBasename(parentInode.inodeNumber, basename)
+	}
+	if ok {
 		globalsUnlock()
-		errno = syscall.EISDIR
+		errno = syscall.EEXIST
 		return
 	}
 
-	// One way or another, childInode will be deleted
+	childDirInfo, ok = globals.physChildDirEntryMap.getByBasename(parentInode.inodeNumber, basename+backend.symLinkSuffix)
+	if ok {
+		// A concurrent lookup has already found the object just put
 
-	childInode.pendingDelete = true
-	childInode.touch(nil)
+		childInode, ok = globals.inodeMap.get(childDirInfo.InodeNumber)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childDirInfo.InodeNumber) returned !ok [DoSymLink()]")
+		}
+	} else {
+		parentInode.convertToPhysDirPathIfNecessary()
 
-	if len(childInode.fhSet) != 0 {
-		// Since fhSet is not empty, cannot delete childInode
+		childInode = parentInode.createFileObjectInode(false, basename+backend.symLinkSuffix, uint64(len(symLinkIn.Data)), putFileOutput.eTag, time.Now())
+	}
 
-		globalsUnlock()
+	entryValidSec, entryValidNSec, attrValidSec, attrValidNSec = entryAndAttrValid(backend)
 
-		errno = 0
-		return
+	symLinkOut = &fission.SymLinkOut{
+		EntryOut: fission.EntryOut{
+			NodeID:         childInode.inodeNumber,
+			Generation:     childInode.generation,
+			EntryValidSec:  entryValidSec,
+			AttrValidSec:   attrValidSec,
+			EntryValidNSec: entryValidNSec,
+			AttrValidNSec:  attrValidNSec,
+			Attr: fission.Attr{
+				Ino:     childInode.inodeNumber,
+				Size:    childInode.sizeInMemory,
+				Mode:    childInode.mode,
+				UID:     uint32(backend.uid),
+				GID:     uint32(backend.gid),
+				RDev:    0,
+				Padding: 0,
+			},
+		},
 	}
+	fixAttrSizes(&symLinkOut.Attr)
+	fixAttrTimes(&symLinkOut.Attr, childInode)
 
 	globalsUnlock()
 
-	childInode.finishPendingDelete()
-
 	errno = 0
 	return
 }
 
-// `DoRmDir` implements the package fission callback to remove a directory inode.
-func (*globalsStruct) DoRmDir(inHeader *fission.InHeader, rmDirIn *fission.RmDirIn) (errno syscall.Errno) {
+// `DoMkNod` implements the package fission callback to create a file inode. Regular
+// files are not created via this path. Special files have no representation in an
+// object store so are rejected (with a rate-limited log explaining why) unless the
+// backend is configured to emulate FIFOs in which case a "virt" (i.e. in-memory only)
+// FIFO inode is created. Note that the kernel, not this daemon, implements the pipe
+// semantics of any such FIFO once it is opened.
+func (*globalsStruct) DoMkNod(inHeader *fission.InHeader, mkNodIn *fission.MkNodIn) (mkNodOut *fission.MkNodOut, errno syscall.Errno) {
 	var (
-		backend                             *backendStruct
-		basename                            = string(rmDirIn.Name)
-		childInode                          *inodeStruct
-		childInodePhysChildDirEntryMapLimit uint64
-		childInodePhysChildDirEntryMapStart uint64
-		childInodeVirtChildDirEntryMapLimit uint64
-		childInodeVirtChildDirEntryMapStart uint64
-		latency                             float64
-		ok                                  bool
-		parentInode                         *inodeStruct
-		startTime                           = time.Now()
+		attrValidNSec  uint32
+		attrValidSec   uint64
+		backend        *backendStruct
+		basename       = string(mkNodIn.Name)
+		childInode     *inodeStruct
+		entryValidNSec uint32
+		entryValidSec  uint64
+		fileType       = mkNodIn.Mode & syscall.S_IFMT
+		inFlightOp     = beginInFlightOp("MkNod", inHeader)
+		latency        float64
+		ok             bool
+		parentInode    *inodeStruct
+		startTime      = time.Now()
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
 		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:695:3:funcLit@693")
+		globalsLock("fission.go:981:3:funcLit@979")
 		if errno == 0 {
-			globals.fissionMetrics.RmDirSuccesses.Inc()
-			globals.fissionMetrics.RmDirSuccessLatencies.Observe(latency)
+			globals.fissionMetrics.MkNodSuccesses.Inc()
+			globals.fissionMetrics.MkNodSuccessLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.RmDirSuccesses.Inc()
-				backend.fissionMetrics.RmDirSuccessLatencies.Observe(latency)
+				backend.fissionMetrics.MkNodSuccesses.Inc()
+				backend.fissionMetrics.MkNodSuccessLatencies.Observe(latency)
 			}
 		} else {
-			globals.fissionMetrics.RmDirFailures.Inc()
-			globals.fissionMetrics.RmDirFailureLatencies.Observe(latency)
+			globals.fissionMetrics.MkNodFailures.Inc()
+			globals.fissionMetrics.MkNodFailureLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.RmDirFailures.Inc()
-				backend.fissionMetrics.RmDirFailureLatencies.Observe(latency)
+				backend.fissionMetrics.MkNodFailures.Inc()
+				backend.fissionMetrics.MkNodFailureLatencies.Observe(latency)
 			}
 		}
 		globalsUnlock()
 	}()
 
-	globalsLock("fission.go:714:2:(*globalsStruct).DoRmDir")
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	if (fileType == 0) || (fileType == syscall.S_IFREG) {
+		backend = nil
+		errno = syscall.ENOSYS
+		return
+	}
+
+	globalsLock("fission.go:1011:2:(*globalsStruct).DoMkNod")
 
 	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
 	if !ok {
-		// We no longer know how to map inHeader.NodeID (an inodeNumber) to the parentInode
 		backend = nil
 		globalsUnlock()
 		errno = syscall.ENOENT
@@ -733,94 +1029,78 @@ func (*globalsStruct) DoRmDir(inHeader *fission.InHeader, rmDirIn *fission.RmDir
 	}
 
 	if parentInode.inodeType == FileObject {
-		// The parentInode must be a directory of some sort... not a FileObject
 		globalsUnlock()
 		errno = syscall.ENOTDIR
 		return
 	}
 	if parentInode.inodeType == FUSERootDir {
-		// Never allowed in FUSERootDir
 		globalsUnlock()
-		errno = syscall.EPERM
+		errno = fuseRootDirWriteErrno()
 		return
 	}
 	if backend.readOnly {
-		// Never allowed in a readOnly backend
 		globalsUnlock()
-		errno = syscall.EPERM
+		errno = syscall.EROFS
 		return
 	}
 
-	childInode, ok = parentInode.findChildInode(basename)
-	if !ok {
-		// We didn't find the child directory, so just return ENOENT
+	switch fileType {
+	case syscall.S_IFIFO:
+		if !backend.emulateFIFOs {
+			logMkNodUnsupported(backend, basename, "FIFOs are unsupported on object store backends unless emulate_fifos is enabled")
+			globalsUnlock()
+			errno = syscall.EOPNOTSUPP
+			return
+		}
+	case syscall.S_IFSOCK:
+		logMkNodUnsupported(backend, basename, "sockets are unsupported on object store backends")
 		globalsUnlock()
-		errno = syscall.ENOENT
+		errno = syscall.EOPNOTSUPP
 		return
-	}
-	if childInode.inodeType != PseudoDir {
-		// This child exists but is not a directory, so we return ENOTDIR
+	case syscall.S_IFCHR, syscall.S_IFBLK:
+		logMkNodUnsupported(backend, basename, "device special files are unsupported on object store backends")
 		globalsUnlock()
-		errno = syscall.ENOTDIR
+		errno = syscall.EPERM
 		return
-	}
-	if len(childInode.fhSet) > 0 {
-		// We return EBUSY if the directory is currently "open"
+	default:
 		globalsUnlock()
-		errno = syscall.EBUSY
+		errno = syscall.EINVAL
 		return
 	}
-	childInodePhysChildDirEntryMapStart, childInodePhysChildDirEntryMapLimit = globals.physChildDirEntryMap.getIndexRange(childInode.inodeNumber)
-	childInodeVirtChildDirEntryMapStart, childInodeVirtChildDirEntryMapLimit = globals.virtChildDirEntryMap.getIndexRange(childInode.inodeNumber)
-	if ((childInodePhysChildDirEntryMapLimit - childInodePhysChildDirEntryMapStart) > 0) || ((childInodeVirtChildDirEntryMapLimit - childInodeVirtChildDirEntryMapStart) > 2) {
-		// Return ENOTEMPTY if childInode has any children
+
+	_, ok = parentInode.findChildInode(inFlightOp.ctx, basename)
+	if ok {
 		globalsUnlock()
-		errno = syscall.ENOTEMPTY
+		errno = syscall.EEXIST
 		return
 	}
 
-	// From here, we know we will succeed
-
-	if !childInode.xTime.IsZero() {
-		ok = globals.inodeEvictionQueue.remove(childInode)
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.inodeEvictionQueue.remove(childInode) returned !ok")
-		}
-	}
-
-	ok = globals.virtChildDirEntryMap.delete(childInode.inodeNumber, DotDirEntryBasename)
-	if !ok {
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(childInode.inodeNumber, DotDirEntryBasename) returned !ok")
-	}
-	ok = globals.virtChildDirEntryMap.delete(childInode.inodeNumber, DotDotDirEntryBasename)
-	if !ok {
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(childInode.inodeNumber, DotDotDirEntryBasename) returned !ok")
-	}
+	childInode = parentInode.createFileObjectInode(true, basename, 0, "", time.Now())
+	childInode.mode = syscall.S_IFIFO | (mkNodIn.Mode & ^mkNodIn.UMask & 0o777)
 
-	if childInode.isVirt {
-		ok = globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, basename)
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, basename) returned !ok")
-		}
-	} else {
-		ok = globals.physChildDirEntryMap.delete(parentInode.inodeNumber, basename)
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.delete(parentInode.inodeNumber, basename) returned !ok")
-		}
-	}
+	entryValidSec, entryValidNSec, attrValidSec, attrValidNSec = entryAndAttrValid(backend)
 
-	ok = globals.inodeMap.delete(childInode.inodeNumber)
-	if !ok {
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(childInode.inodeNumber) returned !ok")
+	mkNodOut = &fission.MkNodOut{
+		EntryOut: fission.EntryOut{
+			NodeID:         childInode.inodeNumber,
+			Generation:     childInode.generation,
+			EntryValidSec:  entryValidSec,
+			AttrValidSec:   attrValidSec,
+			EntryValidNSec: entryValidNSec,
+			AttrValidNSec:  attrValidNSec,
+			Attr: fission.Attr{
+				Ino:     childInode.inodeNumber,
+				Size:    0,
+				Mode:    childInode.mode,
+				UID:     uint32(backend.uid),
+				GID:     uint32(backend.gid),
+				RDev:    0,
+				Padding: 0,
+			},
+		},
 	}
-
-	parentInode.touch(nil)
+	fixAttrSizes(&mkNodOut.Attr)
+	fixAttrTimes(&mkNodOut.Attr, childInode)
 
 	globalsUnlock()
 
@@ -828,72 +1108,578 @@ func (*globalsStruct) DoRmDir(inHeader *fission.InHeader, rmDirIn *fission.RmDir
 	return
 }
 
-// `DoRename` implements the package fission callback to rename a directory entry (not supported).
-func (*globalsStruct) DoRename(inHeader *fission.InHeader, renameIn *fission.RenameIn) (errno syscall.Errno) {
-	errno = syscall.EXDEV
-	return
-}
+// `logMkNodUnsupported` is called while globals.Lock() is held to explain why DoMkNod()
+// rejected a special file. To avoid flooding the log with such messages from tools that
+// repeatedly attempt them, at most one is logged per mkNodUnsupportedLogInterval.
+func logMkNodUnsupported(backend *backendStruct, basename string, reason string) {
+	var (
+		timeNow = time.Now()
+	)
 
-// `DoLink` implements the package fission callback to create a hardlink to an existing file inode (not supported).
-func (*globalsStruct) DoLink(inHeader *fission.InHeader, linkIn *fission.LinkIn) (linkOut *fission.LinkOut, errno syscall.Errno) {
-	errno = syscall.ENOSYS
-	return
+	if timeNow.Sub(globals.mkNodUnsupportedLogTime) < mkNodUnsupportedLogInterval {
+		return
+	}
+
+	globals.mkNodUnsupportedLogTime = timeNow
+
+	globals.logger.Printf("[INFO] DoMkNod() of \"%s\" in backends[\"%s\"] rejected: %s (further such messages suppressed for %v)", basename, backend.dirName, reason, mkNodUnsupportedLogInterval)
 }
 
-// `DoOpen` implements the package fission callback to open an existing file inode.
-func (*globalsStruct) DoOpen(inHeader *fission.InHeader, openIn *fission.OpenIn) (openOut *fission.OpenOut, errno syscall.Errno) {
+// `DoMkDir` implements the package fission callback to create a directory inode.
+func (*globalsStruct) DoMkDir(inHeader *fission.InHeader, mkDirIn *fission.MkDirIn) (mkDirOut *fission.MkDirOut, errno syscall.Errno) {
 	var (
-		allowReads   bool
-		allowWrites  bool
-		appendWrites bool
-		backend      *backendStruct
-		fh           *fhStruct
-		fhNonce      uint64
-		inode        *inodeStruct
-		isExclusive  bool
-		latency      float64
-		ok           bool
-		startTime    = time.Now()
+		attrValidNSec  uint32
+		attrValidSec   uint64
+		backend        *backendStruct
+		basename       = string(mkDirIn.Name)
+		childInode     *inodeStruct
+		entryValidNSec uint32
+		entryValidSec  uint64
+		inFlightOp     = beginInFlightOp("MkDir", inHeader)
+		latency        float64
+		ok             bool
+		parentInode    *inodeStruct
+		startTime      = time.Now()
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
 		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:861:3:funcLit@859")
+		globalsLock("fission.go:1149:3:funcLit@1147")
 		if errno == 0 {
-			globals.fissionMetrics.OpenSuccesses.Inc()
-			globals.fissionMetrics.OpenSuccessLatencies.Observe(latency)
+			globals.fissionMetrics.MkDirSuccesses.Inc()
+			globals.fissionMetrics.MkDirSuccessLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.OpenSuccesses.Inc()
-				backend.fissionMetrics.OpenSuccessLatencies.Observe(latency)
+				backend.fissionMetrics.MkDirSuccesses.Inc()
+				backend.fissionMetrics.MkDirSuccessLatencies.Observe(latency)
 			}
 		} else {
-			globals.fissionMetrics.OpenFailures.Inc()
-			globals.fissionMetrics.OpenFailureLatencies.Observe(latency)
+			globals.fissionMetrics.MkDirFailures.Inc()
+			globals.fissionMetrics.MkDirFailureLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.OpenFailures.Inc()
-				backend.fissionMetrics.OpenFailureLatencies.Observe(latency)
+				backend.fissionMetrics.MkDirFailures.Inc()
+				backend.fissionMetrics.MkDirFailureLatencies.Observe(latency)
 			}
 		}
 		globalsUnlock()
 	}()
 
-	globalsLock("fission.go:880:2:(*globalsStruct).DoOpen")
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
 
-	inode, ok = globals.inodeMap.get(inHeader.NodeID)
+	globalsLock("fission.go:1173:2:(*globalsStruct).DoMkDir")
+
+	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
 	if !ok {
+		// We no longer know how to map inHeader.NodeID (an inodeNumber) to the parentInode
 		backend = nil
 		globalsUnlock()
 		errno = syscall.ENOENT
 		return
 	}
 
-	if inode.backendNonce == 0 {
+	if parentInode.backendNonce == 0 {
 		backend = nil
 	} else {
-		backend, ok = globals.backendMap[inode.backendNonce]
+		backend, ok = globals.backendMap[parentInode.backendNonce]
 		if !ok {
 			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce]")
+			globals.logger.Fatalf("[FATAL] globals.backendMap[parentInode.backendNonce]")
+		}
+	}
+
+	if parentInode.inodeType == FileObject {
+		// The parentInode must be a directory of some sort... not a FileObject
+		globalsUnlock()
+		errno = syscall.ENOTDIR
+		return
+	}
+	if parentInode.inodeType == FUSERootDir {
+		// Only allowed in FUSERootDir (as a new prefix of the default_backend) if a default_backend is configured
+		if parentInode.inodeNumber != FUSERootDirInodeNumber {
+			// ...and never in the root directory of one of the mounts (where the new backend would not be presented)
+			errno = fuseRootDirWriteErrno()
+			globalsUnlock()
+			return
+		}
+		globalsUnlock()
+		backend, errno = mkDirDefaultBackend(inHeader, basename)
+		if errno != 0 {
+			return
+		}
+		globalsLock("fission.go:1213:3:(*globalsStruct).DoMkDir")
+		if !backend.mounted {
+			// The new backend was concurrently unmounted
+			globalsUnlock()
+			errno = syscall.ENOENT
+			return
+		}
+		childInode = backend.inode
+	} else {
+		if backend.readOnly || backend.inVersionsDir(parentInode.objectPath+basename+"/") {
+			// Never allowed in a readOnly backend (or a "<name>.versions/" directory)
+			globalsUnlock()
+			errno = syscall.EROFS
+			return
+		}
+
+		_, ok = parentInode.findChildInode(inFlightOp.ctx, basename)
+		if ok {
+			// We just return EEXIST if we find a phys or virt child dir entry (whether or not it is a dir or a file)
+			globalsUnlock()
+			errno = syscall.EEXIST
+			return
+		}
+
+		// From here, we know we will succeed
+
+		childInode = parentInode.createPseudoDirInode(true, basename)
+	}
+
+	entryValidSec, entryValidNSec, attrValidSec, attrValidNSec = entryAndAttrValid(backend)
+
+	mkDirOut = &fission.MkDirOut{
+		EntryOut: fission.EntryOut{
+			NodeID:         childInode.inodeNumber,
+			Generation:     childInode.generation,
+			EntryValidSec:  entryValidSec,
+			AttrValidSec:   attrValidSec,
+			EntryValidNSec: entryValidNSec,
+			AttrValidNSec:  attrValidNSec,
+			Attr: fission.Attr{
+				Ino:     childInode.inodeNumber,
+				Size:    childInode.sizeInMemory,
+				Mode:    childInode.mode,
+				UID:     uint32(backend.uid),
+				GID:     uint32(backend.gid),
+				RDev:    0,
+				Padding: 0,
+			},
+		},
+	}
+	fixAttrSizes(&mkDirOut.Attr)
+	fixAttrTimes(&mkDirOut.Attr, childInode)
+
+	globalsUnlock()
+
+	errno = 0
+	return
+}
+
+// `DoUnlink` implements the package fission callback to remove a directory entry of a
+// file inode that, since hardlinks are not supported, also removes the file inode itself.
+func (*globalsStruct) DoUnlink(inHeader *fission.InHeader, unlinkIn *fission.UnlinkIn) (errno syscall.Errno) {
+	var (
+		backend     *backendStruct
+		basename    = string(unlinkIn.Name)
+		childInode  *inodeStruct
+		inFlightOp  = beginInFlightOp("Unlink", inHeader)
+		latency     float64
+		ok          bool
+		parentInode *inodeStruct
+		startTime   = time.Now()
+	)
+
+	defer endInFlightOp(inFlightOp, &errno)
+
+	// Record metrics on function exit
+	defer func() {
+		latency = time.Since(startTime).Seconds()
+		globalsLock("fission.go:1291:3:funcLit@1289")
+		if errno == 0 {
+			globals.fissionMetrics.UnlinkSuccesses.Inc()
+			globals.fissionMetrics.UnlinkSuccessLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.UnlinkSuccesses.Inc()
+				backend.fissionMetrics.UnlinkSuccessLatencies.Observe(latency)
+			}
+		} else {
+			globals.fissionMetrics.UnlinkFailures.Inc()
+			globals.fissionMetrics.UnlinkFailureLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.UnlinkFailures.Inc()
+				backend.fissionMetrics.UnlinkFailureLatencies.Observe(latency)
+			}
+		}
+		globalsUnlock()
+	}()
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsLock("fission.go:1315:2:(*globalsStruct).DoUnlink")
+
+	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok {
+		backend = nil
+		globalsUnlock()
+		errno = syscall.ENOTDIR
+		return
+	}
+
+	if parentInode.backendNonce == 0 {
+		backend = nil
+	} else {
+		backend, ok = globals.backendMap[parentInode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[parentInode.backendNonce]")
+		}
+	}
+
+	parentInode.touch(nil)
+
+	if parentInode.inodeType == FUSERootDir {
+		globalsUnlock()
+		errno = fuseRootDirWriteErrno()
+		return
+	}
+	if parentInode.inodeType == FileObject {
+		globalsUnlock()
+		errno = syscall.ENOTDIR
+		return
+	}
+	if backend.readOnly {
+		globalsUnlock()
+		errno = syscall.EROFS
+		return
+	}
+
+	childInode, ok = parentInode.findChildInode(inFlightOp.ctx, basename)
+	if !ok {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if childInode.pendingDelete {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+	if childInode.inodeType != FileObject {
+		childInode.touch(nil)
+		globalsUnlock()
+		errno = syscall.EISDIR
+		return
+	}
+
+	// One way or another, childInode will be deleted
+
+	childInode.pendingDelete = true
+	childInode.touch(nil)
+
+	if len(childInode.fhSet) != 0 {
+		// Since fhSet is not empty, cannot delete childInode
+
+		globalsUnlock()
+
+		errno = 0
+		return
+	}
+
+	globalsUnlock()
+
+	childInode.finishPendingDelete(inFlightOp.ctx)
+
+	errno = 0
+	return
+}
+
+// `DoRmDir` implements the package fission callback to remove a directory inode.
+func (*globalsStruct) DoRmDir(inHeader *fission.InHeader, rmDirIn *fission.RmDirIn) (errno syscall.Errno) {
+	var (
+		backend                             *backendStruct
+		basename                            = string(rmDirIn.Name)
+		childInode                          *inodeStruct
+		childInodePhysChildDirEntryMapLimit uint64
+		childInodePhysChildDirEntryMapStart uint64
+		childInodeVirtChildDirEntryMapLimit uint64
+		childInodeVirtChildDirEntryMapStart uint64
+		inFlightOp                          = beginInFlightOp("RmDir", inHeader)
+		latency                             float64
+		ok                                  bool
+		parentInode                         *inodeStruct
+		startTime                           = time.Now()
+	)
+
+	defer endInFlightOp(inFlightOp, &errno)
+
+	defer func() {
+		latency = time.Since(startTime).Seconds()
+		globalsLock("fission.go:1415:3:funcLit@1413")
+		if errno == 0 {
+			globals.fissionMetrics.RmDirSuccesses.Inc()
+			globals.fissionMetrics.RmDirSuccessLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.RmDirSuccesses.Inc()
+				backend.fissionMetrics.RmDirSuccessLatencies.Observe(latency)
+			}
+		} else {
+			globals.fissionMetrics.RmDirFailures.Inc()
+			globals.fissionMetrics.RmDirFailureLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.RmDirFailures.Inc()
+				backend.fissionMetrics.RmDirFailureLatencies.Observe(latency)
+			}
+		}
+		globalsUnlock()
+	}()
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsLock("fission.go:1439:2:(*globalsStruct).DoRmDir")
+
+	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok {
+		// We no longer know how to map inHeader.NodeID (an inodeNumber) to the parentInode
+		backend = nil
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if parentInode.backendNonce == 0 {
+		backend = nil
+	} else {
+		backend, ok = globals.backendMap[parentInode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[parentInode.backendNonce]")
+		}
+	}
+
+	if parentInode.inodeType == FileObject {
+		// The parentInode must be a directory of some sort... not a FileObject
+		globalsUnlock()
+		errno = syscall.ENOTDIR
+		return
+	}
+	if parentInode.inodeType == FUSERootDir {
+		// Never allowed in FUSERootDir
+		globalsUnlock()
+		errno = fuseRootDirWriteErrno()
+		return
+	}
+	if backend.readOnly {
+		// Never allowed in a readOnly backend
+		globalsUnlock()
+		errno = syscall.EROFS
+		return
+	}
+
+	childInode, ok = parentInode.findChildInode(inFlightOp.ctx, basename)
+	if !ok {
+		// We didn't find the child directory, so just return ENOENT
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+	if childInode.inodeType != PseudoDir {
+		// This child exists but is not a directory, so we return ENOTDIR
+		globalsUnlock()
+		errno = syscall.ENOTDIR
+		return
+	}
+	if len(childInode.fhSet) > 0 {
+		// We return EBUSY if the directory is currently "open"
+		globalsUnlock()
+		errno = syscall.EBUSY
+		return
+	}
+	childInodePhysChildDirEntryMapStart, childInodePhysChildDirEntryMapLimit = globals.physChildDirEntryMap.getIndexRange(childInode.inodeNumber)
+	childInodeVirtChildDirEntryMapStart, childInodeVirtChildDirEntryMapLimit = globals.virtChildDirEntryMap.getIndexRange(childInode.inodeNumber)
+	if ((childInodePhysChildDirEntryMapLimit - childInodePhysChildDirEntryMapStart) > 0) || ((childInodeVirtChildDirEntryMapLimit - childInodeVirtChildDirEntryMapStart) > 2) {
+		// Return ENOTEMPTY if childInode has any children
+		globalsUnlock()
+		errno = syscall.ENOTEMPTY
+		return
+	}
+
+	// From here, we know we will succeed
+
+	if !childInode.xTime.IsZero() {
+		ok = globals.inodeEvictionQueue.remove(childInode)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.inodeEvictionQueue.remove(childInode) returned !ok")
+		}
+	}
+
+	ok = globals.virtChildDirEntryMap.delete(childInode.inodeNumber, DotDirEntryBasename)
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(childInode.inodeNumber, DotDirEntryBasename) returned !ok")
+	}
+	ok = globals.virtChildDirEntryMap.delete(childInode.inodeNumber, DotDotDirEntryBasename)
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(childInode.inodeNumber, DotDotDirEntryBasename) returned !ok")
+	}
+
+	if childInode.isVirt {
+		ok = globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, basename)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, basename) returned !ok")
+		}
+	} else {
+		ok = globals.physChildDirEntryMap.delete(parentInode.inodeNumber, basename)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.delete(parentInode.inodeNumber, basename) returned !ok")
+		}
+	}
+
+	childInode.retireInodeHandle()
+
+	ok = globals.inodeMap.delete(childInode.inodeNumber)
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(childInode.inodeNumber) returned !ok")
+	}
+
+	childInode.pruneInodeHandle()
+
+	parentInode.touch(nil)
+
+	globalsUnlock()
+
+	errno = 0
+	return
+}
+
+// `DoRename` implements the package fission callback to rename a directory entry. Only
+// FileObject inodes may be renamed (and only within a backend) as this is accomplished via
+// a server-side copy of the object followed by the deletion of the original.
+func (*globalsStruct) DoRename(inHeader *fission.InHeader, renameIn *fission.RenameIn) (errno syscall.Errno) {
+	var (
+		backend    *backendStruct
+		inFlightOp = beginInFlightOp("Rename", inHeader)
+		latency    float64
+		startTime  = time.Now()
+	)
+
+	defer endInFlightOp(inFlightOp, &errno)
+
+	// Record metrics on function exit
+	defer func() {
+		latency = time.Since(startTime).Seconds()
+		globalsLock("fission.go:1576:3:funcLit@1574")
+		if errno == 0 {
+			globals.fissionMetrics.RenameSuccesses.Inc()
+			globals.fissionMetrics.RenameSuccessLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.RenameSuccesses.Inc()
+				backend.fissionMetrics.RenameSuccessLatencies.Observe(latency)
+			}
+		} else {
+			globals.fissionMetrics.RenameFailures.Inc()
+			globals.fissionMetrics.RenameFailureLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.RenameFailures.Inc()
+				backend.fissionMetrics.RenameFailureLatencies.Observe(latency)
+			}
+		}
+		globalsUnlock()
+	}()
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	backend, errno = renameFileObject(inFlightOp.ctx, inHeader.NodeID, string(renameIn.OldName), renameIn.NewDir, string(renameIn.NewName), false)
+
+	return
+}
+
+// `DoLink` implements the package fission callback to create a hardlink to an existing file inode (not supported).
+func (*globalsStruct) DoLink(inHeader *fission.InHeader, linkIn *fission.LinkIn) (linkOut *fission.LinkOut, errno syscall.Errno) {
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	errno = readOnlyErrno(inHeader.NodeID, syscall.ENOSYS)
+	return
+}
+
+// `DoOpen` implements the package fission callback to open an existing file inode.
+func (*globalsStruct) DoOpen(inHeader *fission.InHeader, openIn *fission.OpenIn) (openOut *fission.OpenOut, errno syscall.Errno) {
+	var (
+		allowReads     bool
+		allowWrites    bool
+		appendWrites   bool
+		backend        *backendStruct
+		cacheBypass    bool
+		cachePartition string
+		fh             *fhStruct
+		fhNonce        uint64
+		inode          *inodeStruct
+		isExclusive    bool
+		latency        float64
+		ok             bool
+		startTime      = time.Now()
+	)
+
+	defer endInFlightOp(beginInFlightOp("Open", inHeader), &errno)
+
+	defer func() {
+		latency = time.Since(startTime).Seconds()
+		globalsLock("fission.go:1638:3:funcLit@1636")
+		if errno == 0 {
+			globals.fissionMetrics.OpenSuccesses.Inc()
+			globals.fissionMetrics.OpenSuccessLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.OpenSuccesses.Inc()
+				backend.fissionMetrics.OpenSuccessLatencies.Observe(latency)
+			}
+		} else {
+			globals.fissionMetrics.OpenFailures.Inc()
+			globals.fissionMetrics.OpenFailureLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.OpenFailures.Inc()
+				backend.fissionMetrics.OpenFailureLatencies.Observe(latency)
+			}
+		}
+		globalsUnlock()
+	}()
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	// Resolved before taking the globals lock as it may need to consult /proc
+
+	cachePartition = cachePartitionKey(inHeader.UID, inHeader.PID)
+
+	globalsLock("fission.go:1666:2:(*globalsStruct).DoOpen")
+
+	inode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok {
+		backend = nil
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if inode.backendNonce == 0 {
+		backend = nil
+	} else {
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce]")
 		}
 	}
 
@@ -930,20 +1716,27 @@ func (*globalsStruct) DoOpen(inHeader *fission.InHeader, openIn *fission.OpenIn)
 	allowReads = (openIn.Flags & (fission.FOpenRequestRDONLY | fission.FOpenRequestWRONLY | fission.FOpenRequestRDWR)) != fission.FOpenRequestWRONLY
 	allowWrites = (openIn.Flags & (fission.FOpenRequestRDONLY | fission.FOpenRequestWRONLY | fission.FOpenRequestRDWR)) != fission.FOpenRequestRDONLY
 	appendWrites = allowWrites && ((openIn.Flags & fission.FOpenRequestAPPEND) == fission.FOpenRequestAPPEND)
+	cacheBypass = backend.cacheBypass || ((openIn.Flags & uint32(syscall.O_DIRECT)) == uint32(syscall.O_DIRECT))
 
 	if allowWrites && backend.readOnly {
 		globalsUnlock()
-		errno = syscall.EACCES
+		errno = syscall.EROFS
 		return
 	}
 
 	fh = &fhStruct{
-		nonce:        fetchNonce(),
-		inode:        inode,
-		isExclusive:  isExclusive,
-		allowReads:   allowReads,
-		allowWrites:  allowWrites,
-		appendWrites: appendWrites,
+		nonce:          fetchNonce(),
+		inode:          inode,
+		openerUID:      inHeader.UID,
+		openerGID:      inHeader.GID,
+		openerPID:      inHeader.PID,
+		openTime:       time.Now(),
+		cachePartition: cachePartition,
+		isExclusive:    isExclusive,
+		allowReads:     allowReads,
+		allowWrites:    allowWrites,
+		appendWrites:   appendWrites,
+		cacheBypass:    cacheBypass,
 	}
 
 	inode.fhSet[fh.nonce] = struct{}{}
@@ -953,7 +1746,7 @@ func (*globalsStruct) DoOpen(inHeader *fission.InHeader, openIn *fission.OpenIn)
 
 	openOut = &fission.OpenOut{
 		FH:        fh.nonce,
-		OpenFlags: computeOpenOutFlags(),
+		OpenFlags: computeOpenOutFlags(fh.cacheBypass),
 		Padding:   0,
 	}
 
@@ -967,6 +1760,7 @@ func (*globalsStruct) DoOpen(inHeader *fission.InHeader, openIn *fission.OpenIn)
 func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn) (readOut *fission.ReadOut, errno syscall.Errno) {
 	var (
 		backend                         *backendStruct
+		cacheBypassed                   bool
 		cacheLineHits                   uint64 // As this is the fall-thru condition, includes +cacheMisses+cacheWaits
 		cacheLineNumber                 uint64
 		cacheLineNumberMaxInBackend     uint64
@@ -976,37 +1770,36 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 		cacheLineWaiter                 sync.WaitGroup
 		cacheLineWaits                  uint64
 		cacheLinesToPotentiallyPrefetch uint64
-		copyDstStart                    int    // len(readOut.Data) snapshot for optimistic-copy rollback
-		copyGeneration                  uint64 // dataCacheLineTracker.contentGeneration snapshot
-		copyLength                      uint64
-		copySrcStart                    uint64
-		copyDiskFile                    *os.File // [cache_storage == "per-inode-file"] snapshot of the line's backing file for unlocked pread
-		copyDiskOffset                  int64    // [cache_storage == "per-inode-file"] byte offset within copyDiskFile to pread from
-		diskReadErr                     error
 		curOffset                       = readIn.Offset
 		dataCacheLineNumber             uint64
 		dataCacheLineNumbers            []uint64
 		dataCacheLineTracker            *dataCacheLineTrackerStruct
+		err                             error
+		fetchArchived                   bool
 		fh                              *fhStruct
+		fissionVolume                   fission.Volume
+		inFlightOp                      = beginInFlightOp("Read", inHeader)
 		inode                           *inodeStruct
 		latency                         float64
+		lineCopy                        *cacheLineCopyStruct
+		objectPath                      string
 		ok                              bool
 		prefetchCacheLinesIssued        uint64
 		prefetchCacheLineNumber         uint64
 		prefetchCacheLineNumberMax      uint64
 		prefetchCacheLineNumberMin      uint64
 		prefetchCacheLineNumbers        []uint64
+		readFileInput                   *readFileInputStruct
+		readFileOutput                  *readFileOutputStruct
+		revalidated                     bool
 		startTime                       = time.Now()
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
 		latency = time.Since(startTime).Seconds()
-		// No global lock here: every field updated below is a goroutine-safe
-		// Prometheus Counter/Histogram (self-synchronized), and backend/readOut/
-		// errno are this call's locals. globals.fissionMetrics is init-once and a
-		// captured backend's .fissionMetrics is set before it is published to
-		// backendMap, so both pointers are stable. Dropping this per-read metrics
-		// lock takes a warm read from two global-lock acquisitions to one.
+		// No global lock here for the reasons given for observeFissionOp()
 		if errno == 0 {
 			globals.fissionMetrics.ReadSuccesses.Inc()
 			globals.fissionMetrics.ReadSuccessLatencies.Observe(latency)
@@ -1034,9 +1827,11 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 		if backend == nil {
 			if cacheLineHits != 0 {
 				globals.fissionMetrics.ReadCacheHits.Add(float64(cacheLineHits))
+				globals.backendMetrics.CacheLineHits.Add(float64(cacheLineHits))
 			}
 			if cacheLineMisses != 0 {
 				globals.fissionMetrics.ReadCacheMisses.Add(float64(cacheLineMisses))
+				globals.backendMetrics.CacheLineMisses.Add(float64(cacheLineMisses))
 			}
 			if cacheLineWaits != 0 {
 				globals.fissionMetrics.ReadCacheWaits.Add(float64(cacheLineWaits))
@@ -1044,14 +1839,21 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 			if prefetchCacheLinesIssued != 0 {
 				globals.fissionMetrics.ReadCachePrefetches.Add(float64(prefetchCacheLinesIssued))
 			}
+			if cacheBypassed {
+				globals.fissionMetrics.ReadCacheBypasses.Inc()
+			}
 		} else {
 			if cacheLineHits != 0 {
 				globals.fissionMetrics.ReadCacheHits.Add(float64(cacheLineHits))
 				backend.fissionMetrics.ReadCacheHits.Add(float64(cacheLineHits))
+				globals.backendMetrics.CacheLineHits.Add(float64(cacheLineHits))
+				backend.backendMetrics.CacheLineHits.Add(float64(cacheLineHits))
 			}
 			if cacheLineMisses != 0 {
 				globals.fissionMetrics.ReadCacheMisses.Add(float64(cacheLineMisses))
 				backend.fissionMetrics.ReadCacheMisses.Add(float64(cacheLineMisses))
+				globals.backendMetrics.CacheLineMisses.Add(float64(cacheLineMisses))
+				backend.backendMetrics.CacheLineMisses.Add(float64(cacheLineMisses))
 			}
 			if cacheLineWaits != 0 {
 				globals.fissionMetrics.ReadCacheWaits.Add(float64(cacheLineWaits))
@@ -1061,15 +1863,44 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 				globals.fissionMetrics.ReadCachePrefetches.Add(float64(prefetchCacheLinesIssued))
 				backend.fissionMetrics.ReadCachePrefetches.Add(float64(prefetchCacheLinesIssued))
 			}
+			if cacheBypassed {
+				globals.fissionMetrics.ReadCacheBypasses.Inc()
+				backend.fissionMetrics.ReadCacheBypasses.Inc()
+			}
 		}
 	}()
 
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
 	readOut = &fission.ReadOut{
 		Data: make([]byte, 0, readIn.Size),
 	}
 
 	for len(readOut.Data) < cap(readOut.Data) {
-		globalsLock("fission.go:1072:3:(*globalsStruct).DoRead")
+		backend, lineCopy, ok = readResidentCacheLine(inHeader.NodeID, readIn.FH, curOffset, uint64(cap(readOut.Data)-len(readOut.Data)), revalidated, startTime)
+		if ok {
+			if lineCopy == nil {
+				break
+			}
+
+			cacheLineHits++
+
+			ok, err = lineCopy.copyTo(readOut)
+			if err != nil {
+				errno = syscall.EIO
+				return
+			}
+			if ok {
+				curOffset += lineCopy.length
+			}
+
+			continue
+		}
+
+		globalsLock("fission.go:1903:3:(*globalsStruct).DoRead")
 
 		inode, ok = globals.inodeMap.get(inHeader.NodeID)
 		if !ok {
@@ -1112,6 +1943,19 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 			return
 		}
 
+		if !revalidated {
+			// Once per DoRead(), ensure no stale cache lines will be served should entry_attr_ttl have expired
+
+			revalidated = true
+
+			if inode.needsRevalidation() {
+				globalsUnlock()
+				revalidateFileObjectInode(inFlightOp.ctx, inode.inodeNumber)
+				continue
+			}
+		}
+
+		inode.times.aTime = startTime
 		inode.touch(nil)
 
 		if curOffset >= inode.sizeInBackend {
@@ -1122,17 +1966,49 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 			break
 		}
 
+		if fh.cacheBypass {
+			// Satisfy the remainder of the read with a single ranged read sized to
+			// the request that neither consults nor populates the data cache
+
+			readFileInput = &readFileInputStruct{
+				filePath: inode.objectPath,
+				offset:   curOffset,
+				length:   min(uint64(cap(readOut.Data)-len(readOut.Data)), inode.sizeInBackend-curOffset),
+				ifMatch:  inode.eTag,
+			}
+
+			globalsUnlock()
+
+			cacheBypassed = true
+
+			readFileOutput, err = hedgedReadFileWrapper(inFlightOp.ctx, backend, readFileInput)
+			if err != nil {
+				globals.logger.Printf("[WARN] DoRead() cache bypassing read of inode %d at offset %d failed: %v", inHeader.NodeID, curOffset, err)
+				if errors.Is(err, errObjectArchived) {
+					errno = archivedFileErrno(inFlightOp.ctx, backend, readFileInput.filePath)
+				} else {
+					errno = syscall.EIO
+				}
+				return
+			}
+
+			readOut.Data = append(readOut.Data, readFileOutput.buf[:min(len(readFileOutput.buf), cap(readOut.Data)-len(readOut.Data))]...)
+
+			break
+		}
+
 		cacheLineNumber = curOffset / globals.config.cacheLineSize
 
 		dataCacheLineNumber, ok = inode.cacheMap[cacheLineNumber]
 		if !ok {
 			cacheLineMisses++
 
-			globals.fissionVolume.HighLatencyCallback(inHeader)
+			fissionVolume, _ = fissionVolumeOf(backend)
+			fissionVolume.HighLatencyCallback(inHeader)
 
 			prefetchCacheLineNumbers = prefetchCacheLineNumbers[:0]
 
-			if globals.config.cacheLinesToPrefetch > 0 {
+			if (globals.config.cacheLinesToPrefetch > 0) && !globals.memoryPressure.Load() {
 				cacheLineNumberMaxInBackend = ((inode.sizeInBackend + globals.config.cacheLineSize - 1) / globals.config.cacheLineSize) - 1
 
 				if cacheLineNumberMaxInBackend >= (cacheLineNumber + globals.config.cacheLinesToPrefetch) {
@@ -1158,9 +2034,9 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 				}
 			}
 
-			dataCacheLineNumbers, _ = allocateDataCacheLines(1 + uint64(len(prefetchCacheLineNumbers)))
+			dataCacheLineNumbers, _ = allocateDataCacheLines(1+uint64(len(prefetchCacheLineNumbers)), fh.cachePartition)
 
-			globalsLock("fission.go:1163:4:(*globalsStruct).DoRead")
+			globalsLock("fission.go:2039:4:(*globalsStruct).DoRead")
 
 			inode, ok = globals.inodeMap.get(inHeader.NodeID)
 			if !ok {
@@ -1244,8 +2120,7 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 			inode.inboundCacheLineCount++
 			globals.dataCacheLineInboundLRU.pushTail(dataCacheLineTracker)
 
-			globals.dataCacheActivityWG.Add(1)
-			go dataCacheLineTracker.fetch()
+			dataCacheLineTracker.startFetch(inFlightOp.ctx)
 
 			for _, prefetchCacheLineNumber = range prefetchCacheLineNumbers {
 				if len(dataCacheLineNumbers) == 0 {
@@ -1271,8 +2146,7 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 				inode.inboundCacheLineCount++
 				globals.dataCacheLineInboundLRU.pushTail(dataCacheLineTracker)
 
-				globals.dataCacheActivityWG.Add(1)
-				go dataCacheLineTracker.fetch()
+				dataCacheLineTracker.startFetch(inFlightOp.ctx)
 
 				prefetchCacheLinesIssued++
 			}
@@ -1281,7 +2155,11 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 
 			globalsUnlock()
 
-			cacheLineWaiter.Wait()
+			err = awaitCacheLineWaiter(inFlightOp.ctx, &cacheLineWaiter)
+			if err != nil {
+				errno = canceledErrno(inFlightOp.ctx)
+				return
+			}
 
 			continue
 		}
@@ -1296,14 +2174,19 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 		if dataCacheLineTracker.state == CacheLineInbound {
 			cacheLineWaits++
 
-			globals.fissionVolume.HighLatencyCallback(inHeader)
+			fissionVolume, _ = fissionVolumeOf(backend)
+			fissionVolume.HighLatencyCallback(inHeader)
 
 			cacheLineWaiter.Add(1)
 			dataCacheLineTracker.waiters = append(dataCacheLineTracker.waiters, &cacheLineWaiter)
 
 			globalsUnlock()
 
-			cacheLineWaiter.Wait()
+			err = awaitCacheLineWaiter(inFlightOp.ctx, &cacheLineWaiter)
+			if err != nil {
+				errno = canceledErrno(inFlightOp.ctx)
+				return
+			}
 
 			continue
 		}
@@ -1317,12 +2200,20 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 			// The backend read that was supposed to populate this cache line
 			// failed. Surface EIO to the caller rather than serving empty/short
 			// content (which previously produced an inverted slice and panicked),
-			// and evict the line so a subsequent read re-fetches it.
+			// and evict the line so a subsequent read re-fetches it. Should the
+			// content be archived, EREMOTEIO or EAGAIN is surfaced instead (and a
+			// restore perhaps requested) so the cause isn't mistaken for a fault.
+			fetchArchived = dataCacheLineTracker.fetchArchived
+			objectPath = inode.objectPath
 			delete(inode.cacheMap, cacheLineNumber)
 			globals.dataCacheLineCleanLRU.popThis(dataCacheLineTracker)
 			dataCacheLineTracker.free()
 			globalsUnlock()
-			errno = syscall.EIO
+			if fetchArchived {
+				errno = archivedFileErrno(inFlightOp.ctx, backend, objectPath)
+			} else {
+				errno = syscall.EIO
+			}
 			return
 		}
 
@@ -1349,81 +2240,239 @@ func (*globalsStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn)
 			break
 		}
 
-		// Optimistic-lock copy: perform the cache-line -> reply memcpy WITHOUT
-		// holding the global lock, so concurrent warm reads do not serialize on
-		// it (the copy-under-lock was the warm-read throughput ceiling at high
-		// thread counts). globals.dataCacheLinesContent (the cache-line content buffer) and
-		// globals.dataCacheLinesTracker are fixed allocations for the life of the
-		// mount, so reading from them after unlock is memory-safe. The only hazard
-		// is that this line is evicted/refetched mid-copy (yielding wrong bytes);
-		// .contentGeneration is bumped (atomically) on every free()/fetch(), so we
-		// snapshot it under the lock, copy unlocked, then re-validate it with a
-		// lock-free atomic load (no second global-lock acquire) and retry the same
-		// offset on mismatch.
-		copyGeneration = dataCacheLineTracker.contentGeneration.Load()
-		copyLength = cacheLineOffsetLimit - cacheLineOffsetStart
-		copyDstStart = len(readOut.Data)
-		if globals.config.cacheStorage == cacheStoragePerInodeFile {
-			copyDiskFile = dataCacheLineTracker.diskFile
-			copyDiskOffset = dataCacheLineTracker.diskOffset + int64(cacheLineOffsetStart)
-		} else {
-			copySrcStart = dataCacheLineTracker.contentStart + cacheLineOffsetStart
-		}
+		lineCopy = pinCacheLineCopy(dataCacheLineTracker, cacheLineOffsetStart, cacheLineOffsetLimit)
 
 		globalsUnlock()
 
-		if copyDiskFile != nil {
-			// Disk backend: pread straight into the reply buffer's backing array
-			// (no intermediate memcpy), outside the lock. A concurrent eviction
-			// bumps contentGeneration (re-checked below); and a stable generation
-			// means this very line is still resident, so its inode file cannot
-			// have been closed under us.
-			readOut.Data = readOut.Data[:copyDstStart+int(copyLength)]
-			_, diskReadErr = copyDiskFile.ReadAt(readOut.Data[copyDstStart:copyDstStart+int(copyLength)], copyDiskOffset)
-		} else {
-			readOut.Data = append(readOut.Data, globals.dataCacheLinesContent[copySrcStart:copySrcStart+copyLength]...)
-		}
-
-		// Lock-free optimistic re-check: re-read .contentGeneration with an atomic
-		// load instead of re-acquiring the global lock -- that second per-read
-		// acquire was the warm-read throughput ceiling at high thread counts. The
-		// evictor atomically bumps .contentGeneration *before* it punches/recycles
-		// the slot (cache.go allocateDataCacheLines/free), so an unchanged
-		// generation here means no eviction touched this line during the unlocked
-		// copy and the bytes are valid; a changed generation means we may have
-		// copied torn/punched bytes, so we discard and retry. The disk pread is a
-		// syscall (full barrier); the arena memcpy precedes this load on amd64.
-		if dataCacheLineTracker.contentGeneration.Load() != copyGeneration {
-			// Evicted/refetched mid-copy: discard and retry this same offset. Any
-			// diskReadErr was a casualty of that eviction, so ignore it.
-			readOut.Data = readOut.Data[:copyDstStart]
-			continue
-		}
-
-		if diskReadErr != nil && diskReadErr != io.EOF {
-			// Generation stable but the pread genuinely failed -> surface EIO.
-			readOut.Data = readOut.Data[:copyDstStart]
+		ok, err = lineCopy.copyTo(readOut)
+		if err != nil {
 			errno = syscall.EIO
 			return
 		}
-
-		curOffset += copyLength
+		if ok {
+			curOffset += lineCopy.length
+		}
 	}
 
 	errno = 0
 	return
 }
 
+// `cacheLineCopyStruct` describes the portion of a pinned data cache line that DoRead()
+// is to copy into its reply without holding globals (see pinCacheLineCopy()).
+type cacheLineCopyStruct struct {
+	dataCacheLineTracker *dataCacheLineTrackerStruct //
+	generation           uint64                      // dataCacheLineTracker.contentGeneration snapshot
+	length               uint64                      // Number of bytes to copy
+	srcStart             uint64                      // [cache_storage == "memory"] starting offset in globals.dataCacheLinesContent to copy from
+	diskFile             *os.File                    // [cache_storage == "per-inode-file"] snapshot of the line's backing file for unlocked pread
+	diskOffset           int64                       // [cache_storage == "per-inode-file"] byte offset within diskFile to pread from
+}
+
+// `pinCacheLineCopy` is called while holding globals (shared or exclusive) to pin the
+// Clean dataCacheLineTracker and snapshot what copyTo() will need to copy its content
+// in [cacheLineOffsetStart:cacheLineOffsetLimit) after globals is released.
+//
+// This is an optimistic-lock copy: the cache-line -> reply memcpy is performed WITHOUT
+// holding the global lock, so concurrent warm reads do not serialize on it (the
+// copy-under-lock was the warm-read throughput ceiling at high thread counts).
+// globals.dataCacheLinesContent (the cache-line content buffer) and
+// globals.dataCacheLinesTracker are fixed allocations for the life of the mount, so
+// reading from them after unlock is memory-safe. The line is pinned for the duration
+// of the copy so that allocateDataCacheLines() cannot recycle it (and a fetch overwrite
+// its content) mid-copy. The remaining hazard is that this line is evicted/freed
+// mid-copy (yielding stale or punched bytes); .contentGeneration is bumped (atomically)
+// on every free()/fetch(), so we snapshot it under the lock, copy unlocked, then
+// re-validate it with a lock-free atomic load (no second global-lock acquire) and retry
+// the same offset on mismatch.
+func pinCacheLineCopy(dataCacheLineTracker *dataCacheLineTrackerStruct, cacheLineOffsetStart, cacheLineOffsetLimit uint64) (lineCopy *cacheLineCopyStruct) {
+	lineCopy = &cacheLineCopyStruct{
+		dataCacheLineTracker: dataCacheLineTracker,
+		generation:           dataCacheLineTracker.contentGeneration.Load(),
+		length:               cacheLineOffsetLimit - cacheLineOffsetStart,
+	}
+	if globals.config.cacheStorage == cacheStoragePerInodeFile {
+		lineCopy.diskFile = dataCacheLineTracker.diskFile
+		lineCopy.diskOffset = dataCacheLineTracker.diskOffset + int64(cacheLineOffsetStart)
+	} else {
+		lineCopy.srcStart = dataCacheLineTracker.contentStart + cacheLineOffsetStart
+	}
+
+	dataCacheLineTracker.pin()
+
+	return
+}
+
+// `copyTo` is called without holding globals to append the content snapshotted by
+// pinCacheLineCopy() to readOut.Data and unpin the line. Should the line have been
+// evicted or refetched mid-copy, the appended bytes are discarded and ok == false
+// (such that the caller retries the same offset). Otherwise, a failed pread of the
+// line's backing file is returned as err.
+func (lineCopy *cacheLineCopyStruct) copyTo(readOut *fission.ReadOut) (ok bool, err error) {
+	var (
+		copyDstStart = len(readOut.Data) // snapshot for optimistic-copy rollback
+	)
+
+	if lineCopy.diskFile != nil {
+		// Disk backend: pread straight into the reply buffer's backing array
+		// (no intermediate memcpy), outside the lock. A concurrent eviction
+		// bumps contentGeneration (re-checked below); and a stable generation
+		// means this very line is still resident, so its inode file cannot
+		// have been closed under us.
+		readOut.Data = readOut.Data[:copyDstStart+int(lineCopy.length)]
+		_, err = lineCopy.diskFile.ReadAt(readOut.Data[copyDstStart:copyDstStart+int(lineCopy.length)], lineCopy.diskOffset)
+	} else {
+		readOut.Data = append(readOut.Data, globals.dataCacheLinesContent[lineCopy.srcStart:lineCopy.srcStart+lineCopy.length]...)
+	}
+
+	lineCopy.dataCacheLineTracker.unpin()
+
+	// Lock-free optimistic re-check: re-read .contentGeneration with an atomic
+	// load instead of re-acquiring the global lock -- that second per-read
+	// acquire was the warm-read throughput ceiling at high thread counts. The
+	// evictor atomically bumps .contentGeneration *before* it punches/recycles
+	// the slot (cache.go allocateDataCacheLines/free), so an unchanged
+	// generation here means no eviction touched this line during the unlocked
+	// copy and the bytes are valid; a changed generation means we may have
+	// copied torn/punched bytes, so we discard and retry. The disk pread is a
+	// syscall (full barrier); the arena memcpy precedes this load on amd64.
+	if lineCopy.dataCacheLineTracker.contentGeneration.Load() != lineCopy.generation {
+		// Evicted/refetched mid-copy: discard and retry this same offset. Any
+		// pread error was a casualty of that eviction, so ignore it.
+		readOut.Data = readOut.Data[:copyDstStart]
+		ok = false
+		err = nil
+		return
+	}
+
+	if (err != nil) && (err != io.EOF) {
+		// Generation stable but the pread genuinely failed
+		readOut.Data = readOut.Data[:copyDstStart]
+		ok = false
+		return
+	}
+
+	ok = true
+	err = nil
+	return
+}
+
+// `readResidentCacheLine` is called by DoRead() to attempt, while holding globals only
+// shared (see globalsRLock()), to pin the resident Clean data cache line holding curOffset
+// of the inode (opened via fhNonce) such that warm reads of independent files need not serialize
+// on globals. Rather than updating the inode's .times.aTime and the LRU positions of the
+// inode and the data cache line, those touches are recorded in the inode's globals.inodeMap
+// shard for the next holder of globals exclusively to apply. If anything beyond such a warm
+// hit is required (e.g. revalidation, a cache miss or in-flight fetch, a cache bypassing
+// handle, or reading zeroes beyond .sizeInBackend), ok == false and DoRead() takes its exclusive
+// path. Should curOffset be at or beyond EOF, ok == true but lineCopy == nil.
+func readResidentCacheLine(inodeNumber, fhNonce, curOffset, remaining uint64, revalidated bool, readTime time.Time) (backend *backendStruct, lineCopy *cacheLineCopyStruct, ok bool) {
+	var (
+		cacheLineNumber      uint64
+		cacheLineOffsetLimit uint64
+		cacheLineOffsetStart uint64
+		dataCacheLineNumber  uint64
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		fh                   *fhStruct
+		inode                *inodeStruct
+	)
+
+	globalsRLock()
+
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	if !ok || (inode.inodeType != FileObject) || (!revalidated && inode.needsRevalidation()) {
+		globalsRUnlock()
+		ok = false
+		return
+	}
+
+	_, ok = inode.fhSet[fhNonce]
+	if !ok {
+		globalsRUnlock()
+		return
+	}
+	fh, ok = globals.fhMap[fhNonce]
+	if !ok || !fh.allowReads || fh.cacheBypass {
+		globalsRUnlock()
+		ok = false
+		return
+	}
+
+	if inode.backendNonce != 0 {
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce]")
+		}
+	}
+
+	if curOffset >= inode.sizeInBackend {
+		// We have reached EOF of the object (and file)
+
+		globals.inodeMap.deferTouch(inode.inodeNumber, readTime)
+		globalsRUnlock()
+		ok = true
+		return
+	}
+
+	cacheLineNumber = curOffset / globals.config.cacheLineSize
+
+	dataCacheLineNumber, ok = inode.cacheMap[cacheLineNumber]
+	if !ok || (dataCacheLineNumber >= uint64(len(globals.dataCacheLinesTracker))) {
+		globalsRUnlock()
+		ok = false
+		return
+	}
+
+	dataCacheLineTracker = &globals.dataCacheLinesTracker[dataCacheLineNumber]
+
+	if (dataCacheLineTracker.state != CacheLineClean) || dataCacheLineTracker.fetchFailed {
+		globalsRUnlock()
+		ok = false
+		return
+	}
+
+	cacheLineOffsetStart = curOffset - (cacheLineNumber * globals.config.cacheLineSize)
+
+	globals.inodeMap.deferTouch(inode.inodeNumber, readTime)
+
+	cacheLineOffsetLimit = min(cacheLineOffsetStart+remaining, globals.config.cacheLineSize, dataCacheLineTracker.contentLength)
+	if cacheLineOffsetLimit <= cacheLineOffsetStart {
+		// We have reached EOF (==) or, defensively, a short/empty cache line (<)...
+		// either way, DoRead() is done and lineCopy == nil so indicates
+
+		globalsRUnlock()
+		ok = true
+		return
+	}
+
+	lineCopy = pinCacheLineCopy(dataCacheLineTracker, cacheLineOffsetStart, cacheLineOffsetLimit)
+
+	globals.inodeMap.deferCacheLineTouch(inode.inodeNumber, dataCacheLineTracker.pos, lineCopy.generation)
+
+	globalsRUnlock()
+
+	ok = true
+	return
+}
+
 // `DoWrite` implements the package fission callback to add or replace a portion of a file inode's contents.
 func (*globalsStruct) DoWrite(inHeader *fission.InHeader, writeIn *fission.WriteIn) (writeOut *fission.WriteOut, errno syscall.Errno) {
-	fmt.Println("[TODO] fission.go::DoWrite()")
-	errno = syscall.ENOSYS
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	errno = readOnlyErrno(inHeader.NodeID, syscall.ENOSYS)
+	if errno == syscall.ENOSYS {
+		fmt.Println("[TODO] fission.go::DoWrite()")
+	}
 	return
 }
 
 // `DoStatFS` implements the package fission callback to fetch statistics about this FUSE file system.
 func (*globalsStruct) DoStatFS(inHeader *fission.InHeader) (statFSOut *fission.StatFSOut, errno syscall.Errno) {
-	globalsLock("fission.go:1426:2:(*globalsStruct).DoStatFS")
+	globalsLock("fission.go:2475:2:(*globalsStruct).DoStatFS")
 
 	statFSOut = &fission.StatFSOut{
 		KStatFS: fission.KStatFS{
@@ -1431,7 +2480,7 @@ func (*globalsStruct) DoStatFS(inHeader *fission.InHeader) (statFSOut *fission.S
 			BFree:   uint64(math.MaxUint64) / statFSBlkSize,
 			BAvail:  uint64(math.MaxUint64) / statFSBlkSize,
 			Files:   uint64(globals.inodeMap.len()),
-			FFree:   uint64(math.MaxUint64) - globals.lastNonce,
+			FFree:   uint64(math.MaxUint64) - globals.lastNonce.Load(),
 			BSize:   uint32(globals.config.cacheLineSize),
 			NameLen: maxNameLen,
 			FRSize:  uint32(globals.config.cacheLineSize),
@@ -1442,137 +2491,338 @@ func (*globalsStruct) DoStatFS(inHeader *fission.InHeader) (statFSOut *fission.S
 
 	globals.fissionMetrics.StatFSCalls.Inc()
 
-	globalsUnlock()
+	globalsUnlock()
+
+	errno = 0
+	return
+}
+
+// `DoRelease` implements the package fission callback to close a file inode's file handle.
+func (*globalsStruct) DoRelease(inHeader *fission.InHeader, releaseIn *fission.ReleaseIn) (errno syscall.Errno) {
+	var (
+		backend    *backendStruct
+		fh         *fhStruct
+		inFlightOp = beginInFlightOp("Release", inHeader)
+		inode      *inodeStruct
+		latency    float64
+		ok         bool
+		startTime  = time.Now()
+	)
+
+	defer endInFlightOp(inFlightOp, &errno)
+
+	defer func() {
+		latency = time.Since(startTime).Seconds()
+		globalsLock("fission.go:2516:3:funcLit@2514")
+		if errno == 0 {
+			globals.fissionMetrics.ReleaseSuccesses.Inc()
+			globals.fissionMetrics.ReleaseSuccessLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.ReleaseSuccesses.Inc()
+				backend.fissionMetrics.ReleaseSuccessLatencies.Observe(latency)
+			}
+		} else {
+			globals.fissionMetrics.ReleaseFailures.Inc()
+			globals.fissionMetrics.ReleaseFailureLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.ReleaseFailures.Inc()
+				backend.fissionMetrics.ReleaseFailureLatencies.Observe(latency)
+			}
+		}
+		globalsUnlock()
+	}()
+
+	globalsLock("fission.go:2535:2:(*globalsStruct).DoRelease")
+
+	inode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok {
+		backend = nil
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if inode.backendNonce == 0 {
+		backend = nil
+	} else {
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce]")
+		}
+	}
+
+	if inode.inodeType != FileObject {
+		inode.touch(nil)
+		globalsUnlock()
+		errno = syscall.EBADF
+		return
+	}
+
+	_, ok = inode.fhSet[releaseIn.FH]
+	if !ok {
+		inode.touch(nil)
+		globalsUnlock()
+		errno = syscall.EBADF
+		return
+	}
+	fh, ok = globals.fhMap[releaseIn.FH]
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.fhMap[releaseIn.FH] returned !ok")
+	}
+
+	delete(inode.fhSet, fh.nonce)
+	delete(globals.fhMap, fh.nonce)
+
+	inode.touch(nil)
+
+	if !inode.pendingDelete || (len(inode.fhSet) != 0) {
+		globalsUnlock()
+
+		errno = 0
+		return
+	}
+
+	globalsUnlock()
+
+	inode.finishPendingDelete(inFlightOp.ctx)
+
+	errno = 0
+	return
+}
+
+// `DoFSync` implements the package fission callback to ensure modified metadata and/or
+// content for a file inode is flushed to the underlying object.
+func (*globalsStruct) DoFSync(inHeader *fission.InHeader, fSyncIn *fission.FSyncIn) (errno syscall.Errno) {
+	fmt.Println("[TODO] fission.go::DoFSync()")
+	errno = syscall.ENOSYS
+	return
+}
+
+// `DoSetXAttr` implements the package fission callback to set or update an extended attribute
+// for an inode. Only the "control" extended attributes XAttrNameCopyTo, setting of which triggers
+// a server-side copy of the inode's object (see copyFileObject()), and XAttrNamePrefetch, setting
+// of which launches a warm-up of the data cache (see startPrefetch()), are supported.
+func (*globalsStruct) DoSetXAttr(inHeader *fission.InHeader, setXAttrIn *fission.SetXAttrIn) (errno syscall.Errno) {
+	var (
+		inFlightOp *inFlightOpStruct
+	)
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	if string(setXAttrIn.Name) == XAttrNameCopyTo {
+		inFlightOp = beginInFlightOp("SetXAttr", inHeader)
+		defer endInFlightOp(inFlightOp, &errno)
+
+		errno = copyFileObject(inFlightOp.ctx, inHeader.NodeID, string(setXAttrIn.Data), inHeader.UID, inHeader.GID)
+		return
+	}
+
+	if string(setXAttrIn.Name) == XAttrNamePrefetch {
+		errno = startPrefetch(inHeader.NodeID, string(setXAttrIn.Data))
+		return
+	}
+
+	errno = readOnlyErrno(inHeader.NodeID, syscall.ENOSYS)
+	return
+}
+
+// `DoGetXAttr` implements the package fission callback to fetch an extended attribute
+// for an inode. Only the read-only user.msc.* extended attributes exposing the metadata
+// of the corresponding backend object (plus the unlisted XAttrNamePresignedURL,
+// XAttrNameTreeSize, and XAttrNamePrefetch) are supported.
+func (*globalsStruct) DoGetXAttr(inHeader *fission.InHeader, getXAttrIn *fission.GetXAttrIn) (getXAttrOut *fission.GetXAttrOut, errno syscall.Errno) {
+	var (
+		backend    *backendStruct
+		inFlightOp = beginInFlightOp("GetXAttr", inHeader)
+		latency    float64
+		ok         bool
+		startTime  = time.Now()
+		value      []byte
+		xattrMap   map[string][]byte
+	)
+
+	defer endInFlightOp(inFlightOp, &errno)
+
+	defer func() {
+		latency = time.Since(startTime).Seconds()
+		globalsLock("fission.go:2653:3:funcLit@2651")
+		if errno == 0 {
+			globals.fissionMetrics.GetXAttrSuccesses.Inc()
+			globals.fissionMetrics.GetXAttrSuccessLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.GetXAttrSuccesses.Inc()
+				backend.fissionMetrics.GetXAttrSuccessLatencies.Observe(latency)
+			}
+		} else {
+			globals.fissionMetrics.GetXAttrFailures.Inc()
+			globals.fissionMetrics.GetXAttrFailureLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.GetXAttrFailures.Inc()
+				backend.fissionMetrics.GetXAttrFailureLatencies.Observe(latency)
+			}
+		}
+		globalsUnlock()
+	}()
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	switch string(getXAttrIn.Name) {
+	case XAttrNamePresignedURL:
+		backend, value, errno = presignObjectURL(inFlightOp.ctx, inHeader.NodeID)
+		if errno != 0 {
+			return
+		}
+	case XAttrNameTreeSize:
+		backend, value, errno = treeSizeXAttr(inFlightOp.ctx, inHeader.NodeID)
+		if errno != 0 {
+			return
+		}
+	case XAttrNamePrefetch:
+		backend, value, errno = prefetchStatus(inHeader.NodeID)
+		if errno != 0 {
+			return
+		}
+	default:
+		if !strings.HasPrefix(string(getXAttrIn.Name), XAttrNamePrefix) {
+			// Avoid a backend HEAD for the likes of security.selinux and system.posix_acl_access
+
+			errno = syscall.ENODATA
+			return
+		}
+
+		backend, xattrMap, errno = fetchObjectXAttrs(inFlightOp.ctx, inHeader.NodeID)
+		if errno != 0 {
+			return
+		}
+
+		value, ok = xattrMap[string(getXAttrIn.Name)]
+		if !ok {
+			errno = syscall.ENODATA
+			return
+		}
+	}
+
+	getXAttrOut = &fission.GetXAttrOut{
+		Size: uint32(len(value)),
+	}
+
+	if getXAttrIn.Size == 0 {
+		// Caller is only asking for the size of the value
+
+		errno = 0
+		return
+	}
+
+	if getXAttrIn.Size < uint32(len(value)) {
+		getXAttrOut = nil
+		errno = syscall.ERANGE
+		return
+	}
+
+	getXAttrOut.Data = value
 
 	errno = 0
 	return
 }
 
-// `DoRelease` implements the package fission callback to close a file inode's file handle.
-func (*globalsStruct) DoRelease(inHeader *fission.InHeader, releaseIn *fission.ReleaseIn) (errno syscall.Errno) {
+// `DoListXAttr` implements the package fission callback to list the extended attributes
+// for an inode. Only the read-only user.msc.* extended attributes exposing the metadata
+// of the corresponding backend object are reported.
+func (*globalsStruct) DoListXAttr(inHeader *fission.InHeader, listXAttrIn *fission.ListXAttrIn) (listXAttrOut *fission.ListXAttrOut, errno syscall.Errno) {
 	var (
-		backend   *backendStruct
-		fh        *fhStruct
-		inode     *inodeStruct
-		latency   float64
-		ok        bool
-		startTime = time.Now()
+		backend    *backendStruct
+		inFlightOp = beginInFlightOp("ListXAttr", inHeader)
+		latency    float64
+		name       string
+		nameList   []string
+		size       uint32
+		startTime  = time.Now()
+		xattrMap   map[string][]byte
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
 		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:1464:3:funcLit@1462")
+		globalsLock("fission.go:2755:3:funcLit@2753")
 		if errno == 0 {
-			globals.fissionMetrics.ReleaseSuccesses.Inc()
-			globals.fissionMetrics.ReleaseSuccessLatencies.Observe(latency)
+			globals.fissionMetrics.ListXAttrSuccesses.Inc()
+			globals.fissionMetrics.ListXAttrSuccessLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.ReleaseSuccesses.Inc()
-				backend.fissionMetrics.ReleaseSuccessLatencies.Observe(latency)
+				backend.fissionMetrics.ListXAttrSuccesses.Inc()
+				backend.fissionMetrics.ListXAttrSuccessLatencies.Observe(latency)
 			}
 		} else {
-			globals.fissionMetrics.ReleaseFailures.Inc()
-			globals.fissionMetrics.ReleaseFailureLatencies.Observe(latency)
+			globals.fissionMetrics.ListXAttrFailures.Inc()
+			globals.fissionMetrics.ListXAttrFailureLatencies.Observe(latency)
 			if backend != nil {
-				backend.fissionMetrics.ReleaseFailures.Inc()
-				backend.fissionMetrics.ReleaseFailureLatencies.Observe(latency)
+				backend.fissionMetrics.ListXAttrFailures.Inc()
+				backend.fissionMetrics.ListXAttrFailureLatencies.Observe(latency)
 			}
 		}
 		globalsUnlock()
 	}()
 
-	globalsLock("fission.go:1483:2:(*globalsStruct).DoRelease")
-
-	inode, ok = globals.inodeMap.get(inHeader.NodeID)
-	if !ok {
-		backend = nil
-		globalsUnlock()
-		errno = syscall.ENOENT
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
 		return
 	}
 
-	if inode.backendNonce == 0 {
-		backend = nil
-	} else {
-		backend, ok = globals.backendMap[inode.backendNonce]
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce]")
-		}
-	}
-
-	if inode.inodeType != FileObject {
-		inode.touch(nil)
-		globalsUnlock()
-		errno = syscall.EBADF
+	backend, xattrMap, errno = fetchObjectXAttrs(inFlightOp.ctx, inHeader.NodeID)
+	if errno != 0 {
 		return
 	}
 
-	_, ok = inode.fhSet[releaseIn.FH]
-	if !ok {
-		inode.touch(nil)
-		globalsUnlock()
-		errno = syscall.EBADF
-		return
-	}
-	fh, ok = globals.fhMap[releaseIn.FH]
-	if !ok {
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] globals.fhMap[releaseIn.FH] returned !ok")
-	}
+	nameList = slices.Sorted(maps.Keys(xattrMap))
 
-	delete(inode.fhSet, fh.nonce)
-	delete(globals.fhMap, fh.nonce)
+	size = 0
+	for _, name = range nameList {
+		size += uint32(len(name)) + 1 // Each name is NUL-terminated
+	}
 
-	inode.touch(nil)
+	listXAttrOut = &fission.ListXAttrOut{
+		Size: size,
+	}
 
-	if !inode.pendingDelete || (len(inode.fhSet) != 0) {
-		globalsUnlock()
+	if listXAttrIn.Size == 0 {
+		// Caller is only asking for the size of the list
 
 		errno = 0
 		return
 	}
 
-	globalsUnlock()
+	if listXAttrIn.Size < size {
+		listXAttrOut = nil
+		errno = syscall.ERANGE
+		return
+	}
 
-	inode.finishPendingDelete()
+	listXAttrOut.Name = make([][]byte, 0, len(nameList))
+	for _, name = range nameList {
+		listXAttrOut.Name = append(listXAttrOut.Name, []byte(name))
+	}
 
 	errno = 0
 	return
 }
 
-// `DoFSync` implements the package fission callback to ensure modified metadata and/or
-// content for a file inode is flushed to the underlying object.
-func (*globalsStruct) DoFSync(inHeader *fission.InHeader, fSyncIn *fission.FSyncIn) (errno syscall.Errno) {
-	fmt.Println("[TODO] fission.go::DoFSync()")
-	errno = syscall.ENOSYS
-	return
-}
-
-// `DoSetXAttr` implements the package fission callback to set or update an extended attribute
-// for an inode (not supported).
-func (*globalsStruct) DoSetXAttr(inHeader *fission.InHeader, setXAttrIn *fission.SetXAttrIn) (errno syscall.Errno) {
-	errno = syscall.ENOSYS
-	return
-}
-
-// `DoGetXAttr` implements the package fission callback to fetch an extended attribute
-// for an inode (not supported).
-func (*globalsStruct) DoGetXAttr(inHeader *fission.InHeader, getXAttrIn *fission.GetXAttrIn) (getXAttrOut *fission.GetXAttrOut, errno syscall.Errno) {
-	errno = syscall.ENOSYS
-	return
-}
-
-// `DoListXAttr` implements the package fission callback to list the extended attributes
-// for an inode (not supported).
-func (*globalsStruct) DoListXAttr(inHeader *fission.InHeader, listXAttrIn *fission.ListXAttrIn) (listXAttrOut *fission.ListXAttrOut, errno syscall.Errno) {
-	errno = syscall.ENOSYS
-	return
-}
-
 // `DoRemoveXAttr` implements the package fission callback to remove an extended attribute
 // for an inode (not supported).
 func (*globalsStruct) DoRemoveXAttr(inHeader *fission.InHeader, removeXAttrIn *fission.RemoveXAttrIn) (errno syscall.Errno) {
-	errno = syscall.ENOSYS
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	errno = readOnlyErrno(inHeader.NodeID, syscall.ENOSYS)
 	return
 }
 
@@ -1610,36 +2860,29 @@ func (*globalsStruct) DoInit(inHeader *fission.InHeader, initIn *fission.InitIn)
 // `DoOpenDir` implements the package fission callback to open a directory inode.
 func (*globalsStruct) DoOpenDir(inHeader *fission.InHeader, openDirIn *fission.OpenDirIn) (openDirOut *fission.OpenDirOut, errno syscall.Errno) {
 	var (
-		backend   *backendStruct
-		fh        *fhStruct
-		inode     *inodeStruct
-		latency   float64
-		ok        bool
-		startTime = time.Now()
+		backend    *backendStruct
+		err        error
+		fh         *fhStruct
+		inFlightOp = beginInFlightOp("OpenDir", inHeader)
+		inode      *inodeStruct
+		ok         bool
+		startTime  = time.Now()
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
-		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:1623:3:funcLit@1621")
-		if errno == 0 {
-			globals.fissionMetrics.OpenDirSuccesses.Inc()
-			globals.fissionMetrics.OpenDirSuccessLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.OpenDirSuccesses.Inc()
-				backend.fissionMetrics.OpenDirSuccessLatencies.Observe(latency)
-			}
-		} else {
-			globals.fissionMetrics.OpenDirFailures.Inc()
-			globals.fissionMetrics.OpenDirFailureLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.OpenDirFailures.Inc()
-				backend.fissionMetrics.OpenDirFailureLatencies.Observe(latency)
-			}
-		}
-		globalsUnlock()
+		observeFissionOp(backend, errno, startTime, func(fissionMetrics *fissionMetricsStruct) fissionOpMetricsStruct {
+			return fissionOpMetricsStruct{fissionMetrics.OpenDirSuccesses, fissionMetrics.OpenDirFailures, fissionMetrics.OpenDirSuccessLatencies, fissionMetrics.OpenDirFailureLatencies}
+		})
 	}()
 
-	globalsLock("fission.go:1642:2:(*globalsStruct).DoOpenDir")
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsLock("fission.go:2885:2:(*globalsStruct).DoOpenDir")
 
 	inode, ok = globals.inodeMap.get(inHeader.NodeID)
 	if !ok {
@@ -1667,8 +2910,12 @@ func (*globalsStruct) DoOpenDir(inHeader *fission.InHeader, openDirIn *fission.O
 
 	if inode.inodeType == FUSERootDir {
 		fh = &fhStruct{
-			nonce: fetchNonce(),
-			inode: inode,
+			nonce:     fetchNonce(),
+			inode:     inode,
+			openerUID: inHeader.UID,
+			openerGID: inHeader.GID,
+			openerPID: inHeader.PID,
+			openTime:  time.Now(),
 		}
 	} else {
 		canServeFromBPTree := false
@@ -1681,6 +2928,10 @@ func (*globalsStruct) DoOpenDir(inHeader *fission.InHeader, openDirIn *fission.O
 		fh = &fhStruct{
 			nonce:                                 fetchNonce(),
 			inode:                                 inode,
+			openerUID:                             inHeader.UID,
+			openerGID:                             inHeader.GID,
+			openerPID:                             inHeader.PID,
+			openTime:                              time.Now(),
 			listDirectoryInProgress:               false,
 			listDirectorySequenceDone:             false,
 			prevListDirectoryOutput:               nil,
@@ -1691,6 +2942,7 @@ func (*globalsStruct) DoOpenDir(inHeader *fission.InHeader, openDirIn *fission.O
 			nextListDirectoryOutputStartingOffset: 0,
 			listDirectorySubdirectorySet:          make(map[string]struct{}),
 			listDirectorySubdirectoryList:         make([]string, 0),
+			listDirectoryAnchorList:               make([]listDirectoryAnchorStruct, 0),
 			serveFromBPTree:                       canServeFromBPTree,
 		}
 	}
@@ -1700,6 +2952,13 @@ func (*globalsStruct) DoOpenDir(inHeader *fission.InHeader, openDirIn *fission.O
 
 	inode.touch(nil)
 
+	if (inode.inodeType != FUSERootDir) && backend.readDirSnapshot && !fh.serveFromBPTree && (backend.manifestPath == "") {
+		err = fh.snapshotListDirectory(inFlightOp.ctx, backend)
+		if err != nil {
+			globals.logger.Printf("[WARN] unable to snapshot \"%s\" in backend \"%s\" (readdir will list it instead): %v", inode.objectPath, backend.dirName, err)
+		}
+	}
+
 	openDirOut = &fission.OpenDirOut{
 		FH:        fh.nonce,
 		OpenFlags: 0,
@@ -1760,7 +3019,7 @@ func (*globalsStruct) DoReadDir(inHeader *fission.InHeader, readDirIn *fission.R
 		dirEntMinSize                               uint64
 		err                                         error
 		fh                                          *fhStruct
-		latency                                     float64
+		inFlightOp                                  = beginInFlightOp("ReadDir", inHeader)
 		listDirectoryOutputFile                     *listDirectoryOutputFileStruct
 		listDirectoryInput                          *listDirectoryInputStruct
 		listDirectoryOutput                         *listDirectoryOutputStruct
@@ -1773,39 +3032,30 @@ func (*globalsStruct) DoReadDir(inHeader *fission.InHeader, readDirIn *fission.R
 		virtChildDirEntryMapIndex                   uint64
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
 		var entriesReturned float64
 		if (errno == 0) && (readDirOut != nil) {
 			entriesReturned = float64(len(readDirOut.DirEnt))
 		}
 
-		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:1783:3:funcLit@1776")
-		if errno == 0 {
-			globals.fissionMetrics.ReadDirSuccesses.Inc()
-			globals.fissionMetrics.ReadDirSuccessLatencies.Observe(latency)
-			if entriesReturned != 0 {
-				globals.fissionMetrics.ReadDirEntriesReturned.Add(entriesReturned)
-			}
-
-			if backend != nil {
-				backend.fissionMetrics.ReadDirSuccesses.Inc()
-				backend.fissionMetrics.ReadDirSuccessLatencies.Observe(latency)
-				if entriesReturned != 0 {
-					backend.fissionMetrics.ReadDirEntriesReturned.Add(entriesReturned)
-				}
-			}
-		} else {
-			globals.fissionMetrics.ReadDirFailures.Inc()
-			globals.fissionMetrics.ReadDirFailureLatencies.Observe(latency)
+		observeFissionOp(backend, errno, startTime, func(fissionMetrics *fissionMetricsStruct) fissionOpMetricsStruct {
+			return fissionOpMetricsStruct{fissionMetrics.ReadDirSuccesses, fissionMetrics.ReadDirFailures, fissionMetrics.ReadDirSuccessLatencies, fissionMetrics.ReadDirFailureLatencies}
+		})
+		if (errno == 0) && (entriesReturned != 0) {
+			globals.fissionMetrics.ReadDirEntriesReturned.Add(entriesReturned)
 			if backend != nil {
-				backend.fissionMetrics.ReadDirFailures.Inc()
-				backend.fissionMetrics.ReadDirFailureLatencies.Observe(latency)
+				backend.fissionMetrics.ReadDirEntriesReturned.Add(entriesReturned)
 			}
 		}
-		globalsUnlock()
 	}()
 
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
 	dirEntMinSize = fission.DirEntFixedPortionSize + 1 + fission.DirEntAlignment - 1
 	dirEntMinSize /= fission.DirEntAlignment
 	dirEntMinSize *= fission.DirEntAlignment
@@ -1818,7 +3068,7 @@ func (*globalsStruct) DoReadDir(inHeader *fission.InHeader, readDirIn *fission.R
 	curReadDirOutSize = 0
 	curOffset = readDirIn.Offset
 
-	globalsLock("fission.go:1821:2:(*globalsStruct).DoReadDir")
+	globalsLock("fission.go:3071:2:(*globalsStruct).DoReadDir")
 
 Restart:
 
@@ -1861,8 +3111,8 @@ Restart:
 	parentInode.touch(nil)
 
 	if parentInode.inodeType == FUSERootDir {
-		parentInodeVirtChildDirEntryMapStart, parentInodeVirtChildDirEntryMapLimit = globals.virtChildDirEntryMap.getIndexRange(FUSERootDirInodeNumber)
-		childDirMapLen = parentInodeVirtChildDirEntryMapLimit - parentInodeVirtChildDirEntryMapStart // Will be == 2 + len(globals.config.backends)
+		parentInodeVirtChildDirEntryMapStart, parentInodeVirtChildDirEntryMapLimit = globals.virtChildDirEntryMap.getIndexRange(parentInode.inodeNumber)
+		childDirMapLen = parentInodeVirtChildDirEntryMapLimit - parentInodeVirtChildDirEntryMapStart // Will be == 2 + the number of backends presented by this mount
 
 		for {
 			if curOffset >= childDirMapLen {
@@ -1885,6 +3135,10 @@ Restart:
 
 			curOffset++
 
+			if childInode.hiddenFrom(inHeader) {
+				continue
+			}
+
 			ok = childInode.appendToReadDirOut(uint64(readDirIn.Size), readDirOut, curOffset, childInodeBasename, &curReadDirOutSize)
 			if !ok {
 				globalsUnlock()
@@ -1897,15 +3151,22 @@ Restart:
 	// If we reach here, we know parentInode.inodeType == BackendRootDir | PseudoDir
 
 	if fh.listDirectoryInProgress {
-		globalsUnlock()
-		errno = syscall.EACCES
-		return
+		// Another readdir of fh awaits a listing page... so await it and start over
+
+		err = fh.awaitListDirectory(inFlightOp.ctx)
+		if err != nil {
+			globalsUnlock()
+			errno = canceledErrno(inFlightOp.ctx)
+			return
+		}
+
+		goto Restart
 	}
 
 	if curOffset < fh.prevListDirectoryOutputStartingOffset {
-		// Adjust curOffset to not try to reference before the start of fh.prevListDirectoryOutput
+		// Restart the listing from the anchor of the page containing curOffset (e.g. following a rewinddir())
 
-		curOffset = fh.prevListDirectoryOutputStartingOffset
+		fh.rewindListDirectory(curOffset)
 	}
 
 	for {
@@ -1921,29 +3182,16 @@ Restart:
 				fh.nextListDirectoryOutputFileLen = 0
 			}
 
-			if fh.prevListDirectoryOutput == nil {
-				listDirectoryInput = &listDirectoryInputStruct{
-					continuationToken: "",
-					maxItems:          backend.directoryPageSize,
-					dirPath:           parentInode.objectPath,
-				}
-			} else {
-				listDirectoryInput = &listDirectoryInputStruct{
-					continuationToken: fh.prevListDirectoryOutput.nextContinuationToken,
-					maxItems:          backend.directoryPageSize,
-					dirPath:           parentInode.objectPath,
-				}
-			}
-
-			fh.listDirectoryInProgress = true
-
-			globalsUnlock()
+			listDirectoryInput = fh.nextListDirectoryInput(backend, parentInode.objectPath)
 
-			listDirectoryOutput, err = listDirectoryWrapper(backend.context, listDirectoryInput)
-
-			globalsLock("fission.go:1944:4:(*globalsStruct).DoReadDir")
+			listDirectoryOutput, ok, err = fh.fetchListDirectory(inFlightOp.ctx, backend, listDirectoryInput, readDirBudget(backend, startTime, len(readDirOut.DirEnt)))
+			if !ok {
+				// readdir_time_budget expired... so return what we have (the remainder will follow)
 
-			fh.listDirectoryInProgress = false
+				globalsUnlock()
+				errno = 0
+				return
+			}
 
 			if err != nil {
 				globalsUnlock()
@@ -1961,11 +3209,11 @@ Restart:
 			if fh.prevListDirectoryOutput == nil {
 				fh.prevListDirectoryOutput = listDirectoryOutput
 				fh.prevListDirectoryOutputFileLen = uint64(len(listDirectoryOutput.file))
-				fh.prevListDirectoryOutputStartingOffset = 0
+				fh.prevListDirectoryOutputStartingOffset = fh.nextListDirectoryOutputStartingOffset
 
 				fh.nextListDirectoryOutput = nil
 				fh.nextListDirectoryOutputFileLen = 0
-				fh.nextListDirectoryOutputStartingOffset = fh.prevListDirectoryOutputFileLen
+				fh.nextListDirectoryOutputStartingOffset = fh.prevListDirectoryOutputStartingOffset + fh.prevListDirectoryOutputFileLen
 			} else {
 				fh.nextListDirectoryOutput = listDirectoryOutput
 				fh.nextListDirectoryOutputFileLen = uint64(len(listDirectoryOutput.file))
@@ -2050,33 +3298,19 @@ func (*globalsStruct) DoReleaseDir(inHeader *fission.InHeader, releaseDirIn *fis
 		backend   *backendStruct
 		fh        *fhStruct
 		inode     *inodeStruct
-		latency   float64
 		ok        bool
 		startTime = time.Now()
 	)
 
+	defer endInFlightOp(beginInFlightOp("ReleaseDir", inHeader), &errno)
+
 	defer func() {
-		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:2060:3:funcLit@2058")
-		if errno == 0 {
-			globals.fissionMetrics.ReleaseDirSuccesses.Inc()
-			globals.fissionMetrics.ReleaseDirSuccessLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.ReleaseDirSuccesses.Inc()
-				backend.fissionMetrics.ReleaseDirSuccessLatencies.Observe(latency)
-			}
-		} else {
-			globals.fissionMetrics.ReleaseDirFailures.Inc()
-			globals.fissionMetrics.ReleaseDirFailureLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.ReleaseDirFailures.Inc()
-				backend.fissionMetrics.ReleaseDirFailureLatencies.Observe(latency)
-			}
-		}
-		globalsUnlock()
+		observeFissionOp(backend, errno, startTime, func(fissionMetrics *fissionMetricsStruct) fissionOpMetricsStruct {
+			return fissionOpMetricsStruct{fissionMetrics.ReleaseDirSuccesses, fissionMetrics.ReleaseDirFailures, fissionMetrics.ReleaseDirSuccessLatencies, fissionMetrics.ReleaseDirFailureLatencies}
+		})
 	}()
 
-	globalsLock("fission.go:2079:2:(*globalsStruct).DoReleaseDir")
+	globalsLock("fission.go:3313:2:(*globalsStruct).DoReleaseDir")
 
 	inode, ok = globals.inodeMap.get(inHeader.NodeID)
 	if !ok {
@@ -2159,12 +3393,129 @@ func (*globalsStruct) DoSetLKW(inHeader *fission.InHeader, setLKWIn *fission.Set
 	return
 }
 
-// `DoAccess` implements the package fission callback to test for access
-// permissions to an inode based solely on user's UID and GID and the
-// metadata for the inode. As this is an incomplete access check, this
-// FUSE file system defers such authorization checks to the kernel.
-func (*globalsStruct) DoAccess(inHeader *fission.InHeader, accessIn *fission.AccessIn) (errno syscall.Errno) {
-	errno = syscall.ENOSYS
+// `DoAccess` implements the package fission callback to test for access
+// permissions to an inode based solely on user's UID and GID and the
+// metadata for the inode. Write access is always denied for read-only
+// backends (and the FUSE root directory). As supplementary groups are not
+// known, this remains an approximation of the kernel's own checks.
+func (*globalsStruct) DoAccess(inHeader *fission.InHeader, accessIn *fission.AccessIn) (errno syscall.Errno) {
+	var (
+		backend   *backendStruct
+		gid       uint32
+		mask      uint32
+		ok        bool
+		permBits  uint32
+		startTime = time.Now()
+		thisInode *inodeStruct
+		uid       uint32
+	)
+
+	defer endInFlightOp(beginInFlightOp("Access", inHeader), &errno)
+
+	defer func() {
+		observeFissionOp(backend, errno, startTime, func(fissionMetrics *fissionMetricsStruct) fissionOpMetricsStruct {
+			return fissionOpMetricsStruct{fissionMetrics.AccessSuccesses, fissionMetrics.AccessFailures, fissionMetrics.AccessSuccessLatencies, fissionMetrics.AccessFailureLatencies}
+		})
+	}()
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsLock("fission.go:3426:2:(*globalsStruct).DoAccess")
+
+	thisInode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok {
+		backend = nil
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if thisInode.backendNonce == 0 {
+		backend = nil
+	} else {
+		backend, ok = globals.backendMap[thisInode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[thisInode.backendNonce]")
+		}
+	}
+
+	if thisInode.pendingDelete {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	thisInode.touch(nil)
+
+	switch thisInode.inodeType {
+	case FileObject:
+		uid = uint32(backend.uid)
+		gid = uint32(backend.gid)
+	case FUSERootDir:
+		uid = uint32(globals.config.uid)
+		gid = uint32(globals.config.gid)
+	case BackendRootDir:
+		uid = uint32(backend.uid)
+		gid = uint32(backend.gid)
+	case PseudoDir:
+		uid = uint32(backend.uid)
+		gid = uint32(backend.gid)
+	default:
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] unrecognized inodeType (%v)", thisInode.inodeType)
+	}
+
+	mask = accessIn.Mask & (accessMaskR | accessMaskW | accessMaskX)
+
+	if (mask & accessMaskW) == accessMaskW {
+		switch {
+		case backend == nil:
+			globalsUnlock()
+			if globals.mountReadOnly {
+				errno = syscall.EROFS
+			} else {
+				errno = syscall.EACCES
+			}
+			return
+		case backend.readOnly:
+			globalsUnlock()
+			errno = syscall.EROFS
+			return
+		}
+	}
+
+	switch {
+	case inHeader.UID == 0:
+		// root is granted R and W regardless of permission bits but X only if some X bit is set (or a directory)
+
+		if thisInode.inodeType == FileObject {
+			permBits = accessMaskR | accessMaskW
+			if (thisInode.mode & 0o111) != 0 {
+				permBits |= accessMaskX
+			}
+		} else {
+			permBits = accessMaskR | accessMaskW | accessMaskX
+		}
+	case inHeader.UID == uid:
+		permBits = (thisInode.mode >> 6) & 0o7
+	case inHeader.GID == gid:
+		permBits = (thisInode.mode >> 3) & 0o7
+	default:
+		permBits = thisInode.mode & 0o7
+	}
+
+	globalsUnlock()
+
+	if (mask & permBits) != mask {
+		errno = syscall.EACCES
+		return
+	}
+
+	errno = 0
 	return
 }
 
@@ -2173,15 +3524,18 @@ func (*globalsStruct) DoCreate(inHeader *fission.InHeader, createIn *fission.Cre
 	var (
 		backend     *backendStruct
 		basename    = string(createIn.Name)
+		inFlightOp  = beginInFlightOp("Create", inHeader)
 		latency     float64
 		ok          bool
 		parentInode *inodeStruct
 		startTime   = time.Now()
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
 		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:2184:3:funcLit@2182")
+		globalsLock("fission.go:3538:3:funcLit@3536")
 		if errno == 0 {
 			globals.fissionMetrics.CreateSuccesses.Inc()
 			globals.fissionMetrics.CreateSuccessLatencies.Observe(latency)
@@ -2200,7 +3554,12 @@ func (*globalsStruct) DoCreate(inHeader *fission.InHeader, createIn *fission.Cre
 		globalsUnlock()
 	}()
 
-	globalsLock("fission.go:2203:2:(*globalsStruct).DoCreate")
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsLock("fission.go:3562:2:(*globalsStruct).DoCreate")
 
 	parentInode, ok = globals.inodeMap.get(inHeader.NodeID)
 	if !ok {
@@ -2227,15 +3586,15 @@ func (*globalsStruct) DoCreate(inHeader *fission.InHeader, createIn *fission.Cre
 	}
 	if parentInode.inodeType == FUSERootDir {
 		globalsUnlock()
-		errno = syscall.EPERM
+		errno = fuseRootDirWriteErrno()
 		return
 	}
-	if backend.readOnly {
+	if backend.readOnly || backend.inVersionsDir(parentInode.objectPath+basename) {
 		globalsUnlock()
-		errno = syscall.EPERM
+		errno = syscall.EROFS
 		return
 	}
-	_, ok = parentInode.findChildInode(basename)
+	_, ok = parentInode.findChildInode(inFlightOp.ctx, basename)
 	if ok {
 		globalsUnlock()
 		errno = syscall.EEXIST
@@ -2250,8 +3609,11 @@ func (*globalsStruct) DoCreate(inHeader *fission.InHeader, createIn *fission.Cre
 }
 
 // `DoInterrupt` implements the package fission callback to interrupt another
-// active callback (not supported).
-func (*globalsStruct) DoInterrupt(inHeader *fission.InHeader, interruptIn *fission.InterruptIn) {}
+// active callback. Backend calls made on behalf of the interrupted callback are
+// canceled and the interrupted callback (if it then fails) returns EINTR.
+func (*globalsStruct) DoInterrupt(inHeader *fission.InHeader, interruptIn *fission.InterruptIn) {
+	_ = interruptInFlightOp(interruptIn.Unique)
+}
 
 // `DoBMap` implements the package fission callback to map blocks of a FUSE "blkdev" device (not supported).
 func (*globalsStruct) DoBMap(inHeader *fission.InHeader, bMapIn *fission.BMapIn) (bMapOut *fission.BMapOut, errno syscall.Errno) {
@@ -2276,22 +3638,134 @@ func (*globalsStruct) DoBatchForget(inHeader *fission.InHeader, batchForgetIn *f
 
 // `DoFAllocate` implements the package fission callback to reserve space that
 // would subsequently be needed by a DoWrite callback to avoid failures due
-// to space allocation unavailable when that DoWrite callback is made (not supported).
+// to space allocation unavailable when that DoWrite callback is made. As an
+// object store has no notion of preallocation, only FALLOC_FL_KEEP_SIZE (a
+// no-op) is supported. The default mode (that would extend the file) fails
+// with EOPNOTSUPP as there is, as yet, no write path by which the extended
+// size could ever reach the backend.
 func (*globalsStruct) DoFAllocate(inHeader *fission.InHeader, fAllocateIn *fission.FAllocateIn) (errno syscall.Errno) {
-	errno = syscall.ENOSYS
+	var (
+		backend   *backendStruct
+		fh        *fhStruct
+		inode     *inodeStruct
+		latency   float64
+		newSize   uint64
+		ok        bool
+		startTime = time.Now()
+	)
+
+	defer endInFlightOp(beginInFlightOp("FAllocate", inHeader), &errno)
+
+	defer func() {
+		latency = time.Since(startTime).Seconds()
+		globalsLock("fission.go:3661:3:funcLit@3659")
+		if errno == 0 {
+			globals.fissionMetrics.FAllocateSuccesses.Inc()
+			globals.fissionMetrics.FAllocateSuccessLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.FAllocateSuccesses.Inc()
+				backend.fissionMetrics.FAllocateSuccessLatencies.Observe(latency)
+			}
+		} else {
+			globals.fissionMetrics.FAllocateFailures.Inc()
+			globals.fissionMetrics.FAllocateFailureLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.FAllocateFailures.Inc()
+				backend.fissionMetrics.FAllocateFailureLatencies.Observe(latency)
+			}
+		}
+		globalsUnlock()
+	}()
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsLock("fission.go:3685:2:(*globalsStruct).DoFAllocate")
+
+	inode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok {
+		backend = nil
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if inode.backendNonce == 0 {
+		backend = nil
+	} else {
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce]")
+		}
+	}
+
+	if inode.inodeType != FileObject {
+		globalsUnlock()
+		errno = syscall.ENODEV
+		return
+	}
+
+	_, ok = inode.fhSet[fAllocateIn.FH]
+	if !ok {
+		globalsUnlock()
+		errno = syscall.EBADF
+		return
+	}
+	fh, ok = globals.fhMap[fAllocateIn.FH]
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.fhMap[fAllocateIn.FH] returned !ok")
+	}
+	if !fh.allowWrites || backend.readOnly {
+		globalsUnlock()
+		errno = syscall.EBADF
+		return
+	}
+
+	if fAllocateIn.Length == 0 {
+		globalsUnlock()
+		errno = syscall.EINVAL
+		return
+	}
+
+	newSize = fAllocateIn.Offset + fAllocateIn.Length
+	if newSize < fAllocateIn.Offset {
+		globalsUnlock()
+		errno = syscall.EFBIG
+		return
+	}
+
+	switch fAllocateIn.Mode {
+	case FAllocateKeepSize:
+		// Nothing to reserve in an object store
+		inode.touch(nil)
+	default:
+		globalsUnlock()
+		errno = syscall.EOPNOTSUPP
+		return
+	}
+
+	globalsUnlock()
+
+	errno = 0
 	return
 }
 
 // `appendToReadDirPlusOut` appends the information about an inode in the form of a fission.DirEntPlus
 // to the accumulating fission.ReadDirPlusOut struct if there is room.
-func (inode *inodeStruct) appendToReadDirPlusOut(readDirPlusInSize uint64, readDirPlusOut *fission.ReadDirPlusOut, entryAttrValidSec uint64, entryAttrValidNSec uint32, dirEntPlusOff uint64, basename string, curReadDirPlusOutSize *uint64) (ok bool) {
+func (inode *inodeStruct) appendToReadDirPlusOut(readDirPlusInSize uint64, readDirPlusOut *fission.ReadDirPlusOut, dirEntPlusOff uint64, basename string, curReadDirPlusOutSize *uint64) (ok bool) {
 	var (
+		attrValidNSec  uint32
+		attrValidSec   uint64
 		backend        *backendStruct
 		dirEntPlus     fission.DirEntPlus
 		dirEntPlusSize uint64
+		entryValidNSec uint32
+		entryValidSec  uint64
 		gid            uint64
-		mTimeNSec      uint32
-		mTimeSec       uint64
 		uid            uint64
 	)
 
@@ -2306,8 +3780,6 @@ func (inode *inodeStruct) appendToReadDirPlusOut(readDirPlusInSize uint64, readD
 
 	*curReadDirPlusOutSize += dirEntPlusSize
 
-	mTimeSec, mTimeNSec = timeTimeToAttrTime(inode.mTime)
-
 	if inode.inodeType == FUSERootDir {
 		uid = globals.config.uid
 		gid = globals.config.gid
@@ -2322,28 +3794,24 @@ func (inode *inodeStruct) appendToReadDirPlusOut(readDirPlusInSize uint64, readD
 		gid = backend.gid
 	}
 
+	entryValidSec, entryValidNSec, attrValidSec, attrValidNSec = entryAndAttrValid(backend)
+
 	dirEntPlus = fission.DirEntPlus{
 		EntryOut: fission.EntryOut{
 			NodeID:         inode.inodeNumber,
-			Generation:     0,
-			EntryValidSec:  entryAttrValidSec,
-			EntryValidNSec: entryAttrValidNSec,
-			AttrValidSec:   entryAttrValidSec,
-			AttrValidNSec:  entryAttrValidNSec,
+			Generation:     inode.generation,
+			EntryValidSec:  entryValidSec,
+			EntryValidNSec: entryValidNSec,
+			AttrValidSec:   attrValidSec,
+			AttrValidNSec:  attrValidNSec,
 			Attr: fission.Attr{
-				Ino:       inode.inodeNumber,
-				Size:      inode.sizeInMemory,
-				ATimeSec:  mTimeSec,
-				MTimeSec:  mTimeSec,
-				CTimeSec:  mTimeSec,
-				ATimeNSec: mTimeNSec,
-				MTimeNSec: mTimeNSec,
-				CTimeNSec: mTimeNSec,
-				Mode:      inode.mode,
-				UID:       uint32(uid),
-				GID:       uint32(gid),
-				RDev:      0,
-				Padding:   0,
+				Ino:     inode.inodeNumber,
+				Size:    inode.sizeInMemory,
+				Mode:    inode.mode,
+				UID:     uint32(uid),
+				GID:     uint32(gid),
+				RDev:    0,
+				Padding: 0,
 			},
 		},
 		DirEnt: fission.DirEnt{
@@ -2355,6 +3823,7 @@ func (inode *inodeStruct) appendToReadDirPlusOut(readDirPlusInSize uint64, readD
 		},
 	}
 	fixAttrSizes(&dirEntPlus.Attr)
+	fixAttrTimes(&dirEntPlus.Attr, inode)
 
 	readDirPlusOut.DirEntPlus = append(readDirPlusOut.DirEntPlus, dirEntPlus)
 
@@ -2365,6 +3834,8 @@ func (inode *inodeStruct) appendToReadDirPlusOut(readDirPlusInSize uint64, readD
 // `DoReadDirPlus` implements the package fission callback to enumerate a directory inode's entries (verbosely).
 func (*globalsStruct) DoReadDirPlus(inHeader *fission.InHeader, readDirPlusIn *fission.ReadDirPlusIn) (readDirPlusOut *fission.ReadDirPlusOut, errno syscall.Errno) {
 	var (
+		attrValidNSec                               uint32
+		attrValidSec                                uint64
 		backend                                     *backendStruct
 		childDirMapLen                              uint64
 		childInode                                  *inodeStruct
@@ -2378,11 +3849,11 @@ func (*globalsStruct) DoReadDirPlus(inHeader *fission.InHeader, readDirPlusIn *f
 		curReadDirPlusOutSize                       uint64
 		dirEntPlusCountMax                          uint64
 		dirEntPlusMinSize                           uint64
-		entryAttrValidNSec                          uint32
-		entryAttrValidSec                           uint64
+		entryValidNSec                              uint32
+		entryValidSec                               uint64
 		err                                         error
 		fh                                          *fhStruct
-		latency                                     float64
+		inFlightOp                                  = beginInFlightOp("ReadDirPlus", inHeader)
 		listDirectoryOutputFile                     *listDirectoryOutputFileStruct
 		listDirectoryInput                          *listDirectoryInputStruct
 		listDirectoryOutput                         *listDirectoryOutputStruct
@@ -2395,39 +3866,30 @@ func (*globalsStruct) DoReadDirPlus(inHeader *fission.InHeader, readDirPlusIn *f
 		virtChildDirEntryMapIndex                   uint64
 	)
 
+	defer endInFlightOp(inFlightOp, &errno)
+
 	defer func() {
 		var entriesReturned float64
 		if (errno == 0) && (readDirPlusOut != nil) {
 			entriesReturned = float64(len(readDirPlusOut.DirEntPlus))
 		}
 
-		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:2405:3:funcLit@2398")
-		if errno == 0 {
-			globals.fissionMetrics.ReadDirPlusSuccesses.Inc()
-			globals.fissionMetrics.ReadDirPlusSuccessLatencies.Observe(latency)
-			if entriesReturned != 0 {
-				globals.fissionMetrics.ReadDirPlusEntriesReturned.Add(entriesReturned)
-			}
-
-			if backend != nil {
-				backend.fissionMetrics.ReadDirPlusSuccesses.Inc()
-				backend.fissionMetrics.ReadDirPlusSuccessLatencies.Observe(latency)
-				if entriesReturned != 0 {
-					backend.fissionMetrics.ReadDirPlusEntriesReturned.Add(entriesReturned)
-				}
-			}
-		} else {
-			globals.fissionMetrics.ReadDirPlusFailures.Inc()
-			globals.fissionMetrics.ReadDirPlusFailureLatencies.Observe(latency)
+		observeFissionOp(backend, errno, startTime, func(fissionMetrics *fissionMetricsStruct) fissionOpMetricsStruct {
+			return fissionOpMetricsStruct{fissionMetrics.ReadDirPlusSuccesses, fissionMetrics.ReadDirPlusFailures, fissionMetrics.ReadDirPlusSuccessLatencies, fissionMetrics.ReadDirPlusFailureLatencies}
+		})
+		if (errno == 0) && (entriesReturned != 0) {
+			globals.fissionMetrics.ReadDirPlusEntriesReturned.Add(entriesReturned)
 			if backend != nil {
-				backend.fissionMetrics.ReadDirPlusFailures.Inc()
-				backend.fissionMetrics.ReadDirPlusFailureLatencies.Observe(latency)
+				backend.fissionMetrics.ReadDirPlusEntriesReturned.Add(entriesReturned)
 			}
 		}
-		globalsUnlock()
 	}()
 
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
 	dirEntPlusMinSize = fission.DirEntFixedPortionSize + 1 + fission.DirEntAlignment - 1
 	dirEntPlusMinSize /= fission.DirEntAlignment
 	dirEntPlusMinSize *= fission.DirEntAlignment
@@ -2440,9 +3902,7 @@ func (*globalsStruct) DoReadDirPlus(inHeader *fission.InHeader, readDirPlusIn *f
 	curReadDirPlusOutSize = 0
 	curOffset = readDirPlusIn.Offset
 
-	entryAttrValidSec, entryAttrValidNSec = timeDurationToAttrDuration(globals.config.entryAttrTTL)
-
-	globalsLock("fission.go:2445:2:(*globalsStruct).DoReadDirPlus")
+	globalsLock("fission.go:3905:2:(*globalsStruct).DoReadDirPlus")
 
 Restart:
 
@@ -2485,8 +3945,8 @@ Restart:
 	parentInode.touch(nil)
 
 	if parentInode.inodeType == FUSERootDir {
-		parentInodeVirtChildDirEntryMapStart, parentInodeVirtChildDirEntryMapLimit = globals.virtChildDirEntryMap.getIndexRange(FUSERootDirInodeNumber)
-		childDirMapLen = parentInodeVirtChildDirEntryMapLimit - parentInodeVirtChildDirEntryMapStart // Will be == 2 + len(globals.config.backends)
+		parentInodeVirtChildDirEntryMapStart, parentInodeVirtChildDirEntryMapLimit = globals.virtChildDirEntryMap.getIndexRange(parentInode.inodeNumber)
+		childDirMapLen = parentInodeVirtChildDirEntryMapLimit - parentInodeVirtChildDirEntryMapStart // Will be == 2 + the number of backends presented by this mount
 
 		for {
 			if curOffset >= childDirMapLen {
@@ -2509,7 +3969,11 @@ Restart:
 
 			curOffset++
 
-			ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, entryAttrValidSec, entryAttrValidNSec, curOffset, childInodeBasename, &curReadDirPlusOutSize)
+			if childInode.hiddenFrom(inHeader) {
+				continue
+			}
+
+			ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, curOffset, childInodeBasename, &curReadDirPlusOutSize)
 			if !ok {
 				globalsUnlock()
 				errno = 0
@@ -2529,6 +3993,8 @@ Restart:
 		virtDotCount := bpVirtLimit - bpVirtStart
 		totalEntries := bpPhysCount + virtDotCount
 
+		entryValidSec, entryValidNSec, attrValidSec, attrValidNSec = entryAndAttrValid(backend)
+
 		for curOffset < totalEntries {
 			if curOffset < virtDotCount {
 				childInodeBasename, childDirInfo, ok = globals.virtChildDirEntryMap.getByIndex(bpVirtStart + curOffset)
@@ -2544,7 +4010,7 @@ Restart:
 					break
 				}
 				curOffset++
-				ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, entryAttrValidSec, entryAttrValidNSec, curOffset, childInodeBasename, &curReadDirPlusOutSize)
+				ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, curOffset, childInodeBasename, &curReadDirPlusOutSize)
 				if !ok {
 					globalsUnlock()
 					errno = 0
@@ -2567,11 +4033,11 @@ Restart:
 				dirEntPlus := fission.DirEntPlus{
 					EntryOut: fission.EntryOut{
 						NodeID:         bpInfo.InodeNumber,
-						Generation:     0,
-						EntryValidSec:  entryAttrValidSec,
-						AttrValidSec:   entryAttrValidSec,
-						EntryValidNSec: entryAttrValidNSec,
-						AttrValidNSec:  entryAttrValidNSec,
+						Generation:     inodeGeneration(bpInfo.InodeNumber),
+						EntryValidSec:  entryValidSec,
+						AttrValidSec:   attrValidSec,
+						EntryValidNSec: entryValidNSec,
+						AttrValidNSec:  attrValidNSec,
 						Attr: fission.Attr{
 							Ino:       bpInfo.InodeNumber,
 							Size:      bpInfo.Size,
@@ -2620,6 +4086,9 @@ Restart:
 	if backend != nil && backend.manifestPath != "" && !fh.serveFromManifest && fh.manifestEntries == nil {
 		partPath := manifestPartPath(backend.manifestPath, parentInode.objectPath)
 		manifestEntries, manifestErr := readManifestPart(partPath)
+		if manifestErr == nil {
+			manifestEntries = backend.filter.filterManifestEntries(parentInode.objectPath, manifestEntries)
+		}
 		if manifestErr == nil && len(manifestEntries) > 0 {
 			fh.serveFromManifest = true
 			fh.manifestEntries = manifestEntries
@@ -2647,7 +4116,7 @@ Restart:
 					break
 				}
 				curOffset++
-				ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, entryAttrValidSec, entryAttrValidNSec, curOffset, childInodeBasename, &curReadDirPlusOutSize)
+				ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, curOffset, childInodeBasename, &curReadDirPlusOutSize)
 				if !ok {
 					globalsUnlock()
 					errno = 0
@@ -2665,7 +4134,7 @@ Restart:
 				curOffset++
 
 				if !childInode.pendingDelete {
-					ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, entryAttrValidSec, entryAttrValidNSec, curOffset, childInodeBasename, &curReadDirPlusOutSize)
+					ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, curOffset, childInodeBasename, &curReadDirPlusOutSize)
 					if !ok {
 						globalsUnlock()
 						errno = 0
@@ -2683,15 +4152,22 @@ Restart:
 	globals.logger.Printf("[TRACE] DoReadDirPlus: serving from S3 for inode %d (objectPath=%q)", parentInode.inodeNumber, parentInode.objectPath)
 
 	if fh.listDirectoryInProgress {
-		globalsUnlock()
-		errno = syscall.EACCES
-		return
+		// Another readdir of fh awaits a listing page... so await it and start over
+
+		err = fh.awaitListDirectory(inFlightOp.ctx)
+		if err != nil {
+			globalsUnlock()
+			errno = canceledErrno(inFlightOp.ctx)
+			return
+		}
+
+		goto Restart
 	}
 
 	if curOffset < fh.prevListDirectoryOutputStartingOffset {
-		// Adjust curOffset to not try to reference before the start of fh.prevListDirectoryOutput
+		// Restart the listing from the anchor of the page containing curOffset (e.g. following a rewinddir())
 
-		curOffset = fh.prevListDirectoryOutputStartingOffset
+		fh.rewindListDirectory(curOffset)
 	}
 
 	for {
@@ -2707,29 +4183,16 @@ Restart:
 				fh.nextListDirectoryOutputFileLen = 0
 			}
 
-			if fh.prevListDirectoryOutput == nil {
-				listDirectoryInput = &listDirectoryInputStruct{
-					continuationToken: "",
-					maxItems:          backend.directoryPageSize,
-					dirPath:           parentInode.objectPath,
-				}
-			} else {
-				listDirectoryInput = &listDirectoryInputStruct{
-					continuationToken: fh.prevListDirectoryOutput.nextContinuationToken,
-					maxItems:          backend.directoryPageSize,
-					dirPath:           parentInode.objectPath,
-				}
-			}
-
-			fh.listDirectoryInProgress = true
-
-			globalsUnlock()
-
-			listDirectoryOutput, err = listDirectoryWrapper(backend.context, listDirectoryInput)
+			listDirectoryInput = fh.nextListDirectoryInput(backend, parentInode.objectPath)
 
-			globalsLock("fission.go:2730:4:(*globalsStruct).DoReadDirPlus")
+			listDirectoryOutput, ok, err = fh.fetchListDirectory(inFlightOp.ctx, backend, listDirectoryInput, readDirBudget(backend, startTime, len(readDirPlusOut.DirEntPlus)))
+			if !ok {
+				// readdir_time_budget expired... so return what we have (the remainder will follow)
 
-			fh.listDirectoryInProgress = false
+				globalsUnlock()
+				errno = 0
+				return
+			}
 
 			if err != nil {
 				globalsUnlock()
@@ -2747,11 +4210,11 @@ Restart:
 			if fh.prevListDirectoryOutput == nil {
 				fh.prevListDirectoryOutput = listDirectoryOutput
 				fh.prevListDirectoryOutputFileLen = uint64(len(listDirectoryOutput.file))
-				fh.prevListDirectoryOutputStartingOffset = 0
+				fh.prevListDirectoryOutputStartingOffset = fh.nextListDirectoryOutputStartingOffset
 
 				fh.nextListDirectoryOutput = nil
 				fh.nextListDirectoryOutputFileLen = 0
-				fh.nextListDirectoryOutputStartingOffset = fh.prevListDirectoryOutputFileLen
+				fh.nextListDirectoryOutputStartingOffset = fh.prevListDirectoryOutputStartingOffset + fh.prevListDirectoryOutputFileLen
 			} else {
 				fh.nextListDirectoryOutput = listDirectoryOutput
 				fh.nextListDirectoryOutputFileLen = uint64(len(listDirectoryOutput.file))
@@ -2820,7 +4283,7 @@ Restart:
 		curOffset++
 
 		if !childInode.pendingDelete {
-			ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, entryAttrValidSec, entryAttrValidNSec, curOffset, childInodeBasename, &curReadDirPlusOutSize)
+			ok = childInode.appendToReadDirPlusOut(uint64(readDirPlusIn.Size), readDirPlusOut, curOffset, childInodeBasename, &curReadDirPlusOutSize)
 			if !ok {
 				globalsUnlock()
 				errno = 0
@@ -2830,17 +4293,159 @@ Restart:
 	}
 }
 
-// `DoRename2` implements the package fission callback to rename a directory entry (not supported).
+// `DoRename2` implements the package fission callback to rename a directory entry as
+// is done by DoRename(). Of the supported flags, only RENAME_NOREPLACE is honored.
 func (*globalsStruct) DoRename2(inHeader *fission.InHeader, rename2In *fission.Rename2In) (errno syscall.Errno) {
-	errno = syscall.EXDEV
+	var (
+		backend    *backendStruct
+		inFlightOp = beginInFlightOp("Rename2", inHeader)
+		latency    float64
+		startTime  = time.Now()
+	)
+
+	defer endInFlightOp(inFlightOp, &errno)
+
+	// Record metrics on function exit
+	defer func() {
+		latency = time.Since(startTime).Seconds()
+		globalsLock("fission.go:4311:3:funcLit@4309")
+		if errno == 0 {
+			globals.fissionMetrics.RenameSuccesses.Inc()
+			globals.fissionMetrics.RenameSuccessLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.RenameSuccesses.Inc()
+				backend.fissionMetrics.RenameSuccessLatencies.Observe(latency)
+			}
+		} else {
+			globals.fissionMetrics.RenameFailures.Inc()
+			globals.fissionMetrics.RenameFailureLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.RenameFailures.Inc()
+				backend.fissionMetrics.RenameFailureLatencies.Observe(latency)
+			}
+		}
+		globalsUnlock()
+	}()
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	if (rename2In.Flags &^ RenameNoReplace) != 0 {
+		// Neither RENAME_EXCHANGE nor RENAME_WHITEOUT are supported
+		errno = syscall.EINVAL
+		return
+	}
+
+	backend, errno = renameFileObject(inFlightOp.ctx, inHeader.NodeID, string(rename2In.OldName), rename2In.NewDir, string(rename2In.NewName), (rename2In.Flags&RenameNoReplace) != 0)
+
 	return
 }
 
 // `DoLSeek` implements the package fission callback to fetch the offset of the start
 // of the next sequence of data or the next "hole" in data of the content of a file
-// inode (not supported).
+// inode. As objects are never sparse, the entire content is reported as data followed
+// only by the implicit "hole" at EOF.
 func (*globalsStruct) DoLSeek(inHeader *fission.InHeader, lSeekIn *fission.LSeekIn) (lSeekOut *fission.LSeekOut, errno syscall.Errno) {
-	errno = syscall.ENOSYS
+	var (
+		backend   *backendStruct
+		inode     *inodeStruct
+		latency   float64
+		ok        bool
+		startTime = time.Now()
+	)
+
+	defer endInFlightOp(beginInFlightOp("LSeek", inHeader), &errno)
+
+	defer func() {
+		latency = time.Since(startTime).Seconds()
+		globalsLock("fission.go:4363:3:funcLit@4361")
+		if errno == 0 {
+			globals.fissionMetrics.LSeekSuccesses.Inc()
+			globals.fissionMetrics.LSeekSuccessLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.LSeekSuccesses.Inc()
+				backend.fissionMetrics.LSeekSuccessLatencies.Observe(latency)
+			}
+		} else {
+			globals.fissionMetrics.LSeekFailures.Inc()
+			globals.fissionMetrics.LSeekFailureLatencies.Observe(latency)
+			if backend != nil {
+				backend.fissionMetrics.LSeekFailures.Inc()
+				backend.fissionMetrics.LSeekFailureLatencies.Observe(latency)
+			}
+		}
+		globalsUnlock()
+	}()
+
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsLock("fission.go:4387:2:(*globalsStruct).DoLSeek")
+
+	inode, ok = globals.inodeMap.get(inHeader.NodeID)
+	if !ok {
+		backend = nil
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if inode.backendNonce == 0 {
+		backend = nil
+	} else {
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce]")
+		}
+	}
+
+	if inode.inodeType != FileObject {
+		globalsUnlock()
+		errno = syscall.EINVAL
+		return
+	}
+
+	_, ok = inode.fhSet[lSeekIn.FH]
+	if !ok {
+		globalsUnlock()
+		errno = syscall.EBADF
+		return
+	}
+
+	switch lSeekIn.Whence {
+	case LSeekData:
+		if lSeekIn.Offset >= inode.sizeInMemory {
+			globalsUnlock()
+			errno = syscall.ENXIO
+			return
+		}
+		lSeekOut = &fission.LSeekOut{
+			Offset: lSeekIn.Offset,
+		}
+	case LSeekHole:
+		if lSeekIn.Offset >= inode.sizeInMemory {
+			globalsUnlock()
+			errno = syscall.ENXIO
+			return
+		}
+		lSeekOut = &fission.LSeekOut{
+			Offset: inode.sizeInMemory,
+		}
+	default:
+		// SEEK_SET, SEEK_CUR, and SEEK_END are handled by the kernel itself
+		globalsUnlock()
+		errno = syscall.EINVAL
+		return
+	}
+
+	globalsUnlock()
+
+	errno = 0
 	return
 }
 
@@ -2853,42 +4458,31 @@ func (*globalsStruct) DoStatX(inHeader *fission.InHeader, statXIn *fission.StatX
 		attrValidSec  uint64
 		backend       *backendStruct
 		gid           uint32
-		latency       float64
-		mTimeNSec     uint32
-		mTimeSec      uint64
 		ok            bool
 		startTime     = time.Now()
 		thisInode     *inodeStruct
 		uid           uint32
 	)
 
+	defer endInFlightOp(beginInFlightOp("StatX", inHeader), &errno)
+
 	defer func() {
-		latency = time.Since(startTime).Seconds()
-		globalsLock("fission.go:2867:3:funcLit@2865")
-		if errno == 0 {
-			globals.fissionMetrics.StatXSuccesses.Inc()
-			globals.fissionMetrics.StatXSuccessLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.StatXSuccesses.Inc()
-				backend.fissionMetrics.StatXSuccessLatencies.Observe(latency)
-			}
-		} else {
-			globals.fissionMetrics.StatXFailures.Inc()
-			globals.fissionMetrics.StatXFailureLatencies.Observe(latency)
-			if backend != nil {
-				backend.fissionMetrics.StatXFailures.Inc()
-				backend.fissionMetrics.StatXFailureLatencies.Observe(latency)
-			}
-		}
-		globalsUnlock()
+		observeFissionOp(backend, errno, startTime, func(fissionMetrics *fissionMetricsStruct) fissionOpMetricsStruct {
+			return fissionOpMetricsStruct{fissionMetrics.StatXSuccesses, fissionMetrics.StatXFailures, fissionMetrics.StatXSuccessLatencies, fissionMetrics.StatXFailureLatencies}
+		})
 	}()
 
-	globalsLock("fission.go:2886:2:(*globalsStruct).DoStatX")
+	errno = backendACLErrno(inHeader)
+	if errno != 0 {
+		return
+	}
+
+	globalsRLock()
 
 	thisInode, ok = globals.inodeMap.get(inHeader.NodeID)
 	if !ok {
 		backend = nil
-		globalsUnlock()
+		globalsRUnlock()
 		errno = syscall.ENOENT
 		return
 	}
@@ -2904,12 +4498,12 @@ func (*globalsStruct) DoStatX(inHeader *fission.InHeader, statXIn *fission.StatX
 	}
 
 	if thisInode.pendingDelete {
-		globalsUnlock()
+		globalsRUnlock()
 		errno = syscall.ENOENT
 		return
 	}
 
-	thisInode.touch(nil)
+	globals.inodeMap.deferTouch(thisInode.inodeNumber, time.Time{})
 
 	switch thisInode.inodeType {
 	case FileObject:
@@ -2929,8 +4523,7 @@ func (*globalsStruct) DoStatX(inHeader *fission.InHeader, statXIn *fission.StatX
 		globals.logger.Fatalf("[FATAL] unrecognized inodeType (%v)", thisInode.inodeType)
 	}
 
-	attrValidSec, attrValidNSec = timeDurationToAttrDuration(globals.config.entryAttrTTL)
-	mTimeSec, mTimeNSec = timeTimeToAttrTime(thisInode.mTime)
+	_, _, attrValidSec, attrValidNSec = entryAndAttrValid(backend)
 
 	statXOut = &fission.StatXOut{
 		AttrValidSec:  attrValidSec,
@@ -2947,36 +4540,17 @@ func (*globalsStruct) DoStatX(inHeader *fission.InHeader, statXIn *fission.StatX
 			Ino:            thisInode.inodeNumber,
 			Size:           thisInode.sizeInMemory,
 			AttributesMask: 0,
-			ATime: fission.SXTime{
-				TVSec:    mTimeSec,
-				TVNSec:   mTimeNSec,
-				Reserved: 0,
-			},
-			BTime: fission.SXTime{
-				TVSec:    mTimeSec,
-				TVNSec:   mTimeNSec,
-				Reserved: 0,
-			},
-			CTime: fission.SXTime{
-				TVSec:    mTimeSec,
-				TVNSec:   mTimeNSec,
-				Reserved: 0,
-			},
-			MTime: fission.SXTime{
-				TVSec:    mTimeSec,
-				TVNSec:   mTimeNSec,
-				Reserved: 0,
-			},
-			RDevMajor: 0,
-			RDevMinor: 0,
-			DevMajor:  0,
-			DevMinor:  0,
-			Spare2:    [14]uint64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			RDevMajor:      0,
+			RDevMinor:      0,
+			DevMajor:       0,
+			DevMinor:       0,
+			Spare2:         [14]uint64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
 		},
 	}
 	fixStatXSizes(&statXOut.StatX)
+	fixStatXTimes(&statXOut.StatX, thisInode)
 
-	globalsUnlock()
+	globalsRUnlock()
 
 	errno = 0
 	return
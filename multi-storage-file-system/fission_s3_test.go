@@ -0,0 +1,737 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/fission/v4"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	testFissionS3Bucket        = "test"
+	testFissionS3CacheLineSize = uint64(64 * 1024)
+	testFissionS3FileBLen      = 3*testFissionS3CacheLineSize + 123
+)
+
+var (
+	testFissionS3FileBContent []byte
+)
+
+// `fissionS3TestUp` is the analog of fissionTestUp() establishing a single "s3" backend
+// served by testGlobals.testS3Server and populated with:
+//
+//	├── dir1
+//	│   └── fileC (containing "/dir1/fileC\n")
+//	├── fileA (containing "/fileA\n")
+//	└── fileB (containing testFissionS3FileBLen random bytes)
+func fissionS3TestUp(t *testing.T) {
+	fissionS3TestUpWithS3Settings(t, "")
+}
+
+// `fissionS3TestUpWithS3Settings` is fissionS3TestUp() with additional S3 section settings
+// (each a JSON `"key": value` pair followed by a comma) applied to the "s3" backend.
+func fissionS3TestUpWithS3Settings(t *testing.T, s3Settings string) {
+	fissionS3TestUpWithSettings(t, `
+				"bucket_container_name": "`+testFissionS3Bucket+`",`, s3Settings)
+}
+
+// `fissionS3TestUpWithSettings` is fissionS3TestUpWithS3Settings() with the "s3" backend's
+// bucket_container_name setting replaced by backendSettings (in the same form as s3Settings).
+func fissionS3TestUpWithSettings(t *testing.T, backendSettings string, s3Settings string) {
+	fissionS3TestUpWithBackends(t, backendSettings, s3Settings, "")
+}
+
+// `fissionS3TestUpWithBackends` is fissionS3TestUpWithSettings() with additional backends
+// (each a JSON object preceded by a comma) following the "s3" backend.
+func fissionS3TestUpWithBackends(t *testing.T, backendSettings string, s3Settings string, otherBackends string) {
+	var (
+		err                 error
+		fissionVolumeConfig *fission.VolumeConfig
+	)
+
+	testGlobals.testS3Server.reset()
+
+	testFissionS3FileBContent = make([]byte, testFissionS3FileBLen)
+	_, err = rand.Read(testFissionS3FileBContent)
+	if err != nil {
+		t.Fatalf("rand.Read(testFissionS3FileBContent) failed: %v", err)
+	}
+
+	testGlobals.testS3Server.putObject(testFissionS3Bucket, "dir1/fileC", []byte("/dir1/fileC\n"))
+	testGlobals.testS3Server.putObject(testFissionS3Bucket, "fileA", []byte("/fileA\n"))
+	testGlobals.testS3Server.putObject(testFissionS3Bucket, "fileB", testFissionS3FileBContent)
+
+	err = os.Setenv("MSFS_MOUNTPOINT", testGlobals.testMountPoint)
+	if err != nil {
+		t.Fatalf("os.Setenv(\"MSFS_MOUNTPOINT\", testGlobals.testMountPoint) failed: %v", err)
+	}
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".json"]))
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+	{
+		"msfs_version": 1,
+		"cache_line_size": `+strconv.FormatUint(testFissionS3CacheLineSize, 10)+`,
+		"backends": [
+			{
+				"dir_name": "s3",`+backendSettings+`
+				"backend_type": "S3",
+				"readonly": false,
+				"S3": {`+s3Settings+`
+					"region": "us-east-1",
+					"endpoint": "`+testGlobals.testS3Server.endpoint()+`",
+					"access_key_id": "test",
+					"secret_access_key": "test",
+					"retry_base_delay": 1,
+					"retry_max_delay": 10
+				}
+			}`+otherBackends+`
+		]
+	}
+	`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	err = checkConfigFile()
+	if err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	initFS()
+
+	processToMountList()
+
+	fissionVolumeConfig = &fission.VolumeConfig{
+		VolumeName:         globals.config.mountName,
+		MountpointDirPath:  globals.config.mountPoint,
+		FuseSubtype:        fuseSubtype,
+		MaxRead:            maxRead,
+		MaxWrite:           maxWrite,
+		DefaultPermissions: true,
+		AllowOther:         globals.config.allowOther,
+		NumWorkers:         int(globals.config.fuseWorkers),
+		Callbacks:          &globals,
+		Logger:             globals.logger,
+		ErrChan:            globals.errChan,
+	}
+
+	globals.fissionVolume = fission.NewVolume(fissionVolumeConfig)
+}
+
+func fissionS3TestDown(_ *testing.T) {
+	drainFS()
+
+	testGlobals.testS3Server.reset()
+}
+
+// `fissionS3TestLookup` performs a DoLookup() of name in the directory inode parentIno.
+func fissionS3TestLookup(t *testing.T, parentIno uint64, name string) (ino uint64) {
+	var (
+		errno     syscall.Errno
+		lookupOut *fission.LookupOut
+	)
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: parentIno}, &fission.LookupIn{Name: []byte(name)})
+	if errno != 0 {
+		t.Fatalf("DoLookup(%v,Name:\"%s\") unexpectedly failed (errno: %v)", parentIno, name, errno)
+	}
+
+	ino = lookupOut.EntryOut.NodeID
+	return
+}
+
+// TestFissionS3RoundTrip exercises lookups, multi-cache-line reads, renames (CopyObject +
+// DeleteObject), and unlinks of an S3 backend served by the in-process test S3 server.
+func TestFissionS3RoundTrip(t *testing.T) {
+	var (
+		content     []byte
+		dir1Ino     uint64
+		errno       syscall.Errno
+		fileBFH     uint64
+		fileBIno    uint64
+		fileBOffset uint64
+		ok          bool
+		openOut     *fission.OpenOut
+		readOut     *fission.ReadOut
+		s3DirIno    uint64
+	)
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+	dir1Ino = fissionS3TestLookup(t, s3DirIno, "dir1")
+	_ = fissionS3TestLookup(t, dir1Ino, "fileC")
+	fileBIno = fissionS3TestLookup(t, s3DirIno, "fileB")
+
+	openOut, errno = globals.DoOpen(&fission.InHeader{NodeID: fileBIno}, &fission.OpenIn{Flags: fission.FOpenRequestRDONLY})
+	if errno != 0 {
+		t.Fatalf("DoOpen(fileBIno) unexpectedly failed (errno: %v)", errno)
+	}
+	fileBFH = openOut.FH
+
+	for fileBOffset = 0; fileBOffset < testFissionS3FileBLen; fileBOffset += uint64(len(readOut.Data)) {
+		readOut, errno = globals.DoRead(&fission.InHeader{NodeID: fileBIno}, &fission.ReadIn{FH: fileBFH, Offset: fileBOffset, Size: testFissionReadBufSize})
+		if errno != 0 {
+			t.Fatalf("DoRead(fileBFH, Offset: %v) unexpectedly failed (errno: %v)", fileBOffset, errno)
+		}
+		if len(readOut.Data) == 0 {
+			t.Fatalf("DoRead(fileBFH, Offset: %v) unexpectedly returned no data", fileBOffset)
+		}
+		if !bytes.Equal(readOut.Data, testFissionS3FileBContent[fileBOffset:fileBOffset+uint64(len(readOut.Data))]) {
+			t.Fatalf("DoRead(fileBFH, Offset: %v) returned mismatched bytes", fileBOffset)
+		}
+	}
+
+	errno = globals.DoRelease(&fission.InHeader{NodeID: fileBIno}, &fission.ReleaseIn{FH: fileBFH})
+	if errno != 0 {
+		t.Fatalf("DoRelease(fileBFH) unexpectedly failed (errno: %v)", errno)
+	}
+
+	// Rename s3/fileA to s3/dir1/fileD
+
+	errno = globals.DoRename(&fission.InHeader{NodeID: s3DirIno}, &fission.RenameIn{NewDir: dir1Ino, OldName: []byte("fileA"), NewName: []byte("fileD")})
+	if errno != 0 {
+		t.Fatalf("DoRename(s3Dir,\"fileA\",dir1,\"fileD\") unexpectedly failed (errno: %v)", errno)
+	}
+
+	_, ok = testGlobals.testS3Server.getObject(testFissionS3Bucket, "fileA")
+	if ok {
+		t.Fatalf("fileA still exists in test S3 server after rename")
+	}
+	content, ok = testGlobals.testS3Server.getObject(testFissionS3Bucket, "dir1/fileD")
+	if !ok || (string(content) != "/fileA\n") {
+		t.Fatalf("dir1/fileD missing or unexpected in test S3 server after rename")
+	}
+
+	// Unlink s3/dir1/fileD
+
+	errno = globals.DoUnlink(&fission.InHeader{NodeID: dir1Ino}, &fission.UnlinkIn{Name: []byte("fileD")})
+	if errno != 0 {
+		t.Fatalf("DoUnlink(dir1,\"fileD\") unexpectedly failed (errno: %v)", errno)
+	}
+
+	_, ok = testGlobals.testS3Server.getObject(testFissionS3Bucket, "dir1/fileD")
+	if ok {
+		t.Fatalf("dir1/fileD still exists in test S3 server after unlink")
+	}
+}
+
+// TestFissionS3InjectedFaults verifies that injected 5xx responses are retried and that
+// injected slow responses are awaited.
+func TestFissionS3InjectedFaults(t *testing.T) {
+	var (
+		requestCountBefore uint64
+		s3DirIno           uint64
+		startTime          time.Time
+	)
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+
+	testGlobals.testS3Server.putObject(testFissionS3Bucket, "fileE", []byte("/fileE\n"))
+	testGlobals.testS3Server.putObject(testFissionS3Bucket, "fileF", []byte("/fileF\n"))
+
+	requestCountBefore = testGlobals.testS3Server.requestCount()
+	testGlobals.testS3Server.injectFaults(2, 500, 0)
+
+	_ = fissionS3TestLookup(t, s3DirIno, "fileE")
+
+	if testGlobals.testS3Server.requestCount() < requestCountBefore+3 {
+		t.Fatalf("DoLookup(s3Dir,\"fileE\") should have required at least 3 requests (2 of them retried) but took %v", testGlobals.testS3Server.requestCount()-requestCountBefore)
+	}
+
+	testGlobals.testS3Server.injectFaults(1, 0, 50*time.Millisecond)
+
+	startTime = time.Now()
+	_ = fissionS3TestLookup(t, s3DirIno, "fileF")
+	if time.Since(startTime) < 50*time.Millisecond {
+		t.Fatalf("DoLookup(s3Dir,\"fileF\") returned before the injected slow response could have arrived")
+	}
+}
+
+// TestS3BackendMultipartUpload drives a multipart upload (mixing UploadPart and UploadPartCopy)
+// against the in-process test S3 server via the S3 backend's client.
+func TestS3BackendMultipartUpload(t *testing.T) {
+	var (
+		completeOutput *s3.CompleteMultipartUploadOutput
+		content        []byte
+		createOutput   *s3.CreateMultipartUploadOutput
+		err            error
+		ok             bool
+		s3Context      *s3ContextStruct
+		uploadOutput   *s3.UploadPartOutput
+		uploadCopyOut  *s3.UploadPartCopyOutput
+	)
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	s3Context = globals.config.backends["s3"].context.(*s3ContextStruct)
+
+	createOutput, err = s3Context.s3Client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(testFissionS3Bucket),
+		Key:    aws.String("fileMPU"),
+	})
+	if err != nil {
+		t.Fatalf("CreateMultipartUpload() failed: %v", err)
+	}
+
+	uploadOutput, err = s3Context.s3Client.UploadPart(context.Background(), &s3.UploadPartInput{
+		Bucket:     aws.String(testFissionS3Bucket),
+		Body:       bytes.NewReader([]byte("part1:")),
+		Key:        aws.String("fileMPU"),
+		PartNumber: aws.Int32(1),
+		UploadId:   createOutput.UploadId,
+	})
+	if err != nil {
+		t.Fatalf("UploadPart() failed: %v", err)
+	}
+
+	uploadCopyOut, err = s3Context.s3Client.UploadPartCopy(context.Background(), &s3.UploadPartCopyInput{
+		Bucket:          aws.String(testFissionS3Bucket),
+		CopySource:      aws.String(testFissionS3Bucket + "/dir1/fileC"),
+		CopySourceRange: aws.String("bytes=1-4"),
+		Key:             aws.String("fileMPU"),
+		PartNumber:      aws.Int32(2),
+		UploadId:        createOutput.UploadId,
+	})
+	if err != nil {
+		t.Fatalf("UploadPartCopy() failed: %v", err)
+	}
+
+	completeOutput, err = s3Context.s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+		Bucket: aws.String(testFissionS3Bucket),
+		Key:    aws.String("fileMPU"),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: []types.CompletedPart{
+			{ETag: uploadOutput.ETag, PartNumber: aws.Int32(1)},
+			{ETag: uploadCopyOut.CopyPartResult.ETag, PartNumber: aws.Int32(2)},
+		}},
+		UploadId: createOutput.UploadId,
+	})
+	if err != nil {
+		t.Fatalf("CompleteMultipartUpload() failed: %v", err)
+	}
+	if (completeOutput.ETag == nil) || !bytes.HasSuffix([]byte(*completeOutput.ETag), []byte("-2\"")) {
+		t.Fatalf("CompleteMultipartUpload() returned unexpected ETag")
+	}
+
+	content, ok = testGlobals.testS3Server.getObject(testFissionS3Bucket, "fileMPU")
+	if !ok || (string(content) != "part1:dir1") {
+		t.Fatalf("fileMPU missing or unexpected (%q) in test S3 server", content)
+	}
+}
+
+// TestS3BackendRequesterPays verifies that requester_pays and request_headers are sent with
+// each request to a bucket insisting upon them.
+func TestS3BackendRequesterPays(t *testing.T) {
+	var (
+		backend        *backendStruct
+		err            error
+		readFileOutput *readFileOutputStruct
+	)
+
+	fissionS3TestUpWithS3Settings(t, `
+					"requester_pays": true,
+					"request_headers": {"x-amz-expected-bucket-owner": "123456789012"},`)
+
+	testGlobals.testS3Server.requireHeader("X-Amz-Request-Payer", "requester")
+	testGlobals.testS3Server.requireHeader("X-Amz-Expected-Bucket-Owner", "123456789012")
+
+	backend = globals.config.backends["s3"]
+
+	if backend.backendTypeSpecifics.(*backendConfigS3Struct).requestHeaders["X-Amz-Expected-Bucket-Owner"] != "123456789012" {
+		t.Fatalf("request_headers should have been parsed with canonicalized header names")
+	}
+
+	_, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "fileA"})
+	if err != nil {
+		t.Fatalf("statFile(\"fileA\") failed: %v", err)
+	}
+
+	_, err = backend.context.listDirectory(context.Background(), &listDirectoryInputStruct{dirPath: ""})
+	if err != nil {
+		t.Fatalf("listDirectory(\"\") failed: %v", err)
+	}
+
+	readFileOutput, err = backend.context.readFile(context.Background(), &readFileInputStruct{filePath: "fileA", offset: 0, length: 7})
+	if err != nil {
+		t.Fatalf("readFile(\"fileA\") failed: %v", err)
+	}
+	if string(readFileOutput.buf) != "/fileA\n" {
+		t.Fatalf("readFile(\"fileA\") returned %q", readFileOutput.buf)
+	}
+
+	fissionS3TestDown(t)
+
+	// Without the settings, the same bucket refuses access
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	testGlobals.testS3Server.requireHeader("X-Amz-Request-Payer", "requester")
+
+	_, err = globals.config.backends["s3"].context.statFile(context.Background(), &statFileInputStruct{filePath: "fileA"})
+	if err == nil {
+		t.Fatalf("statFile(\"fileA\") should have failed without requester_pays")
+	}
+
+	// Overriding signature-related headers is rejected
+
+	_, err = parseRequestHeaders(map[string]interface{}{"request_headers": map[string]interface{}{"authorization": "x"}})
+	if err == nil {
+		t.Fatalf("parseRequestHeaders() should have rejected an Authorization header")
+	}
+}
+
+// TestS3BackendSSE verifies that an SSE-C key accompanies both reads and writes and that
+// SSE-KMS settings are translated into their request fields.
+func TestS3BackendSSE(t *testing.T) {
+	var (
+		backend        *backendStruct
+		err            error
+		readFileOutput *readFileOutputStruct
+		sse            s3SSEStruct
+		sseCKey        = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{0x5A}, 32))
+		sseCKeyMD5     = md5.Sum(bytes.Repeat([]byte{0x5A}, 32))
+	)
+
+	fissionS3TestUpWithS3Settings(t, `
+					"sse_type": "customer-key",
+					"sse_c_key": "`+sseCKey+`",`)
+	defer fissionS3TestDown(t)
+
+	testGlobals.testS3Server.requireHeader("X-Amz-Server-Side-Encryption-Customer-Algorithm", "AES256")
+	testGlobals.testS3Server.requireHeader("X-Amz-Server-Side-Encryption-Customer-Key-Md5", base64.StdEncoding.EncodeToString(sseCKeyMD5[:]))
+
+	backend = globals.config.backends["s3"]
+
+	_, err = backend.context.statFile(context.Background(), &statFileInputStruct{filePath: "fileA"})
+	if err != nil {
+		t.Fatalf("statFile(\"fileA\") failed: %v", err)
+	}
+
+	readFileOutput, err = backend.context.readFile(context.Background(), &readFileInputStruct{filePath: "fileA", offset: 0, length: 7})
+	if (err != nil) || (string(readFileOutput.buf) != "/fileA\n") {
+		t.Fatalf("readFile(\"fileA\") failed: %v", err)
+	}
+
+	_, err = backend.context.putFile(context.Background(), &putFileInputStruct{filePath: "fileG", buf: []byte("/fileG\n")})
+	if err != nil {
+		t.Fatalf("putFile(\"fileG\") failed: %v", err)
+	}
+
+	_, err = decodeSSECKey(base64.StdEncoding.EncodeToString([]byte("too short")))
+	if err == nil {
+		t.Fatalf("decodeSSECKey() should have rejected a key that is not 256 bits")
+	}
+
+	sse = newS3SSE(&backendConfigS3Struct{sseType: s3SSETypeKMS, kmsKeyID: "alias/test"})
+	if (sse.serverSideEncryption != types.ServerSideEncryptionAwsKms) || (sse.kmsKeyID == nil) || (*sse.kmsKeyID != "alias/test") || (sse.customerKey != nil) {
+		t.Fatalf("newS3SSE() returned unexpected SSE-KMS fields: %+v", sse)
+	}
+}
+
+// TestS3BackendArchivedObjects verifies that reads of archived (e.g. GLACIER) objects fail with
+// EREMOTEIO (or, when restore_archived_objects is set, request a restore and fail with EAGAIN
+// until it completes) and that the restore status is exposed as an extended attribute.
+func TestS3BackendArchivedObjects(t *testing.T) {
+	var (
+		errno       syscall.Errno
+		fileAFH     uint64
+		fileAIno    uint64
+		getXAttrOut *fission.GetXAttrOut
+		openOut     *fission.OpenOut
+		readOut     *fission.ReadOut
+		restore     string
+		s3DirIno    uint64
+	)
+
+	if s3RestoreStatus(types.StorageClassStandard, "", nil) != "" {
+		t.Fatalf("s3RestoreStatus(STANDARD) should have returned \"\"")
+	}
+	if s3RestoreStatus(types.StorageClassDeepArchive, "", nil) != restoreStatusArchived {
+		t.Fatalf("s3RestoreStatus(DEEP_ARCHIVE) should have returned \"%s\"", restoreStatusArchived)
+	}
+	if s3RestoreStatus(types.StorageClassIntelligentTiering, types.ArchiveStatusArchiveAccess, nil) != restoreStatusArchived {
+		t.Fatalf("s3RestoreStatus(INTELLIGENT_TIERING,ARCHIVE_ACCESS) should have returned \"%s\"", restoreStatusArchived)
+	}
+	if s3RestoreStatus(types.StorageClassGlacier, "", aws.String(`ongoing-request="true"`)) != restoreStatusOngoing {
+		t.Fatalf("s3RestoreStatus(GLACIER,ongoing-request=\"true\") should have returned \"%s\"", restoreStatusOngoing)
+	}
+	if s3RestoreStatus(types.StorageClassGlacier, "", aws.String(`ongoing-request="false", expiry-date="Fri, 21 Dec 2035 00:00:00 GMT"`)) != restoreStatusRestored {
+		t.Fatalf("s3RestoreStatus(GLACIER,ongoing-request=\"false\") should have returned \"%s\"", restoreStatusRestored)
+	}
+
+	// Without restore_archived_objects, reads of an archived object fail with EREMOTEIO
+
+	fissionS3TestUp(t)
+
+	testGlobals.testS3Server.archiveObject(testFissionS3Bucket, "fileA", "GLACIER")
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+	fileAIno = fissionS3TestLookup(t, s3DirIno, "fileA")
+
+	openOut, errno = globals.DoOpen(&fission.InHeader{NodeID: fileAIno}, &fission.OpenIn{Flags: fission.FOpenRequestRDONLY})
+	if errno != 0 {
+		t.Fatalf("DoOpen(fileAIno) unexpectedly failed (errno: %v)", errno)
+	}
+	fileAFH = openOut.FH
+
+	_, errno = globals.DoRead(&fission.InHeader{NodeID: fileAIno}, &fission.ReadIn{FH: fileAFH, Offset: 0, Size: testFissionReadBufSize})
+	if errno != syscall.EREMOTEIO {
+		t.Fatalf("DoRead(fileAFH) of archived object returned errno: %v (expected: EREMOTEIO)", errno)
+	}
+
+	restore, _ = testGlobals.testS3Server.objectRestore(testFissionS3Bucket, "fileA")
+	if restore != "" {
+		t.Fatalf("DoRead(fileAFH) should not have requested a restore (x-amz-restore: %s)", restore)
+	}
+
+	getXAttrOut, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.GetXAttrIn{Size: 4096, Name: []byte(XAttrNameStorageClass)})
+	if (errno != 0) || (string(getXAttrOut.Data) != "GLACIER") {
+		t.Fatalf("DoGetXAttr(fileAIno,Name:\"%s\") returned unexpected value (errno: %v)", XAttrNameStorageClass, errno)
+	}
+
+	getXAttrOut, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.GetXAttrIn{Size: 4096, Name: []byte(XAttrNameRestoreStatus)})
+	if (errno != 0) || (string(getXAttrOut.Data) != restoreStatusArchived) {
+		t.Fatalf("DoGetXAttr(fileAIno,Name:\"%s\") returned unexpected value (errno: %v)", XAttrNameRestoreStatus, errno)
+	}
+
+	errno = globals.DoRelease(&fission.InHeader{NodeID: fileAIno}, &fission.ReleaseIn{FH: fileAFH})
+	if errno != 0 {
+		t.Fatalf("DoRelease(fileAFH) unexpectedly failed (errno: %v)", errno)
+	}
+
+	fissionS3TestDown(t)
+
+	// With restore_archived_objects, reads request a restore and fail with EAGAIN until it completes
+
+	fissionS3TestUpWithS3Settings(t, `
+					"restore_archived_objects": true,
+					"restore_tier": "Bulk",`)
+	defer fissionS3TestDown(t)
+
+	testGlobals.testS3Server.archiveObject(testFissionS3Bucket, "fileA", "DEEP_ARCHIVE")
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+	fileAIno = fissionS3TestLookup(t, s3DirIno, "fileA")
+
+	openOut, errno = globals.DoOpen(&fission.InHeader{NodeID: fileAIno}, &fission.OpenIn{Flags: fission.FOpenRequestRDONLY})
+	if errno != 0 {
+		t.Fatalf("DoOpen(fileAIno) unexpectedly failed (errno: %v)", errno)
+	}
+	fileAFH = openOut.FH
+
+	_, errno = globals.DoRead(&fission.InHeader{NodeID: fileAIno}, &fission.ReadIn{FH: fileAFH, Offset: 0, Size: testFissionReadBufSize})
+	if errno != syscall.EAGAIN {
+		t.Fatalf("DoRead(fileAFH) of archived object returned errno: %v (expected: EAGAIN)", errno)
+	}
+
+	restore, _ = testGlobals.testS3Server.objectRestore(testFissionS3Bucket, "fileA")
+	if restore != `ongoing-request="true"` {
+		t.Fatalf("DoRead(fileAFH) should have requested a restore (x-amz-restore: %s)", restore)
+	}
+
+	getXAttrOut, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.GetXAttrIn{Size: 4096, Name: []byte(XAttrNameRestoreStatus)})
+	if (errno != 0) || (string(getXAttrOut.Data) != restoreStatusOngoing) {
+		t.Fatalf("DoGetXAttr(fileAIno,Name:\"%s\") returned unexpected value (errno: %v)", XAttrNameRestoreStatus, errno)
+	}
+
+	_, errno = globals.DoRead(&fission.InHeader{NodeID: fileAIno}, &fission.ReadIn{FH: fileAFH, Offset: 0, Size: testFissionReadBufSize})
+	if errno != syscall.EAGAIN {
+		t.Fatalf("DoRead(fileAFH) of object being restored returned errno: %v (expected: EAGAIN)", errno)
+	}
+
+	testGlobals.testS3Server.completeRestore(testFissionS3Bucket, "fileA")
+
+	readOut, errno = globals.DoRead(&fission.InHeader{NodeID: fileAIno}, &fission.ReadIn{FH: fileAFH, Offset: 0, Size: testFissionReadBufSize})
+	if (errno != 0) || (string(readOut.Data) != "/fileA\n") {
+		t.Fatalf("DoRead(fileAFH) of restored object failed (errno: %v)", errno)
+	}
+
+	errno = globals.DoRelease(&fission.InHeader{NodeID: fileAIno}, &fission.ReleaseIn{FH: fileAFH})
+	if errno != 0 {
+		t.Fatalf("DoRelease(fileAFH) unexpectedly failed (errno: %v)", errno)
+	}
+}
+
+// TestFissionS3CopyTo verifies that setting the user.msc.copy_to extended attribute of a file
+// copies its object server-side both within its S3 backend and to another S3 backend sharing
+// the same endpoint, but not to a backend of another type.
+func TestFissionS3CopyTo(t *testing.T) {
+	var (
+		content  []byte
+		err      error
+		errno    syscall.Errno
+		fileAIno uint64
+		now      = time.Now()
+		ok       bool
+		s3DirIno uint64
+	)
+
+	fissionS3TestUpWithBackends(t, `
+				"bucket_container_name": "`+testFissionS3Bucket+`",`, "", `,
+			{
+				"dir_name": "s3b",
+				"bucket_container_name": "other",
+				"backend_type": "S3",
+				"readonly": false,
+				"S3": {
+					"region": "us-east-1",
+					"endpoint": "`+testGlobals.testS3Server.endpoint()+`",
+					"access_key_id": "test",
+					"secret_access_key": "test",
+					"retry_base_delay": 1,
+					"retry_max_delay": 10
+				}
+			},
+			{
+				"dir_name": "ram",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM",
+				"readonly": false
+			}`)
+	defer fissionS3TestDown(t)
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+	fileAIno = fissionS3TestLookup(t, s3DirIno, "fileA")
+
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.SetXAttrIn{Name: []byte(XAttrNameCopyTo), Data: []byte("s3/dir1/fileA.copy")})
+	if errno != 0 {
+		t.Fatalf("DoSetXAttr(fileAIno,\"s3/dir1/fileA.copy\") unexpectedly failed (errno: %v)", errno)
+	}
+	content, ok = testGlobals.testS3Server.getObject(testFissionS3Bucket, "dir1/fileA.copy")
+	if !ok || (string(content) != "/fileA\n") {
+		t.Fatalf("DoSetXAttr(fileAIno,\"s3/dir1/fileA.copy\") did not copy fileA")
+	}
+
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.SetXAttrIn{Name: []byte(XAttrNameCopyTo), Data: []byte(globals.config.mountPoint + "/s3b/fileA")})
+	if errno != 0 {
+		t.Fatalf("DoSetXAttr(fileAIno,\"<mountPoint>/s3b/fileA\") unexpectedly failed (errno: %v)", errno)
+	}
+	content, ok = testGlobals.testS3Server.getObject("other", "fileA")
+	if !ok || (string(content) != "/fileA\n") {
+		t.Fatalf("DoSetXAttr(fileAIno,\"<mountPoint>/s3b/fileA\") did not copy fileA")
+	}
+
+	// Copies that cannot be performed server-side (or at all) fail
+
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.SetXAttrIn{Name: []byte(XAttrNameCopyTo), Data: []byte("ram/fileA")})
+	if errno != syscall.EXDEV {
+		t.Fatalf("DoSetXAttr(fileAIno,\"ram/fileA\") returned errno %v (expected: EXDEV)", errno)
+	}
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.SetXAttrIn{Name: []byte(XAttrNameCopyTo), Data: []byte("s3/../fileA")})
+	if errno != syscall.EINVAL {
+		t.Fatalf("DoSetXAttr(fileAIno,\"s3/../fileA\") returned errno %v (expected: EINVAL)", errno)
+	}
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.SetXAttrIn{Name: []byte(XAttrNameCopyTo), Data: []byte("nosuch/fileA")})
+	if errno != syscall.ENOENT {
+		t.Fatalf("DoSetXAttr(fileAIno,\"nosuch/fileA\") returned errno %v (expected: ENOENT)", errno)
+	}
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: s3DirIno}, &fission.SetXAttrIn{Name: []byte(XAttrNameCopyTo), Data: []byte("s3b/dir")})
+	if errno != syscall.EISDIR {
+		t.Fatalf("DoSetXAttr(s3DirIno,\"s3b/dir\") returned errno %v (expected: EISDIR)", errno)
+	}
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.SetXAttrIn{Name: []byte("user.test"), Data: []byte("s3b/fileA")})
+	if errno != syscall.ENOSYS {
+		t.Fatalf("DoSetXAttr(fileAIno,Name:\"user.test\") returned errno %v (expected: ENOSYS)", errno)
+	}
+
+	globalsLock("fission_s3_test.go:658:2:TestFissionS3CopyTo")
+	globals.config.backends["s3b"].readOnly = true
+	globalsUnlock()
+
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.SetXAttrIn{Name: []byte(XAttrNameCopyTo), Data: []byte("s3b/fileA.again")})
+	if errno != syscall.EROFS {
+		t.Fatalf("DoSetXAttr(fileAIno,\"s3b/fileA.again\") to a readonly backend returned errno %v (expected: EROFS)", errno)
+	}
+
+	// A snapshot backend's copy source is the version current as of its snapshot_time
+
+	testGlobals.testS3Server.putObjectVersion(testFissionS3Bucket, "fileA", []byte("/fileA (old)\n"), now.Add(-2*time.Hour))
+
+	globalsLock("fission_s3_test.go:671:2:TestFissionS3CopyTo")
+	globals.config.backends["s3"].context.(*s3ContextStruct).snapshot = newS3Snapshot(now.Add(-time.Hour))
+	globals.config.backends["s3b"].readOnly = false
+	globalsUnlock()
+
+	_, err = copyFileWrapper(context.Background(), globals.config.backends["s3b"].context, &copyFileInputStruct{
+		srcFilePath: "fileA",
+		dstFilePath: "fileA.old",
+		srcContext:  globals.config.backends["s3"].context,
+	})
+	if err != nil {
+		t.Fatalf("copyFileWrapper(s3b,srcContext:s3) from a snapshot failed: %v", err)
+	}
+	content, ok = testGlobals.testS3Server.getObject("other", "fileA.old")
+	if !ok || (string(content) != "/fileA (old)\n") {
+		t.Fatalf("copyFileWrapper(s3b,srcContext:s3) from a snapshot did not copy the old version of fileA")
+	}
+}
+
+// TestFissionS3PresignedURL verifies that reading the user.msc.presigned_url extended attribute
+// of a file yields a URL via which its object may be fetched with a plain HTTP GET.
+func TestFissionS3PresignedURL(t *testing.T) {
+	var (
+		content      []byte
+		err          error
+		errno        syscall.Errno
+		fileAIno     uint64
+		getXAttrOut  *fission.GetXAttrOut
+		httpResponse *http.Response
+		presignedURL *url.URL
+		s3DirIno     uint64
+	)
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+	fileAIno = fissionS3TestLookup(t, s3DirIno, "fileA")
+
+	getXAttrOut, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.GetXAttrIn{Name: []byte(XAttrNamePresignedURL), Size: 4096})
+	if errno != 0 {
+		t.Fatalf("DoGetXAttr(fileA, XAttrNamePresignedURL) returned errno: %v", errno)
+	}
+
+	presignedURL, err = url.Parse(string(getXAttrOut.Data))
+	if err != nil {
+		t.Fatalf("url.Parse(%q) failed: %v", getXAttrOut.Data, err)
+	}
+	if presignedURL.Query().Get("X-Amz-Expires") != "3600" {
+		t.Fatalf("pre-signed URL %q should have expired after presigned_url_ttl (3600 seconds)", getXAttrOut.Data)
+	}
+
+	httpResponse, err = http.Get(presignedURL.String())
+	if err != nil {
+		t.Fatalf("http.Get(%q) failed: %v", presignedURL, err)
+	}
+	content, err = io.ReadAll(httpResponse.Body)
+	_ = httpResponse.Body.Close()
+	if (err != nil) || (httpResponse.StatusCode != http.StatusOK) || (string(content) != "/fileA\n") {
+		t.Fatalf("http.Get(%q) returned status %d content %q (err: %v)", presignedURL, httpResponse.StatusCode, content, err)
+	}
+
+	_, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: s3DirIno}, &fission.GetXAttrIn{Name: []byte(XAttrNamePresignedURL), Size: 4096})
+	if errno != syscall.ENODATA {
+		t.Fatalf("DoGetXAttr(s3, XAttrNamePresignedURL) should have returned ENODATA (got errno: %v)", errno)
+	}
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"os"
 	"runtime/debug"
+	"slices"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -14,17 +19,21 @@ import (
 // `initFS` initializes the root of the FUSE file system.
 func initFS() {
 	var (
-		err              error
-		fuseRootDirInode *inodeStruct
-		ok               bool
-		timeNow          time.Time
+		err     error
+		timeNow time.Time
 	)
 
-	globalsLock("fs.go:23:2:initFS")
+	globalsLock("fs.go:26:2:initFS")
 
 	globals.backendMap = make(map[uint64]*backendStruct)
 
-	globals.lastNonce = FUSERootDirInodeNumber
+	globals.treeSizeCache = make(map[treeSizeCacheKeyStruct]*treeSizeCacheEntryStruct)
+	globals.treeSizeWalks = make(map[treeSizeCacheKeyStruct]*treeSizeWalkStruct)
+	globals.xattrCache = make(map[uint64]*xattrCacheEntryStruct)
+	globals.renamesInFlight = make(map[uint64]struct{})
+	globals.prefetches = make(map[uint64]*prefetchStruct)
+
+	globals.lastNonce.Store(FUSERootDirInodeNumber)
 
 	globals.cacheDir, err = os.MkdirTemp(globals.config.cacheDirPath, "MSFS_")
 	if err != nil {
@@ -48,6 +57,9 @@ func initFS() {
 	globals.logger.Printf("[INFO] bptree page store opened at %q", globals.cacheDir)
 
 	globals.inodeMap = newShardedInodeMap("globals.inodeMap", globals.config.inodeMapKeysPerPageMax, globals.config.inodeMapPageEvictLowLimit, globals.config.inodeMapPageEvictHighLimit, globals.config.inodeMapPageDirtyFlushTrigger, globals.config.inodeMapFlushedPerGC)
+	globals.inodeHandleEvicted = make([]uint64, 0, inodeHandleRetention)
+	globals.inodeHandleEvictedNext = 0
+	globals.inodeHandleMap = inodeNumberToInodeHandleMapStructCreate("globals.inodeHandleMap", globals.config.inodeMapKeysPerPageMax, globals.config.inodeMapPageEvictLowLimit, globals.config.inodeMapPageEvictHighLimit, globals.config.inodeMapPageDirtyFlushTrigger, globals.config.inodeMapFlushedPerGC)
 	globals.inodeEvictionQueue = xTimeInodeNumberSetStructCreate("globals.inodeEvictionQueue", globals.config.inodeEvictionQueueKeysPerPageMax, globals.config.inodeEvictionQueuePageEvictLowLimit, globals.config.inodeEvictionQueuePageEvictHighLimit, globals.config.inodeEvictionQueuePageDirtyFlushTrigger, globals.config.inodeEvictionQueueFlushedPerGC)
 	globals.physChildDirEntryMap = newShardedDirEntryMap("globals.physChildDirEntryMap", globals.config.physChildDirEntryMapKeysPerPageMax, globals.config.physChildDirEntryMapPageEvictLowLimit, globals.config.physChildDirEntryMapPageEvictHighLimit, globals.config.physChildDirEntryMapPageDirtyFlushTrigger, globals.config.physChildDirEntryMapFlushedPerGC)
 	globals.virtChildDirEntryMap = parentInodeNumberChildBasenameToChildInodeNumberStructCreate("globals.virtChildDirEntryMap", globals.config.virtChildDirEntryMapKeysPerPageMax, globals.config.virtChildDirEntryMapPageEvictLowLimit, globals.config.virtChildDirEntryMapPageEvictHighLimit, globals.config.virtChildDirEntryMapPageDirtyFlushTrigger, globals.config.virtChildDirEntryMapFlushedPerGC)
@@ -58,11 +70,34 @@ func initFS() {
 
 	timeNow = time.Now()
 
+	createFUSERootDirInode(FUSERootDirInodeNumber, timeNow)
+
+	initMounts(timeNow)
+
+	globals.inodeEvictorWorker = startWorker("inodeEvictor", inodeEvictor)
+
+	globals.fhMap = make(map[uint64]*fhStruct)
+
+	globals.fissionMetrics = newFissionMetrics()
+	globals.backendMetrics = newBackendMetrics()
+
+	globalsUnlock()
+}
+
+// `createFUSERootDirInode` is called while globals.Lock() is held to create the (FUSERootDir)
+// inode presented as the root directory of a FUSE mount: the one at config.mountPoint (with
+// inodeNumber == FUSERootDirInodeNumber) or one of config.mounts.
+func createFUSERootDirInode(inodeNumber uint64, timeNow time.Time) {
+	var (
+		fuseRootDirInode *inodeStruct
+		ok               bool
+	)
+
 	fuseRootDirInode = &inodeStruct{
-		inodeNumber:            FUSERootDirInodeNumber,
+		inodeNumber:            inodeNumber,
 		inodeType:              FUSERootDir,
 		backendNonce:           0,
-		parentInodeNumber:      FUSERootDirInodeNumber,
+		parentInodeNumber:      inodeNumber,
 		isVirt:                 true,
 		objectPath:             "",
 		basename:               "",
@@ -71,7 +106,9 @@ func initFS() {
 		eTag:                   "",
 		mode:                   uint32(syscall.S_IFDIR | globals.config.dirPerm),
 		mTime:                  timeNow,
+		times:                  newInodeTimes(timeNow, timeNow),
 		xTime:                  time.Time{},
+		vTime:                  time.Time{},
 		isPrefetchInProgress:   false,
 		cacheMap:               nil,
 		inboundCacheLineCount:  0,
@@ -87,26 +124,16 @@ func initFS() {
 		globals.logger.Fatalf("[FATAL] globals.inodeMap.put(fuseRootDirInode) returned !ok")
 	}
 
-	ok = globals.virtChildDirEntryMap.put(FUSERootDirInodeNumber, DotDirEntryBasename, FUSERootDirInodeNumber)
+	ok = globals.virtChildDirEntryMap.put(inodeNumber, DotDirEntryBasename, inodeNumber)
 	if !ok {
 		dumpStack()
-		globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(FUSERootDirInodeNumber, DotDirEntryBasename, FUSERootDirInodeNumber) returned !ok")
+		globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(inodeNumber, DotDirEntryBasename, inodeNumber) returned !ok")
 	}
-	ok = globals.virtChildDirEntryMap.put(FUSERootDirInodeNumber, DotDotDirEntryBasename, FUSERootDirInodeNumber)
+	ok = globals.virtChildDirEntryMap.put(inodeNumber, DotDotDirEntryBasename, inodeNumber)
 	if !ok {
 		dumpStack()
-		globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(FUSERootDirInodeNumber, DotDotDirEntryBasename, FUSERootDirInodeNumber) returned !ok")
+		globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(inodeNumber, DotDotDirEntryBasename, inodeNumber) returned !ok")
 	}
-
-	globals.inodeEvictorContext, globals.inodeEvictorCancelFunc = context.WithCancel(context.Background())
-	globals.inodeEvictorWaitGroup.Go(inodeEvictor)
-
-	globals.fhMap = make(map[uint64]*fhStruct)
-
-	globals.fissionMetrics = newFissionMetrics()
-	globals.backendMetrics = newBackendMetrics()
-
-	globalsUnlock()
 }
 
 // `drainFS` awaits all backend/asynchronous traffic to complete before
@@ -117,10 +144,9 @@ func drainFS() {
 		err     error
 	)
 
-	globals.inodeEvictorCancelFunc()
-	globals.inodeEvictorWaitGroup.Wait()
+	globals.inodeEvictorWorker.stop()
 
-	globalsLock("fs.go:123:2:drainFS")
+	globalsLock("fs.go:149:2:drainFS")
 
 	for dirName, backend = range globals.config.backends {
 		globals.backendsToUnmount[dirName] = backend
@@ -166,7 +192,7 @@ func processToMountList() {
 		timeNow time.Time
 	)
 
-	globalsLock("fs.go:169:2:processToMountList")
+	globalsLock("fs.go:195:2:processToMountList")
 
 	timeNow = time.Now()
 
@@ -180,18 +206,39 @@ func processToMountList() {
 		if backend.context == nil {
 			err = backend.setupContext()
 			if err != nil {
-				globals.logger.Printf("[WARN] unable to set up a backend context; skipping (check the backend's credentials/endpoint/prefix)")
+				if backend.mkDirOf != "" {
+					// mkDirDefaultBackend() reports the failure to the mkdir(2) caller
+					continue
+				}
+				backend.setupErr = err
+				_, ok = globals.backendsFailed[dirName]
+				globals.backendsFailed[dirName] = backend
+				if ok {
+					// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
+					globals.logger.Printf("[INFO] backend \"%s\" still unable to set up a backend context: %s", dirName, redactSecrets(backend, err.Error()))
+				} else {
+					// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
+					globals.logger.Printf("[WARN] unable to set up a backend context for backend \"%s\"; skipping (check the backend's credentials/endpoint/prefix): %s", dirName, redactSecrets(backend, err.Error()))
+					publishEvent(EventMountDegraded, dirName, "unable to set up a backend context")
+				}
 				continue
 			}
 		}
 
+		_, ok = globals.backendsFailed[dirName]
+		if ok {
+			delete(globals.backendsFailed, dirName)
+			globals.logger.Printf("[INFO] backend \"%s\" recovered", dirName)
+		}
+		backend.setupErr = nil
+
 		backend.nonce = fetchNonce()
 
 		backend.inode = &inodeStruct{
-			inodeNumber:            fetchNonce(),
+			// inodeNumber: filled in by putWithStableInodeNumber() below
 			inodeType:              BackendRootDir,
 			backendNonce:           backend.nonce,
-			parentInodeNumber:      FUSERootDirInodeNumber,
+			parentInodeNumber:      backendFUSERootDirInodeNumber(backend),
 			isVirt:                 true,
 			objectPath:             "",
 			basename:               dirName,
@@ -200,7 +247,9 @@ func processToMountList() {
 			eTag:                   "",
 			mode:                   uint32(syscall.S_IFDIR | backend.dirPerm),
 			mTime:                  timeNow,
+			times:                  newInodeTimes(timeNow, timeNow),
 			xTime:                  time.Time{},
+			vTime:                  time.Time{},
 			isPrefetchInProgress:   false,
 			cacheMap:               nil,
 			inboundCacheLineCount:  0,
@@ -210,16 +259,12 @@ func processToMountList() {
 			pendingDelete:          false,
 		}
 
-		ok = globals.inodeMap.put(backend.inode)
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.inodeMap.put(backend.inode) returned !ok")
-		}
+		backend.inode.putWithStableInodeNumber(dirName)
 
-		ok = globals.virtChildDirEntryMap.put(FUSERootDirInodeNumber, backend.inode.basename, backend.inode.inodeNumber)
+		ok = globals.virtChildDirEntryMap.put(backend.inode.parentInodeNumber, backend.inode.basename, backend.inode.inodeNumber)
 		if !ok {
 			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(FUSERootDirInodeNumber, backend.inode.basename[\"%s\"], backend.inode.inodeNumber) returned !ok", backend.inode.basename)
+			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(backend.inode.parentInodeNumber, backend.inode.basename[\"%s\"], backend.inode.inodeNumber) returned !ok", backend.inode.basename)
 		}
 
 		ok = globals.virtChildDirEntryMap.put(backend.inode.inodeNumber, DotDirEntryBasename, backend.inode.inodeNumber)
@@ -227,28 +272,36 @@ func processToMountList() {
 			dumpStack()
 			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(backend.inode.inodeNumber, DotDirEntryBasename, backend.inode.inodeNumber) returned !ok")
 		}
-		ok = globals.virtChildDirEntryMap.put(backend.inode.inodeNumber, DotDotDirEntryBasename, FUSERootDirInodeNumber)
+		ok = globals.virtChildDirEntryMap.put(backend.inode.inodeNumber, DotDotDirEntryBasename, backend.inode.parentInodeNumber)
 		if !ok {
 			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(backend.inode.inodeNumber, DotDotDirEntryBasename, FUSERootDirInodeNumber) returned !ok")
+			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(backend.inode.inodeNumber, DotDotDirEntryBasename, backend.inode.parentInodeNumber) returned !ok")
 		}
 
 		backend.fissionMetrics = newFissionMetrics()
 		backend.backendMetrics = newBackendMetrics()
 
+		if backend.hedgeReadPercentile > 0 {
+			backend.hedge = newHedge(backend.hedgeReadPercentile, backend.hedgeReadMinDelay, backend.hedgeReadBudget)
+		}
+
 		backend.mounted = true
 
 		globals.config.backends[dirName] = backend
 		globals.backendMap[backend.nonce] = backend
+
+		publishEvent(EventBackendMounted, dirName, "")
 	}
 
+	updateBackendACLsInUse()
+
 	globalsUnlock()
 }
 
 // `processToUnmountList` is called to remove each backend subdirectory of the FUSE
 // file system's root directory found on the globals.backendsToUnmount list.
 func processToUnmountList() {
-	globalsLock("fs.go:251:2:processToUnmountList")
+	globalsLock("fs.go:304:2:processToUnmountList")
 	processToUnmountListAlreadyLocked()
 	globalsUnlock()
 }
@@ -268,10 +321,10 @@ func processToUnmountListAlreadyLocked() {
 
 		backend.inode.emptyChildInodes()
 
-		ok = globals.virtChildDirEntryMap.delete(FUSERootDirInodeNumber, backend.dirName)
+		ok = globals.virtChildDirEntryMap.delete(backend.inode.parentInodeNumber, backend.dirName)
 		if !ok {
 			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(FUSERootDirInodeNumber, backend.dirName[\"%s\"]) returned !ok", backend.dirName)
+			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(backend.inode.parentInodeNumber, backend.dirName[\"%s\"]) returned !ok", backend.dirName)
 		}
 
 		ok = globals.inodeMap.delete(backend.inode.inodeNumber)
@@ -280,11 +333,17 @@ func processToUnmountListAlreadyLocked() {
 			globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(backend.inode.inodeNumber) returned !ok")
 		}
 
+		backend.inode.pruneInodeHandle()
+
 		backend.mounted = false
 
 		delete(globals.config.backends, dirName)
 		delete(globals.backendMap, backend.nonce)
+
+		publishEvent(EventBackendUnmounted, dirName, "")
 	}
+
+	updateBackendACLsInUse()
 }
 
 // `emptyChildInodes` is called to remove all child inodes.
@@ -341,6 +400,8 @@ func (parentInode *inodeStruct) emptyChildInodes() {
 				globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(childDirInfo.InodeNumber) returned !ok [case physChildDirEntryMap]")
 			}
 
+			childInode.pruneInodeHandle()
+
 			ok = globals.physChildDirEntryMap.delete(parentInode.inodeNumber, childInodeBasename)
 			if !ok {
 				dumpStack()
@@ -392,6 +453,8 @@ func (parentInode *inodeStruct) emptyChildInodes() {
 					dumpStack()
 					globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(childDirInfo.InodeNumber) returned !ok [case virtChildDirEntryMap]")
 				}
+
+				childInode.pruneInodeHandle()
 			}
 
 			ok = globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, childInodeBasename)
@@ -446,6 +509,32 @@ func (childInode *inodeStruct) convertToPhysInodeIfNecessary() {
 	childInode.touch(nil)
 }
 
+// `convertToPhysDirPathIfNecessary` is called while globals.Lock() is held to convert the
+// supplied directory inode, and any "virt" directory inodes above it, from "virt" to "phys"
+// as is required before a "phys" child may be added to it.
+func (dirInode *inodeStruct) convertToPhysDirPathIfNecessary() {
+	var (
+		ok            bool
+		virtDirInodes []*inodeStruct
+	)
+
+	for dirInode.isVirt && (dirInode.inodeType == PseudoDir) {
+		virtDirInodes = append(virtDirInodes, dirInode)
+		dirInode, ok = globals.inodeMap.get(dirInode.parentInodeNumber)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.inodeMap.get(dirInode.parentInodeNumber) returned !ok")
+		}
+	}
+
+	// Convert from the top down so that each parent is "phys" before its child is converted
+
+	for len(virtDirInodes) > 0 {
+		virtDirInodes[len(virtDirInodes)-1].convertToPhysInodeIfNecessary()
+		virtDirInodes = virtDirInodes[:len(virtDirInodes)-1]
+	}
+}
+
 // `createPseudoDirInode` is called while globals.Lock() is held to create a new PseudoDir inodeStruct.
 // If skipTouch is true, the new inode is not placed on the eviction queue (used during bulk ingest
 // where children are added after globals.Unlock, so the empty-dir eviction check would be premature).
@@ -463,7 +552,7 @@ func (parentInode *inodeStruct) createPseudoDirInode(isVirt bool, basename strin
 	}
 
 	pseudoDirInode = &inodeStruct{
-		inodeNumber:       fetchNonce(),
+		// inodeNumber: filled in by putWithStableInodeNumber() below
 		inodeType:         PseudoDir,
 		backendNonce:      backend.nonce,
 		parentInodeNumber: parentInode.inodeNumber,
@@ -475,7 +564,9 @@ func (parentInode *inodeStruct) createPseudoDirInode(isVirt bool, basename strin
 		eTag:                   "",
 		mode:                   uint32(syscall.S_IFDIR | backend.dirPerm),
 		mTime:                  timeNow,
+		times:                  newInodeTimes(timeNow, timeNow),
 		xTime:                  time.Time{},
+		vTime:                  time.Time{},
 		isPrefetchInProgress:   false,
 		cacheMap:               nil,
 		inboundCacheLineCount:  0,
@@ -491,11 +582,7 @@ func (parentInode *inodeStruct) createPseudoDirInode(isVirt bool, basename strin
 		pseudoDirInode.objectPath = parentInode.objectPath + basename + "/"
 	}
 
-	ok = globals.inodeMap.put(pseudoDirInode)
-	if !ok {
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] globals.inodeMap.put(pseudoDirInode) returned !ok")
-	}
+	pseudoDirInode.putWithStableInodeNumber(backend.dirName)
 
 	if isVirt {
 		ok = globals.virtChildDirEntryMap.put(parentInode.inodeNumber, pseudoDirInode.basename, pseudoDirInode.inodeNumber)
@@ -530,7 +617,238 @@ func (parentInode *inodeStruct) createPseudoDirInode(isVirt bool, basename strin
 	return
 }
 
+// `symLinkBasename` returns the basename under which an object with the supplied basename is
+// presented as well as whether or not it is an emulated symlink (i.e. its basename ends with
+// the backend's symlink_suffix and its content is the symlink's target).
+func (backend *backendStruct) symLinkBasename(objectBasename string) (basename string, isSymLink bool) {
+	if (backend.symLinkSuffix != "") && (len(objectBasename) > len(backend.symLinkSuffix)) && strings.HasSuffix(objectBasename, backend.symLinkSuffix) {
+		basename = strings.TrimSuffix(objectBasename, backend.symLinkSuffix)
+		isSymLink = true
+	} else {
+		basename = objectBasename
+		isSymLink = false
+	}
+
+	return
+}
+
+// `isSymLink` is called while globals.Lock() is held to determine if a FileObject inode is an emulated symlink.
+func (inode *inodeStruct) isSymLink() bool {
+	return (inode.inodeType == FileObject) && ((inode.mode & syscall.S_IFMT) == syscall.S_IFLNK)
+}
+
+// `isFIFO` is called while globals.Lock() is held to determine if a FileObject inode is an emulated FIFO.
+func (inode *inodeStruct) isFIFO() bool {
+	return (inode.inodeType == FileObject) && ((inode.mode & syscall.S_IFMT) == syscall.S_IFIFO)
+}
+
+// `newInodeTimes` returns the .times of an inode whose .mTime is mTime that is first seen at bTime.
+func newInodeTimes(mTime time.Time, bTime time.Time) (times inodeTimesStruct) {
+	times = inodeTimesStruct{
+		aTime: mTime,
+		bTime: bTime,
+		cTime: mTime,
+	}
+
+	return
+}
+
+// `stableInodeNumber` returns the inode number derived from a hash of the dirName of a backend
+// and an objectPath within it. As it doesn't depend on the order in which inodes are created,
+// an inode evicted and later re-created (even by a subsequent mount) retains its inode number
+// such that tools relying on (dev, ino) stability (e.g. rsync's hard link detection, find -inum,
+// and NFS re-export) work as expected. Inode numbers up thru FUSERootDirInodeNumber are skipped.
+func stableInodeNumber(dirName string, objectPath string) (inodeNumber uint64) {
+	var (
+		hash = fnv.New64a()
+	)
+
+	_, _ = hash.Write([]byte(dirName))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(objectPath))
+
+	inodeNumber = hash.Sum64()
+	if inodeNumber <= FUSERootDirInodeNumber {
+		inodeNumber += FUSERootDirInodeNumber + 1
+	}
+
+	return
+}
+
+// `putWithStableInodeNumber` sets inode.inodeNumber to the stableInodeNumber() of its objectPath
+// in the backend named dirName and inserts it into globals.inodeMap. Should that inode number
+// already be in use (a hash collision or, say, a pendingDelete inode for the same objectPath),
+// successive inode numbers are probed. As globals.inodeMap.put() fails rather than replaces an
+// existing inode, this may also be called without holding globals.Lock().
+//
+// The inode's generation is that recorded in globals.inodeHandleMap for the inode number if it
+// last identified the same objectPath or, otherwise, is advanced such that file handles for the
+// prior object are not mistaken for handles of this one.
+func (inode *inodeStruct) putWithStableInodeNumber(dirName string) {
+	var (
+		inodeHandle inodeHandleStruct
+		ok          bool
+	)
+
+	inode.inodeNumber = stableInodeNumber(dirName, inode.objectPath)
+
+	for {
+		inodeHandle, ok = globals.inodeHandleMap.get(inode.inodeNumber)
+		switch {
+		case !ok:
+			inode.generation = 1
+		case (inodeHandle.dirName == dirName) && (inodeHandle.objectPath == inode.objectPath):
+			inode.generation = inodeHandle.generation
+		default:
+			inode.generation = inodeHandle.generation + 1
+		}
+
+		if globals.inodeMap.put(inode) {
+			break
+		}
+
+		inode.inodeNumber++
+		if inode.inodeNumber <= FUSERootDirInodeNumber {
+			inode.inodeNumber = FUSERootDirInodeNumber + 1
+		}
+	}
+
+	if !ok || (inodeHandle.generation != inode.generation) {
+		globals.inodeHandleMap.put(inode.inodeNumber, inodeHandleStruct{
+			dirName:    dirName,
+			objectPath: inode.objectPath,
+			generation: inode.generation,
+		})
+	}
+}
+
+// `retireInodeHandle` is called while globals.Lock() is held when the object identified by
+// inode is removed or renamed. While inode remains in globals.inodeMap, it keeps its generation.
+// Should it subsequently be re-materialized (or its inode number reused), however, a new
+// generation is assigned such that file handles referencing the removed object become stale.
+func (inode *inodeStruct) retireInodeHandle() {
+	var (
+		inodeHandle inodeHandleStruct
+		ok          bool
+	)
+
+	inodeHandle, ok = globals.inodeHandleMap.get(inode.inodeNumber)
+	if !ok {
+		return
+	}
+
+	inodeHandle.generation = max(inodeHandle.generation, inode.generation) + 1
+
+	globals.inodeHandleMap.put(inode.inodeNumber, inodeHandle)
+}
+
+// `inodeHandleRetention` is the number of evicted inodes whose globals.inodeHandleMap entries
+// are retained such that file handles referencing them may be re-materialized.
+const inodeHandleRetention = 1 << 20
+
+// `pruneInodeHandle` is called while globals.Lock() is held as inode is evicted. Its entry in
+// globals.inodeHandleMap is retained (see rematerializeInode()) until cap(globals.inodeHandleEvicted)
+// further inodes have been evicted. Each eviction beyond that prunes the entry of the inode evicted
+// longest ago (unless it has since been re-materialized) such that globals.inodeHandleMap is bounded.
+func (inode *inodeStruct) pruneInodeHandle() {
+	var (
+		ok                bool
+		oldestInodeNumber uint64
+	)
+
+	if len(globals.inodeHandleEvicted) < cap(globals.inodeHandleEvicted) {
+		globals.inodeHandleEvicted = append(globals.inodeHandleEvicted, inode.inodeNumber)
+		return
+	}
+
+	if len(globals.inodeHandleEvicted) == 0 {
+		_ = globals.inodeHandleMap.delete(inode.inodeNumber)
+		return
+	}
+
+	oldestInodeNumber = globals.inodeHandleEvicted[globals.inodeHandleEvictedNext]
+	globals.inodeHandleEvicted[globals.inodeHandleEvictedNext] = inode.inodeNumber
+	globals.inodeHandleEvictedNext = (globals.inodeHandleEvictedNext + 1) % len(globals.inodeHandleEvicted)
+
+	_, ok = globals.inodeMap.get(oldestInodeNumber)
+	if !ok {
+		_ = globals.inodeHandleMap.delete(oldestInodeNumber)
+	}
+}
+
+// `inodeGeneration` is called while globals.Lock() is held to fetch the generation of the inode
+// identified by inodeNumber whether or not it is currently in globals.inodeMap.
+func inodeGeneration(inodeNumber uint64) (generation uint64) {
+	var (
+		inode       *inodeStruct
+		inodeHandle inodeHandleStruct
+		ok          bool
+	)
+
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	if ok {
+		generation = inode.generation
+		return
+	}
+
+	inodeHandle, ok = globals.inodeHandleMap.get(inodeNumber)
+	if ok {
+		generation = inodeHandle.generation
+	}
+
+	return
+}
+
+// `rematerializeInode` is called while globals.Lock() is held to re-create the evicted inode
+// identified by inodeNumber (e.g. when the kernel resolves an NFS file handle of a re-exported
+// mount). The objectPath recorded in globals.inodeHandleMap is looked up from its backend's root
+// just as the kernel would have done originally. The return `ok` indicates whether the inode was
+// found at its recorded objectPath and was again assigned inodeNumber.
+func rematerializeInode(ctx context.Context, inodeNumber uint64) (inode *inodeStruct, ok bool) {
+	var (
+		backend     *backendStruct
+		basename    string
+		component   string
+		inodeHandle inodeHandleStruct
+	)
+
+	inodeHandle, ok = globals.inodeHandleMap.get(inodeNumber)
+	if !ok {
+		return
+	}
+
+	backend, ok = globals.config.backends[inodeHandle.dirName]
+	if !ok || !backend.mounted || (backend.inode == nil) {
+		ok = false
+		return
+	}
+
+	inode = backend.inode
+
+	if inodeHandle.objectPath != "" {
+		for _, component = range strings.Split(strings.TrimSuffix(inodeHandle.objectPath, "/"), "/") {
+			if inode.inodeType == FileObject {
+				ok = false
+				return
+			}
+
+			basename, _ = backend.symLinkBasename(component)
+
+			inode, ok = inode.findChildInode(ctx, basename)
+			if !ok || inode.pendingDelete {
+				ok = false
+				return
+			}
+		}
+	}
+
+	ok = (inode.inodeNumber == inodeNumber)
+
+	return
+}
+
 // `createFileObjectInode` is called while globals.Lock() is held to create a new FileObject inodeStruct.
+// Should basename identify an emulated symlink, the inode is presented as such (see symLinkBasename()).
 func (parentInode *inodeStruct) createFileObjectInode(isVirt bool, basename string, size uint64, eTag string, mTime time.Time) (fileObjectInode *inodeStruct) {
 	var (
 		backend *backendStruct
@@ -544,7 +862,7 @@ func (parentInode *inodeStruct) createFileObjectInode(isVirt bool, basename stri
 	}
 
 	fileObjectInode = &inodeStruct{
-		inodeNumber:       fetchNonce(),
+		// inodeNumber: filled in by putWithStableInodeNumber() below
 		inodeType:         FileObject,
 		backendNonce:      backend.nonce,
 		parentInodeNumber: parentInode.inodeNumber,
@@ -556,7 +874,9 @@ func (parentInode *inodeStruct) createFileObjectInode(isVirt bool, basename stri
 		eTag:                   eTag,
 		mode:                   uint32(syscall.S_IFREG | backend.filePerm),
 		mTime:                  mTime,
+		times:                  newInodeTimes(mTime, time.Now()),
 		xTime:                  time.Time{},
+		vTime:                  time.Now().Add(backend.attrTTL),
 		isPrefetchInProgress:   false,
 		cacheMap:               make(map[uint64]uint64),
 		inboundCacheLineCount:  0,
@@ -572,12 +892,13 @@ func (parentInode *inodeStruct) createFileObjectInode(isVirt bool, basename stri
 		fileObjectInode.objectPath = parentInode.objectPath + basename
 	}
 
-	ok = globals.inodeMap.put(fileObjectInode)
-	if !ok {
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] globals.inodeMap.put(fileObjectInode) returned !ok")
+	fileObjectInode.basename, ok = backend.symLinkBasename(basename)
+	if ok {
+		fileObjectInode.mode = uint32(syscall.S_IFLNK | 0o777)
 	}
 
+	fileObjectInode.putWithStableInodeNumber(backend.dirName)
+
 	if isVirt {
 		ok = globals.virtChildDirEntryMap.put(parentInode.inodeNumber, fileObjectInode.basename, fileObjectInode.inodeNumber)
 		if !ok {
@@ -738,7 +1059,7 @@ func convertAncestorDirectoriesToVirtual(dirInode *inodeStruct) {
 }
 
 // `touch` is called to ensure an inode that should be on globals.inodeEvictionLRU has the
-// appropriate .xTime. `touch` will optionally update .mTime as well. If the inode should
+// appropriate .xTime. `touch` will optionally update .mTime (and .times.cTime) as well. If the inode should
 // not be on globals.inodeEvictionLRU, its .listElement will be nil.
 func (inode *inodeStruct) touch(mTimeAsInterface interface{}) {
 	var (
@@ -755,6 +1076,7 @@ func (inode *inodeStruct) touch(mTimeAsInterface interface{}) {
 			dumpStack()
 			globals.logger.Fatalf("[FATAL] mTimeAsInterface.(time.Time) returned !ok")
 		}
+		inode.times.cTime = time.Now()
 	}
 
 	if !inode.xTime.IsZero() {
@@ -810,14 +1132,57 @@ func (inode *inodeStruct) touch(mTimeAsInterface interface{}) {
 	globals.inodeMap.touch(inode)
 }
 
+// `applyDeferredTouches` is called by globalsLock() to apply the inode and data cache line
+// touches deferred by those holding globals only shared (see globalsRLock()). Inodes that have
+// since been evicted are skipped as are data cache lines that have since been freed or refetched.
+func applyDeferredTouches() {
+	var (
+		aTime                time.Time
+		contentGeneration    uint64
+		dataCacheLinePos     uint64
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		inode                *inodeStruct
+		inodeNumber          uint64
+		lineTouches          map[uint64]uint64
+		ok                   bool
+		touches              map[uint64]time.Time
+	)
+
+	if globals.inodeMap == nil {
+		return
+	}
+
+	touches, lineTouches = globals.inodeMap.takeDeferredTouches()
+
+	for inodeNumber, aTime = range touches {
+		inode, ok = globals.inodeMap.get(inodeNumber)
+		if !ok {
+			continue
+		}
+		if aTime.After(inode.times.aTime) {
+			inode.times.aTime = aTime
+		}
+		inode.touch(nil)
+	}
+
+	for dataCacheLinePos, contentGeneration = range lineTouches {
+		if dataCacheLinePos >= uint64(len(globals.dataCacheLinesTracker)) {
+			continue
+		}
+		dataCacheLineTracker = &globals.dataCacheLinesTracker[dataCacheLinePos]
+		if (dataCacheLineTracker.state == CacheLineClean) && (dataCacheLineTracker.contentGeneration.Load() == contentGeneration) {
+			dataCacheLineTracker.touch()
+		}
+	}
+}
+
 // `inodeEvictor` is a goroutine that periodically monitors the cache and globals.inodeEvictionLRU
 // to see if cache limits need to be enforced or any "phys"/"virt" inodes should be evicted/expired.
-func inodeEvictor() {
+func inodeEvictor(ctx context.Context) {
 	var (
 		childInode       *inodeStruct
 		childInodeNumber uint64
 		ok               bool
-		parentInode      *inodeStruct
 		ticker           *time.Ticker
 		timeNow          time.Time
 		xTime            time.Time
@@ -828,7 +1193,7 @@ func inodeEvictor() {
 	for {
 		select {
 		case <-ticker.C:
-			globalsLock("fs.go:831:4:inodeEvictor")
+			globalsLock("fs.go:1196:4:inodeEvictor")
 
 			// Scan globals.inodeEvictionLRU looking for expired inodes to evict
 
@@ -846,45 +1211,11 @@ func inodeEvictor() {
 					globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childInodeNumber) returned !ok")
 				}
 
-				ok = globals.inodeEvictionQueue.remove(childInode)
-				if !ok {
-					dumpStack()
-					globals.logger.Fatalf("[FATAL] globals.inodeEvictionQueue.remove(childInode) returned !ok")
-				}
-
-				clearFileCacheLinesLocked(childInode)
-
-				parentInode, ok = globals.inodeMap.get(childInode.parentInodeNumber)
-				if !ok {
-					dumpStack()
-					globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childInode.parentInodeNumber) returned !ok")
-				}
-
-				if childInode.isVirt {
-					ok = globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, childInode.basename)
-					if !ok {
-						dumpStack()
-						globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, childInode.basename) returned !ok")
-					}
-				} else {
-					ok = globals.physChildDirEntryMap.delete(parentInode.inodeNumber, childInode.basename)
-					if !ok {
-						dumpStack()
-						globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.delete(parentInode.inodeNumber, childInode.basename) returned !ok")
-					}
-				}
-
-				ok = globals.inodeMap.delete(childInodeNumber)
-				if !ok {
-					dumpStack()
-					globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(childInodeNumber) returned !ok")
-				}
-
-				parentInode.touch(nil)
+				childInode.evict()
 			}
 
 			globalsUnlock()
-		case <-globals.inodeEvictorContext.Done():
+		case <-ctx.Done():
 			ticker.Stop()
 			return
 		}
@@ -900,7 +1231,6 @@ func inodeEvictorForceDrain() (numDrained uint64) {
 		childInode       *inodeStruct
 		childInodeNumber uint64
 		ok               bool
-		parentInode      *inodeStruct
 	)
 
 	numDrained = 0
@@ -919,50 +1249,63 @@ func inodeEvictorForceDrain() (numDrained uint64) {
 			globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childInodeNumber) returned !ok")
 		}
 
-		ok = globals.inodeEvictionQueue.remove(childInode)
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.inodeEvictionQueue.remove(childInode) returned !ok")
-		}
+		childInode.evict()
+	}
+
+	return
+}
+
+// `evict` is called while globals.Lock() is held to remove inode (which must be in
+// globals.inodeEvictionQueue) from globals.inodeMap and its parent's directory entries.
+func (inode *inodeStruct) evict() {
+	var (
+		ok          bool
+		parentInode *inodeStruct
+	)
 
-		clearFileCacheLinesLocked(childInode)
+	ok = globals.inodeEvictionQueue.remove(inode)
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.inodeEvictionQueue.remove(inode) returned !ok")
+	}
+
+	clearFileCacheLinesLocked(inode)
+
+	parentInode, ok = globals.inodeMap.get(inode.parentInodeNumber)
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.inodeMap.get(inode.parentInodeNumber) returned !ok")
+	}
 
-		parentInode, ok = globals.inodeMap.get(childInode.parentInodeNumber)
+	if inode.isVirt {
+		ok = globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, inode.basename)
 		if !ok {
 			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childInode.parentInodeNumber) returned !ok")
-		}
-
-		if childInode.isVirt {
-			ok = globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, childInode.basename)
-			if !ok {
-				dumpStack()
-				globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, childInode.basename) returned !ok")
-			}
-		} else {
-			ok = globals.physChildDirEntryMap.delete(parentInode.inodeNumber, childInode.basename)
-			if !ok {
-				dumpStack()
-				globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.delete(parentInode.inodeNumber, childInode.basename) returned !ok")
-			}
+			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(parentInode.inodeNumber, inode.basename) returned !ok")
 		}
-
-		ok = globals.inodeMap.delete(childInodeNumber)
+	} else {
+		ok = globals.physChildDirEntryMap.delete(parentInode.inodeNumber, inode.basename)
 		if !ok {
 			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(childInodeNumber) returned !ok")
+			globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.delete(parentInode.inodeNumber, inode.basename) returned !ok")
 		}
+	}
 
-		parentInode.touch(nil)
+	ok = globals.inodeMap.delete(inode.inodeNumber)
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(inode.inodeNumber) returned !ok")
 	}
 
-	return
+	inode.pruneInodeHandle()
+
+	parentInode.touch(nil)
 }
 
 // `findChildInode` is called to locate or create a child's inodeStruct. The return `ok` indicates
 // that either the child's inodeStruct was already known or has been created in the cases where
 // an existing object or object prefix is found. Callers should already hold globals.Lock().
-func (parentInode *inodeStruct) findChildInode(basename string) (childInode *inodeStruct, ok bool) {
+func (parentInode *inodeStruct) findChildInode(ctx context.Context, basename string) (childInode *inodeStruct, ok bool) {
 	var (
 		backend            *backendStruct
 		childDirInfo       DirEntryInfo
@@ -1013,12 +1356,32 @@ func (parentInode *inodeStruct) findChildInode(basename string) (childInode *ino
 		globals.logger.Fatalf("[FATAL] globals.backendMap[parentInode.backendNonce] returned !ok")
 	}
 
-	// Check manifest per-directory TSV before S3 fallback
-	if backend.manifestPath != "" {
-		manifestPartFile := manifestPartPath(backend.manifestPath, parentInode.objectPath)
-		mEntry, mFound := lookupInManifestPart(manifestPartFile, basename)
-		if mFound {
-			if mEntry.Kind == "d" {
+	// An emulated symlink already known (e.g. listed) needs no backend lookup either
+
+	if backend.symLinkSuffix != "" {
+		childDirInfo, ok = globals.physChildDirEntryMap.getByBasename(parentInode.inodeNumber, basename+backend.symLinkSuffix)
+		if ok {
+			childInode, ok = globals.inodeMap.get(childDirInfo.InodeNumber)
+			if !ok {
+				dumpStack()
+				globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childDirInfo.InodeNumber) returned !ok [findChildInode() case 3]")
+			}
+
+			return
+		}
+	}
+
+	// Check manifest per-directory TSV before S3 fallback
+	if backend.manifestPath != "" {
+		manifestPartFile := manifestPartPath(backend.manifestPath, parentInode.objectPath)
+		mEntry, mFound := lookupInManifestPart(manifestPartFile, basename)
+		if mFound && (mEntry.Kind == "d") {
+			mFound = !backend.filter.hides(parentInode.objectPath + basename + "/")
+		} else if mFound {
+			mFound = !backend.filter.hides(parentInode.objectPath + basename)
+		}
+		if mFound {
+			if mEntry.Kind == "d" {
 				childInode = parentInode.createPseudoDirInode(false, basename)
 			} else {
 				childInode = parentInode.createFileObjectInode(false, basename, mEntry.Size, mEntry.ETag, mEntry.MTime)
@@ -1041,7 +1404,13 @@ func (parentInode *inodeStruct) findChildInode(basename string) (childInode *ino
 		ifMatch:  "",
 	}
 
-	statFileOutput, err = statFileWrapper(backend.context, statFileInput)
+	// Objects and object prefixes hidden by the backend's include/exclude filter are not looked up
+
+	if backend.filter.hides(dirOrFilePath) {
+		err = errFileNotFound
+	} else {
+		statFileOutput, err = statFileWrapper(ctx, backend.context, statFileInput)
+	}
 	if err == nil {
 		// We found an existing object in the backend, so let's create a FileObject inode for it
 
@@ -1049,13 +1418,29 @@ func (parentInode *inodeStruct) findChildInode(basename string) (childInode *ino
 
 		if !parentInode.isPrefetchInProgress {
 			parentInode.isPrefetchInProgress = true
-			go prefetchDirectory(parentInode.inodeNumber)
+			startPrefetchDirectory(ctx, parentInode.inodeNumber)
 		}
 
 		ok = true
 		return
 	}
 
+	// No object found in the backend... what about an emulated symlink?
+
+	if (backend.symLinkSuffix != "") && !backend.filter.hides(dirOrFilePath+backend.symLinkSuffix) {
+		statFileInput = &statFileInputStruct{
+			filePath: dirOrFilePath + backend.symLinkSuffix,
+			ifMatch:  "",
+		}
+
+		statFileOutput, err = statFileWrapper(ctx, backend.context, statFileInput)
+		if err == nil {
+			childInode = parentInode.createFileObjectInode(false, basename+backend.symLinkSuffix, statFileOutput.size, statFileOutput.eTag, statFileOutput.mTime)
+			ok = true
+			return
+		}
+	}
+
 	// No object found in the backend... what about an object prefix?
 	// Note: By convention, we must modify dirOrFileOPath to end in "/"
 
@@ -1065,7 +1450,11 @@ func (parentInode *inodeStruct) findChildInode(basename string) (childInode *ino
 		dirPath: dirOrFilePath,
 	}
 
-	_, err = statDirectoryWrapper(backend.context, statDirectoryInput)
+	if backend.filter.hides(dirOrFilePath) {
+		err = errFileNotFound
+	} else {
+		_, err = statDirectoryWrapper(ctx, backend.context, statDirectoryInput)
+	}
 	if err == nil {
 		// We found an existing object prefix in the backend, so let's create a PseudoDir inode for it
 
@@ -1073,7 +1462,7 @@ func (parentInode *inodeStruct) findChildInode(basename string) (childInode *ino
 
 		if !parentInode.isPrefetchInProgress {
 			parentInode.isPrefetchInProgress = true
-			go prefetchDirectory(parentInode.inodeNumber)
+			startPrefetchDirectory(ctx, parentInode.inodeNumber)
 		}
 
 		ok = true
@@ -1088,11 +1477,19 @@ func (parentInode *inodeStruct) findChildInode(basename string) (childInode *ino
 	return
 }
 
+// `startPrefetchDirectory` launches prefetchDirectory(dirInodeNumber) as a "directoryPrefetch" worker
+// whose backend calls are traced as children of any span in ctx.
+func startPrefetchDirectory(ctx context.Context, dirInodeNumber uint64) {
+	_ = startWorker("directoryPrefetch", func(workerCtx context.Context) {
+		prefetchDirectory(detachedSpanContext(workerCtx, ctx), dirInodeNumber)
+	})
+}
+
 // `prefetchDirectory` is run as a background worker to populate globals.inodeMap
 // with inodeStruct's as would occur in DoReadDir() and DoReadDirPlus() to handle
 // the use cases where paths are known by users without the need to discover them
 // via directory listings that would normally trigger such population.
-func prefetchDirectory(dirInodeNumber uint64) {
+func prefetchDirectory(ctx context.Context, dirInodeNumber uint64) {
 	var (
 		backend                 *backendStruct
 		basename                string
@@ -1107,7 +1504,7 @@ func prefetchDirectory(dirInodeNumber uint64) {
 		startTime               = time.Now()
 	)
 
-	globalsLock("fs.go:1110:2:prefetchDirectory")
+	globalsLock("fs.go:1507:2:prefetchDirectory")
 
 	dirInode, ok = globals.inodeMap.get(dirInodeNumber)
 	if !ok {
@@ -1131,12 +1528,12 @@ func prefetchDirectory(dirInodeNumber uint64) {
 
 		globalsUnlock()
 
-		listDirectoryOutput, err = listDirectoryWrapper(backend.context, listDirectoryInput)
+		listDirectoryOutput, err = listDirectoryWrapper(ctx, backend.context, listDirectoryInput)
 		if err != nil {
 			globals.logger.Printf("[WARN] listDirectoryWrapper(dirInode.backend.context, listDirectoryInput) failed: %v", err)
 		}
 
-		globalsLock("fs.go:1139:3:prefetchDirectory")
+		globalsLock("fs.go:1536:3:prefetchDirectory")
 
 		dirInode, ok = globals.inodeMap.get(dirInodeNumber)
 		if !ok {
@@ -1245,8 +1642,10 @@ func (parentInode *inodeStruct) findChildDirInode(basename string) (childDirInod
 // `findChildFileInode` is called to locate, or create if missing, a child file inodeStruct.
 func (parentInode *inodeStruct) findChildFileInode(basename, eTag string, mTime time.Time, size uint64) (childFileInode *inodeStruct) {
 	var (
-		childDirInfo DirEntryInfo
-		ok           bool
+		backend        *backendStruct
+		childDirInfo   DirEntryInfo
+		objectBasename = basename
+		ok             bool
 	)
 
 	defer func() {
@@ -1254,6 +1653,16 @@ func (parentInode *inodeStruct) findChildFileInode(basename, eTag string, mTime
 		childFileInode.touch(nil)
 	}()
 
+	backend, ok = globals.backendMap[parentInode.backendNonce]
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.backendMap[parentInode.backendNonce] returned !ok")
+	}
+
+	// Emulated symlinks are known by their basename sans symlink_suffix
+
+	basename, _ = backend.symLinkBasename(objectBasename)
+
 	// First see if we already know about the childInode
 
 	childDirInfo, ok = globals.physChildDirEntryMap.getByBasename(parentInode.inodeNumber, basename)
@@ -1286,231 +1695,1543 @@ func (parentInode *inodeStruct) findChildFileInode(basename, eTag string, mTime
 
 	// We didn't already know about the childFileInode... so just create it
 
-	childFileInode = parentInode.createFileObjectInode(false, basename, size, eTag, mTime)
+	childFileInode = parentInode.createFileObjectInode(false, objectBasename, size, eTag, mTime)
 
 	return
 }
 
-const (
-	DUMP_FS_DIR_INDENT = "    "
-)
+// `needsRevalidation` is called while globals.Lock() is held to determine if the
+// backend object underlying a FileObject inode must be re-stat'd before any of
+// its cached content is served.
+func (inode *inodeStruct) needsRevalidation() bool {
+	if (inode.inodeType != FileObject) || inode.isVirt || inode.pendingDelete {
+		return false
+	}
 
-// `dumpFS` logs the entire file system. It must be called without holding globals.Lock().
-func dumpFS(w io.Writer) {
+	return time.Now().After(inode.vTime)
+}
+
+// `revalidateFileObjectInode` is called without holding globals.Lock() to re-stat
+// the backend object underlying a FileObject inode whose .vTime has passed. Should
+// the object's eTag or size have changed, the inode's attributes are refreshed. In
+// any event, Clean cache lines no longer matching the object's eTag are discarded.
+// The re-stat is conditioned on the inode's current eTag (i.e. If-None-Match) such
+// that backends able to answer "not modified" need not return the full metadata.
+func revalidateFileObjectInode(ctx context.Context, inodeNumber uint64) {
 	var (
-		ok           bool
-		rootDirInode *inodeStruct
+		backend              *backendStruct
+		cacheLineNumber      uint64
+		dataCacheLineNumber  uint64
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		err                  error
+		inode                *inodeStruct
+		objectChanged        bool
+		ok                   bool
+		statFileInput        *statFileInputStruct
+		statFileOutput       *statFileOutputStruct
 	)
 
-	globalsLock("fs.go:1305:2:dumpFS")
+	globalsLock("fs.go:1734:2:revalidateFileObjectInode")
 
-	rootDirInode, ok = globals.inodeMap.get(FUSERootDirInodeNumber)
-	if !ok {
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] globals.inodeMap.get(FUSERootDirInodeNumber) returned !ok")
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	if !ok || !inode.needsRevalidation() {
+		globalsUnlock()
+		return
 	}
-	if rootDirInode.inodeType != FUSERootDir {
+
+	backend, ok = globals.backendMap[inode.backendNonce]
+	if !ok {
 		dumpStack()
-		globals.logger.Fatalf("[FATAL] rootDirInode.inodeType(%v) should have been FUSERootDir(%v)", rootDirInode.inodeType, FUSERootDir)
+		globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce] returned !ok [case 1]")
 	}
 
-	rootDirInode.dumpFS(w, "", FUSERootDirInodeNumber, "")
+	statFileInput = &statFileInputStruct{
+		filePath:    inode.objectPath,
+		ifMatch:     "",
+		ifNoneMatch: inode.eTag,
+	}
 
 	globalsUnlock()
-}
 
-// `dumpFS` called on a particular inode recursively dumps a file system element.
-func (thisInode *inodeStruct) dumpFS(w io.Writer, indent string, expectedInodeNumber uint64, expectedBasename string) {
-	var (
-		backend                   *backendStruct
-		childDirInfo              DirEntryInfo
-		childInode                *inodeStruct
-		childInodeBasename        string
-		nextIndent                = indent + DUMP_FS_DIR_INDENT
-		ok                        bool
-		physChildDirEntryMapIndex uint64
-		physChildDirEntryMapLimit uint64
-		physChildDirEntryMapStart uint64
-		thisInodeBasename         string
-		virtChildDirEntryMapIndex uint64
-		virtChildDirEntryMapLimit uint64
-		virtChildDirEntryMapStart uint64
-	)
+	statFileOutput, err = statFileWrapper(ctx, backend.context, statFileInput)
 
-	if thisInode.inodeType != FUSERootDir {
-		backend, ok = globals.backendMap[thisInode.backendNonce]
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.backendMap[thisInode.backendNonce] returned !ok")
-		}
-	}
-	if thisInode.inodeNumber != expectedInodeNumber {
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] thisInode.inodeNumber(%v) != expectedInodeNumber(%v)", thisInode.inodeNumber, expectedInodeNumber)
-	}
-	if thisInode.basename != expectedBasename {
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] thisInode.basename(\"%s\") != expectedBasename(\"%s\")", thisInode.basename, expectedBasename)
-	}
+	globalsLock("fs.go:1758:2:revalidateFileObjectInode")
 
-	switch thisInode.inodeType {
-	case FileObject:
-		thisInodeBasename = "\"" + thisInode.basename + "\""
-	case FUSERootDir:
-		thisInodeBasename = "[FUSERootDir]"
-	case BackendRootDir:
-		thisInodeBasename = "[BackendRootDir] \"" + thisInode.basename + "\" (" + backend.backendPath + ")"
-	case PseudoDir:
-		thisInodeBasename = "[PseudoDir]      \"" + thisInode.basename + "\" (" + thisInode.objectPath + ")"
-	default:
-		dumpStack()
-		globals.logger.Fatalf("[FATAL] dirInode.inodeType should == FUSERootDir(%v) or BackendRootDir(%v) or PseudoDir(%v), not FileObject(%v) nor what was found: %v", FUSERootDir, BackendRootDir, PseudoDir, FileObject, thisInode.inodeType)
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	if !ok || !inode.needsRevalidation() {
+		globalsUnlock()
+		return
 	}
 
-	fmt.Fprintf(w, "%s%5d %s\n", indent, thisInode.inodeNumber, thisInodeBasename)
+	// Whatever the outcome, avoid re-stat'ing the object again until another attr_ttl has passed
 
-	if thisInode.inodeType == FileObject {
+	inode.vTime = time.Now().Add(backend.attrTTL)
+
+	if (err != nil) && !errors.Is(err, errFileNotFound) {
+		// Continue to serve what we have cached... a subsequent fetch() will surface any real problem
+		globals.logger.Printf("[WARN] revalidateFileObjectInode() got statFileWrapper(backend.context, statFileInput) err: %v", err)
+		globalsUnlock()
 		return
 	}
 
-	virtChildDirEntryMapStart, virtChildDirEntryMapLimit = globals.virtChildDirEntryMap.getIndexRange(thisInode.inodeNumber)
+	if (inode.outboundCacheLineCount + inode.dirtyCacheLineCount) > 0 {
+		// Locally modified content takes precedence over that in the backend
+		globalsUnlock()
+		return
+	}
 
-	for virtChildDirEntryMapIndex = virtChildDirEntryMapStart; virtChildDirEntryMapIndex < virtChildDirEntryMapLimit; virtChildDirEntryMapIndex++ {
-		childInodeBasename, childDirInfo, ok = globals.virtChildDirEntryMap.getByIndex(virtChildDirEntryMapIndex)
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.getByIndex(virtChildDirEntryMapIndex) returned !ok")
-		}
+	if err != nil {
+		// The object has been deleted from the backend... so, unless it remains open, forget
+		// the inode such that subsequent lookups find it is gone
 
-		if childInodeBasename == DotDirEntryBasename {
-			if childDirInfo.InodeNumber != thisInode.inodeNumber {
-				dumpStack()
-				globals.logger.Fatalf("[FATAL] childDirInfo.InodeNumber(%v) != thisInode.inodeNumber(%v) [case virt]", childDirInfo.InodeNumber, thisInode.inodeNumber)
-			}
-			fmt.Fprintf(w, "%s%5d \"%s\"\n", nextIndent, childDirInfo.InodeNumber, childInodeBasename)
-			continue
-		}
+		inode.touch(nil)
 
-		if childInodeBasename == DotDotDirEntryBasename {
-			if childDirInfo.InodeNumber != thisInode.parentInodeNumber {
-				dumpStack()
-				globals.logger.Fatalf("[FATAL] childDirInfo.InodeNumber(%v) != thisInode.parentInodeNumber(%v) [case virt]", childDirInfo.InodeNumber, thisInode.parentInodeNumber)
-			}
-			fmt.Fprintf(w, "%s%5d \"%s\"\n", nextIndent, childDirInfo.InodeNumber, childInodeBasename)
-			continue
+		if !inode.xTime.IsZero() {
+			inode.retireInodeHandle()
+			inode.evict()
 		}
 
-		childInode, ok = globals.inodeMap.get(childDirInfo.InodeNumber)
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childDirInfo.InodeNumber) returned !ok [case virt]")
+		globalsUnlock()
+
+		return
+	}
+
+	if statFileOutput.notModified {
+		if statFileOutput.eTag != inode.eTag {
+			// inode.eTag moved on while we were unlocked... so leave it to the next revalidation
+			globalsUnlock()
+			return
 		}
 
-		childInode.dumpFS(w, nextIndent, childDirInfo.InodeNumber, childInodeBasename)
+		// The object is unchanged... so its size & mTime are as previously recorded
+
+		statFileOutput.size = inode.sizeInBackend
+		statFileOutput.mTime = inode.mTime
 	}
 
-	physChildDirEntryMapStart, physChildDirEntryMapLimit = globals.physChildDirEntryMap.getIndexRange(thisInode.inodeNumber)
+	objectChanged = (inode.eTag != statFileOutput.eTag) || (inode.sizeInBackend != statFileOutput.size)
 
-	for physChildDirEntryMapIndex = physChildDirEntryMapStart; physChildDirEntryMapIndex < physChildDirEntryMapLimit; physChildDirEntryMapIndex++ {
-		childInodeBasename, childDirInfo, ok = globals.physChildDirEntryMap.getByIndex(thisInode.inodeNumber, physChildDirEntryMapIndex)
-		if !ok {
+	for cacheLineNumber, dataCacheLineNumber = range inode.cacheMap {
+		if dataCacheLineNumber >= uint64(len(globals.dataCacheLinesTracker)) {
 			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.getByIndex(physChildDirEntryMapIndex) returned !ok")
-		}
-
-		if childInodeBasename == DotDirEntryBasename {
-			if childDirInfo.InodeNumber != thisInode.inodeNumber {
-				dumpStack()
-				globals.logger.Fatalf("[FATAL] childDirInfo.InodeNumber(%v) != thisInode.inodeNumber(%v) [case phys]", childDirInfo.InodeNumber, thisInode.inodeNumber)
-			}
-			fmt.Fprintf(w, "%s%5d \"%s\"\n", nextIndent, childDirInfo.InodeNumber, childInodeBasename)
-			continue
+			globals.logger.Fatalf("[FATAL] inode.cacheMap[cacheLineNumber] returned out-of-range dataCacheLineNumber")
 		}
-
-		if childInodeBasename == DotDotDirEntryBasename {
-			if childDirInfo.InodeNumber != thisInode.parentInodeNumber {
-				dumpStack()
-				globals.logger.Fatalf("[FATAL] childDirInfo.InodeNumber(%v) != thisInode.parentInodeNumber(%v) [case phys]", childDirInfo.InodeNumber, thisInode.parentInodeNumber)
-			}
-			fmt.Fprintf(w, "%s%5d \"%s\"\n", nextIndent, childDirInfo.InodeNumber, childInodeBasename)
+		dataCacheLineTracker = &globals.dataCacheLinesTracker[dataCacheLineNumber]
+		if dataCacheLineTracker.state != CacheLineClean {
+			// Inbound cache lines will be revalidated next time around
 			continue
 		}
-
-		childInode, ok = globals.inodeMap.get(childDirInfo.InodeNumber)
-		if !ok {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childDirInfo.InodeNumber) returned !ok [case phys]")
+		if objectChanged || (dataCacheLineTracker.eTag != statFileOutput.eTag) {
+			delete(inode.cacheMap, cacheLineNumber)
+			globals.dataCacheLineCleanLRU.popThis(dataCacheLineTracker)
+			dataCacheLineTracker.free()
+			globals.backendMetrics.CacheLineInvalidations.Inc()
+			backend.backendMetrics.CacheLineInvalidations.Inc()
 		}
+	}
 
-		childInode.dumpFS(w, nextIndent, childDirInfo.InodeNumber, childInodeBasename)
+	if objectChanged {
+		inode.sizeInBackend = statFileOutput.size
+		inode.sizeInMemory = statFileOutput.size
+		inode.eTag = statFileOutput.eTag
+		inode.mTime = statFileOutput.mTime
+		inode.times.cTime = time.Now()
 	}
+
+	inode.touch(nil)
+
+	globalsUnlock()
 }
 
-// `finishPendingDelete` is called to finish the deletion of a
-// FileInode that includes removing the corresponding backend
-// object (if any). As this may involve blocking (e.g. to await
-// various cache line operations), this function must be called
-// while unlocked.
-func (thisInode *inodeStruct) finishPendingDelete() {
+// `openHandleStruct` describes an open fhStruct as reported via the /open-handles endpoint.
+type openHandleStruct struct {
+	fhNonce     uint64
+	inodeNumber uint64
+	isDir       bool
+	path        string // Relative to the FUSE mountpoint; includes trailing slash if isDir
+	openerUID   uint32
+	openerGID   uint32
+	openerPID   uint32
+	openTime    time.Time
+	allowWrites bool
+}
+
+// `listOpenHandles` returns a description of every open fhStruct sorted by .fhNonce.
+// It must be called without holding globals.Lock().
+func listOpenHandles() (openHandles []openHandleStruct) {
 	var (
-		backend              *backendStruct
-		cacheLineNumber      uint64
-		cacheLineWaiter      sync.WaitGroup
-		dataCacheLineNumber  uint64
-		dataCacheLineTracker *dataCacheLineTrackerStruct
-		deleteFileInput      *deleteFileInputStruct
-		err                  error
-		ok                   bool
-		parentInode          *inodeStruct
+		backend    *backendStruct
+		fh         *fhStruct
+		ok         bool
+		openHandle openHandleStruct
 	)
 
-Restart:
-
-	globalsLock("fs.go:1469:2:(*inodeStruct).finishPendingDelete")
+	globalsLock("fs.go:1869:2:listOpenHandles")
 
-	// Let's just drop cache lines that are either "clean" io "dirty"
+	openHandles = make([]openHandleStruct, 0, len(globals.fhMap))
 
-	for cacheLineNumber, dataCacheLineNumber = range thisInode.cacheMap {
-		if dataCacheLineNumber >= uint64(len(globals.dataCacheLinesTracker)) {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] inode.cacheMap[cacheLineNumber] returned out-of-range dataCacheLineNumber [case 1]")
+	for _, fh = range globals.fhMap {
+		openHandle = openHandleStruct{
+			fhNonce:     fh.nonce,
+			inodeNumber: fh.inode.inodeNumber,
+			isDir:       (fh.inode.inodeType != FileObject),
+			openerUID:   fh.openerUID,
+			openerGID:   fh.openerGID,
+			openerPID:   fh.openerPID,
+			openTime:    fh.openTime,
+			allowWrites: fh.allowWrites,
 		}
-		dataCacheLineTracker = &globals.dataCacheLinesTracker[dataCacheLineNumber]
-		switch dataCacheLineTracker.state {
-		case CacheLineClean:
-			delete(thisInode.cacheMap, cacheLineNumber)
-			globals.dataCacheLineCleanLRU.popThis(dataCacheLineTracker)
-			dataCacheLineTracker.free()
-		case CacheLineDirty:
-			delete(thisInode.cacheMap, cacheLineNumber)
-			globals.dataCacheLineDirtyLRU.popThis(dataCacheLineTracker)
-			if thisInode.dirtyCacheLineCount > 0 {
-				thisInode.dirtyCacheLineCount--
+
+		if fh.inode.inodeType == FUSERootDir {
+			openHandle.path = "/"
+		} else {
+			backend, ok = globals.backendMap[fh.inode.backendNonce]
+			if ok {
+				openHandle.path = "/" + backend.dirName + "/" + fh.inode.objectPath
+			} else {
+				openHandle.path = "<unknown>/" + fh.inode.objectPath
 			}
-			dataCacheLineTracker.free()
-		default:
-			// Nothing for now
 		}
-	}
 
-	// Now we need to await any pending "inbound" or "outbound" cache lines (though this shouldn't happen)
-
-	for _, dataCacheLineNumber = range thisInode.cacheMap {
-		if dataCacheLineNumber >= uint64(len(globals.dataCacheLinesTracker)) {
-			dumpStack()
-			globals.logger.Fatalf("[FATAL] inode.cacheMap returned out-of-range dataCacheLineNumber [case 2]")
-		}
-		dataCacheLineTracker = &globals.dataCacheLinesTracker[dataCacheLineNumber]
-		cacheLineWaiter.Add(1)
-		dataCacheLineTracker.waiters = append(dataCacheLineTracker.waiters, &cacheLineWaiter)
-		globalsUnlock()
-		cacheLineWaiter.Wait()
-		goto Restart
+		openHandles = append(openHandles, openHandle)
 	}
 
-	// Once we make it here, we need to atomically delete the object (if any)
+	globalsUnlock()
 
-	if !thisInode.isVirt {
+	slices.SortFunc(openHandles, func(a, b openHandleStruct) int {
+		return cmp.Compare(a.fhNonce, b.fhNonce)
+	})
+
+	return
+}
+
+// `listDirectoryResultStruct` carries the result of a background listDirectoryWrapper() call
+// launched by fetchListDirectory().
+type listDirectoryResultStruct struct {
+	listDirectoryOutput *listDirectoryOutputStruct
+	err                 error
+}
+
+// `fetchListDirectory` is called by DoReadDir() and DoReadDirPlus() to obtain the next page of
+// fh's directory listing. It must be called holding globals.Lock() which is released while the
+// listing is awaited. If budget == 0 (and no background call is pending), listDirectoryWrapper()
+// is called directly on behalf of ctx. Otherwise, the call is made in the background (or the
+// pending one awaited) and, should budget expire first, !ok is returned leaving the call's
+// result for the next readdir of fh. If ctx is canceled while waiting, err is its cause.
+func (fh *fhStruct) fetchListDirectory(ctx context.Context, backend *backendStruct, listDirectoryInput *listDirectoryInputStruct, budget time.Duration) (listDirectoryOutput *listDirectoryOutputStruct, ok bool, err error) {
+	var (
+		budgetC             <-chan time.Time
+		budgetTimer         *time.Timer
+		listDirectoryResult *listDirectoryResultStruct
+		pending             chan *listDirectoryResultStruct
+	)
+
+	fh.listDirectoryInProgress = true
+	fh.listDirectoryInProgressDone = make(chan struct{})
+
+	if (budget == 0) && (fh.listDirectoryPending == nil) {
+		globalsUnlock()
+
+		listDirectoryOutput, err = listDirectoryWrapper(ctx, backend.context, listDirectoryInput)
+
+		globalsLock("fs.go:1937:3:(*fhStruct).fetchListDirectory")
+
+		fh.listDirectoryInProgress = false
+		close(fh.listDirectoryInProgressDone)
+
+		ok = true
+		return
+	}
+
+	if fh.listDirectoryPending == nil {
+		// The result may be consumed by a subsequent readdir, so ctx's cancellation must not reach the call
+
+		fh.listDirectoryPending = make(chan *listDirectoryResultStruct, 1)
+
+		go func(ctx context.Context, pending chan *listDirectoryResultStruct) {
+			var (
+				listDirectoryResult = &listDirectoryResultStruct{}
+			)
+
+			listDirectoryResult.listDirectoryOutput, listDirectoryResult.err = listDirectoryWrapper(ctx, backend.context, listDirectoryInput)
+
+			pending <- listDirectoryResult
+		}(context.WithoutCancel(ctx), fh.listDirectoryPending)
+	}
+
+	pending = fh.listDirectoryPending
+
+	if budget != 0 {
+		budgetTimer = time.NewTimer(budget)
+		budgetC = budgetTimer.C
+	}
+
+	globalsUnlock()
+
+	select {
+	case listDirectoryResult = <-pending:
+	case <-budgetC:
+	case <-ctx.Done():
+	}
+
+	if budgetTimer != nil {
+		_ = budgetTimer.Stop()
+	}
+
+	globalsLock("fs.go:1981:2:(*fhStruct).fetchListDirectory")
+
+	fh.listDirectoryInProgress = false
+	close(fh.listDirectoryInProgressDone)
+
+	if listDirectoryResult == nil {
+		if ctx.Err() != nil {
+			err = context.Cause(ctx)
+			ok = true
+		} else {
+			ok = false
+		}
+		return
+	}
+
+	fh.listDirectoryPending = nil
+
+	listDirectoryOutput = listDirectoryResult.listDirectoryOutput
+	err = listDirectoryResult.err
+	ok = true
+	return
+}
+
+// `listDirectoryAnchorStruct` records where a page of an fhStruct's directory listing begins:
+// the readdir offset of its first file and the key after which it was listed.
+type listDirectoryAnchorStruct struct {
+	startingOffset uint64
+	startAfter     string // Relative to backend.prefix; if == "", the page began the directory
+}
+
+// `nextListDirectoryInput` returns the listDirectoryInputStruct requesting the page of fh's
+// listing of dirPath following fh.prevListDirectoryOutput. Rather than relying on the backend's
+// continuation token (which, for some backends, is merely an index into the directory's current
+// entries), the page is requested after the last file of the prior page so that objects added or
+// removed concurrently cannot cause entries to be skipped or repeated. The continuation token is
+// only used if the prior page held no files or if backend.keySaltWidth != 0 (as listed basenames
+// are then desalted and, for objects written before key_salt_width was set, the key they were
+// listed under cannot be recovered from them). Each such key-anchored page is remembered in
+// fh.listDirectoryAnchorList for rewindListDirectory(). It must be called holding globals.Lock().
+func (fh *fhStruct) nextListDirectoryInput(backend *backendStruct, dirPath string) (listDirectoryInput *listDirectoryInputStruct) {
+	var (
+		anchor            listDirectoryAnchorStruct
+		anchorListLen     = len(fh.listDirectoryAnchorList)
+		prevListDirectory = fh.prevListDirectoryOutput
+	)
+
+	listDirectoryInput = &listDirectoryInputStruct{
+		maxItems: backend.directoryPageSize,
+		dirPath:  dirPath,
+	}
+
+	switch {
+	case prevListDirectory == nil:
+		// Either the first page or the page being restarted by rewindListDirectory()
+
+		anchor.startingOffset = fh.nextListDirectoryOutputStartingOffset
+		if anchorListLen > 0 {
+			anchor.startAfter = fh.listDirectoryAnchorList[anchorListLen-1].startAfter
+		}
+	case (len(prevListDirectory.file) > 0) && (backend.keySaltWidth == 0):
+		anchor.startingOffset = fh.prevListDirectoryOutputStartingOffset + fh.prevListDirectoryOutputFileLen
+		anchor.startAfter = dirPath + prevListDirectory.file[len(prevListDirectory.file)-1].basename
+	default:
+		listDirectoryInput.continuationToken = prevListDirectory.nextContinuationToken
+		return
+	}
+
+	listDirectoryInput.startAfter = anchor.startAfter
+
+	if (anchorListLen == 0) || (anchor.startingOffset > fh.listDirectoryAnchorList[anchorListLen-1].startingOffset) {
+		fh.listDirectoryAnchorList = append(fh.listDirectoryAnchorList, anchor)
+	}
+
+	return
+}
+
+// `snapshotListDirectory` is called by DoOpenDir() for a backend with readdir_snapshot set to
+// enumerate fh's directory (up to backend.readDirSnapshotMaxEntries entries if != 0) before its
+// first readdir. Readdirs of fh are then served from this snapshot (held as a single
+// fh.prevListDirectoryOutput) such that tools like tar or rsync see a self-consistent directory
+// even as other clients add or remove objects. Should the limit be reached, the remainder of the
+// directory is listed page by page as usual. On failure, fh is left to list the directory page
+// by page. It must be called holding globals.Lock() which is released while listing.
+func (fh *fhStruct) snapshotListDirectory(ctx context.Context, backend *backendStruct) (err error) {
+	var (
+		entries             uint64
+		listDirectoryInput  *listDirectoryInputStruct
+		listDirectoryOutput *listDirectoryOutputStruct
+		ok                  bool
+		subdirectory        string
+	)
+
+	for {
+		listDirectoryInput = fh.nextListDirectoryInput(backend, fh.inode.objectPath)
+
+		listDirectoryOutput, _, err = fh.fetchListDirectory(ctx, backend, listDirectoryInput, 0)
+		if err != nil {
+			fh.listDirectoryAnchorList = fh.listDirectoryAnchorList[:0]
+			fh.listDirectorySequenceDone = false
+			fh.prevListDirectoryOutput = nil
+			fh.prevListDirectoryOutputFileLen = 0
+			fh.nextListDirectoryOutputStartingOffset = 0
+			return
+		}
+
+		if (len(listDirectoryOutput.file) > 0) || (len(listDirectoryOutput.subdirectory) > 0) {
+			fh.inode.convertToPhysInodeIfNecessary()
+		}
+
+		if fh.prevListDirectoryOutput == nil {
+			fh.prevListDirectoryOutput = &listDirectoryOutputStruct{
+				subdirectory: make([]string, 0),
+				file:         make([]listDirectoryOutputFileStruct, 0, len(listDirectoryOutput.file)),
+			}
+		}
+
+		fh.prevListDirectoryOutput.file = append(fh.prevListDirectoryOutput.file, listDirectoryOutput.file...)
+		fh.prevListDirectoryOutput.nextContinuationToken = listDirectoryOutput.nextContinuationToken
+		fh.prevListDirectoryOutput.isTruncated = listDirectoryOutput.isTruncated
+		fh.prevListDirectoryOutputFileLen = uint64(len(fh.prevListDirectoryOutput.file))
+		fh.nextListDirectoryOutputStartingOffset = fh.prevListDirectoryOutputFileLen
+
+		for _, subdirectory = range listDirectoryOutput.subdirectory {
+			_, ok = fh.listDirectorySubdirectorySet[subdirectory]
+			if !ok {
+				fh.listDirectorySubdirectorySet[subdirectory] = struct{}{}
+				fh.listDirectorySubdirectoryList = append(fh.listDirectorySubdirectoryList, subdirectory)
+			}
+		}
+
+		entries += uint64(len(listDirectoryOutput.file) + len(listDirectoryOutput.subdirectory))
+
+		fh.listDirectorySequenceDone = !listDirectoryOutput.isTruncated
+
+		if fh.listDirectorySequenceDone || ((backend.readDirSnapshotMaxEntries != 0) && (entries >= backend.readDirSnapshotMaxEntries)) {
+			return
+		}
+	}
+}
+
+// `rewindListDirectory` is called by DoReadDir() and DoReadDirPlus() when offset precedes the
+// pages of fh's listing still held (e.g. following a rewinddir()). Rather than skipping ahead to
+// those pages, the listing is restarted from the anchor of the page containing offset. Any
+// background listing is abandoned (its buffered channel lets the call complete unobserved). It
+// must be called holding globals.Lock().
+func (fh *fhStruct) rewindListDirectory(offset uint64) {
+	var (
+		anchor      listDirectoryAnchorStruct
+		anchorIndex int
+	)
+
+	anchorIndex = len(fh.listDirectoryAnchorList) - 1
+	for (anchorIndex > 0) && (fh.listDirectoryAnchorList[anchorIndex].startingOffset > offset) {
+		anchorIndex--
+	}
+
+	if anchorIndex >= 0 {
+		anchor = fh.listDirectoryAnchorList[anchorIndex]
+		fh.listDirectoryAnchorList = fh.listDirectoryAnchorList[:anchorIndex+1]
+	}
+
+	fh.listDirectoryPending = nil
+	fh.listDirectorySequenceDone = false
+
+	fh.prevListDirectoryOutput = nil
+	fh.prevListDirectoryOutputFileLen = 0
+	fh.prevListDirectoryOutputStartingOffset = anchor.startingOffset
+
+	fh.nextListDirectoryOutput = nil
+	fh.nextListDirectoryOutputFileLen = 0
+	fh.nextListDirectoryOutputStartingOffset = anchor.startingOffset
+}
+
+// `awaitListDirectory` is called by DoReadDir() and DoReadDirPlus() when fh.listDirectoryInProgress
+// indicates another readdir of fh is awaiting a listing page. Rather than failing, concurrent
+// readdirs of fh thus serialize behind the (at most one) listing in progress for it. It must be
+// called holding globals.Lock() which is released while waiting and re-acquired before returning.
+// As fh may have changed meanwhile, the caller must start over. If ctx is canceled while waiting,
+// err is its cause.
+func (fh *fhStruct) awaitListDirectory(ctx context.Context) (err error) {
+	var (
+		listDirectoryInProgressDone = fh.listDirectoryInProgressDone
+	)
+
+	globalsUnlock()
+
+	select {
+	case <-listDirectoryInProgressDone:
+		err = nil
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	}
+
+	globalsLock("fs.go:2174:2:(*fhStruct).awaitListDirectory")
+
+	return
+}
+
+// `readDirBudget` returns the budget to supply to fetchListDirectory() by a readdir begun at
+// startTime having already filled entries directory entries. No budget applies until at least
+// one entry is held (as returning none would indicate the end of the directory).
+func readDirBudget(backend *backendStruct, startTime time.Time, entries int) (budget time.Duration) {
+	if (backend.readDirTimeBudget == 0) || (entries == 0) {
+		budget = 0
+		return
+	}
+
+	budget = backend.readDirTimeBudget - time.Since(startTime)
+	if budget <= 0 {
+		budget = time.Nanosecond
+	}
+
+	return
+}
+
+// `forceReleaseFH` releases an open fhStruct as if DoRelease() or DoReleaseDir() had been
+// called. Subsequent use of fhNonce by the kernel will fail with EBADF. Returns !found if
+// fhNonce is not open or busy if it refers to a directory with a listing in progress.
+// It must be called without holding globals.Lock().
+func forceReleaseFH(fhNonce uint64) (found bool, busy bool) {
+	var (
+		fh    *fhStruct
+		inode *inodeStruct
+		ok    bool
+	)
+
+	globalsLock("fs.go:2207:2:forceReleaseFH")
+
+	fh, ok = globals.fhMap[fhNonce]
+	if !ok {
+		globalsUnlock()
+		return
+	}
+
+	found = true
+
+	if (fh.inode.inodeType != FileObject) && (fh.inode.inodeType != FUSERootDir) && fh.listDirectoryInProgress {
+		globalsUnlock()
+		busy = true
+		return
+	}
+
+	inode, ok = globals.inodeMap.get(fh.inode.inodeNumber)
+	if !ok {
+		inode = fh.inode
+	}
+
+	delete(inode.fhSet, fhNonce)
+	delete(globals.fhMap, fhNonce)
+
+	inode.touch(nil)
+
+	globals.logger.Printf("[WARN] force-released fh %v (inode %v) opened by pid %v uid %v gid %v at %v", fhNonce, inode.inodeNumber, fh.openerPID, fh.openerUID, fh.openerGID, fh.openTime.Format(time.RFC3339))
+
+	if (inode.inodeType != FileObject) || !inode.pendingDelete || (len(inode.fhSet) != 0) {
+		globalsUnlock()
+		return
+	}
+
+	globalsUnlock()
+
+	inode.finishPendingDelete(context.Background())
+
+	return
+}
+
+// `fetchObjectXAttrs` returns the extended attributes (see XAttrNamePrefix) exposing the backend
+// object metadata for the inode identified by inodeNumber. Only FileObject inodes present in their
+// backend have extended attributes. The values come from a statFileWrapper() call whose result is
+// cached (in globals.xattrCache) for the backend's attr_ttl such that e.g. a listxattr followed by
+// a getxattr of each name costs a single HEAD. The returned xattrMap must not be modified. It must
+// be called without holding globals.Lock().
+func fetchObjectXAttrs(ctx context.Context, inodeNumber uint64) (backend *backendStruct, xattrMap map[string][]byte, errno syscall.Errno) {
+	var (
+		err            error
+		inode          *inodeStruct
+		metadataKey    string
+		metadataValue  string
+		ok             bool
+		statFileInput  *statFileInputStruct
+		statFileOutput *statFileOutputStruct
+		xattrEntry     *xattrCacheEntryStruct
+	)
+
+	xattrMap = make(map[string][]byte)
+
+	globalsLock("fs.go:2267:2:fetchObjectXAttrs")
+
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	if !ok || inode.pendingDelete {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if inode.backendNonce != 0 {
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce] returned !ok")
+		}
+	}
+
+	if inode.inodeType != FileObject {
+		globalsUnlock()
+		errno = 0
+		return
+	}
+
+	inode.touch(nil)
+
+	xattrEntry, ok = globals.xattrCache[inodeNumber]
+	if ok && time.Now().Before(xattrEntry.expiration) {
+		xattrMap = xattrEntry.xattrMap
+		globalsUnlock()
+		errno = 0
+		return
+	}
+
+	statFileInput = &statFileInputStruct{
+		filePath: inode.objectPath,
+		ifMatch:  "",
+	}
+
+	globalsUnlock()
+
+	statFileOutput, err = statFileWrapper(ctx, backend.context, statFileInput)
+	if err != nil {
+		// Most likely, the object has yet to be flushed to the backend
+		errno = 0
+		return
+	}
+
+	if statFileOutput.eTag != "" {
+		xattrMap[XAttrNameETag] = []byte(statFileOutput.eTag)
+	}
+	if statFileOutput.storageClass != "" {
+		xattrMap[XAttrNameStorageClass] = []byte(statFileOutput.storageClass)
+	}
+	if statFileOutput.restoreStatus != "" {
+		xattrMap[XAttrNameRestoreStatus] = []byte(statFileOutput.restoreStatus)
+	}
+	for metadataKey, metadataValue = range statFileOutput.metadata {
+		xattrMap[XAttrNameMetadataPrefix+metadataKey] = []byte(metadataValue)
+	}
+
+	globalsLock("fs.go:2327:2:fetchObjectXAttrs")
+
+	if backend.attrTTL > 0 {
+		pruneXAttrCache()
+		globals.xattrCache[inodeNumber] = &xattrCacheEntryStruct{
+			xattrMap:   xattrMap,
+			expiration: time.Now().Add(backend.attrTTL),
+		}
+	}
+
+	globalsUnlock()
+
+	errno = 0
+	return
+}
+
+// `pruneXAttrCache` removes expired entries from globals.xattrCache. It must be called
+// while holding globals.Lock().
+func pruneXAttrCache() {
+	var (
+		inodeNumber uint64
+		now         = time.Now()
+		xattrEntry  *xattrCacheEntryStruct
+	)
+
+	for inodeNumber, xattrEntry = range globals.xattrCache {
+		if !now.Before(xattrEntry.expiration) {
+			delete(globals.xattrCache, inodeNumber)
+		}
+	}
+}
+
+// `presignObjectURL` returns a pre-signed GET URL (see XAttrNamePresignedURL) valid for
+// presigned_url_ttl for the object of the FileObject inode identified by inodeNumber. Should the
+// inode not be a FileObject or its backend not support such URLs, ENODATA is returned. It must be
+// called without holding globals.Lock().
+func presignObjectURL(ctx context.Context, inodeNumber uint64) (backend *backendStruct, presignedURL []byte, errno syscall.Errno) {
+	var (
+		err               error
+		inode             *inodeStruct
+		ok                bool
+		presignFileInput  *presignFileInputStruct
+		presignFileOutput *presignFileOutputStruct
+	)
+
+	globalsLock("fs.go:2372:2:presignObjectURL")
+
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	if !ok || inode.pendingDelete {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if inode.backendNonce != 0 {
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce] returned !ok")
+		}
+	}
+
+	if inode.inodeType != FileObject {
+		globalsUnlock()
+		errno = syscall.ENODATA
+		return
+	}
+
+	inode.touch(nil)
+
+	presignFileInput = &presignFileInputStruct{
+		filePath: inode.objectPath,
+		expires:  globals.config.presignedURLTTL,
+	}
+
+	globalsUnlock()
+
+	presignFileOutput, err = presignFileWrapper(ctx, backend.context, presignFileInput)
+	if err != nil {
+		if errors.Is(err, errPresignFileNotSupported) {
+			errno = syscall.ENODATA
+		} else {
+			globals.logger.Printf("[WARN] presignObjectURL() got presignFileWrapper(backends[\"%s\"].context, %#v) err: %v", backend.dirName, presignFileInput, err)
+			errno = syscall.EIO
+		}
+		return
+	}
+
+	presignedURL = []byte(presignFileOutput.url)
+
+	errno = 0
+	return
+}
+
+// `copyFileObject` performs a server-side copy of the object of the FileObject inode identified
+// by inodeNumber to dstPath (see XAttrNameCopyTo) on behalf of the requester identified by uid
+// and gid. Either dstPath must name a file in the same backend as the inode or both backends
+// must be S3 backends sharing a region and endpoint (such that CopyObject may name the source
+// bucket). Otherwise, EXDEV is returned and the object must instead be copied through the
+// client. Any inode already caching the destination picks up the copy when next revalidated
+// (see revalidateFileObjectInode()). It must be called without holding globals.Lock().
+func copyFileObject(ctx context.Context, inodeNumber uint64, dstPath string, uid, gid uint32) (errno syscall.Errno) {
+	var (
+		backend       *backendStruct
+		copyFileInput *copyFileInputStruct
+		dstBackend    *backendStruct
+		dstDirName    string
+		dstObjectPath string
+		dstS3Context  *s3ContextStruct
+		err           error
+		inode         *inodeStruct
+		ok            bool
+		pathElement   string
+		srcS3Context  *s3ContextStruct
+	)
+
+	globalsLock("fs.go:2443:2:copyFileObject")
+
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	if !ok || inode.pendingDelete {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if inode.backendNonce != 0 {
+		backend, ok = globals.backendMap[inode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce] returned !ok")
+		}
+	}
+
+	if inode.inodeType != FileObject {
+		globalsUnlock()
+		errno = syscall.EISDIR
+		return
+	}
+	if inode.isSymLink() {
+		globalsUnlock()
+		errno = syscall.EINVAL
+		return
+	}
+	if inode.isVirt || ((inode.outboundCacheLineCount + inode.dirtyCacheLineCount) != 0) {
+		// The object has yet to be (fully) flushed to the backend
+		globalsUnlock()
+		errno = syscall.EBUSY
+		return
+	}
+
+	if strings.HasPrefix(dstPath, globals.config.mountPoint+"/") {
+		dstPath = strings.TrimPrefix(dstPath, globals.config.mountPoint+"/")
+	}
+
+	dstDirName, dstObjectPath, ok = strings.Cut(dstPath, "/")
+	if !ok || (dstDirName == "") || (dstObjectPath == "") {
+		globalsUnlock()
+		errno = syscall.EINVAL
+		return
+	}
+	for _, pathElement = range strings.Split(dstObjectPath, "/") {
+		if (pathElement == "") || (pathElement == DotDirEntryBasename) || (pathElement == DotDotDirEntryBasename) {
+			globalsUnlock()
+			errno = syscall.EINVAL
+			return
+		}
+	}
+
+	dstBackend, ok = globals.config.backends[dstDirName]
+	if !ok || !dstBackend.mounted {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+	if !dstBackend.acl.permits(uid, gid) {
+		globalsUnlock()
+		errno = syscall.EACCES
+		return
+	}
+	if dstBackend.readOnly {
+		globalsUnlock()
+		errno = syscall.EROFS
+		return
+	}
+
+	copyFileInput = &copyFileInputStruct{
+		srcFilePath: inode.objectPath,
+		dstFilePath: dstObjectPath,
+		ifMatch:     inode.eTag,
+	}
+
+	if dstBackend != backend {
+		srcS3Context, ok = unwrapBackendContext(backend.context).(*s3ContextStruct)
+		if ok {
+			dstS3Context, ok = unwrapBackendContext(dstBackend.context).(*s3ContextStruct)
+		}
+		if !ok || !dstS3Context.sameService(srcS3Context) {
+			globalsUnlock()
+			errno = syscall.EXDEV
+			return
+		}
+
+		copyFileInput.srcContext = backend.context
+	}
+
+	inode.touch(nil)
+
+	globalsUnlock()
+
+	_, err = copyFileWrapper(ctx, dstBackend.context, copyFileInput)
+	if err != nil {
+		switch {
+		case errors.Is(err, errCopyFileNotSupported):
+			errno = syscall.EXDEV
+		case errors.Is(err, errFileNotFound):
+			errno = syscall.ENOENT
+		case errors.Is(err, errS3SnapshotReadOnly) || errors.Is(err, errS3VersionsReadOnly):
+			errno = syscall.EROFS
+		default:
+			globals.logger.Printf("[WARN] copyFileObject() got copyFileWrapper(backends[\"%s\"].context, %#v) err: %v", dstBackend.dirName, copyFileInput, err)
+			errno = syscall.EIO
+		}
+		return
+	}
+
+	errno = 0
+	return
+}
+
+// `archivedFileErrno` returns the errno to report for a read of the object at filePath that
+// failed because its content is archived (see errObjectArchived). If a restore of the content
+// is already underway (or complete but not yet visible), or one is successfully requested
+// (see restore_archived_objects), EAGAIN is returned so that the read may later be retried.
+// Otherwise, EREMOTEIO is returned. It must be called without holding globals.Lock().
+func archivedFileErrno(ctx context.Context, backend *backendStruct, filePath string) (errno syscall.Errno) {
+	var (
+		err               error
+		restoreFileOutput *restoreFileOutputStruct
+		statFileOutput    *statFileOutputStruct
+	)
+
+	statFileOutput, err = statFileWrapper(ctx, backend.context, &statFileInputStruct{filePath: filePath})
+	if (err == nil) && ((statFileOutput.restoreStatus == restoreStatusOngoing) || (statFileOutput.restoreStatus == restoreStatusRestored)) {
+		errno = syscall.EAGAIN
+		return
+	}
+
+	restoreFileOutput, err = restoreFileWrapper(ctx, backend.context, &restoreFileInputStruct{filePath: filePath})
+	if err != nil {
+		if !errors.Is(err, errRestoreFileNotSupported) {
+			globals.logger.Printf("[WARN] unable to restore archived \"%s\" in backends[\"%s\"]: %v", filePath, backend.dirName, err)
+		}
+		errno = syscall.EREMOTEIO
+		return
+	}
+
+	if !restoreFileOutput.alreadyInProgress {
+		globals.logger.Printf("[INFO] requested restore of archived \"%s\" in backends[\"%s\"]", filePath, backend.dirName)
+	}
+
+	errno = syscall.EAGAIN
+	return
+}
+
+const (
+	DUMP_FS_DIR_INDENT = "    "
+)
+
+// `dumpFS` logs the entire file system. It must be called without holding globals.Lock().
+func dumpFS(w io.Writer) {
+	var (
+		ok           bool
+		rootDirInode *inodeStruct
+	)
+
+	globalsLock("fs.go:2602:2:dumpFS")
+
+	rootDirInode, ok = globals.inodeMap.get(FUSERootDirInodeNumber)
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.inodeMap.get(FUSERootDirInodeNumber) returned !ok")
+	}
+	if rootDirInode.inodeType != FUSERootDir {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] rootDirInode.inodeType(%v) should have been FUSERootDir(%v)", rootDirInode.inodeType, FUSERootDir)
+	}
+
+	rootDirInode.dumpFS(w, "", FUSERootDirInodeNumber, "")
+
+	globalsUnlock()
+}
+
+// `dumpFS` called on a particular inode recursively dumps a file system element.
+func (thisInode *inodeStruct) dumpFS(w io.Writer, indent string, expectedInodeNumber uint64, expectedBasename string) {
+	var (
+		backend                   *backendStruct
+		childDirInfo              DirEntryInfo
+		childInode                *inodeStruct
+		childInodeBasename        string
+		nextIndent                = indent + DUMP_FS_DIR_INDENT
+		ok                        bool
+		physChildDirEntryMapIndex uint64
+		physChildDirEntryMapLimit uint64
+		physChildDirEntryMapStart uint64
+		thisInodeBasename         string
+		virtChildDirEntryMapIndex uint64
+		virtChildDirEntryMapLimit uint64
+		virtChildDirEntryMapStart uint64
+	)
+
+	if thisInode.inodeType != FUSERootDir {
+		backend, ok = globals.backendMap[thisInode.backendNonce]
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.backendMap[thisInode.backendNonce] returned !ok")
+		}
+	}
+	if thisInode.inodeNumber != expectedInodeNumber {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] thisInode.inodeNumber(%v) != expectedInodeNumber(%v)", thisInode.inodeNumber, expectedInodeNumber)
+	}
+	if thisInode.basename != expectedBasename {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] thisInode.basename(\"%s\") != expectedBasename(\"%s\")", thisInode.basename, expectedBasename)
+	}
+
+	switch thisInode.inodeType {
+	case FileObject:
+		thisInodeBasename = "\"" + thisInode.basename + "\""
+	case FUSERootDir:
+		thisInodeBasename = "[FUSERootDir]"
+	case BackendRootDir:
+		thisInodeBasename = "[BackendRootDir] \"" + thisInode.basename + "\" (" + backend.backendPath + ")"
+	case PseudoDir:
+		thisInodeBasename = "[PseudoDir]      \"" + thisInode.basename + "\" (" + thisInode.objectPath + ")"
+	default:
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] dirInode.inodeType should == FUSERootDir(%v) or BackendRootDir(%v) or PseudoDir(%v), not FileObject(%v) nor what was found: %v", FUSERootDir, BackendRootDir, PseudoDir, FileObject, thisInode.inodeType)
+	}
+
+	fmt.Fprintf(w, "%s%5d %s\n", indent, thisInode.inodeNumber, thisInodeBasename)
+
+	if thisInode.inodeType == FileObject {
+		return
+	}
+
+	virtChildDirEntryMapStart, virtChildDirEntryMapLimit = globals.virtChildDirEntryMap.getIndexRange(thisInode.inodeNumber)
+
+	for virtChildDirEntryMapIndex = virtChildDirEntryMapStart; virtChildDirEntryMapIndex < virtChildDirEntryMapLimit; virtChildDirEntryMapIndex++ {
+		childInodeBasename, childDirInfo, ok = globals.virtChildDirEntryMap.getByIndex(virtChildDirEntryMapIndex)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.getByIndex(virtChildDirEntryMapIndex) returned !ok")
+		}
+
+		if childInodeBasename == DotDirEntryBasename {
+			if childDirInfo.InodeNumber != thisInode.inodeNumber {
+				dumpStack()
+				globals.logger.Fatalf("[FATAL] childDirInfo.InodeNumber(%v) != thisInode.inodeNumber(%v) [case virt]", childDirInfo.InodeNumber, thisInode.inodeNumber)
+			}
+			fmt.Fprintf(w, "%s%5d \"%s\"\n", nextIndent, childDirInfo.InodeNumber, childInodeBasename)
+			continue
+		}
+
+		if childInodeBasename == DotDotDirEntryBasename {
+			if childDirInfo.InodeNumber != thisInode.parentInodeNumber {
+				dumpStack()
+				globals.logger.Fatalf("[FATAL] childDirInfo.InodeNumber(%v) != thisInode.parentInodeNumber(%v) [case virt]", childDirInfo.InodeNumber, thisInode.parentInodeNumber)
+			}
+			fmt.Fprintf(w, "%s%5d \"%s\"\n", nextIndent, childDirInfo.InodeNumber, childInodeBasename)
+			continue
+		}
+
+		childInode, ok = globals.inodeMap.get(childDirInfo.InodeNumber)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childDirInfo.InodeNumber) returned !ok [case virt]")
+		}
+
+		childInode.dumpFS(w, nextIndent, childDirInfo.InodeNumber, childInodeBasename)
+	}
+
+	physChildDirEntryMapStart, physChildDirEntryMapLimit = globals.physChildDirEntryMap.getIndexRange(thisInode.inodeNumber)
+
+	for physChildDirEntryMapIndex = physChildDirEntryMapStart; physChildDirEntryMapIndex < physChildDirEntryMapLimit; physChildDirEntryMapIndex++ {
+		childInodeBasename, childDirInfo, ok = globals.physChildDirEntryMap.getByIndex(thisInode.inodeNumber, physChildDirEntryMapIndex)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.getByIndex(physChildDirEntryMapIndex) returned !ok")
+		}
+
+		if childInodeBasename == DotDirEntryBasename {
+			if childDirInfo.InodeNumber != thisInode.inodeNumber {
+				dumpStack()
+				globals.logger.Fatalf("[FATAL] childDirInfo.InodeNumber(%v) != thisInode.inodeNumber(%v) [case phys]", childDirInfo.InodeNumber, thisInode.inodeNumber)
+			}
+			fmt.Fprintf(w, "%s%5d \"%s\"\n", nextIndent, childDirInfo.InodeNumber, childInodeBasename)
+			continue
+		}
+
+		if childInodeBasename == DotDotDirEntryBasename {
+			if childDirInfo.InodeNumber != thisInode.parentInodeNumber {
+				dumpStack()
+				globals.logger.Fatalf("[FATAL] childDirInfo.InodeNumber(%v) != thisInode.parentInodeNumber(%v) [case phys]", childDirInfo.InodeNumber, thisInode.parentInodeNumber)
+			}
+			fmt.Fprintf(w, "%s%5d \"%s\"\n", nextIndent, childDirInfo.InodeNumber, childInodeBasename)
+			continue
+		}
+
+		childInode, ok = globals.inodeMap.get(childDirInfo.InodeNumber)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.inodeMap.get(childDirInfo.InodeNumber) returned !ok [case phys]")
+		}
+
+		childInode.dumpFS(w, nextIndent, childDirInfo.InodeNumber, childInodeBasename)
+	}
+}
+
+// `renameFileObject` is called to rename the FileObject inode at oldBasename in directory inode
+// oldDirInodeNumber to newBasename in directory inode newDirInodeNumber. As object stores provide
+// no rename operation, the object (if any) is copied server-side to its new path and then deleted
+// from its old path. A FileObject inode already at the new path is replaced unless noReplace is
+// set. Renaming a directory inode or renaming across backends fails with EXDEV so that callers
+// (e.g. `mv`) fall back to copying.
+//
+// The backend operations are performed while globals.Lock() is not held. The inodes involved are
+// recorded in globals.renamesInFlight meanwhile (so that a concurrent rename of either fails with
+// EBUSY) and the inode state is re-validated once globals.Lock() is reacquired. Should the delete
+// of the old object fail following its copy, EIO is returned (see renameFileObjectInBackend()).
+// Should the inode state have changed once the backend move has completed, the inodes cached at
+// both names are forgotten (if not open) such that they are next looked up afresh.
+func renameFileObject(ctx context.Context, oldDirInodeNumber uint64, oldBasename string, newDirInodeNumber uint64, newBasename string, noReplace bool) (backend *backendStruct, errno syscall.Errno) {
+	var (
+		copyFileOutput *copyFileOutputStruct
+		dstInode       *inodeStruct
+		dstIsVirt      bool
+		dstObjectPath  string
+		newDirInode    *inodeStruct
+		newObjectPath  string
+		ok             bool
+		oldDirInode    *inodeStruct
+		srcETag        string
+		srcInode       *inodeStruct
+		srcIsVirt      bool
+		srcObjectPath  string
+	)
+
+	globalsLock("fs.go:2775:2:renameFileObject")
+
+	oldDirInode, ok = globals.inodeMap.get(oldDirInodeNumber)
+	if !ok {
+		globalsUnlock()
+		errno = syscall.ENOTDIR
+		return
+	}
+	newDirInode, ok = globals.inodeMap.get(newDirInodeNumber)
+	if !ok {
+		globalsUnlock()
+		errno = syscall.ENOTDIR
+		return
+	}
+
+	if (oldDirInode.inodeType == FileObject) || (newDirInode.inodeType == FileObject) {
+		globalsUnlock()
+		errno = syscall.ENOTDIR
+		return
+	}
+	if (oldDirInode.inodeType == FUSERootDir) || (newDirInode.inodeType == FUSERootDir) {
+		globalsUnlock()
+		errno = fuseRootDirWriteErrno()
+		return
+	}
+
+	backend, ok = globals.backendMap[oldDirInode.backendNonce]
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.backendMap[oldDirInode.backendNonce] returned !ok")
+	}
+
+	if newDirInode.backendNonce != oldDirInode.backendNonce {
+		globalsUnlock()
+		errno = syscall.EXDEV
+		return
+	}
+	if backend.readOnly || backend.inVersionsDir(oldDirInode.objectPath+oldBasename) || backend.inVersionsDir(newDirInode.objectPath+newBasename) {
+		globalsUnlock()
+		errno = syscall.EROFS
+		return
+	}
+
+	srcInode, ok = oldDirInode.findChildInode(ctx, oldBasename)
+	if !ok || srcInode.pendingDelete {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+	if srcInode.inodeType != FileObject {
+		globalsUnlock()
+		errno = syscall.EXDEV
+		return
+	}
+	if _, ok = globals.renamesInFlight[srcInode.inodeNumber]; ok {
+		globalsUnlock()
+		errno = syscall.EBUSY
+		return
+	}
+	if (srcInode.inboundCacheLineCount + srcInode.outboundCacheLineCount + srcInode.dirtyCacheLineCount) != 0 {
+		globalsUnlock()
+		errno = syscall.EBUSY
+		return
+	}
+
+	dstInode, ok = newDirInode.findChildInode(ctx, newBasename)
+	if ok {
+		if dstInode == srcInode {
+			globalsUnlock()
+			errno = 0
+			return
+		}
+		if noReplace && !dstInode.pendingDelete {
+			globalsUnlock()
+			errno = syscall.EEXIST
+			return
+		}
+		if dstInode.inodeType != FileObject {
+			globalsUnlock()
+			errno = syscall.EISDIR
+			return
+		}
+		if _, ok = globals.renamesInFlight[dstInode.inodeNumber]; ok {
+			globalsUnlock()
+			errno = syscall.EBUSY
+			return
+		}
+		if dstInode.pendingDelete || (len(dstInode.fhSet) != 0) || ((dstInode.inboundCacheLineCount + dstInode.outboundCacheLineCount + dstInode.dirtyCacheLineCount) != 0) {
+			globalsUnlock()
+			errno = syscall.EBUSY
+			return
+		}
+	} else {
+		dstInode = nil
+	}
+
+	if newDirInode.objectPath == "" {
+		newObjectPath = newBasename
+	} else {
+		newObjectPath = newDirInode.objectPath + newBasename
+	}
+	if srcInode.isSymLink() {
+		newObjectPath += backend.symLinkSuffix
+	}
+
+	srcETag = srcInode.eTag
+	srcIsVirt = srcInode.isVirt
+	srcObjectPath = srcInode.objectPath
+
+	globals.renamesInFlight[srcInode.inodeNumber] = struct{}{}
+
+	if dstInode != nil {
+		dstIsVirt = dstInode.isVirt
+		dstObjectPath = dstInode.objectPath
+
+		globals.renamesInFlight[dstInode.inodeNumber] = struct{}{}
+	}
+
+	globalsUnlock()
+
+	// Move the object (if any) in the backend (replacing any object already at newObjectPath)
+
+	copyFileOutput, errno = renameFileObjectInBackend(ctx, backend, srcIsVirt, srcObjectPath, srcETag, (dstInode != nil) && !dstIsVirt, dstObjectPath, newObjectPath)
+
+	globalsLock("fs.go:2899:2:renameFileObject")
+
+	delete(globals.renamesInFlight, srcInode.inodeNumber)
+	if dstInode != nil {
+		delete(globals.renamesInFlight, dstInode.inodeNumber)
+	}
+
+	if errno != 0 {
+		globalsUnlock()
+		return
+	}
+
+	// Ensure nothing moved underneath us while globals.Lock() was not held
+
+	oldDirInode, srcInode, newDirInode, dstInode, ok = renameFileObjectRefetch(oldDirInodeNumber, oldBasename, srcInode.inodeNumber, srcObjectPath, newDirInodeNumber, newBasename, dstInode)
+	if !ok {
+		// The backend move has nonetheless completed... so, rather than leave the inode tree
+		// presenting the object at its old path, forget what is cached at both names
+
+		forgetChildFileObjectInode(oldDirInodeNumber, oldBasename)
+		forgetChildFileObjectInode(newDirInodeNumber, newBasename)
+		globalsUnlock()
+		globals.logger.Printf("[WARN] renameFileObject() found \"%s\" or \"%s\" changed while moving its object to \"%s\"", oldBasename, newBasename, newObjectPath)
+		errno = 0
+		return
+	}
+
+	// Drop the replaced inode (if any) as if it had been evicted
+
+	if dstInode != nil {
+		if !dstInode.xTime.IsZero() {
+			ok = globals.inodeEvictionQueue.remove(dstInode)
+			if !ok {
+				dumpStack()
+				globals.logger.Fatalf("[FATAL] globals.inodeEvictionQueue.remove(dstInode) returned !ok")
+			}
+			dstInode.xTime = time.Time{}
+		}
+
+		clearFileCacheLinesLocked(dstInode)
+
+		if dstInode.isVirt {
+			ok = globals.virtChildDirEntryMap.delete(newDirInode.inodeNumber, dstInode.basename)
+			if !ok {
+				dumpStack()
+				globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(newDirInode.inodeNumber, dstInode.basename) returned !ok")
+			}
+		} else {
+			ok = globals.physChildDirEntryMap.delete(newDirInode.inodeNumber, dstInode.basename)
+			if !ok {
+				dumpStack()
+				globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.delete(newDirInode.inodeNumber, dstInode.basename) returned !ok")
+			}
+		}
+
+		dstInode.retireInodeHandle()
+
+		ok = globals.inodeMap.delete(dstInode.inodeNumber)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(dstInode.inodeNumber) returned !ok")
+		}
+
+		dstInode.pruneInodeHandle()
+	}
+
+	// Now move srcInode from oldDirInode to newDirInode
+
+	if srcInode.isVirt {
+		ok = globals.virtChildDirEntryMap.delete(oldDirInode.inodeNumber, srcInode.basename)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.delete(oldDirInode.inodeNumber, srcInode.basename) returned !ok")
+		}
+		ok = globals.virtChildDirEntryMap.put(newDirInode.inodeNumber, newBasename, srcInode.inodeNumber)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.virtChildDirEntryMap.put(newDirInode.inodeNumber, newBasename, srcInode.inodeNumber) returned !ok")
+		}
+	} else {
+		newDirInode.convertToPhysDirPathIfNecessary()
+
+		ok = globals.physChildDirEntryMap.delete(oldDirInode.inodeNumber, srcInode.basename)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.delete(oldDirInode.inodeNumber, srcInode.basename) returned !ok")
+		}
+		ok = globals.physChildDirEntryMap.put(newDirInode.inodeNumber, newBasename, srcInode.inodeNumber)
+		if !ok {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] globals.physChildDirEntryMap.put(newDirInode.inodeNumber, newBasename, srcInode.inodeNumber) returned !ok")
+		}
+	}
+
+	srcInode.retireInodeHandle()
+
+	srcInode.parentInodeNumber = newDirInode.inodeNumber
+	srcInode.objectPath = newObjectPath
+	srcInode.basename = newBasename
+	srcInode.times.cTime = time.Now()
+
+	if copyFileOutput.eTag != srcInode.eTag {
+		// Cached content was fetched with the old eTag, so drop it (only clean lines remain)
+
+		clearFileCacheLinesLocked(srcInode)
+
+		srcInode.eTag = copyFileOutput.eTag
+	}
+
+	oldDirInode.touch(nil)
+	newDirInode.touch(nil)
+	srcInode.touch(nil)
+
+	globalsUnlock()
+
+	errno = 0
+	return
+}
+
+// `renameFileObjectInBackend` is called by renameFileObject() while globals.Lock() is not held
+// to copy the object at srcObjectPath (unless srcIsVirt) to newObjectPath and then delete it. A
+// source object already deleted (e.g. by another client) is not an error. If dstIsPhys is set and
+// srcIsVirt, the (replaced) object at dstObjectPath is deleted instead. Should the delete of the
+// old object otherwise fail, EIO is returned. In that case, the copy is deleted (best effort) only
+// if it did not replace an object at newObjectPath (whose original content is then already gone);
+// otherwise, both objects are left in place.
+func renameFileObjectInBackend(ctx context.Context, backend *backendStruct, srcIsVirt bool, srcObjectPath, srcETag string, dstIsPhys bool, dstObjectPath, newObjectPath string) (copyFileOutput *copyFileOutputStruct, errno syscall.Errno) {
+	var (
+		copyFileInput *copyFileInputStruct
+		err           error
+	)
+
+	if srcIsVirt {
+		copyFileOutput = &copyFileOutputStruct{
+			eTag: srcETag,
+		}
+
+		if dstIsPhys {
+			_, err = deleteFileWrapper(ctx, backend.context, &deleteFileInputStruct{
+				filePath: dstObjectPath,
+				ifMatch:  "",
+			})
+			if err != nil {
+				globals.logger.Printf("[WARN] renameFileObject() got deleteFileWrapper(backend.context, &deleteFileInputStruct{filePath: \"%s\"}) err: %v", dstObjectPath, err)
+				errno = syscall.EIO
+				return
+			}
+		}
+
+		errno = 0
+		return
+	}
+
+	copyFileInput = &copyFileInputStruct{
+		srcFilePath: srcObjectPath,
+		dstFilePath: newObjectPath,
+		ifMatch:     srcETag,
+	}
+
+	copyFileOutput, err = copyFileWrapper(ctx, backend.context, copyFileInput)
+	if err != nil {
+		if errors.Is(err, errCopyFileNotSupported) {
+			errno = syscall.EXDEV
+		} else {
+			globals.logger.Printf("[WARN] renameFileObject() got copyFileWrapper(backend.context, %#v) err: %v", copyFileInput, err)
+			errno = syscall.EIO
+		}
+		return
+	}
+
+	_, err = deleteFileWrapper(ctx, backend.context, &deleteFileInputStruct{
+		filePath: srcObjectPath,
+		ifMatch:  "",
+	})
+	if (err != nil) && !errors.Is(err, errFileNotFound) {
+		globals.logger.Printf("[WARN] renameFileObject() got deleteFileWrapper(backend.context, &deleteFileInputStruct{filePath: \"%s\"}) err: %v", srcObjectPath, err)
+
+		if dstIsPhys && (dstObjectPath == newObjectPath) {
+			// The copy replaced the destination's content... so deleting it would lose both
+			errno = syscall.EIO
+			return
+		}
+
+		_, err = deleteFileWrapper(ctx, backend.context, &deleteFileInputStruct{
+			filePath: newObjectPath,
+			ifMatch:  copyFileOutput.eTag,
+		})
+		if err != nil {
+			globals.logger.Printf("[WARN] renameFileObject() got deleteFileWrapper(backend.context, &deleteFileInputStruct{filePath: \"%s\"}) err: %v rolling back", newObjectPath, err)
+		}
+
+		errno = syscall.EIO
+		return
+	}
+
+	errno = 0
+	return
+}
+
+// `renameFileObjectRefetch` is called by renameFileObject() once globals.Lock() has been
+// reacquired to re-fetch the inodes involved (as they may have been paged out and back in) and
+// confirm that none of them was evicted, deleted, or moved while the backend operations were
+// underway. If dstInode was nil, the returned dstInode will also be nil.
+func renameFileObjectRefetch(oldDirInodeNumber uint64, oldBasename string, srcInodeNumber uint64, srcObjectPath string, newDirInodeNumber uint64, newBasename string, dstInode *inodeStruct) (oldDirInode, srcInode, newDirInode, newDstInode *inodeStruct, ok bool) {
+	oldDirInode, ok = globals.inodeMap.get(oldDirInodeNumber)
+	if !ok {
+		return
+	}
+	newDirInode, ok = globals.inodeMap.get(newDirInodeNumber)
+	if !ok {
+		return
+	}
+	srcInode, ok = globals.inodeMap.get(srcInodeNumber)
+	if !ok {
+		return
+	}
+
+	ok = !srcInode.pendingDelete &&
+		(srcInode.parentInodeNumber == oldDirInodeNumber) &&
+		(srcInode.basename == oldBasename) &&
+		(srcInode.objectPath == srcObjectPath) &&
+		((srcInode.inboundCacheLineCount + srcInode.outboundCacheLineCount + srcInode.dirtyCacheLineCount) == 0)
+	if !ok || (dstInode == nil) {
+		return
+	}
+
+	newDstInode, ok = globals.inodeMap.get(dstInode.inodeNumber)
+	if !ok {
+		return
+	}
+
+	ok = !newDstInode.pendingDelete &&
+		(newDstInode.parentInodeNumber == newDirInodeNumber) &&
+		(newDstInode.basename == newBasename) &&
+		(len(newDstInode.fhSet) == 0) &&
+		((newDstInode.inboundCacheLineCount + newDstInode.outboundCacheLineCount + newDstInode.dirtyCacheLineCount) == 0)
+
+	return
+}
+
+// `forgetChildFileObjectInode` is called by renameFileObject() while holding globals.Lock() to
+// evict the (physical) FileObject inode, if any, cached at basename in the directory inode
+// dirInodeNumber such that a subsequent lookup finds whatever the backend then holds. Inodes that
+// are open (and hence not on globals.inodeEvictionQueue) are left to revalidateFileObjectInode().
+func forgetChildFileObjectInode(dirInodeNumber uint64, basename string) {
+	var (
+		childDirInfo DirEntryInfo
+		childInode   *inodeStruct
+		ok           bool
+	)
+
+	_, ok = globals.inodeMap.get(dirInodeNumber)
+	if !ok {
+		return
+	}
+
+	childDirInfo, ok = globals.physChildDirEntryMap.getByBasename(dirInodeNumber, basename)
+	if !ok {
+		return
+	}
+
+	childInode, ok = globals.inodeMap.get(childDirInfo.InodeNumber)
+	if !ok || (childInode.inodeType != FileObject) || childInode.xTime.IsZero() {
+		return
+	}
+
+	childInode.retireInodeHandle()
+	childInode.evict()
+}
+
+// `finishPendingDelete` is called to finish the deletion of a
+// FileInode that includes removing the corresponding backend
+// object (if any). As this may involve blocking (e.g. to await
+// various cache line operations or the backend delete), this
+// function must be called while unlocked.
+func (thisInode *inodeStruct) finishPendingDelete(ctx context.Context) {
+	var (
+		backend              *backendStruct
+		cacheLineNumber      uint64
+		cacheLineWaiter      sync.WaitGroup
+		dataCacheLineNumber  uint64
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		deleteFileInput      *deleteFileInputStruct
+		err                  error
+		inodeNumber          uint64
+		ok                   bool
+		parentInode          *inodeStruct
+	)
+
+Restart:
+
+	globalsLock("fs.go:3190:2:(*inodeStruct).finishPendingDelete")
+
+	// Let's just drop cache lines that are either "clean" io "dirty"
+
+	for cacheLineNumber, dataCacheLineNumber = range thisInode.cacheMap {
+		if dataCacheLineNumber >= uint64(len(globals.dataCacheLinesTracker)) {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] inode.cacheMap[cacheLineNumber] returned out-of-range dataCacheLineNumber [case 1]")
+		}
+		dataCacheLineTracker = &globals.dataCacheLinesTracker[dataCacheLineNumber]
+		switch dataCacheLineTracker.state {
+		case CacheLineClean:
+			delete(thisInode.cacheMap, cacheLineNumber)
+			globals.dataCacheLineCleanLRU.popThis(dataCacheLineTracker)
+			dataCacheLineTracker.free()
+		case CacheLineDirty:
+			delete(thisInode.cacheMap, cacheLineNumber)
+			globals.dataCacheLineDirtyLRU.popThis(dataCacheLineTracker)
+			if thisInode.dirtyCacheLineCount > 0 {
+				thisInode.dirtyCacheLineCount--
+			}
+			dataCacheLineTracker.free()
+		default:
+			// Nothing for now
+		}
+	}
+
+	// Now we need to await any pending "inbound" or "outbound" cache lines (though this shouldn't happen)
+
+	for _, dataCacheLineNumber = range thisInode.cacheMap {
+		if dataCacheLineNumber >= uint64(len(globals.dataCacheLinesTracker)) {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] inode.cacheMap returned out-of-range dataCacheLineNumber [case 2]")
+		}
+		dataCacheLineTracker = &globals.dataCacheLinesTracker[dataCacheLineNumber]
+		cacheLineWaiter.Add(1)
+		dataCacheLineTracker.waiters = append(dataCacheLineTracker.waiters, &cacheLineWaiter)
+		globalsUnlock()
+		cacheLineWaiter.Wait()
+		goto Restart
+	}
+
+	// Once we make it here, we need to atomically delete the object (if any)
+
+	if !thisInode.isVirt {
 		backend, ok = globals.backendMap[thisInode.backendNonce]
 		if !ok {
 			dumpStack()
@@ -1522,10 +3243,28 @@ Restart:
 			ifMatch:  "",
 		}
 
+		// The delete (which may await a request slot) is issued unlocked... as thisInode
+		// is pendingDelete with no open handles, nothing else will touch it meanwhile
+
+		inodeNumber = thisInode.inodeNumber
+
+		globalsUnlock()
+
 		// It's actually ok if the object is already gone
-		_, err = deleteFileWrapper(backend.context, deleteFileInput)
+		_, err = deleteFileWrapper(ctx, backend.context, deleteFileInput)
 		if err != nil {
 			globals.logger.Printf("[WARN] deleteBackendObjectWhenAndIfNecessary() got deleteFileWrapper(thisInode.backend.context, deleteFileInput) err: %v", err)
+			publishEvent(EventFlushFailed, backend.dirName, fmt.Sprintf("delete of \"%s\" failed: %v", deleteFileInput.filePath, err))
+		}
+
+		globalsLock("fs.go:3260:3:(*inodeStruct).finishPendingDelete")
+
+		thisInode, ok = globals.inodeMap.get(inodeNumber)
+		if !ok {
+			// Should it nevertheless have been removed meanwhile, there is nothing left to do
+
+			globalsUnlock()
+			return
 		}
 	}
 
@@ -1551,12 +3290,16 @@ Restart:
 		}
 	}
 
+	thisInode.retireInodeHandle()
+
 	ok = globals.inodeMap.delete(thisInode.inodeNumber)
 	if !ok {
 		dumpStack()
 		globals.logger.Fatalf("[FATAL] globals.inodeMap.delete(thisInode.inodeNumber) returned !ok")
 	}
 
+	thisInode.pruneInodeHandle()
+
 	parentInode.touch(nil)
 
 	globalsUnlock()
@@ -0,0 +1,38 @@
+//go:build linux
+
+package main
+
+import "syscall"
+
+const fuseSuperMagic = 0x65735546 // FUSE_SUPER_MAGIC from linux/magic.h
+
+// `fuseConnectionID` returns the ID of the FUSE connection serving mountPoint (i.e. the name of
+// its /sys/fs/fuse/connections/ directory). This is the kernel's (rather than stat(2)'s) encoding
+// of the device number of the mount. If mountPoint is not (yet) a FUSE mount, !ok is returned.
+func fuseConnectionID(mountPoint string) (connectionID uint64, ok bool) {
+	var (
+		dev    uint64
+		major  uint64
+		minor  uint64
+		stat   syscall.Stat_t
+		statfs syscall.Statfs_t
+	)
+
+	if (syscall.Statfs(mountPoint, &statfs) != nil) || (statfs.Type != fuseSuperMagic) {
+		ok = false
+		return
+	}
+
+	if syscall.Stat(mountPoint, &stat) != nil {
+		ok = false
+		return
+	}
+
+	dev = uint64(stat.Dev) //nolint:unconvert // Stat_t.Dev is not a uint64 on every architecture
+	major = ((dev >> 8) & 0xfff) | ((dev >> 32) & 0xfffff000)
+	minor = (dev & 0xff) | ((dev >> 12) & 0xffffff00)
+
+	connectionID = (major << 20) | minor
+	ok = true
+	return
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package main
+
+// `fuseConnectionID` is unsupported on non-Linux platforms (which lack /sys/fs/fuse/connections/).
+// MSFS production targets are Linux, so this path is for local dev/test builds (e.g. macOS) only.
+func fuseConnectionID(mountPoint string) (connectionID uint64, ok bool) {
+	return 0, false
+}
@@ -1,15 +1,18 @@
 package main
 
 import (
-	"context"
+	"crypto/sha256"
 	"log"
+	"log/slog"
 	"os"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/NVIDIA/fission/v4"
+	"github.com/google/uuid"
+
+	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/msc"
 )
 
 //go:generate go run ./tools/lockgen -dir .
@@ -88,29 +91,52 @@ type backendConfigRAMStruct struct {
 	maxListPageSize     uint64 //                  JSON/YAML "max_list_page_size"             default:1000
 	maxTotalObjectSpace uint64 //                  JSON/YAML "max_total_object_space"         default:1073741824
 	maxTotalObjects     uint64 //                  JSON/YAML "max_total_objects"              default:10000
+	snapshotFile        string //                  JSON/YAML "snapshot_file"                  default:"" (none)
 }
 
 // `backendConfigS3Struct` describes a backend's S3-specific settings.
 type backendConfigS3Struct struct {
 	// From <config-file>
-	configCredentialsProfile  string        //     JSON/YAML "config_credentials_profile"     default:"${AWS_PROFILE:-default}"
-	useConfigEnv              bool          //     JSON/YAML "use_config_env"                 default:false
-	configFilePath            string        //     JSON/YAML "config_file_path"               default:"${AWS_CONFIG_FILE:-~/.aws/config}"
-	region                    string        //     JSON/YAML "region"                         default:"${AWS_REGION:-us-east-1}"
-	endpoint                  string        //     JSON/YAML "endpoint"                       default:"${AWS_ENDPOINT}"
-	useCredentialsEnv         bool          //     JSON/YAML "use_credentials_env"            default:false
-	credentialsFilePath       string        //     JSON/YAML "credentials_file_path"          default:"${AWS_SHARED_CREDENTIALS_FILE:-~/.aws/credentials}"
-	accessKeyID               string        //     JSON/YAML "access_key_id"                  default:"${AWS_ACCESS_KEY_ID}"
-	secretAccessKey           string        //     JSON/YAML "secret_access_key"              default:"${AWS_SECRET_ACCESS_KEY}"
-	anonymous                 bool          //     JSON/YAML "anonymous"                      default:false
-	skipTLSCertificateVerify  bool          //     JSON/YAML "skip_tls_certificate_verify"    default:false
-	virtualHostedStyleRequest bool          //     JSON/YAML "virtual_hosted_style_request"   default:false
-	unsignedPayload           bool          //     JSON/YAML "unsigned_payload"               default:false
-	retryBaseDelay            time.Duration //     JSON/YAML "retry_base_delay"               default:10
-	retryNextDelayMultiplier  float64       //     JSON/YAML "retry_next_delay_multiplier"    default:2.0
-	retryMaxDelay             time.Duration //     JSON/YAML "retry_max_delay"                default:2000
+	configCredentialsProfile  string            //     JSON/YAML "config_credentials_profile"     default:"${AWS_PROFILE:-default}"
+	useConfigEnv              bool              //     JSON/YAML "use_config_env"                 default:false
+	configFilePath            string            //     JSON/YAML "config_file_path"               default:"${AWS_CONFIG_FILE:-~/.aws/config}"
+	region                    string            //     JSON/YAML "region"                         default:"${AWS_REGION:-us-east-1}"
+	endpoint                  string            //     JSON/YAML "endpoint"                       default:"${AWS_ENDPOINT}"
+	endpoints                 []string          //     JSON/YAML "endpoints"                      default:[] (if non-empty, supersedes endpoint)
+	endpointCheckInterval     time.Duration     //     JSON/YAML "endpoint_check_interval"        default:5000 (in milliseconds; 0 disables active health checks)
+	readEndpointSelection     string            //     JSON/YAML "read_endpoint_selection"        default:"round-robin" (otherwise "fastest")
+	useCredentialsEnv         bool              //     JSON/YAML "use_credentials_env"            default:false
+	credentialsFilePath       string            //     JSON/YAML "credentials_file_path"          default:"${AWS_SHARED_CREDENTIALS_FILE:-~/.aws/credentials}"
+	accessKeyID               string            //     JSON/YAML "access_key_id"                  default:"${AWS_ACCESS_KEY_ID}"
+	secretAccessKey           string            //     JSON/YAML "secret_access_key"              default:"${AWS_SECRET_ACCESS_KEY}"
+	anonymous                 bool              //     JSON/YAML "anonymous"                      default:false
+	skipTLSCertificateVerify  bool              //     JSON/YAML "skip_tls_certificate_verify"    default:false
+	caBundleFile              string            //     JSON/YAML "ca_bundle_file"                 default:"" (system trust store)
+	clientCertFile            string            //     JSON/YAML "client_cert_file"               default:"" (no mutual TLS; requires client_key_file if set)
+	clientKeyFile             string            //     JSON/YAML "client_key_file"                default:"" (requires client_cert_file if set)
+	virtualHostedStyleRequest bool              //     JSON/YAML "virtual_hosted_style_request"   default:false
+	unsignedPayload           bool              //     JSON/YAML "unsigned_payload"               default:false
+	retryBaseDelay            time.Duration     //     JSON/YAML "retry_base_delay"               default:10
+	retryNextDelayMultiplier  float64           //     JSON/YAML "retry_next_delay_multiplier"    default:2.0
+	retryMaxDelay             time.Duration     //     JSON/YAML "retry_max_delay"                default:2000
+	maxIdleConns              uint64            //     JSON/YAML "max_idle_conns"                 default:0 (SDK default of 100)
+	maxIdleConnsPerHost       uint64            //     JSON/YAML "max_idle_conns_per_host"        default:0 (SDK default of 10)
+	maxConnsPerHost           uint64            //     JSON/YAML "max_conns_per_host"             default:0 (unlimited)
+	requesterPays             bool              //     JSON/YAML "requester_pays"                 default:false
+	requestHeaders            map[string]string //     JSON/YAML "request_headers"                default:{} (Key == canonical header name)
+	sseType                   string            //     JSON/YAML "sse_type"                       default:"" (bucket default; otherwise one of "AES256", "aws:kms", or "customer-key")
+	kmsKeyID                  string            //     JSON/YAML "kms_key_id"                     default:"" (bucket/account default KMS key; only applicable if sse_type == "aws:kms")
+	sseCKey                   string            //     JSON/YAML "sse_c_key"                      required if sse_type == "customer-key" (base64-encoded 256-bit key)
+	restoreArchivedObjects    bool              //     JSON/YAML "restore_archived_objects"       default:false
+	restoreDays               uint64            //     JSON/YAML "restore_days"                   default:1 (must be >= 1)
+	restoreTier               string            //     JSON/YAML "restore_tier"                   default:"Standard" (otherwise "Bulk" or "Expedited")
+	discoverBuckets           bool              //     JSON/YAML "discover_buckets"               default:false (if true, bucket_container_name must be omitted)
+	bucketDiscoveryTTL        time.Duration     //     JSON/YAML "bucket_discovery_ttl"           default:60000 (in milliseconds; 0 means only refreshed on SIGHUP)
+	snapshotTime              time.Time         //     JSON/YAML "snapshot_time"                  default:"" (latest versions; otherwise an RFC 3339 timestamp requiring readonly)
+	exposeVersions            bool              //     JSON/YAML "expose_versions"                default:false (if true, "<name>.versions/" presents each version of "<name>")
 	// Runtime state
-	retryDelay []time.Duration //                  Delay slice indexed by RetryDelay()'s attempt arg - 1
+	retryDelay        []time.Duration            // Delay slice indexed by RetryDelay()'s attempt arg - 1
+	discoveredBuckets *s3DiscoveredBucketsStruct // If discoverBuckets == false, == nil
 }
 
 // `flatDirHintStruct` configures parallel listing for a known flat directory.
@@ -124,26 +150,50 @@ type flatDirHintStruct struct {
 // particulars as well is references to backendType-specific details.
 type backendStruct struct {
 	// From <config-file>
-	dirName                     string              //     JSON/YAML "dir_name"                       required
-	readOnly                    bool                //     JSON/YAML "readonly"                       default:true
-	flushOnClose                bool                //     JSON/YAML "flush_on_close"                 default:true
-	uid                         uint64              //     JSON/YAML "uid"                            default:<current euid>
-	gid                         uint64              //     JSON/YAML "gid"                            default:<current egid>
-	dirPerm                     uint64              //     JSON/YAML "dir_perm"                       default:0o555(ro)/0o777(rw)
-	filePerm                    uint64              //     JSON/YAML "file_perm"                      default:0o444(ro)/0o666(rw)
-	directoryPageSize           uint64              //     JSON/YAML "directory_page_size"            default:0(endpoint determined)
-	multiPartCacheLineThreshold uint64              //     JSON/YAML "multipart_cache_line_threshold" default:512
-	uploadPartCacheLines        uint64              //     JSON/YAML "upload_part_cache_lines"        default:32
-	uploadPartConcurrency       uint64              //     JSON/YAML "upload_part_concurrency"        default:32
-	bucketContainerName         string              //     JSON/YAML "bucket_container_name"          required
-	prefix                      string              //     JSON/YAML "prefix"                         default:""
-	traceLevel                  uint64              //     JSON/YAML "trace_level"                    default:0
-	manifestPath                string              //     JSON/YAML "manifest_path"                  default:""
-	manifestGenWorkers          int                 //     JSON/YAML "manifest_gen_workers"           default:200
-	flatDirConfirmationPages    int                 //     JSON/YAML "flat_dir_confirmation_pages"    default:5
-	flatDirHints                []flatDirHintStruct //     JSON/YAML "flat_dir_hints"                 default:nil
-	backendType                 string              //     JSON/YAML "backend_type"                   required(one of "AIStore", "GCS", "PSEUDO", "RAM", "S3")
-	backendTypeSpecifics        interface{}         //                                                as-required(one of *backendConfig{AIStore|GCS|PSEUDO|RAM|S3}Struct)
+	dirName                     string               //     JSON/YAML "dir_name"                       required
+	readOnly                    bool                 //     JSON/YAML "readonly"                       default:true
+	flushOnClose                bool                 //     JSON/YAML "flush_on_close"                 default:true
+	uid                         uint64               //     JSON/YAML "uid"                            default:<current euid>
+	gid                         uint64               //     JSON/YAML "gid"                            default:<current egid>
+	dirPerm                     uint64               //     JSON/YAML "dir_perm"                       default:0o555(ro)/0o777(rw)
+	filePerm                    uint64               //     JSON/YAML "file_perm"                      default:0o444(ro)/0o666(rw)
+	directoryPageSize           uint64               //     JSON/YAML "directory_page_size"            default:0(endpoint determined)
+	multiPartCacheLineThreshold uint64               //     JSON/YAML "multipart_cache_line_threshold" default:512
+	uploadPartCacheLines        uint64               //     JSON/YAML "upload_part_cache_lines"        default:32
+	uploadPartConcurrency       uint64               //     JSON/YAML "upload_part_concurrency"        default:32
+	bucketContainerName         string               //     JSON/YAML "bucket_container_name"          required (unless S3.discover_buckets == true)
+	prefix                      string               //     JSON/YAML "prefix"                         default:""
+	keySaltWidth                uint64               //     JSON/YAML "key_salt_width"                 default:0 (disabled; otherwise 1..8 hex digits)
+	logLevel                    slog.LevelVar        //     JSON/YAML "log_level"                      default:"error" (or as mapped from the deprecated "trace_level"; changeable via SIGHUP)
+	manifestPath                string               //     JSON/YAML "manifest_path"                  default:""
+	symLinkSuffix               string               //     JSON/YAML "symlink_suffix"                 default:"" (symlinks disabled)
+	cacheBypass                 bool                 //     JSON/YAML "cache_bypass"                   default:false
+	emulateFIFOs                bool                 //     JSON/YAML "emulate_fifos"                  default:false
+	entryTTL                    time.Duration        //     JSON/YAML "entry_ttl"                      default:<entry_ttl> (in milliseconds; changeable via SIGHUP)
+	attrTTL                     time.Duration        //     JSON/YAML "attr_ttl"                       default:<attr_ttl> (in milliseconds; changeable via SIGHUP)
+	manifestGenWorkers          int                  //     JSON/YAML "manifest_gen_workers"           default:200
+	flatDirConfirmationPages    int                  //     JSON/YAML "flat_dir_confirmation_pages"    default:5
+	flatDirHints                []flatDirHintStruct  //     JSON/YAML "flat_dir_hints"                 default:nil
+	hedgeReadPercentile         float64              //     JSON/YAML "hedge_read_percentile"          default:0 (disabled)
+	hedgeReadMinDelay           time.Duration        //     JSON/YAML "hedge_read_min_delay"           default:10 (in milliseconds)
+	hedgeReadBudget             uint64               //     JSON/YAML "hedge_read_budget"              default:5 (as a percentage of readFile calls)
+	readTimeout                 time.Duration        //     JSON/YAML "read_timeout"                   default:0 (none; in milliseconds)
+	listTimeout                 time.Duration        //     JSON/YAML "list_timeout"                   default:0 (none; in milliseconds)
+	headTimeout                 time.Duration        //     JSON/YAML "head_timeout"                   default:0 (none; in milliseconds)
+	writeTimeout                time.Duration        //     JSON/YAML "write_timeout"                  default:0 (none; in milliseconds)
+	readDirTimeBudget           time.Duration        //     JSON/YAML "readdir_time_budget"            default:0 (none; in milliseconds)
+	readDirSnapshot             bool                 //     JSON/YAML "readdir_snapshot"               default:false
+	readDirSnapshotMaxEntries   uint64               //     JSON/YAML "readdir_snapshot_max_entries"   default:100000 (0 means unbounded)
+	maxConcurrentRequests       uint64               //     JSON/YAML "max_concurrent_requests"        default:0 (unlimited)
+	readBandwidthLimit          uint64               //     JSON/YAML "read_bandwidth_limit"           default:0 (unlimited; in bytes per second)
+	requestRateLimit            uint64               //     JSON/YAML "request_rate_limit"             default:0 (unlimited; in requests per second)
+	chaos                       *backendChaosStruct  //     JSON/YAML "chaos"                          default:nil (no fault injection)
+	acl                         *backendACLStruct    //     JSON/YAML "{allow|deny}_{uids|gids}"       default:nil (unrestricted; changeable via SIGHUP)
+	filter                      *backendFilterStruct //    JSON/YAML "{include|exclude}"              default:nil (everything exposed)
+	proxyURL                    string               //     JSON/YAML "proxy_url"                      default:"" (${HTTPS_PROXY}/${HTTP_PROXY} if applicable)
+	noProxy                     string               //     JSON/YAML "no_proxy"                       default:"" (appended to ${NO_PROXY} if applicable)
+	backendType                 string               //     JSON/YAML "backend_type"                   required(one of "AIStore", "GCS", "PSEUDO", "RAM", "S3")
+	backendTypeSpecifics        interface{}          //                                                as-required(one of *backendConfig{AIStore|GCS|PSEUDO|RAM|S3}Struct)
 	// Runtime state
 	nonce          uint64                //        Key in globalsStruct.backendMap
 	backendPath    string                //        URL incorporating each of the above path-related values
@@ -151,7 +201,15 @@ type backendStruct struct {
 	inode          *inodeStruct          //        Link to this backendStruct's inodeStruct with .inodeType == BackendRootDir
 	fissionMetrics *fissionMetricsStruct //
 	backendMetrics *backendMetricsStruct //
+	hedge          *hedgeStruct          //        If hedgeReadPercentile == 0, == nil
+	requestSlots   chan struct{}         //        If maxConcurrentRequests == 0, == nil; otherwise holds one element per outstanding backendContextIf call
+	readBucket     *tokenBucketStruct    //        If readBandwidthLimit == 0, == nil; otherwise charged the length of each readFile()
+	requestBucket  *tokenBucketStruct    //        If requestRateLimit == 0, == nil; otherwise charged for each readFile(), listDirectory(), stat{File|Directory}(), restoreFile(), presignFile(), and copyFile()
 	mounted        bool                  //        If false, backendStruct.dirName not in fuseRootDirInodeMAP
+	mkDirOf        string                //        If != "", dir_name of the default_backend of which mkdir(2) in the FUSE root created this backend as a prefix
+	setupErr       error                 //        If != nil, the error returned by the most recent (failed) setupContext() while on globals.backendsFailed
+	mountPoint     string                //        If != "", mountpoint of the mounts element presenting this backend (otherwise, that of the config file)
+	unsaltedSeen   atomic.Bool           //        If true (only possible if keySaltWidth != 0), a listing has returned an unsalted basename so statFile()/readFile() misses retry the unsalted key
 }
 
 // `configStruct` describes the global configuration settings as well as the array of backendStruct's configured.
@@ -166,18 +224,30 @@ type configStruct struct {
 	gid                                       uint64                     // JSON/YAML "gid"                                               default:<current egid>
 	dirPerm                                   uint64                     // JSON/YAML "dir_perm"                                          default:0o555
 	allowOther                                bool                       // JSON/YAML "allow_other"                                       default:true
+	readOnly                                  bool                       // JSON/YAML "read_only"                                         default:false (if true, every backend is treated as readonly)
+	hideInaccessibleBackends                  bool                       // JSON/YAML "hide_inaccessible_backends"                        default:false (changeable via SIGHUP)
+	defaultBackend                            string                     // JSON/YAML "default_backend"                                   default:"" (none; changeable via SIGHUP)
 	maxWrite                                  uint64                     // JSON/YAML "max_write"                                         default:131072 (128Ki)
 	entryAttrTTL                              time.Duration              // JSON/YAML "entry_attr_ttl"                                    default:10000 (in milliseconds)
+	entryTTL                                  time.Duration              // JSON/YAML "entry_ttl"                                         default:<entry_attr_ttl> (in milliseconds)
+	attrTTL                                   time.Duration              // JSON/YAML "attr_ttl"                                          default:<entry_attr_ttl> (in milliseconds)
 	evictableInodeTTL                         time.Duration              // JSON/YAML "evictable_inode_ttl"                               default:1000000 (in milliseconds)
 	virtualDirTTL                             time.Duration              // JSON/YAML "virtual_dir_ttl"                                   default:1000000 (in milliseconds)
 	virtualFileTTL                            time.Duration              // JSON/YAML "virtual_file_ttl"                                  default:1000000 (in milliseconds)
 	ttlCheckInterval                          time.Duration              // JSON/YAML "ttl_check_interval"                                default:250 (in milliseconds)
+	fuseOpTimeout                             time.Duration              // JSON/YAML "fuse_op_timeout"                                   default:0 (none; in milliseconds)
+	unmountDrainTimeout                       time.Duration              // JSON/YAML "unmount_drain_timeout"                             default:10000 (in milliseconds)
 	cacheStorage                              string                     // JSON/YAML "cache_storage" ("ram"|"mapped-file"|"per-inode-file") default:"mapped-file" (mapped_cache/cache_backend are deprecated aliases)
 	cacheLineSize                             uint64                     // JSON/YAML "cache_line_size"                                   default:10485760 (10Mi)
 	cacheLines                                uint64                     // JSON/YAML "cache_lines"                                       default:128
 	cacheLinesToPrefetch                      uint64                     // JSON/YAML "cache_lines_to_prefetch"                           default:4
+	cacheDedup                                bool                       // JSON/YAML "cache_dedup"                                       default:false
 	dirtyCacheLinesFlushTrigger               uint64                     // JSON/YAML "dirty_cache_lines_flush_trigger"                   default:80 (as a percentage)
 	dirtyCacheLinesMax                        uint64                     // JSON/YAML "dirty_cache_lines_max"                             default:90 (as a percentage)
+	cachePartitionBy                          string                     // JSON/YAML "cache_partition_by" (""|"uid"|"cgroup")            default:"" (no partitioning)
+	cachePartitionDefaultLimit                uint64                     // JSON/YAML "cache_partition_default_limit"                     default:100 (as a percentage)
+	cachePartitions                           []cachePartitionStruct     // JSON/YAML "cache_partitions"                                  default:[] (none)
+	mounts                                    []mountConfigStruct        // JSON/YAML "mounts"                                            default:[] (none)
 	cacheDirPath                              string                     // JSON/YAML "cache_dir_path"                                    default:""
 	metadataCachePagingMode                   string                     // JSON/YAML "metadata_cache_paging_mode"                        default:"pebble"
 	pebbleCacheSize                           uint64                     // JSON/YAML "pebble_cache_size"                                 default:33554432 (32Mi)
@@ -205,19 +275,68 @@ type configStruct struct {
 	virtChildDirEntryMapPageDirtyFlushTrigger uint64                     // JSON/YAML "virt_child_dir_entry_map_page_dirty_flush_trigger" default:50
 	virtChildDirEntryMapFlushedPerGC          uint64                     // JSON/YAML "virt_child_dir_entry_map_flushes_per_gc"           default:10
 	processMemoryLimit                        uint64                     // JSON/YAML "process_memory_limit"                              default:4294967296 (4Gi)
+	memoryPressureInterval                    time.Duration              // JSON/YAML "memory_pressure_interval"                          default:0 (in milliseconds; 0 disables the memory pressure watcher)
+	memoryPressureLimit                       uint64                     // JSON/YAML "memory_pressure_limit"                             default:0 (the cgroup memory limit; otherwise a resident set size limit)
+	memoryPressureThreshold                   uint64                     // JSON/YAML "memory_pressure_threshold"                         default:90 (percent of the limit)
 	autoSIGHUPInterval                        time.Duration              // JSON/YAML "auto_sighup_interval"                              default:0 (none)
+	backendReprobeInterval                    time.Duration              // JSON/YAML "backend_reprobe_interval"                          default:30 (in seconds; 0 disables re-probing)
+	multipartUploadJanitorInterval            time.Duration              // JSON/YAML "multipart_upload_janitor_interval"                 default:0 (in seconds; 0 disables the multipart upload janitor)
+	multipartUploadMaxAge                     time.Duration              // JSON/YAML "multipart_upload_max_age"                          default:86400 (in seconds; older multipart uploads are deemed abandoned)
+	presignedURLTTL                           time.Duration              // JSON/YAML "presigned_url_ttl"                                 default:3600 (in seconds; lifetime of each XAttrNamePresignedURL value)
+	treeSizeCacheTTL                          time.Duration              // JSON/YAML "tree_size_cache_ttl"                               default:60 (in seconds; 0 disables caching of XAttrNameTreeSize values)
+	prefetchConcurrency                       uint64                     // JSON/YAML "prefetch_concurrency"                              default:16 (data cache lines concurrently fetched by each XAttrNamePrefetch warm-up)
 	observability                             *observabilityConfigStruct // JSON/YAML "observability"                                     default:nil (disabled)
 	endpoint                                  string                     // JSON/YAML "endpoint"                                          default:""
+	adminListen                               string                     // JSON/YAML "admin_listen"                                      default:"" (disabled; otherwise "<host>:<port>")
+	errorHints                                []errorHintStruct          // JSON/YAML "error_hints"                                       default:[] (none)
+	eventSink                                 string                     // JSON/YAML "event_sink"                                        default:"" (none)
+	readyFile                                 string                     // JSON/YAML "ready_file"                                        default:"" (none)
+	readyProbeInterval                        time.Duration              // JSON/YAML "ready_probe_interval"                              default:1000 (in milliseconds)
+	logFormat                                 string                     // JSON/YAML "log_format" ("plain"|"text"|"json")                default:"plain"
+	logLevel                                  slog.Level                 // JSON/YAML "log_level"                                         default:"trace"
+	logFile                                   string                     // JSON/YAML "log_file"                                          default:"" (os.Stdout)
+	logFileMaxSize                            uint64                     // JSON/YAML "log_file_max_size"                                 default:0 (no rotation)
+	logFileMaxBackups                         uint64                     // JSON/YAML "log_file_max_backups"                              default:5
 	backends                                  map[string]*backendStruct  // JSON/YAML "backends"                                          Key == backendStruct.mountPointSubdirectoryName
 }
 
+// `errorHintStruct` maps backend errors (matched by HTTP status and/or error code substring)
+// to a user-facing hint appended to the error (and, hence, to any log message reporting it).
+type errorHintStruct struct {
+	httpStatus uint64 // JSON/YAML "http_status" default:0 (any)
+	errorCode  string // JSON/YAML "error_code"  default:"" (any)
+	hint       string // JSON/YAML "hint"        (required)
+}
+
+// `cachePartitionStruct` caps the number of data cache lines that may be held on behalf
+// of requesters whose partition key (a decimal uid or a cgroup path, per cache_partition_by)
+// matches .key. Lines beyond the cap are reclaimed from the partition's own Clean lines.
+type cachePartitionStruct struct {
+	key   string // JSON/YAML "key"   (required)
+	limit uint64 // JSON/YAML "limit" (required; as a percentage of cache_lines, converted to a count of data cache lines)
+}
+
+// `mountConfigStruct` describes an additional FUSE mount at .mountPoint presenting (instead
+// of the mountpoint of the config file) each of the backends whose dir_name is in .backends.
+type mountConfigStruct struct {
+	mountPoint string   // JSON/YAML "mountpoint" (required)
+	backends   []string // JSON/YAML "backends"   (required; dir_name of each backend presented)
+}
+
 // observabilityConfigStruct holds observability configuration
 // Matches MSC Python schema exactly: opentelemetry.metrics.{attributes, reader, exporter}
+// Tracing (an MSFS extension) is configured via opentelemetry.traces.{exporter, sample_ratio}
 type observabilityConfigStruct struct {
 	// Metrics configuration (matches Python schema)
-	metricsAttributes    []attributeProviderStruct // JSON/YAML "metrics.attributes"
-	metricsReaderOptions *readerOptionsStruct      // JSON/YAML "metrics.reader.options"
-	metricsExporter      *exporterStruct           // JSON/YAML "metrics.exporter"
+	metricsAttributes        []attributeProviderStruct // JSON/YAML "metrics.attributes"
+	metricsReaderOptions     *readerOptionsStruct      // JSON/YAML "metrics.reader.options"
+	metricsExporter          *exporterStruct           // JSON/YAML "metrics.exporter"
+	metricsBackendAttributes []string                  // JSON/YAML "metrics.backend_attributes" default:[] (only "bucket"; an MSFS extension)
+	metricsViews             []metricsViewStruct       // JSON/YAML "metrics.views"              default:[] (an MSFS extension)
+
+	// Traces configuration
+	tracesExporter    *exporterStruct // JSON/YAML "traces.exporter"
+	tracesSampleRatio float64         // JSON/YAML "traces.sample_ratio" default:1.0
 }
 
 // attributeProviderStruct matches Python's EXTENSION_SCHEMA for attributes
@@ -232,6 +351,15 @@ type readerOptionsStruct struct {
 	CollectTimeoutMillis  uint64 // JSON/YAML "collect_timeout_millis"  default:10000 (10 seconds)
 	ExportIntervalMillis  uint64 // JSON/YAML "export_interval_millis"  default:60000 (60 seconds)
 	ExportTimeoutMillis   uint64 // JSON/YAML "export_timeout_millis"   default:30000 (30 seconds)
+	Temporality           string // JSON/YAML "temporality"             default:"cumulative" (or "delta"; an MSFS extension)
+	MaxBufferedSnapshots  uint64 // JSON/YAML "max_buffered_snapshots"  default:600 (oldest dropped first; an MSFS extension)
+}
+
+// metricsViewStruct overrides the aggregation of the metric instrument(s) matching Instrument (an MSFS extension)
+type metricsViewStruct struct {
+	Instrument  string    // JSON/YAML "instrument"  e.g. "multistorageclient.latency" (may include "*" and "?" wildcards)
+	Aggregation string    // JSON/YAML "aggregation" e.g. "explicit_bucket_histogram" (see metricsViewAggregations)
+	Boundaries  []float64 // JSON/YAML "boundaries"  default:[] (SDK default boundaries; only for "explicit_bucket_histogram")
 }
 
 // exporterStruct matches Python's EXTENSION_SCHEMA for exporter
@@ -240,6 +368,11 @@ type exporterStruct struct {
 	Options map[string]interface{} // JSON/YAML "options" e.g. {"endpoint": "http://localhost:4318/v1/metrics"}
 }
 
+// Elements of observabilityConfigStruct.metricsBackendAttributes
+const (
+	metricsBackendAttributeBucket = "bucket" // Adds "msfs.bucket" (the backend's bucket_container_name) unless it is ""
+)
+
 const (
 	FUSERootDirInodeNumber uint64 = 1
 )
@@ -247,6 +380,8 @@ const (
 const (
 	DefaultMountPoint = "/mnt"
 	EnvMSFSMountPoint = "MSFS_MOUNTPOINT"
+
+	EnvMSFSConfigOverrides = "MSFS_CONFIG_OVERRIDES" // Comma-separated "<key>=<value>" config file overrides (see applyConfigOverrides())
 )
 
 const (
@@ -254,6 +389,29 @@ const (
 	DotDotDirEntryBasename = ".."
 )
 
+const (
+	XAttrNamePrefix         = "user.msc."                        // All extended attributes exposed by DoGetXAttr() and DoListXAttr() start with this
+	XAttrNameETag           = XAttrNamePrefix + "etag"           // Value is the object's eTag
+	XAttrNameStorageClass   = XAttrNamePrefix + "storage_class"  // Value is the object's storage class (if reported by the backend)
+	XAttrNameRestoreStatus  = XAttrNamePrefix + "restore_status" // Value is one of restoreStatus{Archived|Ongoing|Restored} (if the object's content is archived)
+	XAttrNameMetadataPrefix = XAttrNamePrefix + "meta."          // Followed by each user-defined object metadata key
+	XAttrNameCopyTo         = XAttrNamePrefix + "copy_to"        // Only settable; value is the "<dir_name>/<path>" (or absolute path) to which the object is copied server-side
+	XAttrNamePresignedURL   = XAttrNamePrefix + "presigned_url"  // Only gettable (and not listed); value is a freshly pre-signed GET URL of the object (if supported by the backend)
+	XAttrNameTreeSize       = XAttrNamePrefix + "tree_size"      // Only gettable (and not listed) on directories; value is "<bytes> <objects>" totalled over every file beneath the directory
+	XAttrNamePrefetch       = XAttrNamePrefix + "prefetch"       // Setting (to "" or, for a directory, a newline-separated list of relative paths) warms the data cache; getting (unlisted) reports progress as JSON
+)
+
+const (
+	RenameNoReplace = uint32(1) // Linux RENAME_NOREPLACE flag supplied to DoRename2()
+)
+
+const (
+	FAllocateKeepSize = uint32(0x01) // Linux FALLOC_FL_KEEP_SIZE flag supplied to DoFAllocate()
+
+	LSeekData = uint32(3) // Linux SEEK_DATA whence supplied to DoLSeek()
+	LSeekHole = uint32(4) // Linux SEEK_HOLE whence supplied to DoLSeek()
+)
+
 const (
 	FileObject     uint32 = iota // Transient inode populated by DoLookup(), DoReadDir(), and DoReadDirPlus() mapping to an object in a backend
 	FUSERootDir                  // The "root" of the FUSE file system (i.e. inodeNumber == 1)
@@ -283,13 +441,24 @@ type DirEntryInfo struct {
 type fhStruct struct {
 	nonce uint64 // Key in inodeStruct.fhSet & globalsStruct.fhMap
 	inode *inodeStruct
+	// The following identify the opener (as captured from the fission.InHeader of the DoOpen() or DoOpenDir())
+	openerUID uint32
+	openerGID uint32
+	openerPID uint32
+	openTime  time.Time
 	// The following only applicable if inode.inodeType == FileObject
 	isExclusive  bool
 	allowReads   bool
 	allowWrites  bool
 	appendWrites bool // Only applicable if allowWrites == true
+	// The following only applicable if inode.inodeType == FileObject
+	cacheBypass bool // If true (opened with O_DIRECT or backend.cacheBypass), DoRead() issues ranged reads sized to the request without populating the data cache
+	// The following only applicable if inode.inodeType == FileObject and cache_partition_by != ""
+	cachePartition string // Partition (derived from the opener) charged for data cache lines allocated by DoRead()
 	// The following only applicable if inode.inodeType == BackendRootDir or PseudoDir after enumerating each dir_entry by walking .inode.childDirMap then .inode.childFileMap
 	listDirectoryInProgress               bool
+	listDirectoryInProgressDone           chan struct{}                   // If listDirectoryInProgress, closed once it is cleared (see awaitListDirectory())
+	listDirectoryPending                  chan *listDirectoryResultStruct // If != nil, a background listDirectoryWrapper() call (see fetchListDirectory()) whose result is yet to be consumed
 	listDirectorySequenceDone             bool
 	prevListDirectoryOutput               *listDirectoryOutputStruct
 	prevListDirectoryOutputFileLen        uint64
@@ -299,6 +468,7 @@ type fhStruct struct {
 	nextListDirectoryOutputStartingOffset uint64
 	listDirectorySubdirectorySet          map[string]struct{}
 	listDirectorySubdirectoryList         []string
+	listDirectoryAnchorList               []listDirectoryAnchorStruct // One per page listed by key (see nextListDirectoryInput()) ordered by .startingOffset
 	serveFromBPTree                       bool
 	serveFromManifest                     bool
 	manifestEntries                       []manifestDirEntry
@@ -324,10 +494,17 @@ const (
 
 // `dataCacheLineLRUStruct` is used as the header for an LRU of `dataCacheLineTrackerStruct`'s referenced by their .pos in globals.dataCacheLinesTracker
 type dataCacheLineLRUStruct struct {
+	head     uint64        // Head (least recently used) dataCacheLineTrackerStruct's .pos
+	tail     uint64        // Tail (most  recently used) dataCacheLineTrackerStruct's .pos
+	lruCount atomic.Uint64 // Count of elements on .lruHead (only changed while holding globals.Lock() but may be read without it)
+	state    uint8         // One of CacheLine*; must match every dataCacheLineTrackerStruct's .state on .lruHead's list
+}
+
+// `dataCacheLinePartitionLRUStruct` is used as the header for an LRU of the `dataCacheLineTrackerStruct`'s on globals.dataCacheLineCleanLRU charged to one partition (linked via their .partition{Next|Prev})
+type dataCacheLinePartitionLRUStruct struct {
 	head     uint64 // Head (least recently used) dataCacheLineTrackerStruct's .pos
 	tail     uint64 // Tail (most  recently used) dataCacheLineTrackerStruct's .pos
-	lruCount uint64 // Count of elements on .lruHead
-	state    uint8  // One of CacheLine*; must match every dataCacheLineTrackerStruct's .state on .lruHead's list
+	lruCount uint64 // Count of elements on .head's list
 }
 
 // `dataCacheLineTrackerStruct` contains the state of each data cache line in globals.dataCacheLinesContent.
@@ -338,22 +515,56 @@ type dataCacheLineTrackerStruct struct {
 	state             uint8             // One of CacheLine*; determines membership in one of globals.dataCacheLine{Free|Inbound|Clean|Output|Dirty}LRU
 	waiters           []*sync.WaitGroup // List of those awaiting a state change
 	contentStart      uint64            // Starting offset in globals.dataCacheLinesContent
+	contentSlot       uint64            // Starting offset in globals.dataCacheLinesContent of the slot owned by this line (== .contentStart unless sharing the content of another line; see dedup())
 	contentLength     uint64            // If <pos> is the position of this struct in globals.dataCacheLinesTracker, valid content is [:.contentLen] of globals.datdataCacheLinesContent[<pos>*globals.config.cacheLineSize:(<pos>+1)*globals.config.cacheLineSize]
 	contentGeneration atomic.Uint64     // Incremented each modification so as to enable unlocked reading of content (atomic: re-read locklessly in DoRead's optimistic re-check)
+	pins              atomic.Int32      // Count of DoRead()s copying content without holding the globals lock (see pin()); a pinned line is never recycled by allocateDataCacheLines()
 	inodeNumber       uint64            // Reference to an inodeStruct.inodeNumber
 	lineNumber        uint64            // Identifies file/object range covered by content as up to [lineNumber * globals.config.cacheLineSize:(lineNumber + 1) * global.config.cacheLineSize)
 	eTag              string            // If state == CacheLineClean, value of inodeStruct.eTag when when fetched from backend; Otherwise, == ""
 	fetchFailed       bool              // Set when the backend read populating this line failed; DoRead surfaces this as EIO and evicts the line instead of serving empty/short content
+	fetchArchived     bool              // If fetchFailed, set when the failure was due to the object's content being archived (see errObjectArchived); DoRead surfaces this as EREMOTEIO or EAGAIN instead
 	diskFile          *os.File          // [cache_storage == "per-inode-file"] per-inode backing file this line was written to (== globals.inodeDiskCacheFiles[inodeNumber].file); nil in memory mode
 	diskOffset        int64             // [cache_storage == "per-inode-file"] byte offset of this line within diskFile (== lineNumber * cacheLineSize)
 	diskLength        int64             // [cache_storage == "per-inode-file"] number of valid bytes written at diskOffset (== contentLength)
+	partition         string            // [cache_partition_by != ""] partition key charged for this line while allocated; "" when on the Free LRU
+	partitionNext     uint64            // [cache_partition_by != ""] if .state == CacheLineClean, next     (less recently used) .pos on globals.dataCachePartitionLRUs[.partition]
+	partitionPrev     uint64            // [cache_partition_by != ""] if .state == CacheLineClean, previous (more recently used) .pos on globals.dataCachePartitionLRUs[.partition]
+	contentHash       [sha256.Size]byte // [cache_dedup] if .dedupIndexed, SHA-256 of the line's content (the key of globals.dataCacheDedupMap)
+	dedupIndexed      bool              // [cache_dedup] set while this Clean line either owns or shares content recorded on globals.dataCacheDedupMap
+	dedupOwner        uint64            // [cache_dedup] if .dedupIndexed, .pos of the line owning the slot holding the content (== .pos if this line is the owner)
+	dedupSharers      []uint64          // [cache_dedup] if .dedupIndexed and the owner, .pos of each other line sharing this line's content
+}
+
+// `inodeTimesStruct` contains the timestamps of an inode reported alongside its .mTime.
+type inodeTimesStruct struct {
+	aTime time.Time // Time when the inode's content was last read (initially == .mTime)
+	bTime time.Time // Time when the inode (or the object underlying it) was first seen
+	cTime time.Time // Time when the inode's metadata (including .mTime, size, or path) last changed (initially == .mTime)
+}
+
+// `inodeHandleStruct` records what an inode number identified such that, should the inode be
+// evicted, it may be re-materialized when the kernel looks it up by number (e.g. to resolve
+// an NFS file handle of a re-exported mount). Note that this data structure is serialized
+// and deserialized in bptree.go so changes here must be paired with changes there.
+type inodeHandleStruct struct {
+	dirName    string // backendStruct.dirName of the inode's backend
+	objectPath string // inodeStruct.objectPath when the inode was created
+	generation uint64 // inodeStruct.generation to be assigned should the inode be re-materialized
+}
+
+// `xattrCacheEntryStruct` holds a cached fetchObjectXAttrs() result.
+type xattrCacheEntryStruct struct {
+	xattrMap   map[string][]byte
+	expiration time.Time // Entry is ignored (and eventually pruned) once this time has passed
 }
 
 // `inodeStruct` contains the state of an inode.
 //
 // Note that this data structure is serialized and deserialized in bptree.go so changes here must be paired with changes there.
 type inodeStruct struct {
-	inodeNumber            uint64              // Note that, other than the FUSERootDir, any reference to a backend object path migtht change this value
+	inodeNumber            uint64              // Other than for the FUSERootDir, derived from the backend's dirName and .objectPath at creation (see stableInodeNumber())
+	generation             uint64              // Reported as fission.EntryOut.Generation; distinguishes successive objects assigned the same .inodeNumber (see globals.inodeHandleMap)
 	inodeType              uint32              // One of FileObject, FUSERootDir, BackendRootDir, or PseudoDir
 	backendNonce           uint64              // If inodeType == FUSERootDir, == 0
 	parentInodeNumber      uint64              // If inodeType == FUSERootDir, == .inodeNumber == FUSERootDirInodeNumber [Note: This is only a reference to a directory that may no longer be in globalsStruct.inodeMap]
@@ -364,8 +575,10 @@ type inodeStruct struct {
 	sizeInMemory           uint64              // If inodeType == FileObject, contains the size currently maintained in-memory only until the file is written to the backend; otherwise == 0
 	eTag                   string              // If inodeType == FileObject, contains the eTag returned by the most recent call to readFileWrapper() for the object; otherwise == ""
 	mode                   uint32              // If inodeType == FileObject, == (syscall.S_IFREG | file_perm); otherwise, == (syscall.S_IFDIR | dir_perm)
-	mTime                  time.Time           // Time when this inodeStruct was last modified - note this is reported for aTime, bTime, and cTime as well
+	mTime                  time.Time           // Time when this inodeStruct was last modified
+	times                  inodeTimesStruct    // Times other than .mTime reported by DoGetAttr(), DoStatX(), etc.
 	xTime                  time.Time           // If != time.Time{}, marks the time when, if not recently accessed, the inode may be evicted
+	vTime                  time.Time           // [inodeType == FileObject] marks the time after which the backend object must be re-stat'd (revalidating .eTag) before cached content is served
 	isPrefetchInProgress   bool                // [inodeType == BackendRootDir || PseudoDir] indicates that a background prefetch of the directory is in progress
 	cacheMap               map[uint64]uint64   // [inodeType == FileObject] Key == file offset / globals.config.cacheLineSize; Value = dataCacheLineTrackerStruct.pos
 	inboundCacheLineCount  uint64              // [inodeType == FileObject] count of .cache[] elements in state CacheLineInbound
@@ -375,30 +588,44 @@ type inodeStruct struct {
 	pendingDelete          bool                // [inodeType == FileObject] marked for deletion (prevents being reported in DoReadDir{|Plus}() output but also reuse until last file close enables removal)
 }
 
-// `globalsStruct` is the sync.Mutex protected global data structure under which all details about daemon state are tracked.
+// `globalsStruct` is the sync.RWMutex protected global data structure under which all details about daemon state are tracked.
 type globalsStruct struct {
-	sync.Mutex                                                                       //
-	logger                   *log.Logger                                             //
+	sync.RWMutex                                                                     //
+	logger                   *log.Logger                                             // Writes through globals.logging.handler (see logBridgeStruct)
+	logging                  loggingStruct                                           //
+	daemon                   daemonStruct                                            // Set from the --daemon, --pidfile, and --log-destination command line options
 	metrics                  interface{}                                             // observability.MSFSMetrics (nil if observability disabled)
 	meterProvider            interface{}                                             // *sdkmetric.MeterProvider (nil if observability disabled)
+	tracerProvider           interface{}                                             // *sdktrace.TracerProvider (nil if tracing disabled)
+	attributeProviders       interface{}                                             // []attributes.AttributesProvider (re-collected by refreshMetricsAttributes() once mounted)
+	mountInstanceID          string                                                  // Random UUID distinguishing this daemon from others on the same host (see "mount_instance" attribute provider)
+	mountInstanceStartTime   time.Time                                               //
 	configFilePath           string                                                  //
 	config                   *configStruct                                           //
 	configFileMap            map[string]interface{}                                  // Parsed config map for msc_config attribute provider
 	backendsToUnmount        map[string]*backendStruct                               //
 	backendsToMount          map[string]*backendStruct                               //
 	backendsSkipped          map[string]struct{}                                     //
+	backendsFailed           map[string]*backendStruct                               // Backends whose setupContext() failed (see reprobeFailedBackends()); Key == backendStruct.dirName
 	backendMap               map[uint64]*backendStruct                               // Key == backend.nonce
 	errChan                  chan error                                              //
 	fissionVolume            fission.Volume                                          //
-	lastNonce                uint64                                                  // Used to safely allocate non-repeating values (initialized to FUSERootDirInodeNumber to ensure skipping it); accessed via atomic.AddUint64 in fetchNonce
+	mounts                   map[string]*mountStruct                                 // Key == mountStruct.mountPoint (set at startup from config.mounts)
+	memoryPressure           atomic.Bool                                             // If true, memory usage is near its limit so readahead is paused (see checkMemoryPressure())
+	ready                    atomic.Bool                                             // If true, the file system has been mounted and each mounted backend has passed its initial probe (see markReady())
+	mountReadOnly            bool                                                    // If true, config.readOnly is set or every mounted backend is readonly (see updateMountReadOnly())
+	backendACLsInUse         atomic.Bool                                             // If true, some mounted backend has a non-nil acl (atomic: checked without the globals lock by backendACLErrno())
+	lastNonce                atomic.Uint64                                           // Used to safely allocate non-repeating values (initialized to FUSERootDirInodeNumber to ensure skipping it); advanced by fetchNonce without holding globals.Lock()
 	cacheDir                 string                                                  //
+	mkNodUnsupportedLogTime  time.Time                                               // When DoMkNod() last logged rejecting an unsupported special file (rate limited by mkNodUnsupportedLogInterval)
 	inodeMap                 *shardedInodeMap                                        // Sharded by inodeNumber: Key: inodeStruct.inodeNumber; Value: *inodeStruct
 	inodeEvictionQueue       *xTimeInodeNumberSetStruct                              // Key: tuple(inodeStruct.xTime,inodeStruct.inodeNumber);                     Value: struct{}
+	inodeHandleMap           *inodeNumberToInodeHandleMapStruct                      // Key: inodeStruct.inodeNumber; Value: inodeHandleStruct (retained after eviction until pruned by pruneInodeHandle())
+	inodeHandleEvicted       []uint64                                                // Ring (of capacity inodeHandleRetention) of the inode numbers of evicted inodes whose globals.inodeHandleMap entries are retained
+	inodeHandleEvictedNext   int                                                     // Once .inodeHandleEvicted is full, the index of its oldest element
 	physChildDirEntryMap     *shardedDirEntryMap                                     // Sharded B+Tree: Key: tuple(parent's inodeStruct.inodeNumber,child's inodeStruct.basename); Value: DirEntryInfo
 	virtChildDirEntryMap     *parentInodeNumberChildBasenameToChildInodeNumberStruct // Key: tuple(parent's inodeStruct.inodeNumber,child's inodeStruct.basename); Value: child's inodeStruct.inodeNumber
-	inodeEvictorContext      context.Context                                         //
-	inodeEvictorCancelFunc   context.CancelFunc                                      //
-	inodeEvictorWaitGroup    sync.WaitGroup                                          //
+	inodeEvictorWorker       *workerStruct                                           //
 	dataCacheLinesFile       *os.File                                                // When config.cacheStorage == "mapped-file": backing file for .dataCacheLinesContent mmap; otherwise nil
 	dataCacheLinesContent    []byte                                                  // Holds the content of each data cache line who's state is at the equivalent position in .dataCacheLinesTracker
 	dataCacheLinesTracker    []dataCacheLineTrackerStruct                            // Holds the state of each data cache line who's content is at the equivalent position in .dataCacheLinesContent
@@ -407,11 +634,26 @@ type globalsStruct struct {
 	dataCacheLineCleanLRU    dataCacheLineLRUStruct                                  // LRU-ordered doubly linked list of dataCacheLineTrackerStruct where .state == CacheLineClean
 	dataCacheLineOutboundLRU dataCacheLineLRUStruct                                  // LRU-ordered doubly linked list of dataCacheLineTrackerStruct where .state == CacheLineOutbound
 	dataCacheLineDirtyLRU    dataCacheLineLRUStruct                                  // LRU-ordered doubly linked list of dataCacheLineTrackerStruct where .state == CacheLineDirty
+	dataCachePartitionLines  map[string]uint64                                       // [cache_partition_by != ""] Key == dataCacheLineTrackerStruct.partition; Value == count of allocated data cache lines so charged
+	dataCachePartitionLRUs   map[string]*dataCacheLinePartitionLRUStruct             // [cache_partition_by != ""] Key == dataCacheLineTrackerStruct.partition; Value == LRU of those on globals.dataCacheLineCleanLRU so charged
+	dataCacheDedupMap        map[[sha256.Size]byte]uint64                            // [cache_dedup] Key == dataCacheLineTrackerStruct.contentHash; Value == .pos of the Clean line owning that content
+	dataCacheDedupLines      uint64                                                  // [cache_dedup] Count of Clean data cache lines sharing the content of another (rather than holding their own)
 	dataCacheActivityWG      sync.WaitGroup                                          //
+	cacheLineFetches         map[cacheLineFetchKeyStruct]*cacheLineFetchStruct       // Backend GETs in flight on behalf of data cache line fetch()'s; concurrent misses of the same key share one
 	inodeDiskCacheFiles      map[uint64]*inodeDiskCacheFileStruct                    // [cache_storage == "per-inode-file"] Key == inodeStruct.inodeNumber; per-inode contiguous backing file + resident-line refcount
 	fhMap                    map[uint64]*fhStruct                                    // Key == fhStruct.nonce
+	treeSizeCache            map[treeSizeCacheKeyStruct]*treeSizeCacheEntryStruct    // [tree_size_cache_ttl != 0] Unexpired XAttrNameTreeSize values
+	treeSizeWalks            map[treeSizeCacheKeyStruct]*treeSizeWalkStruct          // walkPrefix()'s in flight on behalf of treeSizeXAttr(); concurrent calls for the same key share one
+	xattrCache               map[uint64]*xattrCacheEntryStruct                       // [attr_ttl != 0] Key == inodeStruct.inodeNumber; unexpired fetchObjectXAttrs() results
+	renamesInFlight          map[uint64]struct{}                                     // Key == inodeStruct.inodeNumber; FileObject inodes whose renameFileObject() backend operations are underway
+	prefetches               map[uint64]*prefetchStruct                              // Key == prefetchStruct.inodeNumber; the most recent prefetch triggered via each inode's XAttrNamePrefetch
 	fissionMetrics           *fissionMetricsStruct                                   //
 	backendMetrics           *backendMetricsStruct                                   //
+	events                   eventsStruct                                            // Protected by its own lock (not globals.Lock())
+	inFlightOps              inFlightOpsStruct                                       // Protected by its own (sharded) locks (not globals.Lock())
+	healthProbes             adminHealthProbesStruct                                 // Protected by its own lock (not globals.Lock())
+	workers                  workersStruct                                           // Protected by its own lock (not globals.Lock())
+	reload                   reloadStruct                                            // Protected by its own lock (not globals.Lock()); serializes config file reloads
 }
 
 var globals globalsStruct
@@ -419,134 +661,27 @@ var globals globalsStruct
 // `initGlobals` initializes the globalsStruct and locates the configuration file's path.
 func initGlobals(osArgs []string) {
 	var (
-		homeEnv                         = os.Getenv("HOME")
-		mscConfigEnv                    = os.Getenv("MSC_CONFIG")
-		xdgConfigDir                    string
-		xdgConfigDirContainedConfigFile bool
-		xdgConfigDirsEnv                = os.Getenv("XDG_CONFIG_DIRS")
-		xdgConfigHomeEnv                = os.Getenv("XDG_CONFIG_HOME")
+		err                    error
+		explicitConfigFilePath string
 	)
 
-	globals.logger = log.New(os.Stdout, "", log.Ldate|log.Ltime|log.Lmsgprefix) // |log.Lmicroseconds|log.Lshortfile
+	_ = initLogging(nil)
 
-	globals.logger.Printf("[INFO] starting %s version %s", osArgs[0], Version)
+	globals.mountInstanceID = uuid.NewString()
+	globals.mountInstanceStartTime = time.Now()
+
+	globals.logger.Printf("[INFO] starting %s version %s (mount instance %s)", osArgs[0], Version, globals.mountInstanceID)
 
 	globals.backendsSkipped = make(map[string]struct{})
 
-	for {
-		if len(osArgs) == 2 {
-			if !checkForFile(osArgs[1]) {
-				dumpStack()
-				globals.logger.Fatalf("[FATAL] file not readable at \"%s\"", osArgs[1])
-			}
-			globals.configFilePath = osArgs[1]
-			break
-		}
-
-		if mscConfigEnv != "" {
-			if !checkForFile(mscConfigEnv) {
-				dumpStack()
-				globals.logger.Fatalf("[FATAL] file not readable at non-empty ${MSC_CONFIG} [\"%s\"]", mscConfigEnv)
-			}
-			globals.configFilePath = mscConfigEnv
-			break
-		}
-
-		if xdgConfigHomeEnv != "" {
-			if checkForFile(xdgConfigHomeEnv + "/msc/config.yaml") {
-				globals.configFilePath = xdgConfigHomeEnv + "/msc/config.yaml"
-				break
-			}
-			if checkForFile(xdgConfigHomeEnv + "/msc/config.yml") {
-				globals.configFilePath = xdgConfigHomeEnv + "/msc/config.yml"
-				break
-			}
-			if checkForFile(xdgConfigHomeEnv + "/msc/config.json") {
-				globals.configFilePath = xdgConfigHomeEnv + "/msc/config.json"
-				break
-			}
-		}
-
-		if homeEnv != "" {
-			if checkForFile(homeEnv + "/.msc_config.yaml") {
-				globals.configFilePath = homeEnv + "/.msc_config.yaml"
-				break
-			}
-			if checkForFile(homeEnv + "/.msc_config.yml") {
-				globals.configFilePath = homeEnv + "/.msc_config.yml"
-				break
-			}
-			if checkForFile(homeEnv + "/.msc_config.json") {
-				globals.configFilePath = homeEnv + "/.msc_config.json"
-				break
-			}
-
-			if checkForFile(homeEnv + "/.config/msc/config.yaml") {
-				globals.configFilePath = homeEnv + "/.config/msc/config.yaml"
-				break
-			}
-			if checkForFile(homeEnv + "/.config/msc/config.yml") {
-				globals.configFilePath = homeEnv + "/.config/msc/config.yml"
-				break
-			}
-			if checkForFile(homeEnv + "/.config/msc/config.json") {
-				globals.configFilePath = homeEnv + "/.config/msc/config.json"
-				break
-			}
-		}
-
-		if xdgConfigDirsEnv == "" {
-			if checkForFile("/etc/xdg/msc/config.yaml") {
-				globals.configFilePath = "/etc/xdg/msc/config.yaml"
-				break
-			}
-			if checkForFile("/etc/xdg/msc/config.yml") {
-				globals.configFilePath = "/etc/xdg/msc/config.yml"
-				break
-			}
-			if checkForFile("/etc/xdg/msc/config.json") {
-				globals.configFilePath = "/etc/xdg/msc/config.json"
-				break
-			}
-		} else { // xdgConfigDirsEnv != ""
-			xdgConfigDirContainedConfigFile = false
-			for _, xdgConfigDir = range strings.Split(xdgConfigDirsEnv, ":") {
-				if checkForFile(xdgConfigDir + "/msc/config.yaml") {
-					globals.configFilePath = xdgConfigDir + "/msc/config.yaml"
-					xdgConfigDirContainedConfigFile = true
-					break
-				}
-				if checkForFile(xdgConfigDir + "/msc/config.yml") {
-					globals.configFilePath = xdgConfigDir + "/msc/config.yml"
-					xdgConfigDirContainedConfigFile = true
-					break
-				}
-				if checkForFile(xdgConfigDir + "/msc/config.json") {
-					globals.configFilePath = xdgConfigDir + "/msc/config.json"
-					xdgConfigDirContainedConfigFile = true
-					break
-				}
-			}
-			if xdgConfigDirContainedConfigFile {
-				break
-			}
-		}
-
-		if checkForFile("/etc/msc_config.yaml") {
-			globals.configFilePath = "/etc/msc_config.yaml"
-			break
-		}
-		if checkForFile("/etc/msc_config.yml") {
-			globals.configFilePath = "/etc/msc_config.yml"
-			break
-		}
-		if checkForFile("/etc/msc_config.json") {
-			globals.configFilePath = "/etc/msc_config.json"
-			break
-		}
+	if len(osArgs) == 2 {
+		explicitConfigFilePath = osArgs[1]
+	}
 
+	globals.configFilePath, err = msc.FindConfigFile(explicitConfigFilePath)
+	if err != nil {
 		dumpStack()
-		globals.logger.Fatalf("[FATAL] config-file not found along search path")
+		globals.logger.Fatalf("[FATAL] %v", err)
 	}
 
 	globals.logger.Printf("[INFO] config-file path: \"%s\"", globals.configFilePath)
@@ -554,20 +689,16 @@ func initGlobals(osArgs []string) {
 	globals.config = nil
 	globals.backendsToUnmount = make(map[string]*backendStruct)
 	globals.backendsToMount = make(map[string]*backendStruct)
+	globals.backendsFailed = make(map[string]*backendStruct)
 
 	globals.errChan = make(chan error, 1)
-}
 
-// `checkForFile` indicates whether or not a file exists at filePath.
-func checkForFile(filePath string) (ok bool) {
-	fileInfo, err := os.Stat(filePath)
-	ok = (err == nil && !fileInfo.IsDir())
-	return
+	globals.reload.errLast = nil
 }
 
 // `fetchNonce` returns the next unique `number only used once` value.
 // Uses atomic increment so it is safe to call with or without globals.Lock().
 func fetchNonce() (nonce uint64) {
-	nonce = atomic.AddUint64(&globals.lastNonce, 1)
+	nonce = globals.lastNonce.Add(1)
 	return
 }
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	hedgeLatencySamplesMax        = 1024 // Size of the ring buffer of recent successful readFile latencies
+	hedgeLatencySamplesMin        = 32   // Number of samples required before hedging is enabled
+	hedgeDelayRecomputeInterval   = 64   // Number of samples between recomputations of hedgeStruct.delay
+	hedgeBudgetWindowReadCountMax = 1 << 16
+)
+
+// `hedgeStruct` tracks the state used to decide when a readFile() call to
+// a backend should be hedged by issuing a second, identical request.
+type hedgeStruct struct {
+	sync.Mutex
+	percentile     float64         // Latency percentile (0 < percentile < 100) after which a hedge is issued
+	minDelay       time.Duration   // Lower bound on delay
+	budget         uint64          // Maximum hedges issued as a percentage of readFile calls
+	samples        []time.Duration // Ring buffer of recent successful readFile latencies
+	samplesNext    int             // Index in samples to be overwritten by the next observation
+	sinceRecompute int             // Number of observations since delay was last recomputed
+	delay          time.Duration   // Current hedge delay; == 0 until len(samples) >= hedgeLatencySamplesMin
+	readCount      uint64          // Number of readFile calls in the current budget window
+	hedgeCount     uint64          // Number of hedges issued in the current budget window
+}
+
+// `hedgeResultStruct` carries the outcome of one of the (up to two)
+// readFile() calls issued by hedgedReadFileWrapper().
+type hedgeResultStruct struct {
+	hedged         bool
+	readFileOutput *readFileOutputStruct
+	err            error
+}
+
+// `newHedge` returns a hedgeStruct for a backend configured with
+// a non-zero hedge_read_percentile.
+func newHedge(percentile float64, minDelay time.Duration, budget uint64) (hedge *hedgeStruct) {
+	hedge = &hedgeStruct{
+		percentile: percentile,
+		minDelay:   minDelay,
+		budget:     budget,
+		samples:    make([]time.Duration, 0, hedgeLatencySamplesMax),
+	}
+
+	return
+}
+
+// `observe` records the latency of a successful readFile() call, periodically
+// recomputing the hedge delay from the configured percentile of recent samples.
+func (hedge *hedgeStruct) observe(latency time.Duration) {
+	var (
+		sorted      []time.Duration
+		sortedIndex int
+	)
+
+	hedge.Lock()
+	defer hedge.Unlock()
+
+	if len(hedge.samples) < hedgeLatencySamplesMax {
+		hedge.samples = append(hedge.samples, latency)
+	} else {
+		hedge.samples[hedge.samplesNext] = latency
+	}
+	hedge.samplesNext = (hedge.samplesNext + 1) % hedgeLatencySamplesMax
+
+	if len(hedge.samples) < hedgeLatencySamplesMin {
+		return
+	}
+
+	hedge.sinceRecompute++
+	if (hedge.delay != 0) && (hedge.sinceRecompute < hedgeDelayRecomputeInterval) {
+		return
+	}
+	hedge.sinceRecompute = 0
+
+	sorted = slices.Clone(hedge.samples)
+	slices.Sort(sorted)
+
+	sortedIndex = int(hedge.percentile / 100.0 * float64(len(sorted)-1))
+
+	hedge.delay = max(sorted[sortedIndex], hedge.minDelay)
+}
+
+// `admit` accounts for a new readFile() call and returns the delay after which
+// it should be hedged. If hedging is not yet enabled, ok == false is returned.
+func (hedge *hedgeStruct) admit() (delay time.Duration, ok bool) {
+	hedge.Lock()
+	defer hedge.Unlock()
+
+	hedge.readCount++
+	if hedge.readCount > hedgeBudgetWindowReadCountMax {
+		// Decay the budget window so that it tracks recent activity
+
+		hedge.readCount /= 2
+		hedge.hedgeCount /= 2
+	}
+
+	delay = hedge.delay
+	ok = (delay != 0)
+
+	return
+}
+
+// `tryHedge` returns true (and charges the budget) if issuing a hedge would
+// not exceed the configured budget.
+func (hedge *hedgeStruct) tryHedge() (ok bool) {
+	hedge.Lock()
+	defer hedge.Unlock()
+
+	if ((hedge.hedgeCount + 1) * 100) > (hedge.budget * hedge.readCount) {
+		ok = false
+		return
+	}
+
+	hedge.hedgeCount++
+	ok = true
+
+	return
+}
+
+// `hedgedReadFileWrapper` is a wrapper around readFileWrapper() that, for backends configured
+// with a non-zero hedge_read_percentile, issues a second identical readFile() if the first has
+// not completed within the current hedge delay. The first successful response is returned and
+// the losing request is cancelled (via its context) so that it does not continue to load the backend.
+func hedgedReadFileWrapper(ctx context.Context, backend *backendStruct, readFileInput *readFileInputStruct) (readFileOutput *readFileOutputStruct, err error) {
+	var (
+		cancelFuncs [2]context.CancelFunc // [0] cancels the original request, [1] cancels the hedge (if issued)
+		delay       time.Duration
+		hedge       = backend.hedge
+		hedgeResult *hedgeResultStruct
+		hedgeTimer  *time.Timer
+		ok          bool
+		outstanding int
+		resultChan  chan *hedgeResultStruct
+	)
+
+	if hedge == nil {
+		readFileOutput, err = readFileWrapper(ctx, backend.context, readFileInput)
+		return
+	}
+
+	delay, ok = hedge.admit()
+
+	resultChan = make(chan *hedgeResultStruct, 2) // Buffered so that an abandoned request never blocks
+
+	// Once a result is returned, cancel whichever request (if any) remains outstanding
+
+	defer func() {
+		for _, cancelFunc := range cancelFuncs {
+			if cancelFunc != nil {
+				cancelFunc()
+			}
+		}
+	}()
+
+	cancelFuncs[0] = hedgedReadFileLaunch(ctx, backend, readFileInput, false, resultChan)
+	outstanding = 1
+
+	if ok {
+		hedgeTimer = time.NewTimer(delay)
+
+		select {
+		case hedgeResult = <-resultChan:
+			_ = hedgeTimer.Stop()
+			readFileOutput, err = hedgeResult.readFileOutput, hedgeResult.err
+			return
+		case <-hedgeTimer.C:
+			if hedge.tryHedge() {
+				cancelFuncs[1] = hedgedReadFileLaunch(ctx, backend, readFileInput, true, resultChan)
+				outstanding++
+
+				recordHedgeMetrics(backend, false)
+			}
+		}
+	}
+
+	for outstanding > 0 {
+		hedgeResult = <-resultChan
+		outstanding--
+
+		readFileOutput, err = hedgeResult.readFileOutput, hedgeResult.err
+
+		if err == nil {
+			if hedgeResult.hedged {
+				recordHedgeMetrics(backend, true)
+			}
+			return
+		}
+	}
+
+	return
+}
+
+// `hedgedReadFileLaunch` issues a readFile() in a new goroutine, delivering its result to resultChan.
+// The returned cancelFunc cancels the readFile() (e.g. once the other request has won).
+func hedgedReadFileLaunch(ctx context.Context, backend *backendStruct, readFileInput *readFileInputStruct, hedged bool, resultChan chan *hedgeResultStruct) (cancelFunc context.CancelFunc) {
+	ctx, cancelFunc = context.WithCancel(ctx)
+
+	globals.dataCacheActivityWG.Add(1)
+
+	_ = startWorker("hedgedReadFile", func(_ context.Context) {
+		var (
+			hedgeResult = &hedgeResultStruct{hedged: hedged}
+			startTime   = time.Now()
+		)
+
+		defer globals.dataCacheActivityWG.Done()
+
+		hedgeResult.readFileOutput, hedgeResult.err = readFileWrapper(ctx, backend.context, readFileInput)
+		if hedgeResult.err == nil {
+			backend.hedge.observe(time.Since(startTime))
+		}
+
+		resultChan <- hedgeResult
+	})
+
+	return
+}
+
+// `recordHedgeMetrics` records either the issuance (won == false) or the win (won == true) of a hedge.
+// As Prometheus metrics synchronize themselves, no global lock is needed.
+func recordHedgeMetrics(backend *backendStruct, won bool) {
+	if won {
+		globals.backendMetrics.ReadFileHedgeWins.Inc()
+		backend.backendMetrics.ReadFileHedgeWins.Inc()
+	} else {
+		globals.backendMetrics.ReadFileHedges.Inc()
+		backend.backendMetrics.ReadFileHedges.Inc()
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// hedgeTestBackendContext wraps mockBackendContext such that the first readFile()
+// stalls for slowDelay (unless canceled) while all subsequent calls complete immediately.
+type hedgeTestBackendContext struct {
+	*mockBackendContext
+	readCalls    atomic.Uint64
+	slowCanceled chan struct{} // Closed if the stalled readFile() is canceled
+	slowDelay    time.Duration
+}
+
+func (h *hedgeTestBackendContext) readFile(ctx context.Context, readFileInput *readFileInputStruct) (*readFileOutputStruct, error) {
+	if h.readCalls.Add(1) == 1 {
+		select {
+		case <-time.After(h.slowDelay):
+			return &readFileOutputStruct{eTag: "slow", buf: []byte("slow")}, nil
+		case <-ctx.Done():
+			close(h.slowCanceled)
+			return nil, ctx.Err()
+		}
+	}
+	return &readFileOutputStruct{eTag: "fast", buf: []byte("fast")}, nil
+}
+
+func newHedgeTestBackend(budget uint64) (backend *backendStruct, hedgeContext *hedgeTestBackendContext) {
+	backend = newMockBackend("", nil)
+	backend.backendMetrics = newBackendMetrics()
+	backend.hedge = newHedge(50, time.Millisecond, budget)
+
+	hedgeContext = &hedgeTestBackendContext{
+		mockBackendContext: backend.context.(*mockBackendContext),
+		slowCanceled:       make(chan struct{}),
+		slowDelay:          time.Second,
+	}
+	backend.context = hedgeContext
+
+	globals.backendMetrics = newBackendMetrics()
+
+	return
+}
+
+func TestHedgeDelayFromPercentile(t *testing.T) {
+	hedge := newHedge(50, time.Millisecond, 5)
+
+	for i := 1; i < hedgeLatencySamplesMin; i++ {
+		hedge.observe(time.Duration(i) * 10 * time.Millisecond)
+	}
+	if _, ok := hedge.admit(); ok {
+		t.Fatalf("hedging should not be enabled before %d samples", hedgeLatencySamplesMin)
+	}
+
+	hedge.observe(time.Duration(hedgeLatencySamplesMin) * 10 * time.Millisecond)
+	delay, ok := hedge.admit()
+	if !ok {
+		t.Fatalf("hedging should be enabled after %d samples", hedgeLatencySamplesMin)
+	}
+	if delay != 160*time.Millisecond {
+		t.Fatalf("expected p50 delay of 160ms, got %v", delay)
+	}
+
+	hedge = newHedge(50, time.Second, 5)
+	for i := 0; i < hedgeLatencySamplesMin; i++ {
+		hedge.observe(time.Millisecond)
+	}
+	if delay, _ = hedge.admit(); delay != time.Second {
+		t.Fatalf("expected delay floored at hedge_read_min_delay (1s), got %v", delay)
+	}
+}
+
+func TestHedgeBudget(t *testing.T) {
+	hedge := newHedge(50, time.Millisecond, 10)
+
+	hedged := 0
+	for i := 0; i < 100; i++ {
+		_, _ = hedge.admit()
+		if hedge.tryHedge() {
+			hedged++
+		}
+	}
+	if hedged != 10 {
+		t.Fatalf("expected budget of 10%% to allow 10 hedges out of 100 reads, got %d", hedged)
+	}
+}
+
+func TestHedgedReadFileWrapper(t *testing.T) {
+	backend, hedgeContext := newHedgeTestBackend(100)
+
+	for i := 0; i < hedgeLatencySamplesMin; i++ {
+		backend.hedge.observe(time.Millisecond)
+	}
+	hedgeContext.readCalls.Store(0)
+
+	start := time.Now()
+	readFileOutput, err := hedgedReadFileWrapper(context.Background(), backend, &readFileInputStruct{filePath: "file"})
+	if err != nil {
+		t.Fatalf("hedgedReadFileWrapper() failed: %v", err)
+	}
+	if readFileOutput.eTag != "fast" {
+		t.Fatalf("expected hedged response to win, got eTag %q", readFileOutput.eTag)
+	}
+	if elapsed := time.Since(start); elapsed >= hedgeContext.slowDelay {
+		t.Fatalf("hedged read took %v; expected less than %v", elapsed, hedgeContext.slowDelay)
+	}
+	if hedgeContext.readCalls.Load() != 2 {
+		t.Fatalf("expected 2 readFile() calls, got %d", hedgeContext.readCalls.Load())
+	}
+
+	select {
+	case <-hedgeContext.slowCanceled:
+	case <-time.After(hedgeContext.slowDelay / 2):
+		t.Fatalf("expected the losing (original) readFile() to be canceled")
+	}
+}
+
+func TestHedgedReadFileWrapperBudgetExhausted(t *testing.T) {
+	backend, hedgeContext := newHedgeTestBackend(0)
+
+	for i := 0; i < hedgeLatencySamplesMin; i++ {
+		backend.hedge.observe(time.Millisecond)
+	}
+	hedgeContext.readCalls.Store(0)
+	hedgeContext.slowDelay = 50 * time.Millisecond
+
+	readFileOutput, err := hedgedReadFileWrapper(context.Background(), backend, &readFileInputStruct{filePath: "file"})
+	if err != nil {
+		t.Fatalf("hedgedReadFileWrapper() failed: %v", err)
+	}
+	if readFileOutput.eTag != "slow" {
+		t.Fatalf("expected no hedge with zero budget, got eTag %q", readFileOutput.eTag)
+	}
+	if hedgeContext.readCalls.Load() != 1 {
+		t.Fatalf("expected 1 readFile() call, got %d", hedgeContext.readCalls.Load())
+	}
+}
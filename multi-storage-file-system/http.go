@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -83,7 +85,7 @@ func startHTTPHandler() {
 		globals.logger.Fatalf("[FATAL] url.Parse(globals.config.endpoint) returned non-empty .Path: \"%s\"", parsedURL.Path)
 	}
 
-	go func(parsedURL *url.URL) {
+	_ = startWorker("httpServer", func(ctx context.Context) {
 		var (
 			err                    error
 			httpServer             *http.Server
@@ -99,12 +101,14 @@ func startHTTPHandler() {
 			ErrorLog:     httpServerLoggerLogger,
 		}
 
+		_ = context.AfterFunc(ctx, func() { _ = httpServer.Close() })
+
 		err = httpServer.ListenAndServe()
-		if err != nil {
+		if (err != nil) && !errors.Is(err, http.ErrServerClosed) {
 			dumpStack()
 			globals.logger.Fatalf("[FATAL] httpServer.ListenAndServe() failed: %v", err)
 		}
-	}(parsedURL)
+	})
 
 	globals.logger.Printf("[INFO] endpoint: %s://%s", parsedURL.Scheme, parsedURL.Host)
 }
@@ -114,6 +118,10 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		backend                   *backendStruct
 		backendName               string
 		backendNames              []string
+		busy                      bool
+		err                       error
+		fhNonce                   uint64
+		found                     bool
 		globalsLockMaxHoldEntries []GlobalsLockMaxHoldEntry
 		globalsLockMaxHoldEntry   GlobalsLockMaxHoldEntry
 		globalsLockedHolderSite   string
@@ -131,7 +139,12 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		holdSumAsStringMaxLen     int
 		locksSortDirective        string
 		numDrained                uint64
+		openHandle                openHandleStruct
+		openHandleMode            string
+		openHandles               []openHandleStruct
 		registry                  *prometheus.Registry
+		timeNow                   time.Time
+		workerKindStatus          workerKindStatusStruct
 	)
 
 	switch {
@@ -144,10 +157,14 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			fmt.Fprintf(w, "  <li><a href=\"/backends\">/backends</a></li>\n")
 			fmt.Fprintf(w, "  <li><a href=\"/drain\">/drain</a></li>\n")
 			fmt.Fprintf(w, "  <li><a href=\"/dump\">/dump</a></li>\n")
+			fmt.Fprintf(w, "  <li><a href=\"/events\">/events</a></li>\n")
 			fmt.Fprintf(w, "  <li><a href=\"/hang\">/hang</a></li>\n")
 			fmt.Fprintf(w, "  <li><a href=\"/locks\">/locks</a></li>\n")
 			fmt.Fprintf(w, "  <li><a href=\"/metrics\">/metrics</a></li>\n")
-			globalsLock("http.go:150:4:(*globalsStruct).ServeHTTP")
+			fmt.Fprintf(w, "  <li><a href=\"/open-handles\">/open-handles</a></li>\n")
+			fmt.Fprintf(w, "  <li>/remount/&lt;dir_name&gt;</li>\n")
+			fmt.Fprintf(w, "  <li><a href=\"/status\">/status</a></li>\n")
+			globalsLock("http.go:167:4:(*globalsStruct).ServeHTTP")
 			backendNames = make([]string, 0, len(globals.config.backends))
 			for _, backend = range globals.config.backends {
 				backendNames = append(backendNames, backend.dirName)
@@ -164,10 +181,15 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			fmt.Fprintf(w, "  /backends\n")
 			fmt.Fprintf(w, "  /drain\n")
 			fmt.Fprintf(w, "  /dump\n")
+			fmt.Fprintf(w, "  /events\n")
 			fmt.Fprintf(w, "  /hang\n")
 			fmt.Fprintf(w, "  /locks\n")
 			fmt.Fprintf(w, "  /metrics\n")
-			globalsLock("http.go:170:4:(*globalsStruct).ServeHTTP")
+			fmt.Fprintf(w, "  /open-handles\n")
+			fmt.Fprintf(w, "  /open-handles/release/<fh>\n")
+			fmt.Fprintf(w, "  /remount/<dir_name>\n")
+			fmt.Fprintf(w, "  /status\n")
+			globalsLock("http.go:192:4:(*globalsStruct).ServeHTTP")
 			backendNames = make([]string, 0, len(globals.config.backends))
 			for _, backend = range globals.config.backends {
 				backendNames = append(backendNames, backend.dirName)
@@ -181,7 +203,7 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case r.RequestURI == "/backends":
 		w.WriteHeader(http.StatusOK)
 
-		globalsLock("http.go:184:3:(*globalsStruct).ServeHTTP")
+		globalsLock("http.go:206:3:(*globalsStruct).ServeHTTP")
 
 		for _, backend = range globals.config.backends {
 			fmt.Fprintf(w, "%s\n", backend.dirName)
@@ -190,7 +212,7 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		globalsUnlock()
 
 	case r.RequestURI == "/drain":
-		globalsLock("http.go:193:3:(*globalsStruct).ServeHTTP")
+		globalsLock("http.go:215:3:(*globalsStruct).ServeHTTP")
 
 		numDrained = inodeEvictorForceDrain()
 
@@ -218,7 +240,7 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			locksSortDirective = "sum"
 		}
 
-		globalsLock("http.go:221:3:(*globalsStruct).ServeHTTP")
+		globalsLock("http.go:243:3:(*globalsStruct).ServeHTTP")
 		globalsLockMaxHoldEntries = GlobalsLockMaxHoldDurations()
 		globalsUnlock()
 
@@ -287,7 +309,7 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case r.RequestURI == "/metrics":
 		registry = prometheus.NewRegistry()
 
-		globalsLock("http.go:290:3:(*globalsStruct).ServeHTTP")
+		globalsLock("http.go:312:3:(*globalsStruct).ServeHTTP")
 
 		registerFissionMetrics(registry, globals.fissionMetrics)
 		registerBackendMetrics(registry, globals.backendMetrics)
@@ -305,7 +327,7 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		globalsLock("http.go:308:3:(*globalsStruct).ServeHTTP")
+		globalsLock("http.go:330:3:(*globalsStruct).ServeHTTP")
 
 		backend = globals.config.backends[backendName]
 		if backend == nil {
@@ -324,16 +346,105 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
 
+	case r.RequestURI == "/events":
+		serveEvents(w, r)
+
+	case r.RequestURI == "/open-handles":
+		openHandles = listOpenHandles()
+		timeNow = time.Now()
+
+		w.WriteHeader(http.StatusOK)
+		for _, openHandle = range openHandles {
+			if openHandle.allowWrites {
+				openHandleMode = "rw"
+			} else {
+				openHandleMode = "ro"
+			}
+			fmt.Fprintf(w, "fh %v inode %v %s pid %v uid %v gid %v age %v path %s\n",
+				openHandle.fhNonce,
+				openHandle.inodeNumber,
+				openHandleMode,
+				openHandle.openerPID,
+				openHandle.openerUID,
+				openHandle.openerGID,
+				timeNow.Sub(openHandle.openTime).Truncate(time.Second),
+				openHandle.path)
+		}
+
+	case strings.HasPrefix(r.RequestURI, "/open-handles/release/"):
+		fhNonce, err = strconv.ParseUint(strings.TrimPrefix(r.RequestURI, "/open-handles/release/"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "bad fh - must be a decimal number as listed by /open-handles\n")
+			return
+		}
+
+		found, busy = forceReleaseFH(fhNonce)
+		switch {
+		case !found:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, "fh %v not found\n", fhNonce)
+		case busy:
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprintf(w, "fh %v busy (directory listing in progress)\n", fhNonce)
+		default:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "fh %v released\n", fhNonce)
+		}
+
+	case strings.HasPrefix(r.RequestURI, "/remount/"):
+		backendName = strings.TrimPrefix(r.RequestURI, "/remount/")
+		if backendName == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "backend name required\n")
+			return
+		}
+
+		found, err = remountBackend(backendName)
+		switch {
+		case !found:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, "backend %q not found\n", backendName)
+		case err != nil:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "%v\n", err)
+		default:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "backend %q remounted\n", backendName)
+		}
+
+	case r.RequestURI == "/status":
+		w.WriteHeader(http.StatusOK)
+		for _, workerKindStatus = range workersStatus() {
+			fmt.Fprintf(w, "worker %s started %v running %v completed %v panicked %v",
+				workerKindStatus.Kind,
+				workerKindStatus.Started,
+				workerKindStatus.Running,
+				workerKindStatus.Completed,
+				workerKindStatus.Panicked)
+			if workerKindStatus.Panicked > 0 {
+				fmt.Fprintf(w, " last-panic-age %v last-panic %q",
+					time.Since(workerKindStatus.LastPanicTime).Truncate(time.Second),
+					workerKindStatus.LastPanic)
+			}
+			fmt.Fprintf(w, "\n")
+		}
+
 	default:
 		w.WriteHeader(http.StatusNotFound)
 		fmt.Fprintf(w, "unknown endpoint - must be one of:\n")
 		fmt.Fprintf(w, "  /backends\n")
 		fmt.Fprintf(w, "  /drain\n")
 		fmt.Fprintf(w, "  /dump\n")
+		fmt.Fprintf(w, "  /events\n")
 		fmt.Fprintf(w, "  /hang\n")
 		fmt.Fprintf(w, "  /locks\n")
 		fmt.Fprintf(w, "  /metrics\n")
-		globalsLock("http.go:336:3:(*globalsStruct).ServeHTTP")
+		fmt.Fprintf(w, "  /open-handles\n")
+		fmt.Fprintf(w, "  /open-handles/release/<fh>\n")
+		fmt.Fprintf(w, "  /remount/<dir_name>\n")
+		fmt.Fprintf(w, "  /status\n")
+		globalsLock("http.go:447:3:(*globalsStruct).ServeHTTP")
 		for _, backend = range globals.config.backends {
 			fmt.Fprintf(w, "  /metrics/%s\n", backend.dirName)
 		}
@@ -341,6 +452,53 @@ func (*globalsStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// `serveEvents` streams each subsequently published event (see publishEvent()) as a
+// Server-Sent Event until the client disconnects.
+func serveEvents(w http.ResponseWriter, r *http.Request) {
+	var (
+		err                error
+		eventLine          []byte
+		responseController = http.NewResponseController(w)
+		subscriberChan     chan []byte
+	)
+
+	// Streamed responses must not be subject to HTTP_SERVER_WRITE_TIMEOUT
+
+	err = responseController.SetWriteDeadline(time.Time{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "streaming not supported: %v\n", err)
+		return
+	}
+
+	subscriberChan = subscribeEvents()
+	defer unsubscribeEvents(subscriberChan)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	err = responseController.Flush()
+	if err != nil {
+		return
+	}
+
+	for {
+		select {
+		case eventLine = <-subscriberChan:
+			_, err = fmt.Fprintf(w, "data: %s\n", eventLine) // eventLine already ends in '\n'
+			if err == nil {
+				err = responseController.Flush()
+			}
+			if err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
 func registerFissionMetrics(registry *prometheus.Registry, m *fissionMetricsStruct) {
 	if m == nil {
 		dumpStack()
@@ -354,6 +512,14 @@ func registerFissionMetrics(registry *prometheus.Registry, m *fissionMetricsStru
 	registry.MustRegister(m.GetAttrFailures)
 	registry.MustRegister(m.GetAttrSuccessLatencies)
 	registry.MustRegister(m.GetAttrFailureLatencies)
+	registry.MustRegister(m.ReadLinkSuccesses)
+	registry.MustRegister(m.ReadLinkFailures)
+	registry.MustRegister(m.ReadLinkSuccessLatencies)
+	registry.MustRegister(m.ReadLinkFailureLatencies)
+	registry.MustRegister(m.SymLinkSuccesses)
+	registry.MustRegister(m.SymLinkFailures)
+	registry.MustRegister(m.SymLinkSuccessLatencies)
+	registry.MustRegister(m.SymLinkFailureLatencies)
 	registry.MustRegister(m.MkNodSuccesses)
 	registry.MustRegister(m.MkNodFailures)
 	registry.MustRegister(m.MkNodSuccessLatencies)
@@ -370,6 +536,10 @@ func registerFissionMetrics(registry *prometheus.Registry, m *fissionMetricsStru
 	registry.MustRegister(m.RmDirFailures)
 	registry.MustRegister(m.RmDirSuccessLatencies)
 	registry.MustRegister(m.RmDirFailureLatencies)
+	registry.MustRegister(m.RenameSuccesses)
+	registry.MustRegister(m.RenameFailures)
+	registry.MustRegister(m.RenameSuccessLatencies)
+	registry.MustRegister(m.RenameFailureLatencies)
 	registry.MustRegister(m.OpenSuccesses)
 	registry.MustRegister(m.OpenFailures)
 	registry.MustRegister(m.OpenSuccessLatencies)
@@ -384,6 +554,7 @@ func registerFissionMetrics(registry *prometheus.Registry, m *fissionMetricsStru
 	registry.MustRegister(m.ReadCacheMisses)
 	registry.MustRegister(m.ReadCacheWaits)
 	registry.MustRegister(m.ReadCachePrefetches)
+	registry.MustRegister(m.ReadCacheBypasses)
 	registry.MustRegister(m.StatFSCalls)
 	registry.MustRegister(m.ReleaseSuccesses)
 	registry.MustRegister(m.ReleaseFailures)
@@ -415,6 +586,26 @@ func registerFissionMetrics(registry *prometheus.Registry, m *fissionMetricsStru
 	registry.MustRegister(m.StatXFailures)
 	registry.MustRegister(m.StatXSuccessLatencies)
 	registry.MustRegister(m.StatXFailureLatencies)
+	registry.MustRegister(m.AccessSuccesses)
+	registry.MustRegister(m.AccessFailures)
+	registry.MustRegister(m.AccessSuccessLatencies)
+	registry.MustRegister(m.AccessFailureLatencies)
+	registry.MustRegister(m.FAllocateSuccesses)
+	registry.MustRegister(m.FAllocateFailures)
+	registry.MustRegister(m.FAllocateSuccessLatencies)
+	registry.MustRegister(m.FAllocateFailureLatencies)
+	registry.MustRegister(m.LSeekSuccesses)
+	registry.MustRegister(m.LSeekFailures)
+	registry.MustRegister(m.LSeekSuccessLatencies)
+	registry.MustRegister(m.LSeekFailureLatencies)
+	registry.MustRegister(m.GetXAttrSuccesses)
+	registry.MustRegister(m.GetXAttrFailures)
+	registry.MustRegister(m.GetXAttrSuccessLatencies)
+	registry.MustRegister(m.GetXAttrFailureLatencies)
+	registry.MustRegister(m.ListXAttrSuccesses)
+	registry.MustRegister(m.ListXAttrFailures)
+	registry.MustRegister(m.ListXAttrSuccessLatencies)
+	registry.MustRegister(m.ListXAttrFailureLatencies)
 }
 
 func registerBackendMetrics(registry *prometheus.Registry, m *backendMetricsStruct) {
@@ -422,6 +613,10 @@ func registerBackendMetrics(registry *prometheus.Registry, m *backendMetricsStru
 		dumpStack()
 		globals.logger.Fatalf("[FATAL] registerBackendMetrics() passed a nil *backendMetricsStruct")
 	}
+	registry.MustRegister(m.CopyFileSuccesses)
+	registry.MustRegister(m.CopyFileFailures)
+	registry.MustRegister(m.CopyFileSuccessLatencies)
+	registry.MustRegister(m.CopyFileFailureLatencies)
 	registry.MustRegister(m.DeleteFileSuccesses)
 	registry.MustRegister(m.DeleteFileFailures)
 	registry.MustRegister(m.DeleteFileSuccessLatencies)
@@ -434,10 +629,16 @@ func registerBackendMetrics(registry *prometheus.Registry, m *backendMetricsStru
 	registry.MustRegister(m.ListObjectsFailures)
 	registry.MustRegister(m.ListObjectsSuccessLatencies)
 	registry.MustRegister(m.ListObjectsFailureLatencies)
+	registry.MustRegister(m.PutFileSuccesses)
+	registry.MustRegister(m.PutFileFailures)
+	registry.MustRegister(m.PutFileSuccessLatencies)
+	registry.MustRegister(m.PutFileFailureLatencies)
 	registry.MustRegister(m.ReadFileSuccesses)
 	registry.MustRegister(m.ReadFileFailures)
 	registry.MustRegister(m.ReadFileSuccessLatencies)
 	registry.MustRegister(m.ReadFileFailureLatencies)
+	registry.MustRegister(m.ReadFileHedges)
+	registry.MustRegister(m.ReadFileHedgeWins)
 	registry.MustRegister(m.StatDirectorySuccesses)
 	registry.MustRegister(m.StatDirectoryFailures)
 	registry.MustRegister(m.StatDirectorySuccessLatencies)
@@ -446,5 +647,17 @@ func registerBackendMetrics(registry *prometheus.Registry, m *backendMetricsStru
 	registry.MustRegister(m.StatFileFailures)
 	registry.MustRegister(m.StatFileSuccessLatencies)
 	registry.MustRegister(m.StatFileFailureLatencies)
+	registry.MustRegister(m.RetryAttempts)
+	registry.MustRegister(m.RetryDelays)
+	registry.MustRegister(m.RetriesExhausted)
 	registry.MustRegister(m.DirectoryPrefetchLatencies)
+	registry.MustRegister(m.CacheLineHits)
+	registry.MustRegister(m.CacheLineMisses)
+	registry.MustRegister(m.CacheLineFetchLatencies)
+	registry.MustRegister(m.CacheLineFetchesCoalesced)
+	registry.MustRegister(m.CacheLineEvictions)
+	registry.MustRegister(m.CacheLineInvalidations)
+	registry.MustRegister(m.ThrottledRequests)
+	registry.MustRegister(m.ThrottleDelays)
+	registry.MustRegister(m.MultipartUploadsAborted)
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// `startMultipartUploadJanitor` launches a worker calling abortAbandonedMultipartUploads()
+// every globals.config.multipartUploadJanitorInterval (if != 0).
+func startMultipartUploadJanitor() {
+	var (
+		multipartUploadJanitorInterval = globals.config.multipartUploadJanitorInterval
+	)
+
+	if multipartUploadJanitorInterval == 0 {
+		return
+	}
+
+	_ = startWorker("multipartUploadJanitor", func(ctx context.Context) {
+		var (
+			ticker = time.NewTicker(multipartUploadJanitorInterval)
+		)
+
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				abortAbandonedMultipartUploads(ctx)
+			}
+		}
+	})
+}
+
+// `abortAbandonedMultipartUploads` aborts the multipart uploads (e.g. of a server-side copy
+// interrupted by a crash) initiated more than multipart_upload_max_age ago in each mounted
+// backend that is not readonly (as only those initiate multipart uploads). Backends without
+// multipart uploads are skipped.
+func abortAbandonedMultipartUploads(ctx context.Context) {
+	var (
+		abortMultipartUploadsInput  *abortMultipartUploadsInputStruct
+		abortMultipartUploadsOutput *abortMultipartUploadsOutputStruct
+		aborted                     abortedMultipartUploadStruct
+		backend                     *backendStruct
+		backends                    []*backendStruct
+		err                         error
+	)
+
+	globalsLock("janitor.go:52:2:abortAbandonedMultipartUploads")
+
+	if globals.config.readOnly {
+		globalsUnlock()
+		return
+	}
+
+	abortMultipartUploadsInput = &abortMultipartUploadsInputStruct{
+		initiatedBefore: time.Now().Add(-globals.config.multipartUploadMaxAge),
+	}
+
+	backends = make([]*backendStruct, 0, len(globals.config.backends))
+	for _, backend = range globals.config.backends {
+		if backend.mounted && !backend.readOnly {
+			backends = append(backends, backend)
+		}
+	}
+
+	globalsUnlock()
+
+	for _, backend = range backends {
+		abortMultipartUploadsOutput, err = abortMultipartUploadsWrapper(ctx, backend.context, abortMultipartUploadsInput)
+		if err != nil {
+			if !errors.Is(err, errAbortMultipartUploadsNotSupported) && (ctx.Err() == nil) {
+				globals.logger.Printf("[WARN] aborting abandoned multipart uploads of backend \"%s\" failed: %s", backend.dirName, redactSecrets(backend, err.Error()))
+			}
+			continue
+		}
+
+		for _, aborted = range abortMultipartUploadsOutput.aborted {
+			globals.logger.Printf("[INFO] aborted abandoned multipart upload of \"%s/%s\" (initiated %s)", backend.dirName, aborted.filePath, aborted.initiated.UTC().Format(time.RFC3339))
+		}
+	}
+}
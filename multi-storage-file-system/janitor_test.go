@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestAbortAbandonedMultipartUploads verifies that abortAbandonedMultipartUploads() aborts only
+// those multipart uploads initiated more than multipart_upload_max_age ago.
+func TestAbortAbandonedMultipartUploads(t *testing.T) {
+	var (
+		freshUploadID string
+		now           = time.Now()
+		staleUploadID string
+	)
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	staleUploadID = testGlobals.testS3Server.putMultipartUpload(testFissionS3Bucket, "dir1/stale", now.Add(-2*time.Hour))
+	freshUploadID = testGlobals.testS3Server.putMultipartUpload(testFissionS3Bucket, "dir1/fresh", now)
+
+	globalsLock("janitor_test.go:24:2:TestAbortAbandonedMultipartUploads")
+	globals.config.multipartUploadMaxAge = time.Hour
+	globalsUnlock()
+
+	abortAbandonedMultipartUploads(context.Background())
+
+	if testGlobals.testS3Server.hasMultipartUpload(staleUploadID) {
+		t.Fatalf("abortAbandonedMultipartUploads() should have aborted the stale multipart upload")
+	}
+	if !testGlobals.testS3Server.hasMultipartUpload(freshUploadID) {
+		t.Fatalf("abortAbandonedMultipartUploads() should not have aborted the fresh multipart upload")
+	}
+}
@@ -0,0 +1,490 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	LogFormatPlain = "plain" // "<date> <time> [<LEVEL>] <msg> [<key>=<value>...]" (matching the historical log.Logger output)
+	LogFormatText  = "text"  // slog.TextHandler (logfmt-style "key=value" pairs)
+	LogFormatJSON  = "json"  // slog.JSONHandler (one JSON object per line)
+)
+
+const (
+	logLevelTrace = slog.LevelDebug - 4 // Below slog.LevelDebug; used by "[TRACE]" lines
+	logLevelFatal = slog.LevelError + 4 // Above slog.LevelError; used by "[FATAL]" lines (immediately followed by os.Exit(1))
+)
+
+const (
+	logFileMaxBackupsDefault = 5 // Default number of rotated log files retained
+)
+
+// `loggingStruct` holds the slog.Handler through which all logging flows. The
+// historical globals.logger (a *log.Logger) remains in place for its Printf/Fatalf
+// call sites but now writes through a logBridgeStruct that converts each line's
+// "[<LEVEL>]" and "[<COMPONENT>]" tags into a structured slog.Record.
+type loggingStruct struct {
+	handler slog.Handler   // Accepts records of every level; filtering is per-source (see level below and backendStruct.logLevel)
+	level   *slog.LevelVar // Minimum level of records logged via globals.logger
+	file    io.Closer      // If logging to a file, the (possibly rotating) file to close upon exit; otherwise nil
+
+	destinationWriter *logDestinationWriterStruct // If --log-destination is other than LogDestinationStdout, used in place of os.Stdout (see openLogDestination())
+}
+
+// `initLogging` (re)initializes globals.logging and globals.logger. Prior to the
+// config file being parsed (i.e. if config == nil), logging is in LogFormatPlain to
+// os.Stdout (or the --log-destination) at logLevelTrace (i.e. everything is logged).
+func initLogging(config *configStruct) (err error) {
+	var (
+		format     = LogFormatPlain
+		level      = logLevelTrace
+		logFile    *rotatingFileStruct
+		out        io.Writer
+		oldLogFile io.Closer
+	)
+
+	out = os.Stdout
+
+	if config != nil {
+		format = config.logFormat
+		level = config.logLevel
+
+		if config.logFile != "" {
+			logFile, err = openRotatingFile(config.logFile, config.logFileMaxSize, config.logFileMaxBackups)
+			if err != nil {
+				return
+			}
+			out = logFile
+		}
+	}
+
+	oldLogFile = globals.logging.file
+
+	globals.logging.level = &slog.LevelVar{}
+	globals.logging.level.Set(level)
+	if (logFile == nil) && (globals.logging.destinationWriter != nil) {
+		globals.logging.handler = &logDestinationHandlerStruct{
+			Handler: newLogHandler(format, globals.logging.destinationWriter),
+			writer:  globals.logging.destinationWriter,
+		}
+	} else {
+		globals.logging.handler = newLogHandler(format, out)
+	}
+	if logFile == nil {
+		globals.logging.file = nil
+	} else {
+		globals.logging.file = logFile
+	}
+
+	globals.logger = log.New(&logBridgeStruct{}, "", 0)
+
+	if oldLogFile != nil {
+		_ = oldLogFile.Close()
+	}
+
+	return
+}
+
+// `closeLogging` closes any log file (subsequent logging reverts to os.Stdout).
+func closeLogging() {
+	if globals.logging.file != nil {
+		_ = initLogging(nil)
+	}
+}
+
+// `newLogHandler` returns a slog.Handler (enabled for all levels) writing to out in format.
+func newLogHandler(format string, out io.Writer) (handler slog.Handler) {
+	var (
+		handlerOptions = &slog.HandlerOptions{
+			Level:       logLevelTrace,
+			ReplaceAttr: logReplaceLevelAttr,
+		}
+	)
+
+	switch format {
+	case LogFormatText:
+		handler = slog.NewTextHandler(out, handlerOptions)
+	case LogFormatJSON:
+		handler = slog.NewJSONHandler(out, handlerOptions)
+	default:
+		handler = &plainLogHandlerStruct{mutex: &sync.Mutex{}, out: out}
+	}
+
+	return
+}
+
+// `logReplaceLevelAttr` renders logLevelTrace and logLevelFatal as "TRACE" and "FATAL"
+// (rather than slog's default "DEBUG-4" and "ERROR+4").
+func logReplaceLevelAttr(groups []string, attr slog.Attr) slog.Attr {
+	if (len(groups) == 0) && (attr.Key == slog.LevelKey) {
+		if level, ok := attr.Value.Any().(slog.Level); ok {
+			attr.Value = slog.StringValue(logLevelString(level))
+		}
+	}
+
+	return attr
+}
+
+// `logLevelString` returns the "[<LEVEL>]" tag (sans brackets) used for level.
+func logLevelString(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return "TRACE"
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARN"
+	case level < logLevelFatal:
+		return "ERROR"
+	default:
+		return "FATAL"
+	}
+}
+
+// `parseLogLevel` converts a "log_level" config setting (case insensitive) to an slog.Level.
+func parseLogLevel(levelAsString string) (level slog.Level, ok bool) {
+	ok = true
+
+	switch strings.ToLower(levelAsString) {
+	case "trace":
+		level = logLevelTrace
+	case "debug":
+		level = slog.LevelDebug
+	case "info":
+		level = slog.LevelInfo
+	case "warn":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	default:
+		ok = false
+	}
+
+	return
+}
+
+// `traceLevelToLogLevel` maps the deprecated per-backend "trace_level" setting
+// to its "log_level" equivalent.
+func traceLevelToLogLevel(traceLevel uint64) slog.Level {
+	switch traceLevel {
+	case 0:
+		return slog.LevelError // Trace nothing (backend call failures are logged at slog.LevelWarn)
+	case 1:
+		return slog.LevelWarn // Trace failures
+	case 2:
+		return slog.LevelInfo // Trace failures and successes
+	default:
+		return slog.LevelDebug // Trace failures and successes (with details)
+	}
+}
+
+// `logBridgeStruct` is the io.Writer behind globals.logger. Each Write() is a single
+// (newline terminated) log.Logger line that is parsed by parseLogLine() and handed to
+// globals.logging.handler if its level is enabled.
+type logBridgeStruct struct{}
+
+func (*logBridgeStruct) Write(p []byte) (n int, err error) {
+	var (
+		component string
+		level     slog.Level
+		msg       string
+		record    slog.Record
+	)
+
+	n = len(p)
+
+	level, component, msg = parseLogLine(strings.TrimSuffix(string(p), "\n"))
+
+	if level < globals.logging.level.Level() {
+		return
+	}
+
+	record = slog.NewRecord(time.Now(), level, msg, 0)
+	if component != "" {
+		record.AddAttrs(slog.String("component", component))
+	}
+
+	err = globals.logging.handler.Handle(context.Background(), record)
+
+	return
+}
+
+// `parseLogLine` strips the leading "[<TAG>] " tags (each composed of upper case
+// letters, digits, "-", and "_") from line. A tag naming a level (e.g. "[WARN]")
+// determines the returned level (slog.LevelInfo if absent) while any others (e.g.
+// the "[FISSION]" prefix of package fission's logger or the "[PEBBLE]" tag of
+// pebbleLogger) are joined (with "/") into the returned component.
+func parseLogLine(line string) (level slog.Level, component string, msg string) {
+	var (
+		components []string
+		end        int
+		tag        string
+	)
+
+	level = slog.LevelInfo
+	msg = line
+
+	for strings.HasPrefix(msg, "[") {
+		end = strings.Index(msg, "]")
+		if end < 0 {
+			break
+		}
+
+		tag = msg[1:end]
+		if !isLogTag(tag) {
+			break
+		}
+
+		switch tag {
+		case "TRACE":
+			level = logLevelTrace
+		case "DEBUG":
+			level = slog.LevelDebug
+		case "INFO":
+			level = slog.LevelInfo
+		case "WARN":
+			level = slog.LevelWarn
+		case "ERROR":
+			level = slog.LevelError
+		case "FATAL":
+			level = logLevelFatal
+		default:
+			components = append(components, tag)
+		}
+
+		msg = strings.TrimPrefix(msg[end+1:], " ")
+	}
+
+	component = strings.Join(components, "/")
+
+	return
+}
+
+// `isLogTag` returns whether or not tag is non-empty and composed solely of upper
+// case letters, digits, "-", and "_".
+func isLogTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	for _, r := range tag {
+		if ((r < 'A') || (r > 'Z')) && ((r < '0') || (r > '9')) && (r != '-') && (r != '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// `logEnabled` returns whether or not the backend will log at level.
+func (backend *backendStruct) logEnabled(level slog.Level) bool {
+	return level >= backend.logLevel.Level()
+}
+
+// `logf` logs (if enabled by the backend's log_level) a message with a "backend" attribute.
+func (backend *backendStruct) logf(level slog.Level, format string, args ...interface{}) {
+	var (
+		record slog.Record
+	)
+
+	if !backend.logEnabled(level) || (globals.logging.handler == nil) {
+		return
+	}
+
+	record = slog.NewRecord(time.Now(), level, fmt.Sprintf(format, args...), 0)
+	record.AddAttrs(slog.String("backend", backend.dirName))
+
+	_ = globals.logging.handler.Handle(context.Background(), record)
+}
+
+// `plainLogHandlerStruct` is an slog.Handler emitting LogFormatPlain lines.
+type plainLogHandlerStruct struct {
+	mutex *sync.Mutex // Shared by all plainLogHandlerStruct's derived (via WithAttrs()) from the same newLogHandler() call
+	out   io.Writer
+	attrs []slog.Attr
+}
+
+func (*plainLogHandlerStruct) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (plainLogHandler *plainLogHandlerStruct) Handle(_ context.Context, record slog.Record) (err error) {
+	var (
+		attr slog.Attr
+		buf  strings.Builder
+	)
+
+	buf.WriteString(record.Time.Format("2006/01/02 15:04:05"))
+	buf.WriteString(" [")
+	buf.WriteString(logLevelString(record.Level))
+	buf.WriteString("] ")
+
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "component" {
+			buf.WriteString("[" + attr.Value.String() + "] ")
+		}
+		return true
+	})
+
+	buf.WriteString(record.Message)
+
+	for _, attr = range plainLogHandler.attrs {
+		buf.WriteString(" " + attr.String())
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		if (attr.Key != "component") && (attr.Key != "backend") {
+			buf.WriteString(" " + attr.String())
+		}
+		return true
+	})
+
+	buf.WriteString("\n")
+
+	plainLogHandler.mutex.Lock()
+	_, err = io.WriteString(plainLogHandler.out, buf.String())
+	plainLogHandler.mutex.Unlock()
+
+	return
+}
+
+func (plainLogHandler *plainLogHandlerStruct) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &plainLogHandlerStruct{
+		mutex: plainLogHandler.mutex,
+		out:   plainLogHandler.out,
+		attrs: append(append([]slog.Attr{}, plainLogHandler.attrs...), attrs...),
+	}
+}
+
+func (plainLogHandler *plainLogHandlerStruct) WithGroup(_ string) slog.Handler {
+	return plainLogHandler
+}
+
+// `rotatingFileStruct` is an io.WriteCloser appending to a log file that, once a
+// write would grow it beyond maxSize (if != 0), is renamed to "<path>.1" (after
+// first renaming "<path>.<n>" to "<path>.<n+1>" for each retained older file and
+// removing any beyond maxBackups) with a new log file taking its place.
+type rotatingFileStruct struct {
+	sync.Mutex
+	path       string
+	maxSize    uint64
+	maxBackups uint64
+	file       *os.File
+	size       uint64
+}
+
+// `openRotatingFile` opens (appending to) the log file at path.
+func openRotatingFile(path string, maxSize uint64, maxBackups uint64) (rotatingFile *rotatingFileStruct, err error) {
+	rotatingFile = &rotatingFileStruct{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	err = rotatingFile.open()
+	if err != nil {
+		rotatingFile = nil
+	}
+
+	return
+}
+
+// `open` is called while holding rotatingFile.Lock() (or before it is shared) to
+// open (appending to) rotatingFile.path.
+func (rotatingFile *rotatingFileStruct) open() (err error) {
+	var (
+		fileInfo os.FileInfo
+	)
+
+	rotatingFile.file, err = os.OpenFile(rotatingFile.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return
+	}
+
+	fileInfo, err = rotatingFile.file.Stat()
+	if err != nil {
+		_ = rotatingFile.file.Close()
+		rotatingFile.file = nil
+		return
+	}
+
+	rotatingFile.size = uint64(fileInfo.Size())
+
+	return
+}
+
+// `rotate` is called while holding rotatingFile.Lock() to shift each retained
+// log file down one generation and start a new (empty) log file.
+func (rotatingFile *rotatingFileStruct) rotate() (err error) {
+	var (
+		generation uint64
+	)
+
+	_ = rotatingFile.file.Close()
+	rotatingFile.file = nil
+
+	if rotatingFile.maxBackups == 0 {
+		_ = os.Remove(rotatingFile.path)
+	} else {
+		_ = os.Remove(fmt.Sprintf("%s.%d", rotatingFile.path, rotatingFile.maxBackups))
+
+		for generation = rotatingFile.maxBackups - 1; generation > 0; generation-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", rotatingFile.path, generation), fmt.Sprintf("%s.%d", rotatingFile.path, generation+1))
+		}
+
+		err = os.Rename(rotatingFile.path, rotatingFile.path+".1")
+		if err != nil {
+			return
+		}
+	}
+
+	err = rotatingFile.open()
+
+	return
+}
+
+func (rotatingFile *rotatingFileStruct) Write(p []byte) (n int, err error) {
+	rotatingFile.Lock()
+
+	if (rotatingFile.file != nil) && (rotatingFile.maxSize != 0) && (rotatingFile.size != 0) && ((rotatingFile.size + uint64(len(p))) > rotatingFile.maxSize) {
+		err = rotatingFile.rotate()
+		if err != nil {
+			// Fall back to stderr rather than lose the log line
+			fmt.Fprintf(os.Stderr, "unable to rotate log file (\"%s\"): %v\n", rotatingFile.path, err)
+		}
+	}
+
+	if rotatingFile.file == nil {
+		n, err = os.Stderr.Write(p)
+	} else {
+		n, err = rotatingFile.file.Write(p)
+		rotatingFile.size += uint64(n)
+	}
+
+	rotatingFile.Unlock()
+
+	return
+}
+
+func (rotatingFile *rotatingFileStruct) Close() (err error) {
+	rotatingFile.Lock()
+
+	if rotatingFile.file != nil {
+		err = rotatingFile.file.Close()
+		rotatingFile.file = nil
+	}
+
+	rotatingFile.Unlock()
+
+	return
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLine(t *testing.T) {
+	var (
+		component string
+		level     slog.Level
+		msg       string
+	)
+
+	for _, testCase := range []struct {
+		line      string
+		level     slog.Level
+		component string
+		msg       string
+	}{
+		{"[INFO] mounted", slog.LevelInfo, "", "mounted"},
+		{"[WARN] parsing config-file failed", slog.LevelWarn, "", "parsing config-file failed"},
+		{"[FATAL] boom", logLevelFatal, "", "boom"},
+		{"[TRACE] DoReadDirPlus", logLevelTrace, "", "DoReadDirPlus"},
+		{"[ERROR] [PEBBLE] compaction stalled", slog.LevelError, "PEBBLE", "compaction stalled"},
+		{"[FISSION] [WARN] read loop", slog.LevelWarn, "FISSION", "read loop"},
+		{"[HTTP-SERVER] http: TLS handshake error", slog.LevelInfo, "HTTP-SERVER", "http: TLS handshake error"},
+		{"untagged", slog.LevelInfo, "", "untagged"},
+		{"[WARN] [dir1/fileA] not a tag", slog.LevelWarn, "", "[dir1/fileA] not a tag"},
+	} {
+		level, component, msg = parseLogLine(testCase.line)
+		if (level != testCase.level) || (component != testCase.component) || (msg != testCase.msg) {
+			t.Errorf("parseLogLine(%q) returned (%v, %q, %q); expected (%v, %q, %q)", testCase.line, level, component, msg, testCase.level, testCase.component, testCase.msg)
+		}
+	}
+}
+
+func TestLoggingJSONWithBackendLevels(t *testing.T) {
+	var (
+		err         error
+		line        string
+		lines       []string
+		logContent  []byte
+		logFilePath = filepath.Join(t.TempDir(), "msfs.log")
+		record      map[string]interface{}
+	)
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".yaml"]))
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+msfs_version: 1
+log_format: json
+log_level: warn
+log_file: "`+logFilePath+`"
+backends: [
+  {
+    dir_name: quiet,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+  },
+  {
+    dir_name: verbose,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+    log_level: debug,
+  },
+  {
+    dir_name: legacy,
+    bucket_container_name: ignored,
+    backend_type: RAM,
+    trace_level: 1,
+  },
+]
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = checkConfigFile(); err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	if globals.backendsToMount["legacy"].logLevel.Level() != slog.LevelWarn {
+		t.Fatalf("trace_level: 1 should have mapped to log_level: warn, got %v", globals.backendsToMount["legacy"].logLevel.Level())
+	}
+
+	if err = initLogging(globals.config); err != nil {
+		t.Fatalf("initLogging() failed: %v", err)
+	}
+
+	globals.logger.Printf("[INFO] filtered by log_level")
+	globals.logger.Printf("[WARN] [PEBBLE] kept")
+
+	globals.backendsToMount["quiet"].logf(slog.LevelInfo, "quiet success")
+	globals.backendsToMount["quiet"].logf(slog.LevelWarn, "quiet failure")
+	globals.backendsToMount["verbose"].logf(slog.LevelDebug, "verbose details")
+	globals.backendsToMount["legacy"].logf(slog.LevelWarn, "legacy failure")
+
+	closeLogging()
+
+	logContent, err = os.ReadFile(logFilePath)
+	if err != nil {
+		t.Fatalf("os.ReadFile(logFilePath) failed: %v", err)
+	}
+
+	for _, line = range strings.Split(strings.TrimSpace(string(logContent)), "\n") {
+		record = make(map[string]interface{})
+		if err = json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("json.Unmarshal(%q) failed: %v", line, err)
+		}
+		lines = append(lines, record["level"].(string)+" "+record["msg"].(string))
+		switch record["msg"] {
+		case "kept":
+			if record["component"] != "PEBBLE" {
+				t.Fatalf("expected component \"PEBBLE\", got %v", record["component"])
+			}
+		case "verbose details":
+			if record["backend"] != "verbose" {
+				t.Fatalf("expected backend \"verbose\", got %v", record["backend"])
+			}
+		}
+	}
+
+	if strings.Join(lines, "|") != "WARN kept|DEBUG verbose details|WARN legacy failure" {
+		t.Fatalf("unexpected log content: %v", lines)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	var (
+		content      []byte
+		err          error
+		logFilePath  = filepath.Join(t.TempDir(), "msfs.log")
+		rotatingFile *rotatingFileStruct
+	)
+
+	rotatingFile, err = openRotatingFile(logFilePath, 10, 2)
+	if err != nil {
+		t.Fatalf("openRotatingFile() failed: %v", err)
+	}
+
+	for _, line := range []string{"line1...\n", "line2...\n", "line3...\n", "line4...\n"} {
+		if _, err = rotatingFile.Write([]byte(line)); err != nil {
+			t.Fatalf("rotatingFile.Write() failed: %v", err)
+		}
+	}
+
+	if err = rotatingFile.Close(); err != nil {
+		t.Fatalf("rotatingFile.Close() failed: %v", err)
+	}
+
+	for suffix, expected := range map[string]string{"": "line4...\n", ".1": "line3...\n", ".2": "line2...\n"} {
+		content, err = os.ReadFile(logFilePath + suffix)
+		if err != nil {
+			t.Fatalf("os.ReadFile(logFilePath+\"%s\") failed: %v", suffix, err)
+		}
+		if string(content) != expected {
+			t.Fatalf("logFilePath+\"%s\" contains %q; expected %q", suffix, content, expected)
+		}
+	}
+
+	if _, err = os.Stat(logFilePath + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("logFilePath+\".3\" should not exist (log_file_max_backups == 2)")
+	}
+}
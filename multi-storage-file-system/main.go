@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"flag"
 	"fmt"
 	"os"
 	"os/signal"
@@ -12,6 +11,7 @@ import (
 	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry"
 	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry/attributes"
 	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry/auth"
+	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry/metrics/readers"
 )
 
 // `main` is the entrypoint for the FUSE file system daemon. It parses the
@@ -23,26 +23,55 @@ import (
 // beneath the root of the FUSE file system. The daemon then enters a loop
 // until receiving a SIGINT or SIGTERM. Either periodically or in response
 // to a SIGHUP, the configuration file is re-read and the list of backends
-// is adjusted based on any changes detected.
+// is adjusted based on any changes detected. If --daemon is specified, the
+// daemon runs in the background once the FUSE file system is mounted (the
+// foreground process exiting with a non-zero status if the mount fails).
 func main() {
 	var (
-		displayHelp            bool
-		displayHelpMatchSet    map[string]struct{}
-		err                    error
-		errLastCheckConfigFile error
-		osArgs                 []string // Copy of os.Args so that initGlobals() can be passed a modified set of arguments in testing/benchmarking
-		signalChan             chan os.Signal
-		signalReceived         os.Signal
-		ticker                 *time.Ticker
+		daemonArgs          []string // Copy of osArgs prior to parseDaemonArgs() to be supplied to the background process started by daemonize()
+		displayHelp         bool
+		displayHelpMatchSet map[string]struct{}
+		err                 error
+		fake                bool
+		osArgs              []string // Copy of os.Args so that initGlobals() can be passed a modified set of arguments in testing/benchmarking
+		signalChan          chan os.Signal
+		signalReceived      os.Signal
+		subcommand          subcommandStruct
+		ticker              *time.Ticker
 	)
 
 	osArgs = make([]string, len(os.Args))
 	_ = copy(osArgs, os.Args)
 
-	// Handle "generate-manifest" subcommand before normal CLI parsing
-	if len(osArgs) >= 2 && osArgs[1] == "generate-manifest" {
-		runGenerateManifest(osArgs)
-		return
+	// Handle subcommands (other than "mount") before normal CLI parsing
+
+	if len(osArgs) >= 2 {
+		for _, subcommand = range subcommands {
+			if (osArgs[1] == subcommand.name) && (subcommand.run != nil) {
+				subcommand.run(osArgs)
+				return
+			}
+		}
+	}
+
+	// Handle invocation as a mount(8) helper (e.g. "mount.mscp") or "mount" subcommand by translating to --daemon
+	if isMountHelperInvocation(osArgs) {
+		osArgs, fake, err = parseMountHelperArgs(osArgs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		if fake {
+			os.Exit(0)
+		}
+	}
+
+	daemonArgs = osArgs
+
+	osArgs, err = parseDaemonArgs(osArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
 	}
 
 	displayHelpMatchSet = make(map[string]struct{})
@@ -65,8 +94,10 @@ func main() {
 	}
 
 	if displayHelp {
-		fmt.Printf("usage: %s [{-?|-h|help|-help|--help|-v|-version|--version} | <config-file>]\n", osArgs[0])
-		fmt.Printf("       %s generate-manifest --backend <name> [--output <path>] [--workers N] [--temp-dir <dir>] [<config-file>]\n", osArgs[0])
+		fmt.Printf("usage: %s [{-?|-h|help|-help|--help|-v|-version|--version} | [--daemon] [--pidfile <path>] [--log-destination {stdout|syslog|journald}] <config-file>]\n", osArgs[0])
+		for _, subcommand = range subcommands {
+			fmt.Printf("       %s %s %s\n", osArgs[0], subcommand.name, subcommand.usage)
+		}
 		fmt.Printf("  where a <config-file>, ending in suffix .yaml, .yml, or .json, is to be found while searching:\n")
 		fmt.Printf("    ${MSC_CONFIG}\n")
 		fmt.Printf("    ${XDG_CONFIG_HOME}/msc/config.{yaml|yml|json}\n")
@@ -79,6 +110,16 @@ func main() {
 		os.Exit(0)
 	}
 
+	err = openLogDestination()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to open --log-destination \"%s\": %v\n", globals.daemon.logDestination, err)
+		os.Exit(1)
+	}
+
+	if globals.daemon.enabled {
+		daemonize(daemonArgs)
+	}
+
 	initGlobals(osArgs)
 
 	err = checkConfigFile()
@@ -90,8 +131,16 @@ func main() {
 		globals.logger.Fatalf("[FATAL] parsing config-file (\"%s\") failed: %s", globals.configFilePath, redactSecrets(nil, err.Error()))
 	}
 
+	err = initLogging(globals.config)
+	if err != nil {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] unable to open log_file (\"%s\"): %v", globals.config.logFile, err)
+	}
+
 	initObservability()
 
+	startEventSink()
+
 	initFS()
 
 	processToMountList()
@@ -102,12 +151,27 @@ func main() {
 		globals.logger.Fatalf("[FATAL] unable to perform FUSE mount [Err: %v]", err)
 	}
 
+	updateMountReadOnly()
+
+	refreshMetricsAttributes()
+
 	startHTTPHandler()
+	startAdminHandler()
+
+	daemonNotify()
+
+	startReadinessProbe()
+
+	startBackendReprober()
+
+	startMemoryPressureWatcher()
+
+	startMultipartUploadJanitor()
 
 	for _, backend := range globals.config.backends {
 		if backend.readOnly && backend.manifestPath != "" {
 			manifestBackend := backend
-			go func() {
+			_ = startWorker("manifestBootstrap", func(_ context.Context) {
 				manifestStartTime := time.Now()
 
 				_, statErr := os.Stat(manifestBackend.manifestPath)
@@ -155,7 +219,7 @@ func main() {
 
 				globals.logger.Printf("[INFO] manifest-bootstrap: total bootstrap time for backend %q: %v",
 					manifestBackend.dirName, time.Since(manifestStartTime).Round(time.Millisecond))
-			}()
+			})
 		}
 	}
 
@@ -169,15 +233,15 @@ func main() {
 		ticker = time.NewTicker(globals.config.autoSIGHUPInterval)
 	}
 
-	errLastCheckConfigFile = nil
-
 	for {
 		select {
 		case signalReceived = <-signalChan:
 			if signalReceived != syscall.SIGHUP {
 				// We received either syscall.SIGINT or syscall.SIGTERM...so terminate normally
 
-				err = performFissionUnmount()
+				clearReady()
+
+				err = gracefulUnmount()
 				if err != nil {
 					dumpStack()
 					globals.logger.Fatalf("[FATAL] unexpected error during FUSE unmount: %v", err)
@@ -185,6 +249,10 @@ func main() {
 
 				drainFS()
 
+				stopEventSink()
+
+				_ = stopWorkers(workerStopTimeout)
+
 				// Shutdown observability (flush pending metrics)
 				if globals.meterProvider != nil {
 					shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -198,42 +266,33 @@ func main() {
 					cancel()
 				}
 
-				os.Exit(0)
-			}
-
-			// We received a syscall.SIGHUP... so re-parse (current) content of globals.condfigFilePath and resume
+				// Shutdown tracing (flush pending spans)
+				if globals.tracerProvider != nil {
+					shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					if tp, ok := globals.tracerProvider.(interface{ Shutdown(context.Context) error }); ok {
+						if err := tp.Shutdown(shutdownCtx); err != nil {
+							globals.logger.Printf("[WARN] error shutting down tracer provider: %v", err)
+						} else {
+							globals.logger.Printf("[INFO] tracer provider shut down successfully")
+						}
+					}
+					cancel()
+				}
 
-			err = checkConfigFile()
-			if err == nil {
-				globals.logger.Printf("[INFO] parsing config-file (\"%s\") succeeded", globals.configFilePath)
+				removePIDFile()
 
-				processToUnmountList()
+				closeLogging()
 
-				processToMountList()
-			} else {
-				// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
-				globals.logger.Printf("[WARN] parsing config-file (\"%s\") failed: %s", globals.configFilePath, redactSecrets(nil, err.Error()))
+				os.Exit(0)
 			}
 
-			errLastCheckConfigFile = err
-		case <-ticker.C:
-			// Act like we received a syscall.SIGHUP... so re-parse (current) content of globals.condfigFilePath and resume
-
-			err = checkConfigFile()
-			if err == nil {
-				if errLastCheckConfigFile != nil {
-					globals.logger.Printf("[INFO] parsing config-file (\"%s\") succeeded", globals.configFilePath)
-				}
+			// We received a syscall.SIGHUP... so re-parse (current) content of globals.configFilePath and resume
 
-				processToUnmountList()
-
-				processToMountList()
-			} else if (errLastCheckConfigFile == nil) || (errLastCheckConfigFile.Error() != err.Error()) {
-				// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
-				globals.logger.Printf("[WARN] parsing config-file (\"%s\") failed: %s", globals.configFilePath, redactSecrets(nil, err.Error()))
-			}
+			_ = reloadConfigFile(reloadTriggerSIGHUP)
+		case <-ticker.C:
+			// Act like we received a syscall.SIGHUP... so re-parse (current) content of globals.configFilePath and resume
 
-			errLastCheckConfigFile = err
+			_ = reloadConfigFile(reloadTriggerAuto)
 		case err = <-globals.errChan:
 			// We received an Unexpected exit of /dev/fuse read loop... to terminate abnormally
 
@@ -253,6 +312,8 @@ func initObservability() {
 		return
 	}
 
+	initTracing()
+
 	// Check if metrics exporter is configured (matches Python schema requirement)
 	if globals.config.observability.metricsExporter == nil {
 		globals.logger.Printf("[INFO] metrics exporter not configured, skipping metrics initialization")
@@ -268,12 +329,16 @@ func initObservability() {
 	collectTimeoutMs := uint64(10000) // 10 seconds default
 	exportIntervalMs := uint64(60000) // 60 seconds default
 	exportTimeoutMs := uint64(30000)  // 30 seconds default
+	maxBufferedSnapshots := uint64(readers.DefaultMaxBufferedSnapshots)
+	temporality := readers.TemporalityCumulative
 
 	if globals.config.observability.metricsReaderOptions != nil {
 		collectIntervalMs = globals.config.observability.metricsReaderOptions.CollectIntervalMillis
 		collectTimeoutMs = globals.config.observability.metricsReaderOptions.CollectTimeoutMillis
 		exportIntervalMs = globals.config.observability.metricsReaderOptions.ExportIntervalMillis
 		exportTimeoutMs = globals.config.observability.metricsReaderOptions.ExportTimeoutMillis
+		maxBufferedSnapshots = globals.config.observability.metricsReaderOptions.MaxBufferedSnapshots
+		temporality = globals.config.observability.metricsReaderOptions.Temporality
 	}
 
 	// Process attribute providers (matches Python: instantiate providers from config)
@@ -286,8 +351,18 @@ func initObservability() {
 	metricsConfig.CollectTimeoutMillis = collectTimeoutMs
 	metricsConfig.ExportIntervalMillis = exportIntervalMs
 	metricsConfig.ExportTimeoutMillis = exportTimeoutMs
+	metricsConfig.MaxBufferedSnapshots = maxBufferedSnapshots
 	metricsConfig.ServiceName = "msc-posix"
 	metricsConfig.AttributeProviders = attributeProviders
+	metricsConfig.Temporality = temporality
+	for _, view := range globals.config.observability.metricsViews {
+		metricsConfig.Views = append(metricsConfig.Views, telemetry.MetricsView{
+			InstrumentName: view.Instrument,
+			Aggregation:    view.Aggregation,
+			Boundaries:     view.Boundaries,
+		})
+	}
+	globals.attributeProviders = attributeProviders
 
 	// Handle different exporter types
 	switch exporterType {
@@ -383,8 +458,8 @@ func initObservability() {
 		return
 	}
 
-	globals.logger.Printf("[INFO] metrics initialized with diperiodic pattern (collect=%dms, export=%dms), sending to %s",
-		collectIntervalMs, exportIntervalMs, metricsConfig.OTLPEndpoint)
+	globals.logger.Printf("[INFO] metrics initialized with diperiodic pattern (collect=%dms, export=%dms, temporality=%s, views=%d), sending to %s",
+		collectIntervalMs, exportIntervalMs, temporality, len(metricsConfig.Views), metricsConfig.OTLPEndpoint)
 
 	// Create MSCP metrics instruments (matches MSC Python: gauges use LastValue, counters use Sum)
 	// Pass metricAttrs so they're added to every metric recording (matching Python behavior)
@@ -399,64 +474,69 @@ func initObservability() {
 	globals.logger.Printf("[INFO] metrics instruments created successfully")
 }
 
-// `runGenerateManifest` handles the "generate-manifest" subcommand.
-// It parses the config, sets up the specified backend, runs the BFS manifest
-// generation pipeline, and exits.
-func runGenerateManifest(osArgs []string) {
-	fs := flag.NewFlagSet("generate-manifest", flag.ExitOnError)
-	backendName := fs.String("backend", "", "backend name from config (required)")
-	outputPath := fs.String("output", ".msfs_manifest", "output manifest directory path (per-directory format)")
-	workers := fs.Int("workers", defaultManifestGenWorkers, "number of parallel listing workers")
-	tempDir := fs.String("temp-dir", "", "directory for temporary shard files (default: system temp)")
-
-	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, "usage: %s generate-manifest --backend <name> [--output <path>] [--workers N] [--temp-dir <dir>] [<config-file>]\n", osArgs[0])
-		fs.PrintDefaults()
+// refreshMetricsAttributes re-collects the metrics attribute providers once the FUSE mount is in
+// place such that attributes only then known (i.e. the "mount_instance" provider's FUSE connection ID)
+// are added to subsequent metric recordings. Note that the Resource attributes, collected at startup,
+// are not updated.
+func refreshMetricsAttributes() {
+	if globals.metrics == nil {
+		return
 	}
 
-	if err := fs.Parse(osArgs[2:]); err != nil {
-		os.Exit(1)
+	metrics, ok := globals.metrics.(telemetry.MSCPMetricsDiperiodic)
+	if !ok {
+		return
 	}
 
-	if *backendName == "" {
-		fmt.Fprintf(os.Stderr, "error: --backend is required\n")
-		fs.Usage()
-		os.Exit(1)
+	attributeProviders, ok := globals.attributeProviders.([]attributes.AttributesProvider)
+	if !ok {
+		return
 	}
 
-	// Remaining non-flag args are treated as [config-file]
-	configArgs := []string{osArgs[0]}
-	if fs.NArg() > 0 {
-		configArgs = append(configArgs, fs.Arg(0))
+	metrics.SetBaseAttributes(attributes.CollectAttributes(attributeProviders))
+}
+
+// initTracing initializes tracing via OTLP if opentelemetry.traces.exporter is configured.
+// Each FUSE operation is then traced as a span with a child span per backend call.
+func initTracing() {
+	if globals.config.observability.tracesExporter == nil {
+		globals.logger.Printf("[INFO] traces exporter not configured, skipping tracing initialization")
+		return
 	}
 
-	initGlobals(configArgs)
+	exporterType := globals.config.observability.tracesExporter.Type
+	exporterOptions := globals.config.observability.tracesExporter.Options
 
-	err := checkConfigFile()
-	if err != nil {
-		dumpStack()
-		// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
-		globals.logger.Fatalf("[FATAL] parsing config-file (\"%s\") failed: %s", globals.configFilePath, redactSecrets(nil, err.Error()))
+	if exporterType != "otlp" {
+		globals.logger.Printf("[WARN] unsupported traces exporter type: %s (supported: 'otlp')", exporterType)
+		return
 	}
 
-	cfg := &manifestGenConfig{
-		workers:     *workers,
-		outputPath:  *outputPath,
-		tempDir:     *tempDir,
-		backendName: *backendName,
+	endpoint, ok := exporterOptions["endpoint"].(string)
+	if !ok {
+		globals.logger.Printf("[WARN] traces exporter endpoint not configured, skipping tracing initialization")
+		return
 	}
 
-	globals.logger.Printf("[INFO] manifest-gen: starting (backend=%q, workers=%d, output=%q)",
-		cfg.backendName, cfg.workers, cfg.outputPath)
+	insecure := true // default to insecure for dev (as for metrics)
+	if insecureVal, ok := exporterOptions["insecure"].(bool); ok {
+		insecure = insecureVal
+	}
 
-	err = generateManifest(cfg)
+	tracerProvider, err := telemetry.SetupTracing(&telemetry.TracingConfig{
+		OTLPEndpoint:       endpoint,
+		Insecure:           insecure,
+		SampleRatio:        globals.config.observability.tracesSampleRatio,
+		ServiceName:        "msc-posix",
+		AttributeProviders: processAttributeProviders(globals.config.observability.metricsAttributes),
+	})
 	if err != nil {
-		// CodeQL [SM01413]: clear-text-logging false positive — manifest-gen errors
-		// originate from backend listings and writeFile, never embed credentials.
-		globals.logger.Fatalf("[FATAL] manifest-gen failed: %s", redactSecrets(cfg.backend, err.Error()))
+		globals.logger.Printf("[WARN] failed to initialize tracing: %v", err)
+		return
 	}
 
-	os.Exit(0)
+	globals.tracerProvider = tracerProvider // Store for shutdown later
+	globals.logger.Printf("[INFO] tracing initialized (sample_ratio=%v), sending to %s", globals.config.observability.tracesSampleRatio, endpoint)
 }
 
 // processAttributeProviders instantiates attribute providers from configuration.
@@ -487,6 +567,16 @@ func processAttributeProviders(configs []attributeProviderStruct) []attributes.A
 			}
 			return attributes.NewMSCConfigAttributesProvider(opts)
 		},
+		"mount_instance": func(opts map[string]interface{}) attributes.AttributesProvider {
+			// Distinguishes multiple MSCP daemons on one host (MSCP's equivalent of Python's "thread" provider)
+			return attributes.NewMountInstanceAttributesProvider(opts, &attributes.MountInstance{
+				ID:        globals.mountInstanceID,
+				StartTime: globals.mountInstanceStartTime,
+				FUSEConnectionID: func() (uint64, bool) {
+					return fuseConnectionID(globals.config.mountPoint)
+				},
+			})
+		},
 	}
 
 	providers = make([]attributes.AttributesProvider, 0, len(configs))
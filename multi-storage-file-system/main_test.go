@@ -9,6 +9,7 @@ import (
 type testGlobalsStruct struct {
 	testMountPoint        string
 	testConfigFilePathMap map[string]string
+	testS3Server          *testS3ServerStruct
 }
 
 var testGlobals testGlobalsStruct
@@ -47,8 +48,12 @@ func TestMain(m *testing.M) {
 		}
 	}
 
+	testGlobals.testS3Server = newTestS3Server()
+
 	runExitCode = m.Run()
 
+	testGlobals.testS3Server.close()
+
 	for _, testConfigFilePath = range testGlobals.testConfigFilePathMap {
 		err = os.Remove(testConfigFilePath)
 		if err != nil {
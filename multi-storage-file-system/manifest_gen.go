@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -226,7 +227,7 @@ func (gen *manifestGenerator) listDirectory(dirPath string, depth int) {
 		maxItems: 1000,
 		dirPath:  dirPath,
 	}
-	firstPage, err := gen.backend.context.listDirectory(input)
+	firstPage, err := gen.backend.context.listDirectory(context.Background(), input)
 	if err != nil {
 		globals.logger.Printf("[WARN] manifest-gen: listDirectory failed for %q: %v", dirPath, err)
 		gen.errors.Add(1)
@@ -269,7 +270,7 @@ func (gen *manifestGenerator) confirmFlatDirectory(dirPath string, firstPage *li
 	}
 
 	for range confirmPages - 1 {
-		page, err := gen.backend.context.listDirectory(&listDirectoryInputStruct{
+		page, err := gen.backend.context.listDirectory(context.Background(), &listDirectoryInputStruct{
 			continuationToken: token,
 			maxItems:          1000,
 			dirPath:           dirPath,
@@ -332,7 +333,7 @@ func (gen *manifestGenerator) listDirectoryNormalWithPages(dirPath string, depth
 
 	continuationToken := lastPage.nextContinuationToken
 	for {
-		page, pageErr := gen.backend.context.listDirectory(&listDirectoryInputStruct{
+		page, pageErr := gen.backend.context.listDirectory(context.Background(), &listDirectoryInputStruct{
 			continuationToken: continuationToken,
 			maxItems:          1000,
 			dirPath:           dirPath,
@@ -394,7 +395,7 @@ func (gen *manifestGenerator) listDirectoryNormal(dirPath string, depth int, fir
 			dirPath:           dirPath,
 		}
 
-		page, pageErr := gen.backend.context.listDirectory(input)
+		page, pageErr := gen.backend.context.listDirectory(context.Background(), input)
 		if pageErr != nil {
 			globals.logger.Printf("[WARN] manifest-gen: listDirectory failed for %q: %v", dirPath, pageErr)
 			gen.errors.Add(1)
@@ -461,7 +462,7 @@ func (gen *manifestGenerator) resumeBFSAfterFlat(dirPath, flatPrefix string, dep
 			input.startAfter = startAfterKey
 		}
 
-		page, err := gen.backend.context.listDirectory(input)
+		page, err := gen.backend.context.listDirectory(context.Background(), input)
 		if err != nil {
 			globals.logger.Printf("[WARN] manifest-gen: resumeBFSAfterFlat failed for %q: %v", dirPath, err)
 			gen.errors.Add(1)
@@ -580,7 +581,7 @@ func (gen *manifestGenerator) discoverPrefixes(dirPath string) []string {
 		go func(c rune) {
 			defer probeWG.Done()
 			subPrefix := dirPath + string(c)
-			output, err := listPrefixWrapper(gen.backend.context, &listPrefixInputStruct{
+			output, err := listPrefixWrapper(context.Background(), gen.backend.context, &listPrefixInputStruct{
 				prefix:   subPrefix,
 				maxItems: 1,
 			})
@@ -703,7 +704,7 @@ func (gen *manifestGenerator) listFlatWithRangeSplitting(dirPath, flatPrefix str
 // firstKnownKey is the first key from the collected pages (our lower bound).
 func (gen *manifestGenerator) estimateLastKey(flatPrefix, firstKnownKey string) (string, error) {
 	hasKeysAfter := func(probe string) bool {
-		output, err := listPrefixWrapper(gen.backend.context, &listPrefixInputStruct{
+		output, err := listPrefixWrapper(context.Background(), gen.backend.context, &listPrefixInputStruct{
 			prefix:     flatPrefix,
 			startAfter: probe,
 			maxItems:   1,
@@ -737,7 +738,7 @@ func (gen *manifestGenerator) estimateLastKey(flatPrefix, firstKnownKey string)
 	// The binary search gets us close; the sweep handles any residual gap.
 	sweepKey := low
 	for range 50 {
-		output, err := listPrefixWrapper(gen.backend.context, &listPrefixInputStruct{
+		output, err := listPrefixWrapper(context.Background(), gen.backend.context, &listPrefixInputStruct{
 			prefix:     flatPrefix,
 			startAfter: sweepKey,
 			maxItems:   1000,
@@ -824,7 +825,7 @@ func (gen *manifestGenerator) listPrefixRange(queryPrefix, startAfter, stopAt st
 			maxItems:          1000,
 		}
 
-		output, listErr := listPrefixWrapper(gen.backend.context, input)
+		output, listErr := listPrefixWrapper(context.Background(), gen.backend.context, input)
 		if listErr != nil {
 			globals.logger.Printf("[WARN] manifest-gen: listPrefix failed for %q: %v", queryPrefix, listErr)
 			gen.errors.Add(1)
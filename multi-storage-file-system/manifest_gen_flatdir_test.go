@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -34,13 +35,17 @@ type mockBackendContext struct {
 	objects []mockObject
 }
 
+func (m *mockBackendContext) abortMultipartUploads(_ context.Context, _ *abortMultipartUploadsInputStruct) (*abortMultipartUploadsOutputStruct, error) {
+	return nil, errAbortMultipartUploadsNotSupported
+}
+
 func (m *mockBackendContext) backendCommon() *backendStruct { return m.backend }
 
-func (m *mockBackendContext) deleteFile(_ *deleteFileInputStruct) (*deleteFileOutputStruct, error) {
+func (m *mockBackendContext) deleteFile(_ context.Context, _ *deleteFileInputStruct) (*deleteFileOutputStruct, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockBackendContext) listDirectory(input *listDirectoryInputStruct) (*listDirectoryOutputStruct, error) {
+func (m *mockBackendContext) listDirectory(_ context.Context, input *listDirectoryInputStruct) (*listDirectoryOutputStruct, error) {
 	prefix := m.backend.prefix + input.dirPath
 	delimiter := "/"
 
@@ -118,7 +123,7 @@ func (m *mockBackendContext) listDirectory(input *listDirectoryInputStruct) (*li
 // stopAt bound is applied by listPrefixWrapper, not here. Object paths are returned
 // relative to backend.prefix (so they include the sub-prefix), matching the real
 // backends.
-func (m *mockBackendContext) listObjects(input *listObjectsInputStruct) (*listObjectsOutputStruct, error) {
+func (m *mockBackendContext) listObjects(_ context.Context, input *listObjectsInputStruct) (*listObjectsOutputStruct, error) {
 	fullPrefix := m.backend.prefix + input.prefix
 	fullStartAfter := ""
 	if input.startAfter != "" {
@@ -177,19 +182,35 @@ func (m *mockBackendContext) listObjects(input *listObjectsInputStruct) (*listOb
 	return output, nil
 }
 
+func (m *mockBackendContext) copyFile(_ context.Context, _ *copyFileInputStruct) (*copyFileOutputStruct, error) {
+	return nil, errCopyFileNotSupported
+}
+
+func (m *mockBackendContext) presignFile(_ context.Context, _ *presignFileInputStruct) (*presignFileOutputStruct, error) {
+	return nil, errPresignFileNotSupported
+}
+
+func (m *mockBackendContext) putFile(_ context.Context, _ *putFileInputStruct) (*putFileOutputStruct, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockBackendContext) redactSecrets(s string) string {
 	return s
 }
 
-func (m *mockBackendContext) readFile(_ *readFileInputStruct) (*readFileOutputStruct, error) {
+func (m *mockBackendContext) readFile(_ context.Context, _ *readFileInputStruct) (*readFileOutputStruct, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockBackendContext) statDirectory(_ *statDirectoryInputStruct) (*statDirectoryOutputStruct, error) {
+func (m *mockBackendContext) restoreFile(_ context.Context, _ *restoreFileInputStruct) (*restoreFileOutputStruct, error) {
+	return nil, errRestoreFileNotSupported
+}
+
+func (m *mockBackendContext) statDirectory(_ context.Context, _ *statDirectoryInputStruct) (*statDirectoryOutputStruct, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockBackendContext) statFile(_ *statFileInputStruct) (*statFileOutputStruct, error) {
+func (m *mockBackendContext) statFile(_ context.Context, _ *statFileInputStruct) (*statFileOutputStruct, error) {
 	return nil, errors.New("not implemented")
 }
 
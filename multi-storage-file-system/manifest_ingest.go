@@ -224,7 +224,6 @@ func ingestWriteBatch(batch *dirBatch, backend *backendStruct, dirCache map[stri
 		basename         string
 		mTime            time.Time
 		err              error
-		inodeNumber      uint64
 		fileInode        *inodeStruct
 		now              time.Time
 		total            int64
@@ -243,7 +242,7 @@ func ingestWriteBatch(batch *dirBatch, backend *backendStruct, dirCache map[stri
 	tWaitLock := time.Now()
 
 	// Step 1a: Resolve dir chain under globals.Lock (fast, dirs are cached in localDirCache)
-	globalsLock("manifest_ingest.go:246:2:ingestWriteBatch")
+	globalsLock("manifest_ingest.go:245:2:ingestWriteBatch")
 
 	tGotLock := time.Now()
 
@@ -270,10 +269,8 @@ func ingestWriteBatch(batch *dirBatch, backend *backendStruct, dirCache map[stri
 			mTime = time.Now()
 		}
 
-		inodeNumber = fetchNonce()
-
 		fileInode = &inodeStruct{
-			inodeNumber:       inodeNumber,
+			// inodeNumber: filled in by putWithStableInodeNumber() below
 			inodeType:         FileObject,
 			backendNonce:      backend.nonce,
 			parentInodeNumber: parentInode.inodeNumber,
@@ -285,12 +282,13 @@ func ingestWriteBatch(batch *dirBatch, backend *backendStruct, dirCache map[stri
 			eTag:              entry.ETag,
 			mode:              uint32(syscall.S_IFREG | backend.filePerm),
 			mTime:             mTime,
+			times:             newInodeTimes(mTime, time.Now()),
 			xTime:             time.Time{},
 			cacheMap:          make(map[uint64]uint64),
 			fhSet:             make(map[uint64]struct{}),
 		}
 
-		globals.inodeMap.put(fileInode)
+		fileInode.putWithStableInodeNumber(backend.dirName)
 
 		pending = append(pending, pendingEntry{
 			basename: basename,
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	memoryPressureHysteresis  = uint64(10)            // Percentage points below memory_pressure_threshold at which memory pressure is deemed relieved
+	memoryPressureShrinkRatio = uint64(10)            // Each memory_pressure_interval under pressure frees up to 1/memoryPressureShrinkRatio of the data cache lines
+	cgroupUnlimited           = uint64(1) << 60       // cgroup v1 reports "no limit" as a huge (page rounded) value rather than "max"
+	cgroupFSRoot              = "/sys/fs/cgroup"      // Mountpoint of the cgroup file system
+	procSelfStatm             = "/proc/self/statm"    // Reports (among other things) the resident set size of this process (in pages)
+	cgroupV1MemorySubdir      = "memory"              // Subdirectory of cgroupFSRoot holding the cgroup v1 memory controller hierarchy
+	cgroupInactiveFileV2      = "inactive_file"       // memory.stat key (cgroup v2) of reclaimable page cache excluded from usage
+	cgroupInactiveFileV1      = "total_inactive_file" // memory.stat key (cgroup v1) of reclaimable page cache excluded from usage
+)
+
+// `startMemoryPressureWatcher` launches a worker calling checkMemoryPressure() every
+// globals.config.memoryPressureInterval (if != 0).
+func startMemoryPressureWatcher() {
+	var (
+		memoryPressureInterval = globals.config.memoryPressureInterval
+	)
+
+	if memoryPressureInterval == 0 {
+		return
+	}
+
+	_ = startWorker("memoryPressureWatcher", func(ctx context.Context) {
+		var (
+			ticker = time.NewTicker(memoryPressureInterval)
+		)
+
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				checkMemoryPressure()
+			}
+		}
+	})
+}
+
+// `checkMemoryPressure` compares the memory usage of the process against its limit: either
+// memory_pressure_limit (compared to the resident set size) or, if that is 0, the limit of
+// the process's cgroup (compared to the cgroup's usage less its inactive page cache). Once
+// usage reaches memory_pressure_threshold percent of the limit, globals.memoryPressure is
+// set (pausing readahead) and Clean data cache lines are freed (see shrinkDataCache()) each
+// time usage is found above that threshold. Memory pressure is relieved once usage drops
+// memoryPressureHysteresis percentage points below the threshold.
+func checkMemoryPressure() {
+	var (
+		cacheLines uint64
+		err        error
+		freed      uint64
+		limit      uint64
+		threshold  uint64
+		usage      uint64
+	)
+
+	globalsLock("mempressure.go:71:2:checkMemoryPressure")
+	cacheLines = globals.config.cacheLines
+	limit = globals.config.memoryPressureLimit
+	threshold = globals.config.memoryPressureThreshold
+	globalsUnlock()
+
+	if limit == 0 {
+		limit, usage, err = cgroupMemoryUsage()
+	} else {
+		usage, err = processRSS()
+	}
+	if err != nil {
+		globals.logger.Printf("[WARN] unable to determine memory usage: %v", err)
+		return
+	}
+	if limit == 0 {
+		return // No cgroup limit applies
+	}
+
+	switch {
+	case (usage * 100) >= (limit * threshold):
+		if !globals.memoryPressure.Swap(true) {
+			globals.logger.Printf("[WARN] memory pressure detected (usage %v of limit %v) - pausing readahead and shrinking data cache", usage, limit)
+		}
+
+		freed = shrinkDataCache(max(1, cacheLines/memoryPressureShrinkRatio))
+
+		debug.FreeOSMemory()
+
+		globals.logger.Printf("[INFO] memory pressure freed %v Clean data cache lines", freed)
+	case (usage * 100) < (limit * (threshold - min(threshold, memoryPressureHysteresis))):
+		if globals.memoryPressure.Swap(false) {
+			globals.logger.Printf("[INFO] memory pressure relieved (usage %v of limit %v) - resuming readahead", usage, limit)
+		}
+	}
+}
+
+// `shrinkDataCache` frees up to count of the least recently used (unpinned) Clean data
+// cache lines, releasing the memory holding their content (see releaseDataCacheSlot()),
+// returning the number freed.
+func shrinkDataCache(count uint64) (freed uint64) {
+	var (
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+	)
+
+	globalsLock("mempressure.go:116:2:shrinkDataCache")
+
+	for freed < count {
+		dataCacheLineTracker, _ = globals.dataCacheLineCleanLRU.popHeadUnpinned()
+		if dataCacheLineTracker == nil {
+			break
+		}
+
+		dataCacheLineTracker.evict()
+		dataCacheLineTracker.free()
+
+		if globals.dataCacheLinesContent != nil {
+			releaseDataCacheSlot(dataCacheLineTracker.contentStart)
+		}
+
+		freed++
+	}
+
+	globalsUnlock()
+
+	return
+}
+
+// `processRSS` returns the resident set size of the process.
+func processRSS() (rss uint64, err error) {
+	var (
+		residentPages uint64
+		statmContent  []byte
+		statmFields   []string
+	)
+
+	statmContent, err = os.ReadFile(procSelfStatm)
+	if err != nil {
+		return
+	}
+
+	statmFields = strings.Fields(string(statmContent))
+	if len(statmFields) < 2 {
+		err = errors.New(procSelfStatm + " unexpectedly has fewer than 2 fields")
+		return
+	}
+
+	residentPages, err = strconv.ParseUint(statmFields[1], 10, 64)
+	if err != nil {
+		return
+	}
+
+	rss = residentPages * uint64(os.Getpagesize())
+
+	return
+}
+
+// `cgroupMemoryUsage` returns the memory limit of the process's cgroup (0 if there is none)
+// and its usage less the (reclaimable) inactive page cache charged to it (i.e. its working
+// set). The unified (cgroup v2) hierarchy is consulted if mounted, otherwise the cgroup v1
+// memory controller hierarchy.
+func cgroupMemoryUsage() (limit uint64, usage uint64, err error) {
+	var (
+		cgroupDir       string
+		inactiveFile    uint64
+		inactiveFileKey string
+		limitFile       string
+		usageFile       string
+	)
+
+	_, err = os.Stat(filepath.Join(cgroupFSRoot, "cgroup.controllers"))
+	if err == nil {
+		cgroupDir = filepath.Join(cgroupFSRoot, cgroupOfPID(uint32(os.Getpid())))
+		limitFile, usageFile, inactiveFileKey = "memory.max", "memory.current", cgroupInactiveFileV2
+	} else {
+		cgroupDir = filepath.Join(cgroupFSRoot, cgroupV1MemorySubdir)
+		limitFile, usageFile, inactiveFileKey = "memory.limit_in_bytes", "memory.usage_in_bytes", cgroupInactiveFileV1
+	}
+
+	limit, err = readCgroupUint64(filepath.Join(cgroupDir, limitFile))
+	if (err != nil) || (limit >= cgroupUnlimited) {
+		limit = 0 // Either no memory controller is available or it imposes no limit
+		err = nil
+		return
+	}
+
+	usage, err = readCgroupUint64(filepath.Join(cgroupDir, usageFile))
+	if err != nil {
+		return
+	}
+
+	inactiveFile, err = readCgroupStat(filepath.Join(cgroupDir, "memory.stat"), inactiveFileKey)
+	if err != nil {
+		return
+	}
+
+	usage -= min(usage, inactiveFile)
+
+	return
+}
+
+// `readCgroupUint64` returns the value of a single-valued cgroup file with "max" (i.e.
+// unlimited) returned as cgroupUnlimited.
+func readCgroupUint64(path string) (value uint64, err error) {
+	var (
+		content []byte
+		trimmed string
+	)
+
+	content, err = os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	trimmed = strings.TrimSpace(string(content))
+	if trimmed == "max" {
+		value = cgroupUnlimited
+		return
+	}
+
+	value, err = strconv.ParseUint(trimmed, 10, 64)
+
+	return
+}
+
+// `readCgroupStat` returns the value of key in a cgroup memory.stat file (0 if absent).
+func readCgroupStat(path string, key string) (value uint64, err error) {
+	var (
+		content    []byte
+		line       string
+		lineFields []string
+	)
+
+	content, err = os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	for _, line = range strings.Split(string(content), "\n") {
+		lineFields = strings.Fields(line)
+		if (len(lineFields) == 2) && (lineFields[0] == key) {
+			value, err = strconv.ParseUint(lineFields[1], 10, 64)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,21 @@
+//go:build linux
+
+package main
+
+import (
+	"syscall"
+)
+
+// `releaseCacheLineMemory` returns the pages holding the content of a (just freed) data
+// cache line to the kernel via madvise(MADV_DONTNEED). As the data cache content is an
+// mmap (see dataCacheUp()), freeing a line would otherwise leave its pages resident.
+func releaseCacheLineMemory(content []byte) {
+	var (
+		err error
+	)
+
+	err = syscall.Madvise(content, syscall.MADV_DONTNEED)
+	if err != nil {
+		globals.logger.Printf("[WARN] syscall.Madvise(,MADV_DONTNEED) of a data cache line failed: %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// `releaseCacheLineMemory` is a no-op on non-Linux platforms. A freed data cache line's
+// pages simply remain resident until reused. MSFS production targets are Linux, so this
+// path is for local dev/test builds (e.g. macOS) only.
+func releaseCacheLineMemory(_ []byte) {}
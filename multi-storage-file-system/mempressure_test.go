@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMemoryPressure verifies that checkMemoryPressure() sets globals.memoryPressure and
+// frees Clean data cache lines once usage reaches memory_pressure_threshold of the limit,
+// and clears it once usage is well below that threshold.
+func TestMemoryPressure(t *testing.T) {
+	var (
+		cacheLineNumber      uint64
+		cleanBefore          uint64
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		lines                []uint64
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	globalsLock("mempressure_test.go:23:2:TestMemoryPressure")
+	lines, _ = allocateDataCacheLines(globals.dataCacheLineFreeLRU.lruCount.Load(), "")
+	globalsLock("mempressure_test.go:25:2:TestMemoryPressure")
+	for _, cacheLineNumber = range lines {
+		dataCacheLineTracker = &globals.dataCacheLinesTracker[cacheLineNumber]
+		dataCacheLineTracker.inodeNumber = globals.config.backends["ram"].inode.inodeNumber
+		dataCacheLineTracker.lineNumber = cacheLineNumber
+		globals.dataCacheLineCleanLRU.pushTail(dataCacheLineTracker)
+	}
+	cleanBefore = globals.dataCacheLineCleanLRU.lruCount.Load()
+	globals.config.memoryPressureLimit = 1 // Any resident set size exceeds this
+	globalsUnlock()
+
+	if cleanBefore < 2 {
+		t.Fatalf("expected at least 2 Clean data cache lines but got %v", cleanBefore)
+	}
+
+	checkMemoryPressure()
+
+	if !globals.memoryPressure.Load() {
+		t.Fatalf("checkMemoryPressure() should have detected memory pressure")
+	}
+	if globals.dataCacheLineCleanLRU.lruCount.Load() >= cleanBefore {
+		t.Fatalf("checkMemoryPressure() should have freed Clean data cache lines (Clean: %v)", globals.dataCacheLineCleanLRU.lruCount.Load())
+	}
+	if !adminCache().MemoryPressure {
+		t.Fatalf("adminCache() should have reported memory pressure")
+	}
+
+	globalsLock("mempressure_test.go:52:2:TestMemoryPressure")
+	globals.config.memoryPressureLimit = uint64(1) << 62 // No resident set size approaches this
+	globalsUnlock()
+
+	checkMemoryPressure()
+
+	if globals.memoryPressure.Load() {
+		t.Fatalf("checkMemoryPressure() should have relieved memory pressure")
+	}
+}
+
+// TestReadCgroupFiles verifies the parsing of single-valued and memory.stat cgroup files.
+func TestReadCgroupFiles(t *testing.T) {
+	var (
+		dir   = t.TempDir()
+		err   error
+		value uint64
+	)
+
+	err = os.WriteFile(filepath.Join(dir, "memory.max"), []byte("max\n"), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile(memory.max) failed: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "memory.current"), []byte("12345\n"), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile(memory.current) failed: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "memory.stat"), []byte("anon 100\ninactive_file 678\nactive_file 9\n"), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile(memory.stat) failed: %v", err)
+	}
+
+	value, err = readCgroupUint64(filepath.Join(dir, "memory.max"))
+	if (err != nil) || (value != cgroupUnlimited) {
+		t.Fatalf("readCgroupUint64(memory.max) returned %v, %v (expected cgroupUnlimited, nil)", value, err)
+	}
+
+	value, err = readCgroupUint64(filepath.Join(dir, "memory.current"))
+	if (err != nil) || (value != 12345) {
+		t.Fatalf("readCgroupUint64(memory.current) returned %v, %v (expected 12345, nil)", value, err)
+	}
+
+	value, err = readCgroupStat(filepath.Join(dir, "memory.stat"), cgroupInactiveFileV2)
+	if (err != nil) || (value != 678) {
+		t.Fatalf("readCgroupStat(memory.stat, %q) returned %v, %v (expected 678, nil)", cgroupInactiveFileV2, value, err)
+	}
+
+	value, err = readCgroupStat(filepath.Join(dir, "memory.stat"), cgroupInactiveFileV1)
+	if (err != nil) || (value != 0) {
+		t.Fatalf("readCgroupStat(memory.stat, %q) returned %v, %v (expected 0, nil)", cgroupInactiveFileV1, value, err)
+	}
+}
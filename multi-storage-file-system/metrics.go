@@ -1,6 +1,9 @@
 package main
 
 import (
+	"syscall"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -17,6 +20,14 @@ type fissionMetricsStruct struct {
 	GetAttrFailures             prometheus.Counter
 	GetAttrSuccessLatencies     prometheus.Histogram
 	GetAttrFailureLatencies     prometheus.Histogram
+	ReadLinkSuccesses           prometheus.Counter
+	ReadLinkFailures            prometheus.Counter
+	ReadLinkSuccessLatencies    prometheus.Histogram
+	ReadLinkFailureLatencies    prometheus.Histogram
+	SymLinkSuccesses            prometheus.Counter
+	SymLinkFailures             prometheus.Counter
+	SymLinkSuccessLatencies     prometheus.Histogram
+	SymLinkFailureLatencies     prometheus.Histogram
 	MkNodSuccesses              prometheus.Counter
 	MkNodFailures               prometheus.Counter
 	MkNodSuccessLatencies       prometheus.Histogram
@@ -33,6 +44,10 @@ type fissionMetricsStruct struct {
 	RmDirFailures               prometheus.Counter
 	RmDirSuccessLatencies       prometheus.Histogram
 	RmDirFailureLatencies       prometheus.Histogram
+	RenameSuccesses             prometheus.Counter
+	RenameFailures              prometheus.Counter
+	RenameSuccessLatencies      prometheus.Histogram
+	RenameFailureLatencies      prometheus.Histogram
 	OpenSuccesses               prometheus.Counter
 	OpenFailures                prometheus.Counter
 	OpenSuccessLatencies        prometheus.Histogram
@@ -47,6 +62,7 @@ type fissionMetricsStruct struct {
 	ReadCacheMisses             prometheus.Counter
 	ReadCacheWaits              prometheus.Counter
 	ReadCachePrefetches         prometheus.Counter
+	ReadCacheBypasses           prometheus.Counter
 	StatFSCalls                 prometheus.Counter // Only applicable to globals.fissionMetrics
 	ReleaseSuccesses            prometheus.Counter
 	ReleaseFailures             prometheus.Counter
@@ -78,6 +94,26 @@ type fissionMetricsStruct struct {
 	StatXFailures               prometheus.Counter
 	StatXSuccessLatencies       prometheus.Histogram
 	StatXFailureLatencies       prometheus.Histogram
+	AccessSuccesses             prometheus.Counter
+	AccessFailures              prometheus.Counter
+	AccessSuccessLatencies      prometheus.Histogram
+	AccessFailureLatencies      prometheus.Histogram
+	FAllocateSuccesses          prometheus.Counter
+	FAllocateFailures           prometheus.Counter
+	FAllocateSuccessLatencies   prometheus.Histogram
+	FAllocateFailureLatencies   prometheus.Histogram
+	LSeekSuccesses              prometheus.Counter
+	LSeekFailures               prometheus.Counter
+	LSeekSuccessLatencies       prometheus.Histogram
+	LSeekFailureLatencies       prometheus.Histogram
+	GetXAttrSuccesses           prometheus.Counter
+	GetXAttrFailures            prometheus.Counter
+	GetXAttrSuccessLatencies    prometheus.Histogram
+	GetXAttrFailureLatencies    prometheus.Histogram
+	ListXAttrSuccesses          prometheus.Counter
+	ListXAttrFailures           prometheus.Counter
+	ListXAttrSuccessLatencies   prometheus.Histogram
+	ListXAttrFailureLatencies   prometheus.Histogram
 }
 
 // `newFissionMetrics` provisions and initializes a `fissionMetricsStruct`.
@@ -123,6 +159,44 @@ func newFissionMetrics() (fissionMetrics *fissionMetricsStruct) {
 			Buckets: latencyBuckets,
 		}),
 
+		ReadLinkSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_readlink_successes_total",
+			Help: "Total number of successful ReadLink operations",
+		}),
+		ReadLinkFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_readlink_failures_total",
+			Help: "Total number of failed ReadLink operations",
+		}),
+		ReadLinkSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_readlink_success_latency_seconds",
+			Help:    "Latency of successful ReadLink operations",
+			Buckets: latencyBuckets,
+		}),
+		ReadLinkFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_readlink_failure_latency_seconds",
+			Help:    "Latency of failed ReadLink operations",
+			Buckets: latencyBuckets,
+		}),
+
+		SymLinkSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_symlink_successes_total",
+			Help: "Total number of successful SymLink operations",
+		}),
+		SymLinkFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_symlink_failures_total",
+			Help: "Total number of failed SymLink operations",
+		}),
+		SymLinkSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_symlink_success_latency_seconds",
+			Help:    "Latency of successful SymLink operations",
+			Buckets: latencyBuckets,
+		}),
+		SymLinkFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_symlink_failure_latency_seconds",
+			Help:    "Latency of failed SymLink operations",
+			Buckets: latencyBuckets,
+		}),
+
 		MkNodSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "fission_mknod_successes_total",
 			Help: "Total number of successful MkNod operations",
@@ -199,6 +273,25 @@ func newFissionMetrics() (fissionMetrics *fissionMetricsStruct) {
 			Buckets: latencyBuckets,
 		}),
 
+		RenameSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_rename_successes_total",
+			Help: "Total number of successful Rename operations",
+		}),
+		RenameFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_rename_failures_total",
+			Help: "Total number of failed Rename operations",
+		}),
+		RenameSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_rename_success_latency_seconds",
+			Help:    "Latency of successful Rename operations",
+			Buckets: latencyBuckets,
+		}),
+		RenameFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_rename_failure_latency_seconds",
+			Help:    "Latency of failed Rename operations",
+			Buckets: latencyBuckets,
+		}),
+
 		OpenSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "fission_open_successes_total",
 			Help: "Total number of successful Open operations",
@@ -262,6 +355,10 @@ func newFissionMetrics() (fissionMetrics *fissionMetricsStruct) {
 			Name: "fission_read_cache_prefetches_total",
 			Help: "Total number of Read operation triggered cache prefetches",
 		}),
+		ReadCacheBypasses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_read_cache_bypasses_total",
+			Help: "Total number of Read operations served by ranged backend reads bypassing the cache",
+		}),
 
 		StatFSCalls: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "fission_statfs_calls_total",
@@ -408,14 +505,160 @@ func newFissionMetrics() (fissionMetrics *fissionMetricsStruct) {
 			Help:    "Latency of failed StatX operations",
 			Buckets: latencyBuckets,
 		}),
+
+		AccessSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_access_successes_total",
+			Help: "Total number of successful Access operations",
+		}),
+		AccessFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_access_failures_total",
+			Help: "Total number of failed (including denied) Access operations",
+		}),
+		AccessSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_access_success_latency_seconds",
+			Help:    "Latency of successful Access operations",
+			Buckets: latencyBuckets,
+		}),
+		AccessFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_access_failure_latency_seconds",
+			Help:    "Latency of failed (including denied) Access operations",
+			Buckets: latencyBuckets,
+		}),
+
+		FAllocateSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_fallocate_successes_total",
+			Help: "Total number of successful FAllocate operations",
+		}),
+		FAllocateFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_fallocate_failures_total",
+			Help: "Total number of failed FAllocate operations",
+		}),
+		FAllocateSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_fallocate_success_latency_seconds",
+			Help:    "Latency of successful FAllocate operations",
+			Buckets: latencyBuckets,
+		}),
+		FAllocateFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_fallocate_failure_latency_seconds",
+			Help:    "Latency of failed FAllocate operations",
+			Buckets: latencyBuckets,
+		}),
+
+		LSeekSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_lseek_successes_total",
+			Help: "Total number of successful LSeek operations",
+		}),
+		LSeekFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_lseek_failures_total",
+			Help: "Total number of failed LSeek operations",
+		}),
+		LSeekSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_lseek_success_latency_seconds",
+			Help:    "Latency of successful LSeek operations",
+			Buckets: latencyBuckets,
+		}),
+		LSeekFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_lseek_failure_latency_seconds",
+			Help:    "Latency of failed LSeek operations",
+			Buckets: latencyBuckets,
+		}),
+
+		GetXAttrSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_getxattr_successes_total",
+			Help: "Total number of successful GetXAttr operations",
+		}),
+		GetXAttrFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_getxattr_failures_total",
+			Help: "Total number of failed (including ENODATA) GetXAttr operations",
+		}),
+		GetXAttrSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_getxattr_success_latency_seconds",
+			Help:    "Latency of successful GetXAttr operations",
+			Buckets: latencyBuckets,
+		}),
+		GetXAttrFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_getxattr_failure_latency_seconds",
+			Help:    "Latency of failed (including ENODATA) GetXAttr operations",
+			Buckets: latencyBuckets,
+		}),
+
+		ListXAttrSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_listxattr_successes_total",
+			Help: "Total number of successful ListXAttr operations",
+		}),
+		ListXAttrFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fission_listxattr_failures_total",
+			Help: "Total number of failed ListXAttr operations",
+		}),
+		ListXAttrSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_listxattr_success_latency_seconds",
+			Help:    "Latency of successful ListXAttr operations",
+			Buckets: latencyBuckets,
+		}),
+		ListXAttrFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fission_listxattr_failure_latency_seconds",
+			Help:    "Latency of failed ListXAttr operations",
+			Buckets: latencyBuckets,
+		}),
 	}
 
 	return
 }
 
+// `fissionOpMetricsStruct` holds the success and failure Counters and latency Histograms
+// of a fissionMetricsStruct for a particular `fission` front end operation.
+type fissionOpMetricsStruct struct {
+	successes        prometheus.Counter
+	failures         prometheus.Counter
+	successLatencies prometheus.Histogram
+	failureLatencies prometheus.Histogram
+}
+
+// `observeFissionOp` is deferred by `fission` front end operations to record their outcome
+// (per errno) and latency (since startTime) in the metrics selected by opMetricsOf from both
+// globals.fissionMetrics and, if not nil, backend.fissionMetrics.
+//
+// This is done without holding the global lock. Every metric updated is a goroutine-safe
+// Prometheus Counter or Histogram (self-synchronized), and backend and errno are the caller's
+// locals. globals.fissionMetrics is init-once and a backend's .fissionMetrics is set before the
+// backend is published to globals.backendMap, so both pointers are stable. Not taking the global
+// lock a second time just to update these halves global lock traffic of e.g. tree walks.
+func observeFissionOp(backend *backendStruct, errno syscall.Errno, startTime time.Time, opMetricsOf func(fissionMetrics *fissionMetricsStruct) fissionOpMetricsStruct) {
+	var (
+		latency   = time.Since(startTime).Seconds()
+		opMetrics fissionOpMetricsStruct
+	)
+
+	opMetrics = opMetricsOf(globals.fissionMetrics)
+	if errno == 0 {
+		opMetrics.successes.Inc()
+		opMetrics.successLatencies.Observe(latency)
+	} else {
+		opMetrics.failures.Inc()
+		opMetrics.failureLatencies.Observe(latency)
+	}
+
+	if backend == nil {
+		return
+	}
+
+	opMetrics = opMetricsOf(backend.fissionMetrics)
+	if errno == 0 {
+		opMetrics.successes.Inc()
+		opMetrics.successLatencies.Observe(latency)
+	} else {
+		opMetrics.failures.Inc()
+		opMetrics.failureLatencies.Observe(latency)
+	}
+}
+
 // `backendMetricsStruct` is used to record metrics for the `fission` front end
 // operations. Such metrics will be maintained globally as well as for each backend.
 type backendMetricsStruct struct {
+	CopyFileSuccesses             prometheus.Counter
+	CopyFileFailures              prometheus.Counter
+	CopyFileSuccessLatencies      prometheus.Histogram
+	CopyFileFailureLatencies      prometheus.Histogram
 	DeleteFileSuccesses           prometheus.Counter
 	DeleteFileFailures            prometheus.Counter
 	DeleteFileSuccessLatencies    prometheus.Histogram
@@ -428,10 +671,20 @@ type backendMetricsStruct struct {
 	ListObjectsFailures           prometheus.Counter
 	ListObjectsSuccessLatencies   prometheus.Histogram
 	ListObjectsFailureLatencies   prometheus.Histogram
+	PutFileSuccesses              prometheus.Counter
+	PutFileFailures               prometheus.Counter
+	PutFileSuccessLatencies       prometheus.Histogram
+	PutFileFailureLatencies       prometheus.Histogram
 	ReadFileSuccesses             prometheus.Counter
 	ReadFileFailures              prometheus.Counter
 	ReadFileSuccessLatencies      prometheus.Histogram
 	ReadFileFailureLatencies      prometheus.Histogram
+	ReadFileHedges                prometheus.Counter
+	ReadFileHedgeWins             prometheus.Counter
+	RestoreFileSuccesses          prometheus.Counter
+	RestoreFileFailures           prometheus.Counter
+	RestoreFileSuccessLatencies   prometheus.Histogram
+	RestoreFileFailureLatencies   prometheus.Histogram
 	StatDirectorySuccesses        prometheus.Counter
 	StatDirectoryFailures         prometheus.Counter
 	StatDirectorySuccessLatencies prometheus.Histogram
@@ -441,7 +694,23 @@ type backendMetricsStruct struct {
 	StatFileSuccessLatencies      prometheus.Histogram
 	StatFileFailureLatencies      prometheus.Histogram
 
+	RetryAttempts    prometheus.Histogram
+	RetryDelays      prometheus.Histogram
+	RetriesExhausted prometheus.Counter
+
 	DirectoryPrefetchLatencies prometheus.Histogram
+
+	CacheLineHits             prometheus.Counter
+	CacheLineMisses           prometheus.Counter
+	CacheLineFetchLatencies   prometheus.Histogram
+	CacheLineFetchesCoalesced prometheus.Counter
+	CacheLineEvictions        prometheus.Counter
+	CacheLineInvalidations    prometheus.Counter
+
+	ThrottledRequests prometheus.Counter
+	ThrottleDelays    prometheus.Histogram
+
+	MultipartUploadsAborted prometheus.Counter
 }
 
 // `newBackendMetrics` provisions and initializes a `backendMetricsStruct`.
@@ -449,6 +718,25 @@ func newBackendMetrics() (backendMetrics *backendMetricsStruct) {
 	latencyBuckets := prometheus.DefBuckets
 
 	backendMetrics = &backendMetricsStruct{
+		CopyFileSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_copy_file_successes_total",
+			Help: "Total number of successful CopyFile operations",
+		}),
+		CopyFileFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_copy_file_failures_total",
+			Help: "Total number of failed CopyFile operations",
+		}),
+		CopyFileSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_copy_file_success_latency_seconds",
+			Help:    "Latency of successful CopyFile operations",
+			Buckets: latencyBuckets,
+		}),
+		CopyFileFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_copy_file_failure_latency_seconds",
+			Help:    "Latency of failed CopyFile operations",
+			Buckets: latencyBuckets,
+		}),
+
 		DeleteFileSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "backend_delete_file_successes_total",
 			Help: "Total number of successful DeleteFile operations",
@@ -506,6 +794,25 @@ func newBackendMetrics() (backendMetrics *backendMetricsStruct) {
 			Buckets: latencyBuckets,
 		}),
 
+		PutFileSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_put_file_successes_total",
+			Help: "Total number of successful PutFile operations",
+		}),
+		PutFileFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_put_file_failures_total",
+			Help: "Total number of failed PutFile operations",
+		}),
+		PutFileSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_put_file_success_latency_seconds",
+			Help:    "Latency of successful PutFile operations",
+			Buckets: latencyBuckets,
+		}),
+		PutFileFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_put_file_failure_latency_seconds",
+			Help:    "Latency of failed PutFile operations",
+			Buckets: latencyBuckets,
+		}),
+
 		ReadFileSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "backend_read_file_successes_total",
 			Help: "Total number of successful ReadFile operations",
@@ -524,6 +831,33 @@ func newBackendMetrics() (backendMetrics *backendMetricsStruct) {
 			Help:    "Latency of failed ReadFile operations",
 			Buckets: latencyBuckets,
 		}),
+		ReadFileHedges: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_read_file_hedges_total",
+			Help: "Total number of hedged ReadFile operations issued",
+		}),
+		ReadFileHedgeWins: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_read_file_hedge_wins_total",
+			Help: "Total number of hedged ReadFile operations completing before the original",
+		}),
+
+		RestoreFileSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_restore_file_successes_total",
+			Help: "Total number of successful RestoreFile operations",
+		}),
+		RestoreFileFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_restore_file_failures_total",
+			Help: "Total number of failed RestoreFile operations",
+		}),
+		RestoreFileSuccessLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_restore_file_success_latency_seconds",
+			Help:    "Latency of successful RestoreFile operations",
+			Buckets: latencyBuckets,
+		}),
+		RestoreFileFailureLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_restore_file_failure_latency_seconds",
+			Help:    "Latency of failed RestoreFile operations",
+			Buckets: latencyBuckets,
+		}),
 
 		StatDirectorySuccesses: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "backend_stat_directory_successes_total",
@@ -563,11 +897,67 @@ func newBackendMetrics() (backendMetrics *backendMetricsStruct) {
 			Buckets: latencyBuckets,
 		}),
 
+		RetryAttempts: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_retry_attempts",
+			Help:    "Number of attempts (including the initial attempt) made per backend operation",
+			Buckets: []float64{1, 2, 3, 4, 5, 6, 8, 10, 15, 20},
+		}),
+		RetryDelays: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_retry_delay_seconds",
+			Help:    "Cumulative retry backoff delay of backend operations requiring more than one attempt",
+			Buckets: latencyBuckets,
+		}),
+		RetriesExhausted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_retries_exhausted_total",
+			Help: "Total number of backend operations that failed after more than one attempt",
+		}),
+
 		DirectoryPrefetchLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Name:    "backend_directory_prefetch_latency_seconds",
 			Help:    "Latency of directory prefetch operations",
 			Buckets: latencyBuckets,
 		}),
+
+		CacheLineHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_cache_line_hits_total",
+			Help: "Total number of data cache lines read that were already present in the cache",
+		}),
+		CacheLineMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_cache_line_misses_total",
+			Help: "Total number of data cache lines read that had to be fetched from the backend",
+		}),
+		CacheLineFetchLatencies: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_cache_line_fetch_latency_seconds",
+			Help:    "Latency of populating a data cache line from the backend (including any hedged fetch)",
+			Buckets: latencyBuckets,
+		}),
+		CacheLineFetchesCoalesced: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_cache_line_fetches_coalesced_total",
+			Help: "Total number of data cache line fetches satisfied by sharing an identical backend read already in flight",
+		}),
+		CacheLineEvictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_cache_line_evictions_total",
+			Help: "Total number of Clean data cache lines evicted to make room for other content",
+		}),
+		CacheLineInvalidations: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_cache_line_invalidations_total",
+			Help: "Total number of Clean data cache lines discarded upon finding their object changed in the backend",
+		}),
+
+		ThrottledRequests: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_throttled_requests_total",
+			Help: "Total number of readFile and listDirectory requests delayed by the read_bandwidth_limit or request_rate_limit",
+		}),
+		ThrottleDelays: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "backend_throttle_delay_seconds",
+			Help:    "Time readFile and listDirectory requests were delayed by the read_bandwidth_limit or request_rate_limit",
+			Buckets: latencyBuckets,
+		}),
+
+		MultipartUploadsAborted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "backend_multipart_uploads_aborted_total",
+			Help: "Total number of abandoned multipart uploads aborted by the multipart upload janitor (or \"cleanup\" subcommand)",
+		}),
 	}
 
 	return
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+// `mountStruct` is the runtime state of one of the additional FUSE mounts described by
+// config.mounts. Each presents its own FUSERootDir inode (rather than the one with inode number
+// FUSERootDirInodeNumber) as the root directory of its fission.Volume. As all of the other inodes,
+// the data cache, and the globals lock are shared with the mount at config.mountPoint, so too is
+// the budget of data cache lines.
+//
+// The kernel always refers to the root directory of a FUSE mount by NodeID FUSERootDirInodeNumber,
+// so mountStruct implements fission.Callbacks by mapping that NodeID to .rootInodeNumber before
+// calling the corresponding globalsStruct callback (and mapping it back in any reply).
+type mountStruct struct {
+	mountPoint      string         // Key in globalsStruct.mounts
+	rootInodeNumber uint64         // The inode (with .inodeType == FUSERootDir) presented as this mount's root directory
+	fissionVolume   fission.Volume //
+}
+
+// `parseMounts` fills in config.mounts from the "mounts" section (if any) of configFileMap. It must
+// be called once config.backends has been filled in as it also sets the .mountPoint of each backend
+// to be presented by one of the mounts.
+func parseMounts(configFileMap map[string]interface{}, config *configStruct) (err error) {
+	var (
+		backend                     *backendStruct
+		dirName                     string
+		dirNameAsInterface          interface{}
+		dirNamesAsInterfaceSlice    []interface{}
+		mountAsInterface            interface{}
+		mountAsMap                  map[string]interface{}
+		mountConfig                 mountConfigStruct
+		mountsAsInterface           interface{}
+		mountsAsInterfaceSlice      []interface{}
+		mountsAsInterfaceSliceIndex int
+		ok                          bool
+	)
+
+	mountsAsInterface, ok = configFileMap["mounts"]
+	if !ok {
+		return
+	}
+
+	mountsAsInterfaceSlice, ok = mountsAsInterface.([]interface{})
+	if !ok {
+		err = errors.New("bad mounts section")
+		return
+	}
+
+	config.mounts = make([]mountConfigStruct, 0, len(mountsAsInterfaceSlice))
+
+	for mountsAsInterfaceSliceIndex, mountAsInterface = range mountsAsInterfaceSlice {
+		mountAsMap, ok = mountAsInterface.(map[string]interface{})
+		if !ok {
+			err = fmt.Errorf("bad mounts[%v]", mountsAsInterfaceSliceIndex)
+			return
+		}
+
+		mountConfig = mountConfigStruct{}
+
+		mountConfig.mountPoint, ok = parseString(mountAsMap, "mountpoint", nil)
+		if !ok || !filepath.IsAbs(mountConfig.mountPoint) {
+			err = fmt.Errorf("missing or bad mountpoint at mounts[%v] (must be an absolute path)", mountsAsInterfaceSliceIndex)
+			return
+		}
+		mountConfig.mountPoint = filepath.Clean(mountConfig.mountPoint)
+		if (mountConfig.mountPoint == filepath.Clean(config.mountPoint)) || slices.ContainsFunc(config.mounts, func(other mountConfigStruct) bool { return other.mountPoint == mountConfig.mountPoint }) {
+			err = fmt.Errorf("duplicate mountpoint (%q) at mounts[%v]", mountConfig.mountPoint, mountsAsInterfaceSliceIndex)
+			return
+		}
+
+		dirNamesAsInterfaceSlice, ok = mountAsMap["backends"].([]interface{})
+		if !ok || (len(dirNamesAsInterfaceSlice) == 0) {
+			err = fmt.Errorf("missing or bad backends at mounts[%v]", mountsAsInterfaceSliceIndex)
+			return
+		}
+
+		mountConfig.backends = make([]string, 0, len(dirNamesAsInterfaceSlice))
+
+		for _, dirNameAsInterface = range dirNamesAsInterfaceSlice {
+			dirName, ok = dirNameAsInterface.(string)
+			if !ok {
+				err = fmt.Errorf("bad backends element at mounts[%v]", mountsAsInterfaceSliceIndex)
+				return
+			}
+
+			backend, ok = config.backends[dirName]
+			if !ok {
+				err = fmt.Errorf("backend \"%s\" at mounts[%v] not found among configured backends", dirName, mountsAsInterfaceSliceIndex)
+				return
+			}
+			if backend.mountPoint != "" {
+				err = fmt.Errorf("backend \"%s\" at mounts[%v] already presented at mountpoint %q", dirName, mountsAsInterfaceSliceIndex, backend.mountPoint)
+				return
+			}
+
+			backend.mountPoint = mountConfig.mountPoint
+
+			mountConfig.backends = append(mountConfig.backends, dirName)
+		}
+
+		config.mounts = append(config.mounts, mountConfig)
+	}
+
+	return
+}
+
+// `equalMounts` reports whether a and b describe the same set of additional FUSE mounts.
+func equalMounts(a, b []mountConfigStruct) bool {
+	return slices.EqualFunc(a, b, func(aMount, bMount mountConfigStruct) bool {
+		return (aMount.mountPoint == bMount.mountPoint) && slices.Equal(aMount.backends, bMount.backends)
+	})
+}
+
+// `initMounts` is called while globals.Lock() is held during initFS() to create the
+// root directory inode of each of config.mounts.
+func initMounts(timeNow time.Time) {
+	var (
+		mount       *mountStruct
+		mountConfig mountConfigStruct
+	)
+
+	globals.mounts = make(map[string]*mountStruct, len(globals.config.mounts))
+
+	for _, mountConfig = range globals.config.mounts {
+		mount = &mountStruct{
+			mountPoint:      mountConfig.mountPoint,
+			rootInodeNumber: fetchNonce(),
+		}
+
+		createFUSERootDirInode(mount.rootInodeNumber, timeNow)
+
+		globals.mounts[mount.mountPoint] = mount
+	}
+}
+
+// `performMountsFissionMount` is called after performFissionMount() to do the FUSE mount of
+// each of config.mounts at startup.
+func performMountsFissionMount(fissionLogger *log.Logger) (err error) {
+	var (
+		mount *mountStruct
+	)
+
+	for _, mount = range globals.mounts {
+		mount.fissionVolume = fission.NewVolume(&fission.VolumeConfig{
+			VolumeName:         globals.config.mountName,
+			MountpointDirPath:  mount.mountPoint,
+			FuseSubtype:        fuseSubtype,
+			MaxRead:            maxRead,
+			MaxWrite:           maxWrite,
+			DefaultPermissions: true,
+			AllowOther:         globals.config.allowOther,
+			NumWorkers:         int(globals.config.fuseWorkers),
+			PerWorkerFD:        globals.config.fuseFdPerWorker,
+			Callbacks:          mount,
+			Logger:             fissionLogger,
+			ErrChan:            globals.errChan,
+		})
+
+		err = mount.fissionVolume.DoMount()
+		if err != nil {
+			err = fmt.Errorf("FUSE mount of %s failed: %v", mount.mountPoint, err)
+			return
+		}
+	}
+
+	return
+}
+
+// `backendFUSERootDirInodeNumber` returns the inode number of the root directory of the FUSE mount
+// presenting backend: that of one of config.mounts if backend.mountPoint is set and, otherwise,
+// FUSERootDirInodeNumber.
+func backendFUSERootDirInodeNumber(backend *backendStruct) (inodeNumber uint64) {
+	var (
+		mount *mountStruct
+		ok    bool
+	)
+
+	mount, ok = globals.mounts[backend.mountPoint]
+	if ok {
+		inodeNumber = mount.rootInodeNumber
+	} else {
+		inodeNumber = FUSERootDirInodeNumber
+	}
+
+	return
+}
+
+// `fissionVolumeOf` returns the fission.Volume of the FUSE mount presenting backend (or, if
+// backend is nil, that of the mount at config.mountPoint) along with the mapping of inode
+// numbers to the NodeIDs by which that mount's kernel refers to them.
+func fissionVolumeOf(backend *backendStruct) (fissionVolume fission.Volume, nodeID func(inodeNumber uint64) uint64) {
+	var (
+		mount *mountStruct
+		ok    bool
+	)
+
+	if backend != nil {
+		mount, ok = globals.mounts[backend.mountPoint]
+	}
+	if !ok {
+		fissionVolume = globals.fissionVolume
+		nodeID = func(inodeNumber uint64) uint64 { return inodeNumber }
+		return
+	}
+
+	fissionVolume = mount.fissionVolume
+	nodeID = mount.nodeID
+
+	return
+}
+
+// `inodeNumber` maps the NodeID by which this mount's kernel refers to an inode to its inode number.
+func (mount *mountStruct) inodeNumber(nodeID uint64) uint64 {
+	if nodeID == FUSERootDirInodeNumber {
+		return mount.rootInodeNumber
+	}
+
+	return nodeID
+}
+
+// `nodeID` maps an inode number to the NodeID by which this mount's kernel refers to that inode.
+func (mount *mountStruct) nodeID(inodeNumber uint64) uint64 {
+	if inodeNumber == mount.rootInodeNumber {
+		return FUSERootDirInodeNumber
+	}
+
+	return inodeNumber
+}
+
+// `inHeader` returns inHeader (or, if it refers to this mount's root directory, a copy of it
+// referring instead to mount.rootInodeNumber) to pass along to a globalsStruct callback.
+func (mount *mountStruct) inHeader(inHeader *fission.InHeader) *fission.InHeader {
+	var (
+		mappedInHeader fission.InHeader
+	)
+
+	if inHeader.NodeID != FUSERootDirInodeNumber {
+		return inHeader
+	}
+
+	mappedInHeader = *inHeader
+	mappedInHeader.NodeID = mount.rootInodeNumber
+
+	return &mappedInHeader
+}
+
+// `DoLookup` implements the package fission callback by calling globals.DoLookup() on behalf of this mount.
+func (mount *mountStruct) DoLookup(inHeader *fission.InHeader, lookupIn *fission.LookupIn) (lookupOut *fission.LookupOut, errno syscall.Errno) {
+	lookupOut, errno = globals.DoLookup(mount.inHeader(inHeader), lookupIn)
+	if errno == 0 {
+		lookupOut.EntryOut.NodeID = mount.nodeID(lookupOut.EntryOut.NodeID)
+	}
+	return
+}
+
+// `DoForget` implements the package fission callback by calling globals.DoForget() on behalf of this mount.
+func (mount *mountStruct) DoForget(inHeader *fission.InHeader, forgetIn *fission.ForgetIn) {
+	globals.DoForget(mount.inHeader(inHeader), forgetIn)
+}
+
+// `DoGetAttr` implements the package fission callback by calling globals.DoGetAttr() on behalf of this mount.
+func (mount *mountStruct) DoGetAttr(inHeader *fission.InHeader, getAttrIn *fission.GetAttrIn) (getAttrOut *fission.GetAttrOut, errno syscall.Errno) {
+	return globals.DoGetAttr(mount.inHeader(inHeader), getAttrIn)
+}
+
+// `DoSetAttr` implements the package fission callback by calling globals.DoSetAttr() on behalf of this mount.
+func (mount *mountStruct) DoSetAttr(inHeader *fission.InHeader, setAttrIn *fission.SetAttrIn) (setAttrOut *fission.SetAttrOut, errno syscall.Errno) {
+	return globals.DoSetAttr(mount.inHeader(inHeader), setAttrIn)
+}
+
+// `DoReadLink` implements the package fission callback by calling globals.DoReadLink() on behalf of this mount.
+func (mount *mountStruct) DoReadLink(inHeader *fission.InHeader) (readLinkOut *fission.ReadLinkOut, errno syscall.Errno) {
+	return globals.DoReadLink(mount.inHeader(inHeader))
+}
+
+// `DoSymLink` implements the package fission callback by calling globals.DoSymLink() on behalf of this mount.
+func (mount *mountStruct) DoSymLink(inHeader *fission.InHeader, symLinkIn *fission.SymLinkIn) (symLinkOut *fission.SymLinkOut, errno syscall.Errno) {
+	return globals.DoSymLink(mount.inHeader(inHeader), symLinkIn)
+}
+
+// `DoMkNod` implements the package fission callback by calling globals.DoMkNod() on behalf of this mount.
+func (mount *mountStruct) DoMkNod(inHeader *fission.InHeader, mkNodIn *fission.MkNodIn) (mkNodOut *fission.MkNodOut, errno syscall.Errno) {
+	return globals.DoMkNod(mount.inHeader(inHeader), mkNodIn)
+}
+
+// `DoMkDir` implements the package fission callback by calling globals.DoMkDir() on behalf of this mount.
+func (mount *mountStruct) DoMkDir(inHeader *fission.InHeader, mkDirIn *fission.MkDirIn) (mkDirOut *fission.MkDirOut, errno syscall.Errno) {
+	return globals.DoMkDir(mount.inHeader(inHeader), mkDirIn)
+}
+
+// `DoUnlink` implements the package fission callback by calling globals.DoUnlink() on behalf of this mount.
+func (mount *mountStruct) DoUnlink(inHeader *fission.InHeader, unlinkIn *fission.UnlinkIn) (errno syscall.Errno) {
+	return globals.DoUnlink(mount.inHeader(inHeader), unlinkIn)
+}
+
+// `DoRmDir` implements the package fission callback by calling globals.DoRmDir() on behalf of this mount.
+func (mount *mountStruct) DoRmDir(inHeader *fission.InHeader, rmDirIn *fission.RmDirIn) (errno syscall.Errno) {
+	return globals.DoRmDir(mount.inHeader(inHeader), rmDirIn)
+}
+
+// `DoRename` implements the package fission callback by calling globals.DoRename() on behalf of this mount.
+func (mount *mountStruct) DoRename(inHeader *fission.InHeader, renameIn *fission.RenameIn) (errno syscall.Errno) {
+	var (
+		mappedRenameIn = *renameIn
+	)
+
+	mappedRenameIn.NewDir = mount.inodeNumber(renameIn.NewDir)
+
+	return globals.DoRename(mount.inHeader(inHeader), &mappedRenameIn)
+}
+
+// `DoLink` implements the package fission callback by calling globals.DoLink() on behalf of this mount.
+func (mount *mountStruct) DoLink(inHeader *fission.InHeader, linkIn *fission.LinkIn) (linkOut *fission.LinkOut, errno syscall.Errno) {
+	return globals.DoLink(mount.inHeader(inHeader), linkIn)
+}
+
+// `DoOpen` implements the package fission callback by calling globals.DoOpen() on behalf of this mount.
+func (mount *mountStruct) DoOpen(inHeader *fission.InHeader, openIn *fission.OpenIn) (openOut *fission.OpenOut, errno syscall.Errno) {
+	return globals.DoOpen(mount.inHeader(inHeader), openIn)
+}
+
+// `DoRead` implements the package fission callback by calling globals.DoRead() on behalf of this mount.
+func (mount *mountStruct) DoRead(inHeader *fission.InHeader, readIn *fission.ReadIn) (readOut *fission.ReadOut, errno syscall.Errno) {
+	return globals.DoRead(mount.inHeader(inHeader), readIn)
+}
+
+// `DoWrite` implements the package fission callback by calling globals.DoWrite() on behalf of this mount.
+func (mount *mountStruct) DoWrite(inHeader *fission.InHeader, writeIn *fission.WriteIn) (writeOut *fission.WriteOut, errno syscall.Errno) {
+	return globals.DoWrite(mount.inHeader(inHeader), writeIn)
+}
+
+// `DoStatFS` implements the package fission callback by calling globals.DoStatFS() on behalf of this mount.
+func (mount *mountStruct) DoStatFS(inHeader *fission.InHeader) (statFSOut *fission.StatFSOut, errno syscall.Errno) {
+	return globals.DoStatFS(mount.inHeader(inHeader))
+}
+
+// `DoRelease` implements the package fission callback by calling globals.DoRelease() on behalf of this mount.
+func (mount *mountStruct) DoRelease(inHeader *fission.InHeader, releaseIn *fission.ReleaseIn) (errno syscall.Errno) {
+	return globals.DoRelease(mount.inHeader(inHeader), releaseIn)
+}
+
+// `DoFSync` implements the package fission callback by calling globals.DoFSync() on behalf of this mount.
+func (mount *mountStruct) DoFSync(inHeader *fission.InHeader, fSyncIn *fission.FSyncIn) (errno syscall.Errno) {
+	return globals.DoFSync(mount.inHeader(inHeader), fSyncIn)
+}
+
+// `DoSetXAttr` implements the package fission callback by calling globals.DoSetXAttr() on behalf of this mount.
+func (mount *mountStruct) DoSetXAttr(inHeader *fission.InHeader, setXAttrIn *fission.SetXAttrIn) (errno syscall.Errno) {
+	return globals.DoSetXAttr(mount.inHeader(inHeader), setXAttrIn)
+}
+
+// `DoGetXAttr` implements the package fission callback by calling globals.DoGetXAttr() on behalf of this mount.
+func (mount *mountStruct) DoGetXAttr(inHeader *fission.InHeader, getXAttrIn *fission.GetXAttrIn) (getXAttrOut *fission.GetXAttrOut, errno syscall.Errno) {
+	return globals.DoGetXAttr(mount.inHeader(inHeader), getXAttrIn)
+}
+
+// `DoListXAttr` implements the package fission callback by calling globals.DoListXAttr() on behalf of this mount.
+func (mount *mountStruct) DoListXAttr(inHeader *fission.InHeader, listXAttrIn *fission.ListXAttrIn) (listXAttrOut *fission.ListXAttrOut, errno syscall.Errno) {
+	return globals.DoListXAttr(mount.inHeader(inHeader), listXAttrIn)
+}
+
+// `DoRemoveXAttr` implements the package fission callback by calling globals.DoRemoveXAttr() on behalf of this mount.
+func (mount *mountStruct) DoRemoveXAttr(inHeader *fission.InHeader, removeXAttrIn *fission.RemoveXAttrIn) (errno syscall.Errno) {
+	return globals.DoRemoveXAttr(mount.inHeader(inHeader), removeXAttrIn)
+}
+
+// `DoFlush` implements the package fission callback by calling globals.DoFlush() on behalf of this mount.
+func (mount *mountStruct) DoFlush(inHeader *fission.InHeader, flushIn *fission.FlushIn) (errno syscall.Errno) {
+	return globals.DoFlush(mount.inHeader(inHeader), flushIn)
+}
+
+// `DoInit` implements the package fission callback by calling globals.DoInit() on behalf of this mount.
+func (mount *mountStruct) DoInit(inHeader *fission.InHeader, initIn *fission.InitIn) (initOut *fission.InitOut, errno syscall.Errno) {
+	return globals.DoInit(mount.inHeader(inHeader), initIn)
+}
+
+// `DoOpenDir` implements the package fission callback by calling globals.DoOpenDir() on behalf of this mount.
+func (mount *mountStruct) DoOpenDir(inHeader *fission.InHeader, openDirIn *fission.OpenDirIn) (openDirOut *fission.OpenDirOut, errno syscall.Errno) {
+	return globals.DoOpenDir(mount.inHeader(inHeader), openDirIn)
+}
+
+// `DoReadDir` implements the package fission callback by calling globals.DoReadDir() on behalf of this mount.
+func (mount *mountStruct) DoReadDir(inHeader *fission.InHeader, readDirIn *fission.ReadDirIn) (readDirOut *fission.ReadDirOut, errno syscall.Errno) {
+	return globals.DoReadDir(mount.inHeader(inHeader), readDirIn)
+}
+
+// `DoReleaseDir` implements the package fission callback by calling globals.DoReleaseDir() on behalf of this mount.
+func (mount *mountStruct) DoReleaseDir(inHeader *fission.InHeader, releaseDirIn *fission.ReleaseDirIn) (errno syscall.Errno) {
+	return globals.DoReleaseDir(mount.inHeader(inHeader), releaseDirIn)
+}
+
+// `DoFSyncDir` implements the package fission callback by calling globals.DoFSyncDir() on behalf of this mount.
+func (mount *mountStruct) DoFSyncDir(inHeader *fission.InHeader, fSyncDirIn *fission.FSyncDirIn) (errno syscall.Errno) {
+	return globals.DoFSyncDir(mount.inHeader(inHeader), fSyncDirIn)
+}
+
+// `DoGetLK` implements the package fission callback by calling globals.DoGetLK() on behalf of this mount.
+func (mount *mountStruct) DoGetLK(inHeader *fission.InHeader, getLKIn *fission.GetLKIn) (getLKOut *fission.GetLKOut, errno syscall.Errno) {
+	return globals.DoGetLK(mount.inHeader(inHeader), getLKIn)
+}
+
+// `DoSetLK` implements the package fission callback by calling globals.DoSetLK() on behalf of this mount.
+func (mount *mountStruct) DoSetLK(inHeader *fission.InHeader, setLKIn *fission.SetLKIn) (errno syscall.Errno) {
+	return globals.DoSetLK(mount.inHeader(inHeader), setLKIn)
+}
+
+// `DoSetLKW` implements the package fission callback by calling globals.DoSetLKW() on behalf of this mount.
+func (mount *mountStruct) DoSetLKW(inHeader *fission.InHeader, setLKWIn *fission.SetLKWIn) (errno syscall.Errno) {
+	return globals.DoSetLKW(mount.inHeader(inHeader), setLKWIn)
+}
+
+// `DoAccess` implements the package fission callback by calling globals.DoAccess() on behalf of this mount.
+func (mount *mountStruct) DoAccess(inHeader *fission.InHeader, accessIn *fission.AccessIn) (errno syscall.Errno) {
+	return globals.DoAccess(mount.inHeader(inHeader), accessIn)
+}
+
+// `DoCreate` implements the package fission callback by calling globals.DoCreate() on behalf of this mount.
+func (mount *mountStruct) DoCreate(inHeader *fission.InHeader, createIn *fission.CreateIn) (createOut *fission.CreateOut, errno syscall.Errno) {
+	return globals.DoCreate(mount.inHeader(inHeader), createIn)
+}
+
+// `DoInterrupt` implements the package fission callback by calling globals.DoInterrupt() on behalf of this mount.
+func (mount *mountStruct) DoInterrupt(inHeader *fission.InHeader, interruptIn *fission.InterruptIn) {
+	globals.DoInterrupt(mount.inHeader(inHeader), interruptIn)
+}
+
+// `DoBMap` implements the package fission callback by calling globals.DoBMap() on behalf of this mount.
+func (mount *mountStruct) DoBMap(inHeader *fission.InHeader, bMapIn *fission.BMapIn) (bMapOut *fission.BMapOut, errno syscall.Errno) {
+	return globals.DoBMap(mount.inHeader(inHeader), bMapIn)
+}
+
+// `DoDestroy` implements the package fission callback by calling globals.DoDestroy() on behalf of this mount.
+func (mount *mountStruct) DoDestroy(inHeader *fission.InHeader) (errno syscall.Errno) {
+	return globals.DoDestroy(mount.inHeader(inHeader))
+}
+
+// `DoPoll` implements the package fission callback by calling globals.DoPoll() on behalf of this mount.
+func (mount *mountStruct) DoPoll(inHeader *fission.InHeader, pollIn *fission.PollIn) (pollOut *fission.PollOut, errno syscall.Errno) {
+	return globals.DoPoll(mount.inHeader(inHeader), pollIn)
+}
+
+// `DoBatchForget` implements the package fission callback by calling globals.DoBatchForget() on behalf of this mount.
+func (mount *mountStruct) DoBatchForget(inHeader *fission.InHeader, batchForgetIn *fission.BatchForgetIn) {
+	globals.DoBatchForget(mount.inHeader(inHeader), batchForgetIn)
+}
+
+// `DoFAllocate` implements the package fission callback by calling globals.DoFAllocate() on behalf of this mount.
+func (mount *mountStruct) DoFAllocate(inHeader *fission.InHeader, fAllocateIn *fission.FAllocateIn) (errno syscall.Errno) {
+	return globals.DoFAllocate(mount.inHeader(inHeader), fAllocateIn)
+}
+
+// `DoReadDirPlus` implements the package fission callback by calling globals.DoReadDirPlus() on behalf of this mount.
+func (mount *mountStruct) DoReadDirPlus(inHeader *fission.InHeader, readDirPlusIn *fission.ReadDirPlusIn) (readDirPlusOut *fission.ReadDirPlusOut, errno syscall.Errno) {
+	var (
+		dirEntPlusIndex int
+	)
+
+	readDirPlusOut, errno = globals.DoReadDirPlus(mount.inHeader(inHeader), readDirPlusIn)
+	if errno == 0 {
+		for dirEntPlusIndex = range readDirPlusOut.DirEntPlus {
+			readDirPlusOut.DirEntPlus[dirEntPlusIndex].EntryOut.NodeID = mount.nodeID(readDirPlusOut.DirEntPlus[dirEntPlusIndex].EntryOut.NodeID)
+		}
+	}
+	return
+}
+
+// `DoRename2` implements the package fission callback by calling globals.DoRename2() on behalf of this mount.
+func (mount *mountStruct) DoRename2(inHeader *fission.InHeader, rename2In *fission.Rename2In) (errno syscall.Errno) {
+	var (
+		mappedRename2In = *rename2In
+	)
+
+	mappedRename2In.NewDir = mount.inodeNumber(rename2In.NewDir)
+
+	return globals.DoRename2(mount.inHeader(inHeader), &mappedRename2In)
+}
+
+// `DoLSeek` implements the package fission callback by calling globals.DoLSeek() on behalf of this mount.
+func (mount *mountStruct) DoLSeek(inHeader *fission.InHeader, lSeekIn *fission.LSeekIn) (lSeekOut *fission.LSeekOut, errno syscall.Errno) {
+	return globals.DoLSeek(mount.inHeader(inHeader), lSeekIn)
+}
+
+// `DoStatX` implements the package fission callback by calling globals.DoStatX() on behalf of this mount.
+func (mount *mountStruct) DoStatX(inHeader *fission.InHeader, statXIn *fission.StatXIn) (statXOut *fission.StatXOut, errno syscall.Errno) {
+	return globals.DoStatX(mount.inHeader(inHeader), statXIn)
+}
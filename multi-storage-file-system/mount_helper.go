@@ -0,0 +1,258 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	mountHelperPrefix     = "mount." // Basename prefix (e.g. "mount.mscp") under which mount(8) invokes a helper
+	mountHelperSubcommand = "mount"  // Alternatively, "msfs mount <config-file> <mountpoint> [-o <options>]"
+)
+
+// `mountHelperIgnoredOptions` are the generic mount(8) and fstab(5) options that have no
+// MSFS equivalent (or are handled by mount(8) itself) and are hence silently accepted.
+var mountHelperIgnoredOptions = map[string]struct{}{
+	"async": {}, "atime": {}, "auto": {}, "defaults": {}, "dev": {}, "exec": {}, "group": {},
+	"noatime": {}, "noauto": {}, "nodev": {}, "nodiratime": {}, "noexec": {}, "nofail": {},
+	"nosuid": {}, "nouser": {}, "owner": {}, "relatime": {}, "rw": {}, "strictatime": {},
+	"suid": {}, "sync": {}, "user": {}, "users": {}, "_netdev": {},
+}
+
+// `isMountHelperInvocation` returns whether or not osArgs reflect an invocation as a mount(8)
+// helper (i.e. via a "mount.<type>" link to this binary) or of the "mount" subcommand.
+func isMountHelperInvocation(osArgs []string) bool {
+	if strings.HasPrefix(filepath.Base(osArgs[0]), mountHelperPrefix) {
+		return true
+	}
+
+	return (len(osArgs) >= 2) && (osArgs[1] == mountHelperSubcommand)
+}
+
+// `parseMountHelperArgs` translates the mount(8) helper command line:
+//
+//	mount.<type> <config-file> <mountpoint> [-sfnv] [-N <namespace>] [-o <options>] [-t <type>]
+//
+// into the equivalent --daemon invocation, returning its arguments. The mountpoint is conveyed
+// via ${MSFS_MOUNTPOINT} and any -o options that override config file settings are conveyed
+// via ${MSFS_CONFIG_OVERRIDES} (see applyConfigOverrides()). If -f ("fake") was specified, the
+// returned fake is true and nothing should be mounted.
+func parseMountHelperArgs(osArgs []string) (daemonArgs []string, fake bool, err error) {
+	var (
+		arg         string
+		argIndex    int
+		configFile  string
+		mountPoint  string
+		options     []string
+		overrides   []string
+		positionals []string
+		sloppy      bool
+	)
+
+	argIndex = 1
+	if !strings.HasPrefix(filepath.Base(osArgs[0]), mountHelperPrefix) {
+		argIndex = 2 // Skip the "mount" subcommand
+	}
+
+	for ; argIndex < len(osArgs); argIndex++ {
+		arg = osArgs[argIndex]
+
+		switch {
+		case (arg == "-o") || (arg == "-t") || (arg == "-N"):
+			argIndex++
+			if argIndex == len(osArgs) {
+				err = fmt.Errorf("%s requires a value", arg)
+				return
+			}
+			if arg == "-o" {
+				options = append(options, strings.Split(osArgs[argIndex], ",")...)
+			}
+		case strings.HasPrefix(arg, "-o") && (len(arg) > 2):
+			options = append(options, strings.Split(arg[2:], ",")...)
+		case strings.HasPrefix(arg, "-") && (len(arg) > 1):
+			for _, flag := range arg[1:] {
+				switch flag {
+				case 's':
+					sloppy = true
+				case 'f':
+					fake = true
+				case 'n', 'v':
+					// Nothing to do here (MSFS maintains no mtab entries and always logs)
+				default:
+					err = fmt.Errorf("unsupported mount helper flag -%c", flag)
+					return
+				}
+			}
+		default:
+			positionals = append(positionals, arg)
+		}
+	}
+
+	if len(positionals) != 2 {
+		err = fmt.Errorf("usage: %s <config-file> <mountpoint> [-sfnv] [-o <options>]", osArgs[0])
+		return
+	}
+
+	configFile, mountPoint = positionals[0], positionals[1]
+
+	daemonArgs = []string{osArgs[0], "--daemon"}
+
+	for _, option := range options {
+		name, value, hasValue := strings.Cut(option, "=")
+
+		switch {
+		case option == "":
+			// Nothing to do here
+		case strings.HasPrefix(name, "x-") || (name == "comment"):
+			// Nothing to do here (reserved for use by userspace tools such as systemd)
+		case !hasValue && isMountHelperIgnoredOption(name):
+			// Nothing to do here
+		case (name == "ro") && !hasValue:
+			overrides = append(overrides, "backends.readonly=true")
+		case (name == "allow_other") && !hasValue:
+			overrides = append(overrides, "allow_other=true")
+		case (name == "pidfile") && hasValue:
+			daemonArgs = append(daemonArgs, "--pidfile", value)
+		case (name == "log_destination") && hasValue:
+			daemonArgs = append(daemonArgs, "--log-destination", value)
+		case hasValue && isConfigOverrideKey(name):
+			overrides = append(overrides, option)
+		case sloppy:
+			// Nothing to do here (-s requests that unsupported options be ignored)
+		default:
+			err = fmt.Errorf("unsupported mount option \"%s\"", option)
+			return
+		}
+	}
+
+	daemonArgs = append(daemonArgs, configFile)
+
+	err = os.Setenv(EnvMSFSMountPoint, mountPoint)
+	if err != nil {
+		return
+	}
+
+	if len(overrides) == 0 {
+		err = os.Unsetenv(EnvMSFSConfigOverrides)
+	} else {
+		err = os.Setenv(EnvMSFSConfigOverrides, strings.Join(overrides, ","))
+	}
+
+	return
+}
+
+// `isMountHelperIgnoredOption` returns whether or not option is among mountHelperIgnoredOptions.
+func isMountHelperIgnoredOption(option string) (ignored bool) {
+	_, ignored = mountHelperIgnoredOptions[option]
+	return
+}
+
+// `isConfigOverrideKey` returns whether or not key is of the form of a (top-level or, if
+// prefixed by "backends.", per-backend) config file setting (e.g. "cache_lines").
+func isConfigOverrideKey(key string) bool {
+	key = strings.TrimPrefix(key, "backends.")
+
+	if key == "" {
+		return false
+	}
+
+	for _, r := range key {
+		if ((r < 'a') || (r > 'z')) && ((r < '0') || (r > '9')) && (r != '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// `applyConfigOverrides` applies the comma-separated "<key>=<value>" settings found in
+// ${MSFS_CONFIG_OVERRIDES} (as set by parseMountHelperArgs()) to configFileMap. A key
+// prefixed by "backends." is applied to every backend. Each value is interpreted as a
+// YAML scalar (e.g. "true" as a bool, "1000" as a number) unless the setting being
+// overridden is a string in the config file (or is a "*_perm" setting) in which case
+// the value is taken verbatim.
+func applyConfigOverrides(configFileMap map[string]interface{}) (err error) {
+	var (
+		backendAsInterface  interface{}
+		backendAsMap        map[string]interface{}
+		backendsAsInterface interface{}
+		backendsAsSlice     []interface{}
+		key                 string
+		ok                  bool
+		override            string
+		overrides           string
+		value               string
+	)
+
+	overrides = os.Getenv(EnvMSFSConfigOverrides)
+	if overrides == "" {
+		return
+	}
+
+	for _, override = range strings.Split(overrides, ",") {
+		key, value, ok = strings.Cut(override, "=")
+		if !ok || !isConfigOverrideKey(key) {
+			err = fmt.Errorf("bad %s entry \"%s\"", EnvMSFSConfigOverrides, override)
+			return
+		}
+
+		if !strings.HasPrefix(key, "backends.") {
+			configFileMap[key], err = configOverrideValue(configFileMap, key, value)
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		key = strings.TrimPrefix(key, "backends.")
+
+		backendsAsInterface, ok = configFileMap["backends"]
+		if !ok {
+			continue
+		}
+		backendsAsSlice, ok = backendsAsInterface.([]interface{})
+		if !ok {
+			err = errors.New("bad backends section")
+			return
+		}
+
+		for _, backendAsInterface = range backendsAsSlice {
+			backendAsMap, ok = backendAsInterface.(map[string]interface{})
+			if !ok {
+				err = errors.New("bad backends section")
+				return
+			}
+			backendAsMap[key], err = configOverrideValue(backendAsMap, key, value)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// `configOverrideValue` returns the value to store at m[key] for an override of value.
+func configOverrideValue(m map[string]interface{}, key string, value string) (v interface{}, err error) {
+	var (
+		existingIsString bool
+	)
+
+	_, existingIsString = m[key].(string)
+	if existingIsString || strings.HasSuffix(key, "_perm") {
+		v = value
+		return
+	}
+
+	err = yaml.Unmarshal([]byte(value), &v)
+	if err != nil {
+		err = fmt.Errorf("bad %s value for \"%s\": %v", EnvMSFSConfigOverrides, key, err)
+	}
+
+	return
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestParseMountHelperArgs(t *testing.T) {
+	var (
+		daemonArgs []string
+		err        error
+		fake       bool
+	)
+
+	t.Setenv(EnvMSFSMountPoint, "")
+	t.Setenv(EnvMSFSConfigOverrides, "")
+
+	if !isMountHelperInvocation([]string{"/usr/sbin/mount.mscp", "config.yaml", "/mnt/msc"}) {
+		t.Fatalf("isMountHelperInvocation() should have recognized mount.mscp")
+	}
+	if !isMountHelperInvocation([]string{"msfs", "mount", "config.yaml", "/mnt/msc"}) {
+		t.Fatalf("isMountHelperInvocation() should have recognized the mount subcommand")
+	}
+	if isMountHelperInvocation([]string{"msfs", "config.yaml"}) {
+		t.Fatalf("isMountHelperInvocation() should not have recognized a normal invocation")
+	}
+
+	daemonArgs, fake, err = parseMountHelperArgs([]string{"/usr/sbin/mount.mscp", "config.yaml", "/mnt/msc", "-n", "-o", "rw,_netdev,x-systemd.automount,allow_other,ro,cache_lines=8192,pidfile=/run/msfs.pid,backends.directory_page_size=500"})
+	if err != nil {
+		t.Fatalf("parseMountHelperArgs() failed: %v", err)
+	}
+	if fake {
+		t.Fatalf("parseMountHelperArgs() without -f returned fake")
+	}
+	if !slices.Equal(daemonArgs, []string{"/usr/sbin/mount.mscp", "--daemon", "--pidfile", "/run/msfs.pid", "config.yaml"}) {
+		t.Fatalf("parseMountHelperArgs() returned unexpected %v", daemonArgs)
+	}
+	if os.Getenv(EnvMSFSMountPoint) != "/mnt/msc" {
+		t.Fatalf("parseMountHelperArgs() set ${%s} to %q", EnvMSFSMountPoint, os.Getenv(EnvMSFSMountPoint))
+	}
+	if os.Getenv(EnvMSFSConfigOverrides) != "allow_other=true,backends.readonly=true,cache_lines=8192,backends.directory_page_size=500" {
+		t.Fatalf("parseMountHelperArgs() set ${%s} to %q", EnvMSFSConfigOverrides, os.Getenv(EnvMSFSConfigOverrides))
+	}
+
+	_, fake, err = parseMountHelperArgs([]string{"msfs", "mount", "config.yaml", "/mnt/msc", "-fv"})
+	if (err != nil) || !fake {
+		t.Fatalf("parseMountHelperArgs() with -f returned fake: %v err: %v", fake, err)
+	}
+
+	_, _, err = parseMountHelperArgs([]string{"/usr/sbin/mount.mscp", "config.yaml", "/mnt/msc", "-o", "bogus"})
+	if err == nil {
+		t.Fatalf("parseMountHelperArgs() with an unsupported option should have failed")
+	}
+
+	_, _, err = parseMountHelperArgs([]string{"/usr/sbin/mount.mscp", "config.yaml", "/mnt/msc", "-s", "-o", "bogus"})
+	if err != nil {
+		t.Fatalf("parseMountHelperArgs() with -s and an unsupported option failed: %v", err)
+	}
+
+	_, _, err = parseMountHelperArgs([]string{"/usr/sbin/mount.mscp", "config.yaml"})
+	if err == nil {
+		t.Fatalf("parseMountHelperArgs() without a mountpoint should have failed")
+	}
+}
+
+func TestApplyConfigOverrides(t *testing.T) {
+	var (
+		backendAsMap  map[string]interface{}
+		configFileMap map[string]interface{}
+		err           error
+	)
+
+	backendAsMap = map[string]interface{}{"dir_name": "ram", "readonly": false}
+	configFileMap = map[string]interface{}{
+		"mountname": "msfs",
+		"backends":  []interface{}{backendAsMap},
+	}
+
+	t.Setenv(EnvMSFSConfigOverrides, "allow_other=true,cache_lines=8192,mountname=1234,dir_perm=755,backends.readonly=true")
+
+	err = applyConfigOverrides(configFileMap)
+	if err != nil {
+		t.Fatalf("applyConfigOverrides() failed: %v", err)
+	}
+
+	if configFileMap["allow_other"] != true {
+		t.Fatalf("allow_other override returned %#v", configFileMap["allow_other"])
+	}
+	if configFileMap["cache_lines"] != 8192 {
+		t.Fatalf("cache_lines override returned %#v", configFileMap["cache_lines"])
+	}
+	if configFileMap["mountname"] != "1234" {
+		t.Fatalf("mountname override (of a string setting) returned %#v", configFileMap["mountname"])
+	}
+	if configFileMap["dir_perm"] != "755" {
+		t.Fatalf("dir_perm override returned %#v", configFileMap["dir_perm"])
+	}
+	if backendAsMap["readonly"] != true {
+		t.Fatalf("backends.readonly override returned %#v", backendAsMap["readonly"])
+	}
+
+	t.Setenv(EnvMSFSConfigOverrides, "no_equals_sign")
+
+	err = applyConfigOverrides(configFileMap)
+	if err == nil {
+		t.Fatalf("applyConfigOverrides() of a malformed entry should have failed")
+	}
+}
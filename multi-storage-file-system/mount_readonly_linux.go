@@ -0,0 +1,19 @@
+//go:build linux
+
+package main
+
+import "syscall"
+
+// `remountReadOnlySyscall` performs mount(2) with MS_REMOUNT|MS_BIND to toggle MS_RDONLY on
+// the already established mountPoint without disturbing the FUSE session.
+func remountReadOnlySyscall(mountPoint string, readOnly bool) error {
+	var (
+		flags uintptr = syscall.MS_REMOUNT | syscall.MS_BIND
+	)
+
+	if readOnly {
+		flags |= syscall.MS_RDONLY
+	}
+
+	return syscall.Mount("", mountPoint, "", flags, "")
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// `remountReadOnlySyscall` is unsupported on non-Linux platforms, leaving the FUSE callbacks
+// alone to reject modifications with EROFS. MSFS production targets are Linux, so this path
+// is for local dev/test builds (e.g. macOS) only.
+func remountReadOnlySyscall(mountPoint string, readOnly bool) error {
+	return errors.New("read-only remount not supported on this platform")
+}
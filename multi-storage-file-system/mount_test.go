@@ -0,0 +1,203 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+const (
+	testMountsMountPoint = "/msfs-test-mounts/ram"
+)
+
+// `mountsTestUp` is fissionTestUp() but with the "ram" backend presented by one of the mounts
+// (at testMountsMountPoint) rather than at the mountpoint of the config file.
+func mountsTestUp(t *testing.T) {
+	var (
+		err error
+	)
+
+	err = os.Setenv("MSFS_MOUNTPOINT", testGlobals.testMountPoint)
+	if err != nil {
+		t.Fatalf("os.Setenv(\"MSFS_MOUNTPOINT\", testGlobals.testMountPoint) failed: %v", err)
+	}
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".json"]))
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+	{
+		"msfs_version": 1,
+		"mounts": [
+			{
+				"mountpoint": "`+testMountsMountPoint+`",
+				"backends": ["ram"]
+			}
+		],
+		"backends": [
+			{
+				"dir_name": "ram2",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM"
+			},
+			{
+				"dir_name": "ram",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM",
+				"readonly": false
+			}
+		]
+	}
+	`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	err = checkConfigFile()
+	if err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	initFS()
+
+	processToMountList()
+
+	globals.fissionVolume = fission.NewVolume(&fission.VolumeConfig{
+		VolumeName:        globals.config.mountName,
+		MountpointDirPath: globals.config.mountPoint,
+		FuseSubtype:       fuseSubtype,
+		Callbacks:         &globals,
+		Logger:            globals.logger,
+		ErrChan:           globals.errChan,
+	})
+}
+
+// TestFissionMounts verifies that a backend presented by one of the mounts is found in the root
+// directory of that mount (referred to by the kernel as NodeID FUSERootDirInodeNumber) rather
+// than in the root directory of the mount at the mountpoint of the config file.
+func TestFissionMounts(t *testing.T) {
+	var (
+		errno      syscall.Errno
+		lookupOut  *fission.LookupOut
+		mount      *mountStruct
+		names      []string
+		ok         bool
+		openDirOut *fission.OpenDirOut
+		ramDirIno  uint64
+		readDirOut *fission.ReadDirOut
+		rootHeader = &fission.InHeader{NodeID: FUSERootDirInodeNumber}
+	)
+
+	mountsTestUp(t)
+	defer fissionTestDown(t)
+
+	mount, ok = globals.mounts[testMountsMountPoint]
+	if !ok {
+		t.Fatalf("globals.mounts[%q] returned !ok", testMountsMountPoint)
+	}
+
+	_, errno = globals.DoLookup(rootHeader, &fission.LookupIn{Name: []byte("ram")})
+	if errno != syscall.ENOENT {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") returned errno %v (expected: ENOENT)", errno)
+	}
+
+	_, errno = globals.DoLookup(rootHeader, &fission.LookupIn{Name: []byte("ram2")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"ram2\") unexpectedly failed (errno: %v)", errno)
+	}
+
+	_, errno = mount.DoLookup(rootHeader, &fission.LookupIn{Name: []byte("ram2")})
+	if errno != syscall.ENOENT {
+		t.Fatalf("mount.DoLookup(FUSERootDirInodeNumber,Name:\"ram2\") returned errno %v (expected: ENOENT)", errno)
+	}
+
+	lookupOut, errno = mount.DoLookup(rootHeader, &fission.LookupIn{Name: []byte("ram")})
+	if errno != 0 {
+		t.Fatalf("mount.DoLookup(FUSERootDirInodeNumber,Name:\"ram\") unexpectedly failed (errno: %v)", errno)
+	}
+	ramDirIno = lookupOut.EntryOut.NodeID
+	if ramDirIno != stableInodeNumber("ram", "") {
+		t.Fatalf("mount.DoLookup(FUSERootDirInodeNumber,Name:\"ram\") returned NodeID %v (expected: %v)", ramDirIno, stableInodeNumber("ram", ""))
+	}
+
+	_, errno = mount.DoGetAttr(rootHeader, &fission.GetAttrIn{})
+	if errno != 0 {
+		t.Fatalf("mount.DoGetAttr(FUSERootDirInodeNumber) unexpectedly failed (errno: %v)", errno)
+	}
+
+	openDirOut, errno = mount.DoOpenDir(rootHeader, &fission.OpenDirIn{})
+	if errno != 0 {
+		t.Fatalf("mount.DoOpenDir(FUSERootDirInodeNumber) unexpectedly failed (errno: %v)", errno)
+	}
+
+	readDirOut, errno = mount.DoReadDir(rootHeader, &fission.ReadDirIn{FH: openDirOut.FH, Offset: 0, Size: testFissionReadDirBufSize})
+	if errno != 0 {
+		t.Fatalf("mount.DoReadDir(FUSERootDirInodeNumber) unexpectedly failed (errno: %v)", errno)
+	}
+	for _, dirEnt := range readDirOut.DirEnt {
+		names = append(names, string(dirEnt.Name))
+	}
+	if (len(names) != 3) || !slices.Contains(names, "ram") || slices.Contains(names, "ram2") {
+		t.Fatalf("mount.DoReadDir(FUSERootDirInodeNumber) returned %v (expected: \".\", \"..\", and \"ram\")", names)
+	}
+
+	errno = mount.DoReleaseDir(rootHeader, &fission.ReleaseDirIn{FH: openDirOut.FH})
+	if errno != 0 {
+		t.Fatalf("mount.DoReleaseDir(FUSERootDirInodeNumber) unexpectedly failed (errno: %v)", errno)
+	}
+
+	// A new backend may not be created in the root directory of one of the mounts
+
+	_, errno = mount.DoMkDir(rootHeader, &fission.MkDirIn{Name: []byte("scratch")})
+	if errno != syscall.EPERM {
+		t.Fatalf("mount.DoMkDir(FUSERootDirInodeNumber,Name:\"scratch\") returned errno %v (expected: EPERM)", errno)
+	}
+}
+
+// TestBadMountsConfig verifies that mounts naming an unknown backend, a backend already presented
+// by another of the mounts, or a relative or duplicate mountpoint are rejected.
+func TestBadMountsConfig(t *testing.T) {
+	var (
+		err    error
+		mounts string
+	)
+
+	for _, mounts = range []string{
+		`[{"mountpoint": "/a", "backends": ["missing"]}]`,
+		`[{"mountpoint": "/a", "backends": ["ram"]}, {"mountpoint": "/b", "backends": ["ram"]}]`,
+		`[{"mountpoint": "a", "backends": ["ram"]}]`,
+		`[{"mountpoint": "/a", "backends": ["ram"]}, {"mountpoint": "/a/", "backends": ["ram2"]}]`,
+		`[{"mountpoint": "/a", "backends": []}]`,
+	} {
+		initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".json"]))
+
+		err = os.WriteFile(globals.configFilePath, []byte(`
+		{
+			"msfs_version": 1,
+			"mounts": `+mounts+`,
+			"backends": [
+				{
+					"dir_name": "ram2",
+					"bucket_container_name": "ignored",
+					"backend_type": "RAM"
+				},
+				{
+					"dir_name": "ram",
+					"bucket_container_name": "ignored",
+					"backend_type": "RAM"
+				}
+			]
+		}
+		`), 0o600)
+		if err != nil {
+			t.Fatalf("os.WriteFile() failed: %v", err)
+		}
+
+		err = checkConfigFile()
+		if err == nil {
+			t.Fatalf("checkConfigFile() unexpectedly allowed mounts: %s", mounts)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package msc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// `Client` resolves msc:// URLs against the profiles of a config file.
+// A Client is safe for concurrent use.
+type Client struct {
+	sync.Mutex
+	configFilePath string
+	profiles       map[string]*profileStruct
+	s3Clients      map[string]*s3.Client // Key == profileStruct.name; lazily populated
+}
+
+// `ObjectInfo` describes an object.
+type ObjectInfo struct {
+	Key          string // Relative to the profile's base_path
+	ETag         string
+	LastModified time.Time
+	Size         int64
+}
+
+// `Object` provides read access to an object returned by Client.Open(). Reads are
+// satisfied by ranged GETs conditioned on the ETag observed at Open() time such that
+// a concurrently replaced object results in an error rather than mixed content.
+type Object struct {
+	ObjectInfo
+	s3Client *s3.Client
+	bucket   string
+	fullKey  string
+	offset   int64 // Position used by Read() and Seek()
+}
+
+// `NewClient` returns a Client for the profiles in the config file at configFilePath
+// or, if configFilePath is empty, the config file found via FindConfigFile().
+func NewClient(configFilePath string) (client *Client, err error) {
+	configFilePath, err = FindConfigFile(configFilePath)
+	if err != nil {
+		return
+	}
+
+	client = &Client{
+		configFilePath: configFilePath,
+		s3Clients:      make(map[string]*s3.Client),
+	}
+
+	client.profiles, err = parseConfigFile(configFilePath)
+	if err != nil {
+		client = nil
+	}
+
+	return
+}
+
+// `ConfigFilePath` returns the path of the config file in use.
+func (client *Client) ConfigFilePath() string {
+	return client.configFilePath
+}
+
+// `ParseURL` splits an msc://profile/path URL into its profile name and path.
+func ParseURL(mscURL string) (profileName string, path string, err error) {
+	var (
+		parsedURL *url.URL
+	)
+
+	parsedURL, err = url.Parse(mscURL)
+	if err != nil {
+		err = fmt.Errorf("unable to parse \"%s\": %v", mscURL, err)
+		return
+	}
+	if parsedURL.Scheme != "msc" {
+		err = fmt.Errorf("unsupported scheme in \"%s\" - must be \"msc\"", mscURL)
+		return
+	}
+	if parsedURL.Host == "" {
+		err = fmt.Errorf("missing profile in \"%s\"", mscURL)
+		return
+	}
+
+	profileName = parsedURL.Host
+	path = strings.TrimPrefix(parsedURL.Path, "/")
+
+	return
+}
+
+// `Stat` returns the ObjectInfo for the object at mscURL.
+func (client *Client) Stat(ctx context.Context, mscURL string) (objectInfo *ObjectInfo, err error) {
+	var (
+		headObjectOutput *s3.HeadObjectOutput
+		path             string
+		profile          *profileStruct
+		s3Client         *s3.Client
+	)
+
+	profile, path, s3Client, err = client.resolve(ctx, mscURL)
+	if err != nil {
+		return
+	}
+
+	headObjectOutput, err = s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(profile.bucket),
+		Key:    aws.String(profile.prefix + path),
+	})
+	if err != nil {
+		err = fmt.Errorf("unable to stat \"%s\": %v", mscURL, err)
+		return
+	}
+
+	objectInfo = &ObjectInfo{
+		Key:  path,
+		ETag: strings.Trim(aws.ToString(headObjectOutput.ETag), "\""),
+		Size: aws.ToInt64(headObjectOutput.ContentLength),
+	}
+	if headObjectOutput.LastModified != nil {
+		objectInfo.LastModified = *headObjectOutput.LastModified
+	}
+
+	return
+}
+
+// `Open` returns an Object from which the content of the object at mscURL may be read.
+func (client *Client) Open(mscURL string) (object *Object, err error) {
+	var (
+		ctx        = context.Background()
+		objectInfo *ObjectInfo
+		path       string
+		profile    *profileStruct
+		s3Client   *s3.Client
+	)
+
+	profile, path, s3Client, err = client.resolve(ctx, mscURL)
+	if err != nil {
+		return
+	}
+
+	objectInfo, err = client.Stat(ctx, mscURL)
+	if err != nil {
+		return
+	}
+
+	object = &Object{
+		ObjectInfo: *objectInfo,
+		s3Client:   s3Client,
+		bucket:     profile.bucket,
+		fullKey:    profile.prefix + path,
+	}
+
+	return
+}
+
+// `resolve` returns the profile, path, and S3 client with which to access mscURL.
+func (client *Client) resolve(ctx context.Context, mscURL string) (profile *profileStruct, path string, s3Client *s3.Client, err error) {
+	var (
+		configOptions []func(*config.LoadOptions) error
+		ok            bool
+		profileName   string
+		s3Config      aws.Config
+	)
+
+	profileName, path, err = ParseURL(mscURL)
+	if err != nil {
+		return
+	}
+
+	client.Lock()
+	defer client.Unlock()
+
+	profile, ok = client.profiles[profileName]
+	if !ok {
+		err = fmt.Errorf("profile \"%s\" not found (or not of a supported storage_provider type) in \"%s\"", profileName, client.configFilePath)
+		return
+	}
+
+	s3Client, ok = client.s3Clients[profileName]
+	if ok {
+		return
+	}
+
+	if profile.useConfigEnv {
+		configOptions = append(configOptions, config.WithSharedConfigProfile(""))
+	} else {
+		configOptions = append(configOptions, config.WithSharedConfigFiles(nil), config.WithRegion(profile.region))
+	}
+
+	if !profile.useCredentialsEnv {
+		configOptions = append(configOptions, config.WithSharedCredentialsFiles(nil), config.WithCredentialsProvider(credentials.StaticCredentialsProvider{
+			Value: aws.Credentials{
+				AccessKeyID:     profile.accessKeyID,
+				SecretAccessKey: profile.secretAccessKey,
+			}}))
+	}
+
+	s3Config, err = config.LoadDefaultConfig(ctx, configOptions...)
+	if err != nil {
+		err = fmt.Errorf("config.LoadDefaultConfig() for profile \"%s\" failed: %v", profileName, err)
+		return
+	}
+
+	s3Client = s3.NewFromConfig(s3Config, func(o *s3.Options) {
+		if !profile.useConfigEnv && (profile.endpoint != "") {
+			o.BaseEndpoint = aws.String(profile.endpoint)
+		}
+		o.UsePathStyle = true
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	})
+
+	client.s3Clients[profileName] = s3Client
+
+	return
+}
+
+// `ReadAt` implements io.ReaderAt.
+func (object *Object) ReadAt(p []byte, off int64) (n int, err error) {
+	var (
+		getObjectOutput *s3.GetObjectOutput
+		length          int64
+	)
+
+	if off < 0 {
+		err = errors.New("negative offset")
+		return
+	}
+	if off >= object.Size {
+		err = io.EOF
+		return
+	}
+	if len(p) == 0 {
+		return
+	}
+
+	length = int64(len(p))
+	if off+length > object.Size {
+		length = object.Size - off
+	}
+
+	getObjectOutput, err = object.s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket:  aws.String(object.bucket),
+		Key:     aws.String(object.fullKey),
+		Range:   aws.String(fmt.Sprintf("bytes=%d-%d", off, off+length-1)),
+		IfMatch: aws.String(object.ETag),
+	})
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = getObjectOutput.Body.Close()
+	}()
+
+	n, err = io.ReadFull(getObjectOutput.Body, p[:length])
+	if (err == nil) && (int64(n) < int64(len(p))) {
+		err = io.EOF
+	}
+
+	return
+}
+
+// `Read` implements io.Reader.
+func (object *Object) Read(p []byte) (n int, err error) {
+	n, err = object.ReadAt(p, object.offset)
+	object.offset += int64(n)
+	if (err == io.EOF) && (n > 0) {
+		err = nil
+	}
+	return
+}
+
+// `Seek` implements io.Seeker.
+func (object *Object) Seek(offset int64, whence int) (newOffset int64, err error) {
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = object.offset + offset
+	case io.SeekEnd:
+		newOffset = object.Size + offset
+	default:
+		err = fmt.Errorf("invalid whence (%d)", whence)
+		return
+	}
+
+	if newOffset < 0 {
+		err = errors.New("negative position")
+		return
+	}
+
+	object.offset = newOffset
+
+	return
+}
+
+// `Close` implements io.Closer. As no resources are held between reads, it is a no-op.
+func (object *Object) Close() (err error) {
+	return
+}
@@ -0,0 +1,202 @@
+package msc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseURL(t *testing.T) {
+	for _, testCase := range []struct {
+		mscURL      string
+		profileName string
+		path        string
+		ok          bool
+	}{
+		{"msc://profile/dir/file.bin", "profile", "dir/file.bin", true},
+		{"msc://profile/", "profile", "", true},
+		{"msc://profile", "profile", "", true},
+		{"s3://bucket/file.bin", "", "", false},
+		{"msc:///file.bin", "", "", false},
+	} {
+		profileName, path, err := ParseURL(testCase.mscURL)
+		if testCase.ok {
+			if err != nil {
+				t.Errorf("ParseURL(\"%s\") unexpectedly failed: %v", testCase.mscURL, err)
+			} else if (profileName != testCase.profileName) || (path != testCase.path) {
+				t.Errorf("ParseURL(\"%s\") returned (\"%s\",\"%s\")", testCase.mscURL, profileName, path)
+			}
+		} else if err == nil {
+			t.Errorf("ParseURL(\"%s\") unexpectedly succeeded", testCase.mscURL)
+		}
+	}
+}
+
+func TestFindConfigFile(t *testing.T) {
+	var (
+		err            error
+		homeDir        = t.TempDir()
+		configFilePath string
+		xdgDir         = t.TempDir()
+	)
+
+	t.Setenv("MSC_CONFIG", "")
+	t.Setenv("HOME", homeDir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CONFIG_DIRS", xdgDir)
+
+	_, err = FindConfigFile("")
+	if err == nil {
+		t.Fatalf("FindConfigFile(\"\") unexpectedly succeeded with no config file present")
+	}
+
+	err = os.MkdirAll(filepath.Join(xdgDir, "msc"), 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(xdgDir, "msc", "config.json"), []byte("{}"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configFilePath, err = FindConfigFile("")
+	if (err != nil) || (configFilePath != filepath.Join(xdgDir, "msc", "config.json")) {
+		t.Fatalf("FindConfigFile(\"\") returned (\"%s\",%v)", configFilePath, err)
+	}
+
+	err = os.WriteFile(filepath.Join(homeDir, ".msc_config.yml"), []byte("{}"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configFilePath, err = FindConfigFile("")
+	if (err != nil) || (configFilePath != filepath.Join(homeDir, ".msc_config.yml")) {
+		t.Fatalf("FindConfigFile(\"\") should have preferred ${HOME}/.msc_config.yml but returned (\"%s\",%v)", configFilePath, err)
+	}
+
+	t.Setenv("MSC_CONFIG", filepath.Join(homeDir, "missing.yaml"))
+
+	_, err = FindConfigFile("")
+	if err == nil {
+		t.Fatalf("FindConfigFile(\"\") unexpectedly succeeded with ${MSC_CONFIG} referencing a missing file")
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	var (
+		configFilePath = filepath.Join(t.TempDir(), "config.yaml")
+		err            error
+	)
+
+	t.Setenv("TEST_SECRET_KEY", "secret")
+
+	err = os.WriteFile(configFilePath, []byte(`
+profiles:
+  data:
+    storage_provider:
+      type: s3
+      options:
+        base_path: bucket/some/prefix
+        endpoint_url: http://localhost:9000
+        region_name: us-west-2
+    credentials_provider:
+      type: S3Credentials
+      options:
+        access_key: access
+        secret_key: ${TEST_SECRET_KEY}
+  envonly:
+    storage_provider:
+      type: s8k
+      options:
+        base_path: other-bucket
+  local:
+    storage_provider:
+      type: file
+      options:
+        base_path: /tmp
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(configFilePath)
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+
+	data, ok := client.profiles["data"]
+	if !ok {
+		t.Fatalf("profile \"data\" missing")
+	}
+	if (data.bucket != "bucket") || (data.prefix != "some/prefix/") || (data.endpoint != "http://localhost:9000") || (data.region != "us-west-2") {
+		t.Fatalf("profile \"data\" storage_provider mis-parsed: %+v", data)
+	}
+	if data.useConfigEnv || data.useCredentialsEnv || (data.accessKeyID != "access") || (data.secretAccessKey != "secret") {
+		t.Fatalf("profile \"data\" credentials_provider mis-parsed: %+v", data)
+	}
+
+	envOnly, ok := client.profiles["envonly"]
+	if !ok {
+		t.Fatalf("profile \"envonly\" missing")
+	}
+	if (envOnly.bucket != "other-bucket") || (envOnly.prefix != "") || !envOnly.useConfigEnv || !envOnly.useCredentialsEnv {
+		t.Fatalf("profile \"envonly\" mis-parsed: %+v", envOnly)
+	}
+
+	_, ok = client.profiles["local"]
+	if ok {
+		t.Fatalf("profile \"local\" should have been skipped")
+	}
+
+	_, err = client.Open("msc://local/file")
+	if err == nil {
+		t.Fatalf("Open(\"msc://local/file\") unexpectedly succeeded")
+	}
+}
+
+func TestTranslateProfile(t *testing.T) {
+	var (
+		backendAsMap map[string]interface{}
+		err          error
+		skipReason   string
+	)
+
+	backendAsMap, skipReason, err = TranslateProfile("data", map[string]interface{}{
+		"storage_provider": map[string]interface{}{
+			"type": "s3",
+			"options": map[string]interface{}{
+				"base_path":           "bucket/prefix",
+				"multipart_chunksize": "16M",
+			},
+		},
+	}, 8*1024*1024)
+	if (err != nil) || (skipReason != "") {
+		t.Fatalf("TranslateProfile() returned (\"%s\",%v)", skipReason, err)
+	}
+	if (backendAsMap["backend_type"] != "S3") || (backendAsMap["bucket_container_name"] != "bucket") || (backendAsMap["prefix"] != "prefix/") || (backendAsMap["upload_part_cache_lines"] != uint64(2)) {
+		t.Fatalf("TranslateProfile() mis-translated: %+v", backendAsMap)
+	}
+
+	_, skipReason, err = TranslateProfile("local", map[string]interface{}{
+		"storage_provider": map[string]interface{}{
+			"type":    "file",
+			"options": map[string]interface{}{"base_path": "/tmp"},
+		},
+	}, 0)
+	if (err != nil) || (skipReason == "") {
+		t.Fatalf("TranslateProfile() should have skipped a \"file\" profile but returned (\"%s\",%v)", skipReason, err)
+	}
+
+	_, _, err = TranslateProfile("bad", map[string]interface{}{
+		"storage_provider": map[string]interface{}{
+			"type": "s3",
+			"options": map[string]interface{}{
+				"base_path":   "bucket",
+				"rust_client": map[string]interface{}{"max_concurrency": 0},
+			},
+		},
+	}, 8*1024*1024)
+	if err == nil {
+		t.Fatalf("TranslateProfile() should have rejected a max_concurrency of 0")
+	}
+}
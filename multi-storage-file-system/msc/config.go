@@ -0,0 +1,814 @@
+// Package msc provides library-level access to the objects reachable via the
+// profiles of a Multi-Storage Client configuration file. It resolves msc://
+// URLs the same way as both the Python library and the MSFS daemon such that
+// Go tools may read objects without going through a FUSE mount.
+package msc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drone/envsubst"
+	"gopkg.in/yaml.v3"
+)
+
+// `secretCommandTimeout` bounds the time a from_command secret reference may take (see ParseSecret()).
+const secretCommandTimeout = 10 * time.Second
+
+// `profileStruct` holds the settings of a single profile of a config file
+// as needed to access its storage provider.
+type profileStruct struct {
+	name              string
+	bucket            string
+	prefix            string // If != "", ends with a trailing "/"
+	endpoint          string // Only applicable if !useConfigEnv
+	region            string // Only applicable if !useConfigEnv
+	accessKeyID       string // Only applicable if !useCredentialsEnv
+	secretAccessKey   string // Only applicable if !useCredentialsEnv
+	useConfigEnv      bool
+	useCredentialsEnv bool
+}
+
+// `FindConfigFile` locates the config file to use. If explicitConfigFilePath is
+// not empty, it must be readable and is returned. Otherwise, the following are
+// searched (in order) with the first found being returned:
+//
+//	${MSC_CONFIG}
+//	${XDG_CONFIG_HOME}/msc/config.{yaml|yml|json}
+//	${HOME}/.msc_config.{yaml|yml|json}
+//	${HOME}/.config/msc/config.{yaml|yml|json}
+//	${XDG_CONFIG_DIRS:-/etc/xdg}/msc/config.{yaml|yml|json}
+//	/etc/msc_config.{yaml|yml|json}
+func FindConfigFile(explicitConfigFilePath string) (configFilePath string, err error) {
+	var (
+		candidate        string
+		homeEnv          = os.Getenv("HOME")
+		mscConfigEnv     = os.Getenv("MSC_CONFIG")
+		searchDirs       []string
+		searchPaths      []string
+		xdgConfigDir     string
+		xdgConfigDirsEnv = os.Getenv("XDG_CONFIG_DIRS")
+		xdgConfigHomeEnv = os.Getenv("XDG_CONFIG_HOME")
+	)
+
+	if explicitConfigFilePath != "" {
+		if !checkForFile(explicitConfigFilePath) {
+			err = fmt.Errorf("file not readable at \"%s\"", explicitConfigFilePath)
+			return
+		}
+		configFilePath = explicitConfigFilePath
+		return
+	}
+
+	if mscConfigEnv != "" {
+		if !checkForFile(mscConfigEnv) {
+			err = fmt.Errorf("file not readable at non-empty ${MSC_CONFIG} [\"%s\"]", mscConfigEnv)
+			return
+		}
+		configFilePath = mscConfigEnv
+		return
+	}
+
+	if xdgConfigHomeEnv != "" {
+		searchPaths = append(searchPaths, configFileCandidates(xdgConfigHomeEnv+"/msc/config")...)
+	}
+
+	if homeEnv != "" {
+		searchPaths = append(searchPaths, configFileCandidates(homeEnv+"/.msc_config")...)
+		searchPaths = append(searchPaths, configFileCandidates(homeEnv+"/.config/msc/config")...)
+	}
+
+	if xdgConfigDirsEnv == "" {
+		searchDirs = []string{"/etc/xdg"}
+	} else {
+		searchDirs = strings.Split(xdgConfigDirsEnv, ":")
+	}
+	for _, xdgConfigDir = range searchDirs {
+		searchPaths = append(searchPaths, configFileCandidates(xdgConfigDir+"/msc/config")...)
+	}
+
+	searchPaths = append(searchPaths, configFileCandidates("/etc/msc_config")...)
+
+	for _, candidate = range searchPaths {
+		if checkForFile(candidate) {
+			configFilePath = candidate
+			return
+		}
+	}
+
+	err = errors.New("config-file not found along search path")
+	return
+}
+
+// `configFileCandidates` returns the supported config file suffixes, in
+// search order, appended to pathWithoutSuffix.
+func configFileCandidates(pathWithoutSuffix string) (candidates []string) {
+	candidates = []string{
+		pathWithoutSuffix + ".yaml",
+		pathWithoutSuffix + ".yml",
+		pathWithoutSuffix + ".json",
+	}
+	return
+}
+
+// `checkForFile` indicates whether or not a file exists at filePath.
+func checkForFile(filePath string) (ok bool) {
+	fileInfo, err := os.Stat(filePath)
+	ok = (err == nil && !fileInfo.IsDir())
+	return
+}
+
+// `parseConfigFile` reads the profiles section of the config file at configFilePath. Each
+// profile is translated by TranslateProfile() just as the MSFS daemon does. Only those yielding
+// an "S3" backend_type are returned... others are skipped.
+func parseConfigFile(configFilePath string) (profiles map[string]*profileStruct, err error) {
+	var (
+		backendAsMap         map[string]interface{}
+		backendConfigS3AsMap map[string]interface{}
+		configFileContent    []byte
+		configFileMap        map[string]interface{}
+		configFilePathExt    string
+		ok                   bool
+		profile              *profileStruct
+		profileAsInterface   interface{}
+		profileAsMap         map[string]interface{}
+		profileName          string
+		profilesAsMap        map[string]interface{}
+		skipReason           string
+	)
+
+	configFileContent, err = os.ReadFile(configFilePath)
+	if err != nil {
+		err = fmt.Errorf("unable to read config-file: %v", err)
+		return
+	}
+
+	configFileMap = make(map[string]interface{})
+
+	configFilePathExt = filepath.Ext(configFilePath)
+
+	switch configFilePathExt {
+	case ".json":
+		err = json.Unmarshal(configFileContent, &configFileMap)
+		if err != nil {
+			err = fmt.Errorf("unable to parse config-file \"%s\" as JSON (err: %v)", configFilePath, err)
+			return
+		}
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(configFileContent, &configFileMap)
+		if err != nil {
+			err = fmt.Errorf("unable to parse config-file \"%s\" as YAML (err: %v)", configFilePath, err)
+			return
+		}
+	default:
+		err = fmt.Errorf("unsupported extension (\"%s\") in config-file \"%s\" - must be one of \".json\" or \".yaml\"", configFilePathExt, configFilePath)
+		return
+	}
+
+	profiles = make(map[string]*profileStruct)
+
+	if configFileMap["profiles"] == nil {
+		return
+	}
+	profilesAsMap, ok = configFileMap["profiles"].(map[string]interface{})
+	if !ok {
+		err = errors.New("bad profiles section")
+		return
+	}
+
+	for profileName, profileAsInterface = range profilesAsMap {
+		profileAsMap, ok = profileAsInterface.(map[string]interface{})
+		if !ok {
+			err = fmt.Errorf("bad profile \"%s\"", profileName)
+			return
+		}
+
+		backendAsMap, skipReason, err = TranslateProfile(profileName, profileAsMap, 0)
+		if err != nil {
+			return
+		}
+		if (skipReason != "") || (backendAsMap["backend_type"] != "S3") {
+			continue
+		}
+
+		backendConfigS3AsMap = backendAsMap["S3"].(map[string]interface{})
+
+		profile = &profileStruct{
+			name:              profileName,
+			bucket:            backendAsMap["bucket_container_name"].(string),
+			prefix:            backendAsMap["prefix"].(string),
+			useConfigEnv:      backendConfigS3AsMap["use_config_env"].(bool),
+			useCredentialsEnv: backendConfigS3AsMap["use_credentials_env"].(bool),
+		}
+
+		if !profile.useConfigEnv {
+			profile.region, ok = backendConfigS3AsMap["region"].(string)
+			if !ok {
+				profile.region, _ = parseString(backendConfigS3AsMap, "region", "${AWS_REGION:-us-east-1}") // The MSFS S3 backend's default
+			}
+			profile.endpoint, _ = backendConfigS3AsMap["endpoint"].(string)
+		}
+
+		if !profile.useCredentialsEnv {
+			profile.accessKeyID, _ = backendConfigS3AsMap["access_key_id"].(string)
+			profile.secretAccessKey, _ = backendConfigS3AsMap["secret_access_key"].(string)
+		}
+
+		profiles[profileName] = profile
+	}
+
+	return
+}
+
+// `TranslateProfile` maps a profile of a config file onto the equivalent MSFS backend section
+// (in the form accepted by the MSFS config file schema). It is shared by the MSFS daemon and
+// Client such that both resolve a given profile identically. A profile lacking a corresponding
+// backend_type returns a non-empty skipReason (e.g. "with storage_provider \"file\"") rather than
+// an error. If cacheLineSize != 0, the transfer tuning options of the storage_provider are also
+// translated (see translateStorageProviderOptions()).
+func TranslateProfile(profileName string, profileAsMap map[string]interface{}, cacheLineSize uint64) (backendAsMap map[string]interface{}, skipReason string, err error) {
+	var (
+		backendConfigS3AsMap                  map[string]interface{}
+		credentialsProviderAsInterface        interface{}
+		credentialsProviderAsMap              map[string]interface{}
+		credentialsProviderOptionsAccessKey   string
+		credentialsProviderOptionsAsInterface interface{}
+		credentialsProviderOptionsAsMap       map[string]interface{}
+		credentialsProviderOptionsSecretKey   string
+		credentialsProviderType               string
+		ok                                    bool
+		storageProviderAsInterface            interface{}
+		storageProviderAsMap                  map[string]interface{}
+		storageProviderOptionsAsInterface     interface{}
+		storageProviderOptionsAsMap           map[string]interface{}
+		storageProviderOptionsBasePath        string
+		storageProviderOptionsBasePathPrefix  string
+		storageProviderOptionsBasePathSplit   []string
+		storageProviderOptionsEndpointURL     string
+		storageProviderOptionsRegionName      string
+		storageProviderType                   string
+	)
+
+	storageProviderAsInterface, ok = profileAsMap["storage_provider"]
+	if !ok {
+		skipReason = "with no storage_provider"
+		return
+	}
+	storageProviderAsMap, ok = storageProviderAsInterface.(map[string]interface{})
+	if !ok {
+		err = fmt.Errorf("bad profile \"%s\" storage_provider", profileName)
+		return
+	}
+
+	storageProviderType, ok = parseString(storageProviderAsMap, "type", nil)
+	if !ok {
+		err = fmt.Errorf("missing or bad profile \"%s\" storage_provider type", profileName)
+		return
+	}
+	switch storageProviderType {
+	case "s3":
+		// This one is supported
+	case "s8k":
+		// This is compatible with "s3", so simply operate as if storageProviderType == "s3"
+	case "gcs":
+		// This one is supported (see translateGCSProfile())
+	default:
+		// Note that "azure", "oci", and "file" lack a corresponding backend_type
+		skipReason = fmt.Sprintf("with storage_provider \"%s\"", storageProviderType)
+		return
+	}
+
+	backendAsMap = make(map[string]interface{})
+
+	backendAsMap["dir_name"] = profileName
+
+	storageProviderOptionsAsInterface, ok = storageProviderAsMap["options"]
+	if !ok {
+		err = fmt.Errorf("missing profile \"%s\" storage_provider options", profileName)
+		return
+	}
+	storageProviderOptionsAsMap, ok = storageProviderOptionsAsInterface.(map[string]interface{})
+	if !ok {
+		err = fmt.Errorf("bad profile \"%s\" storage_provider options", profileName)
+		return
+	}
+
+	storageProviderOptionsBasePath, ok = parseString(storageProviderOptionsAsMap, "base_path", nil)
+	if !ok || (storageProviderOptionsBasePath == "") {
+		err = fmt.Errorf("missing or bad profile \"%s\" storage_provider options base_path", profileName)
+		return
+	}
+
+	storageProviderOptionsBasePathSplit = strings.Split(storageProviderOptionsBasePath, "/")
+	backendAsMap["bucket_container_name"] = storageProviderOptionsBasePathSplit[0]
+	backendAsMap["prefix"] = ""
+	if len(storageProviderOptionsBasePathSplit) > 1 {
+		storageProviderOptionsBasePathPrefix = strings.Join(storageProviderOptionsBasePathSplit[1:], "/")
+		if (storageProviderOptionsBasePathPrefix != "") && !strings.HasSuffix(storageProviderOptionsBasePathPrefix, "/") {
+			storageProviderOptionsBasePathPrefix += "/"
+		}
+		backendAsMap["prefix"] = storageProviderOptionsBasePathPrefix
+	}
+
+	if cacheLineSize != 0 {
+		err = translateStorageProviderOptions(storageProviderOptionsAsMap, cacheLineSize, backendAsMap)
+		if err != nil {
+			err = fmt.Errorf("bad profile \"%s\" storage_provider options %v", profileName, err)
+			return
+		}
+	}
+
+	if storageProviderType == "gcs" {
+		err = translateGCSProfile(profileAsMap, storageProviderOptionsAsMap, backendAsMap)
+		if err != nil {
+			err = fmt.Errorf("bad profile \"%s\" %v", profileName, err)
+		}
+		return
+	}
+
+	backendConfigS3AsMap = make(map[string]interface{})
+
+	if parseAnyOf(storageProviderOptionsAsMap, []string{"endpoint_url", "region_name"}) {
+		backendConfigS3AsMap["use_config_env"] = false // The default
+
+		storageProviderOptionsRegionName, ok = parseString(storageProviderOptionsAsMap, "region_name", "")
+		if !ok {
+			err = fmt.Errorf("bad profile \"%s\" storage_provider options region_name", profileName)
+			return
+		}
+		if storageProviderOptionsRegionName != "" {
+			backendConfigS3AsMap["region"] = storageProviderOptionsRegionName
+		}
+
+		storageProviderOptionsEndpointURL, ok = parseString(storageProviderOptionsAsMap, "endpoint_url", "${AWS_ENDPOINT}")
+		if !ok {
+			err = fmt.Errorf("bad profile \"%s\" storage_provider options endpoint_url", profileName)
+			return
+		}
+		if storageProviderOptionsEndpointURL != "" {
+			backendConfigS3AsMap["endpoint"] = storageProviderOptionsEndpointURL
+		}
+	} else { // !parseAnyOf(storageProviderOptionsAsMap, []string{"endpoint_url", "region_name"})
+		backendConfigS3AsMap["use_config_env"] = true
+	}
+
+	credentialsProviderAsInterface, ok = profileAsMap["credentials_provider"]
+	if ok {
+		backendConfigS3AsMap["use_credentials_env"] = false // The default
+
+		credentialsProviderAsMap, ok = credentialsProviderAsInterface.(map[string]interface{})
+		if !ok {
+			err = fmt.Errorf("bad profile \"%s\" credentials_provider", profileName)
+			return
+		}
+
+		credentialsProviderType, ok = parseString(credentialsProviderAsMap, "type", nil)
+		if !ok {
+			err = fmt.Errorf("missing or bad profile \"%s\" credentials_provider type", profileName)
+			return
+		}
+		if credentialsProviderType != "S3Credentials" {
+			err = fmt.Errorf("bad profile \"%s\" credentials_provider type (\"%s\") - must be \"S3Credentials\"", profileName, credentialsProviderType)
+			return
+		}
+
+		credentialsProviderOptionsAsInterface, ok = credentialsProviderAsMap["options"]
+		if !ok {
+			err = fmt.Errorf("missing profile \"%s\" credentials_provider options", profileName)
+			return
+		}
+		credentialsProviderOptionsAsMap, ok = credentialsProviderOptionsAsInterface.(map[string]interface{})
+		if !ok {
+			err = fmt.Errorf("bad profile \"%s\" credentials_provider options", profileName)
+			return
+		}
+
+		credentialsProviderOptionsAccessKey, err = ParseSecret(credentialsProviderOptionsAsMap, "access_key", "")
+		if err != nil {
+			err = fmt.Errorf("bad profile \"%s\" credentials_provider options access_key: %v", profileName, err)
+			return
+		}
+		if credentialsProviderOptionsAccessKey != "" {
+			backendConfigS3AsMap["access_key_id"] = credentialsProviderOptionsAccessKey
+		}
+
+		credentialsProviderOptionsSecretKey, err = ParseSecret(credentialsProviderOptionsAsMap, "secret_key", "")
+		if err != nil {
+			err = fmt.Errorf("bad profile \"%s\" credentials_provider options secret_key: %v", profileName, err)
+			return
+		}
+		if credentialsProviderOptionsSecretKey != "" {
+			backendConfigS3AsMap["secret_access_key"] = credentialsProviderOptionsSecretKey
+		}
+	} else { // profileAsMap["credentials_provider"] returned !ok
+		backendConfigS3AsMap["use_credentials_env"] = true
+	}
+
+	backendAsMap["backend_type"] = "S3"
+	backendAsMap["S3"] = backendConfigS3AsMap
+
+	return
+}
+
+// `ParseSize` converts a Python MSC size setting (e.g. 500M, 10G, or 1TB) into bytes. Note
+// that, as for Python MSC, unit multipliers are powers of 1024. A value lacking a unit is in bytes.
+func ParseSize(sizeAsInterface interface{}) (size uint64, ok bool) {
+	var (
+		err          error
+		multiplier   uint64
+		sizeAsString string
+		unitIndex    int
+	)
+
+	switch sizeAsTyped := sizeAsInterface.(type) {
+	case int:
+		if sizeAsTyped < 0 {
+			ok = false
+			return
+		}
+		size = uint64(sizeAsTyped)
+		ok = true
+		return
+	case float64:
+		if (sizeAsTyped < 0) || (sizeAsTyped != math.Trunc(sizeAsTyped)) {
+			ok = false
+			return
+		}
+		size = uint64(sizeAsTyped)
+		ok = true
+		return
+	case string:
+		sizeAsString = strings.ToUpper(strings.TrimSpace(sizeAsTyped))
+	default:
+		ok = false
+		return
+	}
+
+	sizeAsString = strings.TrimSuffix(strings.TrimSuffix(sizeAsString, "B"), "I")
+
+	unitIndex = strings.IndexAny(sizeAsString, "KMGTP")
+	switch {
+	case unitIndex == -1:
+		multiplier = 1
+	case unitIndex != len(sizeAsString)-1:
+		ok = false
+		return
+	default:
+		multiplier = uint64(1) << (10 * (strings.IndexByte("KMGTP", sizeAsString[unitIndex]) + 1))
+		sizeAsString = strings.TrimSpace(sizeAsString[:unitIndex])
+	}
+
+	size, err = strconv.ParseUint(sizeAsString, 10, 64)
+	if (err != nil) || (size > (math.MaxUint64 / multiplier)) {
+		ok = false
+		return
+	}
+
+	size *= multiplier
+	ok = true
+	return
+}
+
+// `translateGCSProfile` maps a Python MSC "gcs" profile onto a GCS backend (whose
+// bucket_container_name and prefix have already been derived from base_path):
+//
+//	storage_provider options endpoint_url => GCS.endpoint
+//
+// The project_id option is not needed. As the GCS backend supports only anonymous (or API key)
+// access, a profile specifying a credentials_provider is rejected rather than silently mounted
+// (or skipped) without the credentials it asked for.
+func translateGCSProfile(profileAsMap map[string]interface{}, storageProviderOptionsAsMap map[string]interface{}, backendAsMap map[string]interface{}) (err error) {
+	var (
+		backendConfigGCSAsMap             map[string]interface{}
+		credentialsProviderAsInterface    interface{}
+		credentialsProviderAsMap          map[string]interface{}
+		credentialsProviderType           string
+		ok                                bool
+		storageProviderOptionsEndpointURL string
+	)
+
+	credentialsProviderAsInterface, ok = profileAsMap["credentials_provider"]
+	if ok && (credentialsProviderAsInterface != nil) {
+		credentialsProviderAsMap, ok = credentialsProviderAsInterface.(map[string]interface{})
+		if !ok {
+			err = errors.New("credentials_provider")
+			return
+		}
+		credentialsProviderType, ok = parseString(credentialsProviderAsMap, "type", nil)
+		if !ok {
+			err = errors.New("credentials_provider type")
+			return
+		}
+		err = fmt.Errorf("credentials_provider type (\"%s\") not supported with storage_provider \"gcs\" - the GCS backend supports only anonymous or API key (GCS.api_key) access", credentialsProviderType)
+		return
+	}
+
+	backendConfigGCSAsMap = make(map[string]interface{})
+
+	storageProviderOptionsEndpointURL, ok = parseString(storageProviderOptionsAsMap, "endpoint_url", "")
+	if !ok {
+		err = errors.New("storage_provider options endpoint_url")
+		return
+	}
+	if storageProviderOptionsEndpointURL != "" {
+		backendConfigGCSAsMap["endpoint"] = storageProviderOptionsEndpointURL
+	}
+
+	backendAsMap["backend_type"] = "GCS"
+	backendAsMap["GCS"] = backendConfigGCSAsMap
+
+	return
+}
+
+// `translateStorageProviderOptions` maps the Python MSC transfer tuning options of a
+// profile's storage_provider options (or, taking precedence, of its rust_client sub-section)
+// onto the equivalent backend settings:
+//
+//	multipart_threshold                => multipart_cache_line_threshold (i.e. multipart_threshold / cache_line_size)
+//	multipart_chunksize (or part_size) => upload_part_cache_lines (i.e. multipart_chunksize / cache_line_size)
+//	max_concurrency                    => upload_part_concurrency
+//
+// Sizes are rounded up to a whole number of cache lines. Other options are ignored.
+func translateStorageProviderOptions(storageProviderOptionsAsMap map[string]interface{}, cacheLineSize uint64, backendAsMap map[string]interface{}) (err error) {
+	var (
+		maxConcurrency        uint64
+		multipartThreshold    uint64
+		ok                    bool
+		optionsAsMap          map[string]interface{}
+		optionsPrefix         string
+		partSize              uint64
+		partSizeKey           string
+		rustClientAsInterface interface{}
+		rustClientAsMap       map[string]interface{}
+	)
+
+	optionsAsMap = storageProviderOptionsAsMap
+
+	rustClientAsInterface, ok = storageProviderOptionsAsMap["rust_client"]
+	if ok {
+		rustClientAsMap, ok = rustClientAsInterface.(map[string]interface{})
+		if !ok {
+			err = errors.New("rust_client")
+			return
+		}
+	}
+
+	for {
+		if parseAny(optionsAsMap, "multipart_threshold") {
+			multipartThreshold, ok = ParseSize(optionsAsMap["multipart_threshold"])
+			if !ok {
+				err = fmt.Errorf("%smultipart_threshold", optionsPrefix)
+				return
+			}
+			backendAsMap["multipart_cache_line_threshold"] = (multipartThreshold + cacheLineSize - 1) / cacheLineSize
+		}
+
+		for _, partSizeKey = range []string{"part_size", "multipart_chunksize"} {
+			if parseAny(optionsAsMap, partSizeKey) {
+				partSize, ok = ParseSize(optionsAsMap[partSizeKey])
+				if !ok || (partSize == 0) {
+					err = fmt.Errorf("%s%s", optionsPrefix, partSizeKey)
+					return
+				}
+				backendAsMap["upload_part_cache_lines"] = (partSize + cacheLineSize - 1) / cacheLineSize
+			}
+		}
+
+		if parseAny(optionsAsMap, "max_concurrency") {
+			maxConcurrency, ok = parseUint64(optionsAsMap, "max_concurrency", nil)
+			if !ok || (maxConcurrency == 0) {
+				err = fmt.Errorf("%smax_concurrency", optionsPrefix)
+				return
+			}
+			backendAsMap["upload_part_concurrency"] = maxConcurrency
+		}
+
+		if (rustClientAsMap == nil) || (optionsPrefix != "") {
+			return
+		}
+
+		optionsAsMap = rustClientAsMap
+		optionsPrefix = "rust_client "
+	}
+}
+
+// `parseAny` provides a convenient test for the existence of
+// a key string in the map.
+func parseAny(m map[string]interface{}, key string) (ok bool) {
+	_, ok = m[key]
+	return
+}
+
+// `parseAnyOf` provides a convenient test for the existence of
+// any of the supplied key strings in the map.
+func parseAnyOf(m map[string]interface{}, keySet []string) (ok bool) {
+	var (
+		key string
+	)
+
+	ok = false // Handles the case where len(keySet) == 0
+
+	for _, key = range keySet {
+		_, ok = m[key]
+		if ok {
+			return
+		}
+	}
+
+	return // If we make it to here, ok remains false
+}
+
+// `ParseSecret` fetches what is expected to be a secret value for the specified key from the
+// map. The value may be a string (subject to environment variable expansion as for parseString())
+// or a secret reference taking one of the following forms:
+//
+//	{"from_env": "VAR"}                        => the value of environment variable VAR (which must be set)
+//	{"from_file": "/run/secrets/key"}          => the content of the file (less any trailing newline)
+//	{"from_command": "cmd args"}               => the output of the command run by "/bin/sh -c"
+//	{"from_command": ["cmd", "arg", ...]}      => the output of the command run directly
+//
+// If the key is missing and a non-nil dflt is provided, the func will return this dflt (expanded).
+// As the map is re-parsed on each SIGHUP, so are secret references re-resolved. Note that the
+// returned err never includes the secret value itself.
+func ParseSecret(m map[string]interface{}, key string, dflt interface{}) (s string, err error) {
+	var (
+		argsAsInterface              interface{}
+		argsAsInterfaceSlice         []interface{}
+		argv                         []string
+		cancel                       context.CancelFunc
+		content                      []byte
+		ctx                          context.Context
+		ok                           bool
+		secretReferenceAsMap         map[string]interface{}
+		secretReferenceSource        string
+		secretReferenceValue         interface{}
+		secretReferenceValueAsString string
+	)
+
+	secretReferenceAsMap, ok = m[key].(map[string]interface{})
+	if !ok {
+		s, ok = parseString(m, key, dflt)
+		if !ok {
+			err = errors.New("must be a string or a secret reference")
+		}
+		return
+	}
+
+	if len(secretReferenceAsMap) != 1 {
+		err = errors.New("secret reference must have exactly one of from_env, from_file, or from_command")
+		return
+	}
+
+	for secretReferenceSource, secretReferenceValue = range secretReferenceAsMap {
+		// Just fetch the lone element
+	}
+
+	if secretReferenceSource == "from_command" {
+		argsAsInterface = secretReferenceValue
+		argsAsInterfaceSlice, ok = argsAsInterface.([]interface{})
+		if ok {
+			for _, argsAsInterface = range argsAsInterfaceSlice {
+				secretReferenceValueAsString, ok = argsAsInterface.(string)
+				if !ok {
+					err = errors.New("from_command list must contain only strings")
+					return
+				}
+				argv = append(argv, secretReferenceValueAsString)
+			}
+			if len(argv) == 0 {
+				err = errors.New("from_command list must not be empty")
+				return
+			}
+		} else {
+			secretReferenceValueAsString, ok = argsAsInterface.(string)
+			if !ok || (secretReferenceValueAsString == "") {
+				err = errors.New("from_command must be a non-empty string or list of strings")
+				return
+			}
+			argv = []string{"/bin/sh", "-c", secretReferenceValueAsString}
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), secretCommandTimeout)
+		defer cancel()
+
+		content, err = exec.CommandContext(ctx, argv[0], argv[1:]...).Output()
+		if err != nil {
+			err = fmt.Errorf("from_command \"%s\" failed: %v", argv[0], err)
+			return
+		}
+
+		s = strings.TrimRight(string(content), "\r\n")
+		return
+	}
+
+	secretReferenceValueAsString, ok = secretReferenceValue.(string)
+	if !ok || (secretReferenceValueAsString == "") {
+		err = fmt.Errorf("%s must be a non-empty string", secretReferenceSource)
+		return
+	}
+
+	switch secretReferenceSource {
+	case "from_env":
+		s, ok = os.LookupEnv(secretReferenceValueAsString)
+		if !ok {
+			err = fmt.Errorf("from_env variable \"%s\" not set", secretReferenceValueAsString)
+			return
+		}
+	case "from_file":
+		content, err = os.ReadFile(secretReferenceValueAsString)
+		if err != nil {
+			err = fmt.Errorf("from_file unreadable: %v", err)
+			return
+		}
+		s = strings.TrimRight(string(content), "\r\n")
+	default:
+		err = fmt.Errorf("unsupported secret reference source \"%s\" - must be one of from_env, from_file, or from_command", secretReferenceSource)
+	}
+
+	return
+}
+
+// `parseString` fetches what is expected to be a string value for the specified key
+// from the map expanding any environment variable references. If the key is missing
+// and a non-nil dflt is provided, dflt (also expanded) is returned.
+func parseString(m map[string]interface{}, key string, dflt interface{}) (s string, ok bool) {
+	var (
+		err error
+		v   interface{}
+	)
+
+	v, ok = m[key]
+	if ok {
+		s, ok = v.(string)
+		if ok {
+			s = os.ExpandEnv(s)
+		}
+		return
+	}
+
+	if dflt == nil {
+		ok = false
+		return
+	}
+
+	s, ok = dflt.(string)
+	if ok {
+		s, err = envsubst.Eval(s, os.Getenv)
+		if err != nil {
+			ok = false
+			return
+		}
+	}
+
+	return
+}
+
+// `parseUint64` fetches what is expected to be a uint64 value for the
+// specified key from the map. If the key is missing and a non-nil
+// dflt is provided, the func will return this dflt.
+func parseUint64(m map[string]interface{}, key string, dflt interface{}) (u uint64, ok bool) {
+	var (
+		f float64
+		i int
+		v interface{}
+	)
+
+	v, ok = m[key]
+	if ok {
+		f, ok = v.(float64)
+		if ok {
+			u = uint64(f)
+			ok = (float64(u) == f)
+			return
+		}
+
+		i, ok = v.(int)
+		if ok {
+			u = uint64(i)
+			ok = (int(u) == i)
+			return
+		}
+
+		u, ok = v.(uint64)
+
+		return
+	}
+
+	if dflt == nil {
+		ok = false
+		return
+	}
+
+	u, ok = dflt.(uint64)
+
+	return
+}
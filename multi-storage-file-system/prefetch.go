@@ -0,0 +1,514 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// `errPrefetchStopped` is returned by a prefetchStruct's walkPrefix() visit callback to end
+// the walk early (e.g. once the data cache would otherwise be recycled by the prefetch itself).
+var errPrefetchStopped = errors.New("prefetch stopped")
+
+// `prefetchStruct` tracks a warm-up of the data cache for the files beneath (or of) an inode
+// triggered by setting its XAttrNamePrefetch extended attribute.
+type prefetchStruct struct {
+	inodeNumber  uint64         // Inode whose XAttrNamePrefetch extended attribute was set
+	backend      *backendStruct //
+	objectPath   string         // The inode's objectPath
+	isFile       bool           // If true, the inode is a FileObject (and .paths == []string{.objectPath})
+	paths        []string       // Relative to backend.prefix; each either a directory (to be walked) or a file
+	startTime    time.Time      //
+	endTime      time.Time      // Protected by globals.Lock(); == time.Time{} while running
+	err          error          // Protected by globals.Lock()
+	files        atomic.Uint64  // Files whose data cache lines have been queued for fetching
+	linesQueued  atomic.Uint64  // Data cache lines queued for fetching
+	linesFetched atomic.Uint64  // Data cache lines successfully fetched
+	bytesFetched atomic.Uint64  // Bytes of content in .linesFetched
+	truncated    atomic.Bool    // Set if the prefetch stopped early for lack of data cache lines or due to memory pressure
+}
+
+// `prefetchLineStruct` identifies a data cache line queued for fetching by a prefetchStruct.
+type prefetchLineStruct struct {
+	inodeNumber uint64
+	lineNumber  uint64
+}
+
+// `prefetchStatusStruct` is the JSON form of a prefetchStruct reported by /prefetch and as the
+// value of XAttrNamePrefetch.
+type prefetchStatusStruct struct {
+	InodeNumber  uint64    `json:"inode_number"`
+	Backend      string    `json:"backend"`
+	Path         string    `json:"path"`
+	State        string    `json:"state"` // One of "running", "done", or "failed"
+	Files        uint64    `json:"files"`
+	LinesQueued  uint64    `json:"lines_queued"`
+	LinesFetched uint64    `json:"lines_fetched"`
+	BytesFetched uint64    `json:"bytes_fetched"`
+	Truncated    bool      `json:"truncated"`
+	Error        string    `json:"error,omitempty"`
+	StartTime    time.Time `json:"start_time"`
+	Duration     string    `json:"duration"`
+}
+
+// `startPrefetch` launches a warm-up of the data cache for the inode identified by inodeNumber
+// (see XAttrNamePrefetch). If the inode is a directory, value is either empty (in which case
+// every file beneath the directory is prefetched) or a newline-separated list of paths relative
+// to the directory, each either a file or a directory to be prefetched. If the inode is a file,
+// value must be empty. The data cache lines of each file are fetched by prefetch_concurrency
+// concurrent workers. Progress is reported by prefetchStatus(). It must be called without holding
+// globals.Lock().
+func startPrefetch(inodeNumber uint64, value string) (errno syscall.Errno) {
+	var (
+		entry    string
+		inode    *inodeStruct
+		ok       bool
+		prefetch *prefetchStruct
+	)
+
+	globalsLock("prefetch.go:76:2:startPrefetch")
+
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	if !ok || inode.pendingDelete {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if inode.inodeType == FUSERootDir {
+		globalsUnlock()
+		errno = syscall.EINVAL
+		return
+	}
+
+	prefetch, ok = globals.prefetches[inodeNumber]
+	if ok && prefetch.endTime.IsZero() {
+		globalsUnlock()
+		errno = syscall.EBUSY
+		return
+	}
+
+	prefetch = &prefetchStruct{
+		inodeNumber: inodeNumber,
+		objectPath:  inode.objectPath,
+		startTime:   time.Now(),
+	}
+
+	prefetch.backend, ok = globals.backendMap[inode.backendNonce]
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce] returned !ok")
+	}
+
+	switch {
+	case inode.inodeType == FileObject:
+		if strings.TrimSpace(value) != "" {
+			globalsUnlock()
+			errno = syscall.ENOTDIR
+			return
+		}
+		prefetch.isFile = true
+		prefetch.paths = []string{inode.objectPath}
+	case strings.TrimSpace(value) == "":
+		prefetch.paths = []string{inode.objectPath}
+	default:
+		for entry = range strings.SplitSeq(value, "\n") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			if path.IsAbs(entry) || (path.Clean(entry) == "..") || strings.HasPrefix(path.Clean(entry), "../") {
+				globalsUnlock()
+				errno = syscall.EINVAL
+				return
+			}
+			prefetch.paths = append(prefetch.paths, inode.objectPath+path.Clean(entry))
+		}
+	}
+
+	inode.touch(nil)
+
+	globals.prefetches[inodeNumber] = prefetch
+
+	globalsUnlock()
+
+	_ = startWorker("prefetch", prefetch.run)
+
+	errno = 0
+	return
+}
+
+// `run` walks each of prefetch.paths queueing the data cache lines of the files found for
+// fetching by prefetch_concurrency concurrent workers.
+func (prefetch *prefetchStruct) run(ctx context.Context) {
+	var (
+		err          error
+		linesChan    chan prefetchLineStruct
+		prefetchPath string
+		visited      atomic.Bool
+		workers      int
+		workersWG    sync.WaitGroup
+	)
+
+	globalsLock("prefetch.go:160:2:(*prefetchStruct).run")
+	workers = int(globals.config.prefetchConcurrency)
+	globalsUnlock()
+
+	linesChan = make(chan prefetchLineStruct, workers)
+
+	for range workers {
+		workersWG.Add(1)
+		go func() {
+			defer workersWG.Done()
+			for prefetchLine := range linesChan {
+				prefetch.fetchLine(ctx, prefetchLine)
+			}
+		}()
+	}
+
+	for _, prefetchPath = range prefetch.paths {
+		if prefetch.isFile {
+			err = prefetch.queueFile(ctx, linesChan, &walkEntryStruct{path: prefetchPath})
+			break
+		}
+
+		visited.Store(false)
+
+		_, err = walkPrefix(ctx, prefetch.backend, prefetchPath, 0, func(walkEntry *walkEntryStruct) (err error) {
+			visited.Store(true)
+			err = prefetch.queueFile(ctx, linesChan, walkEntry)
+			return
+		})
+		if err != nil {
+			break
+		}
+
+		if !visited.Load() && (prefetchPath != prefetch.objectPath) {
+			// Having nothing beneath it, this listed path may instead be a file
+
+			err = prefetch.queueObject(ctx, linesChan, prefetchPath)
+			if err != nil {
+				if !errors.Is(err, errFileNotFound) {
+					break
+				}
+				globals.logger.Printf("[WARN] prefetch of %s/%s found nothing at \"%s\"", prefetch.backend.dirName, prefetch.objectPath, prefetchPath)
+				err = nil
+			}
+		}
+	}
+
+	close(linesChan)
+
+	workersWG.Wait()
+
+	if errors.Is(err, errPrefetchStopped) {
+		err = nil
+	}
+
+	globalsLock("prefetch.go:215:2:(*prefetchStruct).run")
+	prefetch.endTime = time.Now()
+	prefetch.err = err
+	globalsUnlock()
+
+	if err == nil {
+		globals.logger.Printf("[INFO] prefetch of %s/%s fetched %d of %d data cache line(s) (%d bytes) for %d file(s) in %v (truncated: %v)", prefetch.backend.dirName, prefetch.objectPath, prefetch.linesFetched.Load(), prefetch.linesQueued.Load(), prefetch.bytesFetched.Load(), prefetch.files.Load(), prefetch.endTime.Sub(prefetch.startTime), prefetch.truncated.Load())
+	} else {
+		globals.logger.Printf("[WARN] prefetch of %s/%s failed: %v", prefetch.backend.dirName, prefetch.objectPath, err)
+	}
+}
+
+// `queueObject` stats the object at filePath (relative to backend.prefix) and, if found, queues
+// its data cache lines via queueFile().
+func (prefetch *prefetchStruct) queueObject(ctx context.Context, linesChan chan prefetchLineStruct, filePath string) (err error) {
+	var (
+		statFileOutput *statFileOutputStruct
+	)
+
+	if prefetch.backend.filter.hides(filePath) {
+		err = errFileNotFound
+		return
+	}
+
+	statFileOutput, err = statFileWrapper(ctx, prefetch.backend.context, &statFileInputStruct{filePath: filePath})
+	if err != nil {
+		return
+	}
+
+	err = prefetch.queueFile(ctx, linesChan, &walkEntryStruct{
+		path:  filePath,
+		eTag:  statFileOutput.eTag,
+		mTime: statFileOutput.mTime,
+		size:  statFileOutput.size,
+	})
+
+	return
+}
+
+// `queueFile` locates (or creates) the inode of the file described by walkEntry (or, if
+// prefetch.isFile, simply uses prefetch's own inode) and queues each
+// of its data cache lines not already cached on linesChan. Once as many data cache lines as the
+// data cache holds have been queued (such that further fetches would recycle those just
+// fetched), or should memory pressure arise, errPrefetchStopped is returned.
+func (prefetch *prefetchStruct) queueFile(ctx context.Context, linesChan chan prefetchLineStruct, walkEntry *walkEntryStruct) (err error) {
+	var (
+		basename    string
+		dirInode    *inodeStruct
+		dirName     string
+		dirPath     string
+		fileInode   *inodeStruct
+		lineNumber  uint64
+		lineNumbers []uint64
+		ok          bool
+	)
+
+	if prefetch.backend.filter.hides(walkEntry.path) {
+		return
+	}
+
+	if globals.memoryPressure.Load() {
+		prefetch.truncated.Store(true)
+		err = errPrefetchStopped
+		return
+	}
+
+	globalsLock("prefetch.go:281:2:(*prefetchStruct).queueFile")
+
+	if prefetch.isFile {
+		fileInode, ok = globals.inodeMap.get(prefetch.inodeNumber)
+		if !ok || fileInode.pendingDelete {
+			globalsUnlock()
+			err = errFileNotFound
+			return
+		}
+	} else {
+		dirInode, ok = globals.inodeMap.get(prefetch.inodeNumber)
+		if !ok || dirInode.pendingDelete {
+			globalsUnlock()
+			err = errFileNotFound
+			return
+		}
+
+		dirPath, basename = path.Split(strings.TrimPrefix(walkEntry.path, prefetch.objectPath))
+
+		for dirName = range strings.SplitSeq(strings.TrimSuffix(dirPath, "/"), "/") {
+			if dirName == "" {
+				continue
+			}
+			dirInode = dirInode.findChildDirInode(dirName)
+			if dirInode.inodeType == FileObject {
+				// A file shadows the directory containing this one
+				globalsUnlock()
+				return
+			}
+		}
+
+		fileInode = dirInode.findChildFileInode(basename, walkEntry.eTag, walkEntry.mTime, walkEntry.size)
+		if fileInode.inodeType != FileObject {
+			globalsUnlock()
+			return
+		}
+	}
+
+	for lineNumber = 0; (lineNumber * globals.config.cacheLineSize) < fileInode.sizeInBackend; lineNumber++ {
+		_, ok = fileInode.cacheMap[lineNumber]
+		if !ok {
+			lineNumbers = append(lineNumbers, lineNumber)
+		}
+	}
+
+	globalsUnlock()
+
+	prefetch.files.Add(1)
+
+	for _, lineNumber = range lineNumbers {
+		if prefetch.linesQueued.Load() >= globals.config.cacheLines {
+			prefetch.truncated.Store(true)
+			err = errPrefetchStopped
+			return
+		}
+
+		prefetch.linesQueued.Add(1)
+
+		select {
+		case linesChan <- prefetchLineStruct{inodeNumber: fileInode.inodeNumber, lineNumber: lineNumber}:
+		case <-ctx.Done():
+			err = context.Cause(ctx)
+			return
+		}
+	}
+
+	return
+}
+
+// `fetchLine` fetches the data cache line identified by prefetchLine (unless already cached)
+// awaiting its arrival.
+func (prefetch *prefetchStruct) fetchLine(ctx context.Context, prefetchLine prefetchLineStruct) {
+	var (
+		cacheLineWaiter      sync.WaitGroup
+		dataCacheLineNumbers []uint64
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		err                  error
+		inode                *inodeStruct
+		ok                   bool
+	)
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	globalsLock("prefetch.go:366:2:(*prefetchStruct).fetchLine")
+
+	dataCacheLineNumbers, _ = allocateDataCacheLines(1, "")
+
+	globalsLock("prefetch.go:370:2:(*prefetchStruct).fetchLine")
+
+	inode, ok = globals.inodeMap.get(prefetchLine.inodeNumber)
+	if !ok || inode.pendingDelete || (inode.inodeType != FileObject) || ((prefetchLine.lineNumber * globals.config.cacheLineSize) >= inode.sizeInBackend) {
+		releaseDataCacheLines(dataCacheLineNumbers)
+		globalsUnlock()
+		return
+	}
+
+	_, ok = inode.cacheMap[prefetchLine.lineNumber]
+	if ok {
+		releaseDataCacheLines(dataCacheLineNumbers)
+		globalsUnlock()
+		return
+	}
+
+	if len(dataCacheLineNumbers) == 0 {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] allocateDataCacheLines() returned no data cache lines")
+	}
+
+	inode.touch(nil)
+
+	dataCacheLineTracker = &globals.dataCacheLinesTracker[dataCacheLineNumbers[0]]
+
+	cacheLineWaiter.Add(1)
+	dataCacheLineTracker.waiters = make([]*sync.WaitGroup, 1)
+	dataCacheLineTracker.waiters[0] = &cacheLineWaiter
+	dataCacheLineTracker.contentLength = 0
+	dataCacheLineTracker.contentGeneration.Add(1)
+	dataCacheLineTracker.inodeNumber = inode.inodeNumber
+	dataCacheLineTracker.lineNumber = prefetchLine.lineNumber
+	dataCacheLineTracker.eTag = ""
+
+	inode.cacheMap[prefetchLine.lineNumber] = dataCacheLineTracker.pos
+	inode.inboundCacheLineCount++
+	globals.dataCacheLineInboundLRU.pushTail(dataCacheLineTracker)
+
+	dataCacheLineTracker.startFetch(ctx)
+
+	releaseDataCacheLines(dataCacheLineNumbers[1:])
+
+	globalsUnlock()
+
+	err = awaitCacheLineWaiter(ctx, &cacheLineWaiter)
+	if err != nil {
+		return
+	}
+
+	globalsLock("prefetch.go:419:2:(*prefetchStruct).fetchLine")
+
+	if (dataCacheLineTracker.state == CacheLineClean) && (dataCacheLineTracker.inodeNumber == prefetchLine.inodeNumber) && (dataCacheLineTracker.lineNumber == prefetchLine.lineNumber) {
+		prefetch.linesFetched.Add(1)
+		prefetch.bytesFetched.Add(dataCacheLineTracker.contentLength)
+	}
+
+	globalsUnlock()
+}
+
+// `status` returns the JSON form of prefetch. It must be called while holding globals.Lock().
+func (prefetch *prefetchStruct) status() (prefetchStatus *prefetchStatusStruct) {
+	prefetchStatus = &prefetchStatusStruct{
+		InodeNumber:  prefetch.inodeNumber,
+		Backend:      prefetch.backend.dirName,
+		Path:         prefetch.objectPath,
+		Files:        prefetch.files.Load(),
+		LinesQueued:  prefetch.linesQueued.Load(),
+		LinesFetched: prefetch.linesFetched.Load(),
+		BytesFetched: prefetch.bytesFetched.Load(),
+		Truncated:    prefetch.truncated.Load(),
+		StartTime:    prefetch.startTime,
+	}
+
+	switch {
+	case prefetch.endTime.IsZero():
+		prefetchStatus.State = "running"
+		prefetchStatus.Duration = time.Since(prefetch.startTime).String()
+	case prefetch.err != nil:
+		prefetchStatus.State = "failed"
+		prefetchStatus.Error = prefetch.err.Error()
+		prefetchStatus.Duration = prefetch.endTime.Sub(prefetch.startTime).String()
+	default:
+		prefetchStatus.State = "done"
+		prefetchStatus.Duration = prefetch.endTime.Sub(prefetch.startTime).String()
+	}
+
+	return
+}
+
+// `prefetchStatus` returns the JSON form of the most recent prefetch triggered via the
+// XAttrNamePrefetch extended attribute of the inode identified by inodeNumber. Should there be
+// none, ENODATA is returned. It must be called without holding globals.Lock().
+func prefetchStatus(inodeNumber uint64) (backend *backendStruct, value []byte, errno syscall.Errno) {
+	var (
+		err      error
+		ok       bool
+		prefetch *prefetchStruct
+	)
+
+	globalsLock("prefetch.go:469:2:prefetchStatus")
+
+	prefetch, ok = globals.prefetches[inodeNumber]
+	if !ok {
+		globalsUnlock()
+		errno = syscall.ENODATA
+		return
+	}
+
+	backend = prefetch.backend
+
+	value, err = json.Marshal(prefetch.status())
+
+	globalsUnlock()
+
+	if err != nil {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] json.Marshal(prefetch.status()) failed: %v", err)
+	}
+
+	errno = 0
+	return
+}
+
+// `adminPrefetches` is called to report each prefetch triggered via XAttrNamePrefetch.
+func adminPrefetches() (adminPrefetches []*prefetchStatusStruct) {
+	var (
+		prefetch *prefetchStruct
+	)
+
+	globalsLock("prefetch.go:499:2:adminPrefetches")
+
+	adminPrefetches = make([]*prefetchStatusStruct, 0, len(globals.prefetches))
+
+	for _, prefetch = range globals.prefetches {
+		adminPrefetches = append(adminPrefetches, prefetch.status())
+	}
+
+	globalsUnlock()
+
+	slices.SortFunc(adminPrefetches, func(a, b *prefetchStatusStruct) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+
+	return
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+// testPrefetchAwait sets the user.msc.prefetch extended attribute of the inode identified by
+// inodeNumber to value and awaits the completion of the resulting warm-up, returning its status.
+func testPrefetchAwait(t *testing.T, inodeNumber uint64, value string) (prefetchStatus *prefetchStatusStruct) {
+	var (
+		deadline    = time.Now().Add(10 * time.Second)
+		err         error
+		errno       syscall.Errno
+		getXAttrOut *fission.GetXAttrOut
+	)
+
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: inodeNumber}, &fission.SetXAttrIn{Name: []byte(XAttrNamePrefetch), Data: []byte(value)})
+	if errno != 0 {
+		t.Fatalf("DoSetXAttr(%d, XAttrNamePrefetch, %q) returned errno: %v", inodeNumber, value, errno)
+	}
+
+	for {
+		getXAttrOut, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: inodeNumber}, &fission.GetXAttrIn{Name: []byte(XAttrNamePrefetch), Size: 4096})
+		if errno != 0 {
+			t.Fatalf("DoGetXAttr(%d, XAttrNamePrefetch) returned errno: %v", inodeNumber, errno)
+		}
+
+		prefetchStatus = &prefetchStatusStruct{}
+		err = json.Unmarshal(getXAttrOut.Data, prefetchStatus)
+		if err != nil {
+			t.Fatalf("json.Unmarshal(%q) failed: %v", getXAttrOut.Data, err)
+		}
+
+		if prefetchStatus.State != "running" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("prefetch of %d did not complete (last status: %+v)", inodeNumber, prefetchStatus)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestPrefetch verifies that setting the user.msc.prefetch extended attribute of a directory
+// fetches the content of every file beneath it (or of just those listed) into the data cache.
+func TestPrefetch(t *testing.T) {
+	var (
+		cachedLines    int
+		dir1Ino        uint64
+		errno          syscall.Errno
+		fileBInode     *inodeStruct
+		fileBIno       uint64
+		ok             bool
+		prefetchStatus *prefetchStatusStruct
+		s3DirIno       uint64
+		wantBytes      = uint64(len("/dir1/fileC\n")+len("/fileA\n")) + testFissionS3FileBLen
+	)
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+
+	_, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: s3DirIno}, &fission.GetXAttrIn{Name: []byte(XAttrNamePrefetch), Size: 4096})
+	if errno != syscall.ENODATA {
+		t.Fatalf("DoGetXAttr(s3, XAttrNamePrefetch) before any prefetch should have returned ENODATA (got errno: %v)", errno)
+	}
+
+	prefetchStatus = testPrefetchAwait(t, s3DirIno, "")
+	if (prefetchStatus.State != "done") || (prefetchStatus.Files != 3) || (prefetchStatus.LinesFetched != 6) || (prefetchStatus.BytesFetched != wantBytes) || prefetchStatus.Truncated {
+		t.Fatalf("prefetch of s3 returned unexpected status: %+v", prefetchStatus)
+	}
+
+	fileBIno = fissionS3TestLookup(t, s3DirIno, "fileB")
+
+	globalsLock("prefetch_test.go:82:2:TestPrefetch")
+	fileBInode, ok = globals.inodeMap.get(fileBIno)
+	if ok {
+		cachedLines = len(fileBInode.cacheMap)
+	}
+	globalsUnlock()
+	if !ok || (cachedLines != 4) {
+		t.Fatalf("prefetch of s3 should have cached all 4 data cache lines of fileB (found %d)", cachedLines)
+	}
+
+	dir1Ino = fissionS3TestLookup(t, s3DirIno, "dir1")
+
+	prefetchStatus = testPrefetchAwait(t, dir1Ino, "fileC\nnosuch\n")
+	if (prefetchStatus.State != "done") || (prefetchStatus.Files != 1) || (prefetchStatus.LinesQueued != 0) {
+		t.Fatalf("prefetch of s3/dir1 listing already cached fileC returned unexpected status: %+v", prefetchStatus)
+	}
+
+	errno = globals.DoSetXAttr(&fission.InHeader{NodeID: dir1Ino}, &fission.SetXAttrIn{Name: []byte(XAttrNamePrefetch), Data: []byte("../fileA")})
+	if errno != syscall.EINVAL {
+		t.Fatalf("DoSetXAttr(s3/dir1, XAttrNamePrefetch, \"../fileA\") should have returned EINVAL (got errno: %v)", errno)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// `tokenBucketStruct` implements a token bucket refilled at .rate tokens per second up to
+// .burst tokens. A take() of more tokens than are available is nonetheless granted at once
+// (driving .tokens negative) with the caller then waiting for the shortfall to be refilled.
+// Requests larger than .burst (e.g. a data cache line larger than a second's worth of
+// read_bandwidth_limit) are thus throttled rather than blocked forever.
+type tokenBucketStruct struct {
+	sync.Mutex
+	rate   float64   // Tokens added per second
+	burst  float64   // Maximum number of tokens accumulated while idle
+	tokens float64   // Tokens currently available (negative if owed by granted take()s)
+	last   time.Time // When .tokens was last refilled
+}
+
+// `newTokenBucket` returns a (full) tokenBucketStruct refilled at rate tokens per second
+// able to accumulate up to a second's worth of tokens. If rate == 0, nil (i.e. unlimited)
+// is returned.
+func newTokenBucket(rate uint64) (tokenBucket *tokenBucketStruct) {
+	if rate == 0 {
+		return
+	}
+
+	tokenBucket = &tokenBucketStruct{
+		rate:   float64(rate),
+		burst:  float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+
+	return
+}
+
+// `refill` adds the tokens accumulated since .last (while tokenBucket is locked).
+func (tokenBucket *tokenBucketStruct) refill(timeNow time.Time) {
+	tokenBucket.tokens = min(tokenBucket.burst, tokenBucket.tokens+(timeNow.Sub(tokenBucket.last).Seconds()*tokenBucket.rate))
+	tokenBucket.last = timeNow
+}
+
+// `take` removes n tokens from tokenBucket waiting, if necessary, until they would have been
+// available. Should ctx be done first, the tokens are returned and context.Cause(ctx) is
+// returned instead. A nil tokenBucket imposes no limit.
+func (tokenBucket *tokenBucketStruct) take(ctx context.Context, n uint64) (delay time.Duration, err error) {
+	var (
+		timer *time.Timer
+	)
+
+	if (tokenBucket == nil) || (n == 0) {
+		return
+	}
+
+	tokenBucket.Lock()
+	tokenBucket.refill(time.Now())
+	tokenBucket.tokens -= float64(n)
+	if tokenBucket.tokens < 0 {
+		delay = time.Duration(-tokenBucket.tokens / tokenBucket.rate * float64(time.Second))
+	}
+	tokenBucket.Unlock()
+
+	if delay == 0 {
+		return
+	}
+
+	timer = time.NewTimer(delay)
+
+	select {
+	case <-timer.C:
+		err = nil
+	case <-ctx.Done():
+		timer.Stop()
+		tokenBucket.give(n)
+		err = context.Cause(ctx)
+	}
+
+	return
+}
+
+// `give` returns n (unused) tokens previously obtained via take() to tokenBucket.
+func (tokenBucket *tokenBucketStruct) give(n uint64) {
+	if (tokenBucket == nil) || (n == 0) {
+		return
+	}
+
+	tokenBucket.Lock()
+	tokenBucket.refill(time.Now())
+	tokenBucket.tokens = min(tokenBucket.burst, tokenBucket.tokens+float64(n))
+	tokenBucket.Unlock()
+}
+
+// `throttle` waits until both the request_rate_limit and (if bytes != 0) the
+// read_bandwidth_limit of backend permit a request (of bytes). Any time spent waiting
+// is recorded in the backend's throttling metrics. Should ctx be done first,
+// context.Cause(ctx) is returned instead.
+func (backend *backendStruct) throttle(ctx context.Context, bytes uint64) (err error) {
+	var (
+		bandwidthDelay time.Duration
+		requestDelay   time.Duration
+	)
+
+	requestDelay, err = backend.requestBucket.take(ctx, 1)
+	if err == nil {
+		bandwidthDelay, err = backend.readBucket.take(ctx, bytes)
+		if err != nil {
+			backend.requestBucket.give(1)
+		}
+	}
+
+	if (requestDelay + bandwidthDelay) > 0 {
+		globals.backendMetrics.ThrottledRequests.Inc()
+		globals.backendMetrics.ThrottleDelays.Observe((requestDelay + bandwidthDelay).Seconds())
+
+		backend.backendMetrics.ThrottledRequests.Inc()
+		backend.backendMetrics.ThrottleDelays.Observe((requestDelay + bandwidthDelay).Seconds())
+	}
+
+	return
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTokenBucket verifies that a tokenBucketStruct grants its burst at once, delays takes
+// beyond it by the time needed to refill the shortfall, and returns the tokens of a take
+// whose ctx is done first.
+func TestTokenBucket(t *testing.T) {
+	var (
+		cancel      context.CancelFunc
+		ctx         context.Context
+		delay       time.Duration
+		err         error
+		tokenBucket *tokenBucketStruct
+	)
+
+	tokenBucket = newTokenBucket(0)
+	if tokenBucket != nil {
+		t.Fatalf("newTokenBucket(0) returned non-nil")
+	}
+
+	delay, err = tokenBucket.take(context.Background(), 1000000)
+	if (err != nil) || (delay != 0) {
+		t.Fatalf("nil tokenBucket.take() returned delay %v err %v (expected 0 and nil)", delay, err)
+	}
+
+	tokenBucket = newTokenBucket(1000)
+
+	delay, err = tokenBucket.take(context.Background(), 1000)
+	if (err != nil) || (delay != 0) {
+		t.Fatalf("tokenBucket.take(1000) [burst] returned delay %v err %v (expected 0 and nil)", delay, err)
+	}
+
+	delay, err = tokenBucket.take(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("tokenBucket.take(50) failed: %v", err)
+	}
+	if (delay <= 0) || (delay > 50*time.Millisecond) {
+		t.Fatalf("tokenBucket.take(50) returned delay %v (expected (0,50ms])", delay)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	_, err = tokenBucket.take(ctx, 1000)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("tokenBucket.take(canceled ctx, 1000) returned err %v (expected context.Canceled)", err)
+	}
+
+	tokenBucket.Lock()
+	if tokenBucket.tokens < -1 {
+		tokenBucket.Unlock()
+		t.Fatalf("tokenBucket.take(canceled ctx, 1000) did not return its tokens (tokens: %v)", tokenBucket.tokens)
+	}
+	tokenBucket.Unlock()
+}
+
+// TestRequestRateLimitCoversStat verifies that request_rate_limit charges stats (not just
+// reads and directory listings) against the backend's request token bucket.
+func TestRequestRateLimitCoversStat(t *testing.T) {
+	var (
+		err           error
+		ok            bool
+		pseudoBackend *backendStruct
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	pseudoBackend, ok = globals.config.backends["pseudo"]
+	if !ok {
+		t.Fatalf("globals.config.backends[\"pseudo\"] returned !ok")
+	}
+
+	pseudoBackend.requestBucket = newTokenBucket(1)
+	defer func() { pseudoBackend.requestBucket = nil }()
+
+	_, err = statFileWrapper(context.Background(), pseudoBackend.context, &statFileInputStruct{filePath: "file_00000000"})
+	if err != nil {
+		t.Fatalf("statFileWrapper(pseudoBackend.context, \"file_00000000\") failed: %v", err)
+	}
+
+	pseudoBackend.requestBucket.Lock()
+	if pseudoBackend.requestBucket.tokens >= 0.5 {
+		pseudoBackend.requestBucket.Unlock()
+		t.Fatalf("statFileWrapper() did not take a request token (tokens: %v)", pseudoBackend.requestBucket.tokens)
+	}
+	pseudoBackend.requestBucket.Unlock()
+}
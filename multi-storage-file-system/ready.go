@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sdNotifySocketEnv = "NOTIFY_SOCKET" // Set by systemd (for Type=notify services) to the datagram socket to which sd_notify(3) states are sent
+	sdNotifyReady     = "READY=1"       // sd_notify(3) state sent once the file system is ready
+	sdNotifyStopping  = "STOPPING=1"    // sd_notify(3) state sent once shutdown begins
+
+	adminLiveTimeout = 5 * time.Second // How long globals.Lock() may be held before /live reports the daemon wedged
+)
+
+// `startReadinessProbe` is called once the FUSE file system (and each of the mounts) has
+// been mounted to launch a worker probing each mounted backend (just as /health does) every
+// globals.config.readyProbeInterval until all of them succeed. Only then is the file system
+// declared ready (see markReady()).
+func startReadinessProbe() {
+	var (
+		readyProbeInterval = globals.config.readyProbeInterval
+	)
+
+	_ = startWorker("readinessProbe", func(ctx context.Context) {
+		var (
+			adminBackendHealth  *adminBackendHealthStruct
+			adminBackendHealths []*adminBackendHealthStruct
+			ready               bool
+		)
+
+		for {
+			adminBackendHealths = adminHealth()
+
+			ready = true
+
+			for _, adminBackendHealth = range adminBackendHealths {
+				if adminBackendHealth.Mounted && !adminBackendHealth.Healthy {
+					ready = false
+					globals.logger.Printf("[INFO] readiness probe of backend \"%s\" failed: %s", adminBackendHealth.DirName, adminBackendHealth.Error)
+				}
+			}
+
+			if ready {
+				markReady()
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(readyProbeInterval):
+			}
+		}
+	})
+}
+
+// `markReady` declares the file system ready: /ready begins reporting so, any ready_file
+// is written, systemd (if it started us as a Type=notify service) is notified, and an
+// EventMountReady is published.
+func markReady() {
+	var (
+		err error
+	)
+
+	globals.ready.Store(true)
+
+	if globals.config.readyFile != "" {
+		err = os.WriteFile(globals.config.readyFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644)
+		if err != nil {
+			globals.logger.Printf("[WARN] unable to write ready_file (\"%s\"): %v", globals.config.readyFile, err)
+		}
+	}
+
+	err = sdNotify(sdNotifyReady)
+	if err != nil {
+		globals.logger.Printf("[WARN] unable to notify systemd of readiness: %v", err)
+	}
+
+	publishEvent(EventMountReady, "", "")
+
+	globals.logger.Printf("[INFO] file system ready")
+}
+
+// `clearReady` is called upon SIGINT or SIGTERM (prior to unmounting) to withdraw the
+// readiness declared by markReady() (if it had been).
+func clearReady() {
+	var (
+		err error
+	)
+
+	if !globals.ready.Swap(false) {
+		return
+	}
+
+	if globals.config.readyFile != "" {
+		err = os.Remove(globals.config.readyFile)
+		if (err != nil) && !errors.Is(err, os.ErrNotExist) {
+			globals.logger.Printf("[WARN] unable to remove ready_file (\"%s\"): %v", globals.config.readyFile, err)
+		}
+	}
+
+	err = sdNotify(sdNotifyStopping)
+	if err != nil {
+		globals.logger.Printf("[WARN] unable to notify systemd of shutdown: %v", err)
+	}
+}
+
+// `sdNotify` sends state to the socket named by ${NOTIFY_SOCKET} (see sd_notify(3)). If
+// ${NOTIFY_SOCKET} is not set (i.e. we were not started as a Type=notify service), it is
+// a no-op. A leading '@' denotes a socket in the abstract namespace.
+func sdNotify(state string) (err error) {
+	var (
+		conn       *net.UnixConn
+		socketPath = os.Getenv(sdNotifySocketEnv)
+	)
+
+	if socketPath == "" {
+		return
+	}
+
+	if strings.HasPrefix(socketPath, "@") {
+		socketPath = "\x00" + socketPath[1:]
+	}
+
+	conn, err = net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		return
+	}
+
+	_, err = conn.Write([]byte(state))
+
+	_ = conn.Close()
+
+	return
+}
+
+// `adminLive` is called to report whether the daemon is responsive. As most package fission
+// callbacks require holding globals exclusively, the daemon is considered wedged should any
+// holder have held it for adminLiveTimeout. This is determined from globalsLockHeldSince
+// without acquiring globals so that a wedged daemon cannot also block (or leak) the prober.
+func adminLive() (live bool) {
+	var (
+		heldSince = globalsLockHeldSince.Load()
+	)
+
+	live = (heldSince == 0) || (time.Since(time.Unix(0, heldSince)) < adminLiveTimeout)
+
+	return
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestReadiness verifies that, once each mounted backend has passed its probe, /ready
+// reports so, the ready_file is written, and READY=1 is sent to $NOTIFY_SOCKET, and that
+// clearReady() withdraws each of them.
+func TestReadiness(t *testing.T) {
+	var (
+		buf        = make([]byte, 64)
+		err        error
+		n          int
+		notifyConn *net.UnixConn
+		readyFile  string
+		socketDir  string
+		socketPath string
+	)
+
+	socketDir, err = os.MkdirTemp("", "msfs-ready") // Kept short as unix socket paths are limited to ~108 bytes
+	if err != nil {
+		t.Fatalf("os.MkdirTemp() failed: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(socketDir) }()
+
+	socketPath = filepath.Join(socketDir, "notify")
+	readyFile = filepath.Join(socketDir, "ready")
+
+	notifyConn, err = net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("net.ListenUnixgram() failed: %v", err)
+	}
+	defer func() { _ = notifyConn.Close() }()
+
+	t.Setenv(sdNotifySocketEnv, socketPath)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	globals.config.readyFile = readyFile
+	globals.config.readyProbeInterval = 10 * time.Millisecond
+
+	get := func(path string, expectedStatusCode int) {
+		recorder := httptest.NewRecorder()
+		(&adminHandlerStruct{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != expectedStatusCode {
+			t.Fatalf("GET %s returned status %d (expected %d)", path, recorder.Code, expectedStatusCode)
+		}
+	}
+
+	get("/live", http.StatusOK)
+
+	globalsLock("ready_test.go:60:2:TestReadiness")
+	globalsLockHeldSince.Store(time.Now().Add(-adminLiveTimeout).UnixNano()) // as if wedged holding globals.Lock()
+	get("/live", http.StatusServiceUnavailable)
+	globalsUnlock()
+
+	get("/live", http.StatusOK)
+
+	get("/ready", http.StatusServiceUnavailable)
+
+	startReadinessProbe()
+
+	_ = notifyConn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	n, err = notifyConn.Read(buf)
+	if err != nil {
+		t.Fatalf("notifyConn.Read() failed: %v", err)
+	}
+	if string(buf[:n]) != sdNotifyReady {
+		t.Fatalf("$NOTIFY_SOCKET received \"%s\" (expected \"%s\")", buf[:n], sdNotifyReady)
+	}
+
+	get("/ready", http.StatusOK)
+
+	_, err = os.Stat(readyFile)
+	if err != nil {
+		t.Fatalf("ready_file not written: %v", err)
+	}
+
+	clearReady()
+
+	n, err = notifyConn.Read(buf)
+	if err != nil {
+		t.Fatalf("notifyConn.Read() failed: %v", err)
+	}
+	if string(buf[:n]) != sdNotifyStopping {
+		t.Fatalf("$NOTIFY_SOCKET received \"%s\" (expected \"%s\")", buf[:n], sdNotifyStopping)
+	}
+
+	get("/ready", http.StatusServiceUnavailable)
+
+	_, err = os.Stat(readyFile)
+	if !os.IsNotExist(err) {
+		t.Fatalf("ready_file not removed (err: %v)", err)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	reloadTriggerSIGHUP = "SIGHUP"               // An explicit syscall.SIGHUP was received
+	reloadTriggerAuto   = "auto_sighup_interval" // The auto_sighup_interval ticker fired
+)
+
+// `reloadStruct` serializes config file reloads. Each reload both re-parses the
+// config file (populating globals.backendsToUnmount and globals.backendsToMount)
+// and then drains those lists, so two reloads must never interleave.
+type reloadStruct struct {
+	sync.Mutex
+	errLast error // Result of the most recent checkConfigFile() (used to suppress repeated auto_sighup_interval logging)
+}
+
+// `reloadConfigFile` re-parses the (current) content of globals.configFilePath and,
+// upon success, applies any resulting backend unmounts and mounts. Both an explicit
+// SIGHUP and the auto_sighup_interval ticker come through here so that they behave
+// identically save for logging: an explicit SIGHUP always logs (and publishes) its
+// outcome while the ticker only does so when the outcome differs from the previous
+// reload's outcome.
+func reloadConfigFile(trigger string) (err error) {
+	var (
+		quiet bool
+	)
+
+	globals.reload.Lock()
+
+	err = checkConfigFile()
+	if err == nil {
+		quiet = (trigger == reloadTriggerAuto) && (globals.reload.errLast == nil)
+
+		if !quiet {
+			globals.logger.Printf("[INFO] parsing config-file (\"%s\") succeeded [%s]", globals.configFilePath, trigger)
+			publishEvent(EventReloadApplied, "", "")
+		}
+
+		processToUnmountList()
+
+		processToMountList()
+
+		updateMountReadOnly()
+	} else {
+		quiet = (trigger == reloadTriggerAuto) && (globals.reload.errLast != nil) && (globals.reload.errLast.Error() == err.Error())
+
+		if !quiet {
+			// CodeQL [SM01413]: clear-text-logging false positive — see audit note at startup.
+			globals.logger.Printf("[WARN] parsing config-file (\"%s\") failed [%s]: %s", globals.configFilePath, trigger, redactSecrets(nil, err.Error()))
+			publishEvent(EventReloadFailed, "", redactSecrets(nil, err.Error()))
+		}
+	}
+
+	globals.reload.errLast = err
+
+	globals.reload.Unlock()
+
+	return
+}
+
+// `applyHotReloadableConfig` copies those settings in the (just validated) config that
+// may safely change on a running mount into globals.config and the backends common to
+// both. Settings read at each use (TTLs, cache partition limits, prefetch depth, and
+// log levels) take effect immediately; all others must be unchanged (as checked by
+// checkConfigFile()). Cache partitions left over a lowered limit are trimmed at once. The settings are swapped atomically with respect to FUSE ops by
+// doing so while holding the globals lock.
+func applyHotReloadableConfig(config *configStruct) {
+	var (
+		backendAsStructNew *backendStruct
+		backendAsStructOld *backendStruct
+		backendS3New       *backendConfigS3Struct
+		backendS3Old       *backendConfigS3Struct
+		dirName            string
+		freed              uint64
+		ok                 bool
+	)
+
+	globalsLock("reload.go:85:2:applyHotReloadableConfig")
+
+	logHotReload("entry_attr_ttl", globals.config.entryAttrTTL, config.entryAttrTTL)
+	globals.config.entryAttrTTL = config.entryAttrTTL
+
+	logHotReload("entry_ttl", globals.config.entryTTL, config.entryTTL)
+	globals.config.entryTTL = config.entryTTL
+
+	logHotReload("attr_ttl", globals.config.attrTTL, config.attrTTL)
+	globals.config.attrTTL = config.attrTTL
+
+	logHotReload("evictable_inode_ttl", globals.config.evictableInodeTTL, config.evictableInodeTTL)
+	globals.config.evictableInodeTTL = config.evictableInodeTTL
+
+	logHotReload("virtual_dir_ttl", globals.config.virtualDirTTL, config.virtualDirTTL)
+	globals.config.virtualDirTTL = config.virtualDirTTL
+
+	logHotReload("virtual_file_ttl", globals.config.virtualFileTTL, config.virtualFileTTL)
+	globals.config.virtualFileTTL = config.virtualFileTTL
+
+	logHotReload("hide_inaccessible_backends", globals.config.hideInaccessibleBackends, config.hideInaccessibleBackends)
+	globals.config.hideInaccessibleBackends = config.hideInaccessibleBackends
+
+	logHotReload("default_backend", globals.config.defaultBackend, config.defaultBackend)
+	globals.config.defaultBackend = config.defaultBackend
+
+	logHotReload("cache_lines_to_prefetch", globals.config.cacheLinesToPrefetch, config.cacheLinesToPrefetch)
+	globals.config.cacheLinesToPrefetch = config.cacheLinesToPrefetch
+
+	logHotReload("memory_pressure_limit", globals.config.memoryPressureLimit, config.memoryPressureLimit)
+	globals.config.memoryPressureLimit = config.memoryPressureLimit
+
+	logHotReload("memory_pressure_threshold", globals.config.memoryPressureThreshold, config.memoryPressureThreshold)
+	globals.config.memoryPressureThreshold = config.memoryPressureThreshold
+
+	logHotReload("multipart_upload_max_age", globals.config.multipartUploadMaxAge, config.multipartUploadMaxAge)
+	globals.config.multipartUploadMaxAge = config.multipartUploadMaxAge
+
+	logHotReload("presigned_url_ttl", globals.config.presignedURLTTL, config.presignedURLTTL)
+	globals.config.presignedURLTTL = config.presignedURLTTL
+
+	logHotReload("tree_size_cache_ttl", globals.config.treeSizeCacheTTL, config.treeSizeCacheTTL)
+	globals.config.treeSizeCacheTTL = config.treeSizeCacheTTL
+
+	logHotReload("prefetch_concurrency", globals.config.prefetchConcurrency, config.prefetchConcurrency)
+	globals.config.prefetchConcurrency = config.prefetchConcurrency
+
+	logHotReload("dirty_cache_lines_flush_trigger", globals.config.dirtyCacheLinesFlushTrigger, config.dirtyCacheLinesFlushTrigger)
+	globals.config.dirtyCacheLinesFlushTrigger = config.dirtyCacheLinesFlushTrigger
+
+	logHotReload("dirty_cache_lines_max", globals.config.dirtyCacheLinesMax, config.dirtyCacheLinesMax)
+	globals.config.dirtyCacheLinesMax = config.dirtyCacheLinesMax
+
+	logHotReload("cache_partition_default_limit", globals.config.cachePartitionDefaultLimit, config.cachePartitionDefaultLimit)
+	globals.config.cachePartitionDefaultLimit = config.cachePartitionDefaultLimit
+
+	if !slices.Equal(globals.config.cachePartitions, config.cachePartitions) {
+		globals.logger.Printf("[INFO] reload changed cache_partitions from %v to %v", globals.config.cachePartitions, config.cachePartitions)
+		globals.config.cachePartitions = config.cachePartitions
+	}
+
+	freed = trimCachePartitions()
+	if freed > 0 {
+		globals.logger.Printf("[INFO] reload freed %v Clean data cache lines of cache partitions over their limit", freed)
+	}
+
+	if globals.config.logLevel != config.logLevel {
+		globals.logger.Printf("[INFO] reload changed log_level from \"%s\" to \"%s\"", strings.ToLower(logLevelString(globals.config.logLevel)), strings.ToLower(logLevelString(config.logLevel)))
+		globals.config.logLevel = config.logLevel
+		globals.logging.level.Set(config.logLevel)
+	}
+
+	for dirName, backendAsStructOld = range globals.config.backends {
+		backendAsStructNew, ok = config.backends[dirName]
+		if !ok && (backendAsStructOld.mkDirOf != "") {
+			// A backend created by mkdir(2) in the FUSE root follows its default_backend
+			backendAsStructNew, ok = config.backends[backendAsStructOld.mkDirOf]
+		}
+		if !ok {
+			continue
+		}
+
+		logHotReload(fmt.Sprintf("backends[\"%s\"] entry_ttl", dirName), backendAsStructOld.entryTTL, backendAsStructNew.entryTTL)
+		backendAsStructOld.entryTTL = backendAsStructNew.entryTTL
+
+		logHotReload(fmt.Sprintf("backends[\"%s\"] attr_ttl", dirName), backendAsStructOld.attrTTL, backendAsStructNew.attrTTL)
+		backendAsStructOld.attrTTL = backendAsStructNew.attrTTL
+
+		if backendAsStructOld.logLevel.Level() != backendAsStructNew.logLevel.Level() {
+			globals.logger.Printf("[INFO] reload changed backends[\"%s\"] log_level from \"%s\" to \"%s\"", dirName, strings.ToLower(logLevelString(backendAsStructOld.logLevel.Level())), strings.ToLower(logLevelString(backendAsStructNew.logLevel.Level())))
+			backendAsStructOld.logLevel.Set(backendAsStructNew.logLevel.Level())
+		}
+
+		if !backendAsStructOld.acl.equal(backendAsStructNew.acl) {
+			globals.logger.Printf("[INFO] reload changed backends[\"%s\"] access control list", dirName)
+			backendAsStructOld.acl = backendAsStructNew.acl
+		}
+
+		if (backendAsStructOld.backendType == "S3") && backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct).discoverBuckets {
+			// Each SIGHUP also triggers rediscovery of the buckets accessible to the backend
+
+			backendS3Old = backendAsStructOld.backendTypeSpecifics.(*backendConfigS3Struct)
+			backendS3New = backendAsStructNew.backendTypeSpecifics.(*backendConfigS3Struct)
+
+			logHotReload(fmt.Sprintf("backends[\"%s\"] S3.bucket_discovery_ttl", dirName), backendS3Old.bucketDiscoveryTTL, backendS3New.bucketDiscoveryTTL)
+
+			if backendS3Old.discoveredBuckets == nil {
+				backendS3Old.bucketDiscoveryTTL = backendS3New.bucketDiscoveryTTL
+			} else {
+				backendS3Old.discoveredBuckets.Lock()
+				backendS3Old.bucketDiscoveryTTL = backendS3New.bucketDiscoveryTTL
+				backendS3Old.discoveredBuckets.fetchTime = time.Time{}
+				backendS3Old.discoveredBuckets.Unlock()
+			}
+		}
+	}
+
+	updateBackendACLsInUse()
+
+	globalsUnlock()
+}
+
+// `logHotReload` logs the change (if any) of a hot-reloadable setting.
+func logHotReload(setting string, oldValue, newValue interface{}) {
+	if oldValue != newValue {
+		globals.logger.Printf("[INFO] reload changed %s from %v to %v", setting, oldValue, newValue)
+	}
+}
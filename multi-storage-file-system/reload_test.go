@@ -0,0 +1,254 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// `testReloadWriteConfig` rewrites the fissionTestUp() config file, adding a
+// "ram2" backend if withRam2.
+func testReloadWriteConfig(t *testing.T, withRam2 bool) {
+	var (
+		ram2 string
+	)
+
+	if withRam2 {
+		ram2 = `,
+			{
+				"dir_name": "ram2",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM"
+			}`
+	}
+
+	err := os.WriteFile(globals.configFilePath, []byte(`
+	{
+		"msfs_version": 1,
+		"backends": [
+			{
+				"dir_name": "pseudo",
+				"bucket_container_name": "ignored",
+				"backend_type": "PSEUDO",
+				"PSEUDO": {
+					"file_size": 1024,
+					"files_at_depth_0": 1,
+					"files_at_depth_1": 2,
+					"files_at_depth_2": 0,
+					"files_at_depth_3": 0,
+					"subdirectories_at_depth_0": 2,
+					"subdirectories_at_depth_1": 0,
+					"subdirectories_at_depth_2": 0
+				}
+			},
+			{
+				"dir_name": "ram",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM",
+				"readonly": false
+			}`+ram2+`
+		]
+	}
+	`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+}
+
+// `testReloadInterleaved` concurrently issues a mix of SIGHUP and auto_sighup_interval
+// triggered reloads, failing the test if any of them fails.
+func testReloadInterleaved(t *testing.T) {
+	var (
+		errChan = make(chan error, 8)
+		wg      sync.WaitGroup
+	)
+
+	for i := range 8 {
+		wg.Add(1)
+		go func(trigger string) {
+			defer wg.Done()
+			errChan <- reloadConfigFile(trigger)
+		}([]string{reloadTriggerSIGHUP, reloadTriggerAuto}[i%2])
+	}
+
+	wg.Wait()
+	close(errChan)
+
+	for err := range errChan {
+		if err != nil {
+			t.Fatalf("reloadConfigFile() unexpectedly failed: %v", err)
+		}
+	}
+}
+
+// `testReloadCheckRam2` verifies that the "ram2" backend is (or is not) mounted
+// and that no backend remains pending either mount or unmount.
+func testReloadCheckRam2(t *testing.T, expectRam2 bool) {
+	var (
+		inBackends        bool
+		inVirtChildDirMap bool
+		start             uint64
+		limit             uint64
+	)
+
+	globalsLock("reload_test.go:96:2:testReloadCheckRam2")
+
+	_, inBackends = globals.config.backends["ram2"]
+	_, inVirtChildDirMap = globals.virtChildDirEntryMap.getByBasename(FUSERootDirInodeNumber, "ram2")
+	start, limit = globals.virtChildDirEntryMap.getIndexRange(FUSERootDirInodeNumber)
+
+	if (len(globals.backendsToMount) != 0) || (len(globals.backendsToUnmount) != 0) {
+		globalsUnlock()
+		t.Fatalf("backends left pending: toMount %d toUnmount %d", len(globals.backendsToMount), len(globals.backendsToUnmount))
+	}
+
+	globalsUnlock()
+
+	if (inBackends != expectRam2) || (inVirtChildDirMap != expectRam2) {
+		t.Fatalf("expected ram2 mounted == %v, got globals.config.backends: %v virtChildDirEntryMap: %v", expectRam2, inBackends, inVirtChildDirMap)
+	}
+
+	// ".", "..", "pseudo", "ram", and (possibly) "ram2"
+	if expectRam2 && ((limit - start) != 5) {
+		t.Fatalf("expected 5 root dir entries, got %d", limit-start)
+	}
+	if !expectRam2 && ((limit - start) != 4) {
+		t.Fatalf("expected 4 root dir entries, got %d", limit-start)
+	}
+}
+
+func TestReloadInterleavedTriggers(t *testing.T) {
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	testReloadWriteConfig(t, true)
+	testReloadInterleaved(t)
+	testReloadCheckRam2(t, true)
+
+	testReloadWriteConfig(t, false)
+	testReloadInterleaved(t)
+	testReloadCheckRam2(t, false)
+}
+
+func TestReloadFailureThenRecovery(t *testing.T) {
+	var (
+		err error
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	err = os.WriteFile(globals.configFilePath, []byte(`{"msfs_version": 1, "backends": [`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = reloadConfigFile(reloadTriggerAuto); err == nil {
+		t.Fatalf("reloadConfigFile(reloadTriggerAuto) unexpectedly succeeded")
+	}
+	if err = reloadConfigFile(reloadTriggerSIGHUP); err == nil {
+		t.Fatalf("reloadConfigFile(reloadTriggerSIGHUP) unexpectedly succeeded")
+	}
+	if globals.reload.errLast == nil {
+		t.Fatalf("globals.reload.errLast should have recorded the failed reload")
+	}
+
+	// A reload that follows a failed one must still apply pending mounts
+
+	testReloadWriteConfig(t, true)
+
+	if err = reloadConfigFile(reloadTriggerAuto); err != nil {
+		t.Fatalf("reloadConfigFile(reloadTriggerAuto) unexpectedly failed: %v", err)
+	}
+	if globals.reload.errLast != nil {
+		t.Fatalf("globals.reload.errLast should have been cleared by the successful reload")
+	}
+
+	testReloadCheckRam2(t, true)
+}
+
+func TestReloadHotReloadableConfig(t *testing.T) {
+	var (
+		err error
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+	{
+		"msfs_version": 1,
+		"entry_attr_ttl": 2500,
+		"cache_lines_to_prefetch": 2,
+		"log_level": "warn",
+		"backends": [
+			{
+				"dir_name": "pseudo",
+				"bucket_container_name": "ignored",
+				"backend_type": "PSEUDO",
+				"PSEUDO": {
+					"file_size": 1024,
+					"files_at_depth_0": 1,
+					"files_at_depth_1": 2,
+					"files_at_depth_2": 0,
+					"files_at_depth_3": 0,
+					"subdirectories_at_depth_0": 2,
+					"subdirectories_at_depth_1": 0,
+					"subdirectories_at_depth_2": 0
+				}
+			},
+			{
+				"dir_name": "ram",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM",
+				"readonly": false,
+				"entry_ttl": 500,
+				"log_level": "debug"
+			}
+		]
+	}
+	`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = reloadConfigFile(reloadTriggerSIGHUP); err != nil {
+		t.Fatalf("reloadConfigFile(reloadTriggerSIGHUP) unexpectedly failed: %v", err)
+	}
+
+	globalsLock("reload_test.go:221:2:TestReloadHotReloadableConfig")
+
+	if globals.config.entryAttrTTL != 2500*time.Millisecond {
+		t.Errorf("entry_attr_ttl should have become 2.5s but is %v", globals.config.entryAttrTTL)
+	}
+	if globals.config.cacheLinesToPrefetch != 2 {
+		t.Errorf("cache_lines_to_prefetch should have become 2 but is %v", globals.config.cacheLinesToPrefetch)
+	}
+	if globals.logging.level.Level() != slog.LevelWarn {
+		t.Errorf("log_level should have become warn but is %v", globals.logging.level.Level())
+	}
+	if globals.config.backends["pseudo"].attrTTL != 2500*time.Millisecond {
+		t.Errorf("backends[\"pseudo\"] attr_ttl should have followed entry_attr_ttl to 2.5s but is %v", globals.config.backends["pseudo"].attrTTL)
+	}
+	if globals.config.backends["ram"].entryTTL != 500*time.Millisecond {
+		t.Errorf("backends[\"ram\"] entry_ttl should have become 500ms but is %v", globals.config.backends["ram"].entryTTL)
+	}
+	if !globals.config.backends["ram"].logEnabled(slog.LevelDebug) {
+		t.Errorf("backends[\"ram\"] log_level should have become debug")
+	}
+
+	globalsUnlock()
+
+	// Settings baked into running state (e.g. the ttl_check_interval ticker) remain frozen
+
+	err = os.WriteFile(globals.configFilePath, []byte(`{"msfs_version": 1, "ttl_check_interval": 500, "backends": []}`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	if err = reloadConfigFile(reloadTriggerSIGHUP); err == nil {
+		t.Fatalf("reloadConfigFile(reloadTriggerSIGHUP) unexpectedly allowed changing ttl_check_interval")
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// `remountDrainPollInterval` is how often remountBackend() re-examines what remains to drain.
+const remountDrainPollInterval = 100 * time.Millisecond
+
+// `remountBackend` is called to drain, tear down, and re-create the backend mounted at dirName
+// (its backend context, data cache lines, and inodes) leaving all other backends online. This
+// is useful to recover a backend in a bad state (e.g. having expired credentials) without a
+// restart. Open file handles referencing the backend are awaited for up to unmount_drain_timeout
+// after which they are force-released (see forceReleaseFH()). Returns !found if no backend is
+// mounted at dirName. It must be called without holding globals.Lock().
+func remountBackend(dirName string) (found bool, err error) {
+	var (
+		backend      *backendStruct
+		backendNonce uint64
+		busyFHNonces []uint64
+		deadline     time.Time
+		fhNonce      uint64
+		fhNonces     []uint64
+		inboundLines uint64
+		releasedBusy bool
+	)
+
+	// Serialize with reloadConfigFile() as both manipulate globals.backendsTo{Unm|M}ount
+
+	globals.reload.Lock()
+	defer globals.reload.Unlock()
+
+	globalsLock("remount.go:34:2:remountBackend")
+	backend, found = globals.config.backends[dirName]
+	if !found {
+		globalsUnlock()
+		return
+	}
+	backendNonce = backend.nonce
+	globalsUnlock()
+
+	globals.logger.Printf("[INFO] remounting backend \"%s\"", dirName)
+
+	// Await the closing of open file handles and the completion of data cache line fetches
+
+	deadline = time.Now().Add(globals.config.unmountDrainTimeout)
+
+	for {
+		globalsLock("remount.go:50:3:remountBackend")
+		fhNonces, inboundLines = backendDrainBacklog(backendNonce)
+		globalsUnlock()
+
+		if ((len(fhNonces) == 0) && (inboundLines == 0)) || !time.Now().Before(deadline) {
+			break
+		}
+
+		time.Sleep(min(remountDrainPollInterval, time.Until(deadline)))
+	}
+
+	for _, fhNonce = range fhNonces {
+		_, releasedBusy = forceReleaseFH(fhNonce)
+		if releasedBusy {
+			busyFHNonces = append(busyFHNonces, fhNonce)
+		}
+	}
+
+	globalsLock("remount.go:68:2:remountBackend")
+
+	if (globals.config.backends[dirName] != backend) || (backend.nonce != backendNonce) {
+		globalsUnlock()
+		err = fmt.Errorf("backend \"%s\" was concurrently unmounted", dirName)
+		return
+	}
+
+	_, inboundLines = backendDrainBacklog(backendNonce)
+	if (len(busyFHNonces) != 0) || (inboundLines != 0) {
+		globalsUnlock()
+		err = fmt.Errorf("backend \"%s\" failed to drain (busy file handles: %v; inbound data cache lines: %v)", dirName, busyFHNonces, inboundLines)
+		return
+	}
+
+	// Tear down the backend's data cache lines and inodes
+
+	purgeBackendDataCacheLines(backendNonce)
+
+	globals.backendsToUnmount[dirName] = backend
+	processToUnmountListAlreadyLocked()
+
+	// Re-create the backend (including a fresh backend context)
+
+	backend.context = nil
+	backend.hedge = nil
+
+	globals.backendsToMount[dirName] = backend
+
+	globalsUnlock()
+
+	processToMountList()
+
+	globalsLock("remount.go:101:2:remountBackend")
+	if !backend.mounted {
+		err = fmt.Errorf("backend \"%s\" unmounted but unable to set up a backend context", dirName)
+	}
+	globalsUnlock()
+
+	if err == nil {
+		globals.logger.Printf("[INFO] remounted backend \"%s\"", dirName)
+	} else {
+		globals.logger.Printf("[WARN] remount of backend \"%s\" failed: %v", dirName, err)
+	}
+
+	return
+}
+
+// `backendDrainBacklog` is called while holding globals.Lock() to return the nonces of the open
+// file handles and the count of Inbound data cache lines referencing inodes of the backend
+// identified by backendNonce.
+func backendDrainBacklog(backendNonce uint64) (fhNonces []uint64, inboundLines uint64) {
+	var (
+		dataCacheLineNumber uint64
+		fh                  *fhStruct
+		inode               *inodeStruct
+		ok                  bool
+	)
+
+	for _, fh = range globals.fhMap {
+		if fh.inode.backendNonce == backendNonce {
+			fhNonces = append(fhNonces, fh.nonce)
+		}
+	}
+
+	for dataCacheLineNumber = range uint64(len(globals.dataCacheLinesTracker)) {
+		if globals.dataCacheLinesTracker[dataCacheLineNumber].state != CacheLineInbound {
+			continue
+		}
+		inode, ok = globals.inodeMap.get(globals.dataCacheLinesTracker[dataCacheLineNumber].inodeNumber)
+		if ok && (inode.backendNonce == backendNonce) {
+			inboundLines++
+		}
+	}
+
+	return
+}
+
+// `purgeBackendDataCacheLines` is called while holding globals.Lock() to free every Clean data
+// cache line holding content of an inode of the backend identified by backendNonce. This must
+// precede removal of those inodes as a Clean data cache line must always reference an inode.
+func purgeBackendDataCacheLines(backendNonce uint64) {
+	var (
+		dataCacheLineNumber  uint64
+		dataCacheLineTracker *dataCacheLineTrackerStruct
+		inode                *inodeStruct
+		ok                   bool
+	)
+
+	for dataCacheLineNumber = range uint64(len(globals.dataCacheLinesTracker)) {
+		dataCacheLineTracker = &globals.dataCacheLinesTracker[dataCacheLineNumber]
+		if dataCacheLineTracker.state != CacheLineClean {
+			continue
+		}
+
+		inode, ok = globals.inodeMap.get(dataCacheLineTracker.inodeNumber)
+		if !ok || (inode.backendNonce != backendNonce) {
+			continue
+		}
+
+		globals.dataCacheLineCleanLRU.popThis(dataCacheLineTracker)
+
+		dataCacheLineTracker.evict()
+		dataCacheLineTracker.free()
+	}
+}
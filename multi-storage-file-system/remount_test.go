@@ -0,0 +1,119 @@
+package main
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+func TestRemountBackend(t *testing.T) {
+	var (
+		backend         *backendStruct
+		backendNonceOld uint64
+		err             error
+		errno           syscall.Errno
+		fileBFH         uint64
+		fileBIno        uint64
+		found           bool
+		lookupOut       *fission.LookupOut
+		openOut         *fission.OpenOut
+		pseudoNonce     uint64
+		ramContextOld   backendContextIf
+		ramDirInoOld    uint64
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.LookupIn{Name: []byte("ram")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") unexpectedly failed (errno: %v)", errno)
+	}
+	ramDirInoOld = lookupOut.EntryOut.NodeID
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: ramDirInoOld}, &fission.LookupIn{Name: []byte("fileB")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(ramDirIno,Name:\"fileB\") unexpectedly failed (errno: %v)", errno)
+	}
+	fileBIno = lookupOut.EntryOut.NodeID
+
+	openOut, errno = globals.DoOpen(&fission.InHeader{NodeID: fileBIno}, &fission.OpenIn{Flags: fission.FOpenRequestRDONLY})
+	if errno != 0 {
+		t.Fatalf("DoOpen(fileBIno, Flags: fission.FOpenRequestRDONLY) unexpectedly failed (errno: %v)", errno)
+	}
+	fileBFH = openOut.FH
+
+	_, errno = globals.DoRead(&fission.InHeader{NodeID: fileBIno}, &fission.ReadIn{FH: fileBFH, Offset: 0, Size: testFissionReadBufSize})
+	if errno != 0 {
+		t.Fatalf("DoRead(FH: fileBFH) unexpectedly failed (errno: %v)", errno)
+	}
+
+	globalsLock("remount_test.go:53:2:TestRemountBackend")
+	globals.config.unmountDrainTimeout = 50 * time.Millisecond
+	backend = globals.config.backends["ram"]
+	backendNonceOld = backend.nonce
+	ramContextOld = backend.context
+	pseudoNonce = globals.config.backends["pseudo"].nonce
+	if globals.dataCacheLineCleanLRU.lruCount.Load() == 0 {
+		globalsUnlock()
+		t.Fatalf("DoRead(FH: fileBFH) should have left Clean data cache lines")
+	}
+	globalsUnlock()
+
+	found, _ = remountBackend("missing")
+	if found {
+		t.Fatalf("remountBackend(\"missing\") should have returned !found")
+	}
+
+	found, err = remountBackend("ram")
+	if !found || (err != nil) {
+		t.Fatalf("remountBackend(\"ram\") returned found: %v err: %v (expected: true, nil)", found, err)
+	}
+
+	globalsLock("remount_test.go:75:2:TestRemountBackend")
+	if !backend.mounted || (globals.config.backends["ram"] != backend) {
+		globalsUnlock()
+		t.Fatalf("remountBackend(\"ram\") should have left the backend mounted")
+	}
+	if (backend.nonce == backendNonceOld) || (backend.context == ramContextOld) {
+		globalsUnlock()
+		t.Fatalf("remountBackend(\"ram\") should have re-created the backend nonce and context")
+	}
+	if globals.config.backends["pseudo"].nonce != pseudoNonce {
+		globalsUnlock()
+		t.Fatalf("remountBackend(\"ram\") should not have touched the \"pseudo\" backend")
+	}
+	if globals.dataCacheLineCleanLRU.lruCount.Load() != 0 {
+		globalsUnlock()
+		t.Fatalf("remountBackend(\"ram\") should have freed all Clean data cache lines (%v remain)", globals.dataCacheLineCleanLRU.lruCount.Load())
+	}
+	if _, found = globals.fhMap[fileBFH]; found {
+		globalsUnlock()
+		t.Fatalf("remountBackend(\"ram\") should have force-released fileBFH")
+	}
+	if _, found = globals.inodeMap.get(fileBIno); found {
+		globalsUnlock()
+		t.Fatalf("remountBackend(\"ram\") should have removed fileB's inode")
+	}
+	globalsUnlock()
+
+	_, errno = globals.DoRead(&fission.InHeader{NodeID: fileBIno}, &fission.ReadIn{FH: fileBFH, Offset: 0, Size: testFissionReadBufSize})
+	if errno == 0 {
+		t.Fatalf("DoRead(FH: fileBFH) after remountBackend(\"ram\") should have failed")
+	}
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.LookupIn{Name: []byte("ram")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") after remountBackend(\"ram\") unexpectedly failed (errno: %v)", errno)
+	}
+	if lookupOut.EntryOut.NodeID == ramDirInoOld {
+		t.Fatalf("remountBackend(\"ram\") should have re-created the backend's root directory inode")
+	}
+
+	_, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.LookupIn{Name: []byte("pseudo")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"pseudo\") after remountBackend(\"ram\") unexpectedly failed (errno: %v)", errno)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	BackendStateMounted   = "mounted"   // The backend is mounted
+	BackendStateUnmounted = "unmounted" // The backend is configured but not (or no longer) mounted
+	BackendStateProbing   = "probing"   // setupContext() failed; the backend is re-probed every backend_reprobe_interval
+	BackendStateFailed    = "failed"    // setupContext() failed; the backend will only be retried upon the next SIGHUP (backend_reprobe_interval == 0)
+)
+
+// `startBackendReprober` launches a worker calling reprobeFailedBackends() every
+// globals.config.backendReprobeInterval (if != 0).
+func startBackendReprober() {
+	var (
+		backendReprobeInterval = globals.config.backendReprobeInterval
+	)
+
+	if backendReprobeInterval == 0 {
+		return
+	}
+
+	_ = startWorker("backendReprober", func(ctx context.Context) {
+		var (
+			ticker = time.NewTicker(backendReprobeInterval)
+		)
+
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reprobeFailedBackends()
+			}
+		}
+	})
+}
+
+// `reprobeFailedBackends` retries the mounting of each backend on globals.backendsFailed
+// (i.e. whose setupContext() previously failed). Those that now succeed are mounted just
+// as if they had been added via SIGHUP; the rest remain on globals.backendsFailed.
+func reprobeFailedBackends() {
+	var (
+		backend *backendStruct
+		dirName string
+	)
+
+	// Serialize with reloadConfigFile() as both manipulate globals.backendsToMount
+
+	globals.reload.Lock()
+
+	globalsLock("reprobe.go:57:2:reprobeFailedBackends")
+
+	if len(globals.backendsFailed) == 0 {
+		globalsUnlock()
+		globals.reload.Unlock()
+		return
+	}
+
+	for dirName, backend = range globals.backendsFailed {
+		globals.backendsToMount[dirName] = backend
+	}
+
+	globalsUnlock()
+
+	processToMountList()
+
+	updateMountReadOnly()
+
+	globals.reload.Unlock()
+}
+
+// `backendState` returns the BackendState* describing backend (while globals.Lock() is held).
+func backendState(backend *backendStruct) (state string) {
+	switch {
+	case backend.mounted:
+		state = BackendStateMounted
+	case backend.setupErr == nil:
+		state = BackendStateUnmounted
+	case globals.config.backendReprobeInterval != 0:
+		state = BackendStateProbing
+	default:
+		state = BackendStateFailed
+	}
+
+	return
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestReprobeFailedBackends verifies that a backend whose setupContext() fails is reported
+// by /health as BackendStateProbing and is mounted by reprobeFailedBackends() once it recovers.
+func TestReprobeFailedBackends(t *testing.T) {
+	var (
+		adminBackendHealth *adminBackendHealthStruct
+		backend            *backendStruct
+		err                error
+		found              bool
+		ok                 bool
+		snapshotFile       = filepath.Join(t.TempDir(), "snapshot.json")
+	)
+
+	err = os.WriteFile(snapshotFile, []byte("not JSON"), 0o600) // Makes setupRAMContext() fail
+	if err != nil {
+		t.Fatalf("os.WriteFile(snapshotFile) failed: %v", err)
+	}
+
+	err = os.Setenv("MSFS_MOUNTPOINT", testGlobals.testMountPoint)
+	if err != nil {
+		t.Fatalf("os.Setenv(\"MSFS_MOUNTPOINT\", testGlobals.testMountPoint) failed: %v", err)
+	}
+
+	initGlobals(testOsArgs(testGlobals.testConfigFilePathMap[".json"]))
+
+	err = os.WriteFile(globals.configFilePath, []byte(`
+	{
+		"msfs_version": 1,
+		"backends": [
+			{
+				"dir_name": "ram",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM"
+			},
+			{
+				"dir_name": "flaky",
+				"bucket_container_name": "ignored",
+				"backend_type": "RAM",
+				"RAM": {
+					"snapshot_file": "`+snapshotFile+`"
+				}
+			}
+		]
+	}
+	`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	err = checkConfigFile()
+	if err != nil {
+		t.Fatalf("checkConfigFile() unexpectedly failed: %v", err)
+	}
+
+	initFS()
+	defer fissionTestDown(t)
+
+	processToMountList()
+
+	globalsLock("reprobe_test.go:67:2:TestReprobeFailedBackends")
+	_, ok = globals.backendsFailed["flaky"]
+	_, found = globals.config.backends["flaky"]
+	globalsUnlock()
+	if !ok || found {
+		t.Fatalf("backend \"flaky\" should have failed to mount")
+	}
+
+	for _, adminBackendHealth = range adminHealth() {
+		if adminBackendHealth.DirName == "flaky" {
+			if (adminBackendHealth.State != BackendStateProbing) || adminBackendHealth.Healthy || (adminBackendHealth.Error == "") {
+				t.Fatalf("adminHealth() reported backend \"flaky\" as %+v", adminBackendHealth)
+			}
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("adminHealth() did not report backend \"flaky\"")
+	}
+
+	// Still failing, reprobeFailedBackends() should leave it on globals.backendsFailed
+
+	reprobeFailedBackends()
+
+	globalsLock("reprobe_test.go:91:2:TestReprobeFailedBackends")
+	_, ok = globals.backendsFailed["flaky"]
+	globalsUnlock()
+	if !ok {
+		t.Fatalf("backend \"flaky\" should still be on globals.backendsFailed")
+	}
+
+	err = os.WriteFile(snapshotFile, []byte(`{"objects": []}`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile(snapshotFile) failed: %v", err)
+	}
+
+	reprobeFailedBackends()
+
+	globalsLock("reprobe_test.go:105:2:TestReprobeFailedBackends")
+	_, ok = globals.backendsFailed["flaky"]
+	backend, found = globals.config.backends["flaky"]
+	globalsUnlock()
+	if ok || !found || !backend.mounted || (backend.setupErr != nil) {
+		t.Fatalf("backend \"flaky\" should have been mounted by reprobeFailedBackends()")
+	}
+}
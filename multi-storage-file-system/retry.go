@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// `retryHistoryStruct` accumulates the attempts made (and the backoff delay incurred between
+// them) while a single backend wrapper call is being satisfied. It is carried in the ctx passed
+// to the backend so that the SDK retry hooks (e.g. the S3 aws.Retryer callbacks) can update it.
+type retryHistoryStruct struct {
+	sync.Mutex
+	attempts       int           // Count of attempts recorded by the SDK retry hooks (0 if none were recorded)
+	retryDelay     time.Duration // Cumulative backoff delay preceding each retry
+	lastHTTPStatus int           // HTTP status of the most recent failed attempt (0 if none known)
+}
+
+// `retryHistoryContextKeyStruct` is the type of the context.Context key used to locate a retryHistoryStruct.
+type retryHistoryContextKeyStruct struct{}
+
+// `withRetryHistory` returns a derived ctx carrying a fresh retryHistoryStruct.
+func withRetryHistory(ctx context.Context) (retryCtx context.Context, retryHistory *retryHistoryStruct) {
+	retryHistory = &retryHistoryStruct{}
+	retryCtx = context.WithValue(ctx, retryHistoryContextKeyStruct{}, retryHistory)
+	return
+}
+
+// `retryHistoryFromContext` returns the retryHistoryStruct carried in ctx (or nil if none).
+func retryHistoryFromContext(ctx context.Context) (retryHistory *retryHistoryStruct) {
+	if ctx == nil {
+		return nil
+	}
+
+	retryHistory, _ = ctx.Value(retryHistoryContextKeyStruct{}).(*retryHistoryStruct)
+	return
+}
+
+// `recordAttempt` notes that another attempt is about to be made.
+func (retryHistory *retryHistoryStruct) recordAttempt() {
+	if retryHistory == nil {
+		return
+	}
+
+	retryHistory.Lock()
+	retryHistory.attempts++
+	retryHistory.Unlock()
+}
+
+// `recordFailure` notes that the most recent attempt failed with err. If that attempt is to be
+// retried, delay is the backoff that will precede the retry.
+func (retryHistory *retryHistoryStruct) recordFailure(err error, delay time.Duration) {
+	var (
+		httpStatus int
+	)
+
+	if retryHistory == nil {
+		return
+	}
+
+	httpStatus = httpStatusOf(err)
+
+	retryHistory.Lock()
+	retryHistory.retryDelay += delay
+	if httpStatus != 0 {
+		retryHistory.lastHTTPStatus = httpStatus
+	}
+	retryHistory.Unlock()
+}
+
+// `recordRetry` notes that the most recent attempt failed with err and will be retried after
+// delay. It is used by SDKs that offer no per-attempt hook (e.g. GCS), where the initial
+// attempt is implied by the first retry.
+func (retryHistory *retryHistoryStruct) recordRetry(err error, delay time.Duration) {
+	if retryHistory == nil {
+		return
+	}
+
+	retryHistory.Lock()
+	if retryHistory.attempts == 0 {
+		retryHistory.attempts = 1
+	}
+	retryHistory.attempts++
+	retryHistory.Unlock()
+
+	retryHistory.recordFailure(err, delay)
+}
+
+// `snapshot` returns the attempt count, cumulative retry delay, and last HTTP status recorded.
+// An attempt count of 1 is reported if no SDK retry hook recorded any attempts.
+func (retryHistory *retryHistoryStruct) snapshot() (attempts int, retryDelay time.Duration, lastHTTPStatus int) {
+	if retryHistory == nil {
+		attempts = 1
+		return
+	}
+
+	retryHistory.Lock()
+	attempts = retryHistory.attempts
+	retryDelay = retryHistory.retryDelay
+	lastHTTPStatus = retryHistory.lastHTTPStatus
+	retryHistory.Unlock()
+
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return
+}
+
+// `annotate` appends the attempt history to a non-nil err. The original err remains available
+// via errors.Is() and errors.As().
+func (retryHistory *retryHistoryStruct) annotate(err error) error {
+	var (
+		attempts       int
+		lastHTTPStatus int
+		retryDelay     time.Duration
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	attempts, retryDelay, lastHTTPStatus = retryHistory.snapshot()
+
+	if lastHTTPStatus == 0 {
+		lastHTTPStatus = httpStatusOf(err)
+	}
+
+	if lastHTTPStatus == 0 {
+		return fmt.Errorf("%w [attempts: %d, retry delay: %v]", err, attempts, retryDelay)
+	}
+
+	return fmt.Errorf("%w [attempts: %d, retry delay: %v, last HTTP status: %d]", err, attempts, retryDelay, lastHTTPStatus)
+}
+
+// `observe` records the attempt history in the supplied backendMetricsStruct's retry histograms.
+func (retryHistory *retryHistoryStruct) observe(backendMetrics *backendMetricsStruct, err error) {
+	var (
+		attempts   int
+		retryDelay time.Duration
+	)
+
+	attempts, retryDelay, _ = retryHistory.snapshot()
+
+	backendMetrics.RetryAttempts.Observe(float64(attempts))
+	if attempts > 1 {
+		backendMetrics.RetryDelays.Observe(retryDelay.Seconds())
+		if err != nil {
+			backendMetrics.RetriesExhausted.Inc()
+		}
+	}
+}
+
+// `httpStatusOf` returns the HTTP status carried by err (or 0 if none is known).
+func httpStatusOf(err error) (httpStatus int) {
+	var (
+		awsErr interface{ HTTPStatusCode() int }
+		gcsErr *googleapi.Error
+	)
+
+	switch {
+	case err == nil:
+		httpStatus = 0
+	case errors.As(err, &awsErr):
+		httpStatus = awsErr.HTTPStatusCode()
+	case errors.As(err, &gcsErr):
+		httpStatus = gcsErr.Code
+	default:
+		httpStatus = 0
+	}
+
+	return
+}
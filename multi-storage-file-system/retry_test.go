@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// `testRetryHTTPErrorStruct` mimics the HTTPStatusCode() method of an awshttp.ResponseError.
+type testRetryHTTPErrorStruct struct {
+	statusCode int
+}
+
+func (testRetryHTTPError *testRetryHTTPErrorStruct) Error() string {
+	return "http error"
+}
+
+func (testRetryHTTPError *testRetryHTTPErrorStruct) HTTPStatusCode() int {
+	return testRetryHTTPError.statusCode
+}
+
+func TestRetryHistoryAnnotate(t *testing.T) {
+	var (
+		annotatedErr error
+		ctx          context.Context
+		errBase      = errors.New("base")
+		retryHistory *retryHistoryStruct
+	)
+
+	if retryHistoryFromContext(context.Background()) != nil {
+		t.Fatalf("retryHistoryFromContext() of a bare ctx should have returned nil")
+	}
+
+	ctx, retryHistory = withRetryHistory(context.Background())
+
+	if retryHistoryFromContext(ctx) != retryHistory {
+		t.Fatalf("retryHistoryFromContext() should have returned the retryHistoryStruct from withRetryHistory()")
+	}
+
+	if retryHistory.annotate(nil) != nil {
+		t.Fatalf("annotate(nil) should have returned nil")
+	}
+
+	annotatedErr = retryHistory.annotate(errBase)
+	if annotatedErr.Error() != "base [attempts: 1, retry delay: 0s]" {
+		t.Fatalf("annotate() without recorded attempts returned unexpected %q", annotatedErr.Error())
+	}
+
+	// Mimic the S3 aws.Retryer hooks: 3 attempts, the first two retried.
+
+	retryHistory.recordAttempt()
+	retryHistory.recordFailure(&testRetryHTTPErrorStruct{statusCode: 503}, 10*time.Millisecond)
+	retryHistory.recordAttempt()
+	retryHistory.recordFailure(&testRetryHTTPErrorStruct{statusCode: 429}, 20*time.Millisecond)
+	retryHistory.recordAttempt()
+
+	annotatedErr = retryHistory.annotate(errBase)
+	if !errors.Is(annotatedErr, errBase) {
+		t.Fatalf("annotate() should have preserved the wrapped err")
+	}
+	if annotatedErr.Error() != "base [attempts: 3, retry delay: 30ms, last HTTP status: 429]" {
+		t.Fatalf("annotate() returned unexpected %q", annotatedErr.Error())
+	}
+
+	// The final err's HTTP status is used if no failed attempt recorded one.
+
+	_, retryHistory = withRetryHistory(context.Background())
+
+	annotatedErr = retryHistory.annotate(&testRetryHTTPErrorStruct{statusCode: 404})
+	if !strings.HasSuffix(annotatedErr.Error(), "[attempts: 1, retry delay: 0s, last HTTP status: 404]") {
+		t.Fatalf("annotate() returned unexpected %q", annotatedErr.Error())
+	}
+}
+
+func TestRetryHistoryRecordRetry(t *testing.T) {
+	var (
+		attempts       int
+		lastHTTPStatus int
+		retryDelay     time.Duration
+		retryHistory   *retryHistoryStruct
+	)
+
+	_, retryHistory = withRetryHistory(context.Background())
+
+	// Mimic the GCS ErrorFunc: two retried errors followed by a non-retried one.
+
+	retryHistory.recordRetry(&testRetryHTTPErrorStruct{statusCode: 500}, 0)
+	retryHistory.recordRetry(&testRetryHTTPErrorStruct{statusCode: 502}, 0)
+	retryHistory.recordFailure(&testRetryHTTPErrorStruct{statusCode: 403}, 0)
+
+	attempts, retryDelay, lastHTTPStatus = retryHistory.snapshot()
+	if (attempts != 3) || (retryDelay != 0) || (lastHTTPStatus != 403) {
+		t.Fatalf("snapshot() returned unexpected (%v, %v, %v)", attempts, retryDelay, lastHTTPStatus)
+	}
+}
+
+func TestRetryHistoryObserve(t *testing.T) {
+	var (
+		backendMetrics = newBackendMetrics()
+		retryHistory   *retryHistoryStruct
+	)
+
+	_, retryHistory = withRetryHistory(context.Background())
+	retryHistory.observe(backendMetrics, nil)
+
+	_, retryHistory = withRetryHistory(context.Background())
+	retryHistory.recordAttempt()
+	retryHistory.recordFailure(&testRetryHTTPErrorStruct{statusCode: 503}, 10*time.Millisecond)
+	retryHistory.recordAttempt()
+	retryHistory.observe(backendMetrics, errors.New("exhausted"))
+
+	if testutil.CollectAndCount(backendMetrics.RetryAttempts) != 1 {
+		t.Fatalf("RetryAttempts should have been collected")
+	}
+	if testutil.ToFloat64(backendMetrics.RetriesExhausted) != 1 {
+		t.Fatalf("RetriesExhausted should have been 1 but was %v", testutil.ToFloat64(backendMetrics.RetriesExhausted))
+	}
+}
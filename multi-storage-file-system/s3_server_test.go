@@ -0,0 +1,1369 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// `testS3ServerStruct` is a lightweight, in-process, S3-compatible object server supporting
+// the subset of the S3 API used by the S3 backend (ListBuckets, ListObjectsV2, ListObjectVersions, GET/HEAD/PUT/DELETE
+// Object, CopyObject, RestoreObject, and multipart uploads including UploadPartCopy and ListMultipartUploads). Only path-style requests are
+// supported and request signatures are not verified. Buckets spring into existence on first use and are versioned.
+//
+// Fault injection is provided by injectFaults() to exercise the S3 backend's retry logic.
+type testS3ServerStruct struct {
+	sync.Mutex
+	httpServer      *httptest.Server
+	objects         map[string]*testS3ObjectStruct   // Key is bucket + "/" + key
+	versions        map[string][]*testS3ObjectStruct // Key is bucket + "/" + key; oldest first (including delete markers)
+	uploads         map[string]*testS3UploadStruct   // Key is UploadId
+	lastUploadID    uint64
+	lastVersionID   uint64
+	requests        uint64        // Count of requests received (including those faulted)
+	faultsRemaining uint64        // Count of upcoming requests to which faultDelay & faultStatusCode apply
+	faultDelay      time.Duration // Delay before responding to each faulted request
+	faultStatusCode int           // If != 0, faulted requests fail with this HTTP status
+	requiredHeaders http.Header   // If non-empty, requests lacking any of these header values fail with 403 (e.g. as would a requester-pays bucket)
+}
+
+// `testS3ObjectStruct` holds the content of an object stored in a testS3ServerStruct.
+type testS3ObjectStruct struct {
+	content      []byte
+	eTag         string // Quoted
+	lastModified time.Time
+	storageClass string // If == "", reported as "STANDARD"
+	restore      string // If != "", value of the x-amz-restore header (set by RestoreObject)
+	versionID    string
+	deleteMarker bool // If true, only versionID and lastModified apply
+}
+
+// `testS3UploadStruct` tracks an in-progress multipart upload.
+type testS3UploadStruct struct {
+	bucketKey string
+	initiated time.Time
+	parts     map[int32][]byte
+}
+
+// `testS3ErrorStruct` is the XML body of an S3 error response.
+type testS3ErrorStruct struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+// `testS3ListBucketResultStruct` is the XML body of a ListObjectsV2 response.
+type testS3ListBucketResultStruct struct {
+	XMLName               xml.Name                       `xml:"ListBucketResult"`
+	Name                  string                         `xml:"Name"`
+	Prefix                string                         `xml:"Prefix"`
+	Delimiter             string                         `xml:"Delimiter,omitempty"`
+	StartAfter            string                         `xml:"StartAfter,omitempty"`
+	ContinuationToken     string                         `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string                         `xml:"NextContinuationToken,omitempty"`
+	KeyCount              int                            `xml:"KeyCount"`
+	MaxKeys               int                            `xml:"MaxKeys"`
+	IsTruncated           bool                           `xml:"IsTruncated"`
+	Contents              []testS3ListContentsStruct     `xml:"Contents"`
+	CommonPrefixes        []testS3ListCommonPrefixStruct `xml:"CommonPrefixes"`
+}
+
+// `testS3ListVersionsResultStruct` is the XML body of a ListObjectVersions response.
+type testS3ListVersionsResultStruct struct {
+	XMLName             xml.Name                       `xml:"ListVersionsResult"`
+	Name                string                         `xml:"Name"`
+	Prefix              string                         `xml:"Prefix"`
+	Delimiter           string                         `xml:"Delimiter,omitempty"`
+	KeyMarker           string                         `xml:"KeyMarker"`
+	VersionIDMarker     string                         `xml:"VersionIdMarker"`
+	NextKeyMarker       string                         `xml:"NextKeyMarker,omitempty"`
+	NextVersionIDMarker string                         `xml:"NextVersionIdMarker,omitempty"`
+	MaxKeys             int                            `xml:"MaxKeys"`
+	IsTruncated         bool                           `xml:"IsTruncated"`
+	Version             []testS3ListVersionStruct      `xml:"Version"`
+	DeleteMarker        []testS3ListDeleteMarkerStruct `xml:"DeleteMarker"`
+	CommonPrefixes      []testS3ListCommonPrefixStruct `xml:"CommonPrefixes"`
+}
+
+// `testS3ListVersionStruct` describes each object version in a testS3ListVersionsResultStruct.
+type testS3ListVersionStruct struct {
+	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+// `testS3ListDeleteMarkerStruct` describes each delete marker in a testS3ListVersionsResultStruct.
+type testS3ListDeleteMarkerStruct struct {
+	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+}
+
+// `testS3ListAllMyBucketsResultStruct` is the XML body of a ListBuckets response.
+type testS3ListAllMyBucketsResultStruct struct {
+	XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+	Buckets struct {
+		Bucket []testS3BucketStruct `xml:"Bucket"`
+	} `xml:"Buckets"`
+}
+
+// `testS3BucketStruct` describes each bucket in a testS3ListAllMyBucketsResultStruct.
+type testS3BucketStruct struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+// `testS3ListContentsStruct` describes each object in a testS3ListBucketResultStruct.
+type testS3ListContentsStruct struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+// `testS3ListCommonPrefixStruct` describes each common prefix in a testS3ListBucketResultStruct.
+type testS3ListCommonPrefixStruct struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// `testS3CopyResultStruct` is the XML body of a CopyObject (CopyObjectResult) or UploadPartCopy (CopyPartResult) response.
+type testS3CopyResultStruct struct {
+	XMLName      xml.Name
+	ETag         string `xml:"ETag"`
+	LastModified string `xml:"LastModified"`
+}
+
+// `testS3InitiateMultipartUploadResultStruct` is the XML body of a CreateMultipartUpload response.
+type testS3InitiateMultipartUploadResultStruct struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+// `testS3ListMultipartUploadsResultStruct` is the XML body of a ListMultipartUploads response.
+type testS3ListMultipartUploadsResultStruct struct {
+	XMLName     xml.Name                  `xml:"ListMultipartUploadsResult"`
+	Bucket      string                    `xml:"Bucket"`
+	Prefix      string                    `xml:"Prefix"`
+	IsTruncated bool                      `xml:"IsTruncated"`
+	Upload      []testS3ListUploadsStruct `xml:"Upload"`
+}
+
+// `testS3ListUploadsStruct` describes each upload in a testS3ListMultipartUploadsResultStruct.
+type testS3ListUploadsStruct struct {
+	Key       string `xml:"Key"`
+	UploadID  string `xml:"UploadId"`
+	Initiated string `xml:"Initiated"`
+}
+
+// `testS3CompleteMultipartUploadStruct` is the XML body of a CompleteMultipartUpload request.
+type testS3CompleteMultipartUploadStruct struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Part    []struct {
+		ETag       string `xml:"ETag"`
+		PartNumber int32  `xml:"PartNumber"`
+	} `xml:"Part"`
+}
+
+// `testS3CompleteMultipartUploadResultStruct` is the XML body of a CompleteMultipartUpload response.
+type testS3CompleteMultipartUploadResultStruct struct {
+	XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+	Bucket  string   `xml:"Bucket"`
+	Key     string   `xml:"Key"`
+	ETag    string   `xml:"ETag"`
+}
+
+// `newTestS3Server` starts an empty testS3ServerStruct listening on a loopback port.
+func newTestS3Server() (testS3Server *testS3ServerStruct) {
+	testS3Server = &testS3ServerStruct{
+		objects:         make(map[string]*testS3ObjectStruct),
+		versions:        make(map[string][]*testS3ObjectStruct),
+		uploads:         make(map[string]*testS3UploadStruct),
+		requiredHeaders: make(http.Header),
+	}
+
+	testS3Server.httpServer = httptest.NewServer(testS3Server)
+
+	return
+}
+
+// `close` shuts down the testS3ServerStruct.
+func (testS3Server *testS3ServerStruct) close() {
+	testS3Server.httpServer.Close()
+}
+
+// `endpoint` returns the URL to supply as an S3 backend's "endpoint".
+func (testS3Server *testS3ServerStruct) endpoint() string {
+	return testS3Server.httpServer.URL
+}
+
+// `reset` discards all objects, in-progress multipart uploads, and pending faults.
+func (testS3Server *testS3ServerStruct) reset() {
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	testS3Server.objects = make(map[string]*testS3ObjectStruct)
+	testS3Server.versions = make(map[string][]*testS3ObjectStruct)
+	testS3Server.uploads = make(map[string]*testS3UploadStruct)
+	testS3Server.requests = 0
+	testS3Server.faultsRemaining = 0
+	testS3Server.requiredHeaders = make(http.Header)
+}
+
+// `requireHeader` arranges for each subsequent request lacking header name: value to fail
+// with 403 (AccessDenied) until the next reset().
+func (testS3Server *testS3ServerStruct) requireHeader(name, value string) {
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	testS3Server.requiredHeaders.Set(name, value)
+}
+
+// `injectFaults` arranges for each of the next count requests to be delayed by delay and,
+// if statusCode != 0, then failed with that HTTP status (e.g. http.StatusInternalServerError).
+func (testS3Server *testS3ServerStruct) injectFaults(count uint64, statusCode int, delay time.Duration) {
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	testS3Server.faultsRemaining = count
+	testS3Server.faultStatusCode = statusCode
+	testS3Server.faultDelay = delay
+}
+
+// `requestCount` returns the number of requests received since the last reset().
+func (testS3Server *testS3ServerStruct) requestCount() uint64 {
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	return testS3Server.requests
+}
+
+// `putObject` stores content at bucket/key bypassing the HTTP interface.
+func (testS3Server *testS3ServerStruct) putObject(bucket, key string, content []byte) {
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	testS3Server.storeVersion(bucket+"/"+key, newTestS3Object(content))
+}
+
+// `putObjectVersion` stores content at bucket/key as a version last modified at lastModified
+// (which may well precede that of existing versions) bypassing the HTTP interface.
+func (testS3Server *testS3ServerStruct) putObjectVersion(bucket, key string, content []byte, lastModified time.Time) {
+	var (
+		object = newTestS3Object(content)
+	)
+
+	object.lastModified = lastModified.UTC().Truncate(time.Second)
+
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	testS3Server.storeVersion(bucket+"/"+key, object)
+}
+
+// `putDeleteMarker` stores a delete marker at bucket/key as a version last modified at
+// lastModified bypassing the HTTP interface.
+func (testS3Server *testS3ServerStruct) putDeleteMarker(bucket, key string, lastModified time.Time) {
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	testS3Server.storeVersion(bucket+"/"+key, &testS3ObjectStruct{
+		lastModified: lastModified.UTC().Truncate(time.Second),
+		deleteMarker: true,
+	})
+}
+
+// `storeVersion` assigns a versionID to object (or delete marker) and adds it to the versions
+// of bucketKey. The latest of those (if not a delete marker) becomes the object at bucketKey.
+// The caller must hold testS3Server's lock.
+func (testS3Server *testS3ServerStruct) storeVersion(bucketKey string, object *testS3ObjectStruct) {
+	var (
+		latest   *testS3ObjectStruct
+		versions []*testS3ObjectStruct
+	)
+
+	testS3Server.lastVersionID++
+	object.versionID = "v" + strconv.FormatUint(testS3Server.lastVersionID, 10)
+
+	versions = append(testS3Server.versions[bucketKey], object)
+	slices.SortStableFunc(versions, func(a, b *testS3ObjectStruct) int {
+		return a.lastModified.Compare(b.lastModified)
+	})
+	testS3Server.versions[bucketKey] = versions
+
+	latest = versions[len(versions)-1]
+	if latest.deleteMarker {
+		delete(testS3Server.objects, bucketKey)
+	} else {
+		testS3Server.objects[bucketKey] = latest
+	}
+}
+
+// `getObject` returns the content at bucket/key (if present) bypassing the HTTP interface.
+func (testS3Server *testS3ServerStruct) getObject(bucket, key string) (content []byte, ok bool) {
+	var (
+		object *testS3ObjectStruct
+	)
+
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	object, ok = testS3Server.objects[bucket+"/"+key]
+	if ok {
+		content = object.content
+	}
+
+	return
+}
+
+// `archiveObject` moves the object at bucket/key (if present) to the specified archival storage
+// class (e.g. "GLACIER") bypassing the HTTP interface. Its content may no longer be read until
+// restored via RestoreObject.
+func (testS3Server *testS3ServerStruct) archiveObject(bucket, key, storageClass string) {
+	var (
+		object *testS3ObjectStruct
+		ok     bool
+	)
+
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	object, ok = testS3Server.objects[bucket+"/"+key]
+	if ok {
+		object.storageClass = storageClass
+		object.restore = ""
+	}
+}
+
+// `objectRestore` returns the x-amz-restore value of the object at bucket/key (if present)
+// bypassing the HTTP interface.
+func (testS3Server *testS3ServerStruct) objectRestore(bucket, key string) (restore string, ok bool) {
+	var (
+		object *testS3ObjectStruct
+	)
+
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	object, ok = testS3Server.objects[bucket+"/"+key]
+	if ok {
+		restore = object.restore
+	}
+
+	return
+}
+
+// `completeRestore` completes any restore of the object at bucket/key bypassing the HTTP interface.
+func (testS3Server *testS3ServerStruct) completeRestore(bucket, key string) {
+	var (
+		object *testS3ObjectStruct
+		ok     bool
+	)
+
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	object, ok = testS3Server.objects[bucket+"/"+key]
+	if ok && (object.restore != "") {
+		object.restore = `ongoing-request="false", expiry-date="Fri, 21 Dec 2035 00:00:00 GMT"`
+	}
+}
+
+// `newTestS3Object` wraps content in a testS3ObjectStruct with an MD5-based ETag.
+func newTestS3Object(content []byte) (object *testS3ObjectStruct) {
+	var (
+		contentMD5 = md5.Sum(content)
+	)
+
+	object = &testS3ObjectStruct{
+		content:      content,
+		eTag:         "\"" + hex.EncodeToString(contentMD5[:]) + "\"",
+		lastModified: time.Now().UTC().Truncate(time.Second),
+	}
+
+	return
+}
+
+// `ServeHTTP` implements http.Handler dispatching each S3 request.
+func (testS3Server *testS3ServerStruct) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		bucket          string
+		delay           time.Duration
+		key             string
+		missingHeader   string
+		name            string
+		query           = r.URL.Query()
+		requiredHeaders http.Header
+		statusCode      int
+	)
+
+	testS3Server.Lock()
+	testS3Server.requests++
+	if testS3Server.faultsRemaining > 0 {
+		testS3Server.faultsRemaining--
+		delay = testS3Server.faultDelay
+		statusCode = testS3Server.faultStatusCode
+	}
+	requiredHeaders = testS3Server.requiredHeaders.Clone()
+	testS3Server.Unlock()
+
+	for name = range requiredHeaders {
+		if r.Header.Get(name) != requiredHeaders.Get(name) {
+			missingHeader = name
+			break
+		}
+	}
+	if missingHeader != "" {
+		_, _ = io.Copy(io.Discard, r.Body)
+		testS3WriteError(w, r, http.StatusForbidden, "AccessDenied", "missing or wrong "+missingHeader+" header")
+		return
+	}
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if statusCode != 0 {
+		_, _ = io.Copy(io.Discard, r.Body)
+		testS3WriteError(w, r, statusCode, "InjectedFault", "injected fault")
+		return
+	}
+
+	bucket, key, _ = strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket == "" {
+		if r.Method == http.MethodGet {
+			testS3Server.listBuckets(w)
+		} else {
+			testS3WriteError(w, r, http.StatusNotImplemented, "NotImplemented", "service operation not supported")
+		}
+		return
+	}
+
+	switch {
+	case (key == "") && (r.Method == http.MethodGet) && (query.Get("list-type") == "2"):
+		testS3Server.listObjectsV2(w, r, bucket)
+	case (key == "") && (r.Method == http.MethodGet) && query.Has("versions"):
+		testS3Server.listObjectVersions(w, r, bucket)
+	case (key == "") && (r.Method == http.MethodGet) && query.Has("uploads"):
+		testS3Server.listMultipartUploads(w, r, bucket)
+	case key == "":
+		testS3WriteError(w, r, http.StatusNotImplemented, "NotImplemented", "bucket operation not supported")
+	case (r.Method == http.MethodPost) && query.Has("restore"):
+		testS3Server.restoreObject(w, r, bucket, key)
+	case (r.Method == http.MethodPost) && query.Has("uploads"):
+		testS3Server.createMultipartUpload(w, r, bucket, key)
+	case (r.Method == http.MethodPost) && query.Has("uploadId"):
+		testS3Server.completeMultipartUpload(w, r, bucket, key)
+	case (r.Method == http.MethodPut) && query.Has("uploadId"):
+		testS3Server.uploadPart(w, r)
+	case (r.Method == http.MethodDelete) && query.Has("uploadId"):
+		testS3Server.abortMultipartUpload(w, r)
+	case (r.Method == http.MethodPut) && (r.Header.Get("x-amz-copy-source") != ""):
+		testS3Server.copyObject(w, r, bucket, key)
+	case r.Method == http.MethodPut:
+		testS3Server.putObjectHTTP(w, r, bucket, key)
+	case (r.Method == http.MethodGet) || (r.Method == http.MethodHead):
+		testS3Server.getOrHeadObject(w, r, bucket, key)
+	case r.Method == http.MethodDelete:
+		testS3Server.deleteObject(w, bucket, key)
+	default:
+		testS3WriteError(w, r, http.StatusNotImplemented, "NotImplemented", r.Method+" not supported")
+	}
+}
+
+// `listBuckets` implements ListBuckets reporting (in a single page) each bucket holding at least one object.
+func (testS3Server *testS3ServerStruct) listBuckets(w http.ResponseWriter) {
+	var (
+		bucket                 string
+		bucketKey              string
+		buckets                []string
+		listAllMyBucketsResult = &testS3ListAllMyBucketsResultStruct{}
+	)
+
+	testS3Server.Lock()
+	for bucketKey = range testS3Server.objects {
+		bucket, _, _ = strings.Cut(bucketKey, "/")
+		if !slices.Contains(buckets, bucket) {
+			buckets = append(buckets, bucket)
+		}
+	}
+	testS3Server.Unlock()
+
+	slices.Sort(buckets)
+
+	for _, bucket = range buckets {
+		listAllMyBucketsResult.Buckets.Bucket = append(listAllMyBucketsResult.Buckets.Bucket, testS3BucketStruct{
+			Name:         bucket,
+			CreationDate: time.Unix(0, 0).UTC().Format(time.RFC3339),
+		})
+	}
+
+	testS3WriteXML(w, http.StatusOK, listAllMyBucketsResult)
+}
+
+// `listObjectsV2` implements ListObjectsV2 honoring prefix, delimiter, start-after,
+// continuation-token, and max-keys. The continuation token is the last key (or common
+// prefix) returned.
+func (testS3Server *testS3ServerStruct) listObjectsV2(w http.ResponseWriter, r *http.Request, bucket string) {
+	var (
+		bucketKey         string
+		commonPrefix      string
+		continuationToken = r.URL.Query().Get("continuation-token")
+		delimiter         = r.URL.Query().Get("delimiter")
+		delimiterIndex    int
+		err               error
+		key               string
+		keys              []string
+		lastCommonPrefix  string
+		listBucketResult  *testS3ListBucketResultStruct
+		marker            string
+		maxKeys           = 1000
+		object            *testS3ObjectStruct
+		prefix            = r.URL.Query().Get("prefix")
+		startAfter        = r.URL.Query().Get("start-after")
+	)
+
+	if r.URL.Query().Has("max-keys") {
+		maxKeys, err = strconv.Atoi(r.URL.Query().Get("max-keys"))
+		if (err != nil) || (maxKeys < 0) {
+			testS3WriteError(w, r, http.StatusBadRequest, "InvalidArgument", "bad max-keys")
+			return
+		}
+	}
+
+	marker = max(startAfter, continuationToken)
+
+	listBucketResult = &testS3ListBucketResultStruct{
+		Name:              bucket,
+		Prefix:            prefix,
+		Delimiter:         delimiter,
+		StartAfter:        startAfter,
+		ContinuationToken: continuationToken,
+		MaxKeys:           maxKeys,
+	}
+
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	for bucketKey = range testS3Server.objects {
+		key = strings.TrimPrefix(bucketKey, bucket+"/")
+		if (key != bucketKey) && strings.HasPrefix(key, prefix) && (key > marker) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key = range keys {
+		// Skip the remaining keys of a common prefix already returned on a prior page
+
+		if (delimiter != "") && strings.HasSuffix(marker, delimiter) && strings.HasPrefix(key, marker) {
+			continue
+		}
+
+		commonPrefix = ""
+		if delimiter != "" {
+			delimiterIndex = strings.Index(key[len(prefix):], delimiter)
+			if delimiterIndex >= 0 {
+				commonPrefix = key[:len(prefix)+delimiterIndex+len(delimiter)]
+				if commonPrefix == lastCommonPrefix {
+					continue
+				}
+			}
+		}
+
+		if listBucketResult.KeyCount == maxKeys {
+			listBucketResult.IsTruncated = true
+			break
+		}
+
+		listBucketResult.KeyCount++
+
+		if commonPrefix == "" {
+			object = testS3Server.objects[bucket+"/"+key]
+			listBucketResult.Contents = append(listBucketResult.Contents, testS3ListContentsStruct{
+				Key:          key,
+				LastModified: object.lastModified.Format(time.RFC3339),
+				ETag:         object.eTag,
+				Size:         int64(len(object.content)),
+				StorageClass: cmp.Or(object.storageClass, "STANDARD"),
+			})
+			listBucketResult.NextContinuationToken = key
+		} else {
+			listBucketResult.CommonPrefixes = append(listBucketResult.CommonPrefixes, testS3ListCommonPrefixStruct{Prefix: commonPrefix})
+			listBucketResult.NextContinuationToken = commonPrefix
+			lastCommonPrefix = commonPrefix
+		}
+	}
+
+	if !listBucketResult.IsTruncated {
+		listBucketResult.NextContinuationToken = ""
+	}
+
+	testS3WriteXML(w, http.StatusOK, listBucketResult)
+}
+
+// `listObjectVersions` implements ListObjectVersions honoring prefix, delimiter, key-marker,
+// version-id-marker, and max-keys. The versions of each key are returned newest first.
+func (testS3Server *testS3ServerStruct) listObjectVersions(w http.ResponseWriter, r *http.Request, bucket string) {
+	var (
+		bucketKey          string
+		commonPrefix       string
+		delimiter          = r.URL.Query().Get("delimiter")
+		delimiterIndex     int
+		err                error
+		key                string
+		keyCount           int
+		keyMarker          = r.URL.Query().Get("key-marker")
+		keys               []string
+		lastCommonPrefix   string
+		listVersionsResult *testS3ListVersionsResultStruct
+		maxKeys            = 1000
+		prefix             = r.URL.Query().Get("prefix")
+		skipping           bool
+		version            *testS3ObjectStruct
+		versionIDMarker    = r.URL.Query().Get("version-id-marker")
+		versionIndex       int
+		versions           []*testS3ObjectStruct
+	)
+
+	if r.URL.Query().Has("max-keys") {
+		maxKeys, err = strconv.Atoi(r.URL.Query().Get("max-keys"))
+		if (err != nil) || (maxKeys < 0) {
+			testS3WriteError(w, r, http.StatusBadRequest, "InvalidArgument", "bad max-keys")
+			return
+		}
+	}
+
+	listVersionsResult = &testS3ListVersionsResultStruct{
+		Name:            bucket,
+		Prefix:          prefix,
+		Delimiter:       delimiter,
+		KeyMarker:       keyMarker,
+		VersionIDMarker: versionIDMarker,
+		MaxKeys:         maxKeys,
+	}
+
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	for bucketKey = range testS3Server.versions {
+		key = strings.TrimPrefix(bucketKey, bucket+"/")
+		if (key != bucketKey) && strings.HasPrefix(key, prefix) && (key >= keyMarker) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+KeysLoop:
+	for _, key = range keys {
+		// Skip keyMarker itself unless resuming within its versions as well as
+		// the remaining keys of a common prefix already returned on a prior page
+
+		if (key == keyMarker) && (versionIDMarker == "") {
+			continue
+		}
+		if (delimiter != "") && strings.HasSuffix(keyMarker, delimiter) && strings.HasPrefix(key, keyMarker) {
+			continue
+		}
+
+		commonPrefix = ""
+		if delimiter != "" {
+			delimiterIndex = strings.Index(key[len(prefix):], delimiter)
+			if delimiterIndex >= 0 {
+				commonPrefix = key[:len(prefix)+delimiterIndex+len(delimiter)]
+				if commonPrefix == lastCommonPrefix {
+					continue
+				}
+			}
+		}
+
+		if commonPrefix != "" {
+			if keyCount == maxKeys {
+				listVersionsResult.IsTruncated = true
+				break
+			}
+			keyCount++
+			listVersionsResult.CommonPrefixes = append(listVersionsResult.CommonPrefixes, testS3ListCommonPrefixStruct{Prefix: commonPrefix})
+			listVersionsResult.NextKeyMarker = commonPrefix
+			listVersionsResult.NextVersionIDMarker = ""
+			lastCommonPrefix = commonPrefix
+			continue
+		}
+
+		versions = testS3Server.versions[bucket+"/"+key]
+		skipping = (key == keyMarker)
+
+		for versionIndex = len(versions) - 1; versionIndex >= 0; versionIndex-- {
+			version = versions[versionIndex]
+
+			if skipping {
+				skipping = (version.versionID != versionIDMarker)
+				continue
+			}
+
+			if keyCount == maxKeys {
+				listVersionsResult.IsTruncated = true
+				break KeysLoop
+			}
+			keyCount++
+
+			if version.deleteMarker {
+				listVersionsResult.DeleteMarker = append(listVersionsResult.DeleteMarker, testS3ListDeleteMarkerStruct{
+					Key:          key,
+					VersionID:    version.versionID,
+					IsLatest:     versionIndex == (len(versions) - 1),
+					LastModified: version.lastModified.Format(time.RFC3339),
+				})
+			} else {
+				listVersionsResult.Version = append(listVersionsResult.Version, testS3ListVersionStruct{
+					Key:          key,
+					VersionID:    version.versionID,
+					IsLatest:     versionIndex == (len(versions) - 1),
+					LastModified: version.lastModified.Format(time.RFC3339),
+					ETag:         version.eTag,
+					Size:         int64(len(version.content)),
+					StorageClass: cmp.Or(version.storageClass, "STANDARD"),
+				})
+			}
+
+			listVersionsResult.NextKeyMarker = key
+			listVersionsResult.NextVersionIDMarker = version.versionID
+		}
+	}
+
+	if !listVersionsResult.IsTruncated {
+		listVersionsResult.NextKeyMarker = ""
+		listVersionsResult.NextVersionIDMarker = ""
+	}
+
+	testS3WriteXML(w, http.StatusOK, listVersionsResult)
+}
+
+// `getOrHeadObject` implements GetObject (including a single "bytes=" Range) and HeadObject
+// honoring If-Match, If-None-Match, and versionId. A GetObject of an archived object not yet
+// restored fails with InvalidObjectState.
+func (testS3Server *testS3ServerStruct) getOrHeadObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	var (
+		err          error
+		object       *testS3ObjectStruct
+		ok           bool
+		rangeBegin   int64
+		rangeEnd     int64
+		restore      string
+		statusCode   = http.StatusOK
+		storageClass string
+		versionID    = r.URL.Query().Get("versionId")
+	)
+
+	testS3Server.Lock()
+	if versionID == "" {
+		object, ok = testS3Server.objects[bucket+"/"+key]
+	} else {
+		object, ok = testS3Server.lookupVersion(bucket+"/"+key, versionID)
+	}
+	if ok {
+		restore = object.restore
+		storageClass = object.storageClass
+	}
+	testS3Server.Unlock()
+
+	if !ok {
+		if versionID == "" {
+			testS3WriteError(w, r, http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+		} else {
+			testS3WriteError(w, r, http.StatusNotFound, "NoSuchVersion", "the specified version does not exist")
+		}
+		return
+	}
+
+	if object.deleteMarker {
+		w.Header().Set("x-amz-delete-marker", "true")
+		testS3WriteError(w, r, http.StatusMethodNotAllowed, "MethodNotAllowed", "the specified version is a delete marker")
+		return
+	}
+
+	if (r.Method == http.MethodGet) && ((storageClass == "GLACIER") || (storageClass == "DEEP_ARCHIVE")) && !strings.Contains(restore, `ongoing-request="false"`) {
+		testS3WriteError(w, r, http.StatusForbidden, "InvalidObjectState", "the operation is not valid for the object's storage class")
+		return
+	}
+
+	if !testS3CheckPreconditions(w, r, object, r.Header.Get("If-Match"), r.Header.Get("If-None-Match")) {
+		return
+	}
+
+	rangeBegin = 0
+	rangeEnd = int64(len(object.content)) - 1
+
+	if r.Header.Get("Range") != "" {
+		rangeBegin, rangeEnd, err = testS3ParseRange(r.Header.Get("Range"), int64(len(object.content)))
+		if err != nil {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(object.content)))
+			testS3WriteError(w, r, http.StatusRequestedRangeNotSatisfiable, "InvalidRange", err.Error())
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rangeBegin, rangeEnd, len(object.content)))
+		statusCode = http.StatusPartialContent
+	}
+
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("Content-Length", strconv.FormatInt(rangeEnd-rangeBegin+1, 10))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("ETag", object.eTag)
+	w.Header().Set("Last-Modified", object.lastModified.Format(http.TimeFormat))
+	w.Header().Set("x-amz-version-id", object.versionID)
+	if storageClass != "" {
+		w.Header().Set("x-amz-storage-class", storageClass)
+	}
+	if restore != "" {
+		w.Header().Set("x-amz-restore", restore)
+	}
+	w.WriteHeader(statusCode)
+
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(object.content[rangeBegin : rangeEnd+1])
+	}
+}
+
+// `lookupVersion` returns the version (possibly a delete marker) of bucketKey identified by
+// versionID. The caller must hold testS3Server's lock.
+func (testS3Server *testS3ServerStruct) lookupVersion(bucketKey, versionID string) (object *testS3ObjectStruct, ok bool) {
+	for _, object = range testS3Server.versions[bucketKey] {
+		if object.versionID == versionID {
+			ok = true
+			return
+		}
+	}
+
+	object = nil
+
+	return
+}
+
+// `restoreObject` implements RestoreObject. The restore never completes on its own; a test
+// may complete it by setting the object's restore to `ongoing-request="false"`.
+func (testS3Server *testS3ServerStruct) restoreObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	var (
+		object *testS3ObjectStruct
+		ok     bool
+	)
+
+	_, _ = io.Copy(io.Discard, r.Body)
+
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	object, ok = testS3Server.objects[bucket+"/"+key]
+	if !ok {
+		testS3WriteError(w, r, http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+		return
+	}
+
+	if (object.storageClass != "GLACIER") && (object.storageClass != "DEEP_ARCHIVE") {
+		testS3WriteError(w, r, http.StatusForbidden, "InvalidObjectState", "the object is not archived")
+		return
+	}
+
+	if object.restore == `ongoing-request="true"` {
+		testS3WriteError(w, r, http.StatusConflict, "RestoreAlreadyInProgress", "object restore is already in progress")
+		return
+	}
+
+	object.restore = `ongoing-request="true"`
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// `putObjectHTTP` implements PutObject.
+func (testS3Server *testS3ServerStruct) putObjectHTTP(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	var (
+		content []byte
+		err     error
+		object  *testS3ObjectStruct
+	)
+
+	content, err = testS3ReadBody(r)
+	if err != nil {
+		testS3WriteError(w, r, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	object = newTestS3Object(content)
+
+	testS3Server.Lock()
+	testS3Server.storeVersion(bucket+"/"+key, object)
+	testS3Server.Unlock()
+
+	w.Header().Set("ETag", object.eTag)
+	w.WriteHeader(http.StatusOK)
+}
+
+// `copyObject` implements CopyObject honoring x-amz-copy-source-if-match.
+func (testS3Server *testS3ServerStruct) copyObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	var (
+		object    *testS3ObjectStruct
+		ok        bool
+		srcObject *testS3ObjectStruct
+	)
+
+	srcObject, ok = testS3Server.lookupCopySource(w, r)
+	if !ok {
+		return
+	}
+
+	object = newTestS3Object(slices.Clone(srcObject.content))
+
+	testS3Server.Lock()
+	testS3Server.storeVersion(bucket+"/"+key, object)
+	testS3Server.Unlock()
+
+	testS3WriteXML(w, http.StatusOK, &testS3CopyResultStruct{
+		XMLName:      xml.Name{Local: "CopyObjectResult"},
+		ETag:         object.eTag,
+		LastModified: object.lastModified.Format(time.RFC3339),
+	})
+}
+
+// `deleteObject` implements DeleteObject (which succeeds even if the key does not exist) by
+// adding a delete marker to the versions of an existing object.
+func (testS3Server *testS3ServerStruct) deleteObject(w http.ResponseWriter, bucket, key string) {
+	var (
+		ok bool
+	)
+
+	testS3Server.Lock()
+	_, ok = testS3Server.objects[bucket+"/"+key]
+	if ok {
+		testS3Server.storeVersion(bucket+"/"+key, &testS3ObjectStruct{
+			lastModified: time.Now().UTC().Truncate(time.Second),
+			deleteMarker: true,
+		})
+	}
+	testS3Server.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// `createMultipartUpload` implements CreateMultipartUpload.
+func (testS3Server *testS3ServerStruct) createMultipartUpload(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	var (
+		uploadID string
+	)
+
+	_, _ = io.Copy(io.Discard, r.Body)
+
+	testS3Server.Lock()
+	testS3Server.lastUploadID++
+	uploadID = strconv.FormatUint(testS3Server.lastUploadID, 10)
+	testS3Server.uploads[uploadID] = &testS3UploadStruct{
+		bucketKey: bucket + "/" + key,
+		initiated: time.Now(),
+		parts:     make(map[int32][]byte),
+	}
+	testS3Server.Unlock()
+
+	testS3WriteXML(w, http.StatusOK, &testS3InitiateMultipartUploadResultStruct{
+		Bucket:   bucket,
+		Key:      key,
+		UploadID: uploadID,
+	})
+}
+
+// `putMultipartUpload` creates a multipart upload of bucket/key (as if initiated at initiated)
+// bypassing the HTTP interface, returning its UploadId.
+func (testS3Server *testS3ServerStruct) putMultipartUpload(bucket, key string, initiated time.Time) (uploadID string) {
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	testS3Server.lastUploadID++
+	uploadID = strconv.FormatUint(testS3Server.lastUploadID, 10)
+	testS3Server.uploads[uploadID] = &testS3UploadStruct{
+		bucketKey: bucket + "/" + key,
+		initiated: initiated.UTC().Truncate(time.Second),
+		parts:     make(map[int32][]byte),
+	}
+
+	return
+}
+
+// `hasMultipartUpload` reports whether the multipart upload identified by uploadID is in progress.
+func (testS3Server *testS3ServerStruct) hasMultipartUpload(uploadID string) (ok bool) {
+	testS3Server.Lock()
+	_, ok = testS3Server.uploads[uploadID]
+	testS3Server.Unlock()
+
+	return
+}
+
+// `listMultipartUploads` implements ListMultipartUploads (in a single page ordered by key and UploadId).
+func (testS3Server *testS3ServerStruct) listMultipartUploads(w http.ResponseWriter, r *http.Request, bucket string) {
+	var (
+		key                        string
+		listMultipartUploadsResult *testS3ListMultipartUploadsResultStruct
+		prefix                     = r.URL.Query().Get("prefix")
+		upload                     *testS3UploadStruct
+		uploadID                   string
+	)
+
+	listMultipartUploadsResult = &testS3ListMultipartUploadsResultStruct{
+		Bucket: bucket,
+		Prefix: prefix,
+	}
+
+	testS3Server.Lock()
+	for uploadID, upload = range testS3Server.uploads {
+		key = strings.TrimPrefix(upload.bucketKey, bucket+"/")
+		if (key == upload.bucketKey) || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		listMultipartUploadsResult.Upload = append(listMultipartUploadsResult.Upload, testS3ListUploadsStruct{
+			Key:       key,
+			UploadID:  uploadID,
+			Initiated: upload.initiated.UTC().Format(time.RFC3339),
+		})
+	}
+	testS3Server.Unlock()
+
+	slices.SortFunc(listMultipartUploadsResult.Upload, func(a, b testS3ListUploadsStruct) int {
+		return cmp.Or(strings.Compare(a.Key, b.Key), strings.Compare(a.UploadID, b.UploadID))
+	})
+
+	testS3WriteXML(w, http.StatusOK, listMultipartUploadsResult)
+}
+
+// `uploadPart` implements both UploadPart and (if x-amz-copy-source is present) UploadPartCopy.
+func (testS3Server *testS3ServerStruct) uploadPart(w http.ResponseWriter, r *http.Request) {
+	var (
+		content    []byte
+		contentMD5 [md5.Size]byte
+		err        error
+		ok         bool
+		partNumber int
+		rangeBegin int64
+		rangeEnd   int64
+		srcObject  *testS3ObjectStruct
+		upload     *testS3UploadStruct
+	)
+
+	partNumber, err = strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if (err != nil) || (partNumber < 1) || (partNumber > 10000) {
+		testS3WriteError(w, r, http.StatusBadRequest, "InvalidArgument", "bad partNumber")
+		return
+	}
+
+	if r.Header.Get("x-amz-copy-source") == "" {
+		content, err = testS3ReadBody(r)
+		if err != nil {
+			testS3WriteError(w, r, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+	} else {
+		srcObject, ok = testS3Server.lookupCopySource(w, r)
+		if !ok {
+			return
+		}
+		content = srcObject.content
+		if r.Header.Get("x-amz-copy-source-range") != "" {
+			rangeBegin, rangeEnd, err = testS3ParseRange(r.Header.Get("x-amz-copy-source-range"), int64(len(content)))
+			if err != nil {
+				testS3WriteError(w, r, http.StatusBadRequest, "InvalidArgument", err.Error())
+				return
+			}
+			content = content[rangeBegin : rangeEnd+1]
+		}
+		content = slices.Clone(content)
+	}
+
+	testS3Server.Lock()
+	upload, ok = testS3Server.uploads[r.URL.Query().Get("uploadId")]
+	if ok {
+		upload.parts[int32(partNumber)] = content
+	}
+	testS3Server.Unlock()
+
+	if !ok {
+		testS3WriteError(w, r, http.StatusNotFound, "NoSuchUpload", "the specified upload does not exist")
+		return
+	}
+
+	contentMD5 = md5.Sum(content)
+
+	if r.Header.Get("x-amz-copy-source") == "" {
+		w.Header().Set("ETag", "\""+hex.EncodeToString(contentMD5[:])+"\"")
+		w.WriteHeader(http.StatusOK)
+	} else {
+		testS3WriteXML(w, http.StatusOK, &testS3CopyResultStruct{
+			XMLName:      xml.Name{Local: "CopyPartResult"},
+			ETag:         "\"" + hex.EncodeToString(contentMD5[:]) + "\"",
+			LastModified: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+// `completeMultipartUpload` implements CompleteMultipartUpload concatenating the listed parts.
+func (testS3Server *testS3ServerStruct) completeMultipartUpload(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	var (
+		body                    []byte
+		completeMultipartUpload testS3CompleteMultipartUploadStruct
+		content                 []byte
+		err                     error
+		object                  *testS3ObjectStruct
+		ok                      bool
+		partContent             []byte
+		partIndex               int
+		upload                  *testS3UploadStruct
+		uploadID                = r.URL.Query().Get("uploadId")
+	)
+
+	body, err = testS3ReadBody(r)
+	if err == nil {
+		err = xml.Unmarshal(body, &completeMultipartUpload)
+	}
+	if err != nil {
+		testS3WriteError(w, r, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+
+	testS3Server.Lock()
+	defer testS3Server.Unlock()
+
+	upload, ok = testS3Server.uploads[uploadID]
+	if !ok || (upload.bucketKey != bucket+"/"+key) {
+		testS3WriteError(w, r, http.StatusNotFound, "NoSuchUpload", "the specified upload does not exist")
+		return
+	}
+
+	for partIndex = range completeMultipartUpload.Part {
+		if (partIndex > 0) && (completeMultipartUpload.Part[partIndex].PartNumber <= completeMultipartUpload.Part[partIndex-1].PartNumber) {
+			testS3WriteError(w, r, http.StatusBadRequest, "InvalidPartOrder", "parts must be listed in ascending order")
+			return
+		}
+		partContent, ok = upload.parts[completeMultipartUpload.Part[partIndex].PartNumber]
+		if !ok {
+			testS3WriteError(w, r, http.StatusBadRequest, "InvalidPart", "a listed part was not uploaded")
+			return
+		}
+		content = append(content, partContent...)
+	}
+
+	object = newTestS3Object(content)
+	object.eTag = strings.TrimSuffix(object.eTag, "\"") + "-" + strconv.Itoa(len(completeMultipartUpload.Part)) + "\""
+
+	testS3Server.storeVersion(upload.bucketKey, object)
+	delete(testS3Server.uploads, uploadID)
+
+	testS3WriteXML(w, http.StatusOK, &testS3CompleteMultipartUploadResultStruct{
+		Bucket: bucket,
+		Key:    key,
+		ETag:   object.eTag,
+	})
+}
+
+// `abortMultipartUpload` implements AbortMultipartUpload.
+func (testS3Server *testS3ServerStruct) abortMultipartUpload(w http.ResponseWriter, r *http.Request) {
+	testS3Server.Lock()
+	delete(testS3Server.uploads, r.URL.Query().Get("uploadId"))
+	testS3Server.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// `lookupCopySource` locates the object (or, if a "?versionId=" is appended, the object version)
+// named by x-amz-copy-source honoring x-amz-copy-source-if-match. If !ok, an error response has
+// already been written.
+func (testS3Server *testS3ServerStruct) lookupCopySource(w http.ResponseWriter, r *http.Request) (srcObject *testS3ObjectStruct, ok bool) {
+	var (
+		copySource string
+		err        error
+		versionID  string
+	)
+
+	copySource, versionID, _ = strings.Cut(strings.TrimPrefix(r.Header.Get("x-amz-copy-source"), "/"), "?versionId=")
+
+	copySource, err = url.PathUnescape(copySource)
+	if err == nil {
+		versionID, err = url.QueryUnescape(versionID)
+	}
+	if err != nil {
+		testS3WriteError(w, r, http.StatusBadRequest, "InvalidArgument", "bad x-amz-copy-source")
+		return
+	}
+
+	testS3Server.Lock()
+	if versionID == "" {
+		srcObject, ok = testS3Server.objects[copySource]
+	} else {
+		srcObject, ok = testS3Server.lookupVersion(copySource, versionID)
+		ok = ok && !srcObject.deleteMarker
+	}
+	testS3Server.Unlock()
+
+	if !ok {
+		testS3WriteError(w, r, http.StatusNotFound, "NoSuchKey", "the specified copy source does not exist")
+		return
+	}
+
+	ok = testS3CheckPreconditions(w, r, srcObject, r.Header.Get("x-amz-copy-source-if-match"), "")
+
+	return
+}
+
+// `testS3CheckPreconditions` evaluates ifMatch and ifNoneMatch against object. If false is
+// returned, the corresponding error (412 or 304) response has already been written.
+func testS3CheckPreconditions(w http.ResponseWriter, r *http.Request, object *testS3ObjectStruct, ifMatch, ifNoneMatch string) bool {
+	if (ifMatch != "") && (strings.Trim(ifMatch, "\"") != strings.Trim(object.eTag, "\"")) {
+		testS3WriteError(w, r, http.StatusPreconditionFailed, "PreconditionFailed", "at least one of the preconditions did not hold")
+		return false
+	}
+
+	if (ifNoneMatch != "") && (strings.Trim(ifNoneMatch, "\"") == strings.Trim(object.eTag, "\"")) {
+		w.Header().Set("ETag", object.eTag)
+		w.WriteHeader(http.StatusNotModified)
+		return false
+	}
+
+	return true
+}
+
+// `testS3ParseRange` parses a single "bytes=<begin>-[<end>]" range clamping end to size-1.
+func testS3ParseRange(rangeHeader string, size int64) (rangeBegin, rangeEnd int64, err error) {
+	var (
+		found           bool
+		rangeBeginAsStr string
+		rangeEndAsStr   string
+	)
+
+	rangeBeginAsStr, rangeEndAsStr, found = strings.Cut(strings.TrimPrefix(rangeHeader, "bytes="), "-")
+	if !found {
+		err = fmt.Errorf("bad range \"%s\"", rangeHeader)
+		return
+	}
+
+	rangeBegin, err = strconv.ParseInt(rangeBeginAsStr, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("bad range \"%s\"", rangeHeader)
+		return
+	}
+
+	if rangeEndAsStr == "" {
+		rangeEnd = size - 1
+	} else {
+		rangeEnd, err = strconv.ParseInt(rangeEndAsStr, 10, 64)
+		if err != nil {
+			err = fmt.Errorf("bad range \"%s\"", rangeHeader)
+			return
+		}
+		rangeEnd = min(rangeEnd, size-1)
+	}
+
+	if (rangeBegin >= size) || (rangeBegin > rangeEnd) {
+		err = fmt.Errorf("range \"%s\" not satisfiable for size %d", rangeHeader, size)
+		return
+	}
+
+	return
+}
+
+// `testS3ReadBody` returns the request payload decoding any "aws-chunked" Content-Encoding
+// (used by the SDK when sending trailing checksums).
+func testS3ReadBody(r *http.Request) (content []byte, err error) {
+	var (
+		body           []byte
+		chunkHeader    []byte
+		chunkSize      int64
+		chunkSizeAsStr string
+		found          bool
+	)
+
+	body, err = io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+
+	if !strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") {
+		content = body
+		return
+	}
+
+	content = make([]byte, 0, len(body))
+
+	for {
+		chunkHeader, body, found = bytes.Cut(body, []byte("\r\n"))
+		if !found {
+			err = errors.New("truncated aws-chunked chunk header")
+			return
+		}
+
+		chunkSizeAsStr, _, _ = strings.Cut(string(chunkHeader), ";")
+
+		chunkSize, err = strconv.ParseInt(chunkSizeAsStr, 16, 64)
+		if err != nil {
+			err = fmt.Errorf("bad aws-chunked chunk size \"%s\"", chunkSizeAsStr)
+			return
+		}
+
+		if chunkSize == 0 {
+			// Any trailing headers (e.g. x-amz-checksum-crc32) are ignored
+			return
+		}
+
+		if int64(len(body)) < chunkSize+2 {
+			err = errors.New("truncated aws-chunked chunk")
+			return
+		}
+
+		content = append(content, body[:chunkSize]...)
+		body = body[chunkSize+2:]
+	}
+}
+
+// `testS3WriteXML` writes an XML-encoded response body.
+func testS3WriteXML(w http.ResponseWriter, statusCode int, v interface{}) {
+	var (
+		body []byte
+		err  error
+	)
+
+	body, err = xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Length", strconv.Itoa(len(xml.Header)+len(body)))
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(body)
+}
+
+// `testS3WriteError` writes an S3 error response (without a body for HEAD requests).
+func testS3WriteError(w http.ResponseWriter, r *http.Request, statusCode int, code string, message string) {
+	if r.Method == http.MethodHead {
+		w.WriteHeader(statusCode)
+		return
+	}
+
+	testS3WriteXML(w, statusCode, &testS3ErrorStruct{
+		Code:    code,
+		Message: message,
+	})
+}
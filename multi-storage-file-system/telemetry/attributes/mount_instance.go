@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attributes
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// `MountInstance` identifies a single MSCP daemon (i.e. mount instance).
+type MountInstance struct {
+	ID               string                // Random UUID generated at startup
+	StartTime        time.Time             // When the daemon started
+	FUSEConnectionID func() (uint64, bool) // Returns the FUSE connection ID (as in /sys/fs/fuse/connections/<id>) or !ok if not (yet) mounted
+}
+
+// `MountInstanceAttributesProvider` provides attributes identifying the mount instance such that
+// multiple MSCP daemons on one host may be distinguished in metric backends.
+// MSCP's equivalent of Python: `multistorageclient.telemetry.attributes.thread.ThreadAttributesProvider`
+// (as MSCP is a single-process daemon, its mount instance rather than a thread distinguishes its metrics)
+type MountInstanceAttributesProvider struct {
+	// Map of attribute key to mount instance attribute type
+	attributes map[string]string
+	instance   *MountInstance
+}
+
+// `NewMountInstanceAttributesProvider` creates a new mount instance attributes provider.
+// Options should contain "attributes" key with a map[string]string mapping attribute keys to mount instance attributes.
+// Supported mount instance attributes: "instance_id" (UUID), "start_time" (RFC 3339), and "fuse_connection_id"
+func NewMountInstanceAttributesProvider(options map[string]interface{}, instance *MountInstance) *MountInstanceAttributesProvider {
+	attrs := make(map[string]string)
+
+	if attrsInterface, ok := options["attributes"]; ok {
+		if attrsMap, ok := attrsInterface.(map[string]interface{}); ok {
+			for key, value := range attrsMap {
+				if strValue, ok := value.(string); ok {
+					attrs[key] = strValue
+				}
+			}
+		}
+	}
+
+	return &MountInstanceAttributesProvider{
+		attributes: attrs,
+		instance:   instance,
+	}
+}
+
+// `Attributes` returns attributes identifying the mount instance.
+// The "fuse_connection_id" attribute is omitted until the FUSE mount is in place.
+func (p *MountInstanceAttributesProvider) Attributes() []attribute.KeyValue {
+	result := []attribute.KeyValue{}
+
+	for attrKey, mountInstanceAttr := range p.attributes {
+		switch mountInstanceAttr {
+		case "instance_id":
+			result = append(result, attribute.String(attrKey, p.instance.ID))
+		case "start_time":
+			result = append(result, attribute.String(attrKey, p.instance.StartTime.UTC().Format(time.RFC3339)))
+		case "fuse_connection_id":
+			if p.instance.FUSEConnectionID != nil {
+				if connectionID, ok := p.instance.FUSEConnectionID(); ok {
+					result = append(result, attribute.Int64(attrKey, int64(connectionID)))
+				}
+			}
+		}
+	}
+
+	return result
+}
@@ -18,6 +18,7 @@ package readers
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -31,6 +32,13 @@ const (
 	DefaultCollectTimeoutMillis  = 10000 // 10 seconds
 	DefaultExportIntervalMillis  = 60000 // 60 seconds
 	DefaultExportTimeoutMillis   = 30000 // 30 seconds
+	DefaultMaxBufferedSnapshots  = 600   // 10 export intervals of snapshots at the default intervals (an MSFS extension)
+)
+
+// Supported temporalities (see WithTemporality())
+const (
+	TemporalityCumulative = "cumulative" // Default (matches ManualReader's default)
+	TemporalityDelta      = "delta"
 )
 
 // DiperiodicReader implements the diperiodic pattern by wrapping ManualReader.
@@ -42,6 +50,7 @@ const (
 // 1. Collects snapshots every collectInterval (default 1s) into collectBuffer
 // 2. Exports accumulated snapshots every exportInterval (default 60s) from exportBuffer
 // 3. Uses double buffering to avoid blocking during export
+// 4. Retains snapshots that failed to export for the next export, dropping the oldest beyond maxBufferedSnapshots
 //
 // Matches Python MSC's DiperiodicExportingMetricReader exactly.
 //
@@ -52,12 +61,19 @@ type DiperiodicReader struct {
 	*metric.ManualReader // Embed to inherit Reader interface
 	exporter             metric.Exporter
 
+	// Options applied to the embedded ManualReader (defaults if none supplied)
+	manualReaderOptions []metric.ManualReaderOption
+
 	// Intervals
 	collectInterval time.Duration
 	collectTimeout  time.Duration
 	exportInterval  time.Duration
 	exportTimeout   time.Duration
 
+	// Bound on len(collectBuffer) - beyond which the oldest snapshots are dropped (and counted)
+	maxBufferedSnapshots int
+	droppedSnapshots     atomic.Uint64
+
 	// Double buffering - accumulate data points across multiple collections
 	collectBuffer []metricdata.ResourceMetrics
 	exportBuffer  []metricdata.ResourceMetrics
@@ -107,6 +123,52 @@ func WithExportTimeout(d time.Duration) Option {
 	}
 }
 
+// WithMaxBufferedSnapshots sets the maximum number of snapshots buffered awaiting export
+func WithMaxBufferedSnapshots(n int) Option {
+	return func(r *DiperiodicReader) {
+		r.maxBufferedSnapshots = n
+	}
+}
+
+// WithTemporalitySelector sets the temporality the embedded ManualReader reports for each instrument kind
+// (default: metric.DefaultTemporalitySelector, i.e. cumulative for all instrument kinds)
+func WithTemporalitySelector(selector metric.TemporalitySelector) Option {
+	return func(r *DiperiodicReader) {
+		r.manualReaderOptions = append(r.manualReaderOptions, metric.WithTemporalitySelector(selector))
+	}
+}
+
+// WithTemporality sets the temporality by name (TemporalityCumulative or TemporalityDelta).
+// Unknown names leave the default (cumulative) temporality in place.
+func WithTemporality(temporality string) Option {
+	return func(r *DiperiodicReader) {
+		if temporality == TemporalityDelta {
+			r.manualReaderOptions = append(r.manualReaderOptions, metric.WithTemporalitySelector(DeltaTemporalitySelector))
+		}
+	}
+}
+
+// WithAggregationSelector sets the default aggregation the embedded ManualReader uses for each instrument kind
+// (default: metric.DefaultAggregationSelector). Per-instrument aggregations are instead set via metric.View's
+// supplied to the MeterProvider.
+func WithAggregationSelector(selector metric.AggregationSelector) Option {
+	return func(r *DiperiodicReader) {
+		r.manualReaderOptions = append(r.manualReaderOptions, metric.WithAggregationSelector(selector))
+	}
+}
+
+// DeltaTemporalitySelector reports delta temporality for all instrument kinds but the UpDownCounters
+// (as does OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE=delta). Gauges then only report values
+// recorded since the previous collection rather than repeating their last value in every snapshot.
+func DeltaTemporalitySelector(kind metric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case metric.InstrumentKindUpDownCounter, metric.InstrumentKindObservableUpDownCounter:
+		return metricdata.CumulativeTemporality
+	default:
+		return metricdata.DeltaTemporality
+	}
+}
+
 // NewDiperiodicReader creates a new diperiodic metric reader.
 //
 // The reader runs two background goroutines:
@@ -122,7 +184,6 @@ func NewDiperiodicReader(exporter metric.Exporter, opts ...Option) metric.Reader
 	ctx, cancelFunc := context.WithCancel(context.Background())
 
 	r := &DiperiodicReader{
-		ManualReader:    metric.NewManualReader(), // Embed SDK's ManualReader
 		exporter:        exporter,
 		collectInterval: DefaultCollectIntervalMillis * time.Millisecond,
 		collectTimeout:  DefaultCollectTimeoutMillis * time.Millisecond,
@@ -133,6 +194,8 @@ func NewDiperiodicReader(exporter metric.Exporter, opts ...Option) metric.Reader
 		ctx:             ctx,
 		cancelFunc:      cancelFunc,
 		flushChan:       make(chan chan error, 1),
+
+		maxBufferedSnapshots: DefaultMaxBufferedSnapshots,
 	}
 
 	// Apply options
@@ -140,6 +203,9 @@ func NewDiperiodicReader(exporter metric.Exporter, opts ...Option) metric.Reader
 		opt(r)
 	}
 
+	// Embed SDK's ManualReader (now that its options are known)
+	r.ManualReader = metric.NewManualReader(r.manualReaderOptions...)
+
 	// Start background goroutines using WaitGroup (matches inode evictor pattern)
 	r.collectTicker = time.NewTicker(r.collectInterval)
 	r.exportTicker = time.NewTicker(r.exportInterval)
@@ -275,10 +341,31 @@ func (r *DiperiodicReader) doCollect(ctx context.Context) error {
 	// Append this snapshot to our buffer
 	// Each call to Collect() represents a snapshot of current gauge values
 	r.collectBuffer = append(r.collectBuffer, rm)
+	r.trimCollectBuffer()
 
 	return nil
 }
 
+// trimCollectBuffer drops the oldest snapshots in collectBuffer beyond maxBufferedSnapshots
+// such that a metrics outage cannot grow it without bound. Caller must hold collectMu.
+func (r *DiperiodicReader) trimCollectBuffer() {
+	excess := len(r.collectBuffer) - r.maxBufferedSnapshots
+	if (r.maxBufferedSnapshots <= 0) || (excess <= 0) {
+		return
+	}
+
+	r.droppedSnapshots.Add(uint64(excess))
+
+	// Copy rather than reslice so that the dropped snapshots' backing array is released
+	r.collectBuffer = append(make([]metricdata.ResourceMetrics, 0, r.maxBufferedSnapshots), r.collectBuffer[excess:]...)
+}
+
+// DroppedSnapshots returns the number of snapshots dropped (oldest first) due to
+// collectBuffer exceeding maxBufferedSnapshots since the reader was created.
+func (r *DiperiodicReader) DroppedSnapshots() uint64 {
+	return r.droppedSnapshots.Load()
+}
+
 // doExport performs a single export iteration with buffer rotation.
 // Matches Python's _export_iteration() method.
 //
@@ -311,6 +398,12 @@ func (r *DiperiodicReader) doExport(ctx context.Context) error {
 	err := r.exporter.Export(ctx, &merged)
 	if err != nil {
 		otel.Handle(err)
+
+		// Retain the snapshots (ahead of those collected meanwhile) for the next export
+		r.collectMu.Lock()
+		r.collectBuffer = append(r.exportBuffer, r.collectBuffer...)
+		r.trimCollectBuffer()
+		r.collectMu.Unlock()
 	}
 
 	// Clear export buffer after export (still protected by defer unlock above)
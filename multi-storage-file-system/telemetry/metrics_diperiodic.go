@@ -17,6 +17,7 @@ package telemetry
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -60,7 +61,8 @@ type MSCPMetricsDiperiodic struct {
 	// Attributes from attribute providers (collected once at startup)
 	// These are added to EVERY metric recording (matching Python behavior)
 	// Python collects these per-metric, but MSCP can collect once since it's a single-process daemon
-	baseAttributes []attribute.KeyValue
+	// (re-collected via SetBaseAttributes() once attributes only known after the FUSE mount are available)
+	baseAttributes *atomic.Pointer[[]attribute.KeyValue]
 
 	// Gauges (using Gauge instrument with LastValue aggregation)
 	// Go OTel has synchronous Gauge types (Float64Gauge, Int64Gauge)
@@ -143,9 +145,12 @@ func NewMSCPMetricsDiperiodic(serviceName string, baseAttributes []attribute.Key
 		return MSCPMetricsDiperiodic{}, err
 	}
 
+	baseAttributesPointer := &atomic.Pointer[[]attribute.KeyValue]{}
+	baseAttributesPointer.Store(&baseAttributes)
+
 	return MSCPMetricsDiperiodic{
 		meter:              meter,
-		baseAttributes:     baseAttributes,
+		baseAttributes:     baseAttributesPointer,
 		latencyGauge:       latencyGauge,
 		dataSizeGauge:      dataSizeGauge,
 		dataRateGauge:      dataRateGauge,
@@ -155,14 +160,24 @@ func NewMSCPMetricsDiperiodic(serviceName string, baseAttributes []attribute.Key
 	}, nil
 }
 
+// SetBaseAttributes replaces the attributes added to every metric recording.
+// Used to re-collect attribute providers once the FUSE mount is in place (e.g. to add the
+// mount instance's FUSE connection ID). Safe to call concurrently with metric recordings.
+func (m *MSCPMetricsDiperiodic) SetBaseAttributes(baseAttributes []attribute.KeyValue) {
+	m.baseAttributes.Store(&baseAttributes)
+}
+
 // RecordBackendRequest records the request counter at the START of a backend operation.
 // Matches Python's behavior: request.sum is recorded BEFORE the operation (line 209).
 // This should be called immediately at function start, NOT in defer.
-func (m *MSCPMetricsDiperiodic) RecordBackendRequest(ctx context.Context, operation, version, backend string) {
+// backendAttributes are the (opt-in) attributes identifying the backend (e.g. its bucket).
+func (m *MSCPMetricsDiperiodic) RecordBackendRequest(ctx context.Context, operation, version, backend string, backendAttributes []attribute.KeyValue) {
 	// Build attribute slice - merging base attributes with operation-specific ones
 	// Python base.py lines 201-206: collect_attributes() + VERSION, PROVIDER, OPERATION
-	allAttrs := make([]attribute.KeyValue, 0, len(m.baseAttributes)+3)
-	allAttrs = append(allAttrs, m.baseAttributes...)
+	baseAttributes := *m.baseAttributes.Load()
+	allAttrs := make([]attribute.KeyValue, 0, len(baseAttributes)+len(backendAttributes)+3)
+	allAttrs = append(allAttrs, baseAttributes...)
+	allAttrs = append(allAttrs, backendAttributes...)
 	allAttrs = append(allAttrs,
 		attribute.String("multistorageclient.version", version),
 		attribute.String("multistorageclient.provider", backend),
@@ -175,8 +190,9 @@ func (m *MSCPMetricsDiperiodic) RecordBackendRequest(ctx context.Context, operat
 
 // RecordBackendOperation records metrics for a backend operation using diperiodic pattern.
 // Matches Python's BaseStorageProvider._emit_metrics()
-// Note: Does not accept additional attributes to avoid high cardinality issues in metric backends.
-func (m *MSCPMetricsDiperiodic) RecordBackendOperation(ctx context.Context, operation, version, backend string, duration time.Duration, success bool, bytesTransferred int64) {
+// Note: Beyond the (opt-in) backendAttributes, does not accept additional attributes to avoid high
+// cardinality issues in metric backends.
+func (m *MSCPMetricsDiperiodic) RecordBackendOperation(ctx context.Context, operation, version, backend string, backendAttributes []attribute.KeyValue, duration time.Duration, success bool, bytesTransferred int64) {
 	status := "success"
 	if !success {
 		// Go errors don't have nice class names like Python (e.g. TimeoutError)
@@ -186,8 +202,10 @@ func (m *MSCPMetricsDiperiodic) RecordBackendOperation(ctx context.Context, oper
 
 	// Build attribute slice - merging base attributes with operation-specific ones
 	// Python base.py lines 201-206: collect_attributes() + VERSION, PROVIDER, OPERATION (and STATUS added after operation)
-	allAttrs := make([]attribute.KeyValue, 0, len(m.baseAttributes)+4)
-	allAttrs = append(allAttrs, m.baseAttributes...)
+	baseAttributes := *m.baseAttributes.Load()
+	allAttrs := make([]attribute.KeyValue, 0, len(baseAttributes)+len(backendAttributes)+4)
+	allAttrs = append(allAttrs, baseAttributes...)
+	allAttrs = append(allAttrs, backendAttributes...)
 	allAttrs = append(allAttrs,
 		attribute.String("multistorageclient.version", version),
 		attribute.String("multistorageclient.provider", backend),
@@ -17,6 +17,7 @@ package telemetry
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry/attributes"
@@ -26,6 +27,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
@@ -40,10 +42,55 @@ type MetricsConfig struct {
 	CollectTimeoutMillis  uint64                          // Collection timeout in milliseconds, default: 10000 (10 seconds)
 	ExportIntervalMillis  uint64                          // Export interval in milliseconds, default: 60000 (60 seconds)
 	ExportTimeoutMillis   uint64                          // Export timeout in milliseconds, default: 30000 (30 seconds)
+	MaxBufferedSnapshots  uint64                          // Max snapshots awaiting export (oldest dropped first), default: 600
 	ServiceName           string                          //
 	Insecure              bool                            // If true, use insecure connection (no TLS)
 	AzureAuth             *auth.Config                    // Optional: Azure MSAL auth config for _otlp_msal exporter
 	AttributeProviders    []attributes.AttributesProvider // Attribute providers to add to resource (matches Python)
+	Temporality           string                          // readers.TemporalityCumulative (default if "") or readers.TemporalityDelta
+	Views                 []MetricsView                   // Per-instrument aggregation overrides
+}
+
+// Supported MetricsView.Aggregation values
+const (
+	AggregationDefault                 = "default"                   // The instrument kind's default (e.g. LastValue for gauges)
+	AggregationDrop                    = "drop"                      // Instrument is not exported
+	AggregationExplicitBucketHistogram = "explicit_bucket_histogram" // Histogram with MetricsView.Boundaries (SDK defaults if empty)
+	AggregationExponentialHistogram    = "exponential_histogram"     // Base2 exponential histogram (160 buckets, max scale 20)
+	AggregationLastValue               = "last_value"                // Gauges only
+	AggregationSum                     = "sum"                       // Counters (and histograms) only
+)
+
+// MetricsView overrides the aggregation of the instrument(s) matching InstrumentName.
+// For example, an explicit bucket histogram of multistorageclient.latency exports a
+// distribution per collection rather than (gauge) samples, as some collectors require.
+type MetricsView struct {
+	InstrumentName string    // Instrument name (may include "*" and "?" wildcards)
+	Aggregation    string    // One of the Aggregation* values
+	Boundaries     []float64 // [Aggregation == AggregationExplicitBucketHistogram] increasing bucket boundaries
+}
+
+// sdkAggregation returns the SDK Aggregation corresponding to view.Aggregation.
+func (view MetricsView) sdkAggregation() (sdkmetric.Aggregation, error) {
+	switch view.Aggregation {
+	case AggregationDefault:
+		return sdkmetric.AggregationDefault{}, nil
+	case AggregationDrop:
+		return sdkmetric.AggregationDrop{}, nil
+	case AggregationExplicitBucketHistogram:
+		if len(view.Boundaries) == 0 {
+			return sdkmetric.DefaultAggregationSelector(sdkmetric.InstrumentKindHistogram), nil
+		}
+		return sdkmetric.AggregationExplicitBucketHistogram{Boundaries: view.Boundaries}, nil
+	case AggregationExponentialHistogram:
+		return sdkmetric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20}, nil
+	case AggregationLastValue:
+		return sdkmetric.AggregationLastValue{}, nil
+	case AggregationSum:
+		return sdkmetric.AggregationSum{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported aggregation \"%s\" for instrument \"%s\"", view.Aggregation, view.InstrumentName)
+	}
 }
 
 // SetupMetricsDiperiodic initializes the OTLP metrics exporter with diperiodic pattern.
@@ -53,6 +100,9 @@ type MetricsConfig struct {
 // - LastValue aggregation for gauges
 // - Sum aggregation for counters
 //
+// Beyond Python, config.Temporality may select delta temporality and config.Views may override
+// the aggregation of specific instruments (e.g. explicit bucket histograms of latency).
+//
 // Returns the MeterProvider and collected attributes from attribute providers.
 // The attributes should be added to each metric recording (matching Python behavior).
 //
@@ -106,14 +156,35 @@ func SetupMetricsDiperiodic(config *MetricsConfig) (*sdkmetric.MeterProvider, []
 		exportTimeout = time.Duration(readers.DefaultExportTimeoutMillis) * time.Millisecond
 	}
 
+	maxBufferedSnapshots := int(config.MaxBufferedSnapshots)
+	if maxBufferedSnapshots <= 0 {
+		maxBufferedSnapshots = readers.DefaultMaxBufferedSnapshots
+	}
+
 	reader := readers.NewDiperiodicReader(
 		exporter,
 		readers.WithCollectInterval(collectInterval),
 		readers.WithCollectTimeout(collectTimeout),
 		readers.WithExportInterval(exportInterval),
 		readers.WithExportTimeout(exportTimeout),
+		readers.WithMaxBufferedSnapshots(maxBufferedSnapshots),
+		readers.WithTemporality(config.Temporality),
 	)
 
+	// Convert per-instrument aggregation overrides to SDK views
+	views := make([]sdkmetric.View, 0, len(config.Views))
+	for _, view := range config.Views {
+		aggregation, err := view.sdkAggregation()
+		if err != nil {
+			_ = reader.Shutdown(ctx)
+			return nil, nil, err
+		}
+		views = append(views, sdkmetric.NewView(
+			sdkmetric.Instrument{Name: view.InstrumentName},
+			sdkmetric.Stream{Aggregation: aggregation},
+		))
+	}
+
 	// Collect attributes from attribute providers (matches Python: collect_attributes())
 	var resourceAttrs []attribute.KeyValue
 	var metricAttrs []attribute.KeyValue // Attributes to add to each metric recording (Python behavior)
@@ -136,13 +207,31 @@ func SetupMetricsDiperiodic(config *MetricsConfig) (*sdkmetric.MeterProvider, []
 	)
 
 	// Create meter provider with diperiodic reader
-	// Note: Gauge instruments automatically use LastValue aggregation
+	// Note: Gauge instruments automatically use LastValue aggregation (unless overridden by a view)
 	// This matches Python's _Gauge behavior
 	meterProvider := sdkmetric.NewMeterProvider(
 		sdkmetric.WithReader(reader),
 		sdkmetric.WithResource(res),
+		sdkmetric.WithView(views...),
 	)
 
+	// Report snapshots the reader dropped (e.g. during a metrics outage) as a metric of its own
+	if diperiodicReader, ok := reader.(*readers.DiperiodicReader); ok {
+		_, err = meterProvider.Meter(config.ServiceName).Int64ObservableCounter(
+			"msfs.metrics.dropped_snapshots",
+			metric.WithDescription("Total number of metric snapshots dropped (oldest first) awaiting export"),
+			metric.WithUnit("{snapshot}"),
+			metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+				observer.Observe(int64(diperiodicReader.DroppedSnapshots()))
+				return nil
+			}),
+		)
+		if err != nil {
+			_ = meterProvider.Shutdown(ctx)
+			return nil, nil, err
+		}
+	}
+
 	// Set global meter provider
 	otel.SetMeterProvider(meterProvider)
 
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+
+	"github.com/NVIDIA/multi-storage-client/multi-storage-file-system/telemetry/attributes"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// TracingConfig holds configuration for OTLP trace export.
+type TracingConfig struct {
+	OTLPEndpoint       string                          // e.g. "otel-collector:4318" (HTTP/OTLP)
+	Insecure           bool                            // If true, use insecure connection (no TLS)
+	SampleRatio        float64                         // Fraction of (root) FUSE operations traced; children follow their parent
+	ServiceName        string                          //
+	AttributeProviders []attributes.AttributesProvider // Attribute providers to add to resource
+}
+
+// SetupTracing initializes the OTLP trace exporter and installs the resulting
+// TracerProvider (and a W3C trace context propagator) globally. Spans are exported
+// in batches so that tracing does not add export latency to FUSE operations.
+//
+// Returns the TracerProvider so that it may be shut down (flushing pending spans).
+func SetupTracing(config *TracingConfig) (*sdktrace.TracerProvider, error) {
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(config.OTLPEndpoint),
+	}
+	if config.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resourceAttrs []attribute.KeyValue
+
+	if len(config.AttributeProviders) > 0 {
+		resourceAttrs = attributes.CollectAttributes(config.AttributeProviders)
+	}
+
+	resourceAttrs = append(resourceAttrs, semconv.ServiceName(config.ServiceName))
+
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		resourceAttrs...,
+	)
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return tracerProvider, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+
+	"github.com/NVIDIA/fission/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/NVIDIA/multi-storage-client/multi-storage-file-system"
+)
+
+// `startFUSESpan` starts (in ctx) the root span covering the servicing of a package fission
+// callback. Absent a configured opentelemetry.traces exporter, the global TracerProvider is
+// a no-op and the returned span records nothing.
+func startFUSESpan(ctx context.Context, op string, inHeader *fission.InHeader) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(
+		ctx,
+		"fuse."+op,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.Int64("fuse.node_id", int64(inHeader.NodeID)),
+			attribute.Int64("fuse.pid", int64(inHeader.PID)),
+			attribute.Int64("fuse.uid", int64(inHeader.UID)),
+		))
+}
+
+// `startBackendSpan` starts a child span of any span in ctx covering a single backend call.
+func startBackendSpan(ctx context.Context, backend *backendStruct, op string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(
+		ctx,
+		"backend."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("msfs.backend", backend.dirName),
+			attribute.String("msfs.backend_type", backend.backendType),
+		),
+		trace.WithAttributes(attributes...))
+}
+
+// `endSpan` ends span, first recording err (if non-nil) as its status.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// `detachedSpanContext` returns a context for work launched on behalf of ctx (e.g. by a
+// worker) that carries ctx's span (so that backend spans remain children of the initiating
+// FUSE operation) but is canceled by workerCtx rather than by ctx.
+func detachedSpanContext(workerCtx context.Context, ctx context.Context) context.Context {
+	return trace.ContextWithSpan(workerCtx, trace.SpanFromContext(ctx))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/NVIDIA/fission/v4"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingFUSEToBackendSpans(t *testing.T) {
+	var (
+		backendSpanFound bool
+		errno            syscall.Errno
+		lookupOut        *fission.LookupOut
+		lookupSpan       sdktrace.ReadOnlySpan
+		previousProvider = otel.GetTracerProvider()
+		span             sdktrace.ReadOnlySpan
+		spanRecorder     = tracetest.NewSpanRecorder()
+	)
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	defer otel.SetTracerProvider(previousProvider)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	lookupOut, errno = globals.DoLookup(&fission.InHeader{NodeID: FUSERootDirInodeNumber}, &fission.LookupIn{Name: []byte("ram")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(FUSERootDirInodeNumber,Name:\"ram\") unexpectedly failed (errno: %v)", errno)
+	}
+
+	_, errno = globals.DoLookup(&fission.InHeader{NodeID: lookupOut.EntryOut.NodeID, UID: 1234}, &fission.LookupIn{Name: []byte("fileA")})
+	if errno != 0 {
+		t.Fatalf("DoLookup(ramDir,Name:\"fileA\") unexpectedly failed (errno: %v)", errno)
+	}
+
+	for _, span = range spanRecorder.Ended() {
+		if span.Name() != "fuse.Lookup" {
+			continue
+		}
+		for _, attribute := range span.Attributes() {
+			if (attribute.Key == "fuse.uid") && (attribute.Value.AsInt64() == 1234) {
+				lookupSpan = span
+			}
+		}
+	}
+	if lookupSpan == nil {
+		t.Fatalf("no fuse.Lookup span recorded for ramDir/fileA")
+	}
+
+	for _, span = range spanRecorder.Ended() {
+		if strings.HasPrefix(span.Name(), "backend.") && (span.Parent().SpanID() == lookupSpan.SpanContext().SpanID()) {
+			backendSpanFound = true
+			if span.Parent().TraceID() != lookupSpan.SpanContext().TraceID() {
+				t.Fatalf("backend span %s not in the same trace as its fuse.Lookup parent", span.Name())
+			}
+		}
+	}
+	if !backendSpanFound {
+		t.Fatalf("no backend span recorded as a child of the fuse.Lookup span")
+	}
+}
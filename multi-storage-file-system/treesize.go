@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// `treeSizeCacheKeyStruct` identifies the directory whose XAttrNameTreeSize value is cached.
+type treeSizeCacheKeyStruct struct {
+	backendNonce uint64 // backendStruct.nonce
+	dirPath      string // Relative to backend.prefix; if != "", ends with a trailing "/"
+}
+
+// `treeSizeCacheEntryStruct` holds a cached XAttrNameTreeSize value.
+type treeSizeCacheEntryStruct struct {
+	objects    uint64
+	bytes      uint64
+	expiration time.Time // Entry is ignored (and eventually pruned) once this time has passed
+}
+
+// `treeSizeWalkStruct` tracks a walkPrefix() issued by treeSizeXAttr() so that concurrent
+// treeSizeXAttr() calls for the same treeSizeCacheKeyStruct may await and share its totals
+// rather than each walking the same prefix.
+type treeSizeWalkStruct struct {
+	done     chan struct{} // Closed once objects, bytes, err, and canceled have been set
+	objects  uint64
+	bytes    uint64
+	err      error
+	canceled bool // If true, err resulted from the walking caller's ctx being canceled (so joiners should walk again)
+}
+
+// `treeSizeXAttr` returns the XAttrNameTreeSize value (the "<bytes> <objects>" totalled over
+// every file at any depth beneath the directory) for the BackendRootDir or PseudoDir inode
+// identified by inodeNumber. Rather than stat'ing each file, the totals are computed from
+// paginated listings of the directory's prefix via walkPrefix(), omitting files hidden by the
+// backend's filter. Unless tree_size_cache_ttl is zero, the totals are cached for that long.
+// Concurrent calls for the same directory share a single walk (see treeSizeWalkStruct), each
+// abandoning its wait should its own ctx be canceled. Should the inode not be such a directory,
+// ENODATA is returned. It must be called without holding globals.Lock().
+func treeSizeXAttr(ctx context.Context, inodeNumber uint64) (backend *backendStruct, value []byte, errno syscall.Errno) {
+	var (
+		bytesAtomic      atomic.Uint64
+		err              error
+		inode            *inodeStruct
+		joined           bool
+		objectsAtomic    atomic.Uint64
+		ok               bool
+		treeSizeCacheKey treeSizeCacheKeyStruct
+		treeSizeEntry    *treeSizeCacheEntryStruct
+		treeSizeWalk     *treeSizeWalkStruct
+	)
+
+Restart:
+
+	globalsLock("treesize.go:58:2:treeSizeXAttr")
+
+	inode, ok = globals.inodeMap.get(inodeNumber)
+	if !ok || inode.pendingDelete {
+		globalsUnlock()
+		errno = syscall.ENOENT
+		return
+	}
+
+	if (inode.inodeType != BackendRootDir) && (inode.inodeType != PseudoDir) {
+		globalsUnlock()
+		errno = syscall.ENODATA
+		return
+	}
+
+	backend, ok = globals.backendMap[inode.backendNonce]
+	if !ok {
+		dumpStack()
+		globals.logger.Fatalf("[FATAL] globals.backendMap[inode.backendNonce] returned !ok")
+	}
+
+	inode.touch(nil)
+
+	treeSizeCacheKey = treeSizeCacheKeyStruct{
+		backendNonce: backend.nonce,
+		dirPath:      inode.objectPath,
+	}
+
+	treeSizeEntry, ok = globals.treeSizeCache[treeSizeCacheKey]
+	if ok && time.Now().Before(treeSizeEntry.expiration) {
+		value = fmt.Appendf(nil, "%d %d", treeSizeEntry.bytes, treeSizeEntry.objects)
+		globalsUnlock()
+		errno = 0
+		return
+	}
+
+	treeSizeWalk, joined = globals.treeSizeWalks[treeSizeCacheKey]
+	if !joined {
+		treeSizeWalk = &treeSizeWalkStruct{
+			done: make(chan struct{}),
+		}
+		globals.treeSizeWalks[treeSizeCacheKey] = treeSizeWalk
+	}
+
+	globalsUnlock()
+
+	if joined {
+		select {
+		case <-treeSizeWalk.done:
+		case <-ctx.Done():
+			errno = syscall.EIO
+			return
+		}
+
+		if treeSizeWalk.canceled && (ctx.Err() == nil) {
+			goto Restart
+		}
+
+		if treeSizeWalk.err != nil {
+			errno = syscall.EIO
+			return
+		}
+
+		value = fmt.Appendf(nil, "%d %d", treeSizeWalk.bytes, treeSizeWalk.objects)
+		errno = 0
+		return
+	}
+
+	_, err = walkPrefix(ctx, backend, treeSizeCacheKey.dirPath, defaultWalkParallelism, func(walkEntry *walkEntryStruct) (err error) {
+		if !backend.filter.hides(walkEntry.path) {
+			objectsAtomic.Add(1)
+			bytesAtomic.Add(walkEntry.size)
+		}
+		return
+	})
+
+	treeSizeWalk.objects = objectsAtomic.Load()
+	treeSizeWalk.bytes = bytesAtomic.Load()
+	treeSizeWalk.err = err
+	treeSizeWalk.canceled = (err != nil) && (ctx.Err() != nil)
+
+	globalsLock("treesize.go:139:2:treeSizeXAttr")
+
+	delete(globals.treeSizeWalks, treeSizeCacheKey)
+
+	if (err == nil) && (globals.config.treeSizeCacheTTL > 0) {
+		pruneTreeSizeCache()
+		globals.treeSizeCache[treeSizeCacheKey] = &treeSizeCacheEntryStruct{
+			objects:    treeSizeWalk.objects,
+			bytes:      treeSizeWalk.bytes,
+			expiration: time.Now().Add(globals.config.treeSizeCacheTTL),
+		}
+	}
+
+	globalsUnlock()
+
+	close(treeSizeWalk.done)
+
+	if err != nil {
+		globals.logger.Printf("[WARN] treeSizeXAttr() got walkPrefix(backends[\"%s\"], \"%s\") err: %v", backend.dirName, treeSizeCacheKey.dirPath, err)
+		errno = syscall.EIO
+		return
+	}
+
+	value = fmt.Appendf(nil, "%d %d", treeSizeWalk.bytes, treeSizeWalk.objects)
+
+	errno = 0
+	return
+}
+
+// `pruneTreeSizeCache` removes expired entries from globals.treeSizeCache. It must be called
+// while holding globals.Lock().
+func pruneTreeSizeCache() {
+	var (
+		now              = time.Now()
+		treeSizeCacheKey treeSizeCacheKeyStruct
+		treeSizeEntry    *treeSizeCacheEntryStruct
+	)
+
+	for treeSizeCacheKey, treeSizeEntry = range globals.treeSizeCache {
+		if !now.Before(treeSizeEntry.expiration) {
+			delete(globals.treeSizeCache, treeSizeCacheKey)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/NVIDIA/fission/v4"
+)
+
+// TestTreeSizeXAttr verifies that the user.msc.tree_size extended attribute of a directory
+// totals the files beneath it and that, once cached, the value does not reflect new objects.
+func TestTreeSizeXAttr(t *testing.T) {
+	var (
+		dir1Ino     uint64
+		errno       syscall.Errno
+		fileAIno    uint64
+		getXAttrOut *fission.GetXAttrOut
+		s3DirIno    uint64
+		wantS3Value = strconv.FormatUint(uint64(len("/dir1/fileC\n")+len("/fileA\n"))+testFissionS3FileBLen, 10) + " 3"
+	)
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+	dir1Ino = fissionS3TestLookup(t, s3DirIno, "dir1")
+	fileAIno = fissionS3TestLookup(t, s3DirIno, "fileA")
+
+	getXAttrOut, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: s3DirIno}, &fission.GetXAttrIn{Name: []byte(XAttrNameTreeSize), Size: 4096})
+	if (errno != 0) || (string(getXAttrOut.Data) != wantS3Value) {
+		t.Fatalf("DoGetXAttr(s3, XAttrNameTreeSize) should have returned %q (got %+v errno: %v)", wantS3Value, getXAttrOut, errno)
+	}
+
+	getXAttrOut, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: dir1Ino}, &fission.GetXAttrIn{Name: []byte(XAttrNameTreeSize), Size: 4096})
+	if (errno != 0) || (string(getXAttrOut.Data) != "12 1") {
+		t.Fatalf("DoGetXAttr(s3/dir1, XAttrNameTreeSize) should have returned \"12 1\" (got %+v errno: %v)", getXAttrOut, errno)
+	}
+
+	testGlobals.testS3Server.putObject(testFissionS3Bucket, "dir1/fileD", []byte("/dir1/fileD\n"))
+
+	getXAttrOut, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: dir1Ino}, &fission.GetXAttrIn{Name: []byte(XAttrNameTreeSize), Size: 4096})
+	if (errno != 0) || (string(getXAttrOut.Data) != "12 1") {
+		t.Fatalf("DoGetXAttr(s3/dir1, XAttrNameTreeSize) should have returned the cached \"12 1\" (got %+v errno: %v)", getXAttrOut, errno)
+	}
+
+	globalsLock("treesize_test.go:48:2:TestTreeSizeXAttr")
+	globals.config.treeSizeCacheTTL = 0
+	clear(globals.treeSizeCache)
+	globalsUnlock()
+
+	getXAttrOut, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: dir1Ino}, &fission.GetXAttrIn{Name: []byte(XAttrNameTreeSize), Size: 4096})
+	if (errno != 0) || (string(getXAttrOut.Data) != "24 2") {
+		t.Fatalf("DoGetXAttr(s3/dir1, XAttrNameTreeSize) should have returned \"24 2\" (got %+v errno: %v)", getXAttrOut, errno)
+	}
+
+	_, errno = globals.DoGetXAttr(&fission.InHeader{NodeID: fileAIno}, &fission.GetXAttrIn{Name: []byte(XAttrNameTreeSize), Size: 4096})
+	if errno != syscall.ENODATA {
+		t.Fatalf("DoGetXAttr(s3/fileA, XAttrNameTreeSize) should have returned ENODATA (got errno: %v)", errno)
+	}
+}
+
+// TestTreeSizeXAttrSharedWalk verifies that a treeSizeXAttr() call for a directory whose walk
+// is already in flight awaits and returns that walk's totals (rather than walking again) and
+// that a waiter whose ctx is canceled gives up without waiting for the walk to finish.
+func TestTreeSizeXAttrSharedWalk(t *testing.T) {
+	var (
+		canceledCtx      context.Context
+		cancel           context.CancelFunc
+		dir1Ino          uint64
+		dir1Inode        *inodeStruct
+		errno            syscall.Errno
+		errnoChan        = make(chan syscall.Errno, 1)
+		ok               bool
+		s3DirIno         uint64
+		treeSizeCacheKey treeSizeCacheKeyStruct
+		treeSizeWalk     = &treeSizeWalkStruct{done: make(chan struct{})}
+		value            []byte
+		valueChan        = make(chan []byte, 1)
+	)
+
+	fissionS3TestUp(t)
+	defer fissionS3TestDown(t)
+
+	s3DirIno = fissionS3TestLookup(t, FUSERootDirInodeNumber, "s3")
+	dir1Ino = fissionS3TestLookup(t, s3DirIno, "dir1")
+
+	globalsLock("treesize_test.go:89:2:TestTreeSizeXAttrSharedWalk")
+	dir1Inode, ok = globals.inodeMap.get(dir1Ino)
+	if !ok {
+		globalsUnlock()
+		t.Fatalf("globals.inodeMap.get(s3/dir1) returned !ok")
+	}
+	treeSizeCacheKey = treeSizeCacheKeyStruct{
+		backendNonce: dir1Inode.backendNonce,
+		dirPath:      dir1Inode.objectPath,
+	}
+	globals.treeSizeWalks[treeSizeCacheKey] = treeSizeWalk
+	globalsUnlock()
+
+	canceledCtx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	_, _, errno = treeSizeXAttr(canceledCtx, dir1Ino)
+	if errno != syscall.EIO {
+		t.Fatalf("treeSizeXAttr(canceledCtx, s3/dir1) should have returned EIO (got errno: %v)", errno)
+	}
+
+	go func() {
+		_, value, errno := treeSizeXAttr(context.Background(), dir1Ino)
+		valueChan <- value
+		errnoChan <- errno
+	}()
+
+	treeSizeWalk.objects = 7
+	treeSizeWalk.bytes = 42
+
+	close(treeSizeWalk.done)
+
+	value, errno = <-valueChan, <-errnoChan
+
+	globalsLock("treesize_test.go:123:2:TestTreeSizeXAttrSharedWalk")
+	delete(globals.treeSizeWalks, treeSizeCacheKey)
+	globalsUnlock()
+	if (errno != 0) || (string(value) != "42 7") {
+		t.Fatalf("treeSizeXAttr(s3/dir1) should have returned the shared walk's \"42 7\" (got %q errno: %v)", value, errno)
+	}
+}
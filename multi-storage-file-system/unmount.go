@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// `unmountDrainPollInterval` is how often awaitUnmountDrain() re-examines what remains to drain.
+const unmountDrainPollInterval = 100 * time.Millisecond
+
+// `gracefulUnmount` is called upon SIGINT or SIGTERM to unmount the FUSE file system (including
+// each of config.mounts). It first awaits (for up to unmount_drain_timeout) the closing of open
+// file handles and the upload of any dirty data cache lines, then cancels any remaining in-flight
+// ops. Should an unmount still fail (e.g. as some process continues to hold a file open), a lazy
+// unmount is performed such that the file system is detached immediately rather than the shutdown
+// being aborted.
+func gracefulUnmount() (err error) {
+	var (
+		mount *mountStruct
+	)
+
+	_ = awaitUnmountDrain(globals.config.unmountDrainTimeout)
+
+	cancelInFlightOps(errFUSEUnmounting)
+
+	for _, mount = range globals.mounts {
+		_ = unmountOrDetach(mount.mountPoint, mount.fissionVolume.DoUnmount)
+	}
+
+	err = unmountOrDetach(globals.config.mountPoint, performFissionUnmount)
+
+	return
+}
+
+// `unmountOrDetach` calls doUnmount to unmount the FUSE file system at mountPoint falling back,
+// should that fail, to a lazy unmount.
+func unmountOrDetach(mountPoint string, doUnmount func() error) (err error) {
+	err = doUnmount()
+	if err == nil {
+		return
+	}
+
+	globals.logger.Printf("[WARN] FUSE unmount of %s failed (%v)... falling back to a lazy unmount", mountPoint, err)
+
+	err = performLazyUnmount(mountPoint)
+	if err == nil {
+		globals.logger.Printf("[INFO] lazy unmount of %s succeeded", mountPoint)
+	}
+
+	return
+}
+
+// `awaitUnmountDrain` waits for up to timeout for all open file handles to be closed and for
+// all dirty data cache lines to be uploaded, returning whether or not that was achieved.
+func awaitUnmountDrain(timeout time.Duration) (drained bool) {
+	var (
+		deadline    = time.Now().Add(timeout)
+		dirtyLines  uint64
+		logged      bool
+		openHandles int
+	)
+
+	for {
+		globalsLock("unmount.go:65:3:awaitUnmountDrain")
+		openHandles = len(globals.fhMap)
+		dirtyLines = globals.dataCacheLineDirtyLRU.lruCount.Load() + globals.dataCacheLineOutboundLRU.lruCount.Load()
+		globalsUnlock()
+
+		if (openHandles == 0) && (dirtyLines == 0) {
+			if logged {
+				globals.logger.Printf("[INFO] unmount drain complete")
+			}
+			drained = true
+			return
+		}
+
+		if !time.Now().Before(deadline) {
+			globals.logger.Printf("[WARN] unmount_drain_timeout (%v) expired with %d open file handle(s) and %d dirty data cache line(s) remaining", timeout, openHandles, dirtyLines)
+			drained = false
+			return
+		}
+
+		if !logged {
+			globals.logger.Printf("[INFO] awaiting %d open file handle(s) and %d dirty data cache line(s) before unmounting (for up to %v)", openHandles, dirtyLines, timeout)
+			logged = true
+		}
+
+		time.Sleep(min(unmountDrainPollInterval, time.Until(deadline)))
+	}
+}
+
+// `performLazyUnmount` detaches the file system mounted at mountPoint such that it is no longer
+// reachable by path even though it may still be in use. If lacking the privilege to do so via
+// umount2(MNT_DETACH), the setuid fusermount3 (or fusermount) utility is employed instead.
+func performLazyUnmount(mountPoint string) (err error) {
+	var (
+		fusermount    string
+		fusermountErr error
+	)
+
+	err = lazyUnmountSyscall(mountPoint)
+	if err == nil {
+		return
+	}
+
+	for _, fusermount = range []string{"fusermount3", "fusermount"} {
+		fusermountErr = exec.Command(fusermount, "-u", "-z", mountPoint).Run()
+		if fusermountErr == nil {
+			err = nil
+			return
+		}
+	}
+
+	err = fmt.Errorf("lazy unmount of %s failed (umount2: %v; fusermount -u -z: %v)", mountPoint, err, fusermountErr)
+	return
+}
@@ -0,0 +1,10 @@
+//go:build linux
+
+package main
+
+import "syscall"
+
+// `lazyUnmountSyscall` performs umount2(mountPoint, MNT_DETACH).
+func lazyUnmountSyscall(mountPoint string) error {
+	return syscall.Unmount(mountPoint, syscall.MNT_DETACH)
+}
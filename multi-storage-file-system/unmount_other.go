@@ -0,0 +1,12 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// `lazyUnmountSyscall` is unsupported on non-Linux platforms, leaving performLazyUnmount()
+// to rely on fusermount. MSFS production targets are Linux, so this path is for local
+// dev/test builds (e.g. macOS) only.
+func lazyUnmountSyscall(mountPoint string) error {
+	return errors.New("lazy unmount not supported on this platform")
+}
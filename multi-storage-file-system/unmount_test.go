@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAwaitUnmountDrain(t *testing.T) {
+	var (
+		fh = &fhStruct{nonce: 0}
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	if !awaitUnmountDrain(time.Second) {
+		t.Fatalf("awaitUnmountDrain() should have succeeded with no open file handles")
+	}
+
+	globalsLock("unmount_test.go:20:2:TestAwaitUnmountDrain")
+	fh.nonce = fetchNonce()
+	globals.fhMap[fh.nonce] = fh
+	globalsUnlock()
+
+	if awaitUnmountDrain(50 * time.Millisecond) {
+		t.Fatalf("awaitUnmountDrain() should have timed out with an open file handle")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		globalsLock("unmount_test.go:31:3:funcLit@29")
+		delete(globals.fhMap, fh.nonce)
+		globalsUnlock()
+	}()
+
+	if !awaitUnmountDrain(5 * time.Second) {
+		t.Fatalf("awaitUnmountDrain() should have succeeded once the file handle was released")
+	}
+}
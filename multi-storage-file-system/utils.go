@@ -6,24 +6,48 @@ import (
 	"time"
 )
 
+const (
+	attrTimeMaxSec = uint64(253402300799) // 9999-12-31T23:59:59Z - the latest time reported in package fission data structures
+)
+
 // `timeDurationToAttrDuration` converts a time.Duration to the seconds:nanoseconds
-// form used in package fission data structures.
+// form used in package fission data structures. As the fields are unsigned, a
+// negative time.Duration is clamped to zero.
 func timeDurationToAttrDuration(timeDuration time.Duration) (timeDurationSec uint64, timeDurationNSec uint32) {
+	if timeDuration <= 0 {
+		return
+	}
+
 	timeDurationSec = uint64(timeDuration / time.Second)
-	timeDurationNSec = uint32((timeDuration - (time.Duration(timeDurationSec) * time.Second)).Nanoseconds())
+	timeDurationNSec = uint32(timeDuration % time.Second)
 
 	return
 }
 
 // `timeTimeToAttrTime` converts a time.Time to the seconds:nanoseconds
-// form used in package fission data structures.
+// form used in package fission data structures. Rather than relying on
+// time.Time.UnixNano() (which is undefined outside the years 1678-2262),
+// seconds and nanoseconds are extracted separately. Times prior to the
+// Unix epoch (including the zero time.Time) are clamped to the epoch and
+// times after attrTimeMaxSec are clamped to it.
 func timeTimeToAttrTime(timeTime time.Time) (timeTimeSec uint64, timeTimeNSec uint32) {
 	var (
-		unixNano = uint64(timeTime.UnixNano())
+		unixSec = timeTime.Unix()
 	)
 
-	timeTimeSec = unixNano / 1e9
-	timeTimeNSec = uint32(unixNano - (timeTimeSec * 1e9))
+	if unixSec < 0 {
+		return
+	}
+
+	timeTimeSec = uint64(unixSec)
+
+	if timeTimeSec > attrTimeMaxSec {
+		timeTimeSec = attrTimeMaxSec
+		timeTimeNSec = 999999999
+		return
+	}
+
+	timeTimeNSec = uint32(timeTime.Nanosecond())
 
 	return
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeDurationToAttrDuration(t *testing.T) {
+	var (
+		timeDurationNSec uint32
+		timeDurationSec  uint64
+	)
+
+	for _, testCase := range []struct {
+		timeDuration     time.Duration
+		timeDurationSec  uint64
+		timeDurationNSec uint32
+	}{
+		{0, 0, 0},
+		{1500 * time.Millisecond, 1, 500000000},
+		{10 * time.Second, 10, 0},
+		{-1, 0, 0},
+		{-10 * time.Second, 0, 0},
+		{math.MinInt64, 0, 0},
+		{math.MaxInt64, 9223372036, 854775807},
+	} {
+		timeDurationSec, timeDurationNSec = timeDurationToAttrDuration(testCase.timeDuration)
+		if (timeDurationSec != testCase.timeDurationSec) || (timeDurationNSec != testCase.timeDurationNSec) {
+			t.Errorf("timeDurationToAttrDuration(%v) returned %v:%v (expected %v:%v)", int64(testCase.timeDuration), timeDurationSec, timeDurationNSec, testCase.timeDurationSec, testCase.timeDurationNSec)
+		}
+	}
+}
+
+func TestTimeTimeToAttrTime(t *testing.T) {
+	var (
+		timeTimeNSec uint32
+		timeTimeSec  uint64
+	)
+
+	for _, testCase := range []struct {
+		name         string
+		timeTime     time.Time
+		timeTimeSec  uint64
+		timeTimeNSec uint32
+	}{
+		{"zero time.Time", time.Time{}, 0, 0},
+		{"Unix epoch", time.Unix(0, 0), 0, 0},
+		{"just before Unix epoch", time.Unix(0, -1), 0, 0},
+		{"1900", time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC), 0, 0},
+		{"just after Unix epoch", time.Unix(0, 1), 0, 1},
+		{"2024", time.Date(2024, time.February, 29, 12, 0, 0, 123456789, time.UTC), 1709208000, 123456789},
+		{"2100", time.Date(2100, time.January, 1, 0, 0, 0, 5, time.UTC), 4102444800, 5},
+		{"2262 (beyond UnixNano() range)", time.Date(2262, time.April, 12, 0, 0, 0, 0, time.UTC), 9223372800, 0},
+		{"9999", time.Date(9999, time.December, 31, 23, 59, 59, 999999999, time.UTC), attrTimeMaxSec, 999999999},
+		{"10000", time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC), attrTimeMaxSec, 999999999},
+		{"non-UTC location", time.Date(2100, time.January, 1, 1, 0, 0, 0, time.FixedZone("UTC+1", 3600)), 4102444800, 0},
+	} {
+		timeTimeSec, timeTimeNSec = timeTimeToAttrTime(testCase.timeTime)
+		if (timeTimeSec != testCase.timeTimeSec) || (timeTimeNSec != testCase.timeTimeNSec) {
+			t.Errorf("timeTimeToAttrTime(%s) returned %v:%v (expected %v:%v)", testCase.name, timeTimeSec, timeTimeNSec, testCase.timeTimeSec, testCase.timeTimeNSec)
+		}
+	}
+}
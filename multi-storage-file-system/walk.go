@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultWalkParallelism = 64                                                                  // Default upper bound on concurrently listed shards
+	walkShardChars         = "-.0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz" // Sorted first-letter range boundaries used to shard flat directories
+)
+
+// `walkEntryStruct` describes each object visited by walkPrefix().
+type walkEntryStruct struct {
+	path  string // Relative to backend.prefix
+	eTag  string
+	mTime time.Time
+	size  uint64
+}
+
+// `walkShardStruct` describes a disjoint portion of the keyspace under walkPrefix()'s prefix.
+// A delimited shard lists dirPath one level at a time with each subdirectory becoming a new
+// delimited shard. A range shard lists every key (at any depth) under dirPath that sorts
+// after startAfter and before stopAt.
+type walkShardStruct struct {
+	dirPath    string // Relative to backend.prefix; if != "", ends with a trailing "/"
+	delimited  bool
+	startAfter string // Range shards only; relative to backend.prefix
+	stopAt     string // Range shards only; relative to backend.prefix; if == "", no limit
+}
+
+// `walkerStruct` holds the state of a single walkPrefix() traversal.
+type walkerStruct struct {
+	sync.Mutex
+	cond    *sync.Cond
+	ctx     context.Context
+	backend *backendStruct
+	visit   func(walkEntry *walkEntryStruct) (err error)
+	queue   []*walkShardStruct
+	active  int   // Number of shards currently being listed
+	err     error // First error encountered; once set, no further shards are listed
+	shards  atomic.Uint64
+}
+
+// `walkPrefix` enumerates every object under prefix (relative to backend.prefix) calling visit()
+// for each. Rather than serially paginating a single recursive listing, the keyspace is split
+// into shards listed concurrently by up to parallelism workers. Each directory discovered via a
+// delimited listing becomes its own shard. A directory whose first page is both truncated and
+// free of subdirectories is considered flat and the remainder of its keyspace is split into
+// first-letter ranges that are then listed concurrently without a delimiter.
+//
+// Since visit() is called concurrently from multiple workers, it must be safe for concurrent
+// use. The first error returned by either a listing or visit() stops the walk and is returned.
+// Each listing is issued (via the backend's wrappers) with the supplied ctx such that canceling
+// it (e.g. as the FUSE request on whose behalf the walk is made is interrupted) ends the walk.
+func walkPrefix(ctx context.Context, backend *backendStruct, prefix string, parallelism int, visit func(walkEntry *walkEntryStruct) (err error)) (shards uint64, err error) {
+	var (
+		walker   *walkerStruct
+		workerWG sync.WaitGroup
+	)
+
+	if parallelism <= 0 {
+		parallelism = defaultWalkParallelism
+	}
+
+	if (prefix != "") && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	walker = &walkerStruct{
+		ctx:     ctx,
+		backend: backend,
+		visit:   visit,
+		queue:   []*walkShardStruct{{dirPath: prefix, delimited: true}},
+	}
+	walker.cond = sync.NewCond(walker)
+
+	for range parallelism {
+		workerWG.Add(1)
+		go walker.worker(&workerWG)
+	}
+
+	workerWG.Wait()
+
+	shards = walker.shards.Load()
+	err = walker.err
+
+	return
+}
+
+// `worker` is called to repeatedly dequeue and list shards until none remain and no other
+// worker is in a position to produce more.
+func (walker *walkerStruct) worker(workerWG *sync.WaitGroup) {
+	var (
+		err   error
+		shard *walkShardStruct
+	)
+
+	defer workerWG.Done()
+
+	for {
+		walker.Lock()
+		for (len(walker.queue) == 0) && (walker.active > 0) {
+			walker.cond.Wait()
+		}
+		if len(walker.queue) == 0 {
+			walker.cond.Broadcast()
+			walker.Unlock()
+			return
+		}
+		shard = walker.queue[0]
+		walker.queue = walker.queue[1:]
+		walker.active++
+		walker.Unlock()
+
+		walker.shards.Add(1)
+
+		if shard.delimited {
+			err = walker.listDelimitedShard(shard)
+		} else {
+			err = walker.listRangeShard(shard)
+		}
+
+		walker.Lock()
+		walker.active--
+		if (err != nil) && (walker.err == nil) {
+			walker.err = err
+			walker.queue = nil
+		}
+		walker.cond.Broadcast()
+		walker.Unlock()
+	}
+}
+
+// `push` is called to enqueue additional shards unless the walk has already failed.
+func (walker *walkerStruct) push(shards ...*walkShardStruct) {
+	walker.Lock()
+	if walker.err == nil {
+		walker.queue = append(walker.queue, shards...)
+		walker.cond.Broadcast()
+	}
+	walker.Unlock()
+}
+
+// `listDelimitedShard` lists a single directory level, enqueueing each subdirectory as a new
+// delimited shard and, should the directory turn out to be flat, splitting the remainder of its
+// keyspace into range shards.
+func (walker *walkerStruct) listDelimitedShard(shard *walkShardStruct) (err error) {
+	var (
+		file                  listDirectoryOutputFileStruct
+		listDirectoryOutput   *listDirectoryOutputStruct
+		nextContinuationToken string
+		pageNumber            int
+		subdirectory          string
+	)
+
+	for pageNumber = 0; ; pageNumber++ {
+		listDirectoryOutput, err = listDirectoryWrapper(walker.ctx, walker.backend.context, &listDirectoryInputStruct{
+			continuationToken: nextContinuationToken,
+			dirPath:           shard.dirPath,
+		})
+		if err != nil {
+			return
+		}
+
+		for _, subdirectory = range listDirectoryOutput.subdirectory {
+			walker.push(&walkShardStruct{dirPath: shard.dirPath + subdirectory + "/", delimited: true})
+		}
+
+		for _, file = range listDirectoryOutput.file {
+			err = walker.visit(&walkEntryStruct{
+				path:  shard.dirPath + file.basename, // Already desalted by listDirectoryWrapper()
+				eTag:  file.eTag,
+				mTime: file.mTime,
+				size:  file.size,
+			})
+			if err != nil {
+				return
+			}
+		}
+
+		if !listDirectoryOutput.isTruncated {
+			return
+		}
+
+		// Range shards are bounded by raw keys, so a salted backend's (desalted) basenames can't
+		// bound them... simply continue paginating such a directory instead
+
+		if (pageNumber == 0) && (len(listDirectoryOutput.subdirectory) == 0) && (len(listDirectoryOutput.file) > 0) && (walker.backend.keySaltWidth == 0) {
+			walker.push(walkRangeShards(shard.dirPath, shard.dirPath+listDirectoryOutput.file[len(listDirectoryOutput.file)-1].basename)...)
+			return
+		}
+
+		nextContinuationToken = listDirectoryOutput.nextContinuationToken
+	}
+}
+
+// `listRangeShard` lists, without a delimiter, every key under shard.dirPath in the range
+// (shard.startAfter, shard.stopAt).
+func (walker *walkerStruct) listRangeShard(shard *walkShardStruct) (err error) {
+	var (
+		basename              string
+		dirPath               string
+		file                  listDirectoryOutputFileStruct
+		listPrefixOutput      *listPrefixOutputStruct
+		nextContinuationToken string
+	)
+
+	for {
+		listPrefixOutput, err = listPrefixWrapper(walker.ctx, walker.backend.context, &listPrefixInputStruct{
+			prefix:            shard.dirPath,
+			startAfter:        shard.startAfter,
+			stopAt:            shard.stopAt,
+			continuationToken: nextContinuationToken,
+		})
+		if err != nil {
+			return
+		}
+
+		for _, file = range listPrefixOutput.file {
+			if strings.HasSuffix(file.basename, "/") {
+				// Skip directory marker objects just as a delimited listing would
+				continue
+			}
+			dirPath, basename = path.Split(shard.dirPath + file.basename)
+			err = walker.visit(&walkEntryStruct{
+				path:  dirPath + walker.backend.desaltBasename(basename),
+				eTag:  file.eTag,
+				mTime: file.mTime,
+				size:  file.size,
+			})
+			if err != nil {
+				return
+			}
+		}
+
+		if listPrefixOutput.stopped || !listPrefixOutput.isTruncated {
+			return
+		}
+
+		nextContinuationToken = listPrefixOutput.nextContinuationToken
+	}
+}
+
+// `walkRangeShards` splits the keyspace under dirPath that sorts after startAfter into
+// contiguous first-letter ranges. Each boundary key (dirPath followed by a single character
+// of walkShardChars) is covered by the range ending with it by means of a stopAt that is its
+// immediate successor (i.e. with a NUL appended).
+func walkRangeShards(dirPath string, startAfter string) (shards []*walkShardStruct) {
+	var (
+		boundary string
+		ch       rune
+	)
+
+	shards = make([]*walkShardStruct, 0, len(walkShardChars)+1)
+
+	for _, ch = range walkShardChars {
+		boundary = dirPath + string(ch)
+		if boundary <= startAfter {
+			continue
+		}
+		shards = append(shards, &walkShardStruct{dirPath: dirPath, startAfter: startAfter, stopAt: boundary + "\x00"})
+		startAfter = boundary
+	}
+
+	shards = append(shards, &walkShardStruct{dirPath: dirPath, startAfter: startAfter})
+
+	return
+}
+
+// `prefixUsage` is called to total the number of objects and bytes under prefix via walkPrefix().
+func prefixUsage(ctx context.Context, backend *backendStruct, prefix string, parallelism int) (objects uint64, bytes uint64, shards uint64, err error) {
+	var (
+		objectsAtomic atomic.Uint64
+		bytesAtomic   atomic.Uint64
+	)
+
+	shards, err = walkPrefix(ctx, backend, prefix, parallelism, func(walkEntry *walkEntryStruct) (err error) {
+		objectsAtomic.Add(1)
+		bytesAtomic.Add(walkEntry.size)
+		return
+	})
+
+	objects = objectsAtomic.Load()
+	bytes = bytesAtomic.Load()
+
+	return
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWalkPrefix verifies that a sharded walk over a mix of flat and hierarchical
+// directories visits every object exactly once.
+func TestWalkPrefix(t *testing.T) {
+	var (
+		allObjects []mockObject
+		seen       = make(map[string]int)
+		seenLock   sync.Mutex
+	)
+
+	allObjects = append(allObjects, generateFlatObjects("test/", 5000, "file-%07d.bin")...)
+	allObjects = append(allObjects, generateFlatObjects("test/flat/", 3000, "%07d.bin")...)
+	allObjects = append(allObjects, generateHierarchicalObjects("test/", "subdir-a", 1500)...)
+	allObjects = append(allObjects, generateHierarchicalObjects("test/subdir-a/", "nested", 20)...)
+	allObjects = append(allObjects,
+		mockObject{key: "test/flat/a", size: 10, mTime: time.Now()},
+		mockObject{key: "test/flat/a0", size: 10, mTime: time.Now()},
+		mockObject{key: "test/flat/~tilde", size: 10, mTime: time.Now()},
+		mockObject{key: "test/flat/deep/er/object", size: 10, mTime: time.Now()},
+	)
+
+	backend := newMockBackend("test/", allObjects)
+
+	shards, err := walkPrefix(context.Background(), backend, "", 8, func(walkEntry *walkEntryStruct) (err error) {
+		seenLock.Lock()
+		seen[walkEntry.path]++
+		seenLock.Unlock()
+		return
+	})
+	if err != nil {
+		t.Fatalf("walkPrefix() failed: %v", err)
+	}
+
+	if len(seen) != len(allObjects) {
+		t.Errorf("expected %d distinct objects, visited %d", len(allObjects), len(seen))
+	}
+	for _, object := range allObjects {
+		if count := seen[strings.TrimPrefix(object.key, "test/")]; count != 1 {
+			t.Errorf("object %q visited %d times", object.key, count)
+		}
+	}
+
+	if shards <= 4 {
+		t.Errorf("expected flat directories to have been split into range shards, but only %d shards were listed", shards)
+	}
+
+	objects, bytes, _, err := prefixUsage(context.Background(), backend, "flat", 4)
+	if err != nil {
+		t.Fatalf("prefixUsage() failed: %v", err)
+	}
+	if (objects != 3004) || (bytes != 30040) {
+		t.Errorf("prefixUsage(\"flat\") returned %d objects, %d bytes", objects, bytes)
+	}
+}
+
+// TestWalkPrefixVisitError verifies that an error returned by visit() stops the walk.
+func TestWalkPrefixVisitError(t *testing.T) {
+	backend := newMockBackend("test/", generateFlatObjects("test/", 5000, "file-%07d.bin"))
+
+	_, err := walkPrefix(context.Background(), backend, "", 4, func(walkEntry *walkEntryStruct) (err error) {
+		if walkEntry.path == "file-0000042.bin" {
+			err = fmt.Errorf("stop at %s", walkEntry.path)
+		}
+		return
+	})
+	if (err == nil) || (err.Error() != "stop at file-0000042.bin") {
+		t.Fatalf("walkPrefix() should have returned visit()'s error, got: %v", err)
+	}
+}
+
+// TestWalkRangeShards verifies that first-letter range shards are contiguous and that
+// each boundary key is covered by exactly one shard.
+func TestWalkRangeShards(t *testing.T) {
+	shards := walkRangeShards("dir/", "dir/b5")
+
+	if shards[0].startAfter != "dir/b5" {
+		t.Fatalf("first shard should start after \"dir/b5\", got %q", shards[0].startAfter)
+	}
+	if shards[len(shards)-1].stopAt != "" {
+		t.Fatalf("last shard should be unbounded, got stopAt %q", shards[len(shards)-1].stopAt)
+	}
+	for i := 1; i < len(shards); i++ {
+		if shards[i-1].stopAt != shards[i].startAfter+"\x00" {
+			t.Fatalf("shards %d and %d are not contiguous: stopAt %q, startAfter %q", i-1, i, shards[i-1].stopAt, shards[i].startAfter)
+		}
+	}
+
+	if shards[0].stopAt != "dir/c\x00" {
+		t.Fatalf("first shard should stop at \"dir/c\\x00\", got %q", shards[0].stopAt)
+	}
+}
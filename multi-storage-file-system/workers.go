@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	workerStopTimeout       = 10 * time.Second      // Maximum time stopWorkers() awaits running workers
+	workerPanicLockWait     = 1 * time.Second       // Maximum time a panicked worker awaits the release of the globals lock
+	workerPanicLockInterval = 10 * time.Millisecond // Interval at which a panicked worker checks for the release of the globals lock
+)
+
+// `workerStruct` tracks a single background goroutine launched via startWorker().
+type workerStruct struct {
+	kind   string
+	cancel context.CancelFunc
+	done   chan struct{} // Closed once the worker has returned (or panicked)
+}
+
+// `workerKindStatsStruct` summarizes the lifecycle of all workers of a given kind. The
+// counters are atomics such that a worker returning normally need not take workers.Lock().
+type workerKindStatsStruct struct {
+	started       atomic.Uint64
+	running       atomic.Uint64
+	completed     atomic.Uint64
+	panicked      atomic.Uint64
+	lastPanic     string    // Protected by workers.Lock()
+	lastPanicTime time.Time // Protected by workers.Lock()
+}
+
+// `workerKindStatusStruct` is a snapshot of a workerKindStatsStruct as reported by workersStatus().
+type workerKindStatusStruct struct {
+	Kind          string    `json:"kind"`
+	Started       uint64    `json:"started"`
+	Running       uint64    `json:"running"`
+	Completed     uint64    `json:"completed"`
+	Panicked      uint64    `json:"panicked"`
+	LastPanic     string    `json:"last_panic,omitempty"`
+	LastPanicTime time.Time `json:"last_panic_time,omitzero"`
+}
+
+// `workersStruct` is the central registry of background goroutines. Each is launched
+// with a context derived from .ctx such that stopWorkers() may ask all of them to
+// return, is tracked by .wg such that their completion may be awaited, and has any
+// panic recovered and logged (rather than silently taking the daemon down or, if
+// the panic is itself recovered elsewhere, vanishing without a trace).
+type workersStruct struct {
+	sync.Mutex
+	ctx      context.Context    // Lazily created; canceled (and reset to nil) by stopWorkers()
+	cancel   context.CancelFunc //
+	wg       *sync.WaitGroup    // Lazily created; replaced by stopWorkers() such that workers started after it returns are awaited separately
+	stopping bool               // While true, stopWorkers() is awaiting .wg so startWorker() launches nothing
+	kinds    map[string]*workerKindStatsStruct
+}
+
+// `startWorker` launches workerFunc in a new goroutine tracked under kind. The supplied
+// context is canceled either by (*workerStruct).stop() or by stopWorkers(). Workers are
+// expected to return promptly once their context is canceled. While stopWorkers() is
+// awaiting running workers, workerFunc is not launched (and the returned worker is
+// already stopped).
+func startWorker(kind string, workerFunc func(ctx context.Context)) (worker *workerStruct) {
+	var (
+		ctx       context.Context
+		kindStats *workerKindStatsStruct
+		ok        bool
+		wg        *sync.WaitGroup
+	)
+
+	globals.workers.Lock()
+
+	if globals.workers.stopping {
+		globals.workers.Unlock()
+		globals.logger.Printf("[WARN] worker \"%s\" not started as workers are being stopped", kind)
+		worker = &workerStruct{
+			kind:   kind,
+			cancel: func() {},
+			done:   make(chan struct{}),
+		}
+		close(worker.done)
+		return
+	}
+
+	if globals.workers.ctx == nil {
+		globals.workers.ctx, globals.workers.cancel = context.WithCancel(context.Background())
+	}
+	if globals.workers.wg == nil {
+		globals.workers.wg = &sync.WaitGroup{}
+	}
+	if globals.workers.kinds == nil {
+		globals.workers.kinds = make(map[string]*workerKindStatsStruct)
+	}
+
+	kindStats, ok = globals.workers.kinds[kind]
+	if !ok {
+		kindStats = &workerKindStatsStruct{}
+		globals.workers.kinds[kind] = kindStats
+	}
+
+	ctx, worker = globals.workers.newWorker(kind)
+
+	wg = globals.workers.wg
+	wg.Add(1)
+
+	globals.workers.Unlock()
+
+	kindStats.started.Add(1)
+	kindStats.running.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer close(worker.done)
+		defer worker.cancel()
+		defer globals.workers.finish(kind, kindStats)
+
+		workerFunc(ctx)
+	}()
+
+	return
+}
+
+// `newWorker` is called while holding workers.Lock() to create a workerStruct whose
+// context is derived from workers.ctx.
+func (workers *workersStruct) newWorker(kind string) (ctx context.Context, worker *workerStruct) {
+	worker = &workerStruct{
+		kind: kind,
+		done: make(chan struct{}),
+	}
+
+	ctx, worker.cancel = context.WithCancel(workers.ctx)
+
+	return
+}
+
+// `finish` is deferred by each worker to record its completion and to recover (and
+// log) any panic. As a worker that panicked while holding the globals lock would leave
+// it held forever, such a panic is fatal rather than recovered. Since the lock does not
+// record its holding goroutine, it is presumed held by the panicked worker if it is not
+// released within workerPanicLockWait.
+func (workers *workersStruct) finish(kind string, kindStats *workerKindStatsStruct) {
+	var (
+		lockWaitDeadline time.Time
+		lockHolderSite   string
+		r                = recover()
+	)
+
+	kindStats.running.Add(^uint64(0))
+
+	if r == nil {
+		kindStats.completed.Add(1)
+		return
+	}
+
+	lockWaitDeadline = time.Now().Add(workerPanicLockWait)
+
+	for {
+		lockHolderSite = GlobalsLockHolderSite()
+		if lockHolderSite == "" {
+			break
+		}
+		if time.Now().After(lockWaitDeadline) {
+			dumpStack()
+			globals.logger.Fatalf("[FATAL] worker \"%s\" panicked with the globals lock held (by \"%s\"): %v", kind, lockHolderSite, r)
+		}
+		time.Sleep(workerPanicLockInterval)
+	}
+
+	globals.logger.Printf("[WARN] worker \"%s\" panicked: %v", kind, r)
+	dumpStack()
+
+	kindStats.panicked.Add(1)
+
+	workers.Lock()
+	kindStats.lastPanic = strings.TrimSpace(strings.SplitN(fmt.Sprint(r), "\n", 2)[0])
+	kindStats.lastPanicTime = time.Now()
+	workers.Unlock()
+}
+
+// `stop` cancels the worker's context and awaits its return.
+func (worker *workerStruct) stop() {
+	worker.cancel()
+	<-worker.done
+}
+
+// `stopWorkers` cancels the context of every worker and awaits their return for up to
+// timeout. No new workers are started while awaiting them. The kinds of any workers still
+// running upon timeout are returned (and logged).
+func stopWorkers(timeout time.Duration) (stragglers []string) {
+	var (
+		doneChan  = make(chan struct{})
+		kind      string
+		kindStats *workerKindStatsStruct
+		wg        *sync.WaitGroup
+	)
+
+	globals.workers.Lock()
+	if globals.workers.cancel != nil {
+		globals.workers.cancel()
+		globals.workers.ctx, globals.workers.cancel = nil, nil
+	}
+	wg = globals.workers.wg
+	globals.workers.wg = nil
+	globals.workers.stopping = true
+	globals.workers.Unlock()
+
+	defer func() {
+		globals.workers.Lock()
+		globals.workers.stopping = false
+		globals.workers.Unlock()
+	}()
+
+	if wg == nil {
+		return
+	}
+
+	go func() {
+		wg.Wait()
+		close(doneChan)
+	}()
+
+	select {
+	case <-doneChan:
+		return
+	case <-time.After(timeout):
+	}
+
+	globals.workers.Lock()
+	for kind, kindStats = range globals.workers.kinds {
+		if kindStats.running.Load() > 0 {
+			stragglers = append(stragglers, kind)
+		}
+	}
+	globals.workers.Unlock()
+
+	slices.Sort(stragglers)
+
+	globals.logger.Printf("[WARN] workers still running after %v: %s", timeout, strings.Join(stragglers, ", "))
+
+	return
+}
+
+// `workersStatus` returns a snapshot, sorted by kind, of the lifecycle of each kind of worker.
+func workersStatus() (workerKindStatuses []workerKindStatusStruct) {
+	var (
+		kind      string
+		kindStats *workerKindStatsStruct
+	)
+
+	globals.workers.Lock()
+
+	workerKindStatuses = make([]workerKindStatusStruct, 0, len(globals.workers.kinds))
+
+	for kind, kindStats = range globals.workers.kinds {
+		workerKindStatuses = append(workerKindStatuses, workerKindStatusStruct{
+			Kind:          kind,
+			Started:       kindStats.started.Load(),
+			Running:       kindStats.running.Load(),
+			Completed:     kindStats.completed.Load(),
+			Panicked:      kindStats.panicked.Load(),
+			LastPanic:     kindStats.lastPanic,
+			LastPanicTime: kindStats.lastPanicTime,
+		})
+	}
+
+	globals.workers.Unlock()
+
+	slices.SortFunc(workerKindStatuses, func(a, b workerKindStatusStruct) int { return strings.Compare(a.Kind, b.Kind) })
+
+	return
+}
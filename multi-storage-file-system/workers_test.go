@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// `testWorkerKindStatus` returns the workersStatus() entry for kind.
+func testWorkerKindStatus(t *testing.T, kind string) (workerKindStatus workerKindStatusStruct) {
+	var (
+		ok bool
+	)
+
+	for _, workerKindStatus = range workersStatus() {
+		if workerKindStatus.Kind == kind {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		t.Fatalf("workersStatus() missing kind \"%s\"", kind)
+	}
+
+	return
+}
+
+func TestWorkerPanicRecovered(t *testing.T) {
+	var (
+		worker           *workerStruct
+		workerKindStatus workerKindStatusStruct
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	worker = startWorker("testPanic", func(_ context.Context) { panic("boom\nsecond line") })
+	worker.stop()
+
+	workerKindStatus = testWorkerKindStatus(t, "testPanic")
+	if (workerKindStatus.Started != 1) || (workerKindStatus.Running != 0) || (workerKindStatus.Completed != 0) || (workerKindStatus.Panicked != 1) {
+		t.Fatalf("unexpected workerKindStatus after panic: %+v", workerKindStatus)
+	}
+	if workerKindStatus.LastPanic != "boom" {
+		t.Fatalf("expected LastPanic \"boom\", got \"%s\"", workerKindStatus.LastPanic)
+	}
+	if workerKindStatus.LastPanicTime.IsZero() {
+		t.Fatalf("expected LastPanicTime to be set")
+	}
+}
+
+func TestWorkerStop(t *testing.T) {
+	var (
+		worker           *workerStruct
+		workerKindStatus workerKindStatusStruct
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	worker = startWorker("testStop", func(ctx context.Context) { <-ctx.Done() })
+
+	workerKindStatus = testWorkerKindStatus(t, "testStop")
+	if workerKindStatus.Running != 1 {
+		t.Fatalf("expected 1 running worker, got %+v", workerKindStatus)
+	}
+
+	worker.stop()
+
+	workerKindStatus = testWorkerKindStatus(t, "testStop")
+	if (workerKindStatus.Running != 0) || (workerKindStatus.Completed != 1) || (workerKindStatus.Panicked != 0) {
+		t.Fatalf("unexpected workerKindStatus after stop(): %+v", workerKindStatus)
+	}
+}
+
+func TestStopWorkers(t *testing.T) {
+	var (
+		release    = make(chan struct{})
+		straggler  *workerStruct
+		stragglers []string
+	)
+
+	fissionTestUp(t)
+	defer fissionTestDown(t)
+
+	_ = startWorker("testCooperative", func(ctx context.Context) { <-ctx.Done() })
+	straggler = startWorker("testStraggler", func(_ context.Context) { <-release })
+
+	stragglers = stopWorkers(10 * time.Millisecond)
+	if !slices.Contains(stragglers, "testStraggler") || slices.Contains(stragglers, "testCooperative") {
+		t.Fatalf("expected stragglers to include only testStraggler of the test workers, got %v", stragglers)
+	}
+
+	if testWorkerKindStatus(t, "testCooperative").Running != 0 {
+		t.Fatalf("cooperative worker should have returned upon stopWorkers()")
+	}
+
+	close(release)
+	straggler.stop()
+
+	stragglers = stopWorkers(time.Second)
+	if slices.Contains(stragglers, "testStraggler") {
+		t.Fatalf("testStraggler should have returned, got stragglers %v", stragglers)
+	}
+}